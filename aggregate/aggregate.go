@@ -18,6 +18,16 @@ type Aggregate interface {
 	AggregateType() AggregateType
 }
 
+// Snapshotter is implemented by aggregates whose state can be saved to and
+// restored from a snapshot
+type Snapshotter interface {
+	// Snapshot returns the serialized aggregate state
+	Snapshot() ([]byte, error)
+	// RestoreSnapshot restores the aggregate state from the provided
+	// serialized state saved at the provided version
+	RestoreSnapshot(version int64, data []byte) error
+}
+
 type Repository interface {
 	Load(id util.ID) (Aggregate, error)
 }
@@ -26,12 +36,31 @@ type HandleCommandError struct {
 	error
 }
 
-func ExecCommand(command *commands.Command, a Aggregate, es *eventstore.EventStore, uidGenerator common.UIDGenerator) (util.ID, int, error) {
+// ExecCommand executes the command on the aggregate and records its execution
+// in the command audit log. The audit entry of an executed command is written
+// in the same eventstore transaction of the command events. If the audit entry
+// can't be written the command fails.
+func ExecCommand(command *commands.Command, a Aggregate, es eventstore.EventStore, uidGenerator common.UIDGenerator) (util.ID, int, error) {
 	commandJson, err := json.Marshal(command)
 	if err == nil {
 		log.Infof("executing command on aggregate: %s %s: %s", a.AggregateType(), a.ID(), commandJson)
 	}
 
+	groupID, n, err := execCommand(command, a, es, uidGenerator)
+	if err != nil {
+		// record the rejected or failed command. Since the command events
+		// weren't written a failed audit entry write fails the command
+		// with the audit error.
+		if aerr := es.WriteCommandAudit(newCommandAuditEntry(command, a, err)); aerr != nil {
+			log.Errorf("failed to write command audit entry for command %s: %+v", command.ID, aerr)
+			return util.NilID, 0, aerr
+		}
+		return util.NilID, 0, err
+	}
+	return groupID, n, nil
+}
+
+func execCommand(command *commands.Command, a Aggregate, es eventstore.EventStore, uidGenerator common.UIDGenerator) (util.ID, int, error) {
 	events, err := a.HandleCommand(command)
 	if err != nil {
 		return util.NilID, 0, &HandleCommandError{err}
@@ -41,11 +70,49 @@ func ExecCommand(command *commands.Command, a Aggregate, es *eventstore.EventSto
 
 	// The events correlationID is the command correlationID
 	// The events causationID is the command ID
-	eventsData, err := ep.GenEventData(events, &command.CorrelationID, &command.ID, &groupID, &command.IssuerID)
+	// The events commandCausationID is the command causationID
+	eventsData, err := ep.GenEventData(events, &command.CorrelationID, &command.ID, &command.CausationID, &groupID, &command.IssuerID)
 	if err != nil {
 		return util.NilID, 0, err
 	}
 
-	se, err := es.WriteEvents(eventsData, a.AggregateType().String(), a.ID(), a.Version())
-	return groupID, len(se), err
+	entry := newCommandAuditEntry(command, a, nil)
+	entry.GroupID = &groupID
+	entry.EventsCount = len(eventsData)
+
+	se, err := es.WriteEventsWithCommandAudit(eventsData, a.AggregateType().String(), a.ID(), a.Version(), entry)
+	if err != nil {
+		return util.NilID, 0, err
+	}
+	return groupID, len(se), nil
+}
+
+// newCommandAuditEntry returns the command audit log entry of the command.
+// If cerr isn't nil the command is recorded as rejected or failed.
+func newCommandAuditEntry(command *commands.Command, a Aggregate, cerr error) *eventstore.CommandAuditEntry {
+	entry := &eventstore.CommandAuditEntry{
+		CommandID:     &command.ID,
+		CommandType:   string(command.CommandType),
+		CorrelationID: &command.CorrelationID,
+		CausationID:   &command.CausationID,
+		IssuerID:      &command.IssuerID,
+		AggregateType: a.AggregateType().String(),
+		AggregateID:   a.ID(),
+		Result:        eventstore.CommandAuditResultExecuted,
+	}
+	if command.IssuerID == util.NilID {
+		entry.IssuerID = nil
+	}
+	if cerr != nil {
+		entry.Result = eventstore.CommandAuditResultFailed
+		if _, ok := cerr.(*HandleCommandError); ok {
+			entry.Result = eventstore.CommandAuditResultRejected
+		}
+		entry.Error = cerr.Error()
+	}
+	if command.RequestInfo != nil {
+		entry.RemoteAddr = command.RequestInfo.RemoteAddr
+		entry.UserAgent = command.RequestInfo.UserAgent
+	}
+	return entry
 }
@@ -1,7 +1,12 @@
 package aggregate
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
@@ -10,8 +15,10 @@ import (
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/sorintlab/sircles/command/commands"
+	"github.com/sorintlab/sircles/db"
 	ep "github.com/sorintlab/sircles/events"
 	"github.com/sorintlab/sircles/eventstore"
+	ln "github.com/sorintlab/sircles/listennotify"
 	"github.com/sorintlab/sircles/util"
 )
 
@@ -96,3 +103,243 @@ func toStoredEvents(events []ep.Event, aggregateType AggregateType, aggregateID
 	}
 	return storedEvents, nil
 }
+
+// appendStoredEvents appends to the stream stored events the provided events
+// with the next stream versions
+func appendStoredEvents(t *testing.T, storedEvents []*eventstore.StoredEvent, events ...ep.Event) []*eventstore.StoredEvent {
+	last := storedEvents[len(storedEvents)-1]
+	newStoredEvents, err := toStoredEvents(events, AggregateType(last.Category), last.StreamID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, e := range newStoredEvents {
+		e.Version += last.Version
+	}
+	return append(storedEvents, newStoredEvents...)
+}
+
+func newTestEventStore() eventstore.EventStore {
+	localln := ln.NewLocalListenNotify()
+	nf := ln.NewLocalNotifierFactory(localln)
+	return eventstore.NewMemoryEventStore(nf)
+}
+
+func TestSnapshot(t *testing.T) {
+	es := newTestEventStore()
+	es.SetSnapshotInterval(2)
+
+	uidGenerator := NewTestUIDGen()
+	rr := NewUniqueValueRegistryRepository(es, uidGenerator)
+
+	registryID := "registry01"
+	for i := 0; i < 5; i++ {
+		r, err := rr.Load(registryID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		correlationID := uidGenerator.UUID("")
+		causationID := uidGenerator.UUID("")
+		command := commands.NewCommand(commands.CommandTypeReserveValue, correlationID, causationID, util.NilID, &commands.ReserveValue{
+			Value:     fmt.Sprintf("value%02d", i),
+			ID:        uidGenerator.UUID(""),
+			RequestID: uidGenerator.UUID(""),
+		})
+		if _, _, err := ExecCommand(command, r, es, uidGenerator); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	r, err := rr.Load(registryID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snapshot, err := es.GetSnapshot(registryID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot == nil {
+		t.Fatalf("expected snapshot, got no snapshot")
+	}
+	// with a snapshot interval of 2 the last snapshot is the one saved
+	// when loading the registry at version 4
+	if snapshot.Version != 4 {
+		t.Fatalf("expected snapshot version %d, got %d", 4, snapshot.Version)
+	}
+
+	// load from the events only and check that the state is the same
+	es.SetSnapshotInterval(0)
+	er, err := rr.Load(registryID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r.Version() != er.Version() {
+		t.Fatalf("expected version %d, got %d", er.Version(), r.Version())
+	}
+	data, err := r.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedData, err := er.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(data, expectedData) {
+		t.Fatalf("got state:\n%s\nwant state:\n%s", data, expectedData)
+	}
+}
+
+// TestCommandAuditCompleteness checks that every command executed on an
+// aggregate has one command audit entry and that the events of an executed
+// command are written only with their audit entry
+func TestCommandAuditCompleteness(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	esDB, err := db.NewDB("sqlite3", filepath.Join(tmpDir, "esdb"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer esDB.Close()
+	if err := esDB.Migrate("eventstore", eventstore.Migrations); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	es := eventstore.NewSQLEventStore(esDB, ln.NewLocalNotifierFactory(ln.NewLocalListenNotify()))
+
+	uidGenerator := NewTestUIDGen()
+	rr := NewUniqueValueRegistryRepository(es, uidGenerator)
+	registryID := "registry01"
+
+	reserveValue := func(r Aggregate, value string, id util.ID) (*commands.Command, error) {
+		command := commands.NewCommand(commands.CommandTypeReserveValue, uidGenerator.UUID(""), uidGenerator.UUID(""), util.NilID, &commands.ReserveValue{
+			Value:     value,
+			ID:        id,
+			RequestID: uidGenerator.UUID(""),
+		})
+		_, _, err := ExecCommand(command, r, es, uidGenerator)
+		return command, err
+	}
+	loadRegistry := func() Aggregate {
+		r, err := rr.Load(registryID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return r
+	}
+
+	id01 := uidGenerator.UUID("")
+	expectedResults := []eventstore.CommandAuditResult{}
+	commandsIDs := []util.ID{}
+
+	// executed
+	command, err := reserveValue(loadRegistry(), "value01", id01)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	commandsIDs = append(commandsIDs, command.ID)
+	expectedResults = append(expectedResults, eventstore.CommandAuditResultExecuted)
+
+	// executed without events
+	command, err = reserveValue(loadRegistry(), "value01", id01)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	commandsIDs = append(commandsIDs, command.ID)
+	expectedResults = append(expectedResults, eventstore.CommandAuditResultExecuted)
+
+	// rejected
+	command, err = reserveValue(loadRegistry(), "value01", uidGenerator.UUID(""))
+	if _, ok := err.(*HandleCommandError); !ok {
+		t.Fatalf("expected handle command error, got: %v", err)
+	}
+	commandsIDs = append(commandsIDs, command.ID)
+	expectedResults = append(expectedResults, eventstore.CommandAuditResultRejected)
+
+	// failed for a concurrent update
+	r := loadRegistry()
+	command, err = reserveValue(loadRegistry(), "value02", uidGenerator.UUID(""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	commandsIDs = append(commandsIDs, command.ID)
+	expectedResults = append(expectedResults, eventstore.CommandAuditResultExecuted)
+	command, err = reserveValue(r, "value03", uidGenerator.UUID(""))
+	if err == nil {
+		t.Fatalf("expected concurrent update error")
+	}
+	commandsIDs = append(commandsIDs, command.ID)
+	expectedResults = append(expectedResults, eventstore.CommandAuditResultFailed)
+
+	entries, err := es.GetCommandAuditEntries(0, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != len(commandsIDs) {
+		t.Fatalf("expected %d command audit entries, got %d entries", len(commandsIDs), len(entries))
+	}
+	events, err := es.GetAllEvents(0, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	eventsCount := 0
+	for i, entry := range entries {
+		if *entry.CommandID != commandsIDs[i] {
+			t.Fatalf("expected command audit entry for command %s, got entry for command %s", commandsIDs[i], *entry.CommandID)
+		}
+		if entry.Result != expectedResults[i] {
+			t.Fatalf("expected command %s result %q, got %q", commandsIDs[i], expectedResults[i], entry.Result)
+		}
+		if entry.Result != eventstore.CommandAuditResultExecuted {
+			continue
+		}
+		// the executed commands events are the ones with the entry group id
+		n := 0
+		for _, e := range events {
+			md, err := ep.UnmarshalMetaData(e)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *md.GroupID == *entry.GroupID {
+				n++
+			}
+		}
+		if n != entry.EventsCount {
+			t.Fatalf("expected %d events for command %s, got %d events", entry.EventsCount, commandsIDs[i], n)
+		}
+		eventsCount += n
+	}
+	if eventsCount != len(events) {
+		t.Fatalf("expected %d events, got %d events", eventsCount, len(events))
+	}
+
+	// if the audit entry can't be written the command fails and its events
+	// aren't written
+	lastSn, err := es.LastSequenceNumber()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = esDB.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			_, err := tx.Exec("drop table commandaudit")
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := reserveValue(loadRegistry(), "value04", uidGenerator.UUID("")); err == nil {
+		t.Fatalf("expected command audit error")
+	}
+	curLastSn, err := es.LastSequenceNumber()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if curLastSn != lastSn {
+		t.Fatalf("expected last sequence number %d, got %d", lastSn, curLastSn)
+	}
+}
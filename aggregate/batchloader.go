@@ -1,11 +1,50 @@
 package aggregate
 
 import (
+	"github.com/pkg/errors"
 	"github.com/sorintlab/sircles/eventstore"
 )
 
-func batchLoader(es *eventstore.EventStore, aggregateID string, a Aggregate) error {
+// errIncompatibleSnapshot is returned by RestoreSnapshot when the snapshot
+// data was saved by a previous aggregate version and cannot be restored. The
+// snapshot is deleted and the aggregate is loaded from all the stream events.
+var errIncompatibleSnapshot = errors.New("incompatible snapshot")
+
+// batchLoader loads the aggregate from the stream events. If the aggregate
+// implements the Snapshotter interface and snapshots are enabled it'll start
+// from the latest stream snapshot replaying only the following events and
+// it'll save a new snapshot when the snapshot interval has been reached.
+func batchLoader(es eventstore.EventStore, aggregateID string, a Aggregate) error {
 	var v int64 = 0
+
+	snapshotter, ok := a.(Snapshotter)
+	snapshotEnabled := ok && es.SnapshotInterval() > 0
+
+	if snapshotEnabled {
+		snapshot, err := es.GetSnapshot(aggregateID)
+		if err != nil {
+			return err
+		}
+		if snapshot != nil && snapshot.Category == a.AggregateType().String() {
+			err := snapshotter.RestoreSnapshot(snapshot.Version, snapshot.Data)
+			switch err {
+			case nil:
+				v = snapshot.Version
+			case errIncompatibleSnapshot:
+				log.Infof("deleting incompatible snapshot for aggregate %s %s", a.AggregateType(), aggregateID)
+				// delete the snapshot or it won't be replaced by the next
+				// snapshot saved at its same version
+				if err := es.DeleteSnapshot(aggregateID); err != nil {
+					log.Errorf("failed to delete snapshot for aggregate %s %s: %+v", a.AggregateType(), aggregateID, err)
+				}
+			default:
+				return err
+			}
+		}
+	}
+
+	snapshotVersion := v
+
 	for {
 		events, err := es.GetEvents(aggregateID, v+1, 100)
 		if err != nil {
@@ -13,7 +52,7 @@ func batchLoader(es *eventstore.EventStore, aggregateID string, a Aggregate) err
 		}
 
 		if len(events) == 0 {
-			return nil
+			break
 		}
 
 		v = events[len(events)-1].Version
@@ -22,4 +61,24 @@ func batchLoader(es *eventstore.EventStore, aggregateID string, a Aggregate) err
 			return err
 		}
 	}
+
+	if snapshotEnabled && a.Version()-snapshotVersion >= es.SnapshotInterval() {
+		data, err := snapshotter.Snapshot()
+		if err != nil {
+			return err
+		}
+		snapshot := &eventstore.Snapshot{
+			Category: a.AggregateType().String(),
+			StreamID: aggregateID,
+			Version:  a.Version(),
+			Data:     data,
+		}
+		// a failed snapshot save isn't fatal since the aggregate can always
+		// be loaded from the events
+		if err := es.WriteSnapshot(snapshot); err != nil {
+			log.Errorf("failed to save snapshot for aggregate %s %s: %+v", a.AggregateType(), aggregateID, err)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,266 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sorintlab/sircles/command/commands"
+	"github.com/sorintlab/sircles/common"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+)
+
+type MeetingRepository struct {
+	es           eventstore.EventStore
+	uidGenerator common.UIDGenerator
+}
+
+func NewMeetingRepository(es eventstore.EventStore, uidGenerator common.UIDGenerator) *MeetingRepository {
+	return &MeetingRepository{es: es, uidGenerator: uidGenerator}
+}
+
+func (mr *MeetingRepository) Load(id util.ID) (*Meeting, error) {
+	log.Debugf("Load id: %s", id)
+	m := NewMeeting(mr.uidGenerator, id)
+
+	if err := batchLoader(mr.es, id.String(), m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Meeting is a governance or tactical meeting of a circle with its agenda
+// items and their outcomes
+type Meeting struct {
+	id      util.ID
+	version int64
+
+	roleID        util.ID
+	facilitatorID *util.ID
+	secretaryID   *util.ID
+	agendaItems   []*models.MeetingAgendaItem
+	closed        bool
+
+	created      bool
+	uidGenerator common.UIDGenerator
+}
+
+func NewMeeting(uidGenerator common.UIDGenerator, id util.ID) *Meeting {
+	return &Meeting{
+		id:           id,
+		uidGenerator: uidGenerator,
+	}
+}
+
+func (m *Meeting) Version() int64 {
+	return m.version
+}
+
+func (m *Meeting) ID() string {
+	return m.id.String()
+}
+
+func (m *Meeting) AggregateType() AggregateType {
+	return MeetingAggregate
+}
+
+func (m *Meeting) HandleCommand(command *commands.Command) ([]ep.Event, error) {
+	var events []ep.Event
+	var err error
+	switch command.CommandType {
+	case commands.CommandTypeCreateMeeting:
+		events, err = m.HandleCreateMeetingCommand(command)
+	case commands.CommandTypeAddMeetingAgendaItem:
+		events, err = m.HandleAddMeetingAgendaItemCommand(command)
+	case commands.CommandTypeSetMeetingAgendaItemOutcome:
+		events, err = m.HandleSetMeetingAgendaItemOutcomeCommand(command)
+	case commands.CommandTypeCloseMeeting:
+		events, err = m.HandleCloseMeetingCommand(command)
+
+	default:
+		err = fmt.Errorf("unhandled command: %#v", command)
+	}
+
+	return events, err
+}
+
+func (m *Meeting) HandleCreateMeetingCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if m.created {
+		return nil, errors.New("meeting already exists")
+	}
+
+	c := command.Data.(*commands.CreateMeeting)
+
+	meeting := &models.Meeting{
+		MeetingType: c.MeetingType,
+		Title:       c.Title,
+		StartTime:   c.StartTime,
+	}
+	meeting.ID = m.id
+
+	events = append(events, ep.NewEventMeetingCreated(meeting, c.RoleID, c.MemberID, c.FacilitatorID, c.SecretaryID, c.AttendeesIDs))
+
+	return events, nil
+}
+
+func (m *Meeting) HandleAddMeetingAgendaItemCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if err := m.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	c := command.Data.(*commands.AddMeetingAgendaItem)
+
+	if m.agendaItem(c.AgendaItemID) != nil {
+		return nil, errors.New("agenda item already exists")
+	}
+
+	events = append(events, ep.NewEventMeetingAgendaItemAdded(c.AgendaItemID, c.TensionID, c.Title))
+
+	return events, nil
+}
+
+func (m *Meeting) HandleSetMeetingAgendaItemOutcomeCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if err := m.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	c := command.Data.(*commands.SetMeetingAgendaItemOutcome)
+
+	if m.agendaItem(c.AgendaItemID) == nil {
+		return nil, errors.New("unexistent agenda item")
+	}
+
+	events = append(events, ep.NewEventMeetingAgendaItemOutcomeSet(c.AgendaItemID, c.Outcome))
+
+	return events, nil
+}
+
+func (m *Meeting) HandleCloseMeetingCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if err := m.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	events = append(events, ep.NewEventMeetingClosed())
+
+	return events, nil
+}
+
+func (m *Meeting) checkOpen() error {
+	if !m.created {
+		return errors.New("unexistent meeting")
+	}
+	if m.closed {
+		return errors.New("meeting already closed")
+	}
+	return nil
+}
+
+func (m *Meeting) agendaItem(id util.ID) *models.MeetingAgendaItem {
+	for _, ai := range m.agendaItems {
+		if ai.ID == id {
+			return ai
+		}
+	}
+	return nil
+}
+
+func (m *Meeting) ApplyEvents(events []*eventstore.StoredEvent) error {
+	for _, e := range events {
+		if err := m.ApplyEvent(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Meeting) ApplyEvent(event *eventstore.StoredEvent) error {
+	log.Debugf("event: %v", event)
+
+	data, err := ep.UnmarshalData(event)
+	if err != nil {
+		return err
+	}
+
+	m.version = event.Version
+
+	switch ep.EventType(event.EventType) {
+	case ep.EventTypeMeetingCreated:
+		data := data.(*ep.EventMeetingCreated)
+
+		m.roleID = data.RoleID
+		m.facilitatorID = data.FacilitatorID
+		m.secretaryID = data.SecretaryID
+
+		m.created = true
+
+	case ep.EventTypeMeetingAgendaItemAdded:
+		data := data.(*ep.EventMeetingAgendaItemAdded)
+
+		m.agendaItems = append(m.agendaItems, &models.MeetingAgendaItem{Vertex: models.Vertex{ID: data.AgendaItemID}, Title: data.Title})
+
+	case ep.EventTypeMeetingAgendaItemOutcomeSet:
+		data := data.(*ep.EventMeetingAgendaItemOutcomeSet)
+
+		if ai := m.agendaItem(data.AgendaItemID); ai != nil {
+			ai.Outcome = data.Outcome
+		}
+
+	case ep.EventTypeMeetingClosed:
+		m.closed = true
+	}
+
+	return nil
+}
+
+type meetingSnapshot struct {
+	RoleID        util.ID
+	FacilitatorID *util.ID
+	SecretaryID   *util.ID
+	AgendaItems   []*models.MeetingAgendaItem
+	Closed        bool
+
+	Created bool
+}
+
+func (m *Meeting) Snapshot() ([]byte, error) {
+	return json.Marshal(&meetingSnapshot{
+		RoleID:        m.roleID,
+		FacilitatorID: m.facilitatorID,
+		SecretaryID:   m.secretaryID,
+		AgendaItems:   m.agendaItems,
+		Closed:        m.closed,
+
+		Created: m.created,
+	})
+}
+
+func (m *Meeting) RestoreSnapshot(version int64, data []byte) error {
+	var s meetingSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Wrap(err, "failed to unmarshal meeting snapshot")
+	}
+
+	m.version = version
+
+	m.roleID = s.RoleID
+	m.facilitatorID = s.FacilitatorID
+	m.secretaryID = s.SecretaryID
+	m.agendaItems = s.AgendaItems
+	m.closed = s.Closed
+
+	m.created = s.Created
+
+	return nil
+}
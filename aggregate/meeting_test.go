@@ -0,0 +1,168 @@
+package aggregate
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sorintlab/sircles/command/commands"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+)
+
+func TestCreateMeeting(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	meetingID := uidGenerator.UUID("")
+	roleID := uidGenerator.UUID("")
+	memberID := uidGenerator.UUID("")
+	secretaryID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	startTime := time.Date(2017, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	aggregate := NewMeeting(uidGenerator, meetingID)
+
+	command := commands.NewCommand(commands.CommandTypeCreateMeeting, correlationID, causationID, util.NilID, &commands.CreateMeeting{
+		RoleID:       roleID,
+		MeetingType:  models.MeetingTypeGovernance,
+		Title:        "meeting01",
+		StartTime:    startTime,
+		MemberID:     memberID,
+		SecretaryID:  &secretaryID,
+		AttendeesIDs: []util.ID{memberID},
+	})
+
+	out := []ep.Event{
+		&ep.EventMeetingCreated{
+			RoleID:       roleID,
+			MeetingType:  models.MeetingTypeGovernance,
+			Title:        "meeting01",
+			StartTime:    startTime,
+			MemberID:     memberID,
+			SecretaryID:  &secretaryID,
+			AttendeesIDs: []util.ID{memberID},
+		},
+	}
+
+	test := &testData{
+		Aggregate: aggregate,
+		Command:   command,
+		Out:       out,
+	}
+
+	runTest(t, test)
+}
+
+func setupMeeting(t *testing.T, meetingID util.ID) []*eventstore.StoredEvent {
+	uidGenerator := NewTestUIDGen()
+
+	roleID := uidGenerator.UUID("")
+	memberID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	aggregate := NewMeeting(uidGenerator, meetingID)
+
+	command := commands.NewCommand(commands.CommandTypeCreateMeeting, correlationID, causationID, util.NilID, &commands.CreateMeeting{
+		RoleID:      roleID,
+		MeetingType: models.MeetingTypeTactical,
+		Title:       "meeting01",
+		StartTime:   time.Date(2017, 5, 1, 10, 0, 0, 0, time.UTC),
+		MemberID:    memberID,
+	})
+
+	out, err := aggregate.HandleCommand(command)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	storedEvents, err := toStoredEvents(out, aggregate.AggregateType(), aggregate.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return storedEvents
+}
+
+func TestSetMeetingAgendaItemOutcome(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	meetingID := uidGenerator.UUID("")
+	agendaItemID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	tests := []struct {
+		state []*eventstore.StoredEvent
+		out   []ep.Event
+		err   error
+	}{
+		{
+			state: appendStoredEvents(t, setupMeeting(t, meetingID), ep.NewEventMeetingAgendaItemAdded(agendaItemID, nil, "agendaitem01")),
+			out: []ep.Event{
+				&ep.EventMeetingAgendaItemOutcomeSet{
+					AgendaItemID: agendaItemID,
+					Outcome:      "outcome01",
+				},
+			},
+		},
+		{
+			state: setupMeeting(t, meetingID),
+			err:   fmt.Errorf("unexistent agenda item"),
+		},
+		{
+			state: appendStoredEvents(t, setupMeeting(t, meetingID),
+				ep.NewEventMeetingAgendaItemAdded(agendaItemID, nil, "agendaitem01"),
+				ep.NewEventMeetingClosed(),
+			),
+			err: fmt.Errorf("meeting already closed"),
+		},
+	}
+
+	for _, tt := range tests {
+		aggregate := NewMeeting(uidGenerator, meetingID)
+
+		command := commands.NewCommand(commands.CommandTypeSetMeetingAgendaItemOutcome, correlationID, causationID, util.NilID, &commands.SetMeetingAgendaItemOutcome{
+			AgendaItemID: agendaItemID,
+			Outcome:      "outcome01",
+		})
+
+		test := &testData{
+			State:     tt.state,
+			Aggregate: aggregate,
+			Command:   command,
+			Out:       tt.out,
+			Err:       tt.err,
+		}
+
+		runTest(t, test)
+	}
+}
+
+func TestCloseNotExistingMeeting(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	meetingID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	aggregate := NewMeeting(uidGenerator, meetingID)
+
+	command := commands.NewCommand(commands.CommandTypeCloseMeeting, correlationID, causationID, util.NilID, &commands.CloseMeeting{})
+
+	test := &testData{
+		Aggregate: aggregate,
+		Command:   command,
+		Err:       fmt.Errorf("unexistent meeting"),
+	}
+
+	runTest(t, test)
+}
@@ -1,6 +1,7 @@
 package aggregate
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -13,11 +14,11 @@ import (
 )
 
 type MemberRepository struct {
-	es           *eventstore.EventStore
+	es           eventstore.EventStore
 	uidGenerator common.UIDGenerator
 }
 
-func NewMemberRepository(es *eventstore.EventStore, uidGenerator common.UIDGenerator) *MemberRepository {
+func NewMemberRepository(es eventstore.EventStore, uidGenerator common.UIDGenerator) *MemberRepository {
 	return &MemberRepository{es: es, uidGenerator: uidGenerator}
 }
 
@@ -42,11 +43,14 @@ type Member struct {
 	matchUID string
 	isAdmin  bool
 
-	created bool
+	created   bool
+	deleted   bool
+	forgotten bool
 
 	createRequests      map[util.ID]struct{}
 	updateRequests      map[util.ID]struct{}
 	setMatchUIDRequests map[util.ID]struct{}
+	deleteRequests      map[util.ID]struct{}
 
 	uidGenerator common.UIDGenerator
 }
@@ -59,6 +63,7 @@ func NewMember(uidGenerator common.UIDGenerator, id util.ID) *Member {
 		createRequests:      make(map[util.ID]struct{}),
 		updateRequests:      make(map[util.ID]struct{}),
 		setMatchUIDRequests: make(map[util.ID]struct{}),
+		deleteRequests:      make(map[util.ID]struct{}),
 	}
 }
 
@@ -82,10 +87,14 @@ func (m *Member) HandleCommand(command *commands.Command) ([]ep.Event, error) {
 		events, err = m.HandleCreateMemberCommand(command)
 	case commands.CommandTypeUpdateMember:
 		events, err = m.HandleUpdateMemberCommand(command)
+	case commands.CommandTypeDeleteMember:
+		events, err = m.HandleDeleteMemberCommand(command)
 	case commands.CommandTypeSetMemberPassword:
 		events, err = m.HandleSetMemberPasswordCommand(command)
 	case commands.CommandTypeSetMemberMatchUID:
 		events, err = m.HandleSetMemberMatchUIDCommand(command)
+	case commands.CommandTypeForgetMember:
+		events, err = m.HandleForgetMemberCommand(command)
 
 	default:
 		err = fmt.Errorf("unhandled command: %#v", command)
@@ -142,8 +151,8 @@ func (m *Member) HandleUpdateMemberCommand(command *commands.Command) ([]ep.Even
 		return nil, nil
 	}
 
-	// if not created return an error
-	if !m.created {
+	// if not created or deleted return an error
+	if !m.created || m.deleted {
 		return nil, fmt.Errorf("unexistent member")
 	}
 
@@ -171,11 +180,34 @@ func (m *Member) HandleUpdateMemberCommand(command *commands.Command) ([]ep.Even
 	return events, nil
 }
 
+func (m *Member) HandleDeleteMemberCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	c := command.Data.(*commands.DeleteMember)
+
+	if _, ok := m.deleteRequests[c.MemberChangeID]; ok {
+		return nil, nil
+	}
+
+	// if not created or already deleted return an error
+	if !m.created || m.deleted {
+		return nil, fmt.Errorf("unexistent member")
+	}
+
+	events = append(events, ep.NewEventMemberDeleted(m.id, c.MemberChangeID, m.userName, m.email, m.matchUID))
+
+	return events, nil
+}
+
 func (m *Member) HandleSetMemberPasswordCommand(command *commands.Command) ([]ep.Event, error) {
 	events := []ep.Event{}
 
 	c := command.Data.(*commands.SetMemberPassword)
 
+	if m.deleted {
+		return nil, fmt.Errorf("unexistent member")
+	}
+
 	events = append(events, ep.NewEventMemberPasswordSet(m.id, c.PasswordHash))
 
 	return events, nil
@@ -190,11 +222,35 @@ func (m *Member) HandleSetMemberMatchUIDCommand(command *commands.Command) ([]ep
 		return nil, nil
 	}
 
+	if m.deleted {
+		return nil, fmt.Errorf("unexistent member")
+	}
+
 	events = append(events, ep.NewEventMemberMatchUIDSet(m.id, c.MemberChangeID, c.MatchUID, m.matchUID))
 
 	return events, nil
 }
 
+func (m *Member) HandleForgetMemberCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if m.forgotten {
+		return nil, nil
+	}
+
+	if !m.created {
+		return nil, fmt.Errorf("unexistent member")
+	}
+	// only deleted members can be forgotten
+	if !m.deleted {
+		return nil, fmt.Errorf("member must be deleted before being forgotten")
+	}
+
+	events = append(events, ep.NewEventMemberForgotten(m.id))
+
+	return events, nil
+}
+
 func (m *Member) ApplyEvents(events []*eventstore.StoredEvent) error {
 	for _, e := range events {
 		if err := m.ApplyEvent(e); err != nil {
@@ -243,6 +299,91 @@ func (m *Member) ApplyEvent(event *eventstore.StoredEvent) error {
 		m.matchUID = data.MatchUID
 
 		m.setMatchUIDRequests[data.MemberChangeID] = struct{}{}
+
+	case ep.EventTypeMemberDeleted:
+		data := data.(*ep.EventMemberDeleted)
+
+		m.deleted = true
+
+		m.deleteRequests[data.MemberChangeID] = struct{}{}
+
+	case ep.EventTypeMemberForgotten:
+		m.forgotten = true
+
+		m.userName = ""
+		m.fullName = ""
+		m.email = ""
+		m.matchUID = ""
+	}
+
+	return nil
+}
+
+type memberSnapshot struct {
+	UserName string
+	FullName string
+	Email    string
+	MatchUID string
+	IsAdmin  bool
+
+	Created   bool
+	Deleted   bool
+	Forgotten bool
+
+	CreateRequests      map[util.ID]struct{}
+	UpdateRequests      map[util.ID]struct{}
+	SetMatchUIDRequests map[util.ID]struct{}
+	DeleteRequests      map[util.ID]struct{}
+}
+
+func (m *Member) Snapshot() ([]byte, error) {
+	return json.Marshal(&memberSnapshot{
+		UserName: m.userName,
+		FullName: m.fullName,
+		Email:    m.email,
+		MatchUID: m.matchUID,
+		IsAdmin:  m.isAdmin,
+
+		Created:   m.created,
+		Deleted:   m.deleted,
+		Forgotten: m.forgotten,
+
+		CreateRequests:      m.createRequests,
+		UpdateRequests:      m.updateRequests,
+		SetMatchUIDRequests: m.setMatchUIDRequests,
+		DeleteRequests:      m.deleteRequests,
+	})
+}
+
+func (m *Member) RestoreSnapshot(version int64, data []byte) error {
+	var s memberSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Wrap(err, "failed to unmarshal member snapshot")
+	}
+
+	m.version = version
+
+	m.userName = s.UserName
+	m.fullName = s.FullName
+	m.email = s.Email
+	m.matchUID = s.MatchUID
+	m.isAdmin = s.IsAdmin
+
+	m.created = s.Created
+	m.deleted = s.Deleted
+	m.forgotten = s.Forgotten
+
+	for id := range s.CreateRequests {
+		m.createRequests[id] = struct{}{}
+	}
+	for id := range s.UpdateRequests {
+		m.updateRequests[id] = struct{}{}
+	}
+	for id := range s.SetMatchUIDRequests {
+		m.setMatchUIDRequests[id] = struct{}{}
+	}
+	for id := range s.DeleteRequests {
+		m.deleteRequests[id] = struct{}{}
 	}
 
 	return nil
@@ -229,3 +229,153 @@ func TestUpdateNotExistingMember(t *testing.T) {
 
 	runTest(t, test)
 }
+
+func TestDeleteMember(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	memberID := uidGenerator.UUID("")
+	storedEvents := setupMember(t, memberID)
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	memberChangeID := uidGenerator.UUID("")
+
+	aggregate := NewMember(uidGenerator, memberID)
+
+	command := commands.NewCommand(commands.CommandTypeDeleteMember, correlationID, causationID, util.NilID, &commands.DeleteMember{
+		MemberChangeID: memberChangeID,
+	})
+
+	out := []ep.Event{
+		&ep.EventMemberDeleted{
+			UserName:       "user01",
+			Email:          "user01@example.com",
+			MemberChangeID: memberChangeID,
+		},
+	}
+
+	test := &testData{
+		State:     storedEvents,
+		Aggregate: aggregate,
+		Command:   command,
+		Out:       out,
+	}
+
+	runTest(t, test)
+
+	// reexecute command using current state, should return no events since the
+	// memberChangeID has been already handled
+	storedEvents, err := toStoredEvents(out, aggregate.AggregateType(), aggregate.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	test = &testData{
+		State:     storedEvents,
+		Aggregate: aggregate,
+		Command:   command,
+	}
+	runTest(t, test)
+
+	// Test delete and update (using another memberChangeID) of an already
+	// deleted member
+	memberChangeID = uidGenerator.UUID("")
+
+	command = commands.NewCommand(commands.CommandTypeDeleteMember, correlationID, causationID, util.NilID, &commands.DeleteMember{
+		MemberChangeID: memberChangeID,
+	})
+	test = &testData{
+		State:     storedEvents,
+		Aggregate: aggregate,
+		Command:   command,
+		Err:       fmt.Errorf("unexistent member"),
+	}
+	runTest(t, test)
+
+	command = commands.NewCommand(commands.CommandTypeUpdateMember, correlationID, causationID, util.NilID, &commands.UpdateMember{
+		IsAdmin:        false,
+		UserName:       "user01",
+		FullName:       "User 01",
+		Email:          "user01@example.com",
+		MemberChangeID: memberChangeID,
+		PrevUserName:   "user01",
+		PrevEmail:      "user01@example.com",
+	})
+	test = &testData{
+		State:     storedEvents,
+		Aggregate: aggregate,
+		Command:   command,
+		Err:       fmt.Errorf("unexistent member"),
+	}
+	runTest(t, test)
+}
+
+func TestForgetMember(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	memberID := uidGenerator.UUID("")
+	storedEvents := setupMember(t, memberID)
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	aggregate := NewMember(uidGenerator, memberID)
+
+	command := commands.NewCommand(commands.CommandTypeForgetMember, correlationID, causationID, util.NilID, &commands.ForgetMember{})
+
+	// Test forget of a not deleted member
+	test := &testData{
+		State:     storedEvents,
+		Aggregate: aggregate,
+		Command:   command,
+		Err:       fmt.Errorf("member must be deleted before being forgotten"),
+	}
+	runTest(t, test)
+
+	storedEvents, err := toStoredEvents([]ep.Event{
+		&ep.EventMemberDeleted{
+			UserName:       "user01",
+			Email:          "user01@example.com",
+			MemberChangeID: uidGenerator.UUID(""),
+		},
+	}, aggregate.AggregateType(), aggregate.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := []ep.Event{
+		&ep.EventMemberForgotten{},
+	}
+
+	test = &testData{
+		State:     storedEvents,
+		Aggregate: aggregate,
+		Command:   command,
+		Out:       out,
+	}
+	runTest(t, test)
+
+	// reexecute command using current state, should return no events since the
+	// member has been already forgotten
+	storedEvents, err = toStoredEvents(out, aggregate.AggregateType(), aggregate.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	test = &testData{
+		State:     storedEvents,
+		Aggregate: aggregate,
+		Command:   command,
+	}
+	runTest(t, test)
+
+	// Test forget of a not existing member
+	aggregate = NewMember(uidGenerator, uidGenerator.UUID(""))
+	test = &testData{
+		Aggregate: aggregate,
+		Command:   command,
+		Err:       fmt.Errorf("unexistent member"),
+	}
+	runTest(t, test)
+}
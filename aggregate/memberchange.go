@@ -1,8 +1,10 @@
 package aggregate
 
 import (
+	"encoding/json"
 	"fmt"
 
+	"github.com/pkg/errors"
 	"github.com/sorintlab/sircles/command/commands"
 	"github.com/sorintlab/sircles/common"
 	ep "github.com/sorintlab/sircles/events"
@@ -12,11 +14,11 @@ import (
 )
 
 type MemberChangeRepository struct {
-	es           *eventstore.EventStore
+	es           eventstore.EventStore
 	uidGenerator common.UIDGenerator
 }
 
-func NewMemberChangeRepository(es *eventstore.EventStore, uidGenerator common.UIDGenerator) *MemberChangeRepository {
+func NewMemberChangeRepository(es eventstore.EventStore, uidGenerator common.UIDGenerator) *MemberChangeRepository {
 	return &MemberChangeRepository{es: es, uidGenerator: uidGenerator}
 }
 
@@ -40,11 +42,11 @@ type MemberChange struct {
 
 	completed bool
 
-	es           *eventstore.EventStore
+	es           eventstore.EventStore
 	uidGenerator common.UIDGenerator
 }
 
-func NewMemberChange(es *eventstore.EventStore, uidGenerator common.UIDGenerator, id util.ID) (*MemberChange, error) {
+func NewMemberChange(es eventstore.EventStore, uidGenerator common.UIDGenerator, id util.ID) (*MemberChange, error) {
 	return &MemberChange{
 		id:           id,
 		es:           es,
@@ -80,6 +82,8 @@ func (m *MemberChange) HandleCommand(command *commands.Command) ([]ep.Event, err
 		events, err = m.HandleRequestUpdateMemberCommand(command)
 	case commands.CommandTypeRequestSetMemberMatchUID:
 		events, err = m.HandleRequestSetMemberMatchUIDCommand(command)
+	case commands.CommandTypeRequestDeleteMember:
+		events, err = m.HandleRequestDeleteMemberCommand(command)
 	case commands.CommandTypeCompleteRequest:
 		events, err = m.HandleCompleteRequestCommand(command)
 
@@ -136,6 +140,16 @@ func (m *MemberChange) HandleRequestSetMemberMatchUIDCommand(command *commands.C
 	return events, nil
 }
 
+func (m *MemberChange) HandleRequestDeleteMemberCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	c := command.Data.(*commands.RequestDeleteMember)
+
+	events = append(events, ep.NewEventMemberChangeDeleteRequested(m.id, c.MemberID, c.TensionIDs))
+
+	return events, nil
+}
+
 func (m *MemberChange) HandleCompleteRequestCommand(command *commands.Command) ([]ep.Event, error) {
 	events := []ep.Event{}
 
@@ -168,3 +182,26 @@ func (m *MemberChange) ApplyEvent(event *eventstore.StoredEvent) error {
 
 	return nil
 }
+
+type memberChangeSnapshot struct {
+	Completed bool
+}
+
+func (m *MemberChange) Snapshot() ([]byte, error) {
+	return json.Marshal(&memberChangeSnapshot{
+		Completed: m.completed,
+	})
+}
+
+func (m *MemberChange) RestoreSnapshot(version int64, data []byte) error {
+	var s memberChangeSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Wrap(err, "failed to unmarshal member change snapshot")
+	}
+
+	m.version = version
+
+	m.completed = s.Completed
+
+	return nil
+}
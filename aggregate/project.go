@@ -0,0 +1,290 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sorintlab/sircles/command/commands"
+	"github.com/sorintlab/sircles/common"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+)
+
+type ProjectRepository struct {
+	es           eventstore.EventStore
+	uidGenerator common.UIDGenerator
+}
+
+func NewProjectRepository(es eventstore.EventStore, uidGenerator common.UIDGenerator) *ProjectRepository {
+	return &ProjectRepository{es: es, uidGenerator: uidGenerator}
+}
+
+func (pr *ProjectRepository) Load(id util.ID) (*Project, error) {
+	log.Debugf("Load id: %s", id)
+	p := NewProject(pr.uidGenerator, id)
+
+	if err := batchLoader(pr.es, id.String(), p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Project is a project owned by a role and a member filling it, with its next
+// actions
+type Project struct {
+	id      util.ID
+	version int64
+
+	roleID      util.ID
+	memberID    util.ID
+	status      models.ProjectStatus
+	nextActions []*models.NextAction
+
+	created      bool
+	uidGenerator common.UIDGenerator
+}
+
+func NewProject(uidGenerator common.UIDGenerator, id util.ID) *Project {
+	return &Project{
+		id:           id,
+		uidGenerator: uidGenerator,
+	}
+}
+
+func (p *Project) Version() int64 {
+	return p.version
+}
+
+func (p *Project) ID() string {
+	return p.id.String()
+}
+
+func (p *Project) AggregateType() AggregateType {
+	return ProjectAggregate
+}
+
+func (p *Project) HandleCommand(command *commands.Command) ([]ep.Event, error) {
+	var events []ep.Event
+	var err error
+	switch command.CommandType {
+	case commands.CommandTypeCreateProject:
+		events, err = p.HandleCreateProjectCommand(command)
+	case commands.CommandTypeUpdateProject:
+		events, err = p.HandleUpdateProjectCommand(command)
+	case commands.CommandTypeChangeProjectStatus:
+		events, err = p.HandleChangeProjectStatusCommand(command)
+	case commands.CommandTypeAddProjectNextAction:
+		events, err = p.HandleAddProjectNextActionCommand(command)
+	case commands.CommandTypeCompleteProjectNextAction:
+		events, err = p.HandleCompleteProjectNextActionCommand(command)
+
+	default:
+		err = fmt.Errorf("unhandled command: %#v", command)
+	}
+
+	return events, err
+}
+
+func (p *Project) HandleCreateProjectCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if p.created {
+		return nil, errors.New("project already exists")
+	}
+
+	c := command.Data.(*commands.CreateProject)
+
+	project := &models.Project{
+		Title:       c.Title,
+		Description: c.Description,
+		Status:      models.ProjectStatusCurrent,
+	}
+	project.ID = p.id
+
+	events = append(events, ep.NewEventProjectCreated(project, c.RoleID, c.MemberID))
+
+	return events, nil
+}
+
+func (p *Project) HandleUpdateProjectCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if err := p.checkNotDone(); err != nil {
+		return nil, err
+	}
+
+	c := command.Data.(*commands.UpdateProject)
+
+	events = append(events, ep.NewEventProjectUpdated(c.Title, c.Description))
+
+	return events, nil
+}
+
+func (p *Project) HandleChangeProjectStatusCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if !p.created {
+		return nil, errors.New("unexistent project")
+	}
+
+	c := command.Data.(*commands.ChangeProjectStatus)
+
+	if c.Status == p.status {
+		return nil, errors.Errorf("project already in status %s", c.Status)
+	}
+
+	events = append(events, ep.NewEventProjectStatusChanged(c.Status, p.status))
+
+	return events, nil
+}
+
+func (p *Project) HandleAddProjectNextActionCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if err := p.checkNotDone(); err != nil {
+		return nil, err
+	}
+
+	c := command.Data.(*commands.AddProjectNextAction)
+
+	if p.nextAction(c.NextActionID) != nil {
+		return nil, errors.New("next action already exists")
+	}
+
+	events = append(events, ep.NewEventProjectNextActionAdded(c.NextActionID, c.Description))
+
+	return events, nil
+}
+
+func (p *Project) HandleCompleteProjectNextActionCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if err := p.checkNotDone(); err != nil {
+		return nil, err
+	}
+
+	c := command.Data.(*commands.CompleteProjectNextAction)
+
+	na := p.nextAction(c.NextActionID)
+	if na == nil {
+		return nil, errors.New("unexistent next action")
+	}
+	if na.Completed {
+		return nil, errors.New("next action already completed")
+	}
+
+	events = append(events, ep.NewEventProjectNextActionCompleted(c.NextActionID))
+
+	return events, nil
+}
+
+func (p *Project) checkNotDone() error {
+	if !p.created {
+		return errors.New("unexistent project")
+	}
+	if p.status == models.ProjectStatusDone {
+		return errors.New("project is done")
+	}
+	return nil
+}
+
+func (p *Project) nextAction(id util.ID) *models.NextAction {
+	for _, na := range p.nextActions {
+		if na.ID == id {
+			return na
+		}
+	}
+	return nil
+}
+
+func (p *Project) ApplyEvents(events []*eventstore.StoredEvent) error {
+	for _, e := range events {
+		if err := p.ApplyEvent(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Project) ApplyEvent(event *eventstore.StoredEvent) error {
+	log.Debugf("event: %v", event)
+
+	data, err := ep.UnmarshalData(event)
+	if err != nil {
+		return err
+	}
+
+	p.version = event.Version
+
+	switch ep.EventType(event.EventType) {
+	case ep.EventTypeProjectCreated:
+		data := data.(*ep.EventProjectCreated)
+
+		p.roleID = data.RoleID
+		p.memberID = data.MemberID
+		p.status = data.Status
+
+		p.created = true
+
+	case ep.EventTypeProjectStatusChanged:
+		data := data.(*ep.EventProjectStatusChanged)
+
+		p.status = data.Status
+
+	case ep.EventTypeProjectNextActionAdded:
+		data := data.(*ep.EventProjectNextActionAdded)
+
+		p.nextActions = append(p.nextActions, &models.NextAction{Vertex: models.Vertex{ID: data.NextActionID}, Description: data.Description})
+
+	case ep.EventTypeProjectNextActionCompleted:
+		data := data.(*ep.EventProjectNextActionCompleted)
+
+		if na := p.nextAction(data.NextActionID); na != nil {
+			na.Completed = true
+		}
+	}
+
+	return nil
+}
+
+type projectSnapshot struct {
+	RoleID      util.ID
+	MemberID    util.ID
+	Status      models.ProjectStatus
+	NextActions []*models.NextAction
+
+	Created bool
+}
+
+func (p *Project) Snapshot() ([]byte, error) {
+	return json.Marshal(&projectSnapshot{
+		RoleID:      p.roleID,
+		MemberID:    p.memberID,
+		Status:      p.status,
+		NextActions: p.nextActions,
+
+		Created: p.created,
+	})
+}
+
+func (p *Project) RestoreSnapshot(version int64, data []byte) error {
+	var s projectSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Wrap(err, "failed to unmarshal project snapshot")
+	}
+
+	p.version = version
+
+	p.roleID = s.RoleID
+	p.memberID = s.MemberID
+	p.status = s.Status
+	p.nextActions = s.NextActions
+
+	p.created = s.Created
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package aggregate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sorintlab/sircles/command/commands"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+)
+
+func TestCreateProject(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	projectID := uidGenerator.UUID("")
+	roleID := uidGenerator.UUID("")
+	memberID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	aggregate := NewProject(uidGenerator, projectID)
+
+	command := commands.NewCommand(commands.CommandTypeCreateProject, correlationID, causationID, util.NilID, &commands.CreateProject{
+		RoleID:      roleID,
+		MemberID:    memberID,
+		Title:       "project01",
+		Description: "description01",
+	})
+
+	out := []ep.Event{
+		&ep.EventProjectCreated{
+			RoleID:      roleID,
+			MemberID:    memberID,
+			Title:       "project01",
+			Description: "description01",
+			Status:      models.ProjectStatusCurrent,
+		},
+	}
+
+	test := &testData{
+		Aggregate: aggregate,
+		Command:   command,
+		Out:       out,
+	}
+
+	runTest(t, test)
+}
+
+func setupProject(t *testing.T, projectID util.ID) []*eventstore.StoredEvent {
+	uidGenerator := NewTestUIDGen()
+
+	roleID := uidGenerator.UUID("")
+	memberID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	aggregate := NewProject(uidGenerator, projectID)
+
+	command := commands.NewCommand(commands.CommandTypeCreateProject, correlationID, causationID, util.NilID, &commands.CreateProject{
+		RoleID:      roleID,
+		MemberID:    memberID,
+		Title:       "project01",
+		Description: "description01",
+	})
+
+	out, err := aggregate.HandleCommand(command)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	storedEvents, err := toStoredEvents(out, aggregate.AggregateType(), aggregate.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return storedEvents
+}
+
+func TestChangeProjectStatus(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	projectID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	tests := []struct {
+		state  []*eventstore.StoredEvent
+		status models.ProjectStatus
+		out    []ep.Event
+		err    error
+	}{
+		{
+			state:  setupProject(t, projectID),
+			status: models.ProjectStatusWaiting,
+			out: []ep.Event{
+				&ep.EventProjectStatusChanged{
+					Status:         models.ProjectStatusWaiting,
+					PreviousStatus: models.ProjectStatusCurrent,
+				},
+			},
+		},
+		{
+			state:  setupProject(t, projectID),
+			status: models.ProjectStatusCurrent,
+			err:    fmt.Errorf("project already in status current"),
+		},
+		// a done project can be reopened
+		{
+			state:  appendStoredEvents(t, setupProject(t, projectID), ep.NewEventProjectStatusChanged(models.ProjectStatusDone, models.ProjectStatusCurrent)),
+			status: models.ProjectStatusCurrent,
+			out: []ep.Event{
+				&ep.EventProjectStatusChanged{
+					Status:         models.ProjectStatusCurrent,
+					PreviousStatus: models.ProjectStatusDone,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		aggregate := NewProject(uidGenerator, projectID)
+
+		command := commands.NewCommand(commands.CommandTypeChangeProjectStatus, correlationID, causationID, util.NilID, &commands.ChangeProjectStatus{
+			Status: tt.status,
+		})
+
+		test := &testData{
+			State:     tt.state,
+			Aggregate: aggregate,
+			Command:   command,
+			Out:       tt.out,
+			Err:       tt.err,
+		}
+
+		runTest(t, test)
+	}
+}
+
+func TestCompleteProjectNextAction(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	projectID := uidGenerator.UUID("")
+	nextActionID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	tests := []struct {
+		state []*eventstore.StoredEvent
+		out   []ep.Event
+		err   error
+	}{
+		{
+			state: appendStoredEvents(t, setupProject(t, projectID), ep.NewEventProjectNextActionAdded(nextActionID, "nextaction01")),
+			out: []ep.Event{
+				&ep.EventProjectNextActionCompleted{
+					NextActionID: nextActionID,
+				},
+			},
+		},
+		{
+			state: setupProject(t, projectID),
+			err:   fmt.Errorf("unexistent next action"),
+		},
+		{
+			state: appendStoredEvents(t, setupProject(t, projectID),
+				ep.NewEventProjectNextActionAdded(nextActionID, "nextaction01"),
+				ep.NewEventProjectNextActionCompleted(nextActionID),
+			),
+			err: fmt.Errorf("next action already completed"),
+		},
+		{
+			state: appendStoredEvents(t, setupProject(t, projectID),
+				ep.NewEventProjectNextActionAdded(nextActionID, "nextaction01"),
+				ep.NewEventProjectStatusChanged(models.ProjectStatusDone, models.ProjectStatusCurrent),
+			),
+			err: fmt.Errorf("project is done"),
+		},
+	}
+
+	for _, tt := range tests {
+		aggregate := NewProject(uidGenerator, projectID)
+
+		command := commands.NewCommand(commands.CommandTypeCompleteProjectNextAction, correlationID, causationID, util.NilID, &commands.CompleteProjectNextAction{
+			NextActionID: nextActionID,
+		})
+
+		test := &testData{
+			State:     tt.state,
+			Aggregate: aggregate,
+			Command:   command,
+			Out:       tt.out,
+			Err:       tt.err,
+		}
+
+		runTest(t, test)
+	}
+}
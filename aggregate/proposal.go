@@ -0,0 +1,277 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sorintlab/sircles/change"
+	"github.com/sorintlab/sircles/command/commands"
+	"github.com/sorintlab/sircles/common"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+)
+
+type ProposalRepository struct {
+	es           eventstore.EventStore
+	uidGenerator common.UIDGenerator
+}
+
+func NewProposalRepository(es eventstore.EventStore, uidGenerator common.UIDGenerator) *ProposalRepository {
+	return &ProposalRepository{es: es, uidGenerator: uidGenerator}
+}
+
+func (pr *ProposalRepository) Load(id util.ID) (*Proposal, error) {
+	log.Debugf("Load id: %s", id)
+	p := NewProposal(pr.uidGenerator, id)
+
+	if err := batchLoader(pr.es, id.String(), p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Proposal is a set of changes to the child roles of a circle, related to a
+// tension, that, once accepted, are applied to the roles tree
+type Proposal struct {
+	id      util.ID
+	version int64
+
+	tensionID  util.ID
+	roleID     util.ID
+	memberID   util.ID
+	changes    change.ProposalChanges
+	objections []*models.ProposalObjection
+	state      models.ProposalState
+
+	created      bool
+	uidGenerator common.UIDGenerator
+}
+
+func NewProposal(uidGenerator common.UIDGenerator, id util.ID) *Proposal {
+	return &Proposal{
+		id:           id,
+		uidGenerator: uidGenerator,
+	}
+}
+
+func (p *Proposal) Version() int64 {
+	return p.version
+}
+
+func (p *Proposal) ID() string {
+	return p.id.String()
+}
+
+func (p *Proposal) AggregateType() AggregateType {
+	return ProposalAggregate
+}
+
+func (p *Proposal) HandleCommand(command *commands.Command) ([]ep.Event, error) {
+	var events []ep.Event
+	var err error
+	switch command.CommandType {
+	case commands.CommandTypeCreateProposal:
+		events, err = p.HandleCreateProposalCommand(command)
+	case commands.CommandTypeUpdateProposal:
+		events, err = p.HandleUpdateProposalCommand(command)
+	case commands.CommandTypeObjectProposal:
+		events, err = p.HandleObjectProposalCommand(command)
+	case commands.CommandTypeAcceptProposal:
+		events, err = p.HandleAcceptProposalCommand(command)
+	case commands.CommandTypeRevertProposalAcceptance:
+		events, err = p.HandleRevertProposalAcceptanceCommand(command)
+
+	default:
+		err = fmt.Errorf("unhandled command: %#v", command)
+	}
+
+	return events, err
+}
+
+func (p *Proposal) HandleCreateProposalCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if p.created {
+		return nil, errors.New("proposal already exists")
+	}
+
+	c := command.Data.(*commands.CreateProposal)
+
+	events = append(events, ep.NewEventProposalCreated(c.TensionID, c.RoleID, c.MemberID, c.ProposalChanges))
+
+	return events, nil
+}
+
+func (p *Proposal) HandleUpdateProposalCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if !p.created {
+		return nil, errors.New("unexistent proposal")
+	}
+	if p.state != models.ProposalStateOpen {
+		return nil, errors.New("proposal isn't open")
+	}
+
+	c := command.Data.(*commands.UpdateProposal)
+
+	events = append(events, ep.NewEventProposalUpdated(c.ProposalChanges))
+
+	return events, nil
+}
+
+func (p *Proposal) HandleObjectProposalCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if !p.created {
+		return nil, errors.New("unexistent proposal")
+	}
+	if p.state != models.ProposalStateOpen {
+		return nil, errors.New("proposal isn't open")
+	}
+
+	c := command.Data.(*commands.ObjectProposal)
+
+	events = append(events, ep.NewEventProposalObjectionRaised(c.MemberID, c.Reason))
+
+	return events, nil
+}
+
+func (p *Proposal) HandleAcceptProposalCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if !p.created {
+		return nil, errors.New("unexistent proposal")
+	}
+	if p.state != models.ProposalStateOpen {
+		return nil, errors.New("proposal isn't open")
+	}
+	if len(p.objections) > 0 {
+		return nil, errors.New("proposal has objections")
+	}
+
+	c := command.Data.(*commands.AcceptProposal)
+
+	events = append(events, ep.NewEventProposalAccepted(c.MemberID))
+
+	return events, nil
+}
+
+// HandleRevertProposalAcceptanceCommand reopens an accepted proposal whose
+// changes couldn't be applied to the roles tree
+func (p *Proposal) HandleRevertProposalAcceptanceCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if !p.created {
+		return nil, errors.New("unexistent proposal")
+	}
+	if p.state != models.ProposalStateAccepted {
+		return nil, errors.New("proposal isn't accepted")
+	}
+
+	c := command.Data.(*commands.RevertProposalAcceptance)
+
+	events = append(events, ep.NewEventProposalAcceptanceReverted(c.Reason))
+
+	return events, nil
+}
+
+func (p *Proposal) ApplyEvents(events []*eventstore.StoredEvent) error {
+	for _, e := range events {
+		if err := p.ApplyEvent(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Proposal) ApplyEvent(event *eventstore.StoredEvent) error {
+	log.Debugf("event: %v", event)
+
+	data, err := ep.UnmarshalData(event)
+	if err != nil {
+		return err
+	}
+
+	p.version = event.Version
+
+	switch ep.EventType(event.EventType) {
+	case ep.EventTypeProposalCreated:
+		data := data.(*ep.EventProposalCreated)
+
+		p.tensionID = data.TensionID
+		p.roleID = data.RoleID
+		p.memberID = data.MemberID
+		p.changes = data.ProposalChanges
+		p.state = models.ProposalStateOpen
+
+		p.created = true
+
+	case ep.EventTypeProposalUpdated:
+		data := data.(*ep.EventProposalUpdated)
+
+		p.changes = data.ProposalChanges
+		// the objections are related to the previous changes
+		p.objections = nil
+
+	case ep.EventTypeProposalObjectionRaised:
+		data := data.(*ep.EventProposalObjectionRaised)
+
+		p.objections = append(p.objections, &models.ProposalObjection{MemberID: data.MemberID, Reason: data.Reason})
+
+	case ep.EventTypeProposalAccepted:
+		p.state = models.ProposalStateAccepted
+
+	case ep.EventTypeProposalAcceptanceReverted:
+		p.state = models.ProposalStateOpen
+	}
+
+	return nil
+}
+
+type proposalSnapshot struct {
+	TensionID  util.ID
+	RoleID     util.ID
+	MemberID   util.ID
+	Changes    change.ProposalChanges
+	Objections []*models.ProposalObjection
+	State      models.ProposalState
+
+	Created bool
+}
+
+func (p *Proposal) Snapshot() ([]byte, error) {
+	return json.Marshal(&proposalSnapshot{
+		TensionID:  p.tensionID,
+		RoleID:     p.roleID,
+		MemberID:   p.memberID,
+		Changes:    p.changes,
+		Objections: p.objections,
+		State:      p.state,
+
+		Created: p.created,
+	})
+}
+
+func (p *Proposal) RestoreSnapshot(version int64, data []byte) error {
+	var s proposalSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Wrap(err, "failed to unmarshal proposal snapshot")
+	}
+
+	p.version = version
+
+	p.tensionID = s.TensionID
+	p.roleID = s.RoleID
+	p.memberID = s.MemberID
+	p.changes = s.Changes
+	p.objections = s.Objections
+	p.state = s.State
+
+	p.created = s.Created
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package aggregate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sorintlab/sircles/change"
+	"github.com/sorintlab/sircles/command/commands"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+)
+
+func testProposalChanges() change.ProposalChanges {
+	return change.ProposalChanges{
+		CreateRoleChanges: []change.CreateRoleChange{
+			{
+				Name:     "role01",
+				RoleType: models.RoleTypeNormal,
+				Purpose:  "purpose01",
+			},
+		},
+	}
+}
+
+func TestCreateProposal(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	proposalID := uidGenerator.UUID("")
+	tensionID := uidGenerator.UUID("")
+	roleID := uidGenerator.UUID("")
+	memberID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	aggregate := NewProposal(uidGenerator, proposalID)
+
+	command := commands.NewCommand(commands.CommandTypeCreateProposal, correlationID, causationID, util.NilID, &commands.CreateProposal{
+		TensionID:       tensionID,
+		RoleID:          roleID,
+		MemberID:        memberID,
+		ProposalChanges: testProposalChanges(),
+	})
+
+	out := []ep.Event{
+		&ep.EventProposalCreated{
+			TensionID:       tensionID,
+			RoleID:          roleID,
+			MemberID:        memberID,
+			ProposalChanges: testProposalChanges(),
+		},
+	}
+
+	test := &testData{
+		Aggregate: aggregate,
+		Command:   command,
+		Out:       out,
+	}
+
+	runTest(t, test)
+}
+
+func setupProposal(t *testing.T, proposalID util.ID) []*eventstore.StoredEvent {
+	uidGenerator := NewTestUIDGen()
+
+	tensionID := uidGenerator.UUID("")
+	roleID := uidGenerator.UUID("")
+	memberID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	aggregate := NewProposal(uidGenerator, proposalID)
+
+	command := commands.NewCommand(commands.CommandTypeCreateProposal, correlationID, causationID, util.NilID, &commands.CreateProposal{
+		TensionID:       tensionID,
+		RoleID:          roleID,
+		MemberID:        memberID,
+		ProposalChanges: testProposalChanges(),
+	})
+
+	out, err := aggregate.HandleCommand(command)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	storedEvents, err := toStoredEvents(out, aggregate.AggregateType(), aggregate.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return storedEvents
+}
+
+func TestAcceptProposal(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	proposalID := uidGenerator.UUID("")
+	memberID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	tests := []struct {
+		state []*eventstore.StoredEvent
+		out   []ep.Event
+		err   error
+	}{
+		{
+			state: setupProposal(t, proposalID),
+			out: []ep.Event{
+				&ep.EventProposalAccepted{
+					MemberID: memberID,
+				},
+			},
+		},
+		{
+			state: appendStoredEvents(t, setupProposal(t, proposalID), ep.NewEventProposalObjectionRaised(memberID, "objection01")),
+			err:   fmt.Errorf("proposal has objections"),
+		},
+		// updating the proposal removes the objections
+		{
+			state: appendStoredEvents(t, setupProposal(t, proposalID),
+				ep.NewEventProposalObjectionRaised(memberID, "objection01"),
+				ep.NewEventProposalUpdated(testProposalChanges()),
+			),
+			out: []ep.Event{
+				&ep.EventProposalAccepted{
+					MemberID: memberID,
+				},
+			},
+		},
+		{
+			state: appendStoredEvents(t, setupProposal(t, proposalID), ep.NewEventProposalAccepted(memberID)),
+			err:   fmt.Errorf("proposal isn't open"),
+		},
+		// a proposal whose acceptance has been reverted can be accepted again
+		{
+			state: appendStoredEvents(t, setupProposal(t, proposalID),
+				ep.NewEventProposalAccepted(memberID),
+				ep.NewEventProposalAcceptanceReverted("reason01"),
+			),
+			out: []ep.Event{
+				&ep.EventProposalAccepted{
+					MemberID: memberID,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		aggregate := NewProposal(uidGenerator, proposalID)
+
+		command := commands.NewCommand(commands.CommandTypeAcceptProposal, correlationID, causationID, util.NilID, &commands.AcceptProposal{
+			MemberID: memberID,
+		})
+
+		test := &testData{
+			State:     tt.state,
+			Aggregate: aggregate,
+			Command:   command,
+			Out:       tt.out,
+			Err:       tt.err,
+		}
+
+		runTest(t, test)
+	}
+}
+
+func TestRevertProposalAcceptance(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	proposalID := uidGenerator.UUID("")
+	memberID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	tests := []struct {
+		state []*eventstore.StoredEvent
+		out   []ep.Event
+		err   error
+	}{
+		{
+			state: appendStoredEvents(t, setupProposal(t, proposalID), ep.NewEventProposalAccepted(memberID)),
+			out: []ep.Event{
+				&ep.EventProposalAcceptanceReverted{
+					Reason: "reason01",
+				},
+			},
+		},
+		{
+			state: setupProposal(t, proposalID),
+			err:   fmt.Errorf("proposal isn't accepted"),
+		},
+		// a reverted proposal is open again
+		{
+			state: appendStoredEvents(t, setupProposal(t, proposalID),
+				ep.NewEventProposalAccepted(memberID),
+				ep.NewEventProposalAcceptanceReverted("reason01"),
+			),
+			err: fmt.Errorf("proposal isn't accepted"),
+		},
+	}
+
+	for _, tt := range tests {
+		aggregate := NewProposal(uidGenerator, proposalID)
+
+		command := commands.NewCommand(commands.CommandTypeRevertProposalAcceptance, correlationID, causationID, util.NilID, &commands.RevertProposalAcceptance{
+			Reason: "reason01",
+		})
+
+		test := &testData{
+			State:     tt.state,
+			Aggregate: aggregate,
+			Command:   command,
+			Out:       tt.out,
+			Err:       tt.err,
+		}
+
+		runTest(t, test)
+	}
+}
+
+func TestObjectNotExistingProposal(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	proposalID := uidGenerator.UUID("")
+	memberID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	aggregate := NewProposal(uidGenerator, proposalID)
+
+	command := commands.NewCommand(commands.CommandTypeObjectProposal, correlationID, causationID, util.NilID, &commands.ObjectProposal{
+		MemberID: memberID,
+		Reason:   "objection01",
+	})
+
+	test := &testData{
+		Aggregate: aggregate,
+		Command:   command,
+		Err:       fmt.Errorf("unexistent proposal"),
+	}
+
+	runTest(t, test)
+}
@@ -0,0 +1,278 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sorintlab/sircles/command/commands"
+	"github.com/sorintlab/sircles/common"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+)
+
+type ReportItemRepository struct {
+	es           eventstore.EventStore
+	uidGenerator common.UIDGenerator
+}
+
+func NewReportItemRepository(es eventstore.EventStore, uidGenerator common.UIDGenerator) *ReportItemRepository {
+	return &ReportItemRepository{es: es, uidGenerator: uidGenerator}
+}
+
+func (rr *ReportItemRepository) Load(id util.ID) (*ReportItem, error) {
+	log.Debugf("Load id: %s", id)
+	r := NewReportItem(rr.uidGenerator, id)
+
+	if err := batchLoader(rr.es, id.String(), r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// ReportItem is a circle checklist item or metric assigned to a role with its
+// reported values
+type ReportItem struct {
+	id      util.ID
+	version int64
+
+	circleID       util.ID
+	roleID         util.ID
+	reportItemType models.ReportItemType
+	frequency      models.ReportFrequency
+	values         []*models.ReportItemValue
+
+	created      bool
+	deleted      bool
+	uidGenerator common.UIDGenerator
+}
+
+func NewReportItem(uidGenerator common.UIDGenerator, id util.ID) *ReportItem {
+	return &ReportItem{
+		id:           id,
+		uidGenerator: uidGenerator,
+	}
+}
+
+func (r *ReportItem) Version() int64 {
+	return r.version
+}
+
+func (r *ReportItem) ID() string {
+	return r.id.String()
+}
+
+func (r *ReportItem) AggregateType() AggregateType {
+	return ReportItemAggregate
+}
+
+func (r *ReportItem) HandleCommand(command *commands.Command) ([]ep.Event, error) {
+	var events []ep.Event
+	var err error
+	switch command.CommandType {
+	case commands.CommandTypeCreateReportItem:
+		events, err = r.HandleCreateReportItemCommand(command)
+	case commands.CommandTypeUpdateReportItem:
+		events, err = r.HandleUpdateReportItemCommand(command)
+	case commands.CommandTypeDeleteReportItem:
+		events, err = r.HandleDeleteReportItemCommand(command)
+	case commands.CommandTypeSetReportItemValue:
+		events, err = r.HandleSetReportItemValueCommand(command)
+
+	default:
+		err = fmt.Errorf("unhandled command: %#v", command)
+	}
+
+	return events, err
+}
+
+func (r *ReportItem) HandleCreateReportItemCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if r.created {
+		return nil, errors.New("report item already exists")
+	}
+
+	c := command.Data.(*commands.CreateReportItem)
+
+	reportItem := &models.ReportItem{
+		ReportItemType: c.ReportItemType,
+		Description:    c.Description,
+		Frequency:      c.Frequency,
+	}
+	reportItem.ID = r.id
+
+	events = append(events, ep.NewEventReportItemCreated(reportItem, c.CircleID, c.RoleID))
+
+	return events, nil
+}
+
+func (r *ReportItem) HandleUpdateReportItemCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if err := r.checkExists(); err != nil {
+		return nil, err
+	}
+
+	c := command.Data.(*commands.UpdateReportItem)
+
+	events = append(events, ep.NewEventReportItemUpdated(c.RoleID, r.roleID, c.Description))
+
+	return events, nil
+}
+
+func (r *ReportItem) HandleDeleteReportItemCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if err := r.checkExists(); err != nil {
+		return nil, err
+	}
+
+	events = append(events, ep.NewEventReportItemDeleted(r.circleID, r.roleID))
+
+	return events, nil
+}
+
+func (r *ReportItem) HandleSetReportItemValueCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if err := r.checkExists(); err != nil {
+		return nil, err
+	}
+
+	c := command.Data.(*commands.SetReportItemValue)
+
+	if !r.frequency.PeriodStart(c.Period).Equal(c.Period) {
+		return nil, errors.Errorf("period %s isn't the start of a %s period", c.Period, r.frequency)
+	}
+	if r.reportItemType == models.ReportItemTypeChecklist && c.Value != 0 && c.Value != 1 {
+		return nil, errors.New("checklist item value must be 0 or 1")
+	}
+
+	var previousValue *float64
+	if v := r.value(c.Period); v != nil {
+		previousValue = &v.Value
+	}
+
+	events = append(events, ep.NewEventReportItemValueSet(c.Period, c.Value, previousValue))
+
+	return events, nil
+}
+
+func (r *ReportItem) checkExists() error {
+	if !r.created {
+		return errors.New("unexistent report item")
+	}
+	if r.deleted {
+		return errors.New("report item is deleted")
+	}
+	return nil
+}
+
+func (r *ReportItem) value(period time.Time) *models.ReportItemValue {
+	for _, v := range r.values {
+		if v.Period.Equal(period) {
+			return v
+		}
+	}
+	return nil
+}
+
+func (r *ReportItem) ApplyEvents(events []*eventstore.StoredEvent) error {
+	for _, e := range events {
+		if err := r.ApplyEvent(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReportItem) ApplyEvent(event *eventstore.StoredEvent) error {
+	log.Debugf("event: %v", event)
+
+	data, err := ep.UnmarshalData(event)
+	if err != nil {
+		return err
+	}
+
+	r.version = event.Version
+
+	switch ep.EventType(event.EventType) {
+	case ep.EventTypeReportItemCreated:
+		data := data.(*ep.EventReportItemCreated)
+
+		r.circleID = data.CircleID
+		r.roleID = data.RoleID
+		r.reportItemType = data.ReportItemType
+		r.frequency = data.Frequency
+
+		r.created = true
+
+	case ep.EventTypeReportItemUpdated:
+		data := data.(*ep.EventReportItemUpdated)
+
+		r.roleID = data.RoleID
+
+	case ep.EventTypeReportItemDeleted:
+		r.deleted = true
+
+	case ep.EventTypeReportItemValueSet:
+		data := data.(*ep.EventReportItemValueSet)
+
+		if v := r.value(data.Period); v != nil {
+			v.Value = data.Value
+		} else {
+			r.values = append(r.values, &models.ReportItemValue{Period: data.Period, Value: data.Value})
+		}
+	}
+
+	return nil
+}
+
+type reportItemSnapshot struct {
+	CircleID       util.ID
+	RoleID         util.ID
+	ReportItemType models.ReportItemType
+	Frequency      models.ReportFrequency
+	Values         []*models.ReportItemValue
+
+	Created bool
+	Deleted bool
+}
+
+func (r *ReportItem) Snapshot() ([]byte, error) {
+	return json.Marshal(&reportItemSnapshot{
+		CircleID:       r.circleID,
+		RoleID:         r.roleID,
+		ReportItemType: r.reportItemType,
+		Frequency:      r.frequency,
+		Values:         r.values,
+
+		Created: r.created,
+		Deleted: r.deleted,
+	})
+}
+
+func (r *ReportItem) RestoreSnapshot(version int64, data []byte) error {
+	var s reportItemSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Wrap(err, "failed to unmarshal report item snapshot")
+	}
+
+	r.version = version
+
+	r.circleID = s.CircleID
+	r.roleID = s.RoleID
+	r.reportItemType = s.ReportItemType
+	r.frequency = s.Frequency
+	r.values = s.Values
+
+	r.created = s.Created
+	r.deleted = s.Deleted
+
+	return nil
+}
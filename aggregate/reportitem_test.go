@@ -0,0 +1,138 @@
+package aggregate
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sorintlab/sircles/command/commands"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+)
+
+func setupReportItem(t *testing.T, reportItemID util.ID, reportItemType models.ReportItemType) []*eventstore.StoredEvent {
+	uidGenerator := NewTestUIDGen()
+
+	circleID := uidGenerator.UUID("")
+	roleID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	aggregate := NewReportItem(uidGenerator, reportItemID)
+
+	command := commands.NewCommand(commands.CommandTypeCreateReportItem, correlationID, causationID, util.NilID, &commands.CreateReportItem{
+		CircleID:       circleID,
+		RoleID:         roleID,
+		ReportItemType: reportItemType,
+		Description:    "reportitem01",
+		Frequency:      models.ReportFrequencyMonthly,
+	})
+
+	out, err := aggregate.HandleCommand(command)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	storedEvents, err := toStoredEvents(out, aggregate.AggregateType(), aggregate.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return storedEvents
+}
+
+func TestSetReportItemValue(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	reportItemID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	period := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+	previousValue := 10.0
+
+	tests := []struct {
+		state  []*eventstore.StoredEvent
+		period time.Time
+		value  float64
+		out    []ep.Event
+		err    error
+	}{
+		{
+			state:  setupReportItem(t, reportItemID, models.ReportItemTypeMetric),
+			period: period,
+			value:  10,
+			out: []ep.Event{
+				&ep.EventReportItemValueSet{
+					Period: period,
+					Value:  10,
+				},
+			},
+		},
+		// a value already reported for the same period is replaced
+		{
+			state:  appendStoredEvents(t, setupReportItem(t, reportItemID, models.ReportItemTypeMetric), ep.NewEventReportItemValueSet(period, 10, nil)),
+			period: period,
+			value:  12.5,
+			out: []ep.Event{
+				&ep.EventReportItemValueSet{
+					Period:        period,
+					Value:         12.5,
+					PreviousValue: &previousValue,
+				},
+			},
+		},
+		{
+			state:  setupReportItem(t, reportItemID, models.ReportItemTypeMetric),
+			period: period.AddDate(0, 0, 1),
+			value:  10,
+			err:    fmt.Errorf("period 2017-05-02 00:00:00 +0000 UTC isn't the start of a monthly period"),
+		},
+		{
+			state:  setupReportItem(t, reportItemID, models.ReportItemTypeChecklist),
+			period: period,
+			value:  1,
+			out: []ep.Event{
+				&ep.EventReportItemValueSet{
+					Period: period,
+					Value:  1,
+				},
+			},
+		},
+		{
+			state:  setupReportItem(t, reportItemID, models.ReportItemTypeChecklist),
+			period: period,
+			value:  2,
+			err:    fmt.Errorf("checklist item value must be 0 or 1"),
+		},
+		{
+			state:  appendStoredEvents(t, setupReportItem(t, reportItemID, models.ReportItemTypeMetric), ep.NewEventReportItemDeleted(util.NilID, util.NilID)),
+			period: period,
+			value:  10,
+			err:    fmt.Errorf("report item is deleted"),
+		},
+	}
+
+	for _, tt := range tests {
+		aggregate := NewReportItem(uidGenerator, reportItemID)
+
+		command := commands.NewCommand(commands.CommandTypeSetReportItemValue, correlationID, causationID, util.NilID, &commands.SetReportItemValue{
+			Period: tt.period,
+			Value:  tt.value,
+		})
+
+		test := &testData{
+			State:     tt.state,
+			Aggregate: aggregate,
+			Command:   command,
+			Out:       tt.out,
+			Err:       tt.err,
+		}
+
+		runTest(t, test)
+	}
+}
@@ -2,8 +2,11 @@ package aggregate
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/sorintlab/sircles/command/commands"
 	"github.com/sorintlab/sircles/common"
@@ -15,6 +18,7 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 const (
@@ -25,13 +29,88 @@ func newDB(dataDir string) (*db.DB, error) {
 	return db.NewDB("sqlite3", filepath.Join(dataDir, dbName))
 }
 
+// CheckRolesTreeDB checks that the rolestree snapshot db saved in dataDir is
+// consistent with the eventstore: the last event applied to the snapshot db
+// must be the same event saved in the eventstore at that version. If not (i.e.
+// the eventstore has been reset or replaced, the snapshot db is corrupted or
+// was created by a previous version without all the current columns) the
+// snapshot db is removed so it'll be rebuilt from scratch at the next
+// load.
+// It must be called before any rolestree repository is used.
+func CheckRolesTreeDB(dataDir string, es eventstore.EventStore) error {
+	dbPath := filepath.Join(dataDir, dbName)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	valid, err := checkRolesTreeDB(dataDir, es)
+	if err != nil {
+		return err
+	}
+	if valid {
+		return nil
+	}
+
+	log.Infof("rolestree snapshot db is stale or corrupted, it will be rebuilt")
+	if err := os.Remove(dbPath); err != nil {
+		return errors.Wrapf(err, "failed to remove rolestree snapshot db")
+	}
+	return nil
+}
+
+func checkRolesTreeDB(dataDir string, es eventstore.EventStore) (bool, error) {
+	ldb, err := newDB(dataDir)
+	if err != nil {
+		log.Errorf("failed to open rolestree snapshot db: %+v", err)
+		return false, nil
+	}
+	defer ldb.Close()
+
+	var version int64
+	var eventID util.ID
+	err = ldb.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			return tx.QueryRow("select version, eventid from version limit 1").Scan(&version, &eventID)
+		})
+	})
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		log.Errorf("failed to read rolestree snapshot db version: %+v", err)
+		return false, nil
+	}
+
+	// snapshot db created before the core roles election expiration was saved
+	err = ldb.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			_, err := tx.Exec("select electionexpiration from rolemember limit 1")
+			return err
+		})
+	})
+	if err != nil {
+		log.Errorf("failed to read rolestree snapshot db role members: %+v", err)
+		return false, nil
+	}
+
+	events, err := es.GetEvents(RolesTreeAggregateID.String(), version, 1)
+	if err != nil {
+		return false, err
+	}
+	if len(events) == 0 || events[0].Version != version || events[0].ID != eventID {
+		return false, nil
+	}
+
+	return true, nil
+}
+
 type RolesTreeRepository struct {
 	dataDir      string
-	es           *eventstore.EventStore
+	es           eventstore.EventStore
 	uidGenerator common.UIDGenerator
 }
 
-func NewRolesTreeRepository(dataDir string, es *eventstore.EventStore, uidGenerator common.UIDGenerator) *RolesTreeRepository {
+func NewRolesTreeRepository(dataDir string, es eventstore.EventStore, uidGenerator common.UIDGenerator) *RolesTreeRepository {
 	return &RolesTreeRepository{dataDir: dataDir, es: es, uidGenerator: uidGenerator}
 }
 
@@ -164,6 +243,8 @@ func (r *RolesTree) HandleCommand(command *commands.Command) ([]ep.Event, error)
 			events, err = r.HandleCircleUpdateChildRoleCommand(tx, command)
 		case commands.CommandTypeCircleDeleteChildRole:
 			events, err = r.HandleCircleDeleteChildRoleCommand(tx, command)
+		case commands.CommandTypeCircleApplyProposal:
+			events, err = r.HandleCircleApplyProposalCommand(tx, command)
 		case commands.CommandTypeSetRoleAdditionalContent:
 			events, err = r.HandleSetRoleAdditionalContentCommand(tx, command)
 		case commands.CommandTypeCircleAddDirectMember:
@@ -178,12 +259,16 @@ func (r *RolesTree) HandleCommand(command *commands.Command) ([]ep.Event, error)
 			events, err = r.HandleCircleSetCoreRoleMemberCommand(tx, command)
 		case commands.CommandTypeCircleUnsetCoreRoleMember:
 			events, err = r.HandleCircleUnsetCoreRoleMemberCommand(tx, command)
+		case commands.CommandTypeCircleExpireCoreRoleElection:
+			events, err = r.HandleCircleExpireCoreRoleElectionCommand(tx, command)
 		case commands.CommandTypeRoleAddMember:
 			events, err = r.HandleRoleAddMemberCommand(tx, command)
 		case commands.CommandTypeRoleRemoveMember:
 			events, err = r.HandleRoleRemoveMemberCommand(tx, command)
 		case commands.CommandTypeRoleUpdateMember:
 			events, err = r.HandleRoleUpdateMemberCommand(tx, command)
+		case commands.CommandTypeRemoveMemberFromRoles:
+			events, err = r.HandleRemoveMemberFromRolesCommand(tx, command)
 
 		default:
 			err = errors.Errorf("unhandled command: %#v", command)
@@ -752,6 +837,9 @@ func (r *RolesTree) HandleCircleDeleteChildRoleCommand(tx *db.Tx, command *comma
 	if err != nil {
 		return nil, err
 	}
+	if childRole == nil {
+		return nil, errors.Errorf("role with id %s doesn't exist", c.DeleteRoleChange.ID)
+	}
 
 	childRoleParent, err := r.roleParent(tx, c.RoleID)
 	if err != nil {
@@ -792,6 +880,112 @@ func (r *RolesTree) HandleCircleDeleteChildRoleCommand(tx *db.Tx, command *comma
 	return events, nil
 }
 
+// HandleCircleApplyProposalCommand applies all the proposal changes in a single
+// command so they'll be atomically saved in the same events group.
+// Every change is checked against the roles tree as modified by the previous
+// changes so the generated events are temporarily applied to the snapshot db
+// inside a savepoint that is always rolled back.
+func (r *RolesTree) HandleCircleApplyProposalCommand(tx *db.Tx, command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	c := command.Data.(*commands.CircleApplyProposal)
+
+	if len(c.NewRoleIDs) != len(c.CreateRoleChanges) {
+		return nil, errors.Errorf("wrong number of new role ids: %d, expected: %d", len(c.NewRoleIDs), len(c.CreateRoleChanges))
+	}
+
+	changeCommands := []*commands.Command{}
+	for i, createRoleChange := range c.CreateRoleChanges {
+		changeCommands = append(changeCommands, commands.NewCommand(commands.CommandTypeCircleCreateChildRole, command.CorrelationID, command.ID, command.IssuerID, &commands.CircleCreateChildRole{RoleID: c.RoleID, NewRoleID: c.NewRoleIDs[i], CreateRoleChange: createRoleChange}))
+	}
+	for _, updateRoleChange := range c.UpdateRoleChanges {
+		changeCommands = append(changeCommands, commands.NewCommand(commands.CommandTypeCircleUpdateChildRole, command.CorrelationID, command.ID, command.IssuerID, &commands.CircleUpdateChildRole{RoleID: c.RoleID, UpdateRoleChange: updateRoleChange}))
+	}
+	for _, deleteRoleChange := range c.DeleteRoleChanges {
+		changeCommands = append(changeCommands, commands.NewCommand(commands.CommandTypeCircleDeleteChildRole, command.CorrelationID, command.ID, command.IssuerID, &commands.CircleDeleteChildRole{RoleID: c.RoleID, DeleteRoleChange: deleteRoleChange}))
+	}
+
+	version, err := r.curVersion(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err := tx.Exec("savepoint applyproposal")
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create savepoint")
+	}
+
+	applyErr := func() error {
+		for _, changeCommand := range changeCommands {
+			var es []ep.Event
+			var err error
+			switch changeCommand.CommandType {
+			case commands.CommandTypeCircleCreateChildRole:
+				es, err = r.HandleCircleCreateChildRoleCommand(tx, changeCommand)
+			case commands.CommandTypeCircleUpdateChildRole:
+				es, err = r.HandleCircleUpdateChildRoleCommand(tx, changeCommand)
+			case commands.CommandTypeCircleDeleteChildRole:
+				es, err = r.HandleCircleDeleteChildRoleCommand(tx, changeCommand)
+			}
+			if err != nil {
+				return err
+			}
+
+			for _, e := range es {
+				version++
+				se, err := toSnapshotStoredEvent(e, version)
+				if err != nil {
+					return err
+				}
+				if err := r.ApplyEvent(tx, se); err != nil {
+					return err
+				}
+			}
+
+			events = append(events, es...)
+		}
+		return nil
+	}()
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		if _, err := tx.Exec("rollback to savepoint applyproposal"); err != nil {
+			return err
+		}
+		_, err := tx.Exec("release savepoint applyproposal")
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to rollback to savepoint")
+	}
+
+	if applyErr != nil {
+		return nil, applyErr
+	}
+
+	events = append(events, ep.NewEventCircleProposalApplied(c.RoleID, c.ProposalID))
+
+	return events, nil
+}
+
+// toSnapshotStoredEvent creates a stored event, not saved in the event store,
+// to be applied only to the snapshot db
+func toSnapshotStoredEvent(e ep.Event, version int64) (*eventstore.StoredEvent, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &eventstore.StoredEvent{
+		ID:        util.NewFromUUID(uuid.NewV4()),
+		EventType: e.EventType().String(),
+		Category:  RolesTreeAggregate.String(),
+		Version:   version,
+		Data:      data,
+	}, nil
+}
+
 func (r *RolesTree) HandleSetRoleAdditionalContentCommand(tx *db.Tx, command *commands.Command) ([]ep.Event, error) {
 	events := []ep.Event{}
 
@@ -982,6 +1176,62 @@ func (r *RolesTree) HandleCircleUnsetCoreRoleMemberCommand(tx *db.Tx, command *c
 	return events, nil
 }
 
+func (r *RolesTree) HandleCircleExpireCoreRoleElectionCommand(tx *db.Tx, command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	c := command.Data.(*commands.CircleExpireCoreRoleElection)
+
+	if !c.RoleType.IsElectedRoleType() {
+		return nil, errors.Errorf("role type %s isn't an elected role", c.RoleType)
+	}
+
+	role, err := r.role(tx, c.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, errors.Errorf("role with id %s doesn't exist", c.RoleID)
+	}
+	if role.RoleType != models.RoleTypeCircle {
+		return nil, errors.Errorf("role with id %s isn't a circle", c.RoleID)
+	}
+	coreRole, err := r.circleCoreRole(tx, c.RoleID, c.RoleType)
+	if err != nil {
+		return nil, err
+	}
+	if coreRole == nil {
+		return nil, errors.Errorf("circle %s doesn't have a %s core role", c.RoleID, c.RoleType)
+	}
+
+	coreRoleMembersIDs, err := r.roleMembersIDs(tx, coreRole.ID)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, memberID := range coreRoleMembersIDs {
+		if memberID == c.MemberID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.Errorf("member with id %s isn't assigned to core role %s", c.MemberID, coreRole.ID)
+	}
+
+	// the member could have been reelected with a new election expiration
+	electionExpiration, err := r.roleMemberElectionExpiration(tx, coreRole.ID, c.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	if electionExpiration == nil || !electionExpiration.Equal(c.ElectionExpiration) {
+		return nil, errors.Errorf("member with id %s core role %s election expiration isn't %s", c.MemberID, coreRole.ID, c.ElectionExpiration)
+	}
+
+	events = append(events, ep.NewEventCircleCoreRoleElectionExpired(c.RoleID, coreRole.ID, c.MemberID, c.RoleType, c.ElectionExpiration))
+
+	return events, nil
+}
+
 func (r *RolesTree) HandleRoleAddMemberCommand(tx *db.Tx, command *commands.Command) ([]ep.Event, error) {
 	events := []ep.Event{}
 
@@ -1079,6 +1329,55 @@ func (r *RolesTree) HandleRoleUpdateMemberCommand(tx *db.Tx, command *commands.C
 	return events, nil
 }
 
+// HandleRemoveMemberFromRolesCommand removes the member from all the roles and
+// circles it's assigned to
+func (r *RolesTree) HandleRemoveMemberFromRolesCommand(tx *db.Tx, command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	c := command.Data.(*commands.RemoveMemberFromRoles)
+
+	memberRolesIDs, err := r.memberRolesIDs(tx, c.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	for _, roleID := range memberRolesIDs {
+		role, err := r.role(tx, roleID)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			return nil, errors.Errorf("role with id %s doesn't exist", roleID)
+		}
+
+		switch {
+		case role.RoleType == models.RoleTypeLeadLink:
+			parentID, err := r.roleParentID(tx, roleID)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, ep.NewEventCircleLeadLinkMemberUnset(*parentID, roleID, c.MemberID))
+		case role.RoleType.IsCoreRoleType():
+			parentID, err := r.roleParentID(tx, roleID)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, ep.NewEventCircleCoreRoleMemberUnset(*parentID, roleID, c.MemberID, role.RoleType))
+		default:
+			events = append(events, ep.NewEventRoleMemberRemoved(roleID, c.MemberID))
+		}
+	}
+
+	memberCirclesIDs, err := r.memberCirclesIDs(tx, c.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	for _, roleID := range memberCirclesIDs {
+		events = append(events, ep.NewEventCircleDirectMemberRemoved(roleID, c.MemberID))
+	}
+
+	return events, nil
+}
+
 func (r *RolesTree) deleteRoleRecursive(tx *db.Tx, roleID util.ID, skipchilds []util.ID) ([]ep.Event, error) {
 	events := []ep.Event{}
 
@@ -1433,15 +1732,22 @@ func (r *RolesTree) ApplyEvent(tx *db.Tx, event *eventstore.StoredEvent) error {
 		if err := r.roleAddMember(tx, data.CoreRoleID, data.MemberID); err != nil {
 			return err
 		}
+		if err := r.roleSetMemberElectionExpiration(tx, data.CoreRoleID, data.MemberID, data.ElectionExpiration); err != nil {
+			return err
+		}
 
 	case ep.EventTypeCircleCoreRoleMemberUnset:
 		data := data.(*ep.EventCircleCoreRoleMemberUnset)
 		if err := r.roleRemoveMember(tx, data.CoreRoleID, data.MemberID); err != nil {
 			return err
 		}
+
+	case ep.EventTypeCircleCoreRoleElectionExpired:
+
+	case ep.EventTypeCircleProposalApplied:
 	}
 
-	if err := r.updateVersion(tx, event.Version); err != nil {
+	if err := r.updateVersion(tx, event.Version, event.ID); err != nil {
 		return err
 	}
 
@@ -1456,8 +1762,8 @@ var rolesTreeDBCreateStmts = []string{
 	"create table if not exists accountability (id uuid, roleid uuid, description varchar, PRIMARY KEY (id))",
 	"create table if not exists roleadditionalcontent (id uuid, roleid uuid, content varchar, PRIMARY KEY (id))",
 	"create table if not exists circledirectmember (memberid uuid, roleid uuid)",
-	"create table if not exists rolemember (memberid uuid, roleid uuid)",
-	"create table if not exists version (version bigint)",
+	"create table if not exists rolemember (memberid uuid, roleid uuid, electionexpiration timestamptz)",
+	"create table if not exists version (version bigint, eventid uuid)",
 }
 
 var (
@@ -1479,17 +1785,19 @@ var (
 	accountabilityUpdate = sb.Update("accountability")
 
 	roleMemberSelect = sb.Select("memberid").From("rolemember")
+	memberRoleSelect = sb.Select("roleid").From("rolemember")
 	roleMemberInsert = sb.Insert("rolemember").Columns("memberid", "roleid")
 	roleMemberDelete = sb.Delete("rolemember")
 	roleMemberUpdate = sb.Update("rolemember")
 
 	circleDirectMemberSelect = sb.Select("memberid").From("circledirectmember")
+	memberCircleSelect       = sb.Select("roleid").From("circledirectmember")
 	circleDirectMemberInsert = sb.Insert("circledirectmember").Columns("memberid", "roleid")
 	circleDirectMemberDelete = sb.Delete("circledirectmember")
 	circleDirectMemberUpdate = sb.Update("circledirectmember")
 
 	versionSelect = sb.Select("version").From("version")
-	versionInsert = sb.Insert("version").Columns("version")
+	versionInsert = sb.Insert("version").Columns("version", "eventid")
 	versionDelete = sb.Delete("version")
 )
 
@@ -1573,6 +1881,41 @@ func (r *RolesTree) roleAddMember(tx *db.Tx, roleID, memberID util.ID) error {
 	return nil
 }
 
+func (r *RolesTree) roleSetMemberElectionExpiration(tx *db.Tx, roleID, memberID util.ID, electionExpiration *time.Time) error {
+	q, args, err := roleMemberUpdate.Set("electionexpiration", electionExpiration).Where(sq.Eq{"roleid": roleID, "memberid": memberID}).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update election expiration of member id %s in role id %s", memberID, roleID)
+	}
+	return nil
+}
+
+func (r *RolesTree) roleMemberElectionExpiration(tx *db.Tx, roleID, memberID util.ID) (*time.Time, error) {
+	q, args, err := sb.Select("electionexpiration").From("rolemember").Where(sq.Eq{"roleid": roleID, "memberid": memberID}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+
+	var electionExpiration *time.Time
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow(q, args...).Scan(&electionExpiration)
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query election expiration of member id %s in role id %s", memberID, roleID)
+	}
+	return electionExpiration, nil
+}
+
 func (r *RolesTree) roleRemoveMember(tx *db.Tx, roleID, memberID util.ID) error {
 	q, args, err := roleMemberDelete.Where(sq.Eq{"roleid": roleID, "memberid": memberID}).ToSql()
 	if err != nil {
@@ -1717,7 +2060,7 @@ func (r *RolesTree) updateAccountability(tx *db.Tx, id util.ID, accountability *
 	return nil
 }
 
-func (r *RolesTree) updateVersion(tx *db.Tx, version int64) error {
+func (r *RolesTree) updateVersion(tx *db.Tx, version int64, eventID util.ID) error {
 	q, args, err := versionDelete.ToSql()
 	if err != nil {
 		return errors.Wrap(err, "failed to build query")
@@ -1730,7 +2073,7 @@ func (r *RolesTree) updateVersion(tx *db.Tx, version int64) error {
 		return errors.Wrapf(err, "failed to delete version: %v", version)
 	}
 
-	q, args, err = versionInsert.Values(version).ToSql()
+	q, args, err = versionInsert.Values(version, eventID).ToSql()
 	if err != nil {
 		return errors.Wrap(err, "failed to build query")
 	}
@@ -1799,6 +2142,35 @@ func (r *RolesTree) roleParent(tx *db.Tx, roleID util.ID) (*models.Role, error)
 	return r.role(tx, role.ID)
 }
 
+func (r *RolesTree) roleParentID(tx *db.Tx, roleID util.ID) (*util.ID, error) {
+	q, args, err := roleSelect.Where(sq.Eq{"id": roleID}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+
+	var parentID *util.ID
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		// To make sqlite3 happy
+		var id util.ID
+		var roleType, name, purpose string
+		row := tx.QueryRow(q, args...)
+		if err := row.Scan(&id, &parentID, &roleType, &name, &purpose); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil, errors.Errorf("role with id %s doesn't exist", roleID)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query parent of role with id: %s", roleID)
+	}
+	if parentID == nil {
+		return nil, errors.Errorf("role with id %s doesn't have a parent", roleID)
+	}
+	return parentID, nil
+}
+
 func (r *RolesTree) rootRole(tx *db.Tx) (*models.Role, error) {
 	q, args, err := roleSelect.Where(sq.Eq{"parentid": nil}).ToSql()
 	if err != nil {
@@ -1960,6 +2332,42 @@ func (r *RolesTree) circleDirectMembersIDs(tx *db.Tx, roleID util.ID) ([]util.ID
 	return members, nil
 }
 
+func (r *RolesTree) memberRolesIDs(tx *db.Tx, memberID util.ID) ([]util.ID, error) {
+	return r.queryMemberRolesIDs(tx, memberRoleSelect, memberID)
+}
+
+func (r *RolesTree) memberCirclesIDs(tx *db.Tx, memberID util.ID) ([]util.ID, error) {
+	return r.queryMemberRolesIDs(tx, memberCircleSelect, memberID)
+}
+
+func (r *RolesTree) queryMemberRolesIDs(tx *db.Tx, sel sq.SelectBuilder, memberID util.ID) ([]util.ID, error) {
+	q, args, err := sel.Where(sq.Eq{"memberid": memberID}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+
+	roles := []util.ID{}
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return errors.Wrap(err, "failed to execute query")
+		}
+		defer rows.Close()
+		for rows.Next() {
+			role := util.ID{}
+			if err := rows.Scan(&role); err != nil {
+				return errors.Wrap(err, "failed to scan rows")
+			}
+			roles = append(roles, role)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query roles for member with id: %s", memberID)
+	}
+	return roles, nil
+}
+
 func (r *RolesTree) roleDomains(tx *db.Tx, roleID util.ID) ([]*models.Domain, error) {
 	q, args, err := domainSelect.Where(sq.Eq{"roleid": roleID}).ToSql()
 	if err != nil {
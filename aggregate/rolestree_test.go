@@ -1,14 +1,18 @@
 package aggregate
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/sorintlab/sircles/change"
 	"github.com/sorintlab/sircles/command/commands"
 	ep "github.com/sorintlab/sircles/events"
 	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
 	"github.com/sorintlab/sircles/util"
 )
 
@@ -395,3 +399,153 @@ func TestCircleCreateChildRole2(t *testing.T) {
 
 	runTest(t, test)
 }
+
+func TestCircleExpireCoreRoleElection(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	rootRoleID := uidGenerator.UUID("General")
+	facilitatorRoleID := util.IDFromStringOrNil("12accb8f-657c-53f8-9571-0fb44734c07b")
+	memberID := uidGenerator.UUID("member01")
+	electionExpiration := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+	newElectionExpiration := time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		state []*eventstore.StoredEvent
+		out   []ep.Event
+		err   error
+	}{
+		{
+			state: appendStoredEvents(t, setupRolesTree(t), ep.NewEventCircleCoreRoleMemberSet(rootRoleID, facilitatorRoleID, memberID, models.RoleTypeFacilitator, &electionExpiration)),
+			out: []ep.Event{
+				&ep.EventCircleCoreRoleElectionExpired{
+					RoleID:             rootRoleID,
+					RoleType:           models.RoleTypeFacilitator,
+					MemberID:           memberID,
+					ElectionExpiration: electionExpiration,
+					CoreRoleID:         facilitatorRoleID,
+				},
+			},
+		},
+		// the member has been unassigned from the core role
+		{
+			state: appendStoredEvents(t, setupRolesTree(t),
+				ep.NewEventCircleCoreRoleMemberSet(rootRoleID, facilitatorRoleID, memberID, models.RoleTypeFacilitator, &electionExpiration),
+				ep.NewEventCircleCoreRoleMemberUnset(rootRoleID, facilitatorRoleID, memberID, models.RoleTypeFacilitator),
+			),
+			err: fmt.Errorf("member with id %s isn't assigned to core role %s", memberID, facilitatorRoleID),
+		},
+		// the member has been reelected with a new election expiration
+		{
+			state: appendStoredEvents(t, setupRolesTree(t),
+				ep.NewEventCircleCoreRoleMemberSet(rootRoleID, facilitatorRoleID, memberID, models.RoleTypeFacilitator, &electionExpiration),
+				ep.NewEventCircleCoreRoleMemberUnset(rootRoleID, facilitatorRoleID, memberID, models.RoleTypeFacilitator),
+				ep.NewEventCircleCoreRoleMemberSet(rootRoleID, facilitatorRoleID, memberID, models.RoleTypeFacilitator, &newElectionExpiration),
+			),
+			err: fmt.Errorf("member with id %s core role %s election expiration isn't %s", memberID, facilitatorRoleID, electionExpiration),
+		},
+	}
+
+	for _, tt := range tests {
+		tmpDir, err := ioutil.TempDir("", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		aggregate, err := NewRolesTree(tmpDir, uidGenerator, RolesTreeAggregateID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		command := commands.NewCommand(commands.CommandTypeCircleExpireCoreRoleElection, correlationID, causationID, util.NilID, &commands.CircleExpireCoreRoleElection{
+			RoleType:           models.RoleTypeFacilitator,
+			RoleID:             rootRoleID,
+			MemberID:           memberID,
+			ElectionExpiration: electionExpiration,
+		})
+
+		test := &testData{
+			State:     tt.state,
+			Aggregate: aggregate,
+			Command:   command,
+			Out:       tt.out,
+			Err:       tt.err,
+		}
+
+		runTest(t, test)
+	}
+}
+
+func TestCheckRolesTreeDB(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dataDir := filepath.Join(tmpDir, "data")
+	if err := os.Mkdir(dataDir, 0700); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dbPath := filepath.Join(dataDir, dbName)
+
+	uidGenerator := NewTestUIDGen()
+
+	es := newTestEventStore()
+
+	// no snapshot db
+	if err := CheckRolesTreeDB(dataDir, es); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeSetupRootRole, correlationID, causationID, util.NilID, &commands.SetupRootRole{
+		RootRoleID: uidGenerator.UUID("General"),
+		Name:       "General",
+	})
+
+	rtr := NewRolesTreeRepository(dataDir, es, uidGenerator)
+	rt, err := rtr.Load(RolesTreeAggregateID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := ExecCommand(command, rt, es, uidGenerator); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// load to update the snapshot db
+	if _, err := rtr.Load(RolesTreeAggregateID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// consistent snapshot db must be kept
+	if err := CheckRolesTreeDB(dataDir, es); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		t.Fatalf("expected snapshot db to exist: %v", err)
+	}
+
+	// snapshot db not consistent with a new eventstore must be removed
+	es = newTestEventStore()
+	if err := CheckRolesTreeDB(dataDir, es); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(dbPath); !os.IsNotExist(err) {
+		t.Fatalf("expected snapshot db to be removed")
+	}
+
+	// corrupted snapshot db must be removed
+	if err := ioutil.WriteFile(dbPath, []byte("corrupted"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CheckRolesTreeDB(dataDir, es); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(dbPath); !os.IsNotExist(err) {
+		t.Fatalf("expected snapshot db to be removed")
+	}
+}
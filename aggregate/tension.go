@@ -1,6 +1,7 @@
 package aggregate
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -13,11 +14,11 @@ import (
 )
 
 type TensionRepository struct {
-	es           *eventstore.EventStore
+	es           eventstore.EventStore
 	uidGenerator common.UIDGenerator
 }
 
-func NewTensionRepository(es *eventstore.EventStore, uidGenerator common.UIDGenerator) *TensionRepository {
+func NewTensionRepository(es eventstore.EventStore, uidGenerator common.UIDGenerator) *TensionRepository {
 	return &TensionRepository{es: es, uidGenerator: uidGenerator}
 }
 
@@ -39,6 +40,12 @@ type Tension struct {
 	title       string
 	description string
 	roleID      *util.ID
+	state       models.TensionState
+	labels      []string
+	commentsIDs []util.ID
+	// the assignee is a member filling a role
+	assigneeRoleID   *util.ID
+	assigneeMemberID *util.ID
 
 	created      bool
 	uidGenerator common.UIDGenerator
@@ -75,6 +82,16 @@ func (t *Tension) HandleCommand(command *commands.Command) ([]ep.Event, error) {
 		events, err = t.HandleChangeTensionRoleCommand(command)
 	case commands.CommandTypeCloseTension:
 		events, err = t.HandleCloseTensionCommand(command)
+	case commands.CommandTypeReopenTension:
+		events, err = t.HandleReopenTensionCommand(command)
+	case commands.CommandTypeChangeTensionState:
+		events, err = t.HandleChangeTensionStateCommand(command)
+	case commands.CommandTypeAddTensionComment:
+		events, err = t.HandleAddTensionCommentCommand(command)
+	case commands.CommandTypeAssignTension:
+		events, err = t.HandleAssignTensionCommand(command)
+	case commands.CommandTypeSetTensionLabels:
+		events, err = t.HandleSetTensionLabelsCommand(command)
 
 	default:
 		err = fmt.Errorf("unhandled command: %#v", command)
@@ -156,6 +173,13 @@ func (t *Tension) HandleChangeTensionRoleCommand(command *commands.Command) ([]e
 func (t *Tension) HandleCloseTensionCommand(command *commands.Command) ([]ep.Event, error) {
 	events := []ep.Event{}
 
+	if !t.created {
+		return nil, errors.New("unexistent tension")
+	}
+	if t.state == models.TensionStateClosed {
+		return nil, errors.New("tension already closed")
+	}
+
 	c := command.Data.(*commands.CloseTension)
 
 	events = append(events, ep.NewEventTensionClosed(t.id, c.Reason))
@@ -163,6 +187,126 @@ func (t *Tension) HandleCloseTensionCommand(command *commands.Command) ([]ep.Eve
 	return events, nil
 }
 
+func (t *Tension) HandleReopenTensionCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if !t.created {
+		return nil, errors.New("unexistent tension")
+	}
+	if t.state != models.TensionStateClosed {
+		return nil, errors.New("tension not closed")
+	}
+
+	events = append(events, ep.NewEventTensionReopened(t.roleID))
+
+	return events, nil
+}
+
+func (t *Tension) HandleChangeTensionStateCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if err := t.checkNotClosed(); err != nil {
+		return nil, err
+	}
+
+	c := command.Data.(*commands.ChangeTensionState)
+
+	switch c.State {
+	case models.TensionStateOpen, models.TensionStateInProgress, models.TensionStateProcessed:
+	default:
+		return nil, errors.Errorf("wrong tension state %q", c.State)
+	}
+	if c.State == t.state {
+		return nil, errors.Errorf("tension already in state %s", c.State)
+	}
+
+	events = append(events, ep.NewEventTensionStateChanged(c.State, t.state))
+
+	return events, nil
+}
+
+func (t *Tension) HandleAddTensionCommentCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if !t.created {
+		return nil, errors.New("unexistent tension")
+	}
+
+	c := command.Data.(*commands.AddTensionComment)
+
+	if t.hasComment(c.CommentID) {
+		return nil, errors.New("comment already exists")
+	}
+	if c.ParentCommentID != nil && !t.hasComment(*c.ParentCommentID) {
+		return nil, errors.New("unexistent parent comment")
+	}
+
+	events = append(events, ep.NewEventTensionCommentAdded(c.CommentID, c.ParentCommentID, c.MemberID, c.Text))
+
+	return events, nil
+}
+
+func (t *Tension) HandleAssignTensionCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if err := t.checkNotClosed(); err != nil {
+		return nil, err
+	}
+
+	c := command.Data.(*commands.AssignTension)
+
+	if (c.RoleID == nil) != (c.MemberID == nil) {
+		return nil, errors.New("both the assignee role and member must be provided")
+	}
+	if equalIDs(c.RoleID, t.assigneeRoleID) && equalIDs(c.MemberID, t.assigneeMemberID) {
+		return nil, errors.New("tension assignee not changed")
+	}
+
+	events = append(events, ep.NewEventTensionAssigneeChanged(c.RoleID, c.MemberID, t.assigneeRoleID, t.assigneeMemberID))
+
+	return events, nil
+}
+
+func (t *Tension) HandleSetTensionLabelsCommand(command *commands.Command) ([]ep.Event, error) {
+	events := []ep.Event{}
+
+	if err := t.checkNotClosed(); err != nil {
+		return nil, err
+	}
+
+	c := command.Data.(*commands.SetTensionLabels)
+
+	events = append(events, ep.NewEventTensionLabelsSet(c.Labels))
+
+	return events, nil
+}
+
+func (t *Tension) checkNotClosed() error {
+	if !t.created {
+		return errors.New("unexistent tension")
+	}
+	if t.state == models.TensionStateClosed {
+		return errors.New("tension is closed")
+	}
+	return nil
+}
+
+func (t *Tension) hasComment(id util.ID) bool {
+	for _, commentID := range t.commentsIDs {
+		if commentID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func equalIDs(a, b *util.ID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func (t *Tension) ApplyEvents(events []*eventstore.StoredEvent) error {
 	for _, e := range events {
 		if err := t.ApplyEvent(e); err != nil {
@@ -189,6 +333,7 @@ func (t *Tension) ApplyEvent(event *eventstore.StoredEvent) error {
 		t.title = data.Title
 		t.description = data.Description
 		t.roleID = data.RoleID
+		t.state = models.TensionStateOpen
 
 		t.created = true
 
@@ -204,8 +349,87 @@ func (t *Tension) ApplyEvent(event *eventstore.StoredEvent) error {
 		t.roleID = data.RoleID
 
 	case ep.EventTypeTensionClosed:
+		t.state = models.TensionStateClosed
+
+	case ep.EventTypeTensionReopened:
+		t.state = models.TensionStateOpen
+
+	case ep.EventTypeTensionStateChanged:
+		data := data.(*ep.EventTensionStateChanged)
+
+		t.state = data.State
 
+	case ep.EventTypeTensionCommentAdded:
+		data := data.(*ep.EventTensionCommentAdded)
+
+		t.commentsIDs = append(t.commentsIDs, data.CommentID)
+
+	case ep.EventTypeTensionAssigneeChanged:
+		data := data.(*ep.EventTensionAssigneeChanged)
+
+		t.assigneeRoleID = data.RoleID
+		t.assigneeMemberID = data.MemberID
+
+	case ep.EventTypeTensionLabelsSet:
+		data := data.(*ep.EventTensionLabelsSet)
+
+		t.labels = data.Labels
 	}
 
 	return nil
 }
+
+type tensionSnapshot struct {
+	Title            string
+	Description      string
+	RoleID           *util.ID
+	State            models.TensionState
+	Labels           []string
+	CommentsIDs      []util.ID
+	AssigneeRoleID   *util.ID
+	AssigneeMemberID *util.ID
+
+	Created bool
+}
+
+func (t *Tension) Snapshot() ([]byte, error) {
+	return json.Marshal(&tensionSnapshot{
+		Title:            t.title,
+		Description:      t.description,
+		RoleID:           t.roleID,
+		State:            t.state,
+		Labels:           t.labels,
+		CommentsIDs:      t.commentsIDs,
+		AssigneeRoleID:   t.assigneeRoleID,
+		AssigneeMemberID: t.assigneeMemberID,
+
+		Created: t.created,
+	})
+}
+
+func (t *Tension) RestoreSnapshot(version int64, data []byte) error {
+	var s tensionSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Wrap(err, "failed to unmarshal tension snapshot")
+	}
+	// snapshots saved before the tension state was tracked cannot tell if
+	// the tension is closed
+	if s.Created && s.State == "" {
+		return errIncompatibleSnapshot
+	}
+
+	t.version = version
+
+	t.title = s.Title
+	t.description = s.Description
+	t.roleID = s.RoleID
+	t.state = s.State
+	t.labels = s.Labels
+	t.commentsIDs = s.CommentsIDs
+	t.assigneeRoleID = s.AssigneeRoleID
+	t.assigneeMemberID = s.AssigneeMemberID
+
+	t.created = s.Created
+
+	return nil
+}
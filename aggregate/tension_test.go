@@ -7,6 +7,7 @@ import (
 	"github.com/sorintlab/sircles/command/commands"
 	ep "github.com/sorintlab/sircles/events"
 	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
 	"github.com/sorintlab/sircles/util"
 )
 
@@ -150,3 +151,393 @@ func TestUpdateNotExistingTension(t *testing.T) {
 
 	runTest(t, test)
 }
+
+func TestReopenTension(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	tensionID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	tests := []struct {
+		state []*eventstore.StoredEvent
+		out   []ep.Event
+		err   error
+	}{
+		{
+			state: appendStoredEvents(t, setupTension(t, tensionID), ep.NewEventTensionClosed(tensionID, "reason")),
+			out: []ep.Event{
+				&ep.EventTensionReopened{},
+			},
+		},
+		{
+			state: setupTension(t, tensionID),
+			err:   fmt.Errorf("tension not closed"),
+		},
+		{
+			err: fmt.Errorf("unexistent tension"),
+		},
+	}
+
+	for _, tt := range tests {
+		aggregate := NewTension(uidGenerator, tensionID)
+
+		command := commands.NewCommand(commands.CommandTypeReopenTension, correlationID, causationID, util.NilID, &commands.ReopenTension{})
+
+		test := &testData{
+			State:     tt.state,
+			Aggregate: aggregate,
+			Command:   command,
+			Out:       tt.out,
+			Err:       tt.err,
+		}
+
+		runTest(t, test)
+	}
+}
+
+func TestCloseTension(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	tensionID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	tests := []struct {
+		state []*eventstore.StoredEvent
+		out   []ep.Event
+		err   error
+	}{
+		{
+			state: setupTension(t, tensionID),
+			out: []ep.Event{
+				&ep.EventTensionClosed{Reason: "reason"},
+			},
+		},
+		{
+			state: appendStoredEvents(t, setupTension(t, tensionID), ep.NewEventTensionClosed(tensionID, "reason")),
+			err:   fmt.Errorf("tension already closed"),
+		},
+		// a reopened tension can be closed again
+		{
+			state: appendStoredEvents(t, setupTension(t, tensionID), ep.NewEventTensionClosed(tensionID, "reason"), ep.NewEventTensionReopened(nil)),
+			out: []ep.Event{
+				&ep.EventTensionClosed{Reason: "reason"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		aggregate := NewTension(uidGenerator, tensionID)
+
+		command := commands.NewCommand(commands.CommandTypeCloseTension, correlationID, causationID, util.NilID, &commands.CloseTension{
+			Reason: "reason",
+		})
+
+		test := &testData{
+			State:     tt.state,
+			Aggregate: aggregate,
+			Command:   command,
+			Out:       tt.out,
+			Err:       tt.err,
+		}
+
+		runTest(t, test)
+	}
+}
+
+func TestChangeTensionState(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	tensionID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	tests := []struct {
+		state        []*eventstore.StoredEvent
+		tensionState models.TensionState
+		out          []ep.Event
+		err          error
+	}{
+		{
+			state:        setupTension(t, tensionID),
+			tensionState: models.TensionStateInProgress,
+			out: []ep.Event{
+				&ep.EventTensionStateChanged{
+					State:         models.TensionStateInProgress,
+					PreviousState: models.TensionStateOpen,
+				},
+			},
+		},
+		{
+			state:        appendStoredEvents(t, setupTension(t, tensionID), ep.NewEventTensionStateChanged(models.TensionStateInProgress, models.TensionStateOpen)),
+			tensionState: models.TensionStateInProgress,
+			err:          fmt.Errorf("tension already in state inprogress"),
+		},
+		// closing must be done with the close command
+		{
+			state:        setupTension(t, tensionID),
+			tensionState: models.TensionStateClosed,
+			err:          fmt.Errorf(`wrong tension state "closed"`),
+		},
+		{
+			state:        appendStoredEvents(t, setupTension(t, tensionID), ep.NewEventTensionClosed(tensionID, "reason")),
+			tensionState: models.TensionStateOpen,
+			err:          fmt.Errorf("tension is closed"),
+		},
+	}
+
+	for _, tt := range tests {
+		aggregate := NewTension(uidGenerator, tensionID)
+
+		command := commands.NewCommand(commands.CommandTypeChangeTensionState, correlationID, causationID, util.NilID, &commands.ChangeTensionState{
+			State: tt.tensionState,
+		})
+
+		test := &testData{
+			State:     tt.state,
+			Aggregate: aggregate,
+			Command:   command,
+			Out:       tt.out,
+			Err:       tt.err,
+		}
+
+		runTest(t, test)
+	}
+}
+
+func TestAddTensionComment(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	tensionID := uidGenerator.UUID("")
+	memberID := uidGenerator.UUID("")
+	commentID := uidGenerator.UUID("")
+	parentCommentID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	tests := []struct {
+		state           []*eventstore.StoredEvent
+		parentCommentID *util.ID
+		out             []ep.Event
+		err             error
+	}{
+		{
+			state: setupTension(t, tensionID),
+			out: []ep.Event{
+				&ep.EventTensionCommentAdded{
+					CommentID: commentID,
+					MemberID:  memberID,
+					Text:      "comment01",
+				},
+			},
+		},
+		{
+			state:           appendStoredEvents(t, setupTension(t, tensionID), ep.NewEventTensionCommentAdded(parentCommentID, nil, memberID, "comment00")),
+			parentCommentID: &parentCommentID,
+			out: []ep.Event{
+				&ep.EventTensionCommentAdded{
+					CommentID:       commentID,
+					ParentCommentID: &parentCommentID,
+					MemberID:        memberID,
+					Text:            "comment01",
+				},
+			},
+		},
+		{
+			state:           setupTension(t, tensionID),
+			parentCommentID: &parentCommentID,
+			err:             fmt.Errorf("unexistent parent comment"),
+		},
+		{
+			state: appendStoredEvents(t, setupTension(t, tensionID), ep.NewEventTensionCommentAdded(commentID, nil, memberID, "comment01")),
+			err:   fmt.Errorf("comment already exists"),
+		},
+		// a closed tension can still be discussed
+		{
+			state: appendStoredEvents(t, setupTension(t, tensionID), ep.NewEventTensionClosed(tensionID, "reason")),
+			out: []ep.Event{
+				&ep.EventTensionCommentAdded{
+					CommentID: commentID,
+					MemberID:  memberID,
+					Text:      "comment01",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		aggregate := NewTension(uidGenerator, tensionID)
+
+		command := commands.NewCommand(commands.CommandTypeAddTensionComment, correlationID, causationID, util.NilID, &commands.AddTensionComment{
+			CommentID:       commentID,
+			ParentCommentID: tt.parentCommentID,
+			MemberID:        memberID,
+			Text:            "comment01",
+		})
+
+		test := &testData{
+			State:     tt.state,
+			Aggregate: aggregate,
+			Command:   command,
+			Out:       tt.out,
+			Err:       tt.err,
+		}
+
+		runTest(t, test)
+	}
+}
+
+func TestAssignTension(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	tensionID := uidGenerator.UUID("")
+	roleID := uidGenerator.UUID("")
+	memberID := uidGenerator.UUID("")
+
+	correlationID := uidGenerator.UUID("")
+	causationID := uidGenerator.UUID("")
+
+	tests := []struct {
+		state    []*eventstore.StoredEvent
+		roleID   *util.ID
+		memberID *util.ID
+		out      []ep.Event
+		err      error
+	}{
+		{
+			state:    setupTension(t, tensionID),
+			roleID:   &roleID,
+			memberID: &memberID,
+			out: []ep.Event{
+				&ep.EventTensionAssigneeChanged{
+					RoleID:   &roleID,
+					MemberID: &memberID,
+				},
+			},
+		},
+		// unassign
+		{
+			state: appendStoredEvents(t, setupTension(t, tensionID), ep.NewEventTensionAssigneeChanged(&roleID, &memberID, nil, nil)),
+			out: []ep.Event{
+				&ep.EventTensionAssigneeChanged{
+					PreviousRoleID:   &roleID,
+					PreviousMemberID: &memberID,
+				},
+			},
+		},
+		{
+			state:    appendStoredEvents(t, setupTension(t, tensionID), ep.NewEventTensionAssigneeChanged(&roleID, &memberID, nil, nil)),
+			roleID:   &roleID,
+			memberID: &memberID,
+			err:      fmt.Errorf("tension assignee not changed"),
+		},
+		{
+			state:  setupTension(t, tensionID),
+			roleID: &roleID,
+			err:    fmt.Errorf("both the assignee role and member must be provided"),
+		},
+		{
+			state:    appendStoredEvents(t, setupTension(t, tensionID), ep.NewEventTensionClosed(tensionID, "reason")),
+			roleID:   &roleID,
+			memberID: &memberID,
+			err:      fmt.Errorf("tension is closed"),
+		},
+	}
+
+	for _, tt := range tests {
+		aggregate := NewTension(uidGenerator, tensionID)
+
+		command := commands.NewCommand(commands.CommandTypeAssignTension, correlationID, causationID, util.NilID, &commands.AssignTension{
+			RoleID:   tt.roleID,
+			MemberID: tt.memberID,
+		})
+
+		test := &testData{
+			State:     tt.state,
+			Aggregate: aggregate,
+			Command:   command,
+			Out:       tt.out,
+			Err:       tt.err,
+		}
+
+		runTest(t, test)
+	}
+}
+
+func TestTensionIncompatibleSnapshot(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+
+	tensionID := uidGenerator.UUID("")
+
+	aggregate := NewTension(uidGenerator, tensionID)
+
+	// snapshot saved before the tension state was tracked
+	data := []byte(`{"Title":"tension01","Description":"Tension 01","RoleID":null,"Created":true}`)
+	if err := aggregate.RestoreSnapshot(1, data); err != errIncompatibleSnapshot {
+		t.Fatalf("expected error %v, got %v", errIncompatibleSnapshot, err)
+	}
+}
+
+func TestTensionIncompatibleSnapshotReplaced(t *testing.T) {
+	es := newTestEventStore()
+	es.SetSnapshotInterval(2)
+
+	uidGenerator := NewTestUIDGen()
+	tr := NewTensionRepository(es, uidGenerator)
+
+	tensionID := uidGenerator.UUID("")
+	for _, c := range []*commands.Command{
+		commands.NewCommand(commands.CommandTypeCreateTension, uidGenerator.UUID(""), uidGenerator.UUID(""), util.NilID, &commands.CreateTension{
+			Title:       "tension01",
+			Description: "Tension 01",
+			MemberID:    uidGenerator.UUID(""),
+		}),
+		commands.NewCommand(commands.CommandTypeUpdateTension, uidGenerator.UUID(""), uidGenerator.UUID(""), util.NilID, &commands.UpdateTension{
+			Title:       "tension 01 new title",
+			Description: "Tension 01 new description",
+		}),
+	} {
+		tension, err := tr.Load(tensionID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, _, err := ExecCommand(c, tension, es, uidGenerator); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// snapshot saved before the tension state was tracked at the current
+	// stream version
+	if err := es.WriteSnapshot(&eventstore.Snapshot{
+		Category: TensionAggregate.String(),
+		StreamID: tensionID.String(),
+		Version:  2,
+		Data:     []byte(`{"Title":"tension01","Description":"Tension 01","RoleID":null,"Created":true}`),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := tr.Load(tensionID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the incompatible snapshot must have been replaced
+	snapshot, err := es.GetSnapshot(tensionID.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot == nil {
+		t.Fatalf("expected snapshot, got no snapshot")
+	}
+	if snapshot.Version != 2 {
+		t.Fatalf("expected snapshot version %d, got %d", 2, snapshot.Version)
+	}
+	if err := NewTension(uidGenerator, tensionID).RestoreSnapshot(snapshot.Version, snapshot.Data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -23,6 +23,11 @@ const (
 	RolesTreeAggregate AggregateType = "rolestree"
 	MemberAggregate    AggregateType = "member"
 	TensionAggregate   AggregateType = "tension"
+	ProposalAggregate  AggregateType = "proposal"
+	MeetingAggregate   AggregateType = "meeting"
+	ProjectAggregate   AggregateType = "project"
+
+	ReportItemAggregate AggregateType = "reportitem"
 
 	MemberChangeAggregate         AggregateType = "memberchange"
 	MemberRequestHandlerAggregate AggregateType = "memberrequesthandler"
@@ -1,8 +1,10 @@
 package aggregate
 
 import (
+	"encoding/json"
 	"fmt"
 
+	"github.com/pkg/errors"
 	"github.com/sorintlab/sircles/command/commands"
 	"github.com/sorintlab/sircles/common"
 	ep "github.com/sorintlab/sircles/events"
@@ -11,11 +13,11 @@ import (
 )
 
 type UniqueValueRegistryRepository struct {
-	es           *eventstore.EventStore
+	es           eventstore.EventStore
 	uidGenerator common.UIDGenerator
 }
 
-func NewUniqueValueRegistryRepository(es *eventstore.EventStore, uidGenerator common.UIDGenerator) *UniqueValueRegistryRepository {
+func NewUniqueValueRegistryRepository(es eventstore.EventStore, uidGenerator common.UIDGenerator) *UniqueValueRegistryRepository {
 	return &UniqueValueRegistryRepository{es: es, uidGenerator: uidGenerator}
 }
 
@@ -41,11 +43,11 @@ type UniqueValueRegistry struct {
 	reserveRequests map[util.ID]struct{}
 	releaseRequests map[util.ID]struct{}
 
-	es           *eventstore.EventStore
+	es           eventstore.EventStore
 	uidGenerator common.UIDGenerator
 }
 
-func NewUniqueValueRegistry(es *eventstore.EventStore, uidGenerator common.UIDGenerator, id string) (*UniqueValueRegistry, error) {
+func NewUniqueValueRegistry(es eventstore.EventStore, uidGenerator common.UIDGenerator, id string) (*UniqueValueRegistry, error) {
 	return &UniqueValueRegistry{
 		id:              id,
 		values:          make(map[string]util.ID),
@@ -159,3 +161,78 @@ func (r *UniqueValueRegistry) ApplyEvent(event *eventstore.StoredEvent) error {
 
 	return nil
 }
+
+type uniqueValueRegistrySnapshot struct {
+	Values          map[string]util.ID
+	ReserveRequests map[util.ID]struct{}
+	ReleaseRequests map[util.ID]struct{}
+}
+
+func (r *UniqueValueRegistry) Snapshot() ([]byte, error) {
+	return json.Marshal(&uniqueValueRegistrySnapshot{
+		Values:          r.values,
+		ReserveRequests: r.reserveRequests,
+		ReleaseRequests: r.releaseRequests,
+	})
+}
+
+func (r *UniqueValueRegistry) RestoreSnapshot(version int64, data []byte) error {
+	var s uniqueValueRegistrySnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Wrap(err, "failed to unmarshal unique value registry snapshot")
+	}
+
+	r.version = version
+
+	for value, id := range s.Values {
+		r.values[value] = id
+	}
+	for id := range s.ReserveRequests {
+		r.reserveRequests[id] = struct{}{}
+	}
+	for id := range s.ReleaseRequests {
+		r.releaseRequests[id] = struct{}{}
+	}
+
+	return nil
+}
+
+// DeleteUniqueValueRegistrySnapshots deletes the unique value registry
+// snapshots containing values reserved to the provided id. The snapshots
+// contain the values in clear so they must be deleted when the personal data
+// of the id owner is forgotten. The snapshots that cannot be decoded are also
+// deleted.
+func DeleteUniqueValueRegistrySnapshots(es eventstore.EventStore, id util.ID) error {
+	afterStreamID := ""
+	for {
+		snapshots, err := es.GetSnapshots(afterStreamID, 100)
+		if err != nil {
+			return err
+		}
+		if len(snapshots) == 0 {
+			return nil
+		}
+		for _, snapshot := range snapshots {
+			if snapshot.Category != UniqueValueRegistryAggregate.String() {
+				continue
+			}
+			reserved := false
+			var s uniqueValueRegistrySnapshot
+			if err := json.Unmarshal(snapshot.Data, &s); err != nil {
+				reserved = true
+			}
+			for _, vid := range s.Values {
+				if vid == id {
+					reserved = true
+					break
+				}
+			}
+			if reserved {
+				if err := es.DeleteSnapshot(snapshot.StreamID); err != nil {
+					return err
+				}
+			}
+		}
+		afterStreamID = snapshots[len(snapshots)-1].StreamID
+	}
+}
@@ -0,0 +1,59 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/util"
+)
+
+func TestDeleteUniqueValueRegistrySnapshots(t *testing.T) {
+	uidGenerator := NewTestUIDGen()
+	es := newTestEventStore()
+
+	memberID := uidGenerator.UUID("member01")
+	otherMemberID := uidGenerator.UUID("member02")
+
+	registrySnapshot := func(values map[string]util.ID) []byte {
+		data, err := json.Marshal(&uniqueValueRegistrySnapshot{Values: values})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return data
+	}
+
+	snapshots := []*eventstore.Snapshot{
+		{Category: UniqueValueRegistryAggregate.String(), StreamID: "username-user01", Version: 1, Data: registrySnapshot(map[string]util.ID{"user01": memberID})},
+		{Category: UniqueValueRegistryAggregate.String(), StreamID: "username-user02", Version: 1, Data: registrySnapshot(map[string]util.ID{"user02": otherMemberID})},
+		{Category: UniqueValueRegistryAggregate.String(), StreamID: "username-user03", Version: 2, Data: registrySnapshot(map[string]util.ID{})},
+		{Category: UniqueValueRegistryAggregate.String(), StreamID: "username-user04", Version: 1, Data: []byte("undecodable")},
+		{Category: MemberAggregate.String(), StreamID: otherMemberID.String(), Version: 1, Data: []byte(`{}`)},
+	}
+	for _, snapshot := range snapshots {
+		if err := es.WriteSnapshot(snapshot); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := DeleteUniqueValueRegistrySnapshots(es, memberID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedDeleted := map[string]bool{
+		"username-user01": true,
+		"username-user04": true,
+	}
+	for _, snapshot := range snapshots {
+		s, err := es.GetSnapshot(snapshot.StreamID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expectedDeleted[snapshot.StreamID] && s != nil {
+			t.Fatalf("expected snapshot %s deleted", snapshot.StreamID)
+		}
+		if !expectedDeleted[snapshot.StreamID] && s == nil {
+			t.Fatalf("expected snapshot %s not deleted", snapshot.StreamID)
+		}
+	}
+}
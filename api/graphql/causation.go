@@ -0,0 +1,167 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+
+	graphql "github.com/neelance/graphql-go"
+	"github.com/pkg/errors"
+)
+
+// marshalEventID marshals event related IDs as plain uuids since they are
+// used to correlate events and logs and not to reference graph nodes
+func marshalEventID(id *util.ID) *graphql.ID {
+	if id == nil {
+		return nil
+	}
+	gid := graphql.ID(id.String())
+	return &gid
+}
+
+type causationTreeResolver struct {
+	t *models.CausationTree
+}
+
+func (r *causationTreeResolver) CorrelationID() graphql.ID {
+	return *marshalEventID(&r.t.CorrelationID)
+}
+
+func (r *causationTreeResolver) Events() []*causationEventResolver {
+	res := make([]*causationEventResolver, len(r.t.Events))
+	for i, e := range r.t.Events {
+		res[i] = &causationEventResolver{e}
+	}
+	return res
+}
+
+type causationEventResolver struct {
+	e *models.CausationEvent
+}
+
+func (r *causationEventResolver) ID() graphql.ID {
+	return *marshalEventID(&r.e.ID)
+}
+
+func (r *causationEventResolver) SequenceNumber() float64 {
+	return float64(r.e.SequenceNumber)
+}
+
+func (r *causationEventResolver) TimeLineID() util.TimeLineNumber {
+	return r.e.TimeLineID
+}
+
+func (r *causationEventResolver) Timestamp() graphql.Time {
+	return graphql.Time{Time: r.e.Timestamp}
+}
+
+func (r *causationEventResolver) EventType() string {
+	return r.e.EventType
+}
+
+func (r *causationEventResolver) AggregateType() string {
+	return r.e.AggregateType
+}
+
+func (r *causationEventResolver) AggregateID() string {
+	return r.e.AggregateID
+}
+
+func (r *causationEventResolver) CorrelationID() *graphql.ID {
+	return marshalEventID(r.e.CorrelationID)
+}
+
+func (r *causationEventResolver) CausationID() *graphql.ID {
+	return marshalEventID(r.e.CausationID)
+}
+
+func (r *causationEventResolver) CommandCausationID() *graphql.ID {
+	return marshalEventID(r.e.CommandCausationID)
+}
+
+func (r *causationEventResolver) GroupID() *graphql.ID {
+	return marshalEventID(r.e.GroupID)
+}
+
+func (r *causationEventResolver) CommandIssuerID() *graphql.ID {
+	return marshalEventID(r.e.CommandIssuerID)
+}
+
+func (r *causationEventResolver) ParentID() *graphql.ID {
+	return marshalEventID(r.e.ParentID)
+}
+
+func (r *causationEventResolver) Depth() int32 {
+	return int32(r.e.Depth)
+}
+
+func (r *Resolver) EventCausation(ctx context.Context, args *struct {
+	EventID    *graphql.ID
+	GroupID    *graphql.ID
+	TimeLineID *util.TimeLineNumber
+}) (*[]*causationTreeResolver, error) {
+	n := 0
+	for _, set := range []bool{args.EventID != nil, args.GroupID != nil, args.TimeLineID != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return nil, errors.Errorf("exactly one of eventID, groupID or timeLineID must be provided")
+	}
+
+	isAdmin, err := r.callingMemberIsAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, nil
+	}
+
+	s, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*models.Event
+	switch {
+	case args.EventID != nil:
+		id, err := unmarshalUID(*args.EventID)
+		if err != nil {
+			return nil, err
+		}
+		event, err := s.Event(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if event != nil {
+			events = append(events, event)
+		}
+	case args.GroupID != nil:
+		id, err := unmarshalUID(*args.GroupID)
+		if err != nil {
+			return nil, err
+		}
+		events, err = s.GroupEvents(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	case args.TimeLineID != nil:
+		events, err = s.TimeLineEvents(ctx, *args.TimeLineID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	trees, err := s.CausationTrees(ctx, events)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*causationTreeResolver, len(trees))
+	for i, t := range trees {
+		res[i] = &causationTreeResolver{t}
+	}
+	return &res, nil
+}
@@ -0,0 +1,207 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/sorintlab/sircles/command"
+	"github.com/sorintlab/sircles/eventstore"
+
+	graphql "github.com/neelance/graphql-go"
+	"github.com/pkg/errors"
+)
+
+type CommandAuditConnectionCursor struct {
+	Filter eventstore.CommandAuditFilter
+}
+
+func marshalCommandAuditConnectionCursor(c *CommandAuditConnectionCursor) (string, error) {
+	cj, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(cj), nil
+}
+
+func unmarshalCommandAuditConnectionCursor(s string) (*CommandAuditConnectionCursor, error) {
+	cj, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var c *CommandAuditConnectionCursor
+	if err := json.Unmarshal(cj, &c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *Resolver) CommandAuditLog(ctx context.Context, args *struct {
+	MemberUID   *graphql.ID
+	CommandType *string
+	FromTime    *graphql.Time
+	ToTime      *graphql.Time
+	First       *float64
+	After       *string
+}) (*commandAuditConnectionResolver, error) {
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	// accept only a cursor or the filters
+	if args.After != nil && (args.MemberUID != nil || args.CommandType != nil || args.FromTime != nil || args.ToTime != nil) {
+		return nil, errors.New("only the cursor or the filters can be provided")
+	}
+
+	isAdmin, err := r.callingMemberIsAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, nil
+	}
+
+	var filter eventstore.CommandAuditFilter
+	if args.After != nil {
+		cursor, err := unmarshalCommandAuditConnectionCursor(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		filter = cursor.Filter
+	} else {
+		if args.MemberUID != nil {
+			memberID, err := unmarshalUID(*args.MemberUID)
+			if err != nil {
+				return nil, err
+			}
+			filter.IssuerID = &memberID
+		}
+		if args.CommandType != nil {
+			filter.CommandType = *args.CommandType
+		}
+		if args.FromTime != nil {
+			filter.FromTime = &args.FromTime.Time
+		}
+		if args.ToTime != nil {
+			filter.ToTime = &args.ToTime.Time
+		}
+	}
+	first := 0
+	if args.First != nil {
+		first = int(*args.First)
+	}
+
+	entries, hasMoreData, err := cs.CommandAudit(ctx, &filter, first)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commandAuditConnectionResolver{entries, hasMoreData, filter}, nil
+}
+
+type commandAuditConnectionResolver struct {
+	entries     []*eventstore.CommandAuditEntry
+	hasMoreData bool
+	filter      eventstore.CommandAuditFilter
+}
+
+func (r *commandAuditConnectionResolver) Edges() (*[]*commandAuditEdgeResolver, error) {
+	edges := make([]*commandAuditEdgeResolver, len(r.entries))
+	for i, e := range r.entries {
+		filter := r.filter
+		filter.Before = e.SequenceNumber
+		cursor, err := marshalCommandAuditConnectionCursor(&CommandAuditConnectionCursor{Filter: filter})
+		if err != nil {
+			return nil, err
+		}
+		edges[i] = &commandAuditEdgeResolver{cursor, e}
+	}
+	return &edges, nil
+}
+
+func (r *commandAuditConnectionResolver) HasMoreData() bool {
+	return r.hasMoreData
+}
+
+type commandAuditEdgeResolver struct {
+	cursor string
+	e      *eventstore.CommandAuditEntry
+}
+
+func (r *commandAuditEdgeResolver) Cursor() string {
+	return r.cursor
+}
+
+func (r *commandAuditEdgeResolver) Entry() *commandAuditEntryResolver {
+	return &commandAuditEntryResolver{r.e}
+}
+
+type commandAuditEntryResolver struct {
+	e *eventstore.CommandAuditEntry
+}
+
+func (r *commandAuditEntryResolver) SequenceNumber() float64 {
+	return float64(r.e.SequenceNumber)
+}
+
+func (r *commandAuditEntryResolver) Timestamp() graphql.Time {
+	return graphql.Time{Time: r.e.Timestamp}
+}
+
+func (r *commandAuditEntryResolver) CommandID() *graphql.ID {
+	return marshalEventID(r.e.CommandID)
+}
+
+func (r *commandAuditEntryResolver) CommandType() string {
+	return r.e.CommandType
+}
+
+func (r *commandAuditEntryResolver) CorrelationID() *graphql.ID {
+	return marshalEventID(r.e.CorrelationID)
+}
+
+func (r *commandAuditEntryResolver) CausationID() *graphql.ID {
+	return marshalEventID(r.e.CausationID)
+}
+
+func (r *commandAuditEntryResolver) IssuerID() *graphql.ID {
+	return marshalEventID(r.e.IssuerID)
+}
+
+func (r *commandAuditEntryResolver) AggregateType() *string {
+	return stringP(r.e.AggregateType)
+}
+
+func (r *commandAuditEntryResolver) AggregateID() *string {
+	return stringP(r.e.AggregateID)
+}
+
+func (r *commandAuditEntryResolver) GroupID() *graphql.ID {
+	return marshalEventID(r.e.GroupID)
+}
+
+func (r *commandAuditEntryResolver) EventsCount() int32 {
+	return int32(r.e.EventsCount)
+}
+
+func (r *commandAuditEntryResolver) Result() string {
+	return string(r.e.Result)
+}
+
+func (r *commandAuditEntryResolver) Error() *string {
+	return stringP(r.e.Error)
+}
+
+func (r *commandAuditEntryResolver) RemoteAddr() *string {
+	return stringP(r.e.RemoteAddr)
+}
+
+func (r *commandAuditEntryResolver) UserAgent() *string {
+	return stringP(r.e.UserAgent)
+}
+
+// stringP returns a pointer to the provided string or nil if it's empty
+func stringP(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
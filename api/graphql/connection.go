@@ -0,0 +1,223 @@
+package graphql
+
+import (
+	"github.com/sorintlab/sircles/dataloader"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+)
+
+type pageArgs struct {
+	First  *float64
+	After  *string
+	Last   *float64
+	Before *string
+}
+
+func (a *pageArgs) page() (*readdb.Page, error) {
+	page := &readdb.Page{}
+	if a.First != nil {
+		page.First = int(*a.First)
+	}
+	if a.Last != nil {
+		page.Last = int(*a.Last)
+	}
+	if a.After != nil {
+		cursor, err := unmarshalPageConnectionCursor(*a.After)
+		if err != nil {
+			return nil, err
+		}
+		page.After = cursor.Position
+	}
+	if a.Before != nil {
+		cursor, err := unmarshalPageConnectionCursor(*a.Before)
+		if err != nil {
+			return nil, err
+		}
+		page.Before = cursor.Position
+	}
+	if err := page.Validate(); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func pageCursor(position int) (string, error) {
+	return marshalPageConnectionCursor(&PageConnectionCursor{Position: position})
+}
+
+type pageInfoResolver struct {
+	pageInfo models.PageInfo
+	// number of items in the page
+	count int
+}
+
+func (r *pageInfoResolver) HasNextPage() bool {
+	return r.pageInfo.Start+r.count-1 < r.pageInfo.TotalCount
+}
+
+func (r *pageInfoResolver) HasPreviousPage() bool {
+	return r.pageInfo.Start > 1
+}
+
+func (r *pageInfoResolver) StartCursor() (*string, error) {
+	if r.count == 0 {
+		return nil, nil
+	}
+	cursor, err := pageCursor(r.pageInfo.Start)
+	return &cursor, err
+}
+
+func (r *pageInfoResolver) EndCursor() (*string, error) {
+	if r.count == 0 {
+		return nil, nil
+	}
+	cursor, err := pageCursor(r.pageInfo.Start + r.count - 1)
+	return &cursor, err
+}
+
+type roleConnectionResolver struct {
+	s          readdb.ReadDBService
+	page       *models.RolesPage
+	timeLineID util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *roleConnectionResolver) Edges() []*roleEdgeResolver {
+	l := make([]*roleEdgeResolver, len(r.page.Roles))
+	for i, role := range r.page.Roles {
+		l[i] = &roleEdgeResolver{r.page.Start + i, NewRoleResolver(r.s, role, r.timeLineID, r.dataLoaders)}
+	}
+	return l
+}
+
+func (r *roleConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{r.page.PageInfo, len(r.page.Roles)}
+}
+
+func (r *roleConnectionResolver) TotalCount() int32 {
+	return int32(r.page.TotalCount)
+}
+
+type roleEdgeResolver struct {
+	position int
+	role     *roleResolver
+}
+
+func (r *roleEdgeResolver) Cursor() (string, error) {
+	return pageCursor(r.position)
+}
+
+func (r *roleEdgeResolver) Role() *roleResolver {
+	return r.role
+}
+
+type tensionConnectionResolver struct {
+	s          readdb.ReadDBService
+	page       *models.TensionsPage
+	timeLineID util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *tensionConnectionResolver) Edges() []*tensionEdgeResolver {
+	l := make([]*tensionEdgeResolver, len(r.page.Tensions))
+	for i, tension := range r.page.Tensions {
+		l[i] = &tensionEdgeResolver{r.page.Start + i, &tensionResolver{r.s, tension, r.timeLineID, r.dataLoaders}}
+	}
+	return l
+}
+
+func (r *tensionConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{r.page.PageInfo, len(r.page.Tensions)}
+}
+
+func (r *tensionConnectionResolver) TotalCount() int32 {
+	return int32(r.page.TotalCount)
+}
+
+type tensionEdgeResolver struct {
+	position int
+	tension  *tensionResolver
+}
+
+func (r *tensionEdgeResolver) Cursor() (string, error) {
+	return pageCursor(r.position)
+}
+
+func (r *tensionEdgeResolver) Tension() *tensionResolver {
+	return r.tension
+}
+
+type circleMemberConnectionResolver struct {
+	s          readdb.ReadDBService
+	page       *models.CircleMemberEdgesPage
+	timeLineID util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *circleMemberConnectionResolver) Edges() []*circleMemberConnectionEdgeResolver {
+	l := make([]*circleMemberConnectionEdgeResolver, len(r.page.CircleMemberEdges))
+	for i, circleMemberEdge := range r.page.CircleMemberEdges {
+		l[i] = &circleMemberConnectionEdgeResolver{&circleMemberEdgeResolver{r.s, circleMemberEdge, r.timeLineID, r.dataLoaders}, r.page.Start + i}
+	}
+	return l
+}
+
+func (r *circleMemberConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{r.page.PageInfo, len(r.page.CircleMemberEdges)}
+}
+
+func (r *circleMemberConnectionResolver) TotalCount() int32 {
+	return int32(r.page.TotalCount)
+}
+
+// circleMemberConnectionEdgeResolver is a circleMemberEdgeResolver with the
+// cursor in its connection
+type circleMemberConnectionEdgeResolver struct {
+	*circleMemberEdgeResolver
+	position int
+}
+
+func (r *circleMemberConnectionEdgeResolver) Cursor() (*string, error) {
+	cursor, err := pageCursor(r.position)
+	return &cursor, err
+}
+
+type memberRoleConnectionResolver struct {
+	s          readdb.ReadDBService
+	page       *models.MemberRoleEdgesPage
+	timeLineID util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *memberRoleConnectionResolver) Edges() []*memberRoleConnectionEdgeResolver {
+	l := make([]*memberRoleConnectionEdgeResolver, len(r.page.MemberRoleEdges))
+	for i, memberRoleEdge := range r.page.MemberRoleEdges {
+		l[i] = &memberRoleConnectionEdgeResolver{&memberRoleEdgeResolver{r.s, memberRoleEdge, r.timeLineID, r.dataLoaders}, r.page.Start + i}
+	}
+	return l
+}
+
+func (r *memberRoleConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{r.page.PageInfo, len(r.page.MemberRoleEdges)}
+}
+
+func (r *memberRoleConnectionResolver) TotalCount() int32 {
+	return int32(r.page.TotalCount)
+}
+
+// memberRoleConnectionEdgeResolver is a memberRoleEdgeResolver with the cursor
+// in its connection
+type memberRoleConnectionEdgeResolver struct {
+	*memberRoleEdgeResolver
+	position int
+}
+
+func (r *memberRoleConnectionEdgeResolver) Cursor() (*string, error) {
+	cursor, err := pageCursor(r.position)
+	return &cursor, err
+}
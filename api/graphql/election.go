@@ -0,0 +1,74 @@
+package graphql
+
+import (
+	"time"
+
+	"github.com/sorintlab/sircles/dataloader"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+
+	graphql "github.com/neelance/graphql-go"
+)
+
+// coreRoleElection is an elected core role assignment with an election
+// expiration
+type coreRoleElection struct {
+	coreRole           *models.Role
+	member             *models.Member
+	electionExpiration time.Time
+}
+
+type coreRoleElections []*coreRoleElection
+
+func (e coreRoleElections) Len() int      { return len(e) }
+func (e coreRoleElections) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e coreRoleElections) Less(i, j int) bool {
+	return e[i].electionExpiration.Before(e[j].electionExpiration)
+}
+
+// expiringElectionsLimit returns the time before which an election expiration
+// is considered expiring
+func expiringElectionsLimit(withinDays int32) time.Time {
+	return time.Now().Add(time.Duration(withinDays) * 24 * time.Hour)
+}
+
+type coreRoleElectionResolver struct {
+	s          readdb.ReadDBService
+	e          *coreRoleElection
+	timeLineID util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *coreRoleElectionResolver) Role() *roleResolver {
+	return NewRoleResolver(r.s, r.e.coreRole, r.timeLineID, r.dataLoaders)
+}
+
+func (r *coreRoleElectionResolver) Circle() (*roleResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLineID).RoleParent.Load(r.e.coreRole.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	role := data.(*models.Role)
+	return NewRoleResolver(r.s, role, r.timeLineID, r.dataLoaders), nil
+}
+
+func (r *coreRoleElectionResolver) Member() *memberResolver {
+	return &memberResolver{r.s, r.e.member, r.timeLineID, r.dataLoaders}
+}
+
+func (r *coreRoleElectionResolver) RoleType() string {
+	return string(r.e.coreRole.RoleType)
+}
+
+func (r *coreRoleElectionResolver) ElectionExpiration() graphql.Time {
+	return graphql.Time{Time: r.e.electionExpiration}
+}
+
+func (r *coreRoleElectionResolver) Expired() bool {
+	return !r.e.electionExpiration.After(time.Now())
+}
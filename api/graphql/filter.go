@@ -0,0 +1,139 @@
+package graphql
+
+import (
+	"strings"
+
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/readdb"
+
+	graphql "github.com/neelance/graphql-go"
+	"github.com/pkg/errors"
+)
+
+type RoleFilter struct {
+	RoleTypes      *[]string
+	MinDepth       *float64
+	MaxDepth       *float64
+	SubtreeRootUID *graphql.ID
+	Unfilled       *bool
+}
+
+func (f *RoleFilter) toReadDBFilter() (*readdb.RoleFilter, error) {
+	if f == nil {
+		return nil, nil
+	}
+	rf := &readdb.RoleFilter{Unfilled: f.Unfilled}
+	if f.RoleTypes != nil {
+		for _, rt := range *f.RoleTypes {
+			roleType := models.RoleTypeFromString(strings.ToLower(rt))
+			if roleType == models.RoleTypeUndefined {
+				return nil, errors.Errorf("unknown role type %q", rt)
+			}
+			rf.RoleTypes = append(rf.RoleTypes, roleType)
+		}
+	}
+	if f.MinDepth != nil {
+		minDepth := int(*f.MinDepth)
+		rf.MinDepth = &minDepth
+	}
+	if f.MaxDepth != nil {
+		maxDepth := int(*f.MaxDepth)
+		rf.MaxDepth = &maxDepth
+	}
+	if f.SubtreeRootUID != nil {
+		id, err := unmarshalUID(*f.SubtreeRootUID)
+		if err != nil {
+			return nil, err
+		}
+		rf.SubtreeRootID = &id
+	}
+	return rf, nil
+}
+
+type RoleOrder struct {
+	Field     string
+	Direction *string
+}
+
+func (o *RoleOrder) toReadDBOrder() (*readdb.RoleOrder, error) {
+	if o == nil {
+		return nil, nil
+	}
+	ro := &readdb.RoleOrder{}
+	switch field := readdb.RoleOrderField(strings.ToLower(o.Field)); field {
+	case readdb.RoleOrderFieldName, readdb.RoleOrderFieldDepth, readdb.RoleOrderFieldRoleType:
+		ro.Field = field
+	default:
+		return nil, errors.Errorf("unknown role order field %q", o.Field)
+	}
+	desc, err := orderDirectionDesc(o.Direction)
+	if err != nil {
+		return nil, err
+	}
+	ro.Desc = desc
+	return ro, nil
+}
+
+type MemberFilter struct {
+	FilledRolesGreaterThan *float64
+	LeadLinkOfUID          *graphql.ID
+}
+
+func (f *MemberFilter) toReadDBFilter() (*readdb.MemberFilter, error) {
+	if f == nil {
+		return nil, nil
+	}
+	mf := &readdb.MemberFilter{}
+	if f.FilledRolesGreaterThan != nil {
+		filledRoles := int(*f.FilledRolesGreaterThan)
+		mf.FilledRolesGreaterThan = &filledRoles
+	}
+	if f.LeadLinkOfUID != nil {
+		id, err := unmarshalUID(*f.LeadLinkOfUID)
+		if err != nil {
+			return nil, err
+		}
+		mf.LeadLinkOfID = &id
+	}
+	return mf, nil
+}
+
+type MemberOrder struct {
+	Field     string
+	Direction *string
+}
+
+func (o *MemberOrder) toReadDBOrder() (*readdb.MemberOrder, error) {
+	if o == nil {
+		return nil, nil
+	}
+	mo := &readdb.MemberOrder{}
+	switch field := readdb.MemberOrderField(strings.ToLower(o.Field)); field {
+	case readdb.MemberOrderFieldFullName, readdb.MemberOrderFieldUserName, readdb.MemberOrderFieldFilledRoles:
+		mo.Field = field
+	default:
+		return nil, errors.Errorf("unknown member order field %q", o.Field)
+	}
+	desc, err := orderDirectionDesc(o.Direction)
+	if err != nil {
+		return nil, err
+	}
+	mo.Desc = desc
+	return mo, nil
+}
+
+// orderDirectionDesc reports if the provided order direction is descending.
+// It defaults to ascending.
+func orderDirectionDesc(direction *string) (bool, error) {
+	if direction == nil {
+		return false, nil
+	}
+	switch strings.ToLower(*direction) {
+	case "asc":
+		return false, nil
+	case "desc":
+		return true, nil
+	default:
+		return false, errors.Errorf("unknown order direction %q", *direction)
+	}
+}
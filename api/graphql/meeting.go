@@ -0,0 +1,209 @@
+package graphql
+
+import (
+	"sort"
+
+	"github.com/sorintlab/sircles/change"
+	"github.com/sorintlab/sircles/dataloader"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+
+	graphql "github.com/neelance/graphql-go"
+)
+
+type meetingResolver struct {
+	s        readdb.ReadDBService
+	m        *models.Meeting
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *meetingResolver) UID() graphql.ID {
+	return marshalUID("meeting", r.m.ID)
+}
+
+func (r *meetingResolver) MeetingType() string {
+	return string(r.m.MeetingType)
+}
+
+func (r *meetingResolver) Title() string {
+	return r.m.Title
+}
+
+func (r *meetingResolver) StartTime() graphql.Time {
+	return graphql.Time{Time: r.m.StartTime}
+}
+
+func (r *meetingResolver) Closed() bool {
+	return r.m.Closed
+}
+
+func (r *meetingResolver) Role() (*roleResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).MeetingRole.Load(r.m.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	role := data.(*models.Role)
+	return NewRoleResolver(r.s, role, r.timeLine, r.dataLoaders), nil
+}
+
+func (r *meetingResolver) Facilitator() (*memberResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).MeetingFacilitator.Load(r.m.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	member := data.(*models.Member)
+	return &memberResolver{r.s, member, r.timeLine, r.dataLoaders}, nil
+}
+
+func (r *meetingResolver) Secretary() (*memberResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).MeetingSecretary.Load(r.m.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	member := data.(*models.Member)
+	return &memberResolver{r.s, member, r.timeLine, r.dataLoaders}, nil
+}
+
+func (r *meetingResolver) Attendees() (*[]*memberResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).MeetingAttendees.Load(r.m.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	members := data.([]*models.Member)
+	l := make([]*memberResolver, len(members))
+	for i, member := range members {
+		l[i] = &memberResolver{r.s, member, r.timeLine, r.dataLoaders}
+	}
+	return &l, nil
+}
+
+func (r *meetingResolver) AgendaItems() (*[]*meetingAgendaItemResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).MeetingAgendaItems.Load(r.m.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	agendaItems := data.([]*models.MeetingAgendaItem)
+	sort.Sort(models.MeetingAgendaItems(agendaItems))
+
+	l := make([]*meetingAgendaItemResolver, len(agendaItems))
+	for i, ai := range agendaItems {
+		l[i] = &meetingAgendaItemResolver{r.s, ai, r.timeLine, r.dataLoaders}
+	}
+	return &l, nil
+}
+
+type meetingAgendaItemResolver struct {
+	s        readdb.ReadDBService
+	ai       *models.MeetingAgendaItem
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *meetingAgendaItemResolver) UID() graphql.ID {
+	return marshalUID("meetingagendaitem", r.ai.ID)
+}
+
+func (r *meetingAgendaItemResolver) Title() string {
+	return r.ai.Title
+}
+
+func (r *meetingAgendaItemResolver) Outcome() string {
+	return r.ai.Outcome
+}
+
+func (r *meetingAgendaItemResolver) Tension() (*tensionResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).AgendaItemTension.Load(r.ai.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	tension := data.(*models.Tension)
+	return &tensionResolver{r.s, tension, r.timeLine, r.dataLoaders}, nil
+}
+
+type createMeetingResultResolver struct {
+	s        readdb.ReadDBService
+	meeting  *models.Meeting
+	res      *change.CreateMeetingResult
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *createMeetingResultResolver) Meeting() *meetingResolver {
+	if r.meeting == nil {
+		return nil
+	}
+	return &meetingResolver{r.s, r.meeting, r.timeLine, r.dataLoaders}
+}
+
+func (r *createMeetingResultResolver) HasErrors() bool {
+	return r.res.HasErrors
+}
+
+func (r *createMeetingResultResolver) GenericError() *string {
+	return errorToStringP(r.res.GenericError)
+}
+
+func (r *createMeetingResultResolver) CreateMeetingChangeErrors() *createMeetingChangeErrorsResolver {
+	return &createMeetingChangeErrorsResolver{r: r.res.CreateMeetingChangeErrors}
+}
+
+type createMeetingChangeErrorsResolver struct {
+	r change.CreateMeetingChangeErrors
+}
+
+func (r *createMeetingChangeErrorsResolver) MeetingType() *string {
+	return errorToStringP(r.r.MeetingType)
+}
+
+func (r *createMeetingChangeErrorsResolver) Title() *string {
+	return errorToStringP(r.r.Title)
+}
+
+type addMeetingAgendaItemResultResolver struct {
+	s        readdb.ReadDBService
+	meeting  *models.Meeting
+	res      *change.AddMeetingAgendaItemResult
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *addMeetingAgendaItemResultResolver) Meeting() *meetingResolver {
+	if r.meeting == nil {
+		return nil
+	}
+	return &meetingResolver{r.s, r.meeting, r.timeLine, r.dataLoaders}
+}
+
+func (r *addMeetingAgendaItemResultResolver) AgendaItemUID() *graphql.ID {
+	if r.res.AgendaItemID == nil {
+		return nil
+	}
+	uid := marshalUID("meetingagendaitem", *r.res.AgendaItemID)
+	return &uid
+}
+
+func (r *addMeetingAgendaItemResultResolver) HasErrors() bool {
+	return r.res.HasErrors
+}
+
+func (r *addMeetingAgendaItemResultResolver) GenericError() *string {
+	return errorToStringP(r.res.GenericError)
+}
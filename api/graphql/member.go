@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"context"
+	"sort"
 
 	"github.com/sorintlab/sircles/change"
 	"github.com/sorintlab/sircles/dataloader"
@@ -79,6 +80,14 @@ func (r *memberCirclePermissionsResolver) ManageRoleAdditionalContent() bool {
 	return r.permissions.ManageRoleAdditionalContent
 }
 
+func (r *memberCirclePermissionsResolver) AcceptProposals() bool {
+	return r.permissions.AcceptProposals
+}
+
+func (r *memberCirclePermissionsResolver) ManageReportItems() bool {
+	return r.permissions.ManageReportItems
+}
+
 func (r *memberCirclePermissionsResolver) AssignRootCircleLeadLink() bool {
 	return r.permissions.AssignRootCircleLeadLink
 }
@@ -150,6 +159,63 @@ func (r *memberResolver) Roles() (*[]*memberRoleEdgeResolver, error) {
 	return &l, nil
 }
 
+func (r *memberResolver) RolesConnection(args *pageArgs) (*memberRoleConnectionResolver, error) {
+	page, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.dataLoaders.Get(r.timeLineID).MemberRoleEdgesPage.Load(dataloader.PageKey(r.m.ID, page))()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	return &memberRoleConnectionResolver{r.s, data.(*models.MemberRoleEdgesPage), r.timeLineID, r.dataLoaders}, nil
+}
+
+func (r *memberResolver) Projects() (*[]*projectResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLineID).MemberProjects.Load(r.m.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	projects := data.([]*models.Project)
+	l := make([]*projectResolver, len(projects))
+	for i, project := range projects {
+		l[i] = &projectResolver{r.s, project, r.timeLineID, r.dataLoaders}
+	}
+	return &l, nil
+}
+
+func (r *memberResolver) ExpiringElections(args *struct {
+	WithinDays int32
+}) (*[]*coreRoleElectionResolver, error) {
+	limit := expiringElectionsLimit(args.WithinDays)
+
+	data, err := r.dataLoaders.Get(r.timeLineID).MemberRoleEdges.Load(r.m.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+
+	elections := coreRoleElections{}
+	for _, memberRoleEdge := range data.([]*models.MemberRoleEdge) {
+		if !memberRoleEdge.Role.RoleType.IsElectedRoleType() {
+			continue
+		}
+		if memberRoleEdge.ElectionExpiration == nil || memberRoleEdge.ElectionExpiration.After(limit) {
+			continue
+		}
+		elections = append(elections, &coreRoleElection{coreRole: memberRoleEdge.Role, member: r.m, electionExpiration: *memberRoleEdge.ElectionExpiration})
+	}
+	sort.Sort(elections)
+
+	l := make([]*coreRoleElectionResolver, len(elections))
+	for i, election := range elections {
+		l[i] = &coreRoleElectionResolver{r.s, election, r.timeLineID, r.dataLoaders}
+	}
+	return &l, nil
+}
+
 func (r *memberResolver) Tensions() (*[]*tensionResolver, error) {
 	data, err := r.dataLoaders.Get(r.timeLineID).MemberTensions.Load(r.m.ID.String())()
 	if err != nil {
@@ -163,11 +229,56 @@ func (r *memberResolver) Tensions() (*[]*tensionResolver, error) {
 	return &l, nil
 }
 
+func (r *memberResolver) TensionsConnection(args *pageArgs) (*tensionConnectionResolver, error) {
+	page, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.dataLoaders.Get(r.timeLineID).MemberTensionsPage.Load(dataloader.PageKey(r.m.ID, page))()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	return &tensionConnectionResolver{r.s, data.(*models.TensionsPage), r.timeLineID, r.dataLoaders}, nil
+}
+
+func (r *memberResolver) Events(ctx context.Context, args *struct {
+	First *float64
+	After *string
+}) (*memberEventConnectionResolver, error) {
+	var start util.TimeLineNumber
+	var after int64
+
+	// by default, if no cursor is defined use the query provided timeline
+	if args.After != nil {
+		cursor, err := unmarshalMemberEventConnectionCursor(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		after = cursor.SequenceNumber
+	} else {
+		start = r.timeLineID
+	}
+	first := 0
+	if args.First != nil {
+		first = int(*args.First)
+	}
+	events, hasMoreData, err := r.s.MemberEvents(ctx, r.m.ID, first, start, after)
+	if err != nil {
+		return nil, err
+	}
+	return &memberEventConnectionResolver{r.s, events, hasMoreData, r.dataLoaders}, nil
+}
+
 type memberConnectionResolver struct {
 	s           readdb.ReadDBService
 	members     []*models.Member
 	hasMoreData bool
 	timeLineID  util.TimeLineNumber
+	// the query state saved in the edges cursors
+	cursor *MemberConnectionCursor
 
 	dataLoaders *dataloader.DataLoaders
 }
@@ -179,7 +290,7 @@ func (r *memberConnectionResolver) HasMoreData() bool {
 func (r *memberConnectionResolver) Edges() *[]*memberEdgeResolver {
 	l := make([]*memberEdgeResolver, len(r.members))
 	for i, member := range r.members {
-		l[i] = &memberEdgeResolver{r.s, member, r.timeLineID, r.dataLoaders}
+		l[i] = &memberEdgeResolver{r.s, member, r.timeLineID, r.cursor, r.dataLoaders}
 	}
 	return &l
 }
@@ -188,12 +299,15 @@ type memberEdgeResolver struct {
 	s          readdb.ReadDBService
 	member     *models.Member
 	timeLineID util.TimeLineNumber
+	cursor     *MemberConnectionCursor
 
 	dataLoaders *dataloader.DataLoaders
 }
 
 func (r *memberEdgeResolver) Cursor() (string, error) {
-	return marshalMemberConnectionCursor(&MemberConnectionCursor{TimeLineID: r.timeLineID, FullName: r.member.FullName})
+	cursor := *r.cursor
+	cursor.MemberID = r.member.ID
+	return marshalMemberConnectionCursor(&cursor)
 }
 
 func (r *memberEdgeResolver) Member() *memberResolver {
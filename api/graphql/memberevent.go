@@ -0,0 +1,193 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/sorintlab/sircles/dataloader"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+
+	graphql "github.com/neelance/graphql-go"
+)
+
+type memberEventConnectionResolver struct {
+	s           readdb.ReadDBService
+	events      []*models.MemberEvent
+	hasMoreData bool
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *memberEventConnectionResolver) HasMoreData() bool {
+	return r.hasMoreData
+}
+
+func (r *memberEventConnectionResolver) Edges() *[]*memberEventEdgeResolver {
+	l := make([]*memberEventEdgeResolver, len(r.events))
+	for i, event := range r.events {
+		l[i] = &memberEventEdgeResolver{r.s, event, r.dataLoaders}
+	}
+	return &l
+}
+
+type memberEventEdgeResolver struct {
+	s     readdb.ReadDBService
+	event *models.MemberEvent
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *memberEventEdgeResolver) Cursor() (string, error) {
+	return marshalMemberEventConnectionCursor(&MemberEventConnectionCursor{SequenceNumber: r.event.SequenceNumber})
+}
+
+func (r *memberEventEdgeResolver) Event() *memberEventResolver {
+	base := &baseMemberEventResolver{r.s, r.event, r.dataLoaders}
+	switch eventData := r.event.Data.(type) {
+	case *models.MemberEventMemberChanged:
+		return &memberEventResolver{&memberEventMemberChangedResolver{base, eventData}}
+	case *models.MemberEventCircleDirectMemberChanged:
+		return &memberEventResolver{&memberEventCircleDirectMemberChangedResolver{base, eventData}}
+	case *models.MemberEventRoleMemberChanged:
+		return &memberEventResolver{&memberEventRoleMemberChangedResolver{base, eventData}}
+	default:
+		return nil
+	}
+}
+
+type memberEvent interface {
+	TimeLine(context.Context) (*timeLineResolver, error)
+	Type() string
+}
+
+type memberEventResolver struct {
+	memberEvent
+}
+
+func (r *memberEventResolver) ToMemberEventMemberChanged() (*memberEventMemberChangedResolver, bool) {
+	t, ok := r.memberEvent.(*memberEventMemberChangedResolver)
+	return t, ok
+}
+
+func (r *memberEventResolver) ToMemberEventCircleDirectMemberChanged() (*memberEventCircleDirectMemberChangedResolver, bool) {
+	t, ok := r.memberEvent.(*memberEventCircleDirectMemberChangedResolver)
+	return t, ok
+}
+
+func (r *memberEventResolver) ToMemberEventRoleMemberChanged() (*memberEventRoleMemberChangedResolver, bool) {
+	t, ok := r.memberEvent.(*memberEventRoleMemberChangedResolver)
+	return t, ok
+}
+
+type baseMemberEventResolver struct {
+	s     readdb.ReadDBService
+	event *models.MemberEvent
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *baseMemberEventResolver) TimeLine(ctx context.Context) (*timeLineResolver, error) {
+	tl, err := r.s.TimeLine(ctx, r.event.TimeLineID)
+	if err != nil {
+		return nil, err
+	}
+	if tl == nil {
+		return nil, nil
+	}
+	return &timeLineResolver{r.s, tl, r.dataLoaders}, nil
+}
+
+func (r *baseMemberEventResolver) Type() string {
+	return string(r.event.EventType)
+}
+
+// role returns the role at the event timeline or, if deleted in the event
+// timeline, before it
+func (r *baseMemberEventResolver) role(ctx context.Context, roleID util.ID) (*roleResolver, error) {
+	for _, tl := range []util.TimeLineNumber{r.event.TimeLineID, r.event.TimeLineID - 1} {
+		role, err := r.s.Role(ctx, tl, roleID)
+		if err != nil {
+			return nil, err
+		}
+		if role != nil {
+			return NewRoleResolver(r.s, role, tl, r.dataLoaders), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *baseMemberEventResolver) member(ctx context.Context, tl util.TimeLineNumber) (*memberResolver, error) {
+	member, err := r.s.Member(ctx, tl, r.event.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, nil
+	}
+	return &memberResolver{r.s, member, tl, r.dataLoaders}, nil
+}
+
+type memberEventMemberChangedResolver struct {
+	*baseMemberEventResolver
+	eventData *models.MemberEventMemberChanged
+}
+
+func (r *memberEventMemberChangedResolver) Member(ctx context.Context) (*memberResolver, error) {
+	return r.member(ctx, r.event.TimeLineID)
+}
+
+func (r *memberEventMemberChangedResolver) PreviousMember(ctx context.Context) (*memberResolver, error) {
+	return r.member(ctx, r.event.TimeLineID-1)
+}
+
+func (r *memberEventMemberChangedResolver) ChangedFields() []string {
+	if r.eventData.ChangedFields == nil {
+		return []string{}
+	}
+	return r.eventData.ChangedFields
+}
+
+type memberEventCircleDirectMemberChangedResolver struct {
+	*baseMemberEventResolver
+	eventData *models.MemberEventCircleDirectMemberChanged
+}
+
+func (r *memberEventCircleDirectMemberChangedResolver) Circle(ctx context.Context) (*roleResolver, error) {
+	return r.role(ctx, r.eventData.CircleID)
+}
+
+type memberEventRoleMemberChangedResolver struct {
+	*baseMemberEventResolver
+	eventData *models.MemberEventRoleMemberChanged
+}
+
+func (r *memberEventRoleMemberChangedResolver) Role(ctx context.Context) (*roleResolver, error) {
+	return r.role(ctx, r.eventData.RoleID)
+}
+
+func (r *memberEventRoleMemberChangedResolver) RoleType() string {
+	return string(r.eventData.RoleType)
+}
+
+func (r *memberEventRoleMemberChangedResolver) Circle(ctx context.Context) (*roleResolver, error) {
+	if r.eventData.CircleID == nil {
+		return nil, nil
+	}
+	return r.role(ctx, *r.eventData.CircleID)
+}
+
+func (r *memberEventRoleMemberChangedResolver) Focus() *string {
+	return r.eventData.Focus
+}
+
+func (r *memberEventRoleMemberChangedResolver) NoCoreMember() bool {
+	return r.eventData.NoCoreMember
+}
+
+func (r *memberEventRoleMemberChangedResolver) ElectionExpiration() *graphql.Time {
+	if r.eventData.ElectionExpiration == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.eventData.ElectionExpiration}
+}
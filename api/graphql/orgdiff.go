@@ -0,0 +1,264 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/sorintlab/sircles/dataloader"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+
+	graphql "github.com/neelance/graphql-go"
+)
+
+// orgDiffResolvers creates the resolvers of the entities of an org diff,
+// returning nil resolvers for missing entities
+type orgDiffResolvers struct {
+	s    readdb.ReadDBService
+	diff *models.OrgDiff
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *orgDiffResolvers) role(role *models.Role, tl util.TimeLineNumber) *roleResolver {
+	if role == nil {
+		return nil
+	}
+	return NewRoleResolver(r.s, role, tl, r.dataLoaders)
+}
+
+func (r *orgDiffResolvers) domain(domain *models.Domain, tl util.TimeLineNumber) *domainResolver {
+	if domain == nil {
+		return nil
+	}
+	return &domainResolver{r.s, domain, tl, r.dataLoaders}
+}
+
+func (r *orgDiffResolvers) accountability(accountability *models.Accountability, tl util.TimeLineNumber) *accountabilityResolver {
+	if accountability == nil {
+		return nil
+	}
+	return &accountabilityResolver{r.s, accountability, tl, r.dataLoaders}
+}
+
+func (r *orgDiffResolvers) roleMember(roleMember *models.RoleMemberEdge, tl util.TimeLineNumber) *roleMemberEdgeResolver {
+	if roleMember == nil {
+		return nil
+	}
+	return &roleMemberEdgeResolver{r.s, roleMember, tl, r.dataLoaders}
+}
+
+type orgDiffResolver struct {
+	*orgDiffResolvers
+}
+
+func (r *orgDiffResolver) FromTimeLineID() util.TimeLineNumber {
+	return r.diff.FromTl
+}
+
+func (r *orgDiffResolver) ToTimeLineID() util.TimeLineNumber {
+	return r.diff.ToTl
+}
+
+func (r *orgDiffResolver) Roles() []*roleDiffResolver {
+	l := make([]*roleDiffResolver, len(r.diff.Roles))
+	for i, d := range r.diff.Roles {
+		l[i] = &roleDiffResolver{r.orgDiffResolvers, d}
+	}
+	return l
+}
+
+func (r *orgDiffResolver) Domains() []*domainDiffResolver {
+	l := make([]*domainDiffResolver, len(r.diff.Domains))
+	for i, d := range r.diff.Domains {
+		l[i] = &domainDiffResolver{r.orgDiffResolvers, d}
+	}
+	return l
+}
+
+func (r *orgDiffResolver) Accountabilities() []*accountabilityDiffResolver {
+	l := make([]*accountabilityDiffResolver, len(r.diff.Accountabilities))
+	for i, d := range r.diff.Accountabilities {
+		l[i] = &accountabilityDiffResolver{r.orgDiffResolvers, d}
+	}
+	return l
+}
+
+func (r *orgDiffResolver) RoleMembers() []*roleMemberDiffResolver {
+	l := make([]*roleMemberDiffResolver, len(r.diff.RoleMembers))
+	for i, d := range r.diff.RoleMembers {
+		l[i] = &roleMemberDiffResolver{r.orgDiffResolvers, d}
+	}
+	return l
+}
+
+func (r *orgDiffResolver) CoreRoles() []*coreRoleDiffResolver {
+	l := make([]*coreRoleDiffResolver, len(r.diff.CoreRoles))
+	for i, d := range r.diff.CoreRoles {
+		l[i] = &coreRoleDiffResolver{r.orgDiffResolvers, d}
+	}
+	return l
+}
+
+type roleDiffResolver struct {
+	*orgDiffResolvers
+	d *models.RoleDiff
+}
+
+func (r *roleDiffResolver) ChangeType() string {
+	return string(r.d.ChangeType)
+}
+
+func (r *roleDiffResolver) Role() *roleResolver {
+	return r.role(r.d.Role, r.diff.ToTl)
+}
+
+func (r *roleDiffResolver) PreviousRole() *roleResolver {
+	return r.role(r.d.PreviousRole, r.diff.FromTl)
+}
+
+func (r *roleDiffResolver) Parent() *roleResolver {
+	return r.role(r.d.Parent, r.diff.ToTl)
+}
+
+func (r *roleDiffResolver) PreviousParent() *roleResolver {
+	return r.role(r.d.PreviousParent, r.diff.FromTl)
+}
+
+type domainDiffResolver struct {
+	*orgDiffResolvers
+	d *models.DomainDiff
+}
+
+func (r *domainDiffResolver) ChangeType() string {
+	return string(r.d.ChangeType)
+}
+
+func (r *domainDiffResolver) Role() *roleResolver {
+	return r.role(r.d.Role, r.diff.ToTl)
+}
+
+func (r *domainDiffResolver) PreviousRole() *roleResolver {
+	return r.role(r.d.PreviousRole, r.diff.FromTl)
+}
+
+func (r *domainDiffResolver) Domain() *domainResolver {
+	return r.domain(r.d.Domain, r.diff.ToTl)
+}
+
+func (r *domainDiffResolver) PreviousDomain() *domainResolver {
+	return r.domain(r.d.PreviousDomain, r.diff.FromTl)
+}
+
+type accountabilityDiffResolver struct {
+	*orgDiffResolvers
+	d *models.AccountabilityDiff
+}
+
+func (r *accountabilityDiffResolver) ChangeType() string {
+	return string(r.d.ChangeType)
+}
+
+func (r *accountabilityDiffResolver) Role() *roleResolver {
+	return r.role(r.d.Role, r.diff.ToTl)
+}
+
+func (r *accountabilityDiffResolver) PreviousRole() *roleResolver {
+	return r.role(r.d.PreviousRole, r.diff.FromTl)
+}
+
+func (r *accountabilityDiffResolver) Accountability() *accountabilityResolver {
+	return r.accountability(r.d.Accountability, r.diff.ToTl)
+}
+
+func (r *accountabilityDiffResolver) PreviousAccountability() *accountabilityResolver {
+	return r.accountability(r.d.PreviousAccountability, r.diff.FromTl)
+}
+
+type roleMemberDiffResolver struct {
+	*orgDiffResolvers
+	d *models.RoleMemberDiff
+}
+
+func (r *roleMemberDiffResolver) ChangeType() string {
+	return string(r.d.ChangeType)
+}
+
+func (r *roleMemberDiffResolver) Role() *roleResolver {
+	return r.role(r.d.Role, r.diff.ToTl)
+}
+
+func (r *roleMemberDiffResolver) PreviousRole() *roleResolver {
+	return r.role(r.d.PreviousRole, r.diff.FromTl)
+}
+
+func (r *roleMemberDiffResolver) RoleMember() *roleMemberEdgeResolver {
+	return r.roleMember(r.d.RoleMember, r.diff.ToTl)
+}
+
+func (r *roleMemberDiffResolver) PreviousRoleMember() *roleMemberEdgeResolver {
+	return r.roleMember(r.d.PreviousRoleMember, r.diff.FromTl)
+}
+
+type coreRoleDiffResolver struct {
+	*orgDiffResolvers
+	d *models.CoreRoleDiff
+}
+
+func (r *coreRoleDiffResolver) ChangeType() string {
+	return string(r.d.ChangeType)
+}
+
+func (r *coreRoleDiffResolver) RoleType() string {
+	return string(r.d.RoleType)
+}
+
+func (r *coreRoleDiffResolver) Circle() *roleResolver {
+	return r.role(r.d.Circle, r.diff.ToTl)
+}
+
+func (r *coreRoleDiffResolver) PreviousCircle() *roleResolver {
+	return r.role(r.d.PreviousCircle, r.diff.FromTl)
+}
+
+func (r *coreRoleDiffResolver) RoleMember() *roleMemberEdgeResolver {
+	return r.roleMember(r.d.RoleMember, r.diff.ToTl)
+}
+
+func (r *coreRoleDiffResolver) PreviousRoleMember() *roleMemberEdgeResolver {
+	return r.roleMember(r.d.PreviousRoleMember, r.diff.FromTl)
+}
+
+func (r *Resolver) OrgDiff(ctx context.Context, args *struct {
+	FromTimeLineID util.TimeLineNumber
+	ToTimeLineID   *util.TimeLineNumber
+	RootRoleUID    *graphql.ID
+}) (*orgDiffResolver, error) {
+	s, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fromTimeLineID, err := getTimeLineNumber(ctx, s, &args.FromTimeLineID)
+	if err != nil {
+		return nil, err
+	}
+	toTimeLineID, err := getTimeLineNumber(ctx, s, args.ToTimeLineID)
+	if err != nil {
+		return nil, err
+	}
+	var rootRoleID *util.ID
+	if args.RootRoleUID != nil {
+		id, err := unmarshalUID(*args.RootRoleUID)
+		if err != nil {
+			return nil, err
+		}
+		rootRoleID = &id
+	}
+
+	diff, err := s.OrgDiff(ctx, fromTimeLineID, toTimeLineID, rootRoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &orgDiffResolver{&orgDiffResolvers{s, diff, dataloader.NewDataLoaders(ctx, s)}}, nil
+}
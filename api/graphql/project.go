@@ -0,0 +1,193 @@
+package graphql
+
+import (
+	"sort"
+
+	"github.com/sorintlab/sircles/change"
+	"github.com/sorintlab/sircles/dataloader"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+
+	graphql "github.com/neelance/graphql-go"
+)
+
+type projectResolver struct {
+	s        readdb.ReadDBService
+	p        *models.Project
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *projectResolver) UID() graphql.ID {
+	return marshalUID("project", r.p.ID)
+}
+
+func (r *projectResolver) Title() string {
+	return r.p.Title
+}
+
+func (r *projectResolver) Description() string {
+	return r.p.Description
+}
+
+func (r *projectResolver) Status() string {
+	return string(r.p.Status)
+}
+
+func (r *projectResolver) Role() (*roleResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).ProjectRole.Load(r.p.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	role := data.(*models.Role)
+	return NewRoleResolver(r.s, role, r.timeLine, r.dataLoaders), nil
+}
+
+func (r *projectResolver) Member() (*memberResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).ProjectMember.Load(r.p.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	member := data.(*models.Member)
+	return &memberResolver{r.s, member, r.timeLine, r.dataLoaders}, nil
+}
+
+func (r *projectResolver) NextActions() (*[]*nextActionResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).ProjectNextActions.Load(r.p.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	nextActions := data.([]*models.NextAction)
+	sort.Sort(models.NextActions(nextActions))
+
+	l := make([]*nextActionResolver, len(nextActions))
+	for i, na := range nextActions {
+		l[i] = &nextActionResolver{na}
+	}
+	return &l, nil
+}
+
+type nextActionResolver struct {
+	na *models.NextAction
+}
+
+func (r *nextActionResolver) UID() graphql.ID {
+	return marshalUID("nextaction", r.na.ID)
+}
+
+func (r *nextActionResolver) Description() string {
+	return r.na.Description
+}
+
+func (r *nextActionResolver) Completed() bool {
+	return r.na.Completed
+}
+
+type createProjectResultResolver struct {
+	s        readdb.ReadDBService
+	project  *models.Project
+	res      *change.CreateProjectResult
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *createProjectResultResolver) Project() *projectResolver {
+	if r.project == nil {
+		return nil
+	}
+	return &projectResolver{r.s, r.project, r.timeLine, r.dataLoaders}
+}
+
+func (r *createProjectResultResolver) HasErrors() bool {
+	return r.res.HasErrors
+}
+
+func (r *createProjectResultResolver) GenericError() *string {
+	return errorToStringP(r.res.GenericError)
+}
+
+func (r *createProjectResultResolver) CreateProjectChangeErrors() *projectChangeErrorsResolver {
+	return &projectChangeErrorsResolver{title: r.res.CreateProjectChangeErrors.Title, description: r.res.CreateProjectChangeErrors.Description}
+}
+
+type updateProjectResultResolver struct {
+	s        readdb.ReadDBService
+	project  *models.Project
+	res      *change.UpdateProjectResult
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *updateProjectResultResolver) Project() *projectResolver {
+	if r.project == nil {
+		return nil
+	}
+	return &projectResolver{r.s, r.project, r.timeLine, r.dataLoaders}
+}
+
+func (r *updateProjectResultResolver) HasErrors() bool {
+	return r.res.HasErrors
+}
+
+func (r *updateProjectResultResolver) GenericError() *string {
+	return errorToStringP(r.res.GenericError)
+}
+
+func (r *updateProjectResultResolver) UpdateProjectChangeErrors() *projectChangeErrorsResolver {
+	return &projectChangeErrorsResolver{title: r.res.UpdateProjectChangeErrors.Title, description: r.res.UpdateProjectChangeErrors.Description}
+}
+
+type projectChangeErrorsResolver struct {
+	title       error
+	description error
+}
+
+func (r *projectChangeErrorsResolver) Title() *string {
+	return errorToStringP(r.title)
+}
+
+func (r *projectChangeErrorsResolver) Description() *string {
+	return errorToStringP(r.description)
+}
+
+type addProjectNextActionResultResolver struct {
+	s        readdb.ReadDBService
+	project  *models.Project
+	res      *change.AddProjectNextActionResult
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *addProjectNextActionResultResolver) Project() *projectResolver {
+	if r.project == nil {
+		return nil
+	}
+	return &projectResolver{r.s, r.project, r.timeLine, r.dataLoaders}
+}
+
+func (r *addProjectNextActionResultResolver) NextActionUID() *graphql.ID {
+	if r.res.NextActionID == nil {
+		return nil
+	}
+	uid := marshalUID("nextaction", *r.res.NextActionID)
+	return &uid
+}
+
+func (r *addProjectNextActionResultResolver) HasErrors() bool {
+	return r.res.HasErrors
+}
+
+func (r *addProjectNextActionResultResolver) GenericError() *string {
+	return errorToStringP(r.res.GenericError)
+}
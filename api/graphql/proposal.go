@@ -0,0 +1,176 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/sorintlab/sircles/change"
+	"github.com/sorintlab/sircles/dataloader"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+
+	graphql "github.com/neelance/graphql-go"
+)
+
+type proposalResolver struct {
+	s        readdb.ReadDBService
+	p        *models.Proposal
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *proposalResolver) UID() graphql.ID {
+	return marshalUID("proposal", r.p.ID)
+}
+
+func (r *proposalResolver) Tension() (*tensionResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).ProposalTension.Load(r.p.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	tension := data.(*models.Tension)
+	return &tensionResolver{r.s, tension, r.timeLine, r.dataLoaders}, nil
+}
+
+func (r *proposalResolver) Role() (*roleResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).ProposalRole.Load(r.p.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	role := data.(*models.Role)
+	return NewRoleResolver(r.s, role, r.timeLine, r.dataLoaders), nil
+}
+
+func (r *proposalResolver) Proposer() (*memberResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).ProposalMember.Load(r.p.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	member := data.(*models.Member)
+	return &memberResolver{r.s, member, r.timeLine, r.dataLoaders}, nil
+}
+
+func (r *proposalResolver) State() string {
+	return string(r.p.State)
+}
+
+func (r *proposalResolver) Changes() string {
+	return r.p.Changes
+}
+
+func (r *proposalResolver) Objections() *[]*proposalObjectionResolver {
+	l := make([]*proposalObjectionResolver, len(r.p.Objections))
+	for i, o := range r.p.Objections {
+		l[i] = &proposalObjectionResolver{r.s, o, r.timeLine, r.dataLoaders}
+	}
+	return &l
+}
+
+type proposalObjectionResolver struct {
+	s        readdb.ReadDBService
+	o        *models.ProposalObjection
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *proposalObjectionResolver) Member(ctx context.Context) (*memberResolver, error) {
+	member, err := r.s.Member(ctx, r.timeLine, r.o.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, nil
+	}
+	return &memberResolver{r.s, member, r.timeLine, r.dataLoaders}, nil
+}
+
+func (r *proposalObjectionResolver) Reason() string {
+	return r.o.Reason
+}
+
+type createProposalResultResolver struct {
+	s        readdb.ReadDBService
+	proposal *models.Proposal
+	res      *change.CreateProposalResult
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *createProposalResultResolver) Proposal() *proposalResolver {
+	if r.proposal == nil {
+		return nil
+	}
+	return &proposalResolver{r.s, r.proposal, r.timeLine, r.dataLoaders}
+}
+
+func (r *createProposalResultResolver) HasErrors() bool {
+	return r.res.HasErrors
+}
+
+func (r *createProposalResultResolver) GenericError() *string {
+	return errorToStringP(r.res.GenericError)
+}
+
+func (r *createProposalResultResolver) ProposalChangesErrors() *proposalChangesErrorsResolver {
+	return &proposalChangesErrorsResolver{r: r.res.ProposalChangesErrors}
+}
+
+type updateProposalResultResolver struct {
+	s        readdb.ReadDBService
+	proposal *models.Proposal
+	res      *change.UpdateProposalResult
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *updateProposalResultResolver) Proposal() *proposalResolver {
+	if r.proposal == nil {
+		return nil
+	}
+	return &proposalResolver{r.s, r.proposal, r.timeLine, r.dataLoaders}
+}
+
+func (r *updateProposalResultResolver) HasErrors() bool {
+	return r.res.HasErrors
+}
+
+func (r *updateProposalResultResolver) GenericError() *string {
+	return errorToStringP(r.res.GenericError)
+}
+
+func (r *updateProposalResultResolver) ProposalChangesErrors() *proposalChangesErrorsResolver {
+	return &proposalChangesErrorsResolver{r: r.res.ProposalChangesErrors}
+}
+
+type proposalChangesErrorsResolver struct {
+	r change.ProposalChangesErrors
+}
+
+func (r *proposalChangesErrorsResolver) CreateRoleChangesErrors() *[]*createRoleChangeErrorsResolver {
+	l := make([]*createRoleChangeErrorsResolver, len(r.r.CreateRoleChangesErrors))
+	for i, r := range r.r.CreateRoleChangesErrors {
+		l[i] = &createRoleChangeErrorsResolver{r: r}
+	}
+	return &l
+}
+
+func (r *proposalChangesErrorsResolver) UpdateRoleChangesErrors() *[]*updateRoleChangeErrorsResolver {
+	l := make([]*updateRoleChangeErrorsResolver, len(r.r.UpdateRoleChangesErrors))
+	for i, r := range r.r.UpdateRoleChangesErrors {
+		l[i] = &updateRoleChangeErrorsResolver{r: r}
+	}
+	return &l
+}
@@ -0,0 +1,109 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/sorintlab/sircles/change"
+	"github.com/sorintlab/sircles/readdb"
+
+	graphql "github.com/neelance/graphql-go"
+	"github.com/pkg/errors"
+)
+
+type readDBRebuildStatusResolver struct {
+	s *readdb.RebuildStatus
+}
+
+func (r *readDBRebuildStatusResolver) Running() bool {
+	return r.s.Running
+}
+
+func (r *readDBRebuildStatusResolver) StartTime() *graphql.Time {
+	if r.s.StartTime == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.s.StartTime}
+}
+
+func (r *readDBRebuildStatusResolver) EndTime() *graphql.Time {
+	if r.s.EndTime == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.s.EndTime}
+}
+
+func (r *readDBRebuildStatusResolver) SequenceNumber() float64 {
+	return float64(r.s.SequenceNumber)
+}
+
+func (r *readDBRebuildStatusResolver) LastSequenceNumber() float64 {
+	return float64(r.s.LastSequenceNumber)
+}
+
+func (r *readDBRebuildStatusResolver) Error() *string {
+	if r.s.Error == "" {
+		return nil
+	}
+	return &r.s.Error
+}
+
+func (r *Resolver) callingMemberIsAdmin(ctx context.Context) (bool, error) {
+	s, err := r.setupReadDB(ctx)
+	if err != nil {
+		return false, err
+	}
+	timeLineID, err := getTimeLineNumber(ctx, s, nil)
+	if err != nil {
+		return false, err
+	}
+	member, err := s.CallingMember(ctx, timeLineID)
+	if err != nil {
+		return false, err
+	}
+	return member != nil && member.IsAdmin, nil
+}
+
+func (r *Resolver) ReadDBRebuildStatus(ctx context.Context) (*readDBRebuildStatusResolver, error) {
+	rebuilder, _ := ctx.Value("readdbrebuilder").(*readdb.Rebuilder)
+	if rebuilder == nil {
+		return nil, nil
+	}
+	isAdmin, err := r.callingMemberIsAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, nil
+	}
+	return &readDBRebuildStatusResolver{rebuilder.Status()}, nil
+}
+
+func (r *Resolver) RebuildReadDB(ctx context.Context) (*genericResultResolver, error) {
+	res := &change.GenericResult{}
+
+	isAdmin, err := r.callingMemberIsAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return &genericResultResolver{res}, nil
+	}
+
+	rebuilder, _ := ctx.Value("readdbrebuilder").(*readdb.Rebuilder)
+	if rebuilder == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("readdb rebuild not available")
+		return &genericResultResolver{res}, nil
+	}
+	if err := rebuilder.Start(); err != nil {
+		if err != readdb.ErrRebuildRunning {
+			return nil, err
+		}
+		res.HasErrors = true
+		res.GenericError = err
+	}
+
+	return &genericResultResolver{res}, nil
+}
@@ -0,0 +1,177 @@
+package graphql
+
+import (
+	"sort"
+
+	"github.com/sorintlab/sircles/change"
+	"github.com/sorintlab/sircles/dataloader"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+
+	graphql "github.com/neelance/graphql-go"
+)
+
+type reportItemResolver struct {
+	s        readdb.ReadDBService
+	r        *models.ReportItem
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *reportItemResolver) UID() graphql.ID {
+	return marshalUID("reportitem", r.r.ID)
+}
+
+func (r *reportItemResolver) ReportItemType() string {
+	return string(r.r.ReportItemType)
+}
+
+func (r *reportItemResolver) Description() string {
+	return r.r.Description
+}
+
+func (r *reportItemResolver) Frequency() string {
+	return string(r.r.Frequency)
+}
+
+func (r *reportItemResolver) Circle() (*roleResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).ReportItemCircle.Load(r.r.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	role := data.(*models.Role)
+	return NewRoleResolver(r.s, role, r.timeLine, r.dataLoaders), nil
+}
+
+func (r *reportItemResolver) Role() (*roleResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).ReportItemRole.Load(r.r.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	role := data.(*models.Role)
+	return NewRoleResolver(r.s, role, r.timeLine, r.dataLoaders), nil
+}
+
+func (r *reportItemResolver) Values(args *struct {
+	From *graphql.Time
+	To   *graphql.Time
+}) (*[]*reportItemValueResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).ReportItemValues.Load(r.r.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	values := data.([]*models.ReportItemValue)
+	sort.Sort(models.ReportItemValues(values))
+
+	l := []*reportItemValueResolver{}
+	for _, v := range values {
+		if args.From != nil && v.Period.Before(args.From.Time) {
+			continue
+		}
+		if args.To != nil && v.Period.After(args.To.Time) {
+			continue
+		}
+		l = append(l, &reportItemValueResolver{v})
+	}
+	return &l, nil
+}
+
+type reportItemValueResolver struct {
+	v *models.ReportItemValue
+}
+
+func (r *reportItemValueResolver) Period() graphql.Time {
+	return graphql.Time{Time: r.v.Period}
+}
+
+func (r *reportItemValueResolver) Value() float64 {
+	return r.v.Value
+}
+
+type createReportItemResultResolver struct {
+	s          readdb.ReadDBService
+	reportItem *models.ReportItem
+	res        *change.CreateReportItemResult
+	timeLine   util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *createReportItemResultResolver) ReportItem() *reportItemResolver {
+	if r.reportItem == nil {
+		return nil
+	}
+	return &reportItemResolver{r.s, r.reportItem, r.timeLine, r.dataLoaders}
+}
+
+func (r *createReportItemResultResolver) HasErrors() bool {
+	return r.res.HasErrors
+}
+
+func (r *createReportItemResultResolver) GenericError() *string {
+	return errorToStringP(r.res.GenericError)
+}
+
+func (r *createReportItemResultResolver) CreateReportItemChangeErrors() *createReportItemChangeErrorsResolver {
+	return &createReportItemChangeErrorsResolver{r: r.res.CreateReportItemChangeErrors}
+}
+
+type createReportItemChangeErrorsResolver struct {
+	r change.CreateReportItemChangeErrors
+}
+
+func (r *createReportItemChangeErrorsResolver) ReportItemType() *string {
+	return errorToStringP(r.r.ReportItemType)
+}
+
+func (r *createReportItemChangeErrorsResolver) Description() *string {
+	return errorToStringP(r.r.Description)
+}
+
+func (r *createReportItemChangeErrorsResolver) Frequency() *string {
+	return errorToStringP(r.r.Frequency)
+}
+
+type updateReportItemResultResolver struct {
+	s          readdb.ReadDBService
+	reportItem *models.ReportItem
+	res        *change.UpdateReportItemResult
+	timeLine   util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *updateReportItemResultResolver) ReportItem() *reportItemResolver {
+	if r.reportItem == nil {
+		return nil
+	}
+	return &reportItemResolver{r.s, r.reportItem, r.timeLine, r.dataLoaders}
+}
+
+func (r *updateReportItemResultResolver) HasErrors() bool {
+	return r.res.HasErrors
+}
+
+func (r *updateReportItemResultResolver) GenericError() *string {
+	return errorToStringP(r.res.GenericError)
+}
+
+func (r *updateReportItemResultResolver) UpdateReportItemChangeErrors() *updateReportItemChangeErrorsResolver {
+	return &updateReportItemChangeErrorsResolver{r: r.res.UpdateReportItemChangeErrors}
+}
+
+type updateReportItemChangeErrorsResolver struct {
+	r change.UpdateReportItemChangeErrors
+}
+
+func (r *updateReportItemChangeErrorsResolver) Description() *string {
+	return errorToStringP(r.r.Description)
+}
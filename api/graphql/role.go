@@ -127,6 +127,21 @@ func (r *roleResolver) Roles() (*[]*roleResolver, error) {
 	return &l, nil
 }
 
+func (r *roleResolver) RolesConnection(args *pageArgs) (*roleConnectionResolver, error) {
+	page, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.dataLoaders.Get(r.timeLineID).ChildRolePage.Load(dataloader.PageKey(r.r.ID, page))()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	return &roleConnectionResolver{r.s, data.(*models.RolesPage), r.timeLineID, r.dataLoaders}, nil
+}
+
 func (r *roleResolver) CircleMembers() (*[]*circleMemberEdgeResolver, error) {
 	data, err := r.dataLoaders.Get(r.timeLineID).CircleMemberEdges.Load(r.r.ID.String())()
 	if err != nil {
@@ -140,6 +155,21 @@ func (r *roleResolver) CircleMembers() (*[]*circleMemberEdgeResolver, error) {
 	return &l, nil
 }
 
+func (r *roleResolver) CircleMembersConnection(args *pageArgs) (*circleMemberConnectionResolver, error) {
+	page, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.dataLoaders.Get(r.timeLineID).CircleMemberEdgesPage.Load(dataloader.PageKey(r.r.ID, page))()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	return &circleMemberConnectionResolver{r.s, data.(*models.CircleMemberEdgesPage), r.timeLineID, r.dataLoaders}, nil
+}
+
 func (r *roleResolver) RoleMembers() (*[]*roleMemberEdgeResolver, error) {
 	data, err := r.dataLoaders.Get(r.timeLineID).RoleMemberEdges.Load(r.r.ID.String())()
 	if err != nil {
@@ -169,6 +199,118 @@ func (r *roleResolver) Tensions() (*[]*tensionResolver, error) {
 	return &l, nil
 }
 
+func (r *roleResolver) TensionsConnection(args *pageArgs) (*tensionConnectionResolver, error) {
+	page, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.dataLoaders.Get(r.timeLineID).RoleTensionsPage.Load(dataloader.PageKey(r.r.ID, page))()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	return &tensionConnectionResolver{r.s, data.(*models.TensionsPage), r.timeLineID, r.dataLoaders}, nil
+}
+
+func (r *roleResolver) Meetings() (*[]*meetingResolver, error) {
+	if r.r.RoleType != models.RoleTypeCircle {
+		return nil, nil
+	}
+	data, err := r.dataLoaders.Get(r.timeLineID).RoleMeetings.Load(r.r.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	meetings := data.([]*models.Meeting)
+	l := make([]*meetingResolver, len(meetings))
+	for i, meeting := range meetings {
+		l[i] = &meetingResolver{r.s, meeting, r.timeLineID, r.dataLoaders}
+	}
+	return &l, nil
+}
+
+func (r *roleResolver) Projects() (*[]*projectResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLineID).RoleProjects.Load(r.r.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	projects := data.([]*models.Project)
+	l := make([]*projectResolver, len(projects))
+	for i, project := range projects {
+		l[i] = &projectResolver{r.s, project, r.timeLineID, r.dataLoaders}
+	}
+	return &l, nil
+}
+
+func (r *roleResolver) ReportItems() (*[]*reportItemResolver, error) {
+	if r.r.RoleType != models.RoleTypeCircle {
+		return nil, nil
+	}
+	data, err := r.dataLoaders.Get(r.timeLineID).CircleReportItems.Load(r.r.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	reportItems := data.([]*models.ReportItem)
+	l := make([]*reportItemResolver, len(reportItems))
+	for i, reportItem := range reportItems {
+		l[i] = &reportItemResolver{r.s, reportItem, r.timeLineID, r.dataLoaders}
+	}
+	return &l, nil
+}
+
+func (r *roleResolver) AssignedReportItems() (*[]*reportItemResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLineID).RoleReportItems.Load(r.r.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	reportItems := data.([]*models.ReportItem)
+	l := make([]*reportItemResolver, len(reportItems))
+	for i, reportItem := range reportItems {
+		l[i] = &reportItemResolver{r.s, reportItem, r.timeLineID, r.dataLoaders}
+	}
+	return &l, nil
+}
+
+func (r *roleResolver) ExpiringElections(args *struct {
+	WithinDays int32
+}) (*[]*coreRoleElectionResolver, error) {
+	if r.r.RoleType != models.RoleTypeCircle {
+		return nil, nil
+	}
+	limit := expiringElectionsLimit(args.WithinDays)
+
+	data, err := r.dataLoaders.Get(r.timeLineID).ChildRole.Load(r.r.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	roles := data.([]*models.Role)
+
+	elections := coreRoleElections{}
+	for _, role := range roles {
+		if !role.RoleType.IsElectedRoleType() {
+			continue
+		}
+		data, err := r.dataLoaders.Get(r.timeLineID).RoleMemberEdges.Load(role.ID.String())()
+		if err != nil {
+			return nil, err
+		}
+		for _, roleMemberEdge := range data.([]*models.RoleMemberEdge) {
+			if roleMemberEdge.ElectionExpiration == nil || roleMemberEdge.ElectionExpiration.After(limit) {
+				continue
+			}
+			elections = append(elections, &coreRoleElection{coreRole: role, member: roleMemberEdge.Member, electionExpiration: *roleMemberEdge.ElectionExpiration})
+		}
+	}
+	sort.Sort(elections)
+
+	l := make([]*coreRoleElectionResolver, len(elections))
+	for i, election := range elections {
+		l[i] = &coreRoleElectionResolver{r.s, election, r.timeLineID, r.dataLoaders}
+	}
+	return &l, nil
+}
+
 func (r *roleResolver) MemberCirclePermissions(ctx context.Context) (*memberCirclePermissionsResolver, error) {
 	m, err := r.s.MemberCirclePermissions(ctx, r.timeLineID, r.r.ID)
 	if err != nil {
@@ -283,6 +425,11 @@ type memberRoleEdgeResolver struct {
 	dataLoaders *dataloader.DataLoaders
 }
 
+// Cursor is nil since the edge isn't part of a connection
+func (r *memberRoleEdgeResolver) Cursor() (*string, error) {
+	return nil, nil
+}
+
 func (r *memberRoleEdgeResolver) Role() *roleResolver {
 	return &roleResolver{r.s, r.m.Role, r.timeLineID, r.dataLoaders}
 }
@@ -310,6 +457,11 @@ type circleMemberEdgeResolver struct {
 	dataLoaders *dataloader.DataLoaders
 }
 
+// Cursor is nil since the edge isn't part of a connection
+func (r *circleMemberEdgeResolver) Cursor() (*string, error) {
+	return nil, nil
+}
+
 func (r *circleMemberEdgeResolver) Member() *memberResolver {
 	return &memberResolver{r.s, r.m.Member, r.timeLineID, r.dataLoaders}
 }
@@ -410,6 +562,10 @@ func (r *updateRootRoleResultResolver) GenericError() *string {
 	return errorToStringP(r.res.GenericError)
 }
 
+func (r *updateRootRoleResultResolver) Conflict() bool {
+	return isConflictError(r.res.GenericError)
+}
+
 func (r *updateRootRoleResultResolver) UpdateRootRoleChangeErrors() *updateRootRoleChangeErrorsResolver {
 	return &updateRootRoleChangeErrorsResolver{r: r.res.UpdateRootRoleChangeErrors}
 }
@@ -482,6 +638,10 @@ func (r *createRoleResultResolver) GenericError() *string {
 	return errorToStringP(r.res.GenericError)
 }
 
+func (r *createRoleResultResolver) Conflict() bool {
+	return isConflictError(r.res.GenericError)
+}
+
 func (r *createRoleResultResolver) CreateRoleChangeErrors() *createRoleChangeErrorsResolver {
 	return &createRoleChangeErrorsResolver{r: r.res.CreateRoleChangeErrors}
 }
@@ -542,6 +702,10 @@ func (r *updateRoleResultResolver) GenericError() *string {
 	return errorToStringP(r.res.GenericError)
 }
 
+func (r *updateRoleResultResolver) Conflict() bool {
+	return isConflictError(r.res.GenericError)
+}
+
 func (r *updateRoleResultResolver) UpdateRoleChangeErrors() *updateRoleChangeErrorsResolver {
 	return &updateRoleChangeErrorsResolver{r: r.res.UpdateRoleChangeErrors}
 }
@@ -606,6 +770,10 @@ func (r *deleteRoleResultResolver) GenericError() *string {
 	return errorToStringP(r.res.GenericError)
 }
 
+func (r *deleteRoleResultResolver) Conflict() bool {
+	return isConflictError(r.res.GenericError)
+}
+
 type createDomainChangeErrorsResolver struct {
 	r change.CreateDomainChangeErrors
 }
@@ -661,3 +829,7 @@ func (r *setRoleAdditionalContentResultResolver) HasErrors() bool {
 func (r *setRoleAdditionalContentResultResolver) GenericError() *string {
 	return errorToStringP(r.res.GenericError)
 }
+
+func (r *setRoleAdditionalContentResultResolver) Conflict() bool {
+	return isConflictError(r.res.GenericError)
+}
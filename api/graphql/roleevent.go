@@ -8,6 +8,8 @@ import (
 	"github.com/sorintlab/sircles/models"
 	"github.com/sorintlab/sircles/readdb"
 	"github.com/sorintlab/sircles/util"
+
+	graphql "github.com/neelance/graphql-go"
 )
 
 type roleEventConnectionResolver struct {
@@ -29,6 +31,8 @@ func (r *roleEventConnectionResolver) Edges() *[]*roleEventEdgeResolver {
 		switch event.EventType {
 		case models.RoleEventTypeCircleChangesApplied:
 			ok = true
+		case models.RoleEventTypeCoreRoleElectionExpired:
+			ok = true
 		}
 		if ok {
 			l = append(l, &roleEventEdgeResolver{r.s, event, r.dataLoaders})
@@ -53,6 +57,9 @@ func (r *roleEventEdgeResolver) Event() *roleEventResolver {
 	case models.RoleEventTypeCircleChangesApplied:
 		eventData := r.event.Data.(*models.RoleEventCircleChangesApplied)
 		return &roleEventResolver{&roleEventCircleChangesAppliedResolver{r.s, r.event, eventData, r.dataLoaders}}
+	case models.RoleEventTypeCoreRoleElectionExpired:
+		eventData := r.event.Data.(*models.RoleEventCoreRoleElectionExpired)
+		return &roleEventResolver{&roleEventCoreRoleElectionExpiredResolver{r.s, r.event, eventData, r.dataLoaders}}
 	default:
 		return nil
 	}
@@ -72,6 +79,11 @@ func (r *roleEventResolver) ToRoleEventCircleChangesApplied() (*roleEventCircleC
 	return t, ok
 }
 
+func (r *roleEventResolver) ToRoleEventCoreRoleElectionExpired() (*roleEventCoreRoleElectionExpiredResolver, bool) {
+	t, ok := r.roleEvent.(*roleEventCoreRoleElectionExpiredResolver)
+	return t, ok
+}
+
 type roleEventCircleChangesAppliedResolver struct {
 	s         readdb.ReadDBService
 	event     *models.RoleEvent
@@ -118,6 +130,20 @@ func (r *roleEventCircleChangesAppliedResolver) Issuer(ctx context.Context) (*me
 	return &memberResolver{r.s, member, r.event.TimeLineID, r.dataLoaders}, nil
 }
 
+func (r *roleEventCircleChangesAppliedResolver) Proposal(ctx context.Context) (*proposalResolver, error) {
+	if r.eventData.ProposalID == nil {
+		return nil, nil
+	}
+	proposal, err := r.s.Proposal(ctx, r.event.TimeLineID, *r.eventData.ProposalID)
+	if err != nil {
+		return nil, err
+	}
+	if proposal == nil {
+		return nil, nil
+	}
+	return &proposalResolver{r.s, proposal, r.event.TimeLineID, r.dataLoaders}, nil
+}
+
 func (r *roleEventCircleChangesAppliedResolver) ChangedRoles() *[]*roleChangeResolver {
 	l := []*roleChangeResolver{}
 	// sort map to get repeatable ordered results
@@ -162,6 +188,70 @@ func (r *roleEventCircleChangesAppliedResolver) RolesFromCircle() *[]*roleParent
 	return &l
 }
 
+type roleEventCoreRoleElectionExpiredResolver struct {
+	s         readdb.ReadDBService
+	event     *models.RoleEvent
+	eventData *models.RoleEventCoreRoleElectionExpired
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *roleEventCoreRoleElectionExpiredResolver) TimeLine(ctx context.Context) (*timeLineResolver, error) {
+	tl, err := r.s.TimeLine(ctx, r.event.TimeLineID)
+	if err != nil {
+		return nil, err
+	}
+	if tl == nil {
+		return nil, nil
+	}
+	return &timeLineResolver{r.s, tl, r.dataLoaders}, nil
+}
+
+func (r *roleEventCoreRoleElectionExpiredResolver) Type() string {
+	return string(r.event.EventType)
+}
+
+func (r *roleEventCoreRoleElectionExpiredResolver) Role(ctx context.Context) (*roleResolver, error) {
+	role, err := r.s.Role(ctx, r.event.TimeLineID, r.event.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, nil
+	}
+	return NewRoleResolver(r.s, role, r.event.TimeLineID, r.dataLoaders), nil
+}
+
+func (r *roleEventCoreRoleElectionExpiredResolver) CoreRole(ctx context.Context) (*roleResolver, error) {
+	role, err := r.s.Role(ctx, r.event.TimeLineID, r.eventData.CoreRoleID)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, nil
+	}
+	return NewRoleResolver(r.s, role, r.event.TimeLineID, r.dataLoaders), nil
+}
+
+func (r *roleEventCoreRoleElectionExpiredResolver) Member(ctx context.Context) (*memberResolver, error) {
+	member, err := r.s.Member(ctx, r.event.TimeLineID, r.eventData.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, nil
+	}
+	return &memberResolver{r.s, member, r.event.TimeLineID, r.dataLoaders}, nil
+}
+
+func (r *roleEventCoreRoleElectionExpiredResolver) RoleType() string {
+	return string(r.eventData.RoleType)
+}
+
+func (r *roleEventCoreRoleElectionExpiredResolver) ElectionExpiration() graphql.Time {
+	return graphql.Time{Time: r.eventData.ElectionExpiration}
+}
+
 type roleChangeResolver struct {
 	s         readdb.ReadDBService
 	event     *models.RoleEvent
@@ -47,57 +47,146 @@ var Schema = `
 		role(timeLineID: TimeLineID, uid: ID!): Role
 		member(timeLineID: TimeLineID, uid: ID!): Member
 		tension(timeLineID: TimeLineID, uid: ID!): Tension
+		meeting(timeLineID: TimeLineID, uid: ID!): Meeting
+		project(timeLineID: TimeLineID, uid: ID!): Project
+		reportItem(timeLineID: TimeLineID, uid: ID!): ReportItem
+
+		// the members matching the search string and the filter, ordered by
+		// fullname if orderBy isn't provided. When after is provided the
+		// other arguments, except first, must not be provided since they're
+		// saved in the cursor
+		members(timeLineID: TimeLineID, search: String, filter: MemberFilter, orderBy: MemberOrder, first: Int, after: String): MemberConnection
+
+		// all the roles matching the filter, not paginated. Use rolesConnection to page them
+		roles(timeLineID: TimeLineID, filter: RoleFilter, orderBy: RoleOrder): [Role!]
+		// all the roles matching the filter ordered by name if orderBy isn't
+		// provided
+		rolesConnection(timeLineID: TimeLineID, filter: RoleFilter, orderBy: RoleOrder, first: Int, after: String, last: Int, before: String): RoleConnection
+
+		// the changes of the organization, or of the roles subtree starting at
+		// rootRoleUID, between two timelines. toTimeLineID defaults to the
+		// current timeline
+		orgDiff(fromTimeLineID: TimeLineID!, toTimeLineID: TimeLineID, rootRoleUID: ID): OrgDiff
 
-		members(timeLineID: TimeLineID, search: String, first: Int, after: String): MemberConnection
+		search(query: String!): SearchResult!
 
-		// TODO(sgotti) add pagination
-		roles(timeLineID: TimeLineID): [Role!]
+		// the status of the current or last readdb rebuild. Only available to admins
+		readDBRebuildStatus: ReadDBRebuildStatus
 
-		search(query: String!): SearchResult!
+		// the causation trees of the event with the provided id or of the
+		// events in the provided group or timeline. Only available to admins
+		eventCausation(eventID: ID, groupID: ID, timeLineID: TimeLineID): [CausationTree!]
+
+		// the command audit log entries, newest first, optionally filtered by
+		// issuing member, command type and time range [fromTime, toTime).
+		// Only available to admins
+		commandAuditLog(memberUID: ID, commandType: String, fromTime: Time, toTime: Time, first: Int, after: String): CommandAuditConnection
 	}
 
 	type Mutation {
+		// The role and tension update mutations accept an optional
+		// expectedTimeLineID, the timeline at which the client read the
+		// entity: the change is rejected with a conflict if the entity has
+		// been changed after it. The child role creation checks the parent
+		// circle and the role members mutations check also the role members
+		// (for a circle its direct members, lead link and core roles
+		// members).
+
 		// updates to root role. The root role can be directly updated by an admin or the leadlink
-		updateRootRole(updateRootRoleChange: UpdateRootRoleChange!): UpdateRootRoleResult
+		updateRootRole(updateRootRoleChange: UpdateRootRoleChange!, expectedTimeLineID: TimeLineID): UpdateRootRoleResult
 
 		// create a sub role inside a circle
-		circleCreateChildRole(roleUID: ID!, createRoleChange: CreateRoleChange!): CreateRoleResult
+		circleCreateChildRole(roleUID: ID!, createRoleChange: CreateRoleChange!, expectedTimeLineID: TimeLineID): CreateRoleResult
 		// updates a sub role inside a circle
-		circleUpdateChildRole(roleUID: ID!, updateRoleChange: UpdateRoleChange!): UpdateRoleResult
+		circleUpdateChildRole(roleUID: ID!, updateRoleChange: UpdateRoleChange!, expectedTimeLineID: TimeLineID): UpdateRoleResult
 		// deletes a sub role inside a circle
-		circleDeleteChildRole(roleUID: ID!, deleteRoleChange: DeleteRoleChange!): DeleteRoleResult
+		circleDeleteChildRole(roleUID: ID!, deleteRoleChange: DeleteRoleChange!, expectedTimeLineID: TimeLineID): DeleteRoleResult
 
-		setRoleAdditionalContent(roleUID: ID! content: String!): SetRoleAdditionalContentResult
+		setRoleAdditionalContent(roleUID: ID! content: String!, expectedTimeLineID: TimeLineID): SetRoleAdditionalContentResult
 
 		// sets a member as the circle's lead link
-		circleSetLeadLinkMember(roleUID: ID!, memberUID: ID!): GenericResult
+		circleSetLeadLinkMember(roleUID: ID!, memberUID: ID!, expectedTimeLineID: TimeLineID): GenericResult
 		// unsets the circle's lead link
-		circleUnsetLeadLinkMember(roleUID: ID!): GenericResult
+		circleUnsetLeadLinkMember(roleUID: ID!, expectedTimeLineID: TimeLineID): GenericResult
 
 		// sets a member as a circle's core role
-		circleSetCoreRoleMember(roleType: RoleType!, roleUID: ID!, memberUID: ID!, electionExpiration: Time): GenericResult
+		circleSetCoreRoleMember(roleType: RoleType!, roleUID: ID!, memberUID: ID!, electionExpiration: Time, expectedTimeLineID: TimeLineID): GenericResult
 		// unsets a circle's core role
-		circleUnsetCoreRoleMember(roleType: RoleType!, roleUID: ID!): GenericResult
+		circleUnsetCoreRoleMember(roleType: RoleType!, roleUID: ID!, expectedTimeLineID: TimeLineID): GenericResult
 
 		// adds a member as a circle's direct member. The member will become a circle core member also if not filling any role
-		circleAddDirectMember(roleUID: ID!, memberUID: ID!): GenericResult
+		circleAddDirectMember(roleUID: ID!, memberUID: ID!, expectedTimeLineID: TimeLineID): GenericResult
 		// removes a member as a circle's direct member.
-		circleRemoveDirectMember(roleUID: ID!, memberUID: ID!): GenericResult
+		circleRemoveDirectMember(roleUID: ID!, memberUID: ID!, expectedTimeLineID: TimeLineID): GenericResult
 
 		// adds a member to a role
-		roleAddMember(roleUID: ID!, memberUID: ID!, focus: String, noCoreMember: Boolean = false): GenericResult
+		roleAddMember(roleUID: ID!, memberUID: ID!, focus: String, noCoreMember: Boolean = false, expectedTimeLineID: TimeLineID): GenericResult
 		// removes a member from a role
-		roleRemoveMember(roleUID: ID!, memberUID: ID!): GenericResult
+		roleRemoveMember(roleUID: ID!, memberUID: ID!, expectedTimeLineID: TimeLineID): GenericResult
 
 		createMember(createMemberChange: CreateMemberChange): CreateMemberResult
 		updateMember(updateMemberChange: UpdateMemberChange): UpdateMemberResult
 		setMemberPassword(memberUID: ID!, curPassword: String, newPassword: String!): GenericResult
 		setMemberMatchUID(memberUID: ID!, matchUID: String!): GenericResult
+		// deletes a member, removing it from all its roles and closing its open tensions
+		deleteMember(memberUID: ID!): GenericResult
+		forgetMember(memberUID: ID!): GenericResult
 		importMember(loginName: String!): Member
 
 		createTension(createTensionChange: CreateTensionChange): CreateTensionResult
-		updateTension(updateTensionChange: UpdateTensionChange): UpdateTensionResult
-		closeTension(closeTensionChange: CloseTensionChange): CloseTensionResult
+		updateTension(updateTensionChange: UpdateTensionChange, expectedTimeLineID: TimeLineID): UpdateTensionResult
+		closeTension(closeTensionChange: CloseTensionChange, expectedTimeLineID: TimeLineID): CloseTensionResult
+		// reopens a closed tension. Only the tension member, the tension circle lead link or an admin can reopen it
+		reopenTension(tensionUID: ID!): GenericResult
+		// changes the state of an open tension (use closeTension to close it). Only the tension member, the tension circle lead link, the assignee or an admin can change it
+		changeTensionState(tensionUID: ID!, state: TensionState!): GenericResult
+		// adds a comment or a reply to a comment. Only the tension member, the tension circle lead link, the assignee or an admin can add it
+		addTensionComment(addTensionCommentChange: AddTensionCommentChange): AddTensionCommentResult
+		// assigns the tension to a member filling the tension circle or one of its child roles, unassigns it when roleUID and memberUID are omitted. Only the tension member, the tension circle lead link or an admin can assign it
+		assignTension(tensionUID: ID!, roleUID: ID, memberUID: ID): GenericResult
+		// replaces the tension labels. Only the tension member, the tension circle lead link or an admin can set them
+		setTensionLabels(tensionUID: ID!, labels: [String!]!): GenericResult
+
+		// creates a proposal of changes to a circle child roles
+		createProposal(createProposalChange: CreateProposalChange): CreateProposalResult
+		// replaces the proposal changes, removing all the current objections
+		updateProposal(updateProposalChange: UpdateProposalChange): UpdateProposalResult
+		// raises an objection to a proposal. Only the proposal circle members can object
+		objectProposal(proposalUID: ID!, reason: String!): GenericResult
+		// accepts a proposal applying all its changes. Only the proposal circle lead link or secretary can accept a proposal
+		acceptProposal(proposalUID: ID!): GenericResult
+
+		// creates a circle meeting. The facilitator and secretary are the members currently filling the circle core roles
+		createMeeting(createMeetingChange: CreateMeetingChange): CreateMeetingResult
+		// adds an agenda item to a meeting, optionally related to an open tension of the meeting circle
+		addMeetingAgendaItem(addMeetingAgendaItemChange: AddMeetingAgendaItemChange): AddMeetingAgendaItemResult
+		// sets the outcome of a meeting agenda item. Only the meeting facilitator or secretary can set it
+		setMeetingAgendaItemOutcome(meetingUID: ID!, agendaItemUID: ID!, outcome: String!): GenericResult
+		// closes a meeting. Only the meeting facilitator or secretary can close it
+		closeMeeting(meetingUID: ID!): GenericResult
+
+		// creates a project owned by a role and by a member filling it. Only the member or an admin can create it
+		createProject(createProjectChange: CreateProjectChange): CreateProjectResult
+		// updates a project. Only the project member or an admin can update it
+		updateProject(updateProjectChange: UpdateProjectChange): UpdateProjectResult
+		// changes the project status. Only the project member or an admin can change it
+		changeProjectStatus(projectUID: ID!, status: ProjectStatus!): GenericResult
+		// adds a next action to a project. Only the project member or an admin can add it
+		addProjectNextAction(addProjectNextActionChange: AddProjectNextActionChange): AddProjectNextActionResult
+		// completes a project next action. Only the project member or an admin can complete it
+		completeProjectNextAction(projectUID: ID!, nextActionUID: ID!): GenericResult
+
+		// creates a circle checklist item or metric assigned to a circle child role. Only the circle lead link or secretary can create it
+		createReportItem(createReportItemChange: CreateReportItemChange): CreateReportItemResult
+		// updates a report item. Only the circle lead link or secretary can update it
+		updateReportItem(updateReportItemChange: UpdateReportItemChange): UpdateReportItemResult
+		// deletes a report item. Only the circle lead link or secretary can delete it
+		deleteReportItem(reportItemUID: ID!): GenericResult
+		// reports the value of a report item for the period containing the provided time. Only the members filling the assigned role or the circle lead link or secretary can report it
+		setReportItemValue(reportItemUID: ID!, period: Time!, value: Float!): GenericResult
+
+		// starts a rebuild of the readdb from the eventstore in background. The current readdb will be atomically replaced at the end of the rebuild. Only an admin can rebuild it
+		rebuildReadDB: GenericResult
 	}
 
 	enum RoleType {
@@ -146,15 +235,73 @@ var Schema = `
 		parent: Role
 		parents: [Role!]
 		roles: [Role!]
+		// child roles ordered by name
+		rolesConnection(first: Int, after: String, last: Int, before: String): RoleConnection
 		// Members of the circle (valid only for circles)
 		// Composed of core members (all members filling a circle role except to ones explictly excluded, directly assigned member, sub-circle replinks)
 		circleMembers: [CircleMemberEdge!]
+		circleMembersConnection(first: Int, after: String, last: Int, before: String): CircleMemberConnection
 		// Members filling the role (valid only for non circles)
 		roleMembers: [RoleMemberEdge!]
 		// tensions for this role, only lead link members can see them
 		tensions: [Tension!]
+		// tensions for this role ordered by title
+		tensionsConnection(first: Int, after: String, last: Int, before: String): TensionConnection
 		memberCirclePermissions: MemberCirclePermission
 		events(first: Int, after: String): RoleEventConnection!
+		// meetings of the circle (valid only for circles)
+		meetings: [Meeting!]
+		// projects owned by the role
+		projects: [Project!]
+		// checklist items and metrics of the circle (valid only for circles)
+		reportItems: [ReportItem!]
+		// checklist items and metrics assigned to the role
+		assignedReportItems: [ReportItem!]
+		// elections of the circle elected core roles expired or expiring
+		// within the provided days (valid only for circles)
+		expiringElections(withinDays: Int = 30): [CoreRoleElection!]
+	}
+
+	# Information about a connection page
+	type PageInfo {
+		hasNextPage: Boolean!
+		hasPreviousPage: Boolean!
+		startCursor: String
+		endCursor: String
+	}
+
+	type RoleConnection {
+		edges: [RoleEdge!]!
+		pageInfo: PageInfo!
+		totalCount: Int!
+	}
+
+	type RoleEdge {
+		cursor: String!
+		role: Role!
+	}
+
+	type CircleMemberConnection {
+		edges: [CircleMemberEdge!]!
+		pageInfo: PageInfo!
+		totalCount: Int!
+	}
+
+	type MemberRoleConnection {
+		edges: [MemberRoleEdge!]!
+		pageInfo: PageInfo!
+		totalCount: Int!
+	}
+
+	type TensionConnection {
+		edges: [TensionEdge!]!
+		pageInfo: PageInfo!
+		totalCount: Int!
+	}
+
+	type TensionEdge {
+		cursor: String!
+		tension: Tension!
 	}
 
 	type RoleEventConnection {
@@ -193,8 +340,20 @@ var Schema = `
 		email: String!
 		circles: [MemberCircleEdge!]
 		roles: [MemberRoleEdge!]
+		// member roles ordered by role name
+		rolesConnection(first: Int, after: String, last: Int, before: String): MemberRoleConnection
 		// Member tensions, only the member can see them
 		tensions: [Tension!]
+		// member tensions ordered by title, only the member can see them
+		tensionsConnection(first: Int, after: String, last: Int, before: String): TensionConnection
+		// projects owned by the member
+		projects: [Project!]
+		// elections of the member elected core roles expired or expiring
+		// within the provided days
+		expiringElections(withinDays: Int = 30): [CoreRoleElection!]
+		// the member history, newest first. Without a cursor it starts from
+		// the query timeline
+		events(first: Int, after: String): MemberEventConnection!
 	}
 
 	type MemberConnection {
@@ -207,6 +366,13 @@ var Schema = `
 		member: Member!
 	}
 
+	enum TensionState {
+		OPEN
+		INPROGRESS
+		PROCESSED
+		CLOSED
+	}
+
 	# A tension
 	type Tension {
 		uid: ID!
@@ -215,7 +381,142 @@ var Schema = `
 		role: Role
 		closed: Boolean!
 		closeReason: String!
-		member: Member!
+		state: TensionState!
+		labels: [String!]!
+		// null when the member has been deleted
+		member: Member
+		proposals: [Proposal!]
+		// the top level comments with their replies, only the members that
+		// can comment the tension can see them
+		comments: [TensionComment!]
+		// null when the tension isn't assigned or the member has been deleted
+		assignee: Member
+		// the role filled by the assignee
+		assigneeRole: Role
+		memberTensionPermissions: MemberTensionPermission
+	}
+
+	type TensionComment {
+		uid: ID!
+		text: String!
+		// null when the member has been deleted
+		member: Member
+		timeLine: TimeLine!
+		replies: [TensionComment!]!
+	}
+
+	type MemberTensionPermission {
+		manageTension: Boolean!
+		changeTensionState: Boolean!
+		comment: Boolean!
+	}
+
+	enum ProposalState {
+		OPEN
+		ACCEPTED
+	}
+
+	# A proposal of changes to a circle child roles
+	type Proposal {
+		uid: ID!
+		tension: Tension
+		role: Role
+		// null when the member has been deleted
+		proposer: Member
+		state: ProposalState!
+		// TODO(sgotti) As a first step we just expose the proposal changes json
+		// as a string field
+		changes: String!
+		objections: [ProposalObjection!]
+	}
+
+	type ProposalObjection {
+		member: Member
+		reason: String!
+	}
+
+	enum MeetingType {
+		GOVERNANCE
+		TACTICAL
+	}
+
+	# A circle meeting
+	type Meeting {
+		uid: ID!
+		meetingType: MeetingType!
+		title: String!
+		startTime: Time!
+		closed: Boolean!
+		role: Role
+		// null when not assigned at meeting creation or when the member has been deleted
+		facilitator: Member
+		// null when not assigned at meeting creation or when the member has been deleted
+		secretary: Member
+		attendees: [Member!]
+		agendaItems: [MeetingAgendaItem!]
+	}
+
+	type MeetingAgendaItem {
+		uid: ID!
+		title: String!
+		// null when the agenda item isn't related to a tension
+		tension: Tension
+		outcome: String!
+	}
+
+	enum ProjectStatus {
+		CURRENT
+		WAITING
+		DONE
+	}
+
+	# A project owned by a role and by a member filling it
+	type Project {
+		uid: ID!
+		title: String!
+		description: String!
+		status: ProjectStatus!
+		role: Role
+		// null when the member has been deleted
+		member: Member
+		nextActions: [NextAction!]
+	}
+
+	type NextAction {
+		uid: ID!
+		description: String!
+		completed: Boolean!
+	}
+
+	enum ReportItemType {
+		CHECKLIST
+		METRIC
+	}
+
+	enum ReportFrequency {
+		WEEKLY
+		MONTHLY
+		QUARTERLY
+	}
+
+	# A circle checklist item or metric assigned to a role
+	type ReportItem {
+		uid: ID!
+		reportItemType: ReportItemType!
+		description: String!
+		frequency: ReportFrequency!
+		circle: Role
+		// null when the role has been deleted
+		role: Role
+		// values reported in the provided periods range ordered by period.
+		// Checklist items values are 1 when done, 0 otherwise
+		values(from: Time, to: Time): [ReportItemValue!]
+	}
+
+	type ReportItemValue {
+		// the start time of the period
+		period: Time!
+		value: Float!
 	}
 
 	# A role member edge
@@ -228,14 +529,30 @@ var Schema = `
 
 	# A member role edge
 	type MemberRoleEdge {
+		// set only when part of a MemberRoleConnection
+		cursor: String
 		role: Role!
 		focus: String
 		noCoreMember: Boolean!
 		electionExpiration: Time
 	}
 
+	# An elected core role (facilitator, secretary, rep link) assignment with an election expiration
+	type CoreRoleElection {
+		// the core role
+		role: Role!
+		// the circle of the core role
+		circle: Role
+		member: Member!
+		roleType: RoleType!
+		electionExpiration: Time!
+		expired: Boolean!
+	}
+
 	# A circle member edge
 	type CircleMemberEdge {
+		// set only when part of a CircleMemberConnection
+		cursor: String
 		member: Member!
 		isCoreMember: Boolean!
 		isDirectMember: Boolean!
@@ -265,6 +582,8 @@ var Schema = `
 		assignCircleDirectMembers: Boolean!
 		manageChildRoles: Boolean!
 		manageRoleAdditionalContent: Boolean!
+		acceptProposals: Boolean!
+		manageReportItems: Boolean!
 		assignRootCircleLeadLink: Boolean!
 		manageRootCircle: Boolean!
 	}
@@ -342,6 +661,9 @@ var Schema = `
 		role: Role
 		hasErrors: Boolean!
 		genericError: String
+		// true when the change is rejected since the entity has been changed
+		// after the provided expectedTimeLineID
+		conflict: Boolean!
 		updateRootRoleChangeErrors: UpdateRootRoleChangeErrors
 	}
 
@@ -375,6 +697,9 @@ var Schema = `
 		role: Role
 		hasErrors: Boolean!
 		genericError: String
+		// true when the change is rejected since the entity has been changed
+		// after the provided expectedTimeLineID
+		conflict: Boolean!
 		createRoleChangeErrors: CreateRoleChangeErrors
 	}
 
@@ -400,6 +725,9 @@ var Schema = `
 		role: Role
 		hasErrors: Boolean!
 		genericError: String
+		// true when the change is rejected since the entity has been changed
+		// after the provided expectedTimeLineID
+		conflict: Boolean!
 		updateRoleChangeErrors: UpdateRoleChangeErrors
 	}
 
@@ -420,17 +748,18 @@ var Schema = `
 	type DeleteRoleResult {
 		hasErrors: Boolean!
 		genericError: String
-	}
-
-	type DeleteRoleResult {
-		hasErrors: Boolean!
-		genericError: String
+		// true when the change is rejected since the entity has been changed
+		// after the provided expectedTimeLineID
+		conflict: Boolean!
 	}
 
 	type SetRoleAdditionalContentResult {
 		roleAdditionalContent: RoleAdditionalContent
 		hasErrors: Boolean!
 		genericError: String
+		// true when the change is rejected since the entity has been changed
+		// after the provided expectedTimeLineID
+		conflict: Boolean!
 	}
 
 	input AvatarData {
@@ -513,6 +842,9 @@ var Schema = `
 		tension: Tension
 		hasErrors: Boolean!
 		genericError: String
+		// true when the change is rejected since the entity has been changed
+		// after the provided expectedTimeLineID
+		conflict: Boolean!
 		updateTensionChangeErrors: UpdateTensionChangeErrors
 	}
 
@@ -529,11 +861,250 @@ var Schema = `
 	type CloseTensionResult {
 		hasErrors: Boolean!
 		genericError: String
+		// true when the change is rejected since the entity has been changed
+		// after the provided expectedTimeLineID
+		conflict: Boolean!
+	}
+
+	input AddTensionCommentChange {
+		tensionUID: ID!
+		// the replied comment
+		parentCommentUID: ID
+		text: String!
+	}
+
+	type AddTensionCommentResult {
+		tension: Tension
+		commentUID: ID
+		hasErrors: Boolean!
+		genericError: String
+	}
+
+	input CreateProposalChange {
+		tensionUID: ID!
+		roleUID: ID!
+		createRoleChanges: [CreateRoleChange!]
+		updateRoleChanges: [UpdateRoleChange!]
+		deleteRoleChanges: [DeleteRoleChange!]
+	}
+
+	type CreateProposalResult {
+		proposal: Proposal
+		hasErrors: Boolean!
+		genericError: String
+		proposalChangesErrors: ProposalChangesErrors
+	}
+
+	input UpdateProposalChange {
+		uid: ID!
+		createRoleChanges: [CreateRoleChange!]
+		updateRoleChanges: [UpdateRoleChange!]
+		deleteRoleChanges: [DeleteRoleChange!]
+	}
+
+	type UpdateProposalResult {
+		proposal: Proposal
+		hasErrors: Boolean!
+		genericError: String
+		proposalChangesErrors: ProposalChangesErrors
+	}
+
+	type ProposalChangesErrors {
+		createRoleChangesErrors: [CreateRoleChangeErrors!]
+		updateRoleChangesErrors: [UpdateRoleChangeErrors!]
+	}
+
+	input CreateMeetingChange {
+		roleUID: ID!
+		meetingType: MeetingType!
+		title: String!
+		startTime: Time!
+		attendeesUIDs: [ID!]
+	}
+
+	type CreateMeetingResult {
+		meeting: Meeting
+		hasErrors: Boolean!
+		genericError: String
+		createMeetingChangeErrors: CreateMeetingChangeErrors
+	}
+
+	type CreateMeetingChangeErrors {
+		meetingType: String
+		title: String
+	}
+
+	input AddMeetingAgendaItemChange {
+		meetingUID: ID!
+		title: String!
+		tensionUID: ID
+	}
+
+	type AddMeetingAgendaItemResult {
+		meeting: Meeting
+		agendaItemUID: ID
+		hasErrors: Boolean!
+		genericError: String
+	}
+
+	input CreateProjectChange {
+		roleUID: ID!
+		memberUID: ID!
+		title: String!
+		description: String!
+	}
+
+	type CreateProjectResult {
+		project: Project
+		hasErrors: Boolean!
+		genericError: String
+		createProjectChangeErrors: ProjectChangeErrors
+	}
+
+	input UpdateProjectChange {
+		uid: ID!
+		title: String!
+		description: String!
+	}
+
+	type UpdateProjectResult {
+		project: Project
+		hasErrors: Boolean!
+		genericError: String
+		updateProjectChangeErrors: ProjectChangeErrors
+	}
+
+	type ProjectChangeErrors {
+		title: String
+		description: String
+	}
+
+	input AddProjectNextActionChange {
+		projectUID: ID!
+		description: String!
+	}
+
+	type AddProjectNextActionResult {
+		project: Project
+		nextActionUID: ID
+		hasErrors: Boolean!
+		genericError: String
+	}
+
+	input CreateReportItemChange {
+		circleUID: ID!
+		roleUID: ID!
+		reportItemType: ReportItemType!
+		description: String!
+		frequency: ReportFrequency!
+	}
+
+	type CreateReportItemResult {
+		reportItem: ReportItem
+		hasErrors: Boolean!
+		genericError: String
+		createReportItemChangeErrors: CreateReportItemChangeErrors
+	}
+
+	type CreateReportItemChangeErrors {
+		reportItemType: String
+		description: String
+		frequency: String
+	}
+
+	input UpdateReportItemChange {
+		uid: ID!
+		roleUID: ID!
+		description: String!
+	}
+
+	type UpdateReportItemResult {
+		reportItem: ReportItem
+		hasErrors: Boolean!
+		genericError: String
+		updateReportItemChangeErrors: UpdateReportItemChangeErrors
+	}
+
+	type UpdateReportItemChangeErrors {
+		description: String
 	}
 
 	type GenericResult {
 		hasErrors: Boolean!
 		genericError: String
+		// true when the change is rejected since the entity has been changed
+		// after the provided expectedTimeLineID
+		conflict: Boolean!
+	}
+
+	type ReadDBRebuildStatus {
+		running: Boolean!
+		startTime: Time
+		endTime: Time
+		// the last event sequence number applied to the rebuilt readdb
+		sequenceNumber: Float!
+		// the eventstore last sequence number
+		lastSequenceNumber: Float!
+		error: String
+	}
+
+	// all the events with the same correlation id arranged in a causation tree
+	type CausationTree {
+		correlationID: ID!
+		// the events in depth first order
+		events: [CausationEvent!]!
+	}
+
+	type CausationEvent {
+		id: ID!
+		sequenceNumber: Float!
+		timeLineID: TimeLineID!
+		timestamp: Time!
+		eventType: String!
+		aggregateType: String!
+		aggregateID: String!
+		correlationID: ID
+		causationID: ID
+		// the causation id of the command generating the event
+		commandCausationID: ID
+		groupID: ID
+		commandIssuerID: ID
+		// the id of the event that caused this event, empty for the tree roots
+		parentID: ID
+		depth: Int!
+	}
+
+	type CommandAuditConnection {
+		edges: [CommandAuditEdge!]
+		hasMoreData: Boolean!
+	}
+
+	type CommandAuditEdge {
+		cursor: String!
+		entry: CommandAuditEntry!
+	}
+
+	// an executed command or a request rejected before issuing a command
+	type CommandAuditEntry {
+		sequenceNumber: Float!
+		timestamp: Time!
+		// empty for requests rejected before issuing a command
+		commandID: ID
+		// the command type or, for requests rejected before issuing a
+		// command, the request type
+		commandType: String!
+		correlationID: ID
+		causationID: ID
+		issuerID: ID
+		aggregateType: String
+		aggregateID: String
+		groupID: ID
+		eventsCount: Int!
+		// one of executed, rejected or failed
+		result: String!
+		error: String
+		remoteAddr: String
+		userAgent: String
 	}
 
 	// TODO(sgotti) As a first step we just expose the bleve search results json
@@ -544,8 +1115,57 @@ var Schema = `
 		result: String!
 	}
 
+	enum OrderDirection {
+		ASC
+		DESC
+	}
+
+	// All the provided conditions must be matched
+	input RoleFilter {
+		roleTypes: [RoleType!]
+		// min and max depth (inclusive) of the roles, the root role has depth 0
+		minDepth: Int
+		maxDepth: Int
+		// only the roles in the subtree starting at this role (included)
+		subtreeRootUID: ID
+		// when true only the roles, excluding circles, without members filling them.
+		// When false only the roles with members filling them
+		unfilled: Boolean
+	}
+
+	enum RoleOrderField {
+		NAME
+		DEPTH
+		ROLETYPE
+	}
+
+	input RoleOrder {
+		field: RoleOrderField!
+		direction: OrderDirection
+	}
+
+	// All the provided conditions must be matched
+	input MemberFilter {
+		// only the members filling more than this number of roles
+		filledRolesGreaterThan: Int
+		// only the lead link of this circle
+		leadLinkOfUID: ID
+	}
+
+	enum MemberOrderField {
+		FULLNAME
+		USERNAME
+		FILLEDROLES
+	}
+
+	input MemberOrder {
+		field: MemberOrderField!
+		direction: OrderDirection
+	}
+
 	enum RoleEventType {
 		CircleChangesApplied
+		CoreRoleElectionExpired
 	}
 
 	interface RoleEvent {
@@ -561,6 +1181,19 @@ var Schema = `
 		changedRoles: [RoleChange!]
 		rolesFromCircle: [RoleParentChange!]
 		rolesToCircle: [RoleParentChange!]
+		// The accepted proposal that generated these changes
+		proposal: Proposal
+	}
+
+	type RoleEventCoreRoleElectionExpired implements RoleEvent {
+		// The circle at the event timeline
+		role: Role
+		// The core role at the event timeline
+		coreRole: Role
+		// The member at the event timeline
+		member: Member
+		roleType: RoleType!
+		electionExpiration: Time!
 	}
 
 	type RoleChange {
@@ -578,6 +1211,124 @@ var Schema = `
 		previousParent: Role!
 		newParent: Role!
 	}
+
+	type MemberEventConnection {
+		edges: [MemberEventEdge!]
+		hasMoreData: Boolean!
+	}
+
+	type MemberEventEdge {
+		cursor: String!
+		event: MemberEvent!
+	}
+
+	enum MemberEventType {
+		MemberCreated
+		MemberUpdated
+		MemberDeleted
+		CircleDirectMemberAdded
+		CircleDirectMemberRemoved
+		RoleMemberAdded
+		RoleMemberUpdated
+		RoleMemberRemoved
+		LeadLinkMemberSet
+		LeadLinkMemberUnset
+		CoreRoleMemberSet
+		CoreRoleMemberUnset
+		CoreRoleElectionExpired
+	}
+
+	interface MemberEvent {
+		timeLine: TimeLine!
+		type: MemberEventType!
+	}
+
+	// The member creation, profile update and deletion
+	type MemberEventMemberChanged implements MemberEvent {
+		// The member at the event timeline
+		member: Member
+		// The member before the event
+		previousMember: Member
+		// The changed profile fields (isAdmin, userName, fullName, email)
+		changedFields: [String!]!
+	}
+
+	// The member added to or removed from the direct members of a circle
+	type MemberEventCircleDirectMemberChanged implements MemberEvent {
+		// The circle at the event timeline
+		circle: Role
+	}
+
+	// The member assigned to or removed from a role, including the lead
+	// link and the elected core roles
+	type MemberEventRoleMemberChanged implements MemberEvent {
+		// The role at the event timeline
+		role: Role
+		roleType: RoleType!
+		// The role circle at the event timeline
+		circle: Role
+		focus: String
+		noCoreMember: Boolean!
+		electionExpiration: Time
+	}
+
+	// The organization changes between two timelines. Every change contains
+	// the changed entity at toTimeLineID and at fromTimeLineID (the previous
+	// fields). changeType is one of new, updated, deleted
+	type OrgDiff {
+		fromTimeLineID: TimeLineID!
+		toTimeLineID: TimeLineID!
+		roles: [RoleDiff!]!
+		domains: [DomainDiff!]!
+		accountabilities: [AccountabilityDiff!]!
+		roleMembers: [RoleMemberDiff!]!
+		coreRoles: [CoreRoleDiff!]!
+	}
+
+	// a role is updated when its type, name, purpose or parent changed
+	type RoleDiff {
+		changeType: String!
+		role: Role
+		previousRole: Role
+		parent: Role
+		previousParent: Role
+	}
+
+	type DomainDiff {
+		changeType: String!
+		role: Role
+		previousRole: Role
+		domain: Domain
+		previousDomain: Domain
+	}
+
+	type AccountabilityDiff {
+		changeType: String!
+		role: Role
+		previousRole: Role
+		accountability: Accountability
+		previousAccountability: Accountability
+	}
+
+	// a member assignment to a role
+	type RoleMemberDiff {
+		changeType: String!
+		role: Role
+		previousRole: Role
+		roleMember: RoleMemberEdge
+		previousRoleMember: RoleMemberEdge
+	}
+
+	// a change of the member filling a circle core role: new when the core
+	// role has been filled, deleted when emptied
+	type CoreRoleDiff {
+		changeType: String!
+		roleType: RoleType!
+		circle: Role
+		previousCircle: Role
+		roleMember: RoleMemberEdge
+		previousRoleMember: RoleMemberEdge
+	}
 `
 
 // NOTE(sgotti) we currently don't provide relay like Node global IDs.
@@ -635,7 +1386,9 @@ func unmarshalTimeLineCursor(s string) (*TimeLineCursor, error) {
 type MemberConnectionCursor struct {
 	TimeLineID   util.TimeLineNumber
 	SearchString string
-	FullName     string
+	Filter       *readdb.MemberFilter
+	Order        *readdb.MemberOrder
+	MemberID     util.ID
 }
 
 func marshalMemberConnectionCursor(c *MemberConnectionCursor) (string, error) {
@@ -682,15 +1435,73 @@ func unmarshalRoleEventConnectionCursor(s string) (*RoleEventConnectionCursor, e
 	return c, nil
 }
 
-func errorToStringP(err error) *string {
-	if err == nil {
-		return nil
-	}
-	s := err.Error()
-	return &s
+type MemberEventConnectionCursor struct {
+	SequenceNumber int64
 }
 
-type UpdateRootRoleChange struct {
+func marshalMemberEventConnectionCursor(c *MemberEventConnectionCursor) (string, error) {
+	cj, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(cj), nil
+}
+
+func unmarshalMemberEventConnectionCursor(s string) (*MemberEventConnectionCursor, error) {
+	cj, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var c *MemberEventConnectionCursor
+	if err := json.Unmarshal(cj, &c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// PageConnectionCursor is the cursor of the role, tension, circle member and
+// member role connections. Since the connections are queried at a fixed
+// timeline it's the item position in the list.
+type PageConnectionCursor struct {
+	Position int
+}
+
+func marshalPageConnectionCursor(c *PageConnectionCursor) (string, error) {
+	cj, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(cj), nil
+}
+
+func unmarshalPageConnectionCursor(s string) (*PageConnectionCursor, error) {
+	cj, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var c *PageConnectionCursor
+	if err := json.Unmarshal(cj, &c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// isConflictError reports if the provided result generic error is a conflict
+// error
+func isConflictError(err error) bool {
+	_, ok := err.(*command.ConflictError)
+	return ok
+}
+
+func errorToStringP(err error) *string {
+	if err == nil {
+		return nil
+	}
+	s := err.Error()
+	return &s
+}
+
+type UpdateRootRoleChange struct {
 	UID                         graphql.ID
 	NameChanged                 *bool
 	Name                        *string
@@ -1257,6 +2068,311 @@ func (t *CloseTensionChange) toCommandChange() (*change.CloseTensionChange, erro
 	return mt, nil
 }
 
+func proposalChangesToCommandChange(createRoleChanges *[]*CreateRoleChange, updateRoleChanges *[]*UpdateRoleChange, deleteRoleChanges *[]*DeleteRoleChange) (*change.ProposalChanges, error) {
+	mp := &change.ProposalChanges{}
+
+	if createRoleChanges != nil {
+		for _, c := range *createRoleChanges {
+			createRoleChange, err := c.toCommandChange()
+			if err != nil {
+				return nil, err
+			}
+			mp.CreateRoleChanges = append(mp.CreateRoleChanges, *createRoleChange)
+		}
+	}
+	if updateRoleChanges != nil {
+		for _, c := range *updateRoleChanges {
+			updateRoleChange, err := c.toCommandChange()
+			if err != nil {
+				return nil, err
+			}
+			mp.UpdateRoleChanges = append(mp.UpdateRoleChanges, *updateRoleChange)
+		}
+	}
+	if deleteRoleChanges != nil {
+		for _, c := range *deleteRoleChanges {
+			deleteRoleChange, err := c.toCommandChange()
+			if err != nil {
+				return nil, err
+			}
+			mp.DeleteRoleChanges = append(mp.DeleteRoleChanges, *deleteRoleChange)
+		}
+	}
+
+	return mp, nil
+}
+
+type AddTensionCommentChange struct {
+	TensionUID       graphql.ID
+	ParentCommentUID *graphql.ID
+	Text             string
+}
+
+func (t *AddTensionCommentChange) toCommandChange() (*change.AddTensionCommentChange, error) {
+	mt := &change.AddTensionCommentChange{
+		Text: t.Text,
+	}
+
+	tensionID, err := unmarshalUID(t.TensionUID)
+	if err != nil {
+		return nil, err
+	}
+	mt.TensionID = tensionID
+
+	if t.ParentCommentUID != nil {
+		parentCommentID, err := unmarshalUID(*t.ParentCommentUID)
+		if err != nil {
+			return nil, err
+		}
+		mt.ParentCommentID = &parentCommentID
+	}
+
+	return mt, nil
+}
+
+type CreateProposalChange struct {
+	TensionUID        graphql.ID
+	RoleUID           graphql.ID
+	CreateRoleChanges *[]*CreateRoleChange
+	UpdateRoleChanges *[]*UpdateRoleChange
+	DeleteRoleChanges *[]*DeleteRoleChange
+}
+
+func (p *CreateProposalChange) toCommandChange() (*change.CreateProposalChange, error) {
+	mp := &change.CreateProposalChange{}
+
+	tensionID, err := unmarshalUID(p.TensionUID)
+	if err != nil {
+		return nil, err
+	}
+	mp.TensionID = tensionID
+
+	roleID, err := unmarshalUID(p.RoleUID)
+	if err != nil {
+		return nil, err
+	}
+	mp.RoleID = roleID
+
+	proposalChanges, err := proposalChangesToCommandChange(p.CreateRoleChanges, p.UpdateRoleChanges, p.DeleteRoleChanges)
+	if err != nil {
+		return nil, err
+	}
+	mp.ProposalChanges = *proposalChanges
+
+	return mp, nil
+}
+
+type CreateMeetingChange struct {
+	RoleUID       graphql.ID
+	MeetingType   string
+	Title         string
+	StartTime     graphql.Time
+	AttendeesUIDs *[]graphql.ID
+}
+
+func (m *CreateMeetingChange) toCommandChange() (*change.CreateMeetingChange, error) {
+	mm := &change.CreateMeetingChange{
+		MeetingType: models.MeetingTypeFromString(m.MeetingType),
+		Title:       m.Title,
+		StartTime:   m.StartTime.Time,
+	}
+
+	roleID, err := unmarshalUID(m.RoleUID)
+	if err != nil {
+		return nil, err
+	}
+	mm.RoleID = roleID
+
+	if m.AttendeesUIDs != nil {
+		for _, attendeeUID := range *m.AttendeesUIDs {
+			attendeeID, err := unmarshalUID(attendeeUID)
+			if err != nil {
+				return nil, err
+			}
+			mm.AttendeesIDs = append(mm.AttendeesIDs, attendeeID)
+		}
+	}
+
+	return mm, nil
+}
+
+type AddMeetingAgendaItemChange struct {
+	MeetingUID graphql.ID
+	Title      string
+	TensionUID *graphql.ID
+}
+
+func (m *AddMeetingAgendaItemChange) toCommandChange() (*change.AddMeetingAgendaItemChange, error) {
+	mm := &change.AddMeetingAgendaItemChange{
+		Title: m.Title,
+	}
+
+	meetingID, err := unmarshalUID(m.MeetingUID)
+	if err != nil {
+		return nil, err
+	}
+	mm.MeetingID = meetingID
+
+	if m.TensionUID != nil {
+		tensionID, err := unmarshalUID(*m.TensionUID)
+		if err != nil {
+			return nil, err
+		}
+		mm.TensionID = &tensionID
+	}
+
+	return mm, nil
+}
+
+type CreateProjectChange struct {
+	RoleUID     graphql.ID
+	MemberUID   graphql.ID
+	Title       string
+	Description string
+}
+
+func (p *CreateProjectChange) toCommandChange() (*change.CreateProjectChange, error) {
+	pp := &change.CreateProjectChange{
+		Title:       p.Title,
+		Description: p.Description,
+	}
+
+	roleID, err := unmarshalUID(p.RoleUID)
+	if err != nil {
+		return nil, err
+	}
+	pp.RoleID = roleID
+
+	memberID, err := unmarshalUID(p.MemberUID)
+	if err != nil {
+		return nil, err
+	}
+	pp.MemberID = memberID
+
+	return pp, nil
+}
+
+type UpdateProjectChange struct {
+	UID         graphql.ID
+	Title       string
+	Description string
+}
+
+func (p *UpdateProjectChange) toCommandChange() (*change.UpdateProjectChange, error) {
+	pp := &change.UpdateProjectChange{
+		Title:       p.Title,
+		Description: p.Description,
+	}
+
+	id, err := unmarshalUID(p.UID)
+	if err != nil {
+		return nil, err
+	}
+	pp.ID = id
+
+	return pp, nil
+}
+
+type AddProjectNextActionChange struct {
+	ProjectUID  graphql.ID
+	Description string
+}
+
+func (p *AddProjectNextActionChange) toCommandChange() (*change.AddProjectNextActionChange, error) {
+	pp := &change.AddProjectNextActionChange{
+		Description: p.Description,
+	}
+
+	projectID, err := unmarshalUID(p.ProjectUID)
+	if err != nil {
+		return nil, err
+	}
+	pp.ProjectID = projectID
+
+	return pp, nil
+}
+
+type CreateReportItemChange struct {
+	CircleUID      graphql.ID
+	RoleUID        graphql.ID
+	ReportItemType string
+	Description    string
+	Frequency      string
+}
+
+func (r *CreateReportItemChange) toCommandChange() (*change.CreateReportItemChange, error) {
+	rr := &change.CreateReportItemChange{
+		ReportItemType: models.ReportItemTypeFromString(r.ReportItemType),
+		Description:    r.Description,
+		Frequency:      models.ReportFrequencyFromString(r.Frequency),
+	}
+
+	circleID, err := unmarshalUID(r.CircleUID)
+	if err != nil {
+		return nil, err
+	}
+	rr.CircleID = circleID
+
+	roleID, err := unmarshalUID(r.RoleUID)
+	if err != nil {
+		return nil, err
+	}
+	rr.RoleID = roleID
+
+	return rr, nil
+}
+
+type UpdateReportItemChange struct {
+	UID         graphql.ID
+	RoleUID     graphql.ID
+	Description string
+}
+
+func (r *UpdateReportItemChange) toCommandChange() (*change.UpdateReportItemChange, error) {
+	rr := &change.UpdateReportItemChange{
+		Description: r.Description,
+	}
+
+	id, err := unmarshalUID(r.UID)
+	if err != nil {
+		return nil, err
+	}
+	rr.ID = id
+
+	roleID, err := unmarshalUID(r.RoleUID)
+	if err != nil {
+		return nil, err
+	}
+	rr.RoleID = roleID
+
+	return rr, nil
+}
+
+type UpdateProposalChange struct {
+	UID               graphql.ID
+	CreateRoleChanges *[]*CreateRoleChange
+	UpdateRoleChanges *[]*UpdateRoleChange
+	DeleteRoleChanges *[]*DeleteRoleChange
+}
+
+func (p *UpdateProposalChange) toCommandChange() (*change.UpdateProposalChange, error) {
+	mp := &change.UpdateProposalChange{}
+
+	id, err := unmarshalUID(p.UID)
+	if err != nil {
+		return nil, err
+	}
+	mp.ID = id
+
+	proposalChanges, err := proposalChangesToCommandChange(p.CreateRoleChanges, p.UpdateRoleChanges, p.DeleteRoleChanges)
+	if err != nil {
+		return nil, err
+	}
+	mp.ProposalChanges = *proposalChanges
+
+	return mp, nil
+}
+
 func getTimeLineNumber(ctx context.Context, readDB readdb.ReadDBService, v *util.TimeLineNumber) (util.TimeLineNumber, error) {
 	curTl := readDB.CurTimeLine(ctx)
 
@@ -1548,58 +2664,157 @@ func (r *Resolver) Tension(ctx context.Context, args *struct {
 	return &tensionResolver{s, tension, timeLineID, dataloader.NewDataLoaders(ctx, s)}, nil
 }
 
-func (r *Resolver) Members(ctx context.Context, args *struct {
+func (r *Resolver) Meeting(ctx context.Context, args *struct {
 	TimeLineID *util.TimeLineNumber
-	Search     *string
-	First      *float64
-	After      *string
-}) (*memberConnectionResolver, error) {
+	UID        graphql.ID
+}) (*meetingResolver, error) {
 	s, err := r.setupReadDB(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	// accept only a cursor or a timeline + search
-	if args.After != nil && (args.Search != nil || args.TimeLineID != nil) {
-		return nil, errors.New("only the cursor or the search and timeline can be provided")
-	}
-
-	var timeLineID util.TimeLineNumber
-	var search string
-	var fullName *string
-	if args.After != nil {
-		cursor, err := unmarshalMemberConnectionCursor(*args.After)
-		if err != nil {
-			return nil, err
-		}
-		timeLineID = cursor.TimeLineID
-		search = cursor.SearchString
-		fullName = &cursor.FullName
-
-	} else {
-		var err error
-		timeLineID, err = getTimeLineNumber(ctx, s, args.TimeLineID)
-		if err != nil {
-			return nil, err
-		}
-		if args.Search != nil && *args.Search != "" {
-			search = *args.Search
-		}
+	timeLineID, err := getTimeLineNumber(ctx, s, args.TimeLineID)
+	if err != nil {
+		return nil, err
 	}
-	first := 0
-	if args.First != nil {
-		first = int(*args.First)
+	// Get meeting id
+	id, err := unmarshalUID(args.UID)
+	if err != nil {
+		return nil, err
 	}
-
-	members, hasMoreData, err := s.Members(ctx, timeLineID, search, first, fullName)
+	meeting, err := s.Meeting(ctx, timeLineID, id)
 	if err != nil {
 		return nil, err
 	}
-
-	return &memberConnectionResolver{s, members, hasMoreData, timeLineID, dataloader.NewDataLoaders(ctx, s)}, nil
+	if meeting == nil {
+		return nil, nil
+	}
+	return &meetingResolver{s, meeting, timeLineID, dataloader.NewDataLoaders(ctx, s)}, nil
+}
+
+func (r *Resolver) Project(ctx context.Context, args *struct {
+	TimeLineID *util.TimeLineNumber
+	UID        graphql.ID
+}) (*projectResolver, error) {
+	s, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeLineID, err := getTimeLineNumber(ctx, s, args.TimeLineID)
+	if err != nil {
+		return nil, err
+	}
+	// Get project id
+	id, err := unmarshalUID(args.UID)
+	if err != nil {
+		return nil, err
+	}
+	project, err := s.Project(ctx, timeLineID, id)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, nil
+	}
+	return &projectResolver{s, project, timeLineID, dataloader.NewDataLoaders(ctx, s)}, nil
+}
+
+func (r *Resolver) ReportItem(ctx context.Context, args *struct {
+	TimeLineID *util.TimeLineNumber
+	UID        graphql.ID
+}) (*reportItemResolver, error) {
+	s, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeLineID, err := getTimeLineNumber(ctx, s, args.TimeLineID)
+	if err != nil {
+		return nil, err
+	}
+	// Get report item id
+	id, err := unmarshalUID(args.UID)
+	if err != nil {
+		return nil, err
+	}
+	reportItem, err := s.ReportItem(ctx, timeLineID, id)
+	if err != nil {
+		return nil, err
+	}
+	if reportItem == nil {
+		return nil, nil
+	}
+	return &reportItemResolver{s, reportItem, timeLineID, dataloader.NewDataLoaders(ctx, s)}, nil
+}
+
+func (r *Resolver) Members(ctx context.Context, args *struct {
+	TimeLineID *util.TimeLineNumber
+	Search     *string
+	Filter     *MemberFilter
+	OrderBy    *MemberOrder
+	First      *float64
+	After      *string
+}) (*memberConnectionResolver, error) {
+	s, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// accept only a cursor or a timeline + search + filter + order
+	if args.After != nil && (args.Search != nil || args.TimeLineID != nil || args.Filter != nil || args.OrderBy != nil) {
+		return nil, errors.New("only the cursor or the search, filter, order and timeline can be provided")
+	}
+
+	var timeLineID util.TimeLineNumber
+	var search string
+	var filter *readdb.MemberFilter
+	var order *readdb.MemberOrder
+	var after *util.ID
+	if args.After != nil {
+		cursor, err := unmarshalMemberConnectionCursor(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		timeLineID = cursor.TimeLineID
+		search = cursor.SearchString
+		filter = cursor.Filter
+		order = cursor.Order
+		after = &cursor.MemberID
+
+	} else {
+		var err error
+		timeLineID, err = getTimeLineNumber(ctx, s, args.TimeLineID)
+		if err != nil {
+			return nil, err
+		}
+		if args.Search != nil && *args.Search != "" {
+			search = *args.Search
+		}
+		filter, err = args.Filter.toReadDBFilter()
+		if err != nil {
+			return nil, err
+		}
+		order, err = args.OrderBy.toReadDBOrder()
+		if err != nil {
+			return nil, err
+		}
+	}
+	first := 0
+	if args.First != nil {
+		first = int(*args.First)
+	}
+
+	members, hasMoreData, err := s.Members(ctx, timeLineID, search, filter, order, first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	return &memberConnectionResolver{s, members, hasMoreData, timeLineID, &MemberConnectionCursor{TimeLineID: timeLineID, SearchString: search, Filter: filter, Order: order}, dataloader.NewDataLoaders(ctx, s)}, nil
 }
 
-func (r *Resolver) Roles(ctx context.Context, args *struct{ TimeLineID *util.TimeLineNumber }) (*[]*roleResolver, error) {
+func (r *Resolver) Roles(ctx context.Context, args *struct {
+	TimeLineID *util.TimeLineNumber
+	Filter     *RoleFilter
+	OrderBy    *RoleOrder
+}) (*[]*roleResolver, error) {
 	s, err := r.setupReadDB(ctx)
 	if err != nil {
 		return nil, err
@@ -1608,7 +2823,15 @@ func (r *Resolver) Roles(ctx context.Context, args *struct{ TimeLineID *util.Tim
 	if err != nil {
 		return nil, err
 	}
-	roles, err := s.Roles(ctx, timeLineID, nil)
+	filter, err := args.Filter.toReadDBFilter()
+	if err != nil {
+		return nil, err
+	}
+	order, err := args.OrderBy.toReadDBOrder()
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.FilteredRoles(ctx, timeLineID, filter, order)
 	if err != nil {
 		return nil, err
 	}
@@ -1619,9 +2842,46 @@ func (r *Resolver) Roles(ctx context.Context, args *struct{ TimeLineID *util.Tim
 	return &l, nil
 }
 
+func (r *Resolver) RolesConnection(ctx context.Context, args *struct {
+	TimeLineID *util.TimeLineNumber
+	Filter     *RoleFilter
+	OrderBy    *RoleOrder
+	First      *float64
+	After      *string
+	Last       *float64
+	Before     *string
+}) (*roleConnectionResolver, error) {
+	s, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeLineID, err := getTimeLineNumber(ctx, s, args.TimeLineID)
+	if err != nil {
+		return nil, err
+	}
+	page, err := (&pageArgs{args.First, args.After, args.Last, args.Before}).page()
+	if err != nil {
+		return nil, err
+	}
+	filter, err := args.Filter.toReadDBFilter()
+	if err != nil {
+		return nil, err
+	}
+	order, err := args.OrderBy.toReadDBOrder()
+	if err != nil {
+		return nil, err
+	}
+	rolesPage, err := s.RolesPage(ctx, timeLineID, filter, order, page)
+	if err != nil {
+		return nil, err
+	}
+	return &roleConnectionResolver{s, rolesPage, timeLineID, dataloader.NewDataLoaders(ctx, s)}, nil
+}
+
 // Mutations
 func (r *Resolver) UpdateRootRole(ctx context.Context, args *struct {
 	UpdateRootRoleChange *UpdateRootRoleChange
+	ExpectedTimeLineID   *util.TimeLineNumber
 }) (*updateRootRoleResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
@@ -1630,7 +2890,10 @@ func (r *Resolver) UpdateRootRole(ctx context.Context, args *struct {
 		return nil, err
 	}
 
-	res, groupID, err := cs.UpdateRootRole(ctx, urc)
+	res, groupID, err := cs.UpdateRootRole(ctx, urc, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "UpdateRootRole", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -1663,8 +2926,9 @@ func (r *Resolver) UpdateRootRole(ctx context.Context, args *struct {
 }
 
 func (r *Resolver) CircleCreateChildRole(ctx context.Context, args *struct {
-	RoleUID          graphql.ID
-	CreateRoleChange *CreateRoleChange
+	RoleUID            graphql.ID
+	CreateRoleChange   *CreateRoleChange
+	ExpectedTimeLineID *util.TimeLineNumber
 }) (*createRoleResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
@@ -1678,7 +2942,10 @@ func (r *Resolver) CircleCreateChildRole(ctx context.Context, args *struct {
 		return nil, err
 	}
 
-	res, groupID, err := cs.CircleCreateChildRole(ctx, roleID, crc)
+	res, groupID, err := cs.CircleCreateChildRole(ctx, roleID, crc, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CircleCreateChildRole", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -1711,8 +2978,9 @@ func (r *Resolver) CircleCreateChildRole(ctx context.Context, args *struct {
 }
 
 func (r *Resolver) CircleUpdateChildRole(ctx context.Context, args *struct {
-	RoleUID          graphql.ID
-	UpdateRoleChange *UpdateRoleChange
+	RoleUID            graphql.ID
+	UpdateRoleChange   *UpdateRoleChange
+	ExpectedTimeLineID *util.TimeLineNumber
 }) (*updateRoleResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
@@ -1726,7 +2994,10 @@ func (r *Resolver) CircleUpdateChildRole(ctx context.Context, args *struct {
 		return nil, err
 	}
 
-	res, groupID, err := cs.CircleUpdateChildRole(ctx, roleID, urc)
+	res, groupID, err := cs.CircleUpdateChildRole(ctx, roleID, urc, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CircleUpdateChildRole", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -1759,8 +3030,9 @@ func (r *Resolver) CircleUpdateChildRole(ctx context.Context, args *struct {
 }
 
 func (r *Resolver) CircleDeleteChildRole(ctx context.Context, args *struct {
-	RoleUID          graphql.ID
-	DeleteRoleChange *DeleteRoleChange
+	RoleUID            graphql.ID
+	DeleteRoleChange   *DeleteRoleChange
+	ExpectedTimeLineID *util.TimeLineNumber
 }) (*deleteRoleResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
@@ -1774,7 +3046,10 @@ func (r *Resolver) CircleDeleteChildRole(ctx context.Context, args *struct {
 		return nil, err
 	}
 
-	res, groupID, err := cs.CircleDeleteChildRole(ctx, roleID, drc)
+	res, groupID, err := cs.CircleDeleteChildRole(ctx, roleID, drc, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CircleDeleteChildRole", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -1800,8 +3075,9 @@ func (r *Resolver) CircleDeleteChildRole(ctx context.Context, args *struct {
 }
 
 func (r *Resolver) SetRoleAdditionalContent(ctx context.Context, args *struct {
-	RoleUID graphql.ID
-	Content string
+	RoleUID            graphql.ID
+	Content            string
+	ExpectedTimeLineID *util.TimeLineNumber
 }) (*setRoleAdditionalContentResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
@@ -1811,7 +3087,10 @@ func (r *Resolver) SetRoleAdditionalContent(ctx context.Context, args *struct {
 		return nil, err
 	}
 
-	res, groupID, err := cs.SetRoleAdditionalContent(ctx, roleID, args.Content)
+	res, groupID, err := cs.SetRoleAdditionalContent(ctx, roleID, args.Content, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "SetRoleAdditionalContent", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -1862,6 +3141,9 @@ func (r *Resolver) CreateMember(ctx context.Context, args *struct {
 	}
 
 	res, groupID, err := cs.CreateMember(ctx, mr)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CreateMember", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -1910,6 +3192,9 @@ func (r *Resolver) UpdateMember(ctx context.Context, args *struct {
 	}
 
 	res, groupID, err := cs.UpdateMember(ctx, mr)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "UpdateMember", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -1959,6 +3244,9 @@ func (r *Resolver) SetMemberPassword(ctx context.Context, args *struct {
 	}
 
 	res, groupID, err := cs.SetMemberPassword(ctx, memberID, curPassword, args.NewPassword)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "SetMemberPassword", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -1985,6 +3273,65 @@ func (r *Resolver) SetMemberMatchUID(ctx context.Context, args *struct {
 	}
 
 	res, groupID, err := cs.SetMemberMatchUID(ctx, memberID, args.MatchUID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "SetMemberMatchUID", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) DeleteMember(ctx context.Context, args *struct {
+	MemberUID graphql.ID
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	memberID, err := unmarshalUID(args.MemberUID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.DeleteMember(ctx, memberID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "DeleteMember", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) ForgetMember(ctx context.Context, args *struct {
+	MemberUID graphql.ID
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	memberID, err := unmarshalUID(args.MemberUID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.ForgetMember(ctx, memberID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "ForgetMember", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -2056,6 +3403,9 @@ func (r *Resolver) CreateTension(ctx context.Context, args *struct {
 	}
 
 	res, groupID, err := cs.CreateTension(ctx, mr)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CreateTension", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -2089,6 +3439,7 @@ func (r *Resolver) CreateTension(ctx context.Context, args *struct {
 
 func (r *Resolver) UpdateTension(ctx context.Context, args *struct {
 	UpdateTensionChange *UpdateTensionChange
+	ExpectedTimeLineID  *util.TimeLineNumber
 }) (*updateTensionResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
@@ -2097,7 +3448,10 @@ func (r *Resolver) UpdateTension(ctx context.Context, args *struct {
 		return nil, err
 	}
 
-	res, groupID, err := cs.UpdateTension(ctx, mr)
+	res, groupID, err := cs.UpdateTension(ctx, mr, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "UpdateTension", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -2131,6 +3485,7 @@ func (r *Resolver) UpdateTension(ctx context.Context, args *struct {
 
 func (r *Resolver) CloseTension(ctx context.Context, args *struct {
 	CloseTensionChange *CloseTensionChange
+	ExpectedTimeLineID *util.TimeLineNumber
 }) (*closeTensionResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
@@ -2139,7 +3494,10 @@ func (r *Resolver) CloseTension(ctx context.Context, args *struct {
 		return nil, err
 	}
 
-	res, groupID, err := cs.CloseTension(ctx, mr)
+	res, groupID, err := cs.CloseTension(ctx, mr, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CloseTension", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -2164,21 +3522,21 @@ func (r *Resolver) CloseTension(ctx context.Context, args *struct {
 	return &closeTensionResultResolver{readdb, res, tl.Number(), dataloader.NewDataLoaders(ctx, readdb)}, nil
 }
 
-func (r *Resolver) CircleSetLeadLinkMember(ctx context.Context, args *struct {
-	RoleUID   graphql.ID
-	MemberUID graphql.ID
+func (r *Resolver) ReopenTension(ctx context.Context, args *struct {
+	TensionUID graphql.ID
 }) (*genericResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
-	roleUID, err := unmarshalUID(args.RoleUID)
+
+	tensionID, err := unmarshalUID(args.TensionUID)
 	if err != nil {
 		return nil, err
 	}
-	memberUID, err := unmarshalUID(args.MemberUID)
-	if err != nil {
-		return nil, err
+
+	res, groupID, err := cs.ReopenTension(ctx, tensionID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "ReopenTension", res.GenericError)
 	}
-	res, groupID, err := cs.CircleSetLeadLinkMember(ctx, roleUID, memberUID)
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -2192,16 +3550,22 @@ func (r *Resolver) CircleSetLeadLinkMember(ctx context.Context, args *struct {
 	return &genericResultResolver{res}, nil
 }
 
-func (r *Resolver) CircleUnsetLeadLinkMember(ctx context.Context, args *struct {
-	RoleUID graphql.ID
+func (r *Resolver) ChangeTensionState(ctx context.Context, args *struct {
+	TensionUID graphql.ID
+	State      string
 }) (*genericResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
-	roleUID, err := unmarshalUID(args.RoleUID)
+
+	tensionID, err := unmarshalUID(args.TensionUID)
 	if err != nil {
 		return nil, err
 	}
-	res, groupID, err := cs.CircleUnsetLeadLinkMember(ctx, roleUID)
+
+	res, groupID, err := cs.ChangeTensionState(ctx, tensionID, models.TensionStateFromString(args.State))
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "ChangeTensionState", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -2215,52 +3579,867 @@ func (r *Resolver) CircleUnsetLeadLinkMember(ctx context.Context, args *struct {
 	return &genericResultResolver{res}, nil
 }
 
-func (r *Resolver) CircleSetCoreRoleMember(ctx context.Context, args *struct {
-	RoleType           string
-	RoleUID            graphql.ID
-	MemberUID          graphql.ID
-	ElectionExpiration *graphql.Time
-}) (*genericResultResolver, error) {
+func (r *Resolver) AddTensionComment(ctx context.Context, args *struct {
+	AddTensionCommentChange *AddTensionCommentChange
+}) (*addTensionCommentResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
-	roleUID, err := unmarshalUID(args.RoleUID)
-	if err != nil {
-		return nil, err
-	}
-	memberUID, err := unmarshalUID(args.MemberUID)
+	mt, err := args.AddTensionCommentChange.toCommandChange()
 	if err != nil {
 		return nil, err
 	}
-	var time *time.Time
-	if args.ElectionExpiration != nil {
-		time = &args.ElectionExpiration.Time
+
+	res, groupID, err := cs.AddTensionComment(ctx, mt)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "AddTensionComment", res.GenericError)
 	}
-	res, groupID, err := cs.CircleSetCoreRoleMember(ctx, models.RoleTypeFromString(args.RoleType), roleUID, memberUID, time)
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
-
-	if err != command.ErrValidation {
-		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
-			return nil, err
-		}
+	if err == command.ErrValidation {
+		return &addTensionCommentResultResolver{nil, nil, res, -1, nil}, nil
 	}
 
-	return &genericResultResolver{res}, nil
-}
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		return nil, err
+	}
 
-func (r *Resolver) CircleUnsetCoreRoleMember(ctx context.Context, args *struct {
-	RoleType string
-	RoleUID  graphql.ID
-}) (*genericResultResolver, error) {
-	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
-	cs := ctx.Value("commandservice").(*command.CommandService)
-	roleUID, err := unmarshalUID(args.RoleUID)
+	readdb, err := r.setupReadDB(ctx)
 	if err != nil {
 		return nil, err
 	}
-	res, groupID, err := cs.CircleUnsetCoreRoleMember(ctx, models.RoleTypeFromString(args.RoleType), roleUID)
-	if err != nil && err != command.ErrValidation {
+
+	tl, err := readdb.TimeLineForGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	tension, err := readdb.Tension(ctx, tl.Number(), mt.TensionID)
+	if err != nil {
+		return nil, err
+	}
+	return &addTensionCommentResultResolver{readdb, tension, res, tl.Number(), dataloader.NewDataLoaders(ctx, readdb)}, nil
+}
+
+func (r *Resolver) AssignTension(ctx context.Context, args *struct {
+	TensionUID graphql.ID
+	RoleUID    *graphql.ID
+	MemberUID  *graphql.ID
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	c := &change.AssignTensionChange{}
+	tensionID, err := unmarshalUID(args.TensionUID)
+	if err != nil {
+		return nil, err
+	}
+	c.TensionID = tensionID
+	if args.RoleUID != nil {
+		roleID, err := unmarshalUID(*args.RoleUID)
+		if err != nil {
+			return nil, err
+		}
+		c.RoleID = &roleID
+	}
+	if args.MemberUID != nil {
+		memberID, err := unmarshalUID(*args.MemberUID)
+		if err != nil {
+			return nil, err
+		}
+		c.MemberID = &memberID
+	}
+
+	res, groupID, err := cs.AssignTension(ctx, c)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "AssignTension", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) SetTensionLabels(ctx context.Context, args *struct {
+	TensionUID graphql.ID
+	Labels     []string
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	tensionID, err := unmarshalUID(args.TensionUID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.SetTensionLabels(ctx, tensionID, args.Labels)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "SetTensionLabels", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) CreateProposal(ctx context.Context, args *struct {
+	CreateProposalChange *CreateProposalChange
+}) (*createProposalResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	mp, err := args.CreateProposalChange.toCommandChange()
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.CreateProposal(ctx, mp)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CreateProposal", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+	if err == command.ErrValidation {
+		return &createProposalResultResolver{nil, nil, res, -1, nil}, nil
+	}
+
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	readdb, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tl, err := readdb.TimeLineForGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var proposal *models.Proposal
+	if res.ProposalID != nil {
+		proposal, err = readdb.Proposal(ctx, tl.Number(), *res.ProposalID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &createProposalResultResolver{readdb, proposal, res, tl.Number(), dataloader.NewDataLoaders(ctx, readdb)}, nil
+}
+
+func (r *Resolver) UpdateProposal(ctx context.Context, args *struct {
+	UpdateProposalChange *UpdateProposalChange
+}) (*updateProposalResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	mp, err := args.UpdateProposalChange.toCommandChange()
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.UpdateProposal(ctx, mp)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "UpdateProposal", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+	if err == command.ErrValidation {
+		return &updateProposalResultResolver{nil, nil, res, -1, nil}, nil
+	}
+
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	readdb, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tl, err := readdb.TimeLineForGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal, err := readdb.Proposal(ctx, tl.Number(), mp.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &updateProposalResultResolver{readdb, proposal, res, tl.Number(), dataloader.NewDataLoaders(ctx, readdb)}, nil
+}
+
+func (r *Resolver) ObjectProposal(ctx context.Context, args *struct {
+	ProposalUID graphql.ID
+	Reason      string
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	proposalID, err := unmarshalUID(args.ProposalUID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.ObjectProposal(ctx, &change.ObjectProposalChange{ID: proposalID, Reason: args.Reason})
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "ObjectProposal", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) AcceptProposal(ctx context.Context, args *struct {
+	ProposalUID graphql.ID
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	proposalID, err := unmarshalUID(args.ProposalUID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.AcceptProposal(ctx, &change.AcceptProposalChange{ID: proposalID})
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "AcceptProposal", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	// a proposal that couldn't be applied has its acceptance reverted, wait
+	// for it also when there're validation errors
+	if err != command.ErrValidation || groupID != util.NilID {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) CreateMeeting(ctx context.Context, args *struct {
+	CreateMeetingChange *CreateMeetingChange
+}) (*createMeetingResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	mm, err := args.CreateMeetingChange.toCommandChange()
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.CreateMeeting(ctx, mm)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CreateMeeting", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+	if err == command.ErrValidation {
+		return &createMeetingResultResolver{nil, nil, res, -1, nil}, nil
+	}
+
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	readdb, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tl, err := readdb.TimeLineForGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var meeting *models.Meeting
+	if res.MeetingID != nil {
+		meeting, err = readdb.Meeting(ctx, tl.Number(), *res.MeetingID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &createMeetingResultResolver{readdb, meeting, res, tl.Number(), dataloader.NewDataLoaders(ctx, readdb)}, nil
+}
+
+func (r *Resolver) AddMeetingAgendaItem(ctx context.Context, args *struct {
+	AddMeetingAgendaItemChange *AddMeetingAgendaItemChange
+}) (*addMeetingAgendaItemResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	mm, err := args.AddMeetingAgendaItemChange.toCommandChange()
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.AddMeetingAgendaItem(ctx, mm)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "AddMeetingAgendaItem", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+	if err == command.ErrValidation {
+		return &addMeetingAgendaItemResultResolver{nil, nil, res, -1, nil}, nil
+	}
+
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	readdb, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tl, err := readdb.TimeLineForGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	meeting, err := readdb.Meeting(ctx, tl.Number(), mm.MeetingID)
+	if err != nil {
+		return nil, err
+	}
+	return &addMeetingAgendaItemResultResolver{readdb, meeting, res, tl.Number(), dataloader.NewDataLoaders(ctx, readdb)}, nil
+}
+
+func (r *Resolver) SetMeetingAgendaItemOutcome(ctx context.Context, args *struct {
+	MeetingUID    graphql.ID
+	AgendaItemUID graphql.ID
+	Outcome       string
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	meetingID, err := unmarshalUID(args.MeetingUID)
+	if err != nil {
+		return nil, err
+	}
+	agendaItemID, err := unmarshalUID(args.AgendaItemUID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.SetMeetingAgendaItemOutcome(ctx, &change.SetMeetingAgendaItemOutcomeChange{MeetingID: meetingID, AgendaItemID: agendaItemID, Outcome: args.Outcome})
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "SetMeetingAgendaItemOutcome", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) CloseMeeting(ctx context.Context, args *struct {
+	MeetingUID graphql.ID
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	meetingID, err := unmarshalUID(args.MeetingUID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.CloseMeeting(ctx, meetingID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CloseMeeting", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) CreateProject(ctx context.Context, args *struct {
+	CreateProjectChange *CreateProjectChange
+}) (*createProjectResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	pp, err := args.CreateProjectChange.toCommandChange()
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.CreateProject(ctx, pp)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CreateProject", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+	if err == command.ErrValidation {
+		return &createProjectResultResolver{nil, nil, res, -1, nil}, nil
+	}
+
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	readdb, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tl, err := readdb.TimeLineForGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var project *models.Project
+	if res.ProjectID != nil {
+		project, err = readdb.Project(ctx, tl.Number(), *res.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &createProjectResultResolver{readdb, project, res, tl.Number(), dataloader.NewDataLoaders(ctx, readdb)}, nil
+}
+
+func (r *Resolver) UpdateProject(ctx context.Context, args *struct {
+	UpdateProjectChange *UpdateProjectChange
+}) (*updateProjectResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	pp, err := args.UpdateProjectChange.toCommandChange()
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.UpdateProject(ctx, pp)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "UpdateProject", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+	if err == command.ErrValidation {
+		return &updateProjectResultResolver{nil, nil, res, -1, nil}, nil
+	}
+
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	readdb, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tl, err := readdb.TimeLineForGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := readdb.Project(ctx, tl.Number(), pp.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &updateProjectResultResolver{readdb, project, res, tl.Number(), dataloader.NewDataLoaders(ctx, readdb)}, nil
+}
+
+func (r *Resolver) ChangeProjectStatus(ctx context.Context, args *struct {
+	ProjectUID graphql.ID
+	Status     string
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	projectID, err := unmarshalUID(args.ProjectUID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.ChangeProjectStatus(ctx, projectID, models.ProjectStatusFromString(args.Status))
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "ChangeProjectStatus", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) AddProjectNextAction(ctx context.Context, args *struct {
+	AddProjectNextActionChange *AddProjectNextActionChange
+}) (*addProjectNextActionResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	pp, err := args.AddProjectNextActionChange.toCommandChange()
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.AddProjectNextAction(ctx, pp)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "AddProjectNextAction", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+	if err == command.ErrValidation {
+		return &addProjectNextActionResultResolver{nil, nil, res, -1, nil}, nil
+	}
+
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	readdb, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tl, err := readdb.TimeLineForGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := readdb.Project(ctx, tl.Number(), pp.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	return &addProjectNextActionResultResolver{readdb, project, res, tl.Number(), dataloader.NewDataLoaders(ctx, readdb)}, nil
+}
+
+func (r *Resolver) CompleteProjectNextAction(ctx context.Context, args *struct {
+	ProjectUID    graphql.ID
+	NextActionUID graphql.ID
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	projectID, err := unmarshalUID(args.ProjectUID)
+	if err != nil {
+		return nil, err
+	}
+	nextActionID, err := unmarshalUID(args.NextActionUID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.CompleteProjectNextAction(ctx, projectID, nextActionID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CompleteProjectNextAction", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) CreateReportItem(ctx context.Context, args *struct {
+	CreateReportItemChange *CreateReportItemChange
+}) (*createReportItemResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	rr, err := args.CreateReportItemChange.toCommandChange()
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.CreateReportItem(ctx, rr)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CreateReportItem", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+	if err == command.ErrValidation {
+		return &createReportItemResultResolver{nil, nil, res, -1, nil}, nil
+	}
+
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	readdb, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tl, err := readdb.TimeLineForGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reportItem *models.ReportItem
+	if res.ReportItemID != nil {
+		reportItem, err = readdb.ReportItem(ctx, tl.Number(), *res.ReportItemID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &createReportItemResultResolver{readdb, reportItem, res, tl.Number(), dataloader.NewDataLoaders(ctx, readdb)}, nil
+}
+
+func (r *Resolver) UpdateReportItem(ctx context.Context, args *struct {
+	UpdateReportItemChange *UpdateReportItemChange
+}) (*updateReportItemResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	rr, err := args.UpdateReportItemChange.toCommandChange()
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.UpdateReportItem(ctx, rr)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "UpdateReportItem", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+	if err == command.ErrValidation {
+		return &updateReportItemResultResolver{nil, nil, res, -1, nil}, nil
+	}
+
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		return nil, err
+	}
+
+	readdb, err := r.setupReadDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tl, err := readdb.TimeLineForGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	reportItem, err := readdb.ReportItem(ctx, tl.Number(), rr.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &updateReportItemResultResolver{readdb, reportItem, res, tl.Number(), dataloader.NewDataLoaders(ctx, readdb)}, nil
+}
+
+func (r *Resolver) DeleteReportItem(ctx context.Context, args *struct {
+	ReportItemUID graphql.ID
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	reportItemID, err := unmarshalUID(args.ReportItemUID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.DeleteReportItem(ctx, reportItemID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "DeleteReportItem", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) SetReportItemValue(ctx context.Context, args *struct {
+	ReportItemUID graphql.ID
+	Period        graphql.Time
+	Value         float64
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+
+	reportItemID, err := unmarshalUID(args.ReportItemUID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, groupID, err := cs.SetReportItemValue(ctx, &change.SetReportItemValueChange{ReportItemID: reportItemID, Period: args.Period.Time, Value: args.Value})
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "SetReportItemValue", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) CircleSetLeadLinkMember(ctx context.Context, args *struct {
+	RoleUID            graphql.ID
+	MemberUID          graphql.ID
+	ExpectedTimeLineID *util.TimeLineNumber
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	roleUID, err := unmarshalUID(args.RoleUID)
+	if err != nil {
+		return nil, err
+	}
+	memberUID, err := unmarshalUID(args.MemberUID)
+	if err != nil {
+		return nil, err
+	}
+	res, groupID, err := cs.CircleSetLeadLinkMember(ctx, roleUID, memberUID, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CircleSetLeadLinkMember", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) CircleUnsetLeadLinkMember(ctx context.Context, args *struct {
+	RoleUID            graphql.ID
+	ExpectedTimeLineID *util.TimeLineNumber
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	roleUID, err := unmarshalUID(args.RoleUID)
+	if err != nil {
+		return nil, err
+	}
+	res, groupID, err := cs.CircleUnsetLeadLinkMember(ctx, roleUID, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CircleUnsetLeadLinkMember", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) CircleSetCoreRoleMember(ctx context.Context, args *struct {
+	RoleType           string
+	RoleUID            graphql.ID
+	MemberUID          graphql.ID
+	ElectionExpiration *graphql.Time
+	ExpectedTimeLineID *util.TimeLineNumber
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	roleUID, err := unmarshalUID(args.RoleUID)
+	if err != nil {
+		return nil, err
+	}
+	memberUID, err := unmarshalUID(args.MemberUID)
+	if err != nil {
+		return nil, err
+	}
+	var time *time.Time
+	if args.ElectionExpiration != nil {
+		time = &args.ElectionExpiration.Time
+	}
+	res, groupID, err := cs.CircleSetCoreRoleMember(ctx, models.RoleTypeFromString(args.RoleType), roleUID, memberUID, time, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CircleSetCoreRoleMember", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
+		return nil, err
+	}
+
+	if err != command.ErrValidation {
+		if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &genericResultResolver{res}, nil
+}
+
+func (r *Resolver) CircleUnsetCoreRoleMember(ctx context.Context, args *struct {
+	RoleType           string
+	RoleUID            graphql.ID
+	ExpectedTimeLineID *util.TimeLineNumber
+}) (*genericResultResolver, error) {
+	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
+	cs := ctx.Value("commandservice").(*command.CommandService)
+	roleUID, err := unmarshalUID(args.RoleUID)
+	if err != nil {
+		return nil, err
+	}
+	res, groupID, err := cs.CircleUnsetCoreRoleMember(ctx, models.RoleTypeFromString(args.RoleType), roleUID, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CircleUnsetCoreRoleMember", res.GenericError)
+	}
+	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
 
@@ -2274,8 +4453,9 @@ func (r *Resolver) CircleUnsetCoreRoleMember(ctx context.Context, args *struct {
 }
 
 func (r *Resolver) CircleAddDirectMember(ctx context.Context, args *struct {
-	RoleUID   graphql.ID
-	MemberUID graphql.ID
+	RoleUID            graphql.ID
+	MemberUID          graphql.ID
+	ExpectedTimeLineID *util.TimeLineNumber
 }) (*genericResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
@@ -2287,7 +4467,10 @@ func (r *Resolver) CircleAddDirectMember(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
-	res, groupID, err := cs.CircleAddDirectMember(ctx, roleUID, memberUID)
+	res, groupID, err := cs.CircleAddDirectMember(ctx, roleUID, memberUID, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CircleAddDirectMember", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -2302,8 +4485,9 @@ func (r *Resolver) CircleAddDirectMember(ctx context.Context, args *struct {
 }
 
 func (r *Resolver) CircleRemoveDirectMember(ctx context.Context, args *struct {
-	RoleUID   graphql.ID
-	MemberUID graphql.ID
+	RoleUID            graphql.ID
+	MemberUID          graphql.ID
+	ExpectedTimeLineID *util.TimeLineNumber
 }) (*genericResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
@@ -2315,7 +4499,10 @@ func (r *Resolver) CircleRemoveDirectMember(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
-	res, groupID, err := cs.CircleRemoveDirectMember(ctx, roleUID, memberUID)
+	res, groupID, err := cs.CircleRemoveDirectMember(ctx, roleUID, memberUID, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "CircleRemoveDirectMember", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -2330,10 +4517,11 @@ func (r *Resolver) CircleRemoveDirectMember(ctx context.Context, args *struct {
 }
 
 func (r *Resolver) RoleAddMember(ctx context.Context, args *struct {
-	RoleUID      graphql.ID
-	MemberUID    graphql.ID
-	Focus        *string
-	NoCoreMember bool
+	RoleUID            graphql.ID
+	MemberUID          graphql.ID
+	Focus              *string
+	NoCoreMember       bool
+	ExpectedTimeLineID *util.TimeLineNumber
 }) (*genericResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
@@ -2345,7 +4533,10 @@ func (r *Resolver) RoleAddMember(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
-	res, groupID, err := cs.RoleAddMember(ctx, roleUID, memberUID, args.Focus, args.NoCoreMember)
+	res, groupID, err := cs.RoleAddMember(ctx, roleUID, memberUID, args.Focus, args.NoCoreMember, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "RoleAddMember", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -2360,8 +4551,9 @@ func (r *Resolver) RoleAddMember(ctx context.Context, args *struct {
 }
 
 func (r *Resolver) RoleRemoveMember(ctx context.Context, args *struct {
-	RoleUID   graphql.ID
-	MemberUID graphql.ID
+	RoleUID            graphql.ID
+	MemberUID          graphql.ID
+	ExpectedTimeLineID *util.TimeLineNumber
 }) (*genericResultResolver, error) {
 	readDBListener := ctx.Value("readdblistener").(readdb.ReadDBListener)
 	cs := ctx.Value("commandservice").(*command.CommandService)
@@ -2373,7 +4565,10 @@ func (r *Resolver) RoleRemoveMember(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
-	res, groupID, err := cs.RoleRemoveMember(ctx, roleUID, memberUID)
+	res, groupID, err := cs.RoleRemoveMember(ctx, roleUID, memberUID, args.ExpectedTimeLineID)
+	if err == command.ErrValidation {
+		cs.AuditRejected(ctx, "RoleRemoveMember", res.GenericError)
+	}
 	if err != nil && err != command.ErrValidation {
 		return nil, err
 	}
@@ -2415,6 +4610,10 @@ func (r *genericResultResolver) GenericError() *string {
 	return errorToStringP(r.res.GenericError)
 }
 
+func (r *genericResultResolver) Conflict() bool {
+	return isConflictError(r.res.GenericError)
+}
+
 func (r *Resolver) setupReadDB(ctx context.Context) (readdb.ReadDBService, error) {
 	utx := ctx.Value("utx").(*db.Tx)
 	config := ctx.Value("config").(*config.Config)
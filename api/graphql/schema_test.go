@@ -20,6 +20,7 @@ import (
 	"github.com/sorintlab/sircles/config"
 	"github.com/sorintlab/sircles/db"
 	"github.com/sorintlab/sircles/eventhandler"
+	ep "github.com/sorintlab/sircles/events"
 	"github.com/sorintlab/sircles/eventstore"
 	ln "github.com/sorintlab/sircles/listennotify"
 	"github.com/sorintlab/sircles/lock"
@@ -346,7 +347,7 @@ func initBasic(ctx context.Context, t *testing.T, rootRoleID util.ID, readDBList
 			Name:     name,
 		}
 		log.Debugf("create root role child %v", rc)
-		r, groupID, err := commandService.CircleCreateChildRole(ctx, rootRoleID, rc)
+		r, groupID, err := commandService.CircleCreateChildRole(ctx, rootRoleID, rc, nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -364,7 +365,7 @@ func initBasic(ctx context.Context, t *testing.T, rootRoleID util.ID, readDBList
 			Name:     name,
 		}
 		log.Debugf("create root role circle %v", rc)
-		rres, groupID, err := commandService.CircleCreateChildRole(ctx, rootRoleID, rc)
+		rres, groupID, err := commandService.CircleCreateChildRole(ctx, rootRoleID, rc, nil)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -381,7 +382,7 @@ func initBasic(ctx context.Context, t *testing.T, rootRoleID util.ID, readDBList
 				Name:     name,
 			}
 			log.Debugf("create chile role sub role %v", rc)
-			r, groupID, err := commandService.CircleCreateChildRole(ctx, *rres.RoleID, rc)
+			r, groupID, err := commandService.CircleCreateChildRole(ctx, *rres.RoleID, rc, nil)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -402,35 +403,35 @@ func initBasic(ctx context.Context, t *testing.T, rootRoleID util.ID, readDBList
 
 	var groupID util.ID
 	var err error
-	if _, groupID, err = commandService.CircleSetLeadLinkMember(ctx, circlesIDs["rootRole-circle01"], membersIDs["user02"]); err != nil {
+	if _, groupID, err = commandService.CircleSetLeadLinkMember(ctx, circlesIDs["rootRole-circle01"], membersIDs["user02"], nil); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if _, groupID, err = commandService.CircleAddDirectMember(ctx, circlesIDs["rootRole-circle01"], membersIDs["user05"]); err != nil {
+	if _, groupID, err = commandService.CircleAddDirectMember(ctx, circlesIDs["rootRole-circle01"], membersIDs["user05"], nil); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if _, groupID, err = commandService.CircleSetLeadLinkMember(ctx, circlesIDs["rootRole-circle02"], membersIDs["user03"]); err != nil {
+	if _, groupID, err = commandService.CircleSetLeadLinkMember(ctx, circlesIDs["rootRole-circle02"], membersIDs["user03"], nil); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if _, groupID, err = commandService.CircleSetCoreRoleMember(ctx, models.RoleTypeSecretary, circlesIDs["rootRole-circle02"], membersIDs["user03"], nil); err != nil {
+	if _, groupID, err = commandService.CircleSetCoreRoleMember(ctx, models.RoleTypeSecretary, circlesIDs["rootRole-circle02"], membersIDs["user03"], nil, nil); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if _, groupID, err = commandService.CircleSetCoreRoleMember(ctx, models.RoleTypeRepLink, circlesIDs["rootRole-circle03"], membersIDs["user04"], nil); err != nil {
+	if _, groupID, err = commandService.CircleSetCoreRoleMember(ctx, models.RoleTypeRepLink, circlesIDs["rootRole-circle03"], membersIDs["user04"], nil, nil); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
@@ -578,11 +579,12 @@ func runTests(t *testing.T, initFunc initFunc, tests []*Test, parallel, skip boo
 	defer readDB.Close()
 	defer esDB.Close()
 
-	es := eventstore.NewEventStore(esDB, esDBNf)
+	es := eventstore.NewSQLEventStore(esDB, esDBNf)
 	es.SetTimeGenerator(timeGenerator)
+	es.SetDataCodec(ep.NewPersonalDataCodec(es))
 
 	readDBh := readdb.NewDBEventHandler(readDB, es, readDBNf)
-	mrh := eventhandler.NewMemberRequestHandler(es, uidGenerator)
+	mrh := eventhandler.NewMemberRequestHandler(tmpDir, es, uidGenerator)
 	drth, err := eventhandler.NewDeletedRoleTensionHandler(tmpDir, es, uidGenerator)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -614,7 +616,7 @@ func runTests(t *testing.T, initFunc initFunc, tests []*Test, parallel, skip boo
 		t.Fatal(err)
 	}
 
-	commandService := command.NewCommandService(tmpDir, readDB, es, uidGenerator, esDBLf, false)
+	commandService := command.NewCommandService(tmpDir, readDB, es, uidGenerator, esDBLf, false, false)
 
 	rootRoleID, groupID, err := commandService.SetupRootRole()
 	if err != nil {
@@ -666,7 +668,7 @@ func runTests(t *testing.T, initFunc initFunc, tests []*Test, parallel, skip boo
 	}
 
 	readDBh = readdb.NewDBEventHandler(readDB, es, readDBNf)
-	mrh = eventhandler.NewMemberRequestHandler(es, uidGenerator)
+	mrh = eventhandler.NewMemberRequestHandler(tmpDir, es, uidGenerator)
 	drth, err = eventhandler.NewDeletedRoleTensionHandler(tmpDir, es, uidGenerator)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -795,7 +797,7 @@ func runTests(t *testing.T, initFunc initFunc, tests []*Test, parallel, skip boo
 	}
 }
 
-func RunTest(ctx context.Context, t *testing.T, tmpDir string, schema *graphql.Schema, db *db.DB, readDBListener readdb.ReadDBListener, es *eventstore.EventStore, uidGenerator common.UIDGenerator, esDBLf ln.ListenerFactory, test *Test) *graphql.Response {
+func RunTest(ctx context.Context, t *testing.T, tmpDir string, schema *graphql.Schema, db *db.DB, readDBListener readdb.ReadDBListener, es eventstore.EventStore, uidGenerator common.UIDGenerator, esDBLf ln.ListenerFactory, test *Test) *graphql.Response {
 	var variables map[string]interface{}
 	if len(test.Variables) > 0 {
 		if err := json.Unmarshal([]byte(test.Variables), &variables); err != nil {
@@ -805,7 +807,7 @@ func RunTest(ctx context.Context, t *testing.T, tmpDir string, schema *graphql.S
 
 	time.Sleep(test.StartSleep)
 
-	commandService := command.NewCommandService(tmpDir, db, es, uidGenerator, esDBLf, false)
+	commandService := command.NewCommandService(tmpDir, db, es, uidGenerator, esDBLf, false, false)
 
 	utx := db.NewUnstartedTx()
 	defer utx.Rollback()
@@ -3696,3 +3698,3844 @@ func TestUnsetCircleChangedToNormalRoleFromTension(t *testing.T) {
 		},
 	})
 }
+
+func TestDeleteMember(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		// Delete user02 (lead link of rootRole-circle01 and owner of tension01)
+		{
+			Query: `
+			mutation DeleteMember($memberUID: ID!) {
+				deleteMember(memberUID: $memberUID) {
+					hasErrors
+				}
+			}
+			`,
+			Variables: `
+			{
+				"memberUID": "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"deleteMember": {
+					"hasErrors": false
+				}
+			}
+			`,
+		},
+		// Check that the member doesn't exist anymore
+		{
+			Query: `
+			query memberQuery($memberUID: ID!){
+				member(uid: $memberUID) {
+					uid
+					userName
+				}
+			}
+			`,
+			Variables: `
+			{
+				"memberUID": "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"member": null
+			}
+			`,
+		},
+		// Check that the member has been removed from rootRole-circle01 and
+		// its tension has been closed
+		{
+			Query: `
+			query roleQuery($roleUID: ID!){
+				role(uid: $roleUID) {
+					name
+					circleMembers {
+						member {
+							userName
+						}
+					}
+					tensions {
+						title
+						closed
+						closeReason
+						member {
+							userName
+						}
+					}
+				}
+			}
+			`,
+			Variables: `
+			{
+				"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"role": {
+					"name": "rootRole-circle01",
+					"circleMembers": [
+						{
+							"member": {
+								"userName": "user05"
+							}
+						}
+					],
+					"tensions": [
+						{
+							"title": "tension01",
+							"closed": true,
+							"closeReason": "member deleted",
+							"member": null
+						}
+					]
+				}
+			}
+			`,
+		},
+		// Delete an already deleted member
+		{
+			Query: `
+			mutation DeleteMember($memberUID: ID!) {
+				deleteMember(memberUID: $memberUID) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"memberUID": "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"deleteMember": {
+					"hasErrors": true,
+					"genericError": "member with id 18724eb3-ccc9-5c96-b0b7-91dcf95bacbf doesn't exist"
+				}
+			}
+			`,
+		},
+		// A member cannot delete itself
+		{
+			Query: `
+			mutation DeleteMember($memberUID: ID!) {
+				deleteMember(memberUID: $memberUID) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"memberUID": "bace0701-15e3-5144-97c5-47487d543032"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"deleteMember": {
+					"hasErrors": true,
+					"genericError": "a member cannot delete itself"
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestForgetMember(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		// Forget a not deleted member
+		{
+			Query: `
+			mutation ForgetMember($memberUID: ID!) {
+				forgetMember(memberUID: $memberUID) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"memberUID": "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"forgetMember": {
+					"hasErrors": true,
+					"genericError": "member must be deleted before being forgotten"
+				}
+			}
+			`,
+		},
+		// Delete user02
+		{
+			Query: `
+			mutation DeleteMember($memberUID: ID!) {
+				deleteMember(memberUID: $memberUID) {
+					hasErrors
+				}
+			}
+			`,
+			Variables: `
+			{
+				"memberUID": "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"deleteMember": {
+					"hasErrors": false
+				}
+			}
+			`,
+		},
+		// Forget user02
+		{
+			Query: `
+			mutation ForgetMember($memberUID: ID!) {
+				forgetMember(memberUID: $memberUID) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"memberUID": "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"forgetMember": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// Check that the member personal data have been replaced in the
+		// member history (timeline -8 is the last one before the member
+		// deletion)
+		{
+			Query: `
+			query memberQuery($timeLineID: TimeLineID, $memberUID: ID!){
+				member(timeLineID: $timeLineID, uid: $memberUID) {
+					userName
+					fullName
+					email
+				}
+			}
+			`,
+			Variables: `
+			{
+				"timeLineID": "-8",
+				"memberUID": "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"member": {
+					"userName": "[forgotten]",
+					"fullName": "[forgotten]",
+					"email": "[forgotten]"
+				}
+			}
+			`,
+		},
+		// Forget an already forgotten member
+		{
+			Query: `
+			mutation ForgetMember($memberUID: ID!) {
+				forgetMember(memberUID: $memberUID) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"memberUID": "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"forgetMember": {
+					"hasErrors": true,
+					"genericError": "member already forgotten"
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestRoleConflict(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		{
+			Query: `
+			query timeLine {
+				timeLine {
+					id
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"timeLine": {
+					"id": "1509031099000000000"
+				}
+			}
+			`,
+		},
+		// The role hasn't changed after the expected timeline
+		{
+			Query: `
+				mutation CircleUpdateChildRole($roleUID: ID!, $updateRoleChange: UpdateRoleChange!, $expectedTimeLineID: TimeLineID) {
+					circleUpdateChildRole(roleUID: $roleUID, updateRoleChange: $updateRoleChange, expectedTimeLineID: $expectedTimeLineID) {
+						hasErrors
+						conflict
+						genericError
+						role {
+							name
+						}
+					}
+				}
+			`,
+			Variables: `
+			{
+				"roleUID": "FDi26qza4rFLLTLdbqzpsd",
+				"expectedTimeLineID": "1509031099000000000",
+				"updateRoleChange": {
+					"uid": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+					"nameChanged": true,
+					"name": "rootRole-circle01-newname"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleUpdateChildRole": {
+					"hasErrors": false,
+					"conflict": false,
+					"genericError": null,
+					"role": {
+						"name": "rootRole-circle01-newname"
+					}
+				}
+			}
+			`,
+		},
+		// A stale change of the same role
+		{
+			Query: `
+				mutation CircleUpdateChildRole($roleUID: ID!, $updateRoleChange: UpdateRoleChange!, $expectedTimeLineID: TimeLineID) {
+					circleUpdateChildRole(roleUID: $roleUID, updateRoleChange: $updateRoleChange, expectedTimeLineID: $expectedTimeLineID) {
+						hasErrors
+						conflict
+						genericError
+						role {
+							name
+						}
+					}
+				}
+			`,
+			Variables: `
+			{
+				"roleUID": "FDi26qza4rFLLTLdbqzpsd",
+				"expectedTimeLineID": "1509031099000000000",
+				"updateRoleChange": {
+					"uid": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+					"purposeChanged": true,
+					"purpose": "newpurpose01"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleUpdateChildRole": {
+					"hasErrors": true,
+					"conflict": true,
+					"genericError": "role changed after timeline 1509031099000000000",
+					"role": null
+				}
+			}
+			`,
+		},
+		// A stale change of another role isn't a conflict
+		{
+			Query: `
+			mutation SetRoleAdditionalContent($roleUID: ID!, $content: String!, $expectedTimeLineID: TimeLineID) {
+				setRoleAdditionalContent(roleUID: $roleUID, content: $content, expectedTimeLineID: $expectedTimeLineID) {
+					hasErrors
+					conflict
+				}
+			}
+			`,
+			Variables: `
+			{
+				"roleUID": "FDi26qza4rFLLTLdbqzpsd",
+				"content": "content01",
+				"expectedTimeLineID": "1509031099000000000"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"setRoleAdditionalContent": {
+					"hasErrors": false,
+					"conflict": false
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestRoleMembersConflict(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		// The circle members haven't changed after the expected timeline
+		{
+			Query: `
+			mutation CircleSetLeadLinkMember($roleUID: ID!, $memberUID: ID!, $expectedTimeLineID: TimeLineID) {
+				circleSetLeadLinkMember(roleUID: $roleUID, memberUID: $memberUID, expectedTimeLineID: $expectedTimeLineID) {
+					hasErrors
+					conflict
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+				"memberUID": "t9oc2y8syqYNNLfxfGkXM7",
+				"expectedTimeLineID": "1509031099000000000"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleSetLeadLinkMember": {
+					"hasErrors": false,
+					"conflict": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// A stale change of the same circle members
+		{
+			Query: `
+			mutation CircleUnsetLeadLinkMember($roleUID: ID!, $expectedTimeLineID: TimeLineID) {
+				circleUnsetLeadLinkMember(roleUID: $roleUID, expectedTimeLineID: $expectedTimeLineID) {
+					hasErrors
+					conflict
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+				"expectedTimeLineID": "1509031099000000000"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleUnsetLeadLinkMember": {
+					"hasErrors": true,
+					"conflict": true,
+					"genericError": "role changed after timeline 1509031099000000000"
+				}
+			}
+			`,
+		},
+		// A child role creation isn't a conflict if the parent circle
+		// definition hasn't changed
+		{
+			Query: `
+			mutation CircleCreateChildRole($roleUID: ID!, $createRoleChange: CreateRoleChange!, $expectedTimeLineID: TimeLineID) {
+				circleCreateChildRole(roleUID: $roleUID, createRoleChange: $createRoleChange, expectedTimeLineID: $expectedTimeLineID) {
+					hasErrors
+					conflict
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+				"expectedTimeLineID": "1509031099000000000",
+				"createRoleChange": {
+					"name": "role01",
+					"roleType": "normal"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleCreateChildRole": {
+					"hasErrors": false,
+					"conflict": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// Change the circle definition
+		{
+			Query: `
+				mutation CircleUpdateChildRole($roleUID: ID!, $updateRoleChange: UpdateRoleChange!) {
+					circleUpdateChildRole(roleUID: $roleUID, updateRoleChange: $updateRoleChange) {
+						hasErrors
+					}
+				}
+			`,
+			Variables: `
+			{
+				"roleUID": "FDi26qza4rFLLTLdbqzpsd",
+				"updateRoleChange": {
+					"uid": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+					"purposeChanged": true,
+					"purpose": "newpurpose01"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleUpdateChildRole": {
+					"hasErrors": false
+				}
+			}
+			`,
+		},
+		// A stale child role creation in the changed circle
+		{
+			Query: `
+			mutation CircleCreateChildRole($roleUID: ID!, $createRoleChange: CreateRoleChange!, $expectedTimeLineID: TimeLineID) {
+				circleCreateChildRole(roleUID: $roleUID, createRoleChange: $createRoleChange, expectedTimeLineID: $expectedTimeLineID) {
+					hasErrors
+					conflict
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+				"expectedTimeLineID": "1509031099000000000",
+				"createRoleChange": {
+					"name": "role02",
+					"roleType": "normal"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleCreateChildRole": {
+					"hasErrors": true,
+					"conflict": true,
+					"genericError": "role changed after timeline 1509031099000000000"
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestOrgDiff(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		{
+			Query: `
+				mutation CircleUpdateChildRole($roleUID: ID!, $updateRoleChange: UpdateRoleChange!) {
+					circleUpdateChildRole(roleUID: $roleUID, updateRoleChange: $updateRoleChange) {
+						hasErrors
+					}
+				}
+			`,
+			Variables: `
+			{
+				"roleUID": "FDi26qza4rFLLTLdbqzpsd",
+				"updateRoleChange": {
+					"uid": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+					"nameChanged": true,
+					"name": "rootRole-circle01-newname",
+					"createDomainChanges": [
+						{ "description": "domain01" }
+					]
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleUpdateChildRole": {
+					"hasErrors": false
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation CircleSetCoreRoleMember($roleType: RoleType!) {
+				circleSetCoreRoleMember(roleType: $roleType, roleUID: "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c", memberUID: "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf") {
+					hasErrors
+				}
+			}
+			`,
+			Variables: `
+			{
+				"roleType": "facilitator"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleSetCoreRoleMember": {
+					"hasErrors": false
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query orgDiff($fromTimeLineID: TimeLineID!) {
+				orgDiff(fromTimeLineID: $fromTimeLineID, rootRoleUID: "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c") {
+					fromTimeLineID
+					roles {
+						changeType
+						role {
+							name
+						}
+						previousRole {
+							name
+						}
+						parent {
+							name
+						}
+						previousParent {
+							name
+						}
+					}
+					domains {
+						changeType
+						role {
+							name
+						}
+						domain {
+							description
+						}
+						previousDomain {
+							description
+						}
+					}
+					accountabilities {
+						changeType
+					}
+					roleMembers {
+						changeType
+						role {
+							roleType
+						}
+						roleMember {
+							member {
+								userName
+							}
+						}
+						previousRoleMember {
+							member {
+								userName
+							}
+						}
+					}
+					coreRoles {
+						changeType
+						roleType
+						circle {
+							name
+						}
+						previousCircle {
+							name
+						}
+						roleMember {
+							member {
+								userName
+							}
+						}
+						previousRoleMember {
+							member {
+								userName
+							}
+						}
+					}
+				}
+			}
+			`,
+			Variables: `
+			{
+				"fromTimeLineID": "1509031099000000000"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"orgDiff": {
+					"fromTimeLineID": "1509031099000000000",
+					"roles": [
+						{
+							"changeType": "updated",
+							"role": { "name": "rootRole-circle01-newname" },
+							"previousRole": { "name": "rootRole-circle01" },
+							"parent": { "name": "General" },
+							"previousParent": { "name": "General" }
+						}
+					],
+					"domains": [
+						{
+							"changeType": "new",
+							"role": { "name": "rootRole-circle01-newname" },
+							"domain": { "description": "domain01" },
+							"previousDomain": null
+						}
+					],
+					"accountabilities": [],
+					"roleMembers": [
+						{
+							"changeType": "new",
+							"role": { "roleType": "facilitator" },
+							"roleMember": { "member": { "userName": "user02" } },
+							"previousRoleMember": null
+						}
+					],
+					"coreRoles": [
+						{
+							"changeType": "new",
+							"roleType": "facilitator",
+							"circle": { "name": "rootRole-circle01-newname" },
+							"previousCircle": null,
+							"roleMember": { "member": { "userName": "user02" } },
+							"previousRoleMember": null
+						}
+					]
+				}
+			}
+			`,
+		},
+		// No changes in another subtree
+		{
+			Query: `
+			query orgDiff($fromTimeLineID: TimeLineID!) {
+				orgDiff(fromTimeLineID: $fromTimeLineID, rootRoleUID: "xfFUSNW7mZUWNYZ6JufB7J") {
+					roles {
+						changeType
+					}
+					domains {
+						changeType
+					}
+					accountabilities {
+						changeType
+					}
+					roleMembers {
+						changeType
+					}
+					coreRoles {
+						changeType
+					}
+				}
+			}
+			`,
+			Variables: `
+			{
+				"fromTimeLineID": "1509031099000000000"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"orgDiff": {
+					"roles": [],
+					"domains": [],
+					"accountabilities": [],
+					"roleMembers": [],
+					"coreRoles": []
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestMemberEvents(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		{
+			Query: `
+			mutation CircleSetCoreRoleMember($roleType: RoleType!) {
+				circleSetCoreRoleMember(roleType: $roleType, roleUID: "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c", memberUID: "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf") {
+					hasErrors
+				}
+			}
+			`,
+			Variables: `
+			{
+				"roleType": "facilitator"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleSetCoreRoleMember": {
+					"hasErrors": false
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			{
+				member(uid: "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf") {
+					events {
+						hasMoreData
+						edges {
+							event {
+								type
+								... on MemberEventMemberChanged {
+									member {
+										userName
+									}
+									previousMember {
+										userName
+									}
+									changedFields
+								}
+								... on MemberEventCircleDirectMemberChanged {
+									circle {
+										name
+									}
+								}
+								... on MemberEventRoleMemberChanged {
+									roleType
+									circle {
+										name
+									}
+									noCoreMember
+								}
+							}
+						}
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"member": {
+					"events": {
+						"hasMoreData": false,
+						"edges": [
+							{
+								"event": {
+									"type": "CoreRoleMemberSet",
+									"roleType": "facilitator",
+									"circle": { "name": "rootRole-circle01" },
+									"noCoreMember": false
+								}
+							},
+							{
+								"event": {
+									"type": "LeadLinkMemberSet",
+									"roleType": "leadlink",
+									"circle": { "name": "rootRole-circle01" },
+									"noCoreMember": false
+								}
+							},
+							{
+								"event": {
+									"type": "MemberCreated",
+									"member": { "userName": "user02" },
+									"previousMember": null,
+									"changedFields": []
+								}
+							}
+						]
+					}
+				}
+			}
+			`,
+		},
+		// Paginated
+		{
+			Query: `
+			{
+				member(uid: "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf") {
+					events(first: 2) {
+						hasMoreData
+						edges {
+							event {
+								type
+							}
+						}
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"member": {
+					"events": {
+						"hasMoreData": true,
+						"edges": [
+							{ "event": { "type": "CoreRoleMemberSet" } },
+							{ "event": { "type": "LeadLinkMemberSet" } }
+						]
+					}
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestConnections(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		{
+			Query: `
+			{
+				rootRole {
+					rolesConnection(first: 2) {
+						totalCount
+						pageInfo {
+							hasNextPage
+							hasPreviousPage
+							startCursor
+							endCursor
+						}
+						edges {
+							cursor
+							role {
+								name
+							}
+						}
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"rootRole": {
+					"rolesConnection": {
+						"totalCount": 11,
+						"pageInfo": {
+							"hasNextPage": true,
+							"hasPreviousPage": false,
+							"startCursor": "eyJQb3NpdGlvbiI6MX0=",
+							"endCursor": "eyJQb3NpdGlvbiI6Mn0="
+						},
+						"edges": [
+							{ "cursor": "eyJQb3NpdGlvbiI6MX0=", "role": { "name": "Facilitator" } },
+							{ "cursor": "eyJQb3NpdGlvbiI6Mn0=", "role": { "name": "Lead Link" } }
+						]
+					}
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			{
+				rootRole {
+					rolesConnection(first: 2, after: "eyJQb3NpdGlvbiI6Mn0=") {
+						totalCount
+						pageInfo {
+							hasNextPage
+							hasPreviousPage
+						}
+						edges {
+							role {
+								name
+							}
+						}
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"rootRole": {
+					"rolesConnection": {
+						"totalCount": 11,
+						"pageInfo": {
+							"hasNextPage": true,
+							"hasPreviousPage": true
+						},
+						"edges": [
+							{ "role": { "name": "Secretary" } },
+							{ "role": { "name": "rootRole-circle01" } }
+						]
+					}
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			{
+				rootRole {
+					rolesConnection(last: 2, before: "eyJQb3NpdGlvbiI6M30=") {
+						pageInfo {
+							hasNextPage
+							hasPreviousPage
+						}
+						edges {
+							role {
+								name
+							}
+						}
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"rootRole": {
+					"rolesConnection": {
+						"pageInfo": {
+							"hasNextPage": true,
+							"hasPreviousPage": false
+						},
+						"edges": [
+							{ "role": { "name": "Facilitator" } },
+							{ "role": { "name": "Lead Link" } }
+						]
+					}
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			{
+				role(uid: "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c") {
+					circleMembersConnection(first: 1) {
+						totalCount
+						pageInfo {
+							hasNextPage
+						}
+						edges {
+							cursor
+							member {
+								userName
+							}
+							isLeadLink
+						}
+					}
+					tensionsConnection {
+						totalCount
+						edges {
+							tension {
+								title
+							}
+						}
+					}
+				}
+				member(uid: "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf") {
+					rolesConnection {
+						totalCount
+						edges {
+							cursor
+							role {
+								roleType
+							}
+						}
+					}
+					// only the member itself can see its tensions
+					tensionsConnection {
+						totalCount
+					}
+				}
+				rolesConnection(first: 1) {
+					totalCount
+					pageInfo {
+						hasNextPage
+						hasPreviousPage
+					}
+					edges {
+						role {
+							name
+						}
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"role": {
+					"circleMembersConnection": {
+						"totalCount": 2,
+						"pageInfo": {
+							"hasNextPage": true
+						},
+						"edges": [
+							{ "cursor": "eyJQb3NpdGlvbiI6MX0=", "member": { "userName": "user05" }, "isLeadLink": false }
+						]
+					},
+					"tensionsConnection": {
+						"totalCount": 1,
+						"edges": [
+							{ "tension": { "title": "tension01" } }
+						]
+					}
+				},
+				"member": {
+					"rolesConnection": {
+						"totalCount": 1,
+						"edges": [
+							{ "cursor": "eyJQb3NpdGlvbiI6MX0=", "role": { "roleType": "leadlink" } }
+						]
+					},
+					"tensionsConnection": null
+				},
+				"rolesConnection": {
+					"totalCount": 44,
+					"pageInfo": {
+						"hasNextPage": true,
+						"hasPreviousPage": false
+					},
+					"edges": [
+						{ "role": { "name": "Facilitator" } }
+					]
+				}
+			}
+			`,
+		},
+		// pages of multiple parents are loaded in a single batch
+		{
+			Query: `
+			{
+				rootRole {
+					roles {
+						name
+						rolesConnection(last: 1) {
+							totalCount
+							edges {
+								role {
+									name
+								}
+							}
+						}
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"rootRole": {
+					"roles": [
+						{ "name": "Facilitator", "rolesConnection": { "totalCount": 0, "edges": [] } },
+						{ "name": "Lead Link", "rolesConnection": { "totalCount": 0, "edges": [] } },
+						{ "name": "Secretary", "rolesConnection": { "totalCount": 0, "edges": [] } },
+						{ "name": "rootRole-circle01", "rolesConnection": { "totalCount": 8, "edges": [{ "role": { "name": "rootRole-circle01-role04" } }] } },
+						{ "name": "rootRole-circle02", "rolesConnection": { "totalCount": 8, "edges": [{ "role": { "name": "rootRole-circle02-role04" } }] } },
+						{ "name": "rootRole-circle03", "rolesConnection": { "totalCount": 8, "edges": [{ "role": { "name": "rootRole-circle03-role04" } }] } },
+						{ "name": "rootRole-circle04", "rolesConnection": { "totalCount": 8, "edges": [{ "role": { "name": "rootRole-circle04-role04" } }] } },
+						{ "name": "rootRole-role01", "rolesConnection": { "totalCount": 0, "edges": [] } },
+						{ "name": "rootRole-role02", "rolesConnection": { "totalCount": 0, "edges": [] } },
+						{ "name": "rootRole-role03", "rolesConnection": { "totalCount": 0, "edges": [] } },
+						{ "name": "rootRole-role04", "rolesConnection": { "totalCount": 0, "edges": [] } }
+					]
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestRolesFilter(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		{
+			Query: `
+			query Roles($filter: RoleFilter, $orderBy: RoleOrder) {
+				roles(filter: $filter, orderBy: $orderBy) {
+					name
+				}
+			}
+			`,
+			Variables: `
+			{
+				"filter": { "roleTypes": ["circle"] },
+				"orderBy": { "field": "name", "direction": "desc" }
+			}
+			`,
+			ExpectedResult: `
+			{
+				"roles": [
+					{ "name": "rootRole-circle04" },
+					{ "name": "rootRole-circle03" },
+					{ "name": "rootRole-circle02" },
+					{ "name": "rootRole-circle01" },
+					{ "name": "General" }
+				]
+			}
+			`,
+		},
+		{
+			Query: `
+			query Roles($filter: RoleFilter) {
+				roles(filter: $filter) {
+					name
+				}
+			}
+			`,
+			Variables: `
+			{
+				"filter": { "roleTypes": ["leadlink"], "subtreeRootUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c" }
+			}
+			`,
+			ExpectedResult: `
+			{
+				"roles": [
+					{ "name": "Lead Link" }
+				]
+			}
+			`,
+		},
+		{
+			Query: `
+			query Roles($filter: RoleFilter) {
+				roles(filter: $filter) {
+					name
+				}
+			}
+			`,
+			Variables: `
+			{
+				"filter": { "unfilled": false }
+			}
+			`,
+			ExpectedResult: `
+			{
+				"roles": [
+					{ "name": "Lead Link" },
+					{ "name": "Lead Link" },
+					{ "name": "Rep Link" },
+					{ "name": "Secretary" }
+				]
+			}
+			`,
+		},
+		{
+			Query: `
+			query Roles($filter: RoleFilter) {
+				roles(filter: $filter) {
+					name
+				}
+			}
+			`,
+			Variables: `
+			{
+				"filter": { "unfilled": true, "subtreeRootUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c" }
+			}
+			`,
+			ExpectedResult: `
+			{
+				"roles": [
+					{ "name": "Facilitator" },
+					{ "name": "Rep Link" },
+					{ "name": "Secretary" },
+					{ "name": "rootRole-circle01-role01" },
+					{ "name": "rootRole-circle01-role02" },
+					{ "name": "rootRole-circle01-role03" },
+					{ "name": "rootRole-circle01-role04" }
+				]
+			}
+			`,
+		},
+		{
+			Query: `
+			query RolesConnection($filter: RoleFilter, $orderBy: RoleOrder) {
+				rolesConnection(filter: $filter, orderBy: $orderBy, first: 2, after: "eyJQb3NpdGlvbiI6Mn0=") {
+					totalCount
+					pageInfo {
+						hasNextPage
+						hasPreviousPage
+					}
+					edges {
+						role {
+							name
+							depth
+						}
+					}
+				}
+			}
+			`,
+			Variables: `
+			{
+				"filter": { "roleTypes": ["normal"], "minDepth": 2, "maxDepth": 2 },
+				"orderBy": { "field": "depth" }
+			}
+			`,
+			ExpectedResult: `
+			{
+				"rolesConnection": {
+					"totalCount": 16,
+					"pageInfo": {
+						"hasNextPage": true,
+						"hasPreviousPage": true
+					},
+					"edges": [
+						{ "role": { "name": "rootRole-circle01-role03", "depth": 2 } },
+						{ "role": { "name": "rootRole-circle01-role04", "depth": 2 } }
+					]
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query Roles($orderBy: RoleOrder) {
+				roles(orderBy: $orderBy) {
+					name
+				}
+			}
+			`,
+			Variables: `
+			{
+				"orderBy": { "field": "unknown" }
+			}
+			`,
+			ExpectedResult: `
+			{
+				"roles": null
+			}
+			`,
+			Error: fmt.Errorf(`graphql: unknown role order field "unknown"`),
+		},
+	})
+}
+
+func TestMembersFilter(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		{
+			Query: `
+			query Members($filter: MemberFilter, $orderBy: MemberOrder) {
+				members(filter: $filter, orderBy: $orderBy) {
+					edges {
+						member {
+							userName
+						}
+					}
+					hasMoreData
+				}
+			}
+			`,
+			Variables: `
+			{
+				"filter": { "filledRolesGreaterThan": 0 },
+				"orderBy": { "field": "filledroles", "direction": "desc" }
+			}
+			`,
+			ExpectedResult: `
+			{
+				"members": {
+					"edges": [
+						{ "member": { "userName": "user03" } },
+						{ "member": { "userName": "user02" } },
+						{ "member": { "userName": "user04" } }
+					],
+					"hasMoreData": false
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query Members($filter: MemberFilter) {
+				members(filter: $filter) {
+					edges {
+						member {
+							userName
+						}
+					}
+					hasMoreData
+				}
+			}
+			`,
+			Variables: `
+			{
+				"filter": { "leadLinkOfUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c" }
+			}
+			`,
+			ExpectedResult: `
+			{
+				"members": {
+					"edges": [
+						{ "member": { "userName": "user02" } }
+					],
+					"hasMoreData": false
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query Members($orderBy: MemberOrder) {
+				members(search: "user", orderBy: $orderBy, first: 2) {
+					edges {
+						cursor
+						member {
+							userName
+						}
+					}
+					hasMoreData
+				}
+			}
+			`,
+			Variables: `
+			{
+				"orderBy": { "field": "username", "direction": "desc" }
+			}
+			`,
+			ExpectedResult: `
+			{
+				"members": {
+					"edges": [
+						{
+							"cursor": "eyJUaW1lTGluZUlEIjoiMTUwOTAzMTA5OTAwMDAwMDAwMCIsIlNlYXJjaFN0cmluZyI6InVzZXIiLCJGaWx0ZXIiOm51bGwsIk9yZGVyIjp7IkZpZWxkIjoidXNlcm5hbWUiLCJEZXNjIjp0cnVlfSwiTWVtYmVySUQiOiJiMmRhNDM4YS1jM2QxLTU1NmItYTRkNC1jMjQ4OWUwODIwN2MifQ==",
+							"member": { "userName": "user09" }
+						},
+						{
+							"cursor": "eyJUaW1lTGluZUlEIjoiMTUwOTAzMTA5OTAwMDAwMDAwMCIsIlNlYXJjaFN0cmluZyI6InVzZXIiLCJGaWx0ZXIiOm51bGwsIk9yZGVyIjp7IkZpZWxkIjoidXNlcm5hbWUiLCJEZXNjIjp0cnVlfSwiTWVtYmVySUQiOiJlMDg0NGQ0Mi1iMmNmLTVlODktODJlNy04YzI3ZjJmZTRhZGUifQ==",
+							"member": { "userName": "user08" }
+						}
+					],
+					"hasMoreData": true
+				}
+			}
+			`,
+		},
+		{
+			// the cursor keeps the search string and the order
+			Query: `
+			{
+				members(first: 3, after: "eyJUaW1lTGluZUlEIjoiMTUwOTAzMTA5OTAwMDAwMDAwMCIsIlNlYXJjaFN0cmluZyI6InVzZXIiLCJGaWx0ZXIiOm51bGwsIk9yZGVyIjp7IkZpZWxkIjoidXNlcm5hbWUiLCJEZXNjIjp0cnVlfSwiTWVtYmVySUQiOiJlMDg0NGQ0Mi1iMmNmLTVlODktODJlNy04YzI3ZjJmZTRhZGUifQ==") {
+					edges {
+						member {
+							userName
+						}
+					}
+					hasMoreData
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"members": {
+					"edges": [
+						{ "member": { "userName": "user07" } },
+						{ "member": { "userName": "user06" } },
+						{ "member": { "userName": "user05" } }
+					],
+					"hasMoreData": true
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestCommandAuditLog(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		// A request rejected by the command service validation
+		{
+			Query: `
+			mutation ForgetMember($memberUID: ID!) {
+				forgetMember(memberUID: $memberUID) {
+					hasErrors
+				}
+			}
+			`,
+			Variables: `
+			{
+				"memberUID": "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"forgetMember": {
+					"hasErrors": true
+				}
+			}
+			`,
+		},
+		// An executed command
+		{
+			Query: `
+			mutation DeleteMember($memberUID: ID!) {
+				deleteMember(memberUID: $memberUID) {
+					hasErrors
+				}
+			}
+			`,
+			Variables: `
+			{
+				"memberUID": "18724eb3-ccc9-5c96-b0b7-91dcf95bacbf"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"deleteMember": {
+					"hasErrors": false
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query commandAuditLog($commandType: String) {
+				commandAuditLog(commandType: $commandType) {
+					edges {
+						entry {
+							commandType
+							issuerID
+							aggregateType
+							aggregateID
+							eventsCount
+							result
+							error
+						}
+					}
+					hasMoreData
+				}
+			}
+			`,
+			Variables: `
+			{
+				"commandType": "ForgetMember"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"commandAuditLog": {
+					"edges": [
+						{
+							"entry": {
+								"commandType": "ForgetMember",
+								"issuerID": "bace0701-15e3-5144-97c5-47487d543032",
+								"aggregateType": null,
+								"aggregateID": null,
+								"eventsCount": 0,
+								"result": "rejected",
+								"error": "member must be deleted before being forgotten"
+							}
+						}
+					],
+					"hasMoreData": false
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query commandAuditLog($memberUID: ID, $commandType: String) {
+				commandAuditLog(memberUID: $memberUID, commandType: $commandType, first: 1) {
+					edges {
+						entry {
+							commandType
+							issuerID
+							aggregateType
+							eventsCount
+							result
+							error
+						}
+					}
+					hasMoreData
+				}
+			}
+			`,
+			Variables: `
+			{
+				"memberUID": "bace0701-15e3-5144-97c5-47487d543032",
+				"commandType": "RequestDeleteMember"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"commandAuditLog": {
+					"edges": [
+						{
+							"entry": {
+								"commandType": "RequestDeleteMember",
+								"issuerID": "bace0701-15e3-5144-97c5-47487d543032",
+								"aggregateType": "memberchange",
+								"eventsCount": 1,
+								"result": "executed",
+								"error": null
+							}
+						}
+					],
+					"hasMoreData": false
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query commandAuditLog($commandType: String) {
+				commandAuditLog(commandType: $commandType, first: 1) {
+					edges {
+						entry {
+							commandType
+							result
+						}
+					}
+					hasMoreData
+				}
+			}
+			`,
+			Variables: `
+			{
+				"commandType": "RequestCreateMember"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"commandAuditLog": {
+					"edges": [
+						{
+							"entry": {
+								"commandType": "RequestCreateMember",
+								"result": "executed"
+							}
+						}
+					],
+					"hasMoreData": true
+				}
+			}
+			`,
+		},
+		// Time range without entries
+		{
+			Query: `
+			query commandAuditLog($fromTime: Time, $toTime: Time) {
+				commandAuditLog(fromTime: $fromTime, toTime: $toTime) {
+					edges {
+						entry {
+							commandType
+						}
+					}
+					hasMoreData
+				}
+			}
+			`,
+			Variables: `
+			{
+				"fromTime": "2000-01-01T00:00:00Z",
+				"toTime": "2001-01-01T00:00:00Z"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"commandAuditLog": {
+					"edges": [],
+					"hasMoreData": false
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestProposal(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		// Create a proposal on tension01 and rootRole-circle01
+		{
+			Query: `
+			mutation CreateProposal($createProposalChange: CreateProposalChange!) {
+				createProposal(createProposalChange: $createProposalChange) {
+					proposal {
+						state
+						tension {
+							title
+						}
+						role {
+							name
+						}
+						proposer {
+							userName
+						}
+					}
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"createProposalChange": {
+					"tensionUID": "3c8f4a9e-2afc-56c8-aefb-e97817511f70",
+					"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+					"createRoleChanges": [
+						{
+							"name": "proposalrole01",
+							"roleType": "normal"
+						}
+					],
+					"updateRoleChanges": [
+						{
+							"uid": "0f2af650-b98b-57f3-9dcb-bb8bd8bf6479",
+							"nameChanged": true,
+							"name": "circle01-role01-new"
+						}
+					],
+					"deleteRoleChanges": [
+						{
+							"uid": "ad04767f-639e-5073-bf44-557f4794f49f"
+						}
+					]
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"createProposal": {
+					"proposal": {
+						"state": "open",
+						"tension": {
+							"title": "tension01"
+						},
+						"role": {
+							"name": "rootRole-circle01"
+						},
+						"proposer": {
+							"userName": "admin"
+						}
+					},
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// A member not in the circle cannot object
+		{
+			Query: `
+			mutation ObjectProposal($proposalUID: ID!, $reason: String!) {
+				objectProposal(proposalUID: $proposalUID, reason: $reason) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"proposalUID": "8236ac42-2177-5d8a-be2c-49b81524704c",
+				"reason": "objection01"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"objectProposal": {
+					"hasErrors": true,
+					"genericError": "member is not member of role"
+				}
+			}
+			`,
+		},
+		// Add member admin as direct member of rootRole-circle01
+		{
+			Query: `
+			mutation CircleAddDirectMember($roleUID: ID!, $memberUID: ID!) {
+				circleAddDirectMember(roleUID: $roleUID, memberUID: $memberUID) {
+					hasErrors
+				}
+			}
+			`,
+			Variables: `
+			{
+				"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+				"memberUID": "bace0701-15e3-5144-97c5-47487d543032"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleAddDirectMember": {
+					"hasErrors": false
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation ObjectProposal($proposalUID: ID!, $reason: String!) {
+				objectProposal(proposalUID: $proposalUID, reason: $reason) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"proposalUID": "8236ac42-2177-5d8a-be2c-49b81524704c",
+				"reason": "objection01"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"objectProposal": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// A proposal with objections cannot be accepted
+		{
+			Query: `
+			mutation AcceptProposal($proposalUID: ID!) {
+				acceptProposal(proposalUID: $proposalUID) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"proposalUID": "8236ac42-2177-5d8a-be2c-49b81524704c"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"acceptProposal": {
+					"hasErrors": true,
+					"genericError": "proposal has objections"
+				}
+			}
+			`,
+		},
+		// Updating the proposal removes the objections
+		{
+			Query: `
+			mutation UpdateProposal($updateProposalChange: UpdateProposalChange!) {
+				updateProposal(updateProposalChange: $updateProposalChange) {
+					proposal {
+						state
+						objections {
+							reason
+						}
+					}
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"updateProposalChange": {
+					"uid": "8236ac42-2177-5d8a-be2c-49b81524704c",
+					"createRoleChanges": [
+						{
+							"name": "proposalrole01",
+							"roleType": "normal"
+						}
+					],
+					"updateRoleChanges": [
+						{
+							"uid": "0f2af650-b98b-57f3-9dcb-bb8bd8bf6479",
+							"nameChanged": true,
+							"name": "circle01-role01-new"
+						}
+					],
+					"deleteRoleChanges": [
+						{
+							"uid": "ad04767f-639e-5073-bf44-557f4794f49f"
+						}
+					]
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"updateProposal": {
+					"proposal": {
+						"state": "open",
+						"objections": []
+					},
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation AcceptProposal($proposalUID: ID!) {
+				acceptProposal(proposalUID: $proposalUID) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"proposalUID": "8236ac42-2177-5d8a-be2c-49b81524704c"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"acceptProposal": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// Check that all the proposal changes have been applied in a single
+		// role event linked to the proposal
+		{
+			Query: `
+			query roleQuery($roleUID: ID!) {
+				role(uid: $roleUID) {
+					roles {
+						name
+					}
+					events(first: 1) {
+						edges {
+							event {
+							type
+							... on RoleEventCircleChangesApplied {
+									changedRoles {
+										changeType
+										previousRole {
+											name
+										}
+									}
+									proposal {
+										state
+										tension {
+											title
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+			`,
+			Variables: `
+			{
+				"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c"
+			}
+			`,
+			// the proposal is reported at the role event timeline, after its
+			// acceptance
+			ExpectedResult: `
+			{
+				"role": {
+					"roles": [
+						{ "name": "Facilitator" },
+						{ "name": "Lead Link" },
+						{ "name": "Rep Link" },
+						{ "name": "Secretary" },
+						{ "name": "circle01-role01-new" },
+						{ "name": "proposalrole01" },
+						{ "name": "rootRole-circle01-role03" },
+						{ "name": "rootRole-circle01-role04" }
+					],
+					"events": {
+						"edges": [
+							{
+								"event": {
+									"type": "CircleChangesApplied",
+									"changedRoles": [
+										{
+											"changeType": "new",
+											"previousRole": null
+										},
+										{
+											"changeType": "updated",
+											"previousRole": {
+												"name": "rootRole-circle01-role01"
+											}
+										},
+										{
+											"changeType": "deleted",
+											"previousRole": {
+												"name": "rootRole-circle01-role02"
+											}
+										}
+									],
+									"proposal": {
+										"state": "accepted",
+										"tension": {
+											"title": "tension01"
+										}
+									}
+								}
+							}
+						]
+					}
+				}
+			}
+			`,
+		},
+		// An accepted proposal cannot be accepted again
+		{
+			Query: `
+			mutation AcceptProposal($proposalUID: ID!) {
+				acceptProposal(proposalUID: $proposalUID) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"proposalUID": "8236ac42-2177-5d8a-be2c-49b81524704c"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"acceptProposal": {
+					"hasErrors": true,
+					"genericError": "proposal isn't open"
+				}
+			}
+			`,
+		},
+	})
+}
+
+// TestProposalNotApplicable checks that a proposal whose changes cannot be
+// applied anymore to the roles tree is left open
+func TestProposalNotApplicable(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		// Create a proposal on tension01 and rootRole-circle01 deleting
+		// rootRole-circle01-role02
+		{
+			Query: `
+			mutation CreateProposal($createProposalChange: CreateProposalChange!) {
+				createProposal(createProposalChange: $createProposalChange) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"createProposalChange": {
+					"tensionUID": "3c8f4a9e-2afc-56c8-aefb-e97817511f70",
+					"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+					"deleteRoleChanges": [
+						{
+							"uid": "ad04767f-639e-5073-bf44-557f4794f49f"
+						}
+					]
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"createProposal": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// Directly delete rootRole-circle01-role02
+		{
+			Query: `
+				mutation CircleDeleteChildRole($roleUID: ID!, $deleteRoleChange: DeleteRoleChange!) {
+					circleDeleteChildRole(roleUID: $roleUID, deleteRoleChange: $deleteRoleChange) {
+						hasErrors
+					}
+				}
+			`,
+			Variables: `
+			{
+				"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+				"deleteRoleChange": {
+					"uid": "ad04767f-639e-5073-bf44-557f4794f49f"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleDeleteChildRole": {
+					"hasErrors": false
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation AcceptProposal($proposalUID: ID!) {
+				acceptProposal(proposalUID: $proposalUID) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"proposalUID": "8236ac42-2177-5d8a-be2c-49b81524704c"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"acceptProposal": {
+					"hasErrors": true,
+					"genericError": "proposal cannot be applied: role with id ad04767f-639e-5073-bf44-557f4794f49f doesn't exist"
+				}
+			}
+			`,
+		},
+		// Check that the proposal acceptance has been reverted
+		{
+			Query: `
+			query tensionQuery($tensionUID: ID!) {
+				tension(uid: $tensionUID) {
+					proposals {
+						state
+					}
+				}
+			}
+			`,
+			Variables: `
+			{
+				"tensionUID": "3c8f4a9e-2afc-56c8-aefb-e97817511f70"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"tension": {
+					"proposals": [
+						{ "state": "open" }
+					]
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestMeeting(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		// Attendees must be circle members
+		{
+			Query: `
+			mutation CreateMeeting($createMeetingChange: CreateMeetingChange!) {
+				createMeeting(createMeetingChange: $createMeetingChange) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"createMeetingChange": {
+					"roleUID": "5a6fee7f-f0ab-5290-b0ce-302376193112",
+					"meetingType": "governance",
+					"title": "governance01",
+					"startTime": "2017-05-01T10:00:00Z",
+					"attendeesUIDs": [ "1699e266-8401-558e-b9f5-7e2d7f965b82" ]
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"createMeeting": {
+					"hasErrors": true,
+					"genericError": "attendee 1699e266-8401-558e-b9f5-7e2d7f965b82 is not member of role"
+				}
+			}
+			`,
+		},
+		// Create a governance meeting on rootRole-circle02, its secretary is
+		// user03
+		{
+			Query: `
+			mutation CreateMeeting($createMeetingChange: CreateMeetingChange!) {
+				createMeeting(createMeetingChange: $createMeetingChange) {
+					meeting {
+						meetingType
+						title
+						startTime
+						closed
+						role {
+							name
+						}
+						facilitator {
+							userName
+						}
+						secretary {
+							userName
+						}
+						attendees {
+							userName
+						}
+					}
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"createMeetingChange": {
+					"roleUID": "5a6fee7f-f0ab-5290-b0ce-302376193112",
+					"meetingType": "governance",
+					"title": "governance01",
+					"startTime": "2017-05-01T10:00:00Z",
+					"attendeesUIDs": [ "58170eb6-8600-5bfd-8018-7bd75e60b1fd" ]
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"createMeeting": {
+					"meeting": {
+						"meetingType": "governance",
+						"title": "governance01",
+						"startTime": "2017-05-01T10:00:00Z",
+						"closed": false,
+						"role": {
+							"name": "rootRole-circle02"
+						},
+						"facilitator": null,
+						"secretary": {
+							"userName": "user03"
+						},
+						"attendees": [
+							{ "userName": "user03" }
+						]
+					},
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// tension01 is assigned to rootRole-circle01
+		{
+			Query: `
+			mutation AddMeetingAgendaItem($addMeetingAgendaItemChange: AddMeetingAgendaItemChange!) {
+				addMeetingAgendaItem(addMeetingAgendaItemChange: $addMeetingAgendaItemChange) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"addMeetingAgendaItemChange": {
+					"meetingUID": "9ef312de-e485-5005-935a-bd47185deb82",
+					"title": "agendaitem01",
+					"tensionUID": "3c8f4a9e-2afc-56c8-aefb-e97817511f70"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"addMeetingAgendaItem": {
+					"hasErrors": true,
+					"genericError": "tension isn't assigned to the meeting role"
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation AddMeetingAgendaItem($addMeetingAgendaItemChange: AddMeetingAgendaItemChange!) {
+				addMeetingAgendaItem(addMeetingAgendaItemChange: $addMeetingAgendaItemChange) {
+					meeting {
+						agendaItems {
+							title
+							tension {
+								title
+							}
+							outcome
+						}
+					}
+					agendaItemUID
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"addMeetingAgendaItemChange": {
+					"meetingUID": "9ef312de-e485-5005-935a-bd47185deb82",
+					"title": "agendaitem01"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"addMeetingAgendaItem": {
+					"meeting": {
+						"agendaItems": [
+							{
+								"title": "agendaitem01",
+								"tension": null,
+								"outcome": ""
+							}
+						]
+					},
+					"agendaItemUID": "2h6iCDgAqxeEG9tGb57atd",
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation SetMeetingAgendaItemOutcome($meetingUID: ID!, $agendaItemUID: ID!, $outcome: String!) {
+				setMeetingAgendaItemOutcome(meetingUID: $meetingUID, agendaItemUID: $agendaItemUID, outcome: $outcome) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"meetingUID": "9ef312de-e485-5005-935a-bd47185deb82",
+				"agendaItemUID": "c9b3bed1-0f82-52a9-a0b3-cd5ab08ec396",
+				"outcome": "outcome01"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"setMeetingAgendaItemOutcome": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation CloseMeeting($meetingUID: ID!) {
+				closeMeeting(meetingUID: $meetingUID) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"meetingUID": "9ef312de-e485-5005-935a-bd47185deb82"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"closeMeeting": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation AddMeetingAgendaItem($addMeetingAgendaItemChange: AddMeetingAgendaItemChange!) {
+				addMeetingAgendaItem(addMeetingAgendaItemChange: $addMeetingAgendaItemChange) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"addMeetingAgendaItemChange": {
+					"meetingUID": "9ef312de-e485-5005-935a-bd47185deb82",
+					"title": "agendaitem02"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"addMeetingAgendaItem": {
+					"hasErrors": true,
+					"genericError": "meeting already closed"
+				}
+			}
+			`,
+		},
+		// Browse the circle meetings
+		{
+			Query: `
+			query {
+				role(uid: "5a6fee7f-f0ab-5290-b0ce-302376193112") {
+					meetings {
+						title
+						closed
+						agendaItems {
+							title
+							outcome
+						}
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"role": {
+					"meetings": [
+						{
+							"title": "governance01",
+							"closed": true,
+							"agendaItems": [
+								{
+									"title": "agendaitem01",
+									"outcome": "outcome01"
+								}
+							]
+						}
+					]
+				}
+			}
+			`,
+		},
+		// Add to a rootRole-circle01 meeting an agenda item discussing
+		// tension01
+		{
+			Query: `
+			mutation CreateMeeting($createMeetingChange: CreateMeetingChange!) {
+				createMeeting(createMeetingChange: $createMeetingChange) {
+					meeting {
+						uid
+					}
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"createMeetingChange": {
+					"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+					"meetingType": "tactical",
+					"title": "tactical01",
+					"startTime": "2017-05-02T10:00:00Z",
+					"attendeesUIDs": []
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"createMeeting": {
+					"meeting": {
+						"uid": "xbeRaPeyRDKXfSV7G3QVyd"
+					},
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation AddMeetingAgendaItem($addMeetingAgendaItemChange: AddMeetingAgendaItemChange!) {
+				addMeetingAgendaItem(addMeetingAgendaItemChange: $addMeetingAgendaItemChange) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"addMeetingAgendaItemChange": {
+					"meetingUID": "ca2fe07b-254e-5c98-84a7-143ea82c5f79",
+					"title": "agendaitem03"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"addMeetingAgendaItem": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation AddMeetingAgendaItem($addMeetingAgendaItemChange: AddMeetingAgendaItemChange!) {
+				addMeetingAgendaItem(addMeetingAgendaItemChange: $addMeetingAgendaItemChange) {
+					meeting {
+						agendaItems {
+							title
+							tension {
+								title
+							}
+						}
+					}
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"addMeetingAgendaItemChange": {
+					"meetingUID": "ca2fe07b-254e-5c98-84a7-143ea82c5f79",
+					"title": "agendaitem04",
+					"tensionUID": "3c8f4a9e-2afc-56c8-aefb-e97817511f70"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"addMeetingAgendaItem": {
+					"meeting": {
+						"agendaItems": [
+							{
+								"title": "agendaitem03",
+								"tension": null
+							},
+							{
+								"title": "agendaitem04",
+								"tension": {
+									"title": "tension01"
+								}
+							}
+						]
+					},
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestProject(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		// The project member must fill the role
+		{
+			Query: `
+			mutation CreateProject($createProjectChange: CreateProjectChange!) {
+				createProject(createProjectChange: $createProjectChange) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"createProjectChange": {
+					"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+					"memberUID": "58170eb6-8600-5bfd-8018-7bd75e60b1fd",
+					"title": "project01",
+					"description": "description01"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"createProject": {
+					"hasErrors": true,
+					"genericError": "member 58170eb6-8600-5bfd-8018-7bd75e60b1fd doesn't fill role"
+				}
+			}
+			`,
+		},
+		// Create a project on rootRole-circle01 owned by user05, a circle
+		// member
+		{
+			Query: `
+			mutation CreateProject($createProjectChange: CreateProjectChange!) {
+				createProject(createProjectChange: $createProjectChange) {
+					project {
+						title
+						description
+						status
+						role {
+							name
+						}
+						member {
+							userName
+						}
+						nextActions {
+							description
+						}
+					}
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"createProjectChange": {
+					"roleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+					"memberUID": "1699e266-8401-558e-b9f5-7e2d7f965b82",
+					"title": "project01",
+					"description": "description01"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"createProject": {
+					"project": {
+						"title": "project01",
+						"description": "description01",
+						"status": "current",
+						"role": {
+							"name": "rootRole-circle01"
+						},
+						"member": {
+							"userName": "user05"
+						},
+						"nextActions": []
+					},
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation AddProjectNextAction($addProjectNextActionChange: AddProjectNextActionChange!) {
+				addProjectNextAction(addProjectNextActionChange: $addProjectNextActionChange) {
+					project {
+						nextActions {
+							description
+							completed
+						}
+					}
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"addProjectNextActionChange": {
+					"projectUID": "6e0686f2-9e69-5b10-89a9-52b29ffb050f",
+					"description": "call the supplier"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"addProjectNextAction": {
+					"project": {
+						"nextActions": [
+							{ "description": "call the supplier", "completed": false }
+						]
+					},
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation {
+				completeProjectNextAction(projectUID: "6e0686f2-9e69-5b10-89a9-52b29ffb050f", nextActionUID: "74ebd0ce-2eec-556a-a80b-834afca7d2af") {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"completeProjectNextAction": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation AddProjectNextAction($addProjectNextActionChange: AddProjectNextActionChange!) {
+				addProjectNextAction(addProjectNextActionChange: $addProjectNextActionChange) {
+					project {
+						nextActions {
+							description
+							completed
+						}
+					}
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"addProjectNextActionChange": {
+					"projectUID": "6e0686f2-9e69-5b10-89a9-52b29ffb050f",
+					"description": "check the delivery"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"addProjectNextAction": {
+					"project": {
+						"nextActions": [
+							{ "description": "call the supplier", "completed": true },
+							{ "description": "check the delivery", "completed": false }
+						]
+					},
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation ChangeProjectStatus($status: ProjectStatus!) {
+				changeProjectStatus(projectUID: "6e0686f2-9e69-5b10-89a9-52b29ffb050f", status: $status) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"status": "done"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"changeProjectStatus": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// A done project cannot be updated
+		{
+			Query: `
+			mutation UpdateProject($updateProjectChange: UpdateProjectChange!) {
+				updateProject(updateProjectChange: $updateProjectChange) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"updateProjectChange": {
+					"uid": "6e0686f2-9e69-5b10-89a9-52b29ffb050f",
+					"title": "project01",
+					"description": "description02"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"updateProject": {
+					"hasErrors": true,
+					"genericError": "project is done"
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query {
+				role(uid: "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c") {
+					projects {
+						title
+						status
+						nextActions {
+							description
+							completed
+						}
+					}
+				}
+				member(uid: "1699e266-8401-558e-b9f5-7e2d7f965b82") {
+					projects {
+						title
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"role": {
+					"projects": [
+						{
+							"title": "project01",
+							"status": "done",
+							"nextActions": [
+								{ "description": "call the supplier", "completed": true },
+								{ "description": "check the delivery", "completed": false }
+							]
+						}
+					]
+				},
+				"member": {
+					"projects": [
+						{ "title": "project01" }
+					]
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestReportItem(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		// The assigned role must be a circle child role
+		{
+			Query: `
+			mutation CreateReportItem($createReportItemChange: CreateReportItemChange!) {
+				createReportItem(createReportItemChange: $createReportItemChange) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"createReportItemChange": {
+					"circleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+					"roleUID": "6c15ab61-4dff-5c93-b8cc-c1e8414d68b0",
+					"reportItemType": "metric",
+					"description": "deployments",
+					"frequency": "monthly"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"createReportItem": {
+					"hasErrors": true,
+					"genericError": "role with id 6c15ab61-4dff-5c93-b8cc-c1e8414d68b0 doesn't have parent circle with id 66c0cc1f-f608-53dc-88b5-f3afd68a4d6c"
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation CreateReportItem($createReportItemChange: CreateReportItemChange!) {
+				createReportItem(createReportItemChange: $createReportItemChange) {
+					reportItem {
+						reportItemType
+						description
+						frequency
+						circle {
+							name
+						}
+						role {
+							name
+						}
+					}
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"createReportItemChange": {
+					"circleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+					"roleUID": "0f2af650-b98b-57f3-9dcb-bb8bd8bf6479",
+					"reportItemType": "metric",
+					"description": "deployments",
+					"frequency": "monthly"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"createReportItem": {
+					"reportItem": {
+						"reportItemType": "metric",
+						"description": "deployments",
+						"frequency": "monthly",
+						"circle": {
+							"name": "rootRole-circle01"
+						},
+						"role": {
+							"name": "rootRole-circle01-role01"
+						}
+					},
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation CreateReportItem($createReportItemChange: CreateReportItemChange!) {
+				createReportItem(createReportItemChange: $createReportItemChange) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"createReportItemChange": {
+					"circleUID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c",
+					"roleUID": "ad04767f-639e-5073-bf44-557f4794f49f",
+					"reportItemType": "checklist",
+					"description": "release notes written",
+					"frequency": "weekly"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"createReportItem": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation SetReportItemValue($period: Time!) {
+				setReportItemValue(reportItemUID: "49b758e3-93c9-509e-9ceb-9f87ad298f1e", period: $period, value: 2) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"period": "2017-05-17T10:00:00Z"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"setReportItemValue": {
+					"hasErrors": true,
+					"genericError": "checklist item value must be 0 or 1"
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation SetReportItemValue($period: Time!) {
+				setReportItemValue(reportItemUID: "6c33d95b-3dc9-533f-b77b-aaab693ce909", period: $period, value: 12) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"period": "2017-05-17T10:00:00Z"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"setReportItemValue": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation SetReportItemValue($period: Time!) {
+				setReportItemValue(reportItemUID: "6c33d95b-3dc9-533f-b77b-aaab693ce909", period: $period, value: 8) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"period": "2017-04-03T00:00:00Z"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"setReportItemValue": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// Replace the value of the 2017-05 period
+		{
+			Query: `
+			mutation SetReportItemValue($period: Time!) {
+				setReportItemValue(reportItemUID: "6c33d95b-3dc9-533f-b77b-aaab693ce909", period: $period, value: 14) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"period": "2017-05-31T23:00:00Z"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"setReportItemValue": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query RoleReportItems($from: Time) {
+				role(uid: "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c") {
+					reportItems {
+						description
+						values {
+							period
+							value
+						}
+						lastValues: values(from: $from) {
+							period
+							value
+						}
+					}
+				}
+			}
+			`,
+			Variables: `
+			{
+				"from": "2017-05-01T00:00:00Z"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"role": {
+					"reportItems": [
+						{
+							"description": "deployments",
+							"values": [
+								{ "period": "2017-04-01T00:00:00Z", "value": 8 },
+								{ "period": "2017-05-01T00:00:00Z", "value": 14 }
+							],
+							"lastValues": [
+								{ "period": "2017-05-01T00:00:00Z", "value": 14 }
+							]
+						},
+						{
+							"description": "release notes written",
+							"values": [],
+							"lastValues": []
+						}
+					]
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query {
+				role(uid: "0f2af650-b98b-57f3-9dcb-bb8bd8bf6479") {
+					assignedReportItems {
+						description
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"role": {
+					"assignedReportItems": [
+						{ "description": "deployments" }
+					]
+				}
+			}
+			`,
+		},
+		// Deleting a report item also removes its values
+		{
+			Query: `
+			mutation {
+				deleteReportItem(reportItemUID: "6c33d95b-3dc9-533f-b77b-aaab693ce909") {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"deleteReportItem": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query {
+				role(uid: "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c") {
+					reportItems {
+						description
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"role": {
+					"reportItems": [
+						{ "description": "release notes written" }
+					]
+				}
+			}
+			`,
+		},
+	})
+}
+
+func TestExpiringElections(t *testing.T) {
+	RunTests(t, initBasic, []*Test{
+		// Set user05 as facilitator of rootRole-circle01 with an already
+		// expired election
+		{
+			Query: `
+			mutation CircleSetCoreRoleMember($roleType: RoleType!, $electionExpiration: Time) {
+				circleSetCoreRoleMember(roleType: $roleType, roleUID: "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c", memberUID: "1699e266-8401-558e-b9f5-7e2d7f965b82", electionExpiration: $electionExpiration) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"roleType": "facilitator",
+				"electionExpiration": "2000-01-01T00:00:00Z"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleSetCoreRoleMember": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// Set user05 as secretary of rootRole-circle01 with an election
+		// expiring in a far future
+		{
+			Query: `
+			mutation CircleSetCoreRoleMember($roleType: RoleType!, $electionExpiration: Time) {
+				circleSetCoreRoleMember(roleType: $roleType, roleUID: "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c", memberUID: "1699e266-8401-558e-b9f5-7e2d7f965b82", electionExpiration: $electionExpiration) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"roleType": "secretary",
+				"electionExpiration": "2100-01-01T00:00:00Z"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"circleSetCoreRoleMember": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query {
+				role(uid: "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c") {
+					name
+					expiringElections {
+						role {
+							name
+						}
+						circle {
+							name
+						}
+						member {
+							userName
+						}
+						roleType
+						electionExpiration
+						expired
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"role": {
+					"name": "rootRole-circle01",
+					"expiringElections": [
+						{
+							"role": {
+								"name": "Facilitator"
+							},
+							"circle": {
+								"name": "rootRole-circle01"
+							},
+							"member": {
+								"userName": "user05"
+							},
+							"roleType": "facilitator",
+							"electionExpiration": "2000-01-01T00:00:00Z",
+							"expired": true
+						}
+					]
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query {
+				member(uid: "1699e266-8401-558e-b9f5-7e2d7f965b82") {
+					userName
+					expiringElections(withinDays: 100000) {
+						role {
+							name
+						}
+						circle {
+							name
+						}
+						roleType
+						electionExpiration
+						expired
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"member": {
+					"userName": "user05",
+					"expiringElections": [
+						{
+							"role": {
+								"name": "Facilitator"
+							},
+							"circle": {
+								"name": "rootRole-circle01"
+							},
+							"roleType": "facilitator",
+							"electionExpiration": "2000-01-01T00:00:00Z",
+							"expired": true
+						},
+						{
+							"role": {
+								"name": "Secretary"
+							},
+							"circle": {
+								"name": "rootRole-circle01"
+							},
+							"roleType": "secretary",
+							"electionExpiration": "2100-01-01T00:00:00Z",
+							"expired": false
+						}
+					]
+				}
+			}
+			`,
+		},
+	})
+}
+
+// initTensionLifecycle, on top of initBasic, assigns tension01 to user05 (a
+// rootRole-circle01 direct member) that moves it in progress and comments it.
+// It also checks the permissions of a member that isn't the tension member
+// or the tension circle lead link.
+func initTensionLifecycle(ctx context.Context, t *testing.T, rootRoleID util.ID, readDBListener readdb.ReadDBListener, commandService *command.CommandService) {
+	initBasic(ctx, t, rootRoleID, readDBListener, commandService)
+
+	tensionID, _ := unmarshalUID("3c8f4a9e-2afc-56c8-aefb-e97817511f70")
+	circleID, _ := unmarshalUID("66c0cc1f-f608-53dc-88b5-f3afd68a4d6c")
+	user02ID, _ := unmarshalUID("ky9j3Uf4PuaYA6f3uRhvM6")
+	user05ID, _ := unmarshalUID("1699e266-8401-558e-b9f5-7e2d7f965b82")
+
+	user02Ctx := context.WithValue(ctx, "userid", user02ID.String())
+	user05Ctx := context.WithValue(ctx, "userid", user05ID.String())
+
+	// user05 cannot see the tension so it cannot comment it
+	res, _, err := commandService.AddTensionComment(user05Ctx, &change.AddTensionCommentChange{TensionID: tensionID, Text: "comment00"})
+	if err != command.ErrValidation {
+		t.Fatalf("expected validation error, got: %v", err)
+	}
+	if res.GenericError.Error() != "member not authorized" {
+		t.Fatalf("unexpected error: %v", res.GenericError)
+	}
+
+	// the tension member assigns the tension
+	_, groupID, err := commandService.AssignTension(user02Ctx, &change.AssignTensionChange{TensionID: tensionID, RoleID: &circleID, MemberID: &user05ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the assignee can change the tension state and comment it
+	_, groupID, err = commandService.ChangeTensionState(user05Ctx, tensionID, models.TensionStateInProgress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, groupID, err = commandService.AddTensionComment(user05Ctx, &change.AddTensionCommentChange{TensionID: tensionID, Text: "comment01"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// but it cannot manage it
+	gres, _, err := commandService.SetTensionLabels(user05Ctx, tensionID, []string{"label01"})
+	if err != command.ErrValidation {
+		t.Fatalf("expected validation error, got: %v", err)
+	}
+	if gres.GenericError.Error() != "member not authorized" {
+		t.Fatalf("unexpected error: %v", gres.GenericError)
+	}
+}
+
+func TestTensionLifecycle(t *testing.T) {
+	RunTests(t, initTensionLifecycle, []*Test{
+		{
+			Query: `
+			query {
+				tension(uid: "3c8f4a9e-2afc-56c8-aefb-e97817511f70") {
+					state
+					closed
+					labels
+					assignee {
+						userName
+					}
+					assigneeRole {
+						name
+					}
+					comments {
+						uid
+						text
+						member {
+							userName
+						}
+						replies {
+							text
+						}
+					}
+					memberTensionPermissions {
+						manageTension
+						changeTensionState
+						comment
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"tension": {
+					"state": "inprogress",
+					"closed": false,
+					"labels": [],
+					"assignee": {
+						"userName": "user05"
+					},
+					"assigneeRole": {
+						"name": "rootRole-circle01"
+					},
+					"comments": [
+						{
+							"uid": "LUPJe6Kj8NTesDDqCpj6MQ",
+							"text": "comment01",
+							"member": {
+								"userName": "user05"
+							},
+							"replies": []
+						}
+					],
+					"memberTensionPermissions": {
+						"manageTension": true,
+						"changeTensionState": true,
+						"comment": true
+					}
+				}
+			}
+			`,
+		},
+		// reply to comment01
+		{
+			Query: `
+			mutation AddTensionComment($addTensionCommentChange: AddTensionCommentChange!) {
+				addTensionComment(addTensionCommentChange: $addTensionCommentChange) {
+					tension {
+						comments {
+							text
+							replies {
+								text
+								member {
+									userName
+								}
+								replies {
+									text
+								}
+							}
+						}
+					}
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"addTensionCommentChange": {
+					"tensionUID": "3c8f4a9e-2afc-56c8-aefb-e97817511f70",
+					"parentCommentUID": "LUPJe6Kj8NTesDDqCpj6MQ",
+					"text": "reply01"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"addTensionComment": {
+					"tension": {
+						"comments": [
+							{
+								"text": "comment01",
+								"replies": [
+									{
+										"text": "reply01",
+										"member": {
+											"userName": "admin"
+										},
+										"replies": []
+									}
+								]
+							}
+						]
+					},
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation SetTensionLabels($labels: [String!]!) {
+				setTensionLabels(tensionUID: "3c8f4a9e-2afc-56c8-aefb-e97817511f70", labels: $labels) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"labels": ["label01", "label01"]
+			}
+			`,
+			ExpectedResult: `
+			{
+				"setTensionLabels": {
+					"hasErrors": true,
+					"genericError": "duplicate label \"label01\""
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation SetTensionLabels($labels: [String!]!) {
+				setTensionLabels(tensionUID: "3c8f4a9e-2afc-56c8-aefb-e97817511f70", labels: $labels) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"labels": ["label01", "label02"]
+			}
+			`,
+			ExpectedResult: `
+			{
+				"setTensionLabels": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query {
+				tension(uid: "3c8f4a9e-2afc-56c8-aefb-e97817511f70") {
+					labels
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"tension": {
+					"labels": ["label01", "label02"]
+				}
+			}
+			`,
+		},
+		// labels are returned in the provided order, removed labels aren't returned
+		{
+			Query: `
+			mutation SetTensionLabels($labels: [String!]!) {
+				setTensionLabels(tensionUID: "3c8f4a9e-2afc-56c8-aefb-e97817511f70", labels: $labels) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"labels": ["label03", "label01"]
+			}
+			`,
+			ExpectedResult: `
+			{
+				"setTensionLabels": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query {
+				tension(uid: "3c8f4a9e-2afc-56c8-aefb-e97817511f70") {
+					labels
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"tension": {
+					"labels": ["label03", "label01"]
+				}
+			}
+			`,
+		},
+		// a removed label can be set again
+		{
+			Query: `
+			mutation SetTensionLabels($labels: [String!]!) {
+				setTensionLabels(tensionUID: "3c8f4a9e-2afc-56c8-aefb-e97817511f70", labels: $labels) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"labels": ["label01", "label02"]
+			}
+			`,
+			ExpectedResult: `
+			{
+				"setTensionLabels": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query {
+				tension(uid: "3c8f4a9e-2afc-56c8-aefb-e97817511f70") {
+					labels
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"tension": {
+					"labels": ["label01", "label02"]
+				}
+			}
+			`,
+		},
+		// top level comments are returned in creation order with their replies
+		{
+			Query: `
+			mutation AddTensionComment($addTensionCommentChange: AddTensionCommentChange!) {
+				addTensionComment(addTensionCommentChange: $addTensionCommentChange) {
+					tension {
+						comments {
+							text
+							replies {
+								text
+							}
+						}
+					}
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"addTensionCommentChange": {
+					"tensionUID": "3c8f4a9e-2afc-56c8-aefb-e97817511f70",
+					"text": "comment02"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"addTensionComment": {
+					"tension": {
+						"comments": [
+							{
+								"text": "comment01",
+								"replies": [
+									{
+										"text": "reply01"
+									}
+								]
+							},
+							{
+								"text": "comment02",
+								"replies": []
+							}
+						]
+					},
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// a tension can be closed only with closeTension
+		{
+			Query: `
+			mutation ChangeTensionState($state: TensionState!) {
+				changeTensionState(tensionUID: "3c8f4a9e-2afc-56c8-aefb-e97817511f70", state: $state) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"state": "closed"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"changeTensionState": {
+					"hasErrors": true,
+					"genericError": "wrong tension state"
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation ChangeTensionState($state: TensionState!) {
+				changeTensionState(tensionUID: "3c8f4a9e-2afc-56c8-aefb-e97817511f70", state: $state) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"state": "processed"
+			}
+			`,
+			ExpectedResult: `
+			{
+				"changeTensionState": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation CloseTension($closeTensionChange: CloseTensionChange!) {
+				closeTension(closeTensionChange: $closeTensionChange) {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			Variables: `
+			{
+				"closeTensionChange": {
+					"uid": "3c8f4a9e-2afc-56c8-aefb-e97817511f70",
+					"reason": "processed"
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"closeTension": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// a closed tension cannot be assigned
+		{
+			Query: `
+			mutation {
+				assignTension(tensionUID: "3c8f4a9e-2afc-56c8-aefb-e97817511f70") {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"assignTension": {
+					"hasErrors": true,
+					"genericError": "tension is closed"
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation {
+				reopenTension(tensionUID: "3c8f4a9e-2afc-56c8-aefb-e97817511f70") {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"reopenTension": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		// a reopened tension keeps its labels, assignee and comments
+		{
+			Query: `
+			query {
+				tension(uid: "3c8f4a9e-2afc-56c8-aefb-e97817511f70") {
+					state
+					closed
+					closeReason
+					labels
+					assignee {
+						userName
+					}
+					comments {
+						text
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"tension": {
+					"state": "open",
+					"closed": false,
+					"closeReason": "",
+					"labels": ["label01", "label02"],
+					"assignee": {
+						"userName": "user05"
+					},
+					"comments": [
+						{
+							"text": "comment01"
+						},
+						{
+							"text": "comment02"
+						}
+					]
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			mutation {
+				reopenTension(tensionUID: "3c8f4a9e-2afc-56c8-aefb-e97817511f70") {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"reopenTension": {
+					"hasErrors": true,
+					"genericError": "tension not closed"
+				}
+			}
+			`,
+		},
+		// unassign the tension
+		{
+			Query: `
+			mutation {
+				assignTension(tensionUID: "3c8f4a9e-2afc-56c8-aefb-e97817511f70") {
+					hasErrors
+					genericError
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"assignTension": {
+					"hasErrors": false,
+					"genericError": null
+				}
+			}
+			`,
+		},
+		{
+			Query: `
+			query {
+				tension(uid: "3c8f4a9e-2afc-56c8-aefb-e97817511f70") {
+					assignee {
+						userName
+					}
+					assigneeRole {
+						name
+					}
+				}
+			}
+			`,
+			ExpectedResult: `
+			{
+				"tension": {
+					"assignee": null,
+					"assigneeRole": null
+				}
+			}
+			`,
+		},
+	})
+}
@@ -1,6 +1,9 @@
 package graphql
 
 import (
+	"context"
+	"sort"
+
 	"github.com/sorintlab/sircles/change"
 	"github.com/sorintlab/sircles/dataloader"
 	"github.com/sorintlab/sircles/models"
@@ -8,6 +11,7 @@ import (
 	"github.com/sorintlab/sircles/util"
 
 	graphql "github.com/neelance/graphql-go"
+	"github.com/pkg/errors"
 )
 
 type tensionResolver struct {
@@ -50,6 +54,24 @@ func (r *tensionResolver) CloseReason() string {
 	return r.t.CloseReason
 }
 
+func (r *tensionResolver) State() string {
+	return string(r.t.State)
+}
+
+func (r *tensionResolver) Labels() ([]string, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).TensionLabels.Load(r.t.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	labels := data.([]*models.TensionLabel)
+	sort.Sort(models.TensionLabels(labels))
+	l := make([]string, len(labels))
+	for i, label := range labels {
+		l[i] = label.Name
+	}
+	return l, nil
+}
+
 func (r *tensionResolver) Member() (*memberResolver, error) {
 	data, err := r.dataLoaders.Get(r.timeLine).TensionMember.Load(r.t.ID.String())()
 	if err != nil {
@@ -62,6 +84,88 @@ func (r *tensionResolver) Member() (*memberResolver, error) {
 	return &memberResolver{r.s, member, r.timeLine, r.dataLoaders}, nil
 }
 
+func (r *tensionResolver) Proposals() (*[]*proposalResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).TensionProposals.Load(r.t.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	proposals := data.([]*models.Proposal)
+	l := make([]*proposalResolver, len(proposals))
+	for i, proposal := range proposals {
+		l[i] = &proposalResolver{r.s, proposal, r.timeLine, r.dataLoaders}
+	}
+	return &l, nil
+}
+
+func (r *tensionResolver) Comments(ctx context.Context) (*[]*tensionCommentResolver, error) {
+	tp, err := r.s.MemberTensionPermissions(ctx, r.timeLine, r.t.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !tp.Comment {
+		return nil, nil
+	}
+	data, err := r.dataLoaders.Get(r.timeLine).TensionComments.Load(r.t.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	comments := data.([]*models.TensionComment)
+	sort.Sort(models.TensionComments(comments))
+
+	// only the top level comments, the replies are returned by their parent
+	// comment
+	keys := make([]string, len(comments))
+	for i, comment := range comments {
+		keys[i] = comment.ID.String()
+	}
+	parents, errs := r.dataLoaders.Get(r.timeLine).CommentParent.LoadMany(keys)()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	l := []*tensionCommentResolver{}
+	for i, comment := range comments {
+		if parents[i] != nil {
+			continue
+		}
+		l = append(l, &tensionCommentResolver{r.s, comment, r.timeLine, r.dataLoaders})
+	}
+	return &l, nil
+}
+
+func (r *tensionResolver) Assignee() (*memberResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).TensionAssignee.Load(r.t.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	member := data.(*models.Member)
+	return &memberResolver{r.s, member, r.timeLine, r.dataLoaders}, nil
+}
+
+func (r *tensionResolver) AssigneeRole() (*roleResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).TensionAssigneeRole.Load(r.t.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	role := data.(*models.Role)
+	return &roleResolver{r.s, role, r.timeLine, r.dataLoaders}, nil
+}
+
+func (r *tensionResolver) MemberTensionPermissions(ctx context.Context) (*memberTensionPermissionsResolver, error) {
+	tp, err := r.s.MemberTensionPermissions(ctx, r.timeLine, r.t.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &memberTensionPermissionsResolver{tp}, nil
+}
+
 type createTensionResultResolver struct {
 	s        readdb.ReadDBService
 	tension  *models.Tension
@@ -126,6 +230,10 @@ func (r *updateTensionResultResolver) GenericError() *string {
 	return errorToStringP(r.res.GenericError)
 }
 
+func (r *updateTensionResultResolver) Conflict() bool {
+	return isConflictError(r.res.GenericError)
+}
+
 func (r *updateTensionResultResolver) UpdateTensionChangeErrors() *updateTensionChangeErrorsResolver {
 	return &updateTensionChangeErrorsResolver{r: r.res.UpdateTensionChangeErrors}
 }
@@ -157,3 +265,108 @@ func (r *closeTensionResultResolver) HasErrors() bool {
 func (r *closeTensionResultResolver) GenericError() *string {
 	return errorToStringP(r.res.GenericError)
 }
+
+func (r *closeTensionResultResolver) Conflict() bool {
+	return isConflictError(r.res.GenericError)
+}
+
+type addTensionCommentResultResolver struct {
+	s        readdb.ReadDBService
+	tension  *models.Tension
+	res      *change.AddTensionCommentResult
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *addTensionCommentResultResolver) Tension() *tensionResolver {
+	if r.tension == nil {
+		return nil
+	}
+	return &tensionResolver{r.s, r.tension, r.timeLine, r.dataLoaders}
+}
+
+func (r *addTensionCommentResultResolver) CommentUID() *graphql.ID {
+	if r.res.CommentID == nil {
+		return nil
+	}
+	uid := marshalUID("tensioncomment", *r.res.CommentID)
+	return &uid
+}
+
+func (r *addTensionCommentResultResolver) HasErrors() bool {
+	return r.res.HasErrors
+}
+
+func (r *addTensionCommentResultResolver) GenericError() *string {
+	return errorToStringP(r.res.GenericError)
+}
+
+type tensionCommentResolver struct {
+	s        readdb.ReadDBService
+	c        *models.TensionComment
+	timeLine util.TimeLineNumber
+
+	dataLoaders *dataloader.DataLoaders
+}
+
+func (r *tensionCommentResolver) UID() graphql.ID {
+	return marshalUID("tensioncomment", r.c.ID)
+}
+
+func (r *tensionCommentResolver) Text() string {
+	return r.c.Text
+}
+
+func (r *tensionCommentResolver) Member() (*memberResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).CommentMember.Load(r.c.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+	member := data.(*models.Member)
+	return &memberResolver{r.s, member, r.timeLine, r.dataLoaders}, nil
+}
+
+func (r *tensionCommentResolver) TimeLine(ctx context.Context) (*timeLineResolver, error) {
+	tl, err := r.s.TimeLine(ctx, r.c.TimeLine)
+	if err != nil {
+		return nil, err
+	}
+	if tl == nil {
+		return nil, errors.Errorf("timeline %d doesn't exist", r.c.TimeLine)
+	}
+	return &timeLineResolver{r.s, tl, r.dataLoaders}, nil
+}
+
+func (r *tensionCommentResolver) Replies() ([]*tensionCommentResolver, error) {
+	data, err := r.dataLoaders.Get(r.timeLine).CommentReplies.Load(r.c.ID.String())()
+	if err != nil {
+		return nil, err
+	}
+	replies := data.([]*models.TensionComment)
+	sort.Sort(models.TensionComments(replies))
+	l := make([]*tensionCommentResolver, len(replies))
+	for i, reply := range replies {
+		l[i] = &tensionCommentResolver{r.s, reply, r.timeLine, r.dataLoaders}
+	}
+	return l, nil
+}
+
+type memberTensionPermissionsResolver struct {
+	permissions *models.MemberTensionPermissions
+}
+
+func (r *memberTensionPermissionsResolver) ManageTension() bool {
+	return r.permissions.ManageTension
+}
+
+func (r *memberTensionPermissionsResolver) ChangeTensionState() bool {
+	return r.permissions.ChangeTensionState
+}
+
+func (r *memberTensionPermissionsResolver) Comment() bool {
+	return r.permissions.Comment
+}
@@ -1,6 +1,8 @@
 package change
 
 import (
+	"time"
+
 	"github.com/sorintlab/sircles/models"
 	"github.com/sorintlab/sircles/util"
 )
@@ -258,7 +260,202 @@ type CloseTensionResult struct {
 	GenericError error
 }
 
+type AddTensionCommentChange struct {
+	TensionID util.ID
+	// ParentCommentID is the replied comment
+	ParentCommentID *util.ID
+	Text            string
+}
+
+type AddTensionCommentResult struct {
+	CommentID    *util.ID
+	HasErrors    bool
+	GenericError error
+}
+
+// AssignTensionChange assigns the tension to a member filling the role. When
+// both are nil the tension is unassigned.
+type AssignTensionChange struct {
+	TensionID util.ID
+	RoleID    *util.ID
+	MemberID  *util.ID
+}
+
 type GenericResult struct {
 	HasErrors    bool
 	GenericError error
 }
+
+// ProposalChanges are the role changes, applied to the proposal circle child
+// roles, carried by a proposal
+type ProposalChanges struct {
+	CreateRoleChanges []CreateRoleChange
+	UpdateRoleChanges []UpdateRoleChange
+	DeleteRoleChanges []DeleteRoleChange
+}
+
+type CreateProposalChange struct {
+	TensionID util.ID
+	RoleID    util.ID
+	ProposalChanges
+}
+
+type CreateProposalResult struct {
+	ProposalID            *util.ID
+	HasErrors             bool
+	GenericError          error
+	ProposalChangesErrors ProposalChangesErrors
+}
+
+type UpdateProposalChange struct {
+	ID util.ID
+	ProposalChanges
+}
+
+type UpdateProposalResult struct {
+	HasErrors             bool
+	GenericError          error
+	ProposalChangesErrors ProposalChangesErrors
+}
+
+type ProposalChangesErrors struct {
+	CreateRoleChangesErrors []CreateRoleChangeErrors
+	UpdateRoleChangesErrors []UpdateRoleChangeErrors
+}
+
+type ObjectProposalChange struct {
+	ID     util.ID
+	Reason string
+}
+
+type AcceptProposalChange struct {
+	ID util.ID
+}
+
+type CreateMeetingChange struct {
+	RoleID       util.ID
+	MeetingType  models.MeetingType
+	Title        string
+	StartTime    time.Time
+	AttendeesIDs []util.ID
+}
+
+type CreateMeetingResult struct {
+	MeetingID                 *util.ID
+	HasErrors                 bool
+	GenericError              error
+	CreateMeetingChangeErrors CreateMeetingChangeErrors
+}
+
+type CreateMeetingChangeErrors struct {
+	MeetingType error
+	Title       error
+}
+
+type AddMeetingAgendaItemChange struct {
+	MeetingID util.ID
+	TensionID *util.ID
+	Title     string
+}
+
+type AddMeetingAgendaItemResult struct {
+	AgendaItemID *util.ID
+	HasErrors    bool
+	GenericError error
+}
+
+type SetMeetingAgendaItemOutcomeChange struct {
+	MeetingID    util.ID
+	AgendaItemID util.ID
+	Outcome      string
+}
+
+type CreateProjectChange struct {
+	RoleID      util.ID
+	MemberID    util.ID
+	Title       string
+	Description string
+}
+
+type CreateProjectResult struct {
+	ProjectID                 *util.ID
+	HasErrors                 bool
+	GenericError              error
+	CreateProjectChangeErrors CreateProjectChangeErrors
+}
+
+type CreateProjectChangeErrors struct {
+	Title       error
+	Description error
+}
+
+type UpdateProjectChange struct {
+	ID          util.ID
+	Title       string
+	Description string
+}
+
+type UpdateProjectResult struct {
+	HasErrors                 bool
+	GenericError              error
+	UpdateProjectChangeErrors UpdateProjectChangeErrors
+}
+
+type UpdateProjectChangeErrors struct {
+	Title       error
+	Description error
+}
+
+type AddProjectNextActionChange struct {
+	ProjectID   util.ID
+	Description string
+}
+
+type AddProjectNextActionResult struct {
+	NextActionID *util.ID
+	HasErrors    bool
+	GenericError error
+}
+
+type CreateReportItemChange struct {
+	CircleID       util.ID
+	RoleID         util.ID
+	ReportItemType models.ReportItemType
+	Description    string
+	Frequency      models.ReportFrequency
+}
+
+type CreateReportItemResult struct {
+	ReportItemID                 *util.ID
+	HasErrors                    bool
+	GenericError                 error
+	CreateReportItemChangeErrors CreateReportItemChangeErrors
+}
+
+type CreateReportItemChangeErrors struct {
+	ReportItemType error
+	Description    error
+	Frequency      error
+}
+
+type UpdateReportItemChange struct {
+	ID          util.ID
+	RoleID      util.ID
+	Description string
+}
+
+type UpdateReportItemResult struct {
+	HasErrors                    bool
+	GenericError                 error
+	UpdateReportItemChangeErrors UpdateReportItemChangeErrors
+}
+
+type UpdateReportItemChangeErrors struct {
+	Description error
+}
+
+type SetReportItemValueChange struct {
+	ReportItemID util.ID
+	Period       time.Time
+	Value        float64
+}
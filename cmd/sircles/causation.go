@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sorintlab/sircles/config"
+	"github.com/sorintlab/sircles/db"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var causationCmd = &cobra.Command{
+	Use:   "causation",
+	Short: "show the causation trees of an event or of the events in a group or timeline",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := causation(cmd, args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(-1)
+		}
+	},
+}
+
+type causationOptions struct {
+	eventID  string
+	groupID  string
+	timeLine int64
+	json     bool
+}
+
+var causationOpts causationOptions
+
+func init() {
+	causationCmd.PersistentFlags().StringVar(&causationOpts.eventID, "event-id", "", "event id")
+	causationCmd.PersistentFlags().StringVar(&causationOpts.groupID, "group-id", "", "event group id")
+	causationCmd.PersistentFlags().Int64Var(&causationOpts.timeLine, "timeline", 0, "timeline id")
+	causationCmd.PersistentFlags().BoolVar(&causationOpts.json, "json", false, "print the causation trees in json format")
+
+	rootCmd.AddCommand(causationCmd)
+}
+
+func causation(cmd *cobra.Command, args []string) error {
+	if configFile == "" {
+		return errors.New("you should provide a config file path (-c option)")
+	}
+
+	n := 0
+	for _, set := range []bool{causationOpts.eventID != "", causationOpts.groupID != "", causationOpts.timeLine != 0} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return errors.New("exactly one of --event-id, --group-id or --timeline must be provided")
+	}
+
+	c, err := config.Parse(configFile)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error parsing configuration file %s", configFile))
+	}
+
+	switch c.ReadDB.Type {
+	case db.Postgres:
+	case db.Sqlite3:
+	default:
+		return errors.Errorf("unsupported read db type: %s", c.ReadDB.Type)
+	}
+
+	readDB, err := db.NewDB(c.ReadDB.Type, c.ReadDB.ConnString)
+	if err != nil {
+		return err
+	}
+	defer readDB.Close()
+
+	ctx := context.Background()
+	var trees []*models.CausationTree
+	err = readDB.Do(func(tx *db.Tx) error {
+		s, err := readdb.NewReadDBService(tx)
+		if err != nil {
+			return err
+		}
+
+		var events []*models.Event
+		switch {
+		case causationOpts.eventID != "":
+			id, err := util.IDFromString(causationOpts.eventID)
+			if err != nil {
+				return errors.Wrapf(err, "wrong event id %q", causationOpts.eventID)
+			}
+			event, err := s.Event(ctx, id)
+			if err != nil {
+				return err
+			}
+			if event != nil {
+				events = append(events, event)
+			}
+		case causationOpts.groupID != "":
+			id, err := util.IDFromString(causationOpts.groupID)
+			if err != nil {
+				return errors.Wrapf(err, "wrong group id %q", causationOpts.groupID)
+			}
+			events, err = s.GroupEvents(ctx, id)
+			if err != nil {
+				return err
+			}
+		case causationOpts.timeLine != 0:
+			events, err = s.TimeLineEvents(ctx, util.TimeLineNumber(causationOpts.timeLine))
+			if err != nil {
+				return err
+			}
+		}
+
+		trees, err = s.CausationTrees(ctx, events)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if causationOpts.json {
+		out, err := json.MarshalIndent(trees, "", "  ")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	if len(trees) == 0 {
+		return errors.New("no events found")
+	}
+	for _, t := range trees {
+		fmt.Printf("correlation id: %s\n", t.CorrelationID)
+		for _, e := range t.Events {
+			fmt.Printf("%s%d %s %s/%s id: %s group: %s timeline: %d\n", strings.Repeat("  ", e.Depth+1), e.SequenceNumber, e.EventType, e.AggregateType, e.AggregateID, e.ID, e.GroupID, e.TimeLineID)
+		}
+	}
+	return nil
+}
@@ -1,20 +1,24 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/sorintlab/sircles/config"
-	"github.com/sorintlab/sircles/db"
 	"github.com/sorintlab/sircles/eventstore"
 	slog "github.com/sorintlab/sircles/log"
+	"github.com/sorintlab/sircles/util"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap/zapcore"
 )
 
+const (
+	dumpBatchSize = 100
+)
+
 var dumpCmd = &cobra.Command{
 	Use: "dump",
 	Run: func(cmd *cobra.Command, args []string) {
@@ -29,8 +33,19 @@ func init() {
 	rootCmd.AddCommand(dumpCmd)
 
 	dumpCmd.PersistentFlags().StringVar(&dumpFile, "dumpfile", "", "path to dump file")
+	dumpCmd.PersistentFlags().Int64Var(&dumpOpts.fromSequence, "from-sequence", 1, "dump only events starting from this sequence number")
+	dumpCmd.PersistentFlags().StringVar(&dumpOpts.category, "category", "", "dump only events of this category")
+	dumpCmd.PersistentFlags().StringVar(&dumpOpts.compression, "compression", dumpCompressionNone, "dump file compression (none, gzip, zstd)")
+}
+
+type dumpOptions struct {
+	fromSequence int64
+	category     string
+	compression  string
 }
 
+var dumpOpts dumpOptions
+
 func dump(cmd *cobra.Command, args []string) error {
 	if configFile == "" {
 		return errors.New("you should provide a config file path (-c option)")
@@ -38,7 +53,6 @@ func dump(cmd *cobra.Command, args []string) error {
 	if dumpFile == "" {
 		return errors.New("you should provide a dump file path (--dumpfile option)")
 	}
-
 	c, err := config.Parse(configFile)
 	if err != nil {
 		return errors.WithMessage(err, fmt.Sprintf("error parsing configuration file %s", configFile))
@@ -48,67 +62,146 @@ func dump(cmd *cobra.Command, args []string) error {
 		slog.SetLevel(zapcore.DebugLevel)
 	}
 
-	if c.EventStore.Type == "" {
-		return errors.New("no eventstore type specified")
-	}
-	if c.EventStore.Type != "sql" {
-		return errors.Errorf("unknown eventstore type: %q", c.EventStore.Type)
+	es, _, _, err := newEventStore(&c.EventStore)
+	if err != nil {
+		return err
 	}
-	if c.EventStore.DB.Type == "" {
-		return errors.New("no eventstore db type specified")
+
+	eventCount, err := dumpEventStore(es, dumpFile, &dumpOpts)
+	if err != nil {
+		return err
 	}
 
-	switch c.EventStore.DB.Type {
-	case db.Postgres:
-	case db.Sqlite3:
+	log.Infof("dumped %d events", eventCount)
+
+	return nil
+}
+
+// dumpEventStore writes to the dump file the eventstore events matching the
+// dump options and all the personal data keys. It returns the number of
+// dumped events.
+// The events are dumped without decoding them, so the dump contains the
+// encrypted personal data and not the decrypted one. The eventstore data
+// codec is removed.
+func dumpEventStore(es eventstore.EventStore, dumpFile string, opts *dumpOptions) (int64, error) {
+	switch opts.compression {
+	case "", dumpCompressionNone, dumpCompressionGzip, dumpCompressionZstd:
 	default:
-		return errors.Errorf("unsupported eventstore db type: %s", c.EventStore.DB.Type)
+		return 0, errors.Errorf("unknown compression %q", opts.compression)
+	}
+	if opts.fromSequence < 1 {
+		return 0, errors.New("--from-sequence must be greater than 0")
 	}
 
-	esLnType := getLNtype(&c.EventStore.DB)
-	_, esNf, err := getListenerNotifierFactories(esLnType, &c.EventStore.DB)
+	es.SetDataCodec(nil)
 
-	esDB, err := db.NewDB(c.EventStore.DB.Type, c.EventStore.DB.ConnString)
+	keys, err := es.PersonalDataKeys()
 	if err != nil {
-		return err
+		return 0, err
+	}
+	keysIDs := make([]util.ID, 0, len(keys))
+	for id := range keys {
+		keysIDs = append(keysIDs, id)
 	}
+	sort.Sort(util.IDs(keysIDs))
 
-	// Populate/migrate esdb
-	if err := esDB.Migrate("eventstore", eventstore.Migrations); err != nil {
-		return err
+	// Dump the events up to the current last sequence number so events
+	// written during the dump won't be included and the event count in the
+	// header will match the dumped events.
+	lastSequenceNumber, err := es.LastSequenceNumber()
+	if err != nil {
+		return 0, err
 	}
 
-	es := eventstore.NewEventStore(esDB, esNf)
+	var eventCount int64
+	if err := dumpEvents(es, opts, lastSequenceNumber, func(event *eventstore.StoredEvent) error {
+		eventCount++
+		return nil
+	}); err != nil {
+		return 0, err
+	}
 
 	f, err := os.Create(dumpFile)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	defer f.Close()
+
+	dw, err := newDumpWriter(f, opts.compression)
+	if err != nil {
+		return 0, err
 	}
 
-	i := int64(1)
-	for {
-		events, err := es.GetAllEvents(i, 100)
+	header := &dumpHeader{
+		FormatVersion:        dumpFormatVersion,
+		SchemaVersion:        len(eventstore.Migrations),
+		EventCount:           eventCount,
+		PersonalDataKeyCount: int64(len(keysIDs)),
+		FromSequence:         opts.fromSequence,
+		Category:             opts.category,
+	}
+	if err := dw.WriteHeader(header); err != nil {
+		return 0, err
+	}
+
+	for _, id := range keysIDs {
+		if err := dw.WritePersonalDataKey(id, keys[id]); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := dumpEvents(es, opts, lastSequenceNumber, func(event *eventstore.StoredEvent) error {
+		log.Debugf("sequencenumber: %d", event.SequenceNumber)
+		return dw.WriteEvent(event)
+	}); err != nil {
+		return 0, err
+	}
+	if dw.eventCount != eventCount {
+		return 0, errors.Errorf("dumped %d events but %d were expected", dw.eventCount, eventCount)
+	}
+
+	if err := dw.Close(); err != nil {
+		return 0, err
+	}
+	if err := f.Sync(); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return eventCount, nil
+}
+
+// dumpEvents calls fn for every event matching the dump options with a
+// sequence number not greater than lastSequenceNumber
+func dumpEvents(es eventstore.EventStore, opts *dumpOptions, lastSequenceNumber int64, fn func(event *eventstore.StoredEvent) error) error {
+	i := opts.fromSequence
+	for i <= lastSequenceNumber {
+		var events []*eventstore.StoredEvent
+		var err error
+		if opts.category != "" {
+			events, err = es.GetEventsByCategory(opts.category, i, dumpBatchSize)
+		} else {
+			events, err = es.GetAllEvents(i, dumpBatchSize)
+		}
 		if err != nil {
 			return err
 		}
 		if len(events) == 0 {
 			break
 		}
-		i += 100
 
 		for _, event := range events {
-			log.Infof("sequencenumber: %d", event.SequenceNumber)
-			eventj, err := json.Marshal(event)
-			if err != nil {
-				return errors.WithStack(err)
+			if event.SequenceNumber > lastSequenceNumber {
+				return nil
+			}
+			if err := fn(event); err != nil {
+				return err
 			}
-			f.Write(eventj)
-			f.Write([]byte("\n"))
-			log.Infof("eventj: %s", eventj)
 		}
+		i = events[len(events)-1].SequenceNumber + 1
 	}
-	f.Close()
-	f.Sync()
 
 	return nil
 }
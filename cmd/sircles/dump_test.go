@@ -0,0 +1,436 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	ln "github.com/sorintlab/sircles/listennotify"
+	"github.com/sorintlab/sircles/util"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func newTestMemoryEventStore() eventstore.EventStore {
+	return eventstore.NewMemoryEventStore(ln.NewLocalNotifierFactory(ln.NewLocalListenNotify()))
+}
+
+// writeTestEvents writes count events in two streams of two different
+// categories
+func writeTestEvents(t *testing.T, es eventstore.EventStore, count int) {
+	versions := map[string]int64{}
+	for i := 0; i < count; i++ {
+		category := "category01"
+		streamID := "stream01"
+		if i%2 == 1 {
+			category = "category02"
+			streamID = "stream02"
+		}
+		eventsData := []*eventstore.EventData{
+			{
+				ID:        util.NewFromUUID(uuid.NewV4()),
+				EventType: "eventtype01",
+				Data:      []byte(fmt.Sprintf(`{"N":%d}`, i)),
+				MetaData:  []byte(`{}`),
+			},
+		}
+		if _, err := es.WriteEvents(eventsData, category, streamID, versions[streamID]); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		versions[streamID]++
+	}
+}
+
+func allEvents(t *testing.T, es eventstore.EventStore) []*eventstore.StoredEvent {
+	events, err := es.GetAllEvents(1, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return events
+}
+
+func checkRestoredEvents(t *testing.T, expected, restored []*eventstore.StoredEvent) {
+	if len(expected) != len(restored) {
+		t.Fatalf("expected %d events, got %d events", len(expected), len(restored))
+	}
+	for i, e := range expected {
+		r := restored[i]
+		if e.ID != r.ID || e.EventType != r.EventType || e.Category != r.Category || e.StreamID != r.StreamID || e.Version != r.Version || !bytes.Equal(e.Data, r.Data) {
+			t.Fatalf("expected event %v, got event %v", e, r)
+		}
+	}
+}
+
+func TestDumpRestore(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	es := newTestMemoryEventStore()
+	writeTestEvents(t, es, 250)
+	events := allEvents(t, es)
+
+	for _, compression := range []string{dumpCompressionNone, dumpCompressionGzip, dumpCompressionZstd} {
+		t.Run(compression, func(t *testing.T) {
+			dumpFile := filepath.Join(tmpDir, "dump-"+compression)
+			count, err := dumpEventStore(es, dumpFile, &dumpOptions{fromSequence: 1, compression: compression})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if count != int64(len(events)) {
+				t.Fatalf("expected %d dumped events, got %d", len(events), count)
+			}
+
+			restoredES := newTestMemoryEventStore()
+			if err := restoreEventStore(restoredES, dumpFile, &restoreOptions{}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			checkRestoredEvents(t, events, allEvents(t, restoredES))
+
+			// a non empty eventstore requires --append or --resume
+			err = restoreEventStore(restoredES, dumpFile, &restoreOptions{})
+			if err == nil || !strings.Contains(err.Error(), "eventstore isn't empty") {
+				t.Fatalf("expected non empty eventstore error, got: %v", err)
+			}
+		})
+	}
+
+	if _, err := dumpEventStore(es, filepath.Join(tmpDir, "dump-lz4"), &dumpOptions{fromSequence: 1, compression: "lz4"}); err == nil {
+		t.Fatalf("expected unknown compression error")
+	}
+}
+
+// TestDumpChecksum checks that a dump with events, header or trailer not
+// matching isn't restored
+func TestDumpChecksum(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	es := newTestMemoryEventStore()
+	writeTestEvents(t, es, 10)
+
+	dumpFile := filepath.Join(tmpDir, "dump")
+	if _, err := dumpEventStore(es, dumpFile, &dumpOptions{fromSequence: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := ioutil.ReadFile(dumpFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		old   string
+		new   string
+		error string
+	}{
+		{
+			name:  "changed event",
+			old:   `eyJOIjo1fQ==`, // base64 of {"N":5}
+			new:   `eyJOIjo2fQ==`, // base64 of {"N":6}
+			error: "dump checksum mismatch",
+		},
+		{
+			name:  "changed header event count",
+			old:   `"EventCount":10,"PersonalDataKeyCount"`,
+			new:   `"EventCount":11,"PersonalDataKeyCount"`,
+			error: "dump header event count 11 doesn't match read events count 10",
+		},
+		{
+			name:  "changed trailer event count",
+			old:   `{"Trailer":{"EventCount":10`,
+			new:   `{"Trailer":{"EventCount":9`,
+			error: "dump trailer event count 9 doesn't match read events count 10",
+		},
+		{
+			name:  "changed trailer checksum",
+			old:   `"Checksum":"sha256:`,
+			new:   `"Checksum":"sha256:00`,
+			error: "dump checksum mismatch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !bytes.Contains(data, []byte(tt.old)) {
+				t.Fatalf("dump doesn't contain %q", tt.old)
+			}
+			changedDumpFile := filepath.Join(tmpDir, "changed")
+			if err := ioutil.WriteFile(changedDumpFile, bytes.Replace(data, []byte(tt.old), []byte(tt.new), 1), 0600); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			restoredES := newTestMemoryEventStore()
+			err := restoreEventStore(restoredES, changedDumpFile, &restoreOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Fatalf("expected error %q, got: %v", tt.error, err)
+			}
+			// nothing must be restored
+			if events := allEvents(t, restoredES); len(events) != 0 {
+				t.Fatalf("expected no restored events, got %d events", len(events))
+			}
+		})
+	}
+}
+
+func TestDumpTruncated(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	es := newTestMemoryEventStore()
+	writeTestEvents(t, es, 10)
+
+	for _, compression := range []string{dumpCompressionNone, dumpCompressionGzip, dumpCompressionZstd} {
+		t.Run(compression, func(t *testing.T) {
+			dumpFile := filepath.Join(tmpDir, "dump-"+compression)
+			if _, err := dumpEventStore(es, dumpFile, &dumpOptions{fromSequence: 1, compression: compression}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			data, err := ioutil.ReadFile(dumpFile)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// truncate the dump in the middle and just before its end
+			for _, size := range []int{len(data) / 2, len(data) - 1} {
+				truncatedDumpFile := filepath.Join(tmpDir, "truncated")
+				if err := ioutil.WriteFile(truncatedDumpFile, data[:size], 0600); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				restoredES := newTestMemoryEventStore()
+				if err := restoreEventStore(restoredES, truncatedDumpFile, &restoreOptions{}); err == nil {
+					t.Fatalf("expected error restoring dump truncated at %d/%d bytes", size, len(data))
+				}
+				if events := allEvents(t, restoredES); len(events) != 0 {
+					t.Fatalf("expected no restored events, got %d events", len(events))
+				}
+			}
+		})
+	}
+
+	// a not compressed dump truncated at a line boundary (without the
+	// trailer)
+	dumpFile := filepath.Join(tmpDir, "dump-"+dumpCompressionNone)
+	data, err := ioutil.ReadFile(dumpFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	i := bytes.LastIndex(data[:len(data)-1], []byte("\n"))
+	truncatedDumpFile := filepath.Join(tmpDir, "truncated")
+	if err := ioutil.WriteFile(truncatedDumpFile, data[:i+1], 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = restoreEventStore(newTestMemoryEventStore(), truncatedDumpFile, &restoreOptions{})
+	if err == nil || !strings.Contains(err.Error(), "missing dump trailer") {
+		t.Fatalf("expected missing dump trailer error, got: %v", err)
+	}
+}
+
+func TestRestoreResume(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	es := newTestMemoryEventStore()
+	writeTestEvents(t, es, 250)
+	events := allEvents(t, es)
+
+	dumpFile := filepath.Join(tmpDir, "dump")
+	if _, err := dumpEventStore(es, dumpFile, &dumpOptions{fromSequence: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// simulate an interrupted restore
+	restoredES := newTestMemoryEventStore()
+	if err := restoredES.RestoreEvents(events[:120]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := restoreEventStore(restoredES, dumpFile, &restoreOptions{resume: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkRestoredEvents(t, events, allEvents(t, restoredES))
+
+	// resuming a completed restore doesn't restore anything
+	if err := restoreEventStore(restoredES, dumpFile, &restoreOptions{resume: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkRestoredEvents(t, events, allEvents(t, restoredES))
+
+	// an eventstore with an event not in the dump cannot be resumed
+	otherES := newTestMemoryEventStore()
+	writeTestEvents(t, otherES, 1)
+	err = restoreEventStore(otherES, dumpFile, &restoreOptions{resume: true})
+	if err == nil || !strings.Contains(err.Error(), "cannot resume the restore") {
+		t.Fatalf("expected cannot resume error, got: %v", err)
+	}
+}
+
+func TestRestorePartialDump(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	es := newTestMemoryEventStore()
+	writeTestEvents(t, es, 20)
+	events := allEvents(t, es)
+
+	for _, opts := range []*dumpOptions{
+		{fromSequence: 11},
+		{fromSequence: 1, category: "category01"},
+	} {
+		dumpFile := filepath.Join(tmpDir, "dump")
+		if _, err := dumpEventStore(es, dumpFile, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err := restoreEventStore(newTestMemoryEventStore(), dumpFile, &restoreOptions{append: true})
+		if err == nil || !strings.Contains(err.Error(), "cannot be restored in an empty eventstore") {
+			t.Fatalf("expected partial dump error, got: %v", err)
+		}
+	}
+
+	// an incremental dump can be appended to an eventstore with the previous
+	// events
+	dumpFile := filepath.Join(tmpDir, "dump")
+	if _, err := dumpEventStore(es, dumpFile, &dumpOptions{fromSequence: 11}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restoredES := newTestMemoryEventStore()
+	if err := restoredES.RestoreEvents(events[:10]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := restoreEventStore(restoredES, dumpFile, &restoreOptions{append: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkRestoredEvents(t, events, allEvents(t, restoredES))
+}
+
+// TestDumpPersonalData checks that the dump contains the encrypted personal
+// data and the personal data keys and that they're restored
+func TestDumpPersonalData(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	newES := func() eventstore.EventStore {
+		es := newTestMemoryEventStore()
+		es.SetDataCodec(ep.NewPersonalDataCodec(es))
+		return es
+	}
+
+	es := newES()
+	membersIDs := writeTestMemberEvents(t, es, "user01", "user02")
+	// forget user02
+	if err := es.DestroyPersonalDataKey(membersIDs[1]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dumpFile := filepath.Join(tmpDir, "dump")
+	if _, err := dumpEventStore(es, dumpFile, &dumpOptions{fromSequence: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := ioutil.ReadFile(dumpFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, _, err := readDump(dumpFile, func(event *eventstore.StoredEvent) error {
+		if bytes.Contains(event.Data, []byte("user01")) {
+			t.Fatalf("expected encrypted personal data, got: %s", event.Data)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := bytes.Count(data, dumpPersonalDataKeyPrefix); n != 2 {
+		t.Fatalf("expected 2 dumped personal data keys, got %d", n)
+	}
+
+	restoredES := newES()
+	if err := restoreEventStore(restoredES, dumpFile, &restoreOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keys, err := restoredES.PersonalDataKeys()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 2 || keys[membersIDs[0]] == nil || keys[membersIDs[1]] != nil {
+		t.Fatalf("unexpected restored personal data keys: %v", keys)
+	}
+	for i, expectedUserName := range []string{"user01", ep.PersonalDataPlaceholder} {
+		var data ep.EventMemberCreated
+		if err := json.Unmarshal(allEvents(t, restoredES)[i].Data, &data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if data.UserName != expectedUserName {
+			t.Fatalf("expected user name %q, got %q", expectedUserName, data.UserName)
+		}
+	}
+
+	// a key destroyed in the dump is destroyed also when appending
+	appendES := newES()
+	if err := appendES.CopyPersonalDataKeys(map[util.ID][]byte{membersIDs[1]: bytes.Repeat([]byte{1}, eventstore.PersonalDataKeySize)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	writeTestEvents(t, appendES, 1)
+	if err := restoreEventStore(appendES, dumpFile, &restoreOptions{append: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keys, err = appendES.PersonalDataKeys()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys[membersIDs[1]] != nil {
+		t.Fatalf("expected destroyed personal data key")
+	}
+
+	// a dump key different than the eventstore one isn't restored
+	conflictES := newES()
+	if err := conflictES.CopyPersonalDataKeys(map[util.ID][]byte{membersIDs[0]: bytes.Repeat([]byte{1}, eventstore.PersonalDataKeySize)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	writeTestEvents(t, conflictES, 1)
+	err = restoreEventStore(conflictES, dumpFile, &restoreOptions{append: true})
+	if err == nil || !strings.Contains(err.Error(), "is different than the eventstore one") {
+		t.Fatalf("expected different personal data key error, got: %v", err)
+	}
+
+	// a changed personal data key is detected
+	changedDumpFile := filepath.Join(tmpDir, "changed")
+	i := bytes.Index(data, dumpPersonalDataKeyPrefix)
+	i += bytes.Index(data[i:], []byte(`"Key":"`)) + len(`"Key":"`)
+	changedData := append([]byte{}, data...)
+	if changedData[i] == 'A' {
+		changedData[i] = 'B'
+	} else {
+		changedData[i] = 'A'
+	}
+	if err := ioutil.WriteFile(changedDumpFile, changedData, 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = restoreEventStore(newES(), changedDumpFile, &restoreOptions{})
+	if err == nil || !strings.Contains(err.Error(), "dump checksum mismatch") {
+		t.Fatalf("expected checksum mismatch error, got: %v", err)
+	}
+}
@@ -0,0 +1,491 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/util"
+
+	"github.com/DataDog/zstd"
+	"github.com/pkg/errors"
+)
+
+// dumpFormatVersion is the version of the dump file format. A dump file is
+// made of a header line, one line for every personal data key, one line for
+// every event and a trailer line, every line is a json object. The events
+// are dumped as saved in the eventstore, so their personal data is encrypted
+// with the dumped keys.
+// Dump files at version 1 don't have the personal data keys and contain the
+// decrypted events. Dump files written by older versions don't have the
+// header and the trailer and contain only the event lines.
+const dumpFormatVersion = 2
+
+const (
+	dumpCompressionNone = "none"
+	dumpCompressionGzip = "gzip"
+	dumpCompressionZstd = "zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+type dumpHeader struct {
+	FormatVersion int
+	// SchemaVersion is the eventstore schema version (number of applied
+	// migrations) of the dumped eventstore
+	SchemaVersion int
+	EventCount    int64
+	// PersonalDataKeyCount is the number of dumped personal data keys (also
+	// the destroyed ones)
+	PersonalDataKeyCount int64
+	// FromSequence and Category are the options used to dump only a part of
+	// the events
+	FromSequence int64
+	Category     string `json:",omitempty"`
+}
+
+// Partial reports if the dump doesn't contain all the eventstore events
+func (h *dumpHeader) Partial() bool {
+	return h.FromSequence > 1 || h.Category != ""
+}
+
+type dumpTrailer struct {
+	EventCount int64
+	Checksum   string
+}
+
+type dumpHeaderLine struct {
+	Header *dumpHeader
+}
+
+type dumpPersonalDataKey struct {
+	ID util.ID
+	// Key is nil for a destroyed key
+	Key []byte
+}
+
+type dumpPersonalDataKeyLine struct {
+	PersonalDataKey *dumpPersonalDataKey
+}
+
+type dumpTrailerLine struct {
+	Trailer *dumpTrailer
+}
+
+var (
+	dumpHeaderPrefix          = []byte(`{"Header":`)
+	dumpPersonalDataKeyPrefix = []byte(`{"PersonalDataKey":`)
+	dumpTrailerPrefix         = []byte(`{"Trailer":`)
+)
+
+func dumpChecksum(h hash.Hash) string {
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// dumpWriter writes a dump file calculating the checksum of the written
+// personal data keys and events
+type dumpWriter struct {
+	w *bufio.Writer
+	// cw is the compression writer
+	cw io.WriteCloser
+	h  hash.Hash
+
+	eventCount           int64
+	personalDataKeyCount int64
+}
+
+func newDumpWriter(w io.Writer, compression string) (*dumpWriter, error) {
+	dw := &dumpWriter{h: sha256.New()}
+	switch compression {
+	case "", dumpCompressionNone:
+		dw.w = bufio.NewWriter(w)
+	case dumpCompressionGzip:
+		dw.cw = gzip.NewWriter(w)
+		dw.w = bufio.NewWriter(dw.cw)
+	case dumpCompressionZstd:
+		dw.cw = zstd.NewWriter(w)
+		dw.w = bufio.NewWriter(dw.cw)
+	default:
+		return nil, errors.Errorf("unknown compression %q", compression)
+	}
+	return dw, nil
+}
+
+func (dw *dumpWriter) writeLine(v interface{}) ([]byte, error) {
+	l, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	l = append(l, '\n')
+	if _, err := dw.w.Write(l); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return l, nil
+}
+
+func (dw *dumpWriter) WriteHeader(header *dumpHeader) error {
+	_, err := dw.writeLine(&dumpHeaderLine{Header: header})
+	return err
+}
+
+// WritePersonalDataKey writes a personal data key. The keys must be written
+// after the header and before the events.
+func (dw *dumpWriter) WritePersonalDataKey(id util.ID, key []byte) error {
+	l, err := dw.writeLine(&dumpPersonalDataKeyLine{PersonalDataKey: &dumpPersonalDataKey{ID: id, Key: key}})
+	if err != nil {
+		return err
+	}
+	dw.h.Write(l)
+	dw.personalDataKeyCount++
+	return nil
+}
+
+func (dw *dumpWriter) WriteEvent(event *eventstore.StoredEvent) error {
+	l, err := dw.writeLine(event)
+	if err != nil {
+		return err
+	}
+	dw.h.Write(l)
+	dw.eventCount++
+	return nil
+}
+
+// Close writes the trailer and flushes all the buffered data. It doesn't
+// close the underlying writer.
+func (dw *dumpWriter) Close() error {
+	trailer := &dumpTrailer{
+		EventCount: dw.eventCount,
+		Checksum:   dumpChecksum(dw.h),
+	}
+	if _, err := dw.writeLine(&dumpTrailerLine{Trailer: trailer}); err != nil {
+		return err
+	}
+	if err := dw.w.Flush(); err != nil {
+		return errors.WithStack(err)
+	}
+	if dw.cw != nil {
+		if err := dw.cw.Close(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// dumpReader reads a dump file, gzip and zstd compressions are automatically
+// detected.
+// The header, if present, is read at creation time.
+type dumpReader struct {
+	r *bufio.Reader
+	// cr is the decompression reader
+	cr io.ReadCloser
+	h  hash.Hash
+
+	header  *dumpHeader
+	trailer *dumpTrailer
+	// personalDataKeys are the dump personal data keys. A destroyed key has a
+	// nil value.
+	personalDataKeys map[util.ID][]byte
+
+	eventCount int64
+}
+
+func newDumpReader(r io.Reader) (*dumpReader, error) {
+	dr := &dumpReader{h: sha256.New(), personalDataKeys: map[util.ID][]byte{}}
+
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, errors.WithStack(err)
+	}
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		dr.cr, err = gzip.NewReader(br)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		br = bufio.NewReader(dr.cr)
+	case bytes.Equal(magic, zstdMagic):
+		dr.cr = zstd.NewReader(&zstdFrameChecker{r: br, need: len(zstdMagic)})
+		br = bufio.NewReader(dr.cr)
+	}
+	dr.r = br
+
+	l, err := dr.r.Peek(len(dumpHeaderPrefix))
+	if err != nil && err != io.EOF {
+		return nil, errors.WithStack(err)
+	}
+	// compressed dumps always have an header (legacy dumps were never
+	// compressed), a missing header means a truncated or corrupted dump
+	if dr.cr != nil && !bytes.Equal(l, dumpHeaderPrefix) {
+		return nil, errors.New("missing dump header in compressed dump, dump file is truncated or corrupted")
+	}
+	if bytes.Equal(l, dumpHeaderPrefix) {
+		line, err := dr.readLine()
+		if err != nil {
+			return nil, err
+		}
+		var hl dumpHeaderLine
+		if err := json.Unmarshal(line, &hl); err != nil {
+			return nil, errors.Wrap(err, "failed to decode dump header")
+		}
+		if hl.Header.FormatVersion > dumpFormatVersion {
+			return nil, errors.Errorf("unsupported dump format version %d", hl.Header.FormatVersion)
+		}
+		dr.header = hl.Header
+
+		if err := dr.readPersonalDataKeys(); err != nil {
+			return nil, err
+		}
+	}
+
+	return dr, nil
+}
+
+// readPersonalDataKeys reads the personal data keys following the header
+func (dr *dumpReader) readPersonalDataKeys() error {
+	for {
+		l, err := dr.r.Peek(len(dumpPersonalDataKeyPrefix))
+		if err != nil && err != io.EOF {
+			return errors.WithStack(err)
+		}
+		if !bytes.Equal(l, dumpPersonalDataKeyPrefix) {
+			return nil
+		}
+		line, err := dr.readLine()
+		if err != nil {
+			return err
+		}
+		var kl dumpPersonalDataKeyLine
+		if err := json.Unmarshal(line, &kl); err != nil {
+			return errors.Wrap(err, "failed to decode dump personal data key")
+		}
+		dr.h.Write(line)
+		dr.personalDataKeys[kl.PersonalDataKey.ID] = kl.PersonalDataKey.Key
+	}
+}
+
+func (dr *dumpReader) readLine() ([]byte, error) {
+	line, err := dr.r.ReadBytes('\n')
+	if err == io.EOF {
+		if len(line) == 0 {
+			return nil, io.EOF
+		}
+		// last line without newline (legacy dumps always end with a
+		// newline, so this is a truncated dump)
+		return nil, errors.New("truncated dump file")
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return line, nil
+}
+
+// Header returns the dump header, nil if the dump doesn't have an header
+// (legacy dump).
+func (dr *dumpReader) Header() *dumpHeader {
+	return dr.header
+}
+
+// PersonalDataKeys returns the dump personal data keys. A destroyed key has a
+// nil value.
+func (dr *dumpReader) PersonalDataKeys() map[util.ID][]byte {
+	return dr.personalDataKeys
+}
+
+// Next returns the next event. At the end of the dump it returns io.EOF after
+// validating the trailer against the read events.
+func (dr *dumpReader) Next() (*eventstore.StoredEvent, error) {
+	if dr.trailer != nil {
+		return nil, io.EOF
+	}
+	line, err := dr.readLine()
+	if err == io.EOF {
+		if dr.header != nil {
+			return nil, errors.New("missing dump trailer, the dump is truncated")
+		}
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if dr.header != nil && bytes.HasPrefix(line, dumpTrailerPrefix) {
+		var tl dumpTrailerLine
+		if err := json.Unmarshal(line, &tl); err != nil {
+			return nil, errors.Wrap(err, "failed to decode dump trailer")
+		}
+		dr.trailer = tl.Trailer
+		if err := dr.validate(); err != nil {
+			return nil, err
+		}
+		if _, err := dr.readLine(); err != io.EOF {
+			return nil, errors.New("unexpected data after dump trailer")
+		}
+		return nil, io.EOF
+	}
+
+	var event *eventstore.StoredEvent
+	if err := json.Unmarshal(line, &event); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode event at line %d", int64(len(dr.personalDataKeys))+dr.eventCount+2)
+	}
+	dr.h.Write(line)
+	dr.eventCount++
+	return event, nil
+}
+
+func (dr *dumpReader) validate() error {
+	if dr.trailer.EventCount != dr.eventCount {
+		return errors.Errorf("dump trailer event count %d doesn't match read events count %d", dr.trailer.EventCount, dr.eventCount)
+	}
+	if dr.header.EventCount != dr.eventCount {
+		return errors.Errorf("dump header event count %d doesn't match read events count %d", dr.header.EventCount, dr.eventCount)
+	}
+	if dr.header.PersonalDataKeyCount != int64(len(dr.personalDataKeys)) {
+		return errors.Errorf("dump header personal data key count %d doesn't match read personal data keys count %d", dr.header.PersonalDataKeyCount, len(dr.personalDataKeys))
+	}
+	if checksum := dumpChecksum(dr.h); dr.trailer.Checksum != checksum {
+		return errors.Errorf("dump checksum mismatch: trailer checksum %q, calculated checksum %q", dr.trailer.Checksum, checksum)
+	}
+	return nil
+}
+
+func (dr *dumpReader) Close() error {
+	if dr.cr != nil {
+		return dr.cr.Close()
+	}
+	return nil
+}
+
+const (
+	zstdStateMagic = iota
+	zstdStateSkippableSize
+	zstdStateFrameHeader
+	zstdStateBlockHeader
+)
+
+// zstdFrameChecker wraps the compressed zstd stream and follows its frames
+// structure returning io.ErrUnexpectedEOF when the stream ends inside a
+// frame. This is needed since the zstd reader doesn't report incomplete
+// frames and just returns io.EOF.
+type zstdFrameChecker struct {
+	r io.Reader
+
+	state int
+	// hdr contains the bytes of the header being read and need its length
+	hdr  []byte
+	need int
+	// skip is the number of bytes to skip before reading the next header
+	skip     uint64
+	checksum bool
+}
+
+func (c *zstdFrameChecker) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if perr := c.process(p[:n]); perr != nil {
+		return n, perr
+	}
+	if err == io.EOF && (c.state != zstdStateMagic || len(c.hdr) != 0 || c.skip != 0) {
+		return n, io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (c *zstdFrameChecker) process(p []byte) error {
+	for len(p) > 0 {
+		if c.skip > 0 {
+			n := uint64(len(p))
+			if n > c.skip {
+				n = c.skip
+			}
+			c.skip -= n
+			p = p[n:]
+			continue
+		}
+		n := c.need - len(c.hdr)
+		if n > len(p) {
+			n = len(p)
+		}
+		c.hdr = append(c.hdr, p[:n]...)
+		p = p[n:]
+		if len(c.hdr) < c.need {
+			continue
+		}
+		if err := c.parseHeader(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseHeader parses the completely read header and sets the bytes to skip
+// and the next header to read
+func (c *zstdFrameChecker) parseHeader() error {
+	hdr := c.hdr
+	c.hdr = c.hdr[:0]
+	switch c.state {
+	case zstdStateMagic:
+		switch {
+		case bytes.Equal(hdr, zstdMagic):
+			// read the frame header descriptor
+			c.state = zstdStateFrameHeader
+			c.need = 1
+		case hdr[0]&0xf0 == 0x50 && bytes.Equal(hdr[1:], []byte{0x2a, 0x4d, 0x18}):
+			c.state = zstdStateSkippableSize
+			c.need = 4
+		default:
+			return errors.New("invalid zstd frame magic number")
+		}
+	case zstdStateSkippableSize:
+		c.skip = uint64(binary.LittleEndian.Uint32(hdr))
+		c.state = zstdStateMagic
+		c.need = len(zstdMagic)
+	case zstdStateFrameHeader:
+		d := hdr[0]
+		singleSegment := d&0x20 != 0
+		c.checksum = d&0x04 != 0
+		// skip the window descriptor, the dictionary id and the frame
+		// content size
+		if !singleSegment {
+			c.skip++
+		}
+		c.skip += []uint64{0, 1, 2, 4}[d&0x03]
+		fcsSizes := []uint64{0, 2, 4, 8}
+		if singleSegment {
+			fcsSizes[0] = 1
+		}
+		c.skip += fcsSizes[d>>6]
+		c.state = zstdStateBlockHeader
+		c.need = 3
+	case zstdStateBlockHeader:
+		h := uint32(hdr[0]) | uint32(hdr[1])<<8 | uint32(hdr[2])<<16
+		last := h&0x01 != 0
+		switch (h >> 1) & 0x03 {
+		case 0, 2:
+			// raw and compressed blocks
+			c.skip = uint64(h >> 3)
+		case 1:
+			// rle block
+			c.skip = 1
+		default:
+			return errors.New("invalid zstd block type")
+		}
+		if last {
+			if c.checksum {
+				c.skip += 4
+			}
+			c.state = zstdStateMagic
+			c.need = len(zstdMagic)
+		}
+	}
+	return nil
+}
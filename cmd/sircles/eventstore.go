@@ -0,0 +1,89 @@
+package main
+
+import (
+	"github.com/sorintlab/sircles/config"
+	"github.com/sorintlab/sircles/db"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	ln "github.com/sorintlab/sircles/listennotify"
+	"github.com/sorintlab/sircles/lock"
+
+	"github.com/pkg/errors"
+)
+
+// newEventStore creates the eventstore defined in the provided config. It also
+// returns the listener factory used to receive the eventstore notifications
+// and the event handlers lock factory related to the eventstore type.
+func newEventStore(c *config.EventStore) (eventstore.EventStore, ln.ListenerFactory, lock.LockFactory, error) {
+	var es eventstore.EventStore
+	var lf ln.ListenerFactory
+	var lkf lock.LockFactory
+
+	switch c.Type {
+	case "sql":
+		if c.DB.Type == "" {
+			return nil, nil, nil, errors.New("no eventstore db type specified")
+		}
+		switch c.DB.Type {
+		case db.Postgres:
+		case db.Sqlite3:
+		default:
+			return nil, nil, nil, errors.Errorf("unsupported eventstore db type: %s", c.DB.Type)
+		}
+
+		esLnType := getLNtype(&c.DB)
+		var nf ln.NotifierFactory
+		var err error
+		lf, nf, err = getListenerNotifierFactories(esLnType, &c.DB)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		esDB, err := db.NewDB(c.DB.Type, c.DB.ConnString)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		// Populate/migrate esdb
+		if err := esDB.Migrate("eventstore", eventstore.Migrations); err != nil {
+			return nil, nil, nil, err
+		}
+
+		lkf, err = getLockFactory(&c.DB, esDB)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		es = eventstore.NewSQLEventStore(esDB, nf)
+
+	case "memory", "file":
+		localln := ln.NewLocalListenNotify()
+		lf = ln.NewLocalListenerFactory(localln)
+		nf := ln.NewLocalNotifierFactory(localln)
+		lkf = lock.NewLocalLockFactory(lock.NewLocalLocks())
+
+		if c.Type == "memory" {
+			log.Warnf("using a memory eventstore, all the data will be lost at exit")
+			es = eventstore.NewMemoryEventStore(nf)
+			break
+		}
+
+		if c.Path == "" {
+			return nil, nil, nil, errors.New("no eventstore path specified")
+		}
+		var err error
+		es, err = eventstore.NewFileEventStore(c.Path, nf)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+	case "":
+		return nil, nil, nil, errors.New("no eventstore type specified")
+	default:
+		return nil, nil, nil, errors.Errorf("unknown eventstore type: %q", c.Type)
+	}
+
+	es.SetDataCodec(ep.NewPersonalDataCodec(es))
+
+	return es, lf, lkf, nil
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/sorintlab/sircles/config"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/lock"
+	slog "github.com/sorintlab/sircles/log"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap/zapcore"
+)
+
+var migrateStoreCmd = &cobra.Command{
+	Use:   "migrate-store",
+	Short: "copy the events, stream versions, snapshots, personal data keys and command audit log to another eventstore",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := migrateStore(cmd, args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(-1)
+		}
+	},
+}
+
+type migrateStoreOptions struct {
+	destConfigFile string
+	batchSize      uint64
+}
+
+var migrateStoreOpts migrateStoreOptions
+
+func init() {
+	rootCmd.AddCommand(migrateStoreCmd)
+
+	migrateStoreCmd.PersistentFlags().StringVar(&migrateStoreOpts.destConfigFile, "dest-config", "", "path to the configuration file defining the destination eventstore")
+	migrateStoreCmd.PersistentFlags().Uint64Var(&migrateStoreOpts.batchSize, "batch-size", 1000, "number of events or stream versions copied in a single transaction")
+}
+
+// openEventStore opens the eventstore defined in the provided config file. It
+// also returns the event handlers lock factory related to the eventstore.
+func openEventStore(configFile string) (eventstore.EventStore, lock.LockFactory, error) {
+	c, err := config.Parse(configFile)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, fmt.Sprintf("error parsing configuration file %s", configFile))
+	}
+
+	if c.Debug {
+		slog.SetLevel(zapcore.DebugLevel)
+	}
+
+	es, _, lkf, err := newEventStore(&c.EventStore)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return es, lkf, nil
+}
+
+func migrateStore(cmd *cobra.Command, args []string) error {
+	if configFile == "" {
+		return errors.New("you should provide a config file path (-c option)")
+	}
+	if migrateStoreOpts.destConfigFile == "" {
+		return errors.New("you should provide a destination config file path (--dest-config option)")
+	}
+	if migrateStoreOpts.batchSize < 1 {
+		return errors.New("--batch-size must be greater than 0")
+	}
+
+	srcES, _, err := openEventStore(configFile)
+	if err != nil {
+		return errors.WithMessage(err, "failed to open source eventstore")
+	}
+	dstES, _, err := openEventStore(migrateStoreOpts.destConfigFile)
+	if err != nil {
+		return errors.WithMessage(err, "failed to open destination eventstore")
+	}
+
+	return migrateEventStore(srcES, dstES, migrateStoreOpts.batchSize)
+}
+
+// migrateEventStore copies to the empty destination eventstore the source
+// eventstore events, stream versions, snapshots, personal data keys (also the
+// destroyed ones) and command audit log.
+//
+// The events are read and written without decoding and encoding them, so
+// they're copied as is: the personal data encrypted in the source eventstore
+// is copied together with its keys and the events written before the personal
+// data encryption are kept in clear.
+//
+// The source eventstore must not be written during the migration: if its last
+// sequence number changes the migration fails.
+func migrateEventStore(srcES, dstES eventstore.EventStore, batchSize uint64) error {
+	if err := checkEmptyEventStore(dstES); err != nil {
+		return errors.WithMessage(err, "destination eventstore isn't empty")
+	}
+
+	srcES.SetDataCodec(nil)
+	dstES.SetDataCodec(nil)
+
+	// copy the personal data keys before the events
+	keys, err := srcES.PersonalDataKeys()
+	if err != nil {
+		return err
+	}
+	if err := dstES.CopyPersonalDataKeys(keys); err != nil {
+		return err
+	}
+	log.Infof("copied %d personal data keys", len(keys))
+
+	lastSequenceNumber, err := srcES.LastSequenceNumber()
+	if err != nil {
+		return err
+	}
+
+	// copy events
+	var eventsCount int64
+	i := int64(1)
+	for i <= lastSequenceNumber {
+		events, err := srcES.GetAllEvents(i, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			break
+		}
+		if err := dstES.CopyEvents(events); err != nil {
+			return err
+		}
+		eventsCount += int64(len(events))
+		i = events[len(events)-1].SequenceNumber + 1
+		log.Infof("copied events up to sequence number %d/%d", i-1, lastSequenceNumber)
+	}
+
+	// copy stream versions
+	var streamVersionsCount int64
+	afterStreamID := ""
+	for {
+		svs, err := srcES.GetStreamVersions(afterStreamID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(svs) == 0 {
+			break
+		}
+		if err := dstES.CopyStreamVersions(svs); err != nil {
+			return err
+		}
+		streamVersionsCount += int64(len(svs))
+		afterStreamID = svs[len(svs)-1].StreamID
+	}
+	log.Infof("copied %d events and %d stream versions", eventsCount, streamVersionsCount)
+
+	// copy snapshots
+	var snapshotsCount int64
+	afterStreamID = ""
+	for {
+		snapshots, err := srcES.GetSnapshots(afterStreamID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(snapshots) == 0 {
+			break
+		}
+		for _, snapshot := range snapshots {
+			if err := dstES.WriteSnapshot(snapshot); err != nil {
+				return err
+			}
+		}
+		snapshotsCount += int64(len(snapshots))
+		afterStreamID = snapshots[len(snapshots)-1].StreamID
+	}
+	log.Infof("copied %d snapshots", snapshotsCount)
+
+	// copy command audit log
+	var commandAuditCount int64
+	var afterSequenceNumber int64
+	for {
+		entries, err := srcES.GetCommandAuditEntries(afterSequenceNumber, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			break
+		}
+		if err := dstES.CopyCommandAuditEntries(entries); err != nil {
+			return err
+		}
+		commandAuditCount += int64(len(entries))
+		afterSequenceNumber = entries[len(entries)-1].SequenceNumber
+	}
+	log.Infof("copied %d command audit entries", commandAuditCount)
+
+	curLastSequenceNumber, err := srcES.LastSequenceNumber()
+	if err != nil {
+		return err
+	}
+	if curLastSequenceNumber != lastSequenceNumber {
+		return errors.Errorf("source eventstore written during the migration (last sequence number changed from %d to %d), stop all the sircles instances using it before migrating", lastSequenceNumber, curLastSequenceNumber)
+	}
+
+	if err := verifyMigratedStore(srcES, dstES, batchSize); err != nil {
+		return errors.WithMessage(err, "migration verification failed")
+	}
+	log.Infof("migration verified")
+
+	return nil
+}
+
+// checkEmptyEventStore returns an error if the eventstore contains events,
+// stream versions, snapshots, personal data keys or command audit entries
+func checkEmptyEventStore(es eventstore.EventStore) error {
+	eventsCount, err := es.EventsCount()
+	if err != nil {
+		return err
+	}
+	if eventsCount > 0 {
+		return errors.New("eventstore contains events")
+	}
+	svs, err := es.GetStreamVersions("", 1)
+	if err != nil {
+		return err
+	}
+	if len(svs) > 0 {
+		return errors.New("eventstore contains stream versions")
+	}
+	snapshots, err := es.GetSnapshots("", 1)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) > 0 {
+		return errors.New("eventstore contains snapshots")
+	}
+	keys, err := es.PersonalDataKeys()
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return errors.New("eventstore contains personal data keys")
+	}
+	entries, err := es.GetCommandAuditEntries(0, 1)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return errors.New("eventstore contains command audit entries")
+	}
+	return nil
+}
+
+// verifyMigratedStore checks that the source and destination eventstores have
+// the same events count, last sequence number, stream versions, personal data
+// keys, snapshots and command audit entries
+func verifyMigratedStore(srcES, dstES eventstore.EventStore, batchSize uint64) error {
+	srcEventsCount, err := srcES.EventsCount()
+	if err != nil {
+		return err
+	}
+	dstEventsCount, err := dstES.EventsCount()
+	if err != nil {
+		return err
+	}
+	if srcEventsCount != dstEventsCount {
+		return errors.Errorf("source events count %d different than destination events count %d", srcEventsCount, dstEventsCount)
+	}
+
+	srcLastSequenceNumber, err := srcES.LastSequenceNumber()
+	if err != nil {
+		return err
+	}
+	dstLastSequenceNumber, err := dstES.LastSequenceNumber()
+	if err != nil {
+		return err
+	}
+	if srcLastSequenceNumber != dstLastSequenceNumber {
+		return errors.Errorf("source last sequence number %d different than destination last sequence number %d", srcLastSequenceNumber, dstLastSequenceNumber)
+	}
+
+	// both stream versions lists are ordered by stream id
+	afterStreamID := ""
+	for {
+		srcSvs, err := srcES.GetStreamVersions(afterStreamID, batchSize)
+		if err != nil {
+			return err
+		}
+		dstSvs, err := dstES.GetStreamVersions(afterStreamID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(srcSvs) != len(dstSvs) {
+			return errors.Errorf("source and destination stream versions count differ")
+		}
+		if len(srcSvs) == 0 {
+			break
+		}
+		for i, srcSv := range srcSvs {
+			dstSv := dstSvs[i]
+			if *srcSv != *dstSv {
+				return errors.Errorf("source stream %q version %d different than destination stream %q version %d", srcSv.StreamID, srcSv.Version, dstSv.StreamID, dstSv.Version)
+			}
+		}
+		afterStreamID = srcSvs[len(srcSvs)-1].StreamID
+	}
+
+	srcKeys, err := srcES.PersonalDataKeys()
+	if err != nil {
+		return err
+	}
+	dstKeys, err := dstES.PersonalDataKeys()
+	if err != nil {
+		return err
+	}
+	if len(srcKeys) != len(dstKeys) {
+		return errors.Errorf("source personal data keys count %d different than destination personal data keys count %d", len(srcKeys), len(dstKeys))
+	}
+	for id, srcKey := range srcKeys {
+		dstKey, ok := dstKeys[id]
+		if !ok || !bytes.Equal(srcKey, dstKey) || (srcKey == nil) != (dstKey == nil) {
+			return errors.Errorf("source and destination personal data key %s differ", id)
+		}
+	}
+
+	afterStreamID = ""
+	for {
+		srcSnapshots, err := srcES.GetSnapshots(afterStreamID, batchSize)
+		if err != nil {
+			return err
+		}
+		dstSnapshots, err := dstES.GetSnapshots(afterStreamID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(srcSnapshots) != len(dstSnapshots) {
+			return errors.Errorf("source and destination snapshots count differ")
+		}
+		if len(srcSnapshots) == 0 {
+			break
+		}
+		for i, srcSnapshot := range srcSnapshots {
+			dstSnapshot := dstSnapshots[i]
+			if srcSnapshot.StreamID != dstSnapshot.StreamID || srcSnapshot.Version != dstSnapshot.Version {
+				return errors.Errorf("source stream %q snapshot version %d different than destination stream %q snapshot version %d", srcSnapshot.StreamID, srcSnapshot.Version, dstSnapshot.StreamID, dstSnapshot.Version)
+			}
+		}
+		afterStreamID = srcSnapshots[len(srcSnapshots)-1].StreamID
+	}
+
+	var afterSequenceNumber int64
+	for {
+		srcEntries, err := srcES.GetCommandAuditEntries(afterSequenceNumber, batchSize)
+		if err != nil {
+			return err
+		}
+		dstEntries, err := dstES.GetCommandAuditEntries(afterSequenceNumber, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(srcEntries) != len(dstEntries) {
+			return errors.Errorf("source and destination command audit entries count differ")
+		}
+		if len(srcEntries) == 0 {
+			break
+		}
+		for i, srcEntry := range srcEntries {
+			if srcEntry.SequenceNumber != dstEntries[i].SequenceNumber {
+				return errors.Errorf("source command audit entry sequence number %d different than destination command audit entry sequence number %d", srcEntry.SequenceNumber, dstEntries[i].SequenceNumber)
+			}
+		}
+		afterSequenceNumber = srcEntries[len(srcEntries)-1].SequenceNumber
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sorintlab/sircles/config"
+	"github.com/sorintlab/sircles/db"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/util"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func newTestConfigEventStore(t *testing.T, c *config.EventStore) eventstore.EventStore {
+	es, _, _, err := newEventStore(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return es
+}
+
+// writeTestMemberEvents writes, for every provided member, a member created
+// event with personal data encrypted by the eventstore data codec
+func writeTestMemberEvents(t *testing.T, es eventstore.EventStore, userNames ...string) []util.ID {
+	membersIDs := []util.ID{}
+	for _, userName := range userNames {
+		memberID := util.NewFromUUID(uuid.NewV4())
+		data, err := json.Marshal(&ep.EventMemberCreated{UserName: userName, FullName: userName, Email: userName + "@example.com"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		eventsData := []*eventstore.EventData{
+			{
+				ID:        util.NewFromUUID(uuid.NewV4()),
+				EventType: string(ep.EventTypeMemberCreated),
+				Data:      data,
+				MetaData:  []byte(`{}`),
+			},
+		}
+		if _, err := es.WriteEvents(eventsData, "member", memberID.String(), 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		membersIDs = append(membersIDs, memberID)
+	}
+	return membersIDs
+}
+
+// TestMigrateStore migrates a sql eventstore to a file eventstore and then to
+// another sql eventstore checking that all the data is copied as is
+func TestMigrateStore(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	srcES := newTestConfigEventStore(t, &config.EventStore{Type: "sql", DB: config.DB{Type: db.Sqlite3, ConnString: filepath.Join(tmpDir, "src.db")}})
+	membersIDs := writeTestMemberEvents(t, srcES, "user01", "user02")
+	writeTestEvents(t, srcES, 10)
+	// a member created event written before the personal data encryption
+	srcES.SetDataCodec(nil)
+	writeTestMemberEvents(t, srcES, "user03")
+	// forget user02
+	if err := srcES.DestroyPersonalDataKey(membersIDs[1]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := srcES.WriteSnapshot(&eventstore.Snapshot{Category: "category01", StreamID: "stream01", Version: 3, Data: []byte("snapshot01")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, commandType := range []string{"command01", "command02"} {
+		if err := srcES.WriteCommandAudit(&eventstore.CommandAuditEntry{CommandType: commandType, Result: eventstore.CommandAuditResultExecuted}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	fileES := newTestConfigEventStore(t, &config.EventStore{Type: "file", Path: filepath.Join(tmpDir, "file")})
+	defer fileES.(*eventstore.FileEventStore).Close()
+	if err := migrateEventStore(srcES, fileES, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dstES := newTestConfigEventStore(t, &config.EventStore{Type: "sql", DB: config.DB{Type: db.Sqlite3, ConnString: filepath.Join(tmpDir, "dst.db")}})
+	if err := migrateEventStore(fileES, dstES, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the destination must be empty
+	err = migrateEventStore(srcES, dstES, 3)
+	if err == nil || !strings.Contains(err.Error(), "destination eventstore isn't empty") {
+		t.Fatalf("expected destination eventstore isn't empty error, got: %v", err)
+	}
+
+	// compare the raw events
+	srcES.SetDataCodec(nil)
+	dstES.SetDataCodec(nil)
+	srcEvents := allEvents(t, srcES)
+	dstEvents := allEvents(t, dstES)
+	if len(srcEvents) != len(dstEvents) {
+		t.Fatalf("expected %d events, got %d events", len(srcEvents), len(dstEvents))
+	}
+	for i, se := range srcEvents {
+		de := dstEvents[i]
+		if se.ID != de.ID || se.SequenceNumber != de.SequenceNumber || se.Version != de.Version || !se.Timestamp.Equal(de.Timestamp) || !bytes.Equal(se.Data, de.Data) || !bytes.Equal(se.MetaData, de.MetaData) {
+			t.Fatalf("expected event %+v, got event %+v", se, de)
+		}
+	}
+	// the personal data is still encrypted
+	if bytes.Contains(dstEvents[0].Data, []byte("user01")) {
+		t.Fatalf("expected encrypted personal data, got: %s", dstEvents[0].Data)
+	}
+	// the event written before the personal data encryption is still in clear
+	if !bytes.Contains(dstEvents[len(dstEvents)-1].Data, []byte("user03")) {
+		t.Fatalf("expected not encrypted personal data, got: %s", dstEvents[len(dstEvents)-1].Data)
+	}
+
+	// the keys are copied with the destroyed key tombstone
+	srcKeys, err := srcES.PersonalDataKeys()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dstKeys, err := dstES.PersonalDataKeys()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dstKeys) != 2 {
+		t.Fatalf("expected 2 personal data keys, got %d keys", len(dstKeys))
+	}
+	if !bytes.Equal(srcKeys[membersIDs[0]], dstKeys[membersIDs[0]]) || dstKeys[membersIDs[0]] == nil {
+		t.Fatalf("expected the same personal data key for member %s", membersIDs[0])
+	}
+	if key, ok := dstKeys[membersIDs[1]]; !ok || key != nil {
+		t.Fatalf("expected a destroyed personal data key for member %s", membersIDs[1])
+	}
+
+	// the personal data is decoded with the copied keys
+	dstES.SetDataCodec(ep.NewPersonalDataCodec(dstES))
+	dstEvents = allEvents(t, dstES)
+	for i, expectedUserName := range []string{"user01", ep.PersonalDataPlaceholder} {
+		var data ep.EventMemberCreated
+		if err := json.Unmarshal(dstEvents[i].Data, &data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if data.UserName != expectedUserName {
+			t.Fatalf("expected user name %q, got %q", expectedUserName, data.UserName)
+		}
+	}
+
+	// the file eventstore doesn't save the snapshots but it keeps them in
+	// memory so they're migrated from it
+	snapshot, err := dstES.GetSnapshot("stream01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot == nil || snapshot.Version != 3 || string(snapshot.Data) != "snapshot01" {
+		t.Fatalf("unexpected snapshot: %+v", snapshot)
+	}
+
+	entries, err := dstES.GetCommandAuditEntries(0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	srcEntries, err := srcES.GetCommandAuditEntries(0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 command audit entries, got %d entries", len(entries))
+	}
+	for i, e := range entries {
+		se := srcEntries[i]
+		if e.SequenceNumber != se.SequenceNumber || e.CommandType != se.CommandType || !e.Timestamp.Equal(se.Timestamp) {
+			t.Fatalf("expected command audit entry %+v, got %+v", se, e)
+		}
+	}
+	// new command audit entries continue the copied sequence
+	if err := dstES.WriteCommandAudit(&eventstore.CommandAuditEntry{CommandType: "command03", Result: eventstore.CommandAuditResultExecuted}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, err = dstES.GetCommandAuditEntries(2, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].SequenceNumber != 3 {
+		t.Fatalf("expected a command audit entry with sequence number 3, got %+v", entries)
+	}
+}
+
+// writingEventStore is an eventstore that writes a new event when its stream
+// versions are read, like a sircles instance writing it during a migration
+type writingEventStore struct {
+	eventstore.EventStore
+	t       *testing.T
+	written bool
+}
+
+func (s *writingEventStore) GetStreamVersions(afterStreamID string, count uint64) ([]*eventstore.StreamVersion, error) {
+	if !s.written {
+		s.written = true
+		writeTestMemberEvents(s.t, s.EventStore, "user02")
+	}
+	return s.EventStore.GetStreamVersions(afterStreamID, count)
+}
+
+func TestMigrateStoreSourceWritten(t *testing.T) {
+	srcES := newTestMemoryEventStore()
+	writeTestMemberEvents(t, srcES, "user01")
+	dstES := newTestMemoryEventStore()
+
+	err := migrateEventStore(&writingEventStore{EventStore: srcES, t: t}, dstES, 3)
+	if err == nil || !strings.Contains(err.Error(), "source eventstore written during the migration") {
+		t.Fatalf("expected source eventstore written during the migration error, got: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/sorintlab/sircles/config"
+	"github.com/sorintlab/sircles/db"
+	slog "github.com/sorintlab/sircles/log"
+	"github.com/sorintlab/sircles/readdb"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap/zapcore"
+)
+
+var rebuildReadDBCmd = &cobra.Command{
+	Use:   "rebuild-readdb",
+	Short: "rebuild the readdb from the eventstore",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := rebuildReadDB(cmd, args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(-1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rebuildReadDBCmd)
+}
+
+func rebuildReadDB(cmd *cobra.Command, args []string) error {
+	if configFile == "" {
+		return errors.New("you should provide a config file path (-c option)")
+	}
+
+	c, err := config.Parse(configFile)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error parsing configuration file %s", configFile))
+	}
+
+	if c.Debug {
+		slog.SetLevel(zapcore.DebugLevel)
+	}
+
+	if c.ReadDB.Type == "" {
+		return errors.New("no read db type specified")
+	}
+
+	switch c.ReadDB.Type {
+	case db.Postgres:
+	case db.Sqlite3:
+	default:
+		return errors.Errorf("unsupported read db type: %s", c.ReadDB.Type)
+	}
+
+	es, lkf, err := openEventStore(configFile)
+	if err != nil {
+		return err
+	}
+
+	readDB, err := db.NewDB(c.ReadDB.Type, c.ReadDB.ConnString)
+	if err != nil {
+		return err
+	}
+
+	// Populate/migrate readdb
+	if err := readDB.Migrate("readdb", readdb.Migrations); err != nil {
+		return err
+	}
+
+	dataDir := c.DataDir
+	if dataDir == "" {
+		dataDir, err = ioutil.TempDir("", "")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dataDir)
+	} else {
+		if err := os.MkdirAll(dataDir, 0700); err != nil {
+			return errors.Wrapf(err, "failed to create data dir %q", dataDir)
+		}
+	}
+
+	_, readDBNf, err := getListenerNotifierFactories(getLNtype(&c.ReadDB), &c.ReadDB)
+	if err != nil {
+		return err
+	}
+
+	rebuilder := readdb.NewRebuilder(readDB, es, readDBNf, lkf, dataDir)
+	rebuilder.SetProgressFunc(func(sn, lastSn int64) {
+		log.Infof("applied events up to sequence number %d/%d", sn, lastSn)
+	})
+
+	return rebuilder.Rebuild()
+}
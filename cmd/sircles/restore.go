@@ -1,14 +1,15 @@
 package main
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/sorintlab/sircles/config"
-	"github.com/sorintlab/sircles/db"
 	"github.com/sorintlab/sircles/eventstore"
 	slog "github.com/sorintlab/sircles/log"
+	"github.com/sorintlab/sircles/util"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -33,8 +34,17 @@ func init() {
 	rootCmd.AddCommand(restoreCmd)
 
 	restoreCmd.PersistentFlags().StringVar(&dumpFile, "dumpfile", "", "path to dump file")
+	restoreCmd.PersistentFlags().BoolVar(&restoreOpts.append, "append", false, "append the dump events to a non empty eventstore")
+	restoreCmd.PersistentFlags().BoolVar(&restoreOpts.resume, "resume", false, "resume an interrupted restore skipping the dump events already restored")
 }
 
+type restoreOptions struct {
+	append bool
+	resume bool
+}
+
+var restoreOpts restoreOptions
+
 func restore(cmd *cobra.Command, args []string) error {
 	if configFile == "" {
 		return errors.New("you should provide a config file path (-c option)")
@@ -52,66 +62,168 @@ func restore(cmd *cobra.Command, args []string) error {
 		slog.SetLevel(zapcore.DebugLevel)
 	}
 
-	if c.EventStore.Type == "" {
-		return errors.New("no eventstore type specified")
+	es, _, _, err := newEventStore(&c.EventStore)
+	if err != nil {
+		return err
 	}
-	if c.EventStore.Type != "sql" {
-		return errors.Errorf("unknown eventstore type: %q", c.EventStore.Type)
+
+	return restoreEventStore(es, dumpFile, &restoreOpts)
+}
+
+// restoreEventStore restores the dump file events in the eventstore
+func restoreEventStore(es eventstore.EventStore, dumpFile string, opts *restoreOptions) error {
+	// Read the whole dump before restoring it to validate the checksum and
+	// the events count, so a corrupted dump won't be partially restored
+	header, keys, eventCount, err := readDump(dumpFile, func(event *eventstore.StoredEvent) error { return nil })
+	if err != nil {
+		return err
 	}
-	if c.EventStore.DB.Type == "" {
-		return errors.New("no eventstore db type specified")
+	if header == nil {
+		log.Infof("dump file without header, it cannot be validated")
+	} else if header.SchemaVersion > len(eventstore.Migrations) {
+		return errors.Errorf("dump eventstore schema version %d is newer than the current schema version %d", header.SchemaVersion, len(eventstore.Migrations))
 	}
 
-	switch c.EventStore.DB.Type {
-	case db.Postgres:
-	case db.Sqlite3:
-	default:
-		return errors.Errorf("unsupported eventstore db type: %s", c.EventStore.DB.Type)
+	lastSequenceNumber, err := es.LastSequenceNumber()
+	if err != nil {
+		return err
 	}
 
-	esLnType := getLNtype(&c.EventStore.DB)
-	_, esNf, err := getListenerNotifierFactories(esLnType, &c.EventStore.DB)
+	// a partial dump doesn't contain the events before its first event, it
+	// can only be appended to an eventstore containing them
+	if lastSequenceNumber == 0 && header != nil && header.Partial() {
+		return errors.Errorf("the dump contains only the events starting from sequence number %d of category %q, it cannot be restored in an empty eventstore", header.FromSequence, header.Category)
+	}
 
-	esDB, err := db.NewDB(c.EventStore.DB.Type, c.EventStore.DB.ConnString)
-	if err != nil {
+	// when resuming skip all the dump events up to the last event in the
+	// eventstore
+	var resumeEventID string
+	if lastSequenceNumber > 0 {
+		if !opts.append && !opts.resume {
+			return errors.New("eventstore isn't empty, use --append to append the dump events or --resume to resume an interrupted restore")
+		}
+		if opts.resume {
+			events, err := es.GetAllEvents(lastSequenceNumber, 1)
+			if err != nil {
+				return err
+			}
+			if len(events) != 1 {
+				return errors.Errorf("cannot get the eventstore last event")
+			}
+			resumeEventID = events[0].ID.String()
+		}
+	}
+
+	skipping := resumeEventID != ""
+	var skipped int64
+	events := []*eventstore.StoredEvent{}
+	// restore the personal data keys before the events since they're needed
+	// to decode them (and to encrypt the decrypted events of older dumps)
+	if err := restorePersonalDataKeys(es, keys); err != nil {
 		return err
 	}
 
-	// Populate/migrate esdb
-	if err := esDB.Migrate("eventstore", eventstore.Migrations); err != nil {
+	if _, _, _, err := readDump(dumpFile, func(event *eventstore.StoredEvent) error {
+		if skipping {
+			skipped++
+			if event.ID.String() == resumeEventID {
+				skipping = false
+			}
+			return nil
+		}
+
+		events = append(events, event)
+		if len(events) >= restoreBatchSize {
+			if err := es.RestoreEvents(events); err != nil {
+				return err
+			}
+			events = []*eventstore.StoredEvent{}
+		}
+		return nil
+	}); err != nil {
 		return err
 	}
+	if skipping {
+		return errors.Errorf("the eventstore last event %s isn't in the dump, cannot resume the restore", resumeEventID)
+	}
+	if len(events) > 0 {
+		if err := es.RestoreEvents(events); err != nil {
+			return err
+		}
+	}
 
-	es := eventstore.NewEventStore(esDB, esNf)
+	if skipped > 0 {
+		log.Infof("skipped %d already restored events", skipped)
+	}
+	log.Infof("restored %d events", eventCount-skipped)
 
-	f, err := os.Open(dumpFile)
+	return nil
+}
+
+// restorePersonalDataKeys saves in the eventstore the dump personal data keys
+// missing in it. A key destroyed in the dump is destroyed also in the
+// eventstore while a key already destroyed in the eventstore isn't restored.
+// The keys existing in both must be the same.
+func restorePersonalDataKeys(es eventstore.EventStore, keys map[util.ID][]byte) error {
+	curKeys, err := es.PersonalDataKeys()
 	if err != nil {
 		return err
 	}
 
-	dec := json.NewDecoder(f)
+	newKeys := map[util.ID][]byte{}
+	destroyedKeys := []util.ID{}
+	for id, key := range keys {
+		curKey, ok := curKeys[id]
+		switch {
+		case !ok:
+			newKeys[id] = key
+		case curKey == nil:
+		case key == nil:
+			destroyedKeys = append(destroyedKeys, id)
+		case !bytes.Equal(key, curKey):
+			return errors.Errorf("dump personal data key %s is different than the eventstore one", id)
+		}
+	}
 
-	events := []*eventstore.StoredEvent{}
-	for {
-		var event *eventstore.StoredEvent
-		err := dec.Decode(&event)
-		if err != nil {
+	if err := es.CopyPersonalDataKeys(newKeys); err != nil {
+		return err
+	}
+	for _, id := range destroyedKeys {
+		if err := es.DestroyPersonalDataKey(id); err != nil {
 			return err
 		}
+	}
+	return nil
+}
 
-		events = append(events, event)
+// readDump reads the dump file calling fn for every event. It returns the dump
+// header (nil for legacy dumps without header), the personal data keys and
+// the number of events.
+func readDump(dumpFile string, fn func(event *eventstore.StoredEvent) error) (*dumpHeader, map[util.ID][]byte, int64, error) {
+	f, err := os.Open(dumpFile)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer f.Close()
 
-		hasMore := dec.More()
-		if len(events) >= restoreBatchSize || !hasMore {
-			if err := es.RestoreEvents(events); err != nil {
-				return err
-			}
-			events = []*eventstore.StoredEvent{}
-		}
-		if !hasMore {
+	dr, err := newDumpReader(f)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer dr.Close()
+
+	for {
+		event, err := dr.Next()
+		if err == io.EOF {
 			break
 		}
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if err := fn(event); err != nil {
+			return nil, nil, 0, err
+		}
 	}
 
-	return nil
+	return dr.Header(), dr.PersonalDataKeys(), dr.eventCount, nil
 }
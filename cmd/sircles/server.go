@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/sorintlab/sircles/aggregate"
 	graphqlapi "github.com/sorintlab/sircles/api/graphql"
 	"github.com/sorintlab/sircles/auth"
 	"github.com/sorintlab/sircles/change"
@@ -98,16 +99,6 @@ func serve(cmd *cobra.Command, args []string) error {
 		return errors.New("no read db type specified")
 	}
 
-	if c.EventStore.Type == "" {
-		return errors.New("no eventstore type specified")
-	}
-	if c.EventStore.Type != "sql" {
-		return errors.Errorf("unknown eventstore type: %q", c.EventStore.Type)
-	}
-	if c.EventStore.DB.Type == "" {
-		return errors.New("no eventstore db type specified")
-	}
-
 	switch c.ReadDB.Type {
 	case db.Postgres:
 	case db.Sqlite3:
@@ -115,24 +106,12 @@ func serve(cmd *cobra.Command, args []string) error {
 		return errors.Errorf("unsupported read db type: %s", c.ReadDB.Type)
 	}
 
-	switch c.EventStore.DB.Type {
-	case db.Postgres:
-	case db.Sqlite3:
-	default:
-		return errors.Errorf("unsupported eventstore db type: %s", c.EventStore.DB.Type)
-	}
-
 	readDBLnType := getLNtype(&c.ReadDB)
-	esLnType := getLNtype(&c.EventStore.DB)
 
 	readDBLf, readDBNf, err := getListenerNotifierFactories(readDBLnType, &c.ReadDB)
 	if err != nil {
 		return err
 	}
-	esLf, esNf, err := getListenerNotifierFactories(esLnType, &c.EventStore.DB)
-	if err != nil {
-		return err
-	}
 
 	tokenSigningData := &handlers.TokenSigningData{Duration: c.TokenSigning.Duration}
 	switch c.TokenSigning.Method {
@@ -183,20 +162,11 @@ func serve(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	esDB, err := db.NewDB(c.EventStore.DB.Type, c.EventStore.DB.ConnString)
-	if err != nil {
-		return err
-	}
-
-	// Populate/migrate esdb
-	if err := esDB.Migrate("eventstore", eventstore.Migrations); err != nil {
-		return err
-	}
-
-	lkf, err := getLockFactory(&c.EventStore.DB, esDB)
+	es, esLf, lkf, err := newEventStore(&c.EventStore)
 	if err != nil {
 		return err
 	}
+	es.SetSnapshotInterval(c.EventStore.SnapshotInterval)
 
 	var authenticator auth.Authenticator
 
@@ -236,7 +206,6 @@ func serve(cmd *cobra.Command, args []string) error {
 	}
 
 	readDBListener := readdb.NewDBListener(readDB, readDBLf)
-	es := eventstore.NewEventStore(esDB, esNf)
 
 	resolver := graphqlapi.NewResolver()
 	// Since we are using dataloaders to avoid N+1 queries problem we want to
@@ -259,21 +228,39 @@ func serve(cmd *cobra.Command, args []string) error {
 		corsHandler = ghandlers.CORS(corsAllowedHeadersOptions, corsAllowedOriginsOptions)
 	}
 
-	// For the moment, create a different temporary dataDir for every
-	// new process execution.
-	// This will trigger the rebuild from scratch, when loaded, of the
-	// aggregates/handlers snapshot db (rolestree aggregate, deletedroletension
-	// handler).
-	dataDir, err := ioutil.TempDir("", "")
-	if err != nil {
+	// The dataDir contains the aggregates/handlers snapshot db (rolestree
+	// aggregate, deletedroletension handler). If not configured, create a
+	// different temporary dataDir for every new process execution. This will
+	// trigger the rebuild from scratch, when loaded, of the snapshot dbs.
+	// The webhooks snapshot db contains the queued deliveries and the dead
+	// letters so it requires a persistent dataDir.
+	dataDir := c.DataDir
+	if dataDir == "" && len(c.Webhooks) > 0 {
+		return errors.New("webhooks require a persistent dataDir defined in the configuration file")
+	}
+	if dataDir == "" {
+		dataDir, err = ioutil.TempDir("", "")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dataDir)
+	} else {
+		if err := os.MkdirAll(dataDir, 0700); err != nil {
+			return errors.Wrapf(err, "failed to create data dir %q", dataDir)
+		}
+	}
+
+	// Check that the rolestree snapshot db is consistent with the eventstore
+	if err := aggregate.CheckRolesTreeDB(dataDir, es); err != nil {
 		return err
 	}
-	defer os.RemoveAll(dataDir)
 
 	loginHandler := handlers.NewLoginHandler(c, dataDir, readDB, es, esLf, authenticator, memberProvider, tokenSigningData)
 	refreshTokenHandler := handlers.NewRefreshTokenHandler(tokenSigningData)
 	oidcAuthURLHandler := handlers.NewOIDCAuthURLHandler(authenticator)
-	graphqlHandler := handlers.NewGraphQLHandler(c, dataDir, readDB, readDBListener, es, esLf, searchEngine, s, memberProvider)
+	rebuilder := readdb.NewRebuilder(readDB, es, readDBNf, lkf, dataDir)
+	graphqlHandler := handlers.NewGraphQLHandler(c, dataDir, readDB, readDBListener, es, esLf, searchEngine, s, memberProvider, rebuilder)
+	eventsHandler := handlers.NewEventsHandler(readDB, readDBLf)
 	authHandler := handlers.NewAuthHandler(readDB, tokenSigningData)
 
 	router := mux.NewRouter()
@@ -283,6 +270,7 @@ func serve(cmd *cobra.Command, args []string) error {
 	apirouter.Handle("/auth/refresh", authHandler(refreshTokenHandler)).Methods("POST")
 	apirouter.Handle("/auth/logout", authHandler(http.HandlerFunc(handlers.LogoutHandler))).Methods("POST")
 	apirouter.Handle("/graphql", authHandler(graphqlHandler))
+	apirouter.Handle("/events", authHandler(eventsHandler)).Methods("GET")
 	// TODO(sgotti) since we are providing avatars for browser displaying we can't
 	// protect them because the browser img src cannot send the auth token. If
 	// protecting the avatar becomes important there's the need to find a way on
@@ -320,13 +308,28 @@ func serve(cmd *cobra.Command, args []string) error {
 	endChs := []chan struct{}{}
 
 	readDBh := readdb.NewDBEventHandler(readDB, es, readDBNf)
-	mrh := eventhandler.NewMemberRequestHandler(es, &common.DefaultUidGenerator{})
+	mrh := eventhandler.NewMemberRequestHandler(dataDir, es, &common.DefaultUidGenerator{})
 	drth, err := eventhandler.NewDeletedRoleTensionHandler(dataDir, es, &common.DefaultUidGenerator{})
 	if err != nil {
 		return err
 	}
 
-	for _, h := range []eventhandler.EventHandler{readDBh, mrh, drth} {
+	eeh, err := eventhandler.NewElectionExpirationHandler(dataDir, es, &common.DefaultUidGenerator{})
+	if err != nil {
+		return err
+	}
+
+	ehs := []eventhandler.EventHandler{readDBh, mrh, drth, eeh}
+	if len(c.Webhooks) > 0 {
+		whh, err := eventhandler.NewWebhookHandler(dataDir, es, c.Webhooks)
+		if err != nil {
+			return err
+		}
+		ehs = append(ehs, whh)
+		endChs = append(endChs, whh.RunDeliveries(stop))
+	}
+
+	for _, h := range ehs {
 		endCh, err := eventhandler.RunEventHandler(h, stop, esLf, lkf)
 		if err != nil {
 			return err
@@ -341,7 +344,7 @@ func serve(cmd *cobra.Command, args []string) error {
 	return <-listenErrChan
 }
 
-func initializeSircles(dataDir string, readDB *db.DB, es *eventstore.EventStore, readDBLf, esLf ln.ListenerFactory, createInitialAdmin bool) error {
+func initializeSircles(dataDir string, readDB *db.DB, es eventstore.EventStore, readDBLf, esLf ln.ListenerFactory, createInitialAdmin bool) error {
 	events, err := es.GetAllEvents(0, 1)
 	if err != nil {
 		return err
@@ -352,7 +355,7 @@ func initializeSircles(dataDir string, readDB *db.DB, es *eventstore.EventStore,
 		return nil
 	}
 
-	commandService := command.NewCommandService(dataDir, readDB, es, nil, esLf, false)
+	commandService := command.NewCommandService(dataDir, readDB, es, nil, esLf, false, false)
 
 	readDBListener := readdb.NewDBListener(readDB, readDBLf)
 
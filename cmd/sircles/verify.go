@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/sorintlab/sircles/aggregate"
+	"github.com/sorintlab/sircles/config"
+	"github.com/sorintlab/sircles/db"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	verifyBatchSize = 1000
+	// maxVerifyCheckErrors is the max number of errors reported for every
+	// check
+	maxVerifyCheckErrors = 100
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "verify the eventstore and readdb consistency and print a json report",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := verify(cmd, args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(-1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
+
+type verifyReport struct {
+	OK     bool           `json:"ok"`
+	Checks []*verifyCheck `json:"checks"`
+}
+
+type verifyCheck struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Skipped bool   `json:"skipped,omitempty"`
+	// Errors are the first maxVerifyCheckErrors errors
+	Errors []string `json:"errors,omitempty"`
+	// MoreErrors is the number of errors not reported in Errors
+	MoreErrors int                    `json:"moreErrors,omitempty"`
+	Info       map[string]interface{} `json:"info,omitempty"`
+}
+
+func newVerifyCheck(name string) *verifyCheck {
+	return &verifyCheck{
+		Name: name,
+		OK:   true,
+		Info: map[string]interface{}{},
+	}
+}
+
+func (c *verifyCheck) addError(format string, args ...interface{}) {
+	c.OK = false
+	if len(c.Errors) < maxVerifyCheckErrors {
+		c.Errors = append(c.Errors, fmt.Sprintf(format, args...))
+	} else {
+		c.MoreErrors++
+	}
+}
+
+func verify(cmd *cobra.Command, args []string) error {
+	if configFile == "" {
+		return errors.New("you should provide a config file path (-c option)")
+	}
+
+	c, err := config.Parse(configFile)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error parsing configuration file %s", configFile))
+	}
+
+	es, _, err := openEventStore(configFile)
+	if err != nil {
+		return err
+	}
+
+	report := &verifyReport{}
+
+	checks, err := verifyEvents(es)
+	if err != nil {
+		return err
+	}
+	report.Checks = append(report.Checks, checks...)
+
+	check, err := verifyRolesTree(es)
+	if err != nil {
+		return err
+	}
+	report.Checks = append(report.Checks, check)
+
+	check, err = verifyReadDB(c)
+	if err != nil {
+		return err
+	}
+	report.Checks = append(report.Checks, check)
+
+	report.OK = true
+	for _, check := range report.Checks {
+		if !check.OK {
+			report.OK = false
+		}
+	}
+
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	fmt.Println(string(out))
+
+	if !report.OK {
+		return errors.New("verification failed")
+	}
+	return nil
+}
+
+type verifyStream struct {
+	category string
+	version  int64
+}
+
+type verifyEventRef struct {
+	sequenceNumber int64
+	eventID        util.ID
+	name           string
+	refID          util.ID
+}
+
+// decodeEvent decodes the event metadata and data. GetEventDataType panics on
+// unknown event types so recover and report it as an error
+func decodeEvent(e *eventstore.StoredEvent) (md *eventstore.EventMetaData, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = errors.Errorf("%v", p)
+		}
+	}()
+	md, err = ep.UnmarshalMetaData(e)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ep.UnmarshalData(e); err != nil {
+		return nil, err
+	}
+	return md, nil
+}
+
+// verifyEvents reads all the events checking that:
+// * the sequence numbers are contiguous
+// * the events versions of every stream are contiguous and the stream
+// versions match the last event version of every stream
+// * every event metadata and data can be decoded
+// * the correlation and causation IDs, when they are the ID of a stored event,
+// reference an event preceding the referencing one. Commands aren't stored, so
+// IDs not matching an event (like the ID of the command generating the event)
+// and missing IDs are only counted.
+func verifyEvents(es eventstore.EventStore) ([]*verifyCheck, error) {
+	seqCheck := newVerifyCheck("sequenceNumbers")
+	streamsCheck := newVerifyCheck("streamVersions")
+	dataCheck := newVerifyCheck("eventData")
+	refsCheck := newVerifyCheck("eventReferences")
+
+	lastSequenceNumber, err := es.LastSequenceNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	streams := map[string]*verifyStream{}
+	eventsSeq := map[util.ID]int64{}
+	// references to events not yet read
+	pendingRefs := []*verifyEventRef{}
+	var eventsCount, missingRefs int64
+
+	checkRef := func(e *eventstore.StoredEvent, name string, id *util.ID) {
+		// events not generated by a command (like the event handlers state
+		// events) don't have a correlation and causation id
+		if id == nil {
+			missingRefs++
+			return
+		}
+		if _, ok := eventsSeq[*id]; !ok {
+			pendingRefs = append(pendingRefs, &verifyEventRef{sequenceNumber: e.SequenceNumber, eventID: e.ID, name: name, refID: *id})
+		}
+	}
+
+	prevSn := int64(0)
+	for prevSn < lastSequenceNumber {
+		events, err := es.GetAllEvents(prevSn+1, verifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) == 0 {
+			break
+		}
+		for _, e := range events {
+			eventsCount++
+			if e.SequenceNumber != prevSn+1 {
+				seqCheck.addError("event %s sequence number %d, expected %d", e.ID, e.SequenceNumber, prevSn+1)
+			}
+			prevSn = e.SequenceNumber
+
+			s, ok := streams[e.StreamID]
+			if !ok {
+				s = &verifyStream{category: e.Category}
+				streams[e.StreamID] = s
+			}
+			if e.Version != s.version+1 {
+				streamsCheck.addError("stream %s event %s version %d, expected %d", e.StreamID, e.ID, e.Version, s.version+1)
+			}
+			if e.Category != s.category {
+				streamsCheck.addError("stream %s event %s category %q, expected %q", e.StreamID, e.ID, e.Category, s.category)
+			}
+			s.version = e.Version
+
+			if _, ok := eventsSeq[e.ID]; ok {
+				refsCheck.addError("duplicated event id %s (sequence number %d)", e.ID, e.SequenceNumber)
+			}
+			eventsSeq[e.ID] = e.SequenceNumber
+
+			md, err := decodeEvent(e)
+			if err != nil {
+				dataCheck.addError("event %s (sequence number %d) of type %q cannot be decoded: %v", e.ID, e.SequenceNumber, e.EventType, err)
+				continue
+			}
+			checkRef(e, "correlation id", md.CorrelationID)
+			checkRef(e, "causation id", md.CausationID)
+		}
+	}
+	seqCheck.Info["lastSequenceNumber"] = lastSequenceNumber
+	seqCheck.Info["events"] = eventsCount
+
+	var unresolvedRefs int64
+	for _, ref := range pendingRefs {
+		if sn, ok := eventsSeq[ref.refID]; ok {
+			refsCheck.addError("event %s (sequence number %d) %s references the event %s with sequence number %d not preceding it", ref.eventID, ref.sequenceNumber, ref.name, ref.refID, sn)
+			continue
+		}
+		unresolvedRefs++
+	}
+	refsCheck.Info["missingReferences"] = missingRefs
+	refsCheck.Info["notEventReferences"] = unresolvedRefs
+
+	var streamVersionsCount int64
+	afterStreamID := ""
+	for {
+		svs, err := es.GetStreamVersions(afterStreamID, verifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(svs) == 0 {
+			break
+		}
+		for _, sv := range svs {
+			streamVersionsCount++
+			s, ok := streams[sv.StreamID]
+			if !ok {
+				streamsCheck.addError("stream version for stream %s without events", sv.StreamID)
+				continue
+			}
+			if sv.Version != s.version {
+				streamsCheck.addError("stream %s version %d different than its last event version %d", sv.StreamID, sv.Version, s.version)
+			}
+			if sv.Category != s.category {
+				streamsCheck.addError("stream %s version category %q different than its events category %q", sv.StreamID, sv.Category, s.category)
+			}
+			delete(streams, sv.StreamID)
+		}
+		afterStreamID = svs[len(svs)-1].StreamID
+	}
+	for streamID := range streams {
+		streamsCheck.addError("stream %s without stream version", streamID)
+	}
+	streamsCheck.Info["streams"] = streamVersionsCount
+
+	return []*verifyCheck{seqCheck, streamsCheck, dataCheck, refsCheck}, nil
+}
+
+// verifyRolesTree rebuilds the rolestree aggregate from the eventstore in a
+// temporary dir. Loading the rolestree checks its broken edges.
+func verifyRolesTree(es eventstore.EventStore) (*verifyCheck, error) {
+	check := newVerifyCheck("rolesTree")
+
+	dataDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer os.RemoveAll(dataDir)
+
+	rtr := aggregate.NewRolesTreeRepository(dataDir, es, nil)
+	rt, err := rtr.Load(aggregate.RolesTreeAggregateID)
+	if err != nil {
+		check.addError("%v", err)
+		return check, nil
+	}
+	check.Info["version"] = rt.Version()
+
+	return check, nil
+}
+
+// verifyReadDB checks the readdb broken edges at the latest timeline
+func verifyReadDB(c *config.Config) (*verifyCheck, error) {
+	check := newVerifyCheck("readDB")
+
+	switch c.ReadDB.Type {
+	case db.Postgres:
+	case db.Sqlite3:
+	default:
+		check.Skipped = true
+		check.Info["reason"] = "no supported read db configured"
+		return check, nil
+	}
+
+	readDB, err := db.NewDB(c.ReadDB.Type, c.ReadDB.ConnString)
+	if err != nil {
+		return nil, err
+	}
+	defer readDB.Close()
+
+	err = readDB.Do(func(tx *db.Tx) error {
+		readDBService, err := readdb.NewReadDBService(tx)
+		if err != nil {
+			return err
+		}
+		tl := readDBService.CurTimeLine(context.Background())
+		if tl == nil || tl.Number() <= 0 {
+			check.Info["timeLine"] = 0
+			return nil
+		}
+		check.Info["timeLine"] = tl.Number()
+		if err := readDBService.CheckBrokenEdges(tl.Number()); err != nil {
+			check.addError("%v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		check.addError("%v", err)
+	}
+
+	return check, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sorintlab/sircles/eventstore"
+)
+
+// newTestVerifyEventStore returns an eventstore containing the provided
+// events and stream versions as is
+func newTestVerifyEventStore(t *testing.T, events []*eventstore.StoredEvent, svs []*eventstore.StreamVersion) eventstore.EventStore {
+	es := newTestMemoryEventStore()
+	if err := es.CopyEvents(events); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := es.CopyStreamVersions(svs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return es
+}
+
+// checkVerifyEvents runs verifyEvents on the eventstore checking that only the
+// check with the provided name fails, with an error containing expectedError.
+// If name is empty all the checks must succeed.
+func checkVerifyEvents(t *testing.T, es eventstore.EventStore, name, expectedError string) {
+	checks, err := verifyEvents(es)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, check := range checks {
+		if check.Name != name {
+			if !check.OK {
+				t.Fatalf("expected check %q ok, got errors: %v", check.Name, check.Errors)
+			}
+			continue
+		}
+		if check.OK {
+			t.Fatalf("expected check %q failed", check.Name)
+		}
+		if len(check.Errors) != 1 || !strings.Contains(check.Errors[0], expectedError) {
+			t.Fatalf("expected check %q error containing %q, got errors: %v", check.Name, expectedError, check.Errors)
+		}
+	}
+}
+
+func TestVerifyEvents(t *testing.T) {
+	srcES := newTestMemoryEventStore()
+	writeTestMemberEvents(t, srcES, "user01", "user02", "user03")
+	events := allEvents(t, srcES)
+	svs, err := srcES.GetStreamVersions("", 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// copyEvents returns a copy of the events to modify them
+	copyEvents := func() []*eventstore.StoredEvent {
+		ces := make([]*eventstore.StoredEvent, len(events))
+		for i, e := range events {
+			ce := *e
+			ces[i] = &ce
+		}
+		return ces
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		checkVerifyEvents(t, newTestVerifyEventStore(t, copyEvents(), svs), "", "")
+	})
+
+	t.Run("broken stream version", func(t *testing.T) {
+		csvs := make([]*eventstore.StreamVersion, len(svs))
+		for i, sv := range svs {
+			csv := *sv
+			csvs[i] = &csv
+		}
+		csvs[1].Version = 2
+		checkVerifyEvents(t, newTestVerifyEventStore(t, copyEvents(), csvs), "streamVersions", "version 2 different than its last event version 1")
+	})
+
+	t.Run("sequence gap", func(t *testing.T) {
+		ces := copyEvents()
+		ces[2].SequenceNumber = 4
+		checkVerifyEvents(t, newTestVerifyEventStore(t, ces, svs), "sequenceNumbers", "sequence number 4, expected 3")
+	})
+
+	t.Run("undecodable event data", func(t *testing.T) {
+		ces := copyEvents()
+		ces[1].Data = []byte(`{"UserName":`)
+		checkVerifyEvents(t, newTestVerifyEventStore(t, ces, svs), "eventData", "cannot be decoded")
+	})
+
+	t.Run("unknown event type", func(t *testing.T) {
+		ces := copyEvents()
+		ces[1].EventType = "UnknownEventType"
+		checkVerifyEvents(t, newTestVerifyEventStore(t, ces, svs), "eventData", `of type "UnknownEventType" cannot be decoded`)
+	})
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sorintlab/sircles/config"
+	"github.com/sorintlab/sircles/eventhandler"
+	"github.com/sorintlab/sircles/util"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "manage the webhooks failed deliveries",
+}
+
+var webhookDeadLettersCmd = &cobra.Command{
+	Use:   "deadletters",
+	Short: "print in json format the events that couldn't be delivered to the webhooks",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := webhookDeadLetters(cmd, args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(-1)
+		}
+	},
+}
+
+var webhookReplayCmd = &cobra.Command{
+	Use:   "replay [deadletter id...]",
+	Short: "queue again for delivery the provided dead letters or, if none is provided, all the dead letters",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := webhookReplay(cmd, args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(-1)
+		}
+	},
+}
+
+type webhookOptions struct {
+	webhook string
+}
+
+var webhookOpts webhookOptions
+
+func init() {
+	webhookCmd.PersistentFlags().StringVar(&webhookOpts.webhook, "webhook", "", "only the dead letters of the webhook with this name")
+
+	webhookCmd.AddCommand(webhookDeadLettersCmd)
+	webhookCmd.AddCommand(webhookReplayCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
+
+func webhookDataDir() (string, error) {
+	if configFile == "" {
+		return "", errors.New("you should provide a config file path (-c option)")
+	}
+
+	c, err := config.Parse(configFile)
+	if err != nil {
+		return "", errors.WithMessage(err, fmt.Sprintf("error parsing configuration file %s", configFile))
+	}
+	if c.DataDir == "" {
+		return "", errors.New("no dataDir defined in the configuration file")
+	}
+	return c.DataDir, nil
+}
+
+func webhookDeadLetters(cmd *cobra.Command, args []string) error {
+	dataDir, err := webhookDataDir()
+	if err != nil {
+		return err
+	}
+
+	dls, err := eventhandler.WebhookDeadLetters(dataDir, webhookOpts.webhook)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(dls, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+func webhookReplay(cmd *cobra.Command, args []string) error {
+	ids := []util.ID{}
+	for _, arg := range args {
+		id, err := util.IDFromString(arg)
+		if err != nil {
+			return errors.Wrapf(err, "wrong dead letter id %q", arg)
+		}
+		ids = append(ids, id)
+	}
+
+	dataDir, err := webhookDataDir()
+	if err != nil {
+		return err
+	}
+
+	dls, err := eventhandler.ReplayWebhookDeadLetters(dataDir, webhookOpts.webhook, ids)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("queued %d dead letters for delivery\n", len(dls))
+	return nil
+}
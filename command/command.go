@@ -3,6 +3,7 @@ package command
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"image"
 	"regexp"
 	"time"
@@ -48,6 +49,21 @@ const (
 	MaxTensionTitleLength       = 100
 	MaxTensionDescriptionLength = 1000 * 1000 // 1M of chars
 	MaxTensionCloseReasonLength = 1000
+	MaxTensionCommentLength     = 10000
+	MaxTensionLabels            = 20
+	MaxTensionLabelLength       = 30
+
+	MaxProposalObjectionReasonLength = 1000
+
+	MaxMeetingTitleLength             = 100
+	MaxMeetingAgendaItemTitleLength   = 100
+	MaxMeetingAgendaItemOutcomeLength = 1000
+
+	MaxProjectTitleLength          = 100
+	MaxProjectDescriptionLength    = 1000
+	MaxNextActionDescriptionLength = 200
+
+	MaxReportItemDescriptionLength = 200
 
 	MaxRoleAssignmentFocusLength = 30
 )
@@ -66,20 +82,22 @@ type CommandService struct {
 	dataDir      string
 	uidGenerator common.UIDGenerator
 	db           *db.DB
-	es           *eventstore.EventStore
+	es           eventstore.EventStore
 	lnf          ln.ListenerFactory
 
-	hasMemberProvider bool
+	hasMemberProvider   bool
+	holacracyStrictMode bool
 }
 
-func NewCommandService(dataDir string, db *db.DB, es *eventstore.EventStore, uidGenerator common.UIDGenerator, lnf ln.ListenerFactory, hasMemberProvider bool) *CommandService {
+func NewCommandService(dataDir string, db *db.DB, es eventstore.EventStore, uidGenerator common.UIDGenerator, lnf ln.ListenerFactory, hasMemberProvider, holacracyStrictMode bool) *CommandService {
 	s := &CommandService{
-		dataDir:           dataDir,
-		uidGenerator:      uidGenerator,
-		db:                db,
-		es:                es,
-		lnf:               lnf,
-		hasMemberProvider: hasMemberProvider,
+		dataDir:             dataDir,
+		uidGenerator:        uidGenerator,
+		db:                  db,
+		es:                  es,
+		lnf:                 lnf,
+		hasMemberProvider:   hasMemberProvider,
+		holacracyStrictMode: holacracyStrictMode,
 	}
 	if uidGenerator == nil {
 		s.uidGenerator = &common.DefaultUidGenerator{}
@@ -88,7 +106,66 @@ func NewCommandService(dataDir string, db *db.DB, es *eventstore.EventStore, uid
 	return s
 }
 
-func (s *CommandService) UpdateRootRole(ctx context.Context, c *change.UpdateRootRoleChange) (*change.UpdateRootRoleResult, util.ID, error) {
+// execCommand executes the command on the aggregate adding to it the info of
+// the client request saved in the context
+func (s *CommandService) execCommand(ctx context.Context, command *commands.Command, a aggregate.Aggregate) (util.ID, int, error) {
+	command.RequestInfo, _ = ctx.Value("requestinfo").(*commands.RequestInfo)
+	return aggregate.ExecCommand(command, a, s.es, s.uidGenerator)
+}
+
+// AuditRejected records in the command audit log a request of type
+// requestType rejected by the command service validation before issuing any
+// command
+func (s *CommandService) AuditRejected(ctx context.Context, requestType string, rerr error) {
+	entry := &eventstore.CommandAuditEntry{
+		CommandType: requestType,
+		Result:      eventstore.CommandAuditResultRejected,
+		Error:       ErrValidation.Error(),
+	}
+	if rerr != nil {
+		entry.Error = rerr.Error()
+	}
+	if userID, ok := ctx.Value("userid").(string); ok {
+		if issuerID, err := util.IDFromString(userID); err == nil {
+			entry.IssuerID = &issuerID
+		}
+	}
+	if ri, ok := ctx.Value("requestinfo").(*commands.RequestInfo); ok && ri != nil {
+		entry.RemoteAddr = ri.RemoteAddr
+		entry.UserAgent = ri.UserAgent
+	}
+
+	if err := s.es.WriteCommandAudit(entry); err != nil {
+		log.Errorf("failed to write command audit entry for rejected %s request: %+v", requestType, err)
+	}
+}
+
+// CommandAudit returns the command audit log entries matching the provided
+// filter. Only admins can read the command audit log.
+func (s *CommandService) CommandAudit(ctx context.Context, filter *eventstore.CommandAuditFilter, limit int) ([]*eventstore.CommandAuditEntry, bool, error) {
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	callingMember, err := readDBService.CallingMember(ctx, curTl.Number())
+	if err != nil {
+		return nil, false, err
+	}
+	if callingMember == nil || !callingMember.IsAdmin {
+		return nil, false, errors.Errorf("member not authorized")
+	}
+
+	return s.es.CommandAudit(filter, limit)
+}
+
+func (s *CommandService) UpdateRootRole(ctx context.Context, c *change.UpdateRootRoleChange, expectedTl *util.TimeLineNumber) (*change.UpdateRootRoleResult, util.ID, error) {
 	res := &change.UpdateRootRoleResult{}
 	res.UpdateRootRoleChangeErrors.CreateDomainChangesErrors = make([]change.CreateDomainChangeErrors, len(c.CreateDomainChanges))
 	res.UpdateRootRoleChangeErrors.UpdateDomainChangesErrors = make([]change.UpdateDomainChangeErrors, len(c.UpdateDomainChanges))
@@ -225,7 +302,17 @@ func (s *CommandService) UpdateRootRole(ctx context.Context, c *change.UpdateRoo
 		return nil, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleConflict(ctx, readDBService, rt, role.ID, expectedTl)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -233,54 +320,63 @@ func (s *CommandService) UpdateRootRole(ctx context.Context, c *change.UpdateRoo
 	return res, groupID, nil
 }
 
-func (s *CommandService) CircleCreateChildRole(ctx context.Context, roleID util.ID, c *change.CreateRoleChange) (*change.CreateRoleResult, util.ID, error) {
-	res := &change.CreateRoleResult{}
-	res.CreateRoleChangeErrors.CreateDomainChangesErrors = make([]change.CreateDomainChangeErrors, len(c.CreateDomainChanges))
-	res.CreateRoleChangeErrors.CreateAccountabilityChangesErrors = make([]change.CreateAccountabilityChangeErrors, len(c.CreateAccountabilityChanges))
+func checkCreateRoleChange(c *change.CreateRoleChange) (change.CreateRoleChangeErrors, bool) {
+	errs := change.CreateRoleChangeErrors{}
+	hasErrors := false
+
+	errs.CreateDomainChangesErrors = make([]change.CreateDomainChangeErrors, len(c.CreateDomainChanges))
+	errs.CreateAccountabilityChangesErrors = make([]change.CreateAccountabilityChangeErrors, len(c.CreateAccountabilityChanges))
 
 	if c.Name == "" {
-		res.HasErrors = true
-		res.CreateRoleChangeErrors.Name = errors.Errorf("empty role name")
+		hasErrors = true
+		errs.Name = errors.Errorf("empty role name")
 	}
 	if len([]rune(c.Name)) > MaxRoleNameLength {
-		res.HasErrors = true
-		res.CreateRoleChangeErrors.Name = errors.Errorf("name too long")
+		hasErrors = true
+		errs.Name = errors.Errorf("name too long")
 	}
 	if len([]rune(c.Purpose)) > MaxRolePurposeLength {
-		res.HasErrors = true
-		res.CreateRoleChangeErrors.Purpose = errors.Errorf("purpose too long")
+		hasErrors = true
+		errs.Purpose = errors.Errorf("purpose too long")
 	}
 
 	switch c.RoleType {
 	case models.RoleTypeNormal:
 	case models.RoleTypeCircle:
 	default:
-		res.HasErrors = true
-		res.CreateRoleChangeErrors.RoleType = errors.Errorf("wrong role type: %s", c.RoleType)
+		hasErrors = true
+		errs.RoleType = errors.Errorf("wrong role type: %s", c.RoleType)
 	}
 
 	for i, createDomainChange := range c.CreateDomainChanges {
 		if createDomainChange.Description == "" {
-			res.HasErrors = true
-			res.CreateRoleChangeErrors.CreateDomainChangesErrors[i].Description = errors.Errorf("empty domain")
+			hasErrors = true
+			errs.CreateDomainChangesErrors[i].Description = errors.Errorf("empty domain")
 		}
 		if len([]rune(createDomainChange.Description)) > MaxRoleDomainLength {
-			res.HasErrors = true
-			res.CreateRoleChangeErrors.CreateDomainChangesErrors[i].Description = errors.Errorf("domain too long")
+			hasErrors = true
+			errs.CreateDomainChangesErrors[i].Description = errors.Errorf("domain too long")
 		}
 	}
 
 	for i, createAccountabilityChange := range c.CreateAccountabilityChanges {
 		if createAccountabilityChange.Description == "" {
-			res.HasErrors = true
-			res.CreateRoleChangeErrors.CreateAccountabilityChangesErrors[i].Description = errors.Errorf("empty accountability")
+			hasErrors = true
+			errs.CreateAccountabilityChangesErrors[i].Description = errors.Errorf("empty accountability")
 		}
 		if len([]rune(createAccountabilityChange.Description)) > MaxRoleAccountabilityLength {
-			res.HasErrors = true
-			res.CreateRoleChangeErrors.CreateAccountabilityChangesErrors[i].Description = errors.Errorf("accountability too long")
+			hasErrors = true
+			errs.CreateAccountabilityChangesErrors[i].Description = errors.Errorf("accountability too long")
 		}
 	}
 
+	return errs, hasErrors
+}
+
+func (s *CommandService) CircleCreateChildRole(ctx context.Context, roleID util.ID, c *change.CreateRoleChange, expectedTl *util.TimeLineNumber) (*change.CreateRoleResult, util.ID, error) {
+	res := &change.CreateRoleResult{}
+	res.CreateRoleChangeErrors, res.HasErrors = checkCreateRoleChange(c)
+
 	if res.HasErrors {
 		return res, util.NilID, ErrValidation
 	}
@@ -338,7 +434,17 @@ func (s *CommandService) CircleCreateChildRole(ctx context.Context, roleID util.
 		return nil, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleConflict(ctx, readDBService, rt, roleID, expectedTl)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -348,79 +454,88 @@ func (s *CommandService) CircleCreateChildRole(ctx context.Context, roleID util.
 	return res, groupID, nil
 }
 
-func (s *CommandService) CircleUpdateChildRole(ctx context.Context, roleID util.ID, c *change.UpdateRoleChange) (*change.UpdateRoleResult, util.ID, error) {
-	res := &change.UpdateRoleResult{}
-	res.UpdateRoleChangeErrors.CreateDomainChangesErrors = make([]change.CreateDomainChangeErrors, len(c.CreateDomainChanges))
-	res.UpdateRoleChangeErrors.UpdateDomainChangesErrors = make([]change.UpdateDomainChangeErrors, len(c.UpdateDomainChanges))
-	res.UpdateRoleChangeErrors.CreateAccountabilityChangesErrors = make([]change.CreateAccountabilityChangeErrors, len(c.CreateAccountabilityChanges))
-	res.UpdateRoleChangeErrors.UpdateAccountabilityChangesErrors = make([]change.UpdateAccountabilityChangeErrors, len(c.UpdateAccountabilityChanges))
+func checkUpdateRoleChange(c *change.UpdateRoleChange) (change.UpdateRoleChangeErrors, bool) {
+	errs := change.UpdateRoleChangeErrors{}
+	hasErrors := false
+
+	errs.CreateDomainChangesErrors = make([]change.CreateDomainChangeErrors, len(c.CreateDomainChanges))
+	errs.UpdateDomainChangesErrors = make([]change.UpdateDomainChangeErrors, len(c.UpdateDomainChanges))
+	errs.CreateAccountabilityChangesErrors = make([]change.CreateAccountabilityChangeErrors, len(c.CreateAccountabilityChanges))
+	errs.UpdateAccountabilityChangesErrors = make([]change.UpdateAccountabilityChangeErrors, len(c.UpdateAccountabilityChanges))
 
 	if c.NameChanged {
 		if c.Name == "" {
-			res.HasErrors = true
-			res.UpdateRoleChangeErrors.Name = errors.Errorf("empty role name")
+			hasErrors = true
+			errs.Name = errors.Errorf("empty role name")
 		}
 		if len([]rune(c.Name)) > MaxRoleNameLength {
-			res.HasErrors = true
-			res.UpdateRoleChangeErrors.Name = errors.Errorf("name too long")
+			hasErrors = true
+			errs.Name = errors.Errorf("name too long")
 		}
 	}
 
 	if c.PurposeChanged {
 		if len([]rune(c.Purpose)) > MaxRolePurposeLength {
-			res.HasErrors = true
-			res.UpdateRoleChangeErrors.Purpose = errors.Errorf("purpose too long")
+			hasErrors = true
+			errs.Purpose = errors.Errorf("purpose too long")
 		}
 	}
 
 	for i, createDomainChange := range c.CreateDomainChanges {
 		if createDomainChange.Description == "" {
-			res.HasErrors = true
-			res.UpdateRoleChangeErrors.CreateDomainChangesErrors[i].Description = errors.Errorf("empty domain")
+			hasErrors = true
+			errs.CreateDomainChangesErrors[i].Description = errors.Errorf("empty domain")
 		}
 		if len([]rune(createDomainChange.Description)) > MaxRoleDomainLength {
-			res.HasErrors = true
-			res.UpdateRoleChangeErrors.CreateDomainChangesErrors[i].Description = errors.Errorf("domain too long")
+			hasErrors = true
+			errs.CreateDomainChangesErrors[i].Description = errors.Errorf("domain too long")
 		}
 	}
 
 	for i, updateDomainChange := range c.UpdateDomainChanges {
 		if updateDomainChange.DescriptionChanged {
 			if updateDomainChange.Description == "" {
-				res.HasErrors = true
-				res.UpdateRoleChangeErrors.UpdateDomainChangesErrors[i].Description = errors.Errorf("empty domain")
+				hasErrors = true
+				errs.UpdateDomainChangesErrors[i].Description = errors.Errorf("empty domain")
 			}
 			if len([]rune(updateDomainChange.Description)) > MaxRoleDomainLength {
-				res.HasErrors = true
-				res.UpdateRoleChangeErrors.UpdateDomainChangesErrors[i].Description = errors.Errorf("domain too long")
+				hasErrors = true
+				errs.UpdateDomainChangesErrors[i].Description = errors.Errorf("domain too long")
 			}
 		}
 	}
 
 	for i, createAccountabilityChange := range c.CreateAccountabilityChanges {
 		if createAccountabilityChange.Description == "" {
-			res.HasErrors = true
-			res.UpdateRoleChangeErrors.CreateAccountabilityChangesErrors[i].Description = errors.Errorf("empty accountability")
+			hasErrors = true
+			errs.CreateAccountabilityChangesErrors[i].Description = errors.Errorf("empty accountability")
 		}
 		if len([]rune(createAccountabilityChange.Description)) > MaxRoleAccountabilityLength {
-			res.HasErrors = true
-			res.UpdateRoleChangeErrors.CreateAccountabilityChangesErrors[i].Description = errors.Errorf("accountability too long")
+			hasErrors = true
+			errs.CreateAccountabilityChangesErrors[i].Description = errors.Errorf("accountability too long")
 		}
 	}
 
 	for i, updateAccountabilityChange := range c.UpdateAccountabilityChanges {
 		if updateAccountabilityChange.DescriptionChanged {
 			if updateAccountabilityChange.Description == "" {
-				res.HasErrors = true
-				res.UpdateRoleChangeErrors.UpdateAccountabilityChangesErrors[i].Description = errors.Errorf("empty accountability")
+				hasErrors = true
+				errs.UpdateAccountabilityChangesErrors[i].Description = errors.Errorf("empty accountability")
 			}
 			if len([]rune(updateAccountabilityChange.Description)) > MaxRoleAccountabilityLength {
-				res.HasErrors = true
-				res.UpdateRoleChangeErrors.UpdateAccountabilityChangesErrors[i].Description = errors.Errorf("accountability too long")
+				hasErrors = true
+				errs.UpdateAccountabilityChangesErrors[i].Description = errors.Errorf("accountability too long")
 			}
 		}
 	}
 
+	return errs, hasErrors
+}
+
+func (s *CommandService) CircleUpdateChildRole(ctx context.Context, roleID util.ID, c *change.UpdateRoleChange, expectedTl *util.TimeLineNumber) (*change.UpdateRoleResult, util.ID, error) {
+	res := &change.UpdateRoleResult{}
+	res.UpdateRoleChangeErrors, res.HasErrors = checkUpdateRoleChange(c)
+
 	if res.HasErrors {
 		return res, util.NilID, ErrValidation
 	}
@@ -494,7 +609,17 @@ func (s *CommandService) CircleUpdateChildRole(ctx context.Context, roleID util.
 		return nil, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleConflict(ctx, readDBService, rt, c.ID, expectedTl)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -502,7 +627,7 @@ func (s *CommandService) CircleUpdateChildRole(ctx context.Context, roleID util.
 	return res, groupID, nil
 }
 
-func (s *CommandService) CircleDeleteChildRole(ctx context.Context, roleID util.ID, c *change.DeleteRoleChange) (*change.DeleteRoleResult, util.ID, error) {
+func (s *CommandService) CircleDeleteChildRole(ctx context.Context, roleID util.ID, c *change.DeleteRoleChange, expectedTl *util.TimeLineNumber) (*change.DeleteRoleResult, util.ID, error) {
 	res := &change.DeleteRoleResult{}
 	tx, err := s.db.NewTx()
 	if err != nil {
@@ -607,7 +732,17 @@ func (s *CommandService) CircleDeleteChildRole(ctx context.Context, roleID util.
 		return nil, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleConflict(ctx, readDBService, rt, c.ID, expectedTl)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -615,7 +750,7 @@ func (s *CommandService) CircleDeleteChildRole(ctx context.Context, roleID util.
 	return res, groupID, nil
 }
 
-func (s *CommandService) SetRoleAdditionalContent(ctx context.Context, roleID util.ID, content string) (*change.SetRoleAdditionalContentResult, util.ID, error) {
+func (s *CommandService) SetRoleAdditionalContent(ctx context.Context, roleID util.ID, content string, expectedTl *util.TimeLineNumber) (*change.SetRoleAdditionalContentResult, util.ID, error) {
 	res := &change.SetRoleAdditionalContentResult{}
 	if len([]rune(content)) > MaxRoleAdditionalContentLength {
 		res.HasErrors = true
@@ -676,7 +811,17 @@ func (s *CommandService) SetRoleAdditionalContent(ctx context.Context, roleID ut
 		return nil, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleConflict(ctx, readDBService, rt, roleID, expectedTl)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -871,7 +1016,7 @@ func (s *CommandService) createMember(ctx context.Context, c *change.CreateMembe
 		return nil, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, mc, s.es, s.uidGenerator)
+	groupID, _, err := s.execCommand(ctx, command, mc)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1048,7 +1193,7 @@ func (s *CommandService) UpdateMember(ctx context.Context, c *change.UpdateMembe
 		return nil, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, mc, s.es, s.uidGenerator)
+	groupID, _, err := s.execCommand(ctx, command, mc)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1127,7 +1272,7 @@ func (s *CommandService) SetMemberPassword(ctx context.Context, memberID util.ID
 		return nil, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, m, s.es, s.uidGenerator)
+	groupID, _, err := s.execCommand(ctx, command, m)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1202,7 +1347,7 @@ func (s *CommandService) setMemberMatchUID(ctx context.Context, memberID util.ID
 		return nil, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, mc, s.es, s.uidGenerator)
+	groupID, _, err := s.execCommand(ctx, command, mc)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1217,6 +1362,217 @@ func (s *CommandService) setMemberMatchUID(ctx context.Context, memberID util.ID
 	return res, groupID, nil
 }
 
+// DeleteMember deletes a member. The member is removed from all its roles and
+// circles and its open tensions are closed
+func (s *CommandService) DeleteMember(ctx context.Context, memberID util.ID) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	// only admin can delete a member
+	if !callingMember.IsAdmin {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	if callingMember.ID == memberID {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("a member cannot delete itself")
+		return res, util.NilID, ErrValidation
+	}
+
+	member, err := readDBService.Member(ctx, curTlSeq, memberID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if member == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member with id %s doesn't exist", memberID)
+		return res, util.NilID, ErrValidation
+	}
+
+	if member.IsAdmin {
+		members, err := readDBService.MembersByIDs(ctx, curTlSeq, nil)
+		if err != nil {
+			return nil, util.NilID, err
+		}
+		adminCount := 0
+		for _, m := range members {
+			if m.IsAdmin {
+				adminCount++
+			}
+		}
+		if adminCount <= 1 {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("deleting admin will leave the organization without any admin")
+			return res, util.NilID, ErrValidation
+		}
+	}
+
+	tensions, err := readDBService.MemberOpenTensions(ctx, curTlSeq, memberID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	tensionIDs := []util.ID{}
+	for _, tension := range tensions {
+		tensionIDs = append(tensionIDs, tension.ID)
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeRequestDeleteMember, correlationID, causationID, callingMember.ID, &commands.RequestDeleteMember{MemberID: memberID, TensionIDs: tensionIDs})
+
+	memberChangeID := s.uidGenerator.UUID("")
+	mcr := aggregate.NewMemberChangeRepository(s.es, s.uidGenerator)
+	mc, err := mcr.Load(memberChangeID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	if _, _, err := s.execCommand(ctx, command, mc); err != nil {
+		return nil, util.NilID, err
+	}
+
+	// the member request saga deletes the member, removes it from its roles
+	// and closes its tensions before completing the request so the returned
+	// group id is the one of the last step
+	log.Debugf("waiting for request completed event for memberChangeID: %s", memberChangeID)
+	groupID, err := s.waitMemberChangeRequest(ctx, memberChangeID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	log.Debugf("received request completed event for memberChangeID: %s, groupID: %s", memberChangeID, groupID)
+
+	return res, groupID, nil
+}
+
+// ForgetMember destroys the personal data key of a deleted member. The member
+// personal data saved in the events will be unreadable and replaced by
+// placeholders while all the other events data will be kept.
+func (s *CommandService) ForgetMember(ctx context.Context, memberID util.ID) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	// only admin can forget a member
+	if !callingMember.IsAdmin {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	// the member must be deleted and its delete request completed since the
+	// member request saga needs the member personal data to release the
+	// member unique values
+	e, err := s.es.GetLastEvent(memberID.String())
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if e == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member with id %s doesn't exist", memberID)
+		return res, util.NilID, ErrValidation
+	}
+	switch ep.EventType(e.EventType) {
+	case ep.EventTypeMemberDeleted:
+	case ep.EventTypeMemberForgotten:
+		// complete a previous forget that failed after the member forgotten
+		// event has been written
+		if err := s.forgetMemberPersonalData(memberID); err != nil {
+			return nil, util.NilID, err
+		}
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member already forgotten")
+		return res, util.NilID, ErrValidation
+	default:
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member must be deleted before being forgotten")
+		return res, util.NilID, ErrValidation
+	}
+	data, err := ep.UnmarshalData(e)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	memberChangeID := data.(*ep.EventMemberDeleted).MemberChangeID
+	mce, err := s.es.GetLastEvent(memberChangeID.String())
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if mce == nil || ep.EventType(mce.EventType) != ep.EventTypeMemberChangeCompleted {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member delete request not yet completed")
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeForgetMember, correlationID, causationID, callingMember.ID, &commands.ForgetMember{})
+
+	mr := aggregate.NewMemberRepository(s.es, s.uidGenerator)
+	m, err := mr.Load(memberID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, m)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	// the personal data is removed only after the member forgotten event has
+	// been written. If this fails a new forget will complete it.
+	if err := s.forgetMemberPersonalData(memberID); err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// forgetMemberPersonalData destroys the member personal data key and removes
+// the snapshots containing the member personal data. It's idempotent.
+func (s *CommandService) forgetMemberPersonalData(memberID util.ID) error {
+	if err := s.es.DestroyPersonalDataKey(memberID); err != nil {
+		return err
+	}
+	if err := s.es.DeleteSnapshot(memberID.String()); err != nil {
+		return err
+	}
+	return aggregate.DeleteUniqueValueRegistrySnapshots(s.es, memberID)
+}
+
 func (s *CommandService) CreateTension(ctx context.Context, c *change.CreateTensionChange) (*change.CreateTensionResult, util.ID, error) {
 	res := &change.CreateTensionResult{}
 	if c.Title == "" {
@@ -1302,7 +1658,7 @@ func (s *CommandService) CreateTension(ctx context.Context, c *change.CreateTens
 		return nil, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, t, s.es, s.uidGenerator)
+	groupID, _, err := s.execCommand(ctx, command, t)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1312,7 +1668,7 @@ func (s *CommandService) CreateTension(ctx context.Context, c *change.CreateTens
 	return res, groupID, nil
 }
 
-func (s *CommandService) UpdateTension(ctx context.Context, c *change.UpdateTensionChange) (*change.UpdateTensionResult, util.ID, error) {
+func (s *CommandService) UpdateTension(ctx context.Context, c *change.UpdateTensionChange, expectedTl *util.TimeLineNumber) (*change.UpdateTensionResult, util.ID, error) {
 	res := &change.UpdateTensionResult{}
 	if c.Title == "" {
 		res.HasErrors = true
@@ -1424,7 +1780,17 @@ func (s *CommandService) UpdateTension(ctx context.Context, c *change.UpdateTens
 		return nil, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, t, s.es, s.uidGenerator)
+	conflict, err := s.checkTensionConflict(ctx, readDBService, t, c.ID, expectedTl)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, t)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1432,7 +1798,7 @@ func (s *CommandService) UpdateTension(ctx context.Context, c *change.UpdateTens
 	return res, groupID, nil
 }
 
-func (s *CommandService) CloseTension(ctx context.Context, c *change.CloseTensionChange) (*change.CloseTensionResult, util.ID, error) {
+func (s *CommandService) CloseTension(ctx context.Context, c *change.CloseTensionChange, expectedTl *util.TimeLineNumber) (*change.CloseTensionResult, util.ID, error) {
 	res := &change.CloseTensionResult{}
 
 	if len([]rune(c.Reason)) > MaxTensionCloseReasonLength {
@@ -1499,7 +1865,2267 @@ func (s *CommandService) CloseTension(ctx context.Context, c *change.CloseTensio
 		return nil, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, t, s.es, s.uidGenerator)
+	conflict, err := s.checkTensionConflict(ctx, readDBService, t, c.ID, expectedTl)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, t)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// ReopenTension reopens a closed tension. Only the tension member, the
+// tension circle lead link or an admin can reopen it.
+func (s *CommandService) ReopenTension(ctx context.Context, tensionID util.ID) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	tension, err := readDBService.Tension(ctx, curTlSeq, tensionID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if tension == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension with id %s doesn't exist", tensionID)
+		return res, util.NilID, ErrValidation
+	}
+	if !tension.Closed {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension not closed")
+		return res, util.NilID, ErrValidation
+	}
+
+	tp, err := readDBService.MemberTensionPermissions(ctx, curTlSeq, tension.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !tp.ManageTension {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeReopenTension, correlationID, causationID, callingMember.ID, &commands.ReopenTension{})
+
+	tr := aggregate.NewTensionRepository(s.es, s.uidGenerator)
+	t, err := tr.Load(tension.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, t)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// ChangeTensionState changes the state of an open tension. A tension can be
+// closed only with CloseTension. Only the tension member, the tension circle
+// lead link, the tension assignee or an admin can change it.
+func (s *CommandService) ChangeTensionState(ctx context.Context, tensionID util.ID, state models.TensionState) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+	switch state {
+	case models.TensionStateOpen, models.TensionStateInProgress, models.TensionStateProcessed:
+	default:
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("wrong tension state")
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	tension, err := readDBService.Tension(ctx, curTlSeq, tensionID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if tension == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension with id %s doesn't exist", tensionID)
+		return res, util.NilID, ErrValidation
+	}
+	if tension.Closed {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension is closed")
+		return res, util.NilID, ErrValidation
+	}
+	if tension.State == state {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension already in state %s", state)
+		return res, util.NilID, ErrValidation
+	}
+
+	tp, err := readDBService.MemberTensionPermissions(ctx, curTlSeq, tension.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !tp.ChangeTensionState {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeChangeTensionState, correlationID, causationID, callingMember.ID, &commands.ChangeTensionState{State: state})
+
+	tr := aggregate.NewTensionRepository(s.es, s.uidGenerator)
+	t, err := tr.Load(tension.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, t)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// AddTensionComment adds a comment, or a reply to another comment, to the
+// tension discussion. Comments can be added also to closed tensions. Only the
+// tension member, the tension circle lead link, the tension assignee or an
+// admin can add it.
+func (s *CommandService) AddTensionComment(ctx context.Context, c *change.AddTensionCommentChange) (*change.AddTensionCommentResult, util.ID, error) {
+	res := &change.AddTensionCommentResult{}
+	if c.Text == "" {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("empty comment text")
+		return res, util.NilID, ErrValidation
+	}
+	if len([]rune(c.Text)) > MaxTensionCommentLength {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("comment text too long")
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	tension, err := readDBService.Tension(ctx, curTlSeq, c.TensionID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if tension == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension with id %s doesn't exist", c.TensionID)
+		return res, util.NilID, ErrValidation
+	}
+
+	if c.ParentCommentID != nil {
+		commentsGroups, err := readDBService.TensionComments(ctx, curTlSeq, []util.ID{tension.ID})
+		if err != nil {
+			return nil, util.NilID, err
+		}
+		found := false
+		for _, comment := range commentsGroups[tension.ID] {
+			if comment.ID == *c.ParentCommentID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("comment with id %s doesn't exist", *c.ParentCommentID)
+			return res, util.NilID, ErrValidation
+		}
+	}
+
+	tp, err := readDBService.MemberTensionPermissions(ctx, curTlSeq, tension.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !tp.Comment {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	commentID := s.uidGenerator.UUID(c.Text)
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeAddTensionComment, correlationID, causationID, callingMember.ID, commands.NewCommandAddTensionComment(commentID, callingMember.ID, c))
+
+	tr := aggregate.NewTensionRepository(s.es, s.uidGenerator)
+	t, err := tr.Load(tension.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, t)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	res.CommentID = &commentID
+
+	return res, groupID, nil
+}
+
+// AssignTension assigns an open tension to a member filling the tension
+// circle or one of its child roles. When both the role and the member are nil
+// the tension is unassigned. Only the tension member, the tension circle lead
+// link or an admin can assign it.
+func (s *CommandService) AssignTension(ctx context.Context, c *change.AssignTensionChange) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+	if (c.RoleID == nil) != (c.MemberID == nil) {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("both the assignee role and member must be provided")
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	tension, err := readDBService.Tension(ctx, curTlSeq, c.TensionID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if tension == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension with id %s doesn't exist", c.TensionID)
+		return res, util.NilID, ErrValidation
+	}
+	if tension.Closed {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension is closed")
+		return res, util.NilID, ErrValidation
+	}
+
+	tp, err := readDBService.MemberTensionPermissions(ctx, curTlSeq, tension.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !tp.ManageTension {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	tensionAssigneeGroups, err := readDBService.TensionAssignee(ctx, curTlSeq, []util.ID{tension.ID})
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	tensionAssigneeRoleGroups, err := readDBService.TensionAssigneeRole(ctx, curTlSeq, []util.ID{tension.ID})
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	assignee := tensionAssigneeGroups[tension.ID]
+	assigneeRole := tensionAssigneeRoleGroups[tension.ID]
+	if c.RoleID == nil && assignee == nil && assigneeRole == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension not assigned")
+		return res, util.NilID, ErrValidation
+	}
+	if c.RoleID != nil && assignee != nil && assigneeRole != nil && assignee.ID == *c.MemberID && assigneeRole.ID == *c.RoleID {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension assignee not changed")
+		return res, util.NilID, ErrValidation
+	}
+
+	if c.RoleID != nil {
+		tensionRoleGroups, err := readDBService.TensionRole(ctx, curTlSeq, []util.ID{tension.ID})
+		if err != nil {
+			return nil, util.NilID, err
+		}
+		circle := tensionRoleGroups[tension.ID]
+		if circle == nil {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("tension without a circle cannot be assigned")
+			return res, util.NilID, ErrValidation
+		}
+
+		role, err := readDBService.Role(ctx, curTlSeq, *c.RoleID)
+		if err != nil {
+			return nil, util.NilID, err
+		}
+		if role == nil {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("role with id %s doesn't exist", *c.RoleID)
+			return res, util.NilID, ErrValidation
+		}
+		if role.ID != circle.ID {
+			parentGroups, err := readDBService.RoleParent(ctx, curTlSeq, []util.ID{role.ID})
+			if err != nil {
+				return nil, util.NilID, err
+			}
+			if parent := parentGroups[role.ID]; parent == nil || parent.ID != circle.ID {
+				res.HasErrors = true
+				res.GenericError = errors.Errorf("role with id %s is not the tension circle or one of its child roles", role.ID)
+				return res, util.NilID, ErrValidation
+			}
+		}
+
+		member, err := readDBService.Member(ctx, curTlSeq, *c.MemberID)
+		if err != nil {
+			return nil, util.NilID, err
+		}
+		if member == nil {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("member with id %s doesn't exist", *c.MemberID)
+			return res, util.NilID, ErrValidation
+		}
+
+		isFiller, err := isRoleFiller(ctx, readDBService, curTlSeq, role, member.ID)
+		if err != nil {
+			return nil, util.NilID, err
+		}
+		if !isFiller {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("member %s doesn't fill role", member.ID)
+			return res, util.NilID, ErrValidation
+		}
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeAssignTension, correlationID, causationID, callingMember.ID, commands.NewCommandAssignTension(c))
+
+	tr := aggregate.NewTensionRepository(s.es, s.uidGenerator)
+	t, err := tr.Load(tension.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, t)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// SetTensionLabels replaces the labels of an open tension. Only the tension
+// member, the tension circle lead link or an admin can set them.
+func (s *CommandService) SetTensionLabels(ctx context.Context, tensionID util.ID, labels []string) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+	if len(labels) > MaxTensionLabels {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("too many labels")
+		return res, util.NilID, ErrValidation
+	}
+	seen := map[string]struct{}{}
+	for _, label := range labels {
+		if label == "" {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("empty label")
+			return res, util.NilID, ErrValidation
+		}
+		if len([]rune(label)) > MaxTensionLabelLength {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("label too long")
+			return res, util.NilID, ErrValidation
+		}
+		if _, ok := seen[label]; ok {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("duplicate label %q", label)
+			return res, util.NilID, ErrValidation
+		}
+		seen[label] = struct{}{}
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	tension, err := readDBService.Tension(ctx, curTlSeq, tensionID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if tension == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension with id %s doesn't exist", tensionID)
+		return res, util.NilID, ErrValidation
+	}
+	if tension.Closed {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension is closed")
+		return res, util.NilID, ErrValidation
+	}
+
+	tp, err := readDBService.MemberTensionPermissions(ctx, curTlSeq, tension.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !tp.ManageTension {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeSetTensionLabels, correlationID, causationID, callingMember.ID, &commands.SetTensionLabels{Labels: labels})
+
+	tr := aggregate.NewTensionRepository(s.es, s.uidGenerator)
+	t, err := tr.Load(tension.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, t)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+func checkProposalChanges(c *change.ProposalChanges) (change.ProposalChangesErrors, bool) {
+	errs := change.ProposalChangesErrors{}
+	hasErrors := false
+
+	for _, createRoleChange := range c.CreateRoleChanges {
+		createRoleChangeErrors, createRoleChangeHasErrors := checkCreateRoleChange(&createRoleChange)
+		if createRoleChangeHasErrors {
+			hasErrors = true
+		}
+		errs.CreateRoleChangesErrors = append(errs.CreateRoleChangesErrors, createRoleChangeErrors)
+	}
+	for _, updateRoleChange := range c.UpdateRoleChanges {
+		updateRoleChangeErrors, updateRoleChangeHasErrors := checkUpdateRoleChange(&updateRoleChange)
+		if updateRoleChangeHasErrors {
+			hasErrors = true
+		}
+		errs.UpdateRoleChangesErrors = append(errs.UpdateRoleChangesErrors, updateRoleChangeErrors)
+	}
+
+	return errs, hasErrors
+}
+
+// checkProposalChangesRoles checks that the roles updated or deleted by the
+// proposal changes are child roles of the proposal circle
+func checkProposalChangesRoles(ctx context.Context, readDBService readdb.ReadDBService, tl util.TimeLineNumber, roleID util.ID, c *change.ProposalChanges) error {
+	if len(c.CreateRoleChanges) == 0 && len(c.UpdateRoleChanges) == 0 && len(c.DeleteRoleChanges) == 0 {
+		return errors.Errorf("empty proposal changes")
+	}
+
+	childsGroups, err := readDBService.ChildRoles(ctx, tl, []util.ID{roleID}, nil)
+	if err != nil {
+		return err
+	}
+	childs := map[util.ID]struct{}{}
+	for _, child := range childsGroups[roleID] {
+		childs[child.ID] = struct{}{}
+	}
+
+	for _, updateRoleChange := range c.UpdateRoleChanges {
+		if _, ok := childs[updateRoleChange.ID]; !ok {
+			return errors.Errorf("role with id %s is not a child of role %s", updateRoleChange.ID, roleID)
+		}
+	}
+	for _, deleteRoleChange := range c.DeleteRoleChanges {
+		if _, ok := childs[deleteRoleChange.ID]; !ok {
+			return errors.Errorf("role with id %s is not a child of role %s", deleteRoleChange.ID, roleID)
+		}
+	}
+
+	return nil
+}
+
+func isCircleMember(ctx context.Context, readDBService readdb.ReadDBService, tl util.TimeLineNumber, roleID, memberID util.ID) (bool, error) {
+	circleMemberEdgesGroups, err := readDBService.CircleMemberEdges(ctx, tl, []util.ID{roleID})
+	if err != nil {
+		return false, err
+	}
+	for _, circleMemberEdge := range circleMemberEdgesGroups[roleID] {
+		if circleMemberEdge.Member.ID == memberID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateProposal creates a new proposal, on the tension, of changes to the
+// provided circle child roles
+func (s *CommandService) CreateProposal(ctx context.Context, c *change.CreateProposalChange) (*change.CreateProposalResult, util.ID, error) {
+	res := &change.CreateProposalResult{}
+	res.ProposalChangesErrors, res.HasErrors = checkProposalChanges(&c.ProposalChanges)
+
+	if res.HasErrors {
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	tension, err := readDBService.Tension(ctx, curTlSeq, c.TensionID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if tension == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension with id %s doesn't exist", c.TensionID)
+		return res, util.NilID, ErrValidation
+	}
+	if tension.Closed {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("tension already closed")
+		return res, util.NilID, ErrValidation
+	}
+
+	role, err := readDBService.Role(ctx, curTlSeq, c.RoleID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if role == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("role with id %s doesn't exist", c.RoleID)
+		return res, util.NilID, ErrValidation
+	}
+	if role.RoleType != models.RoleTypeCircle {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("role with id %s is not a circle", c.RoleID)
+		return res, util.NilID, ErrValidation
+	}
+
+	// Check that the user is a member of the circle
+	isRoleMember, err := isCircleMember(ctx, readDBService, curTlSeq, role.ID, callingMember.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !callingMember.IsAdmin && !isRoleMember {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member is not member of role")
+		return res, util.NilID, ErrValidation
+	}
+
+	if err := checkProposalChangesRoles(ctx, readDBService, curTlSeq, role.ID, &c.ProposalChanges); err != nil {
+		res.HasErrors = true
+		res.GenericError = err
+		return res, util.NilID, ErrValidation
+	}
+
+	proposalID := s.uidGenerator.UUID(c.TensionID.String())
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeCreateProposal, correlationID, causationID, callingMember.ID, commands.NewCommandCreateProposal(callingMember.ID, c))
+
+	pr := aggregate.NewProposalRepository(s.es, s.uidGenerator)
+	p, err := pr.Load(proposalID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, p)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	res.ProposalID = &proposalID
+
+	return res, groupID, nil
+}
+
+// UpdateProposal replaces the proposal changes. The objections raised on the
+// previous changes are removed.
+func (s *CommandService) UpdateProposal(ctx context.Context, c *change.UpdateProposalChange) (*change.UpdateProposalResult, util.ID, error) {
+	res := &change.UpdateProposalResult{}
+	res.ProposalChangesErrors, res.HasErrors = checkProposalChanges(&c.ProposalChanges)
+
+	if res.HasErrors {
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	proposal, err := readDBService.Proposal(ctx, curTlSeq, c.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if proposal == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("proposal with id %s doesn't exist", c.ID)
+		return res, util.NilID, ErrValidation
+	}
+	if proposal.State != models.ProposalStateOpen {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("proposal isn't open")
+		return res, util.NilID, ErrValidation
+	}
+
+	proposalMemberGroups, err := readDBService.ProposalMember(ctx, curTlSeq, []util.ID{proposal.ID})
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	proposalMember := proposalMemberGroups[proposal.ID]
+
+	// the proposal member could have been deleted
+	if !callingMember.IsAdmin && (proposalMember == nil || callingMember.ID != proposalMember.ID) {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	proposalRoleGroups, err := readDBService.ProposalRole(ctx, curTlSeq, []util.ID{proposal.ID})
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	proposalRole := proposalRoleGroups[proposal.ID]
+	if proposalRole == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("proposal role doesn't exist")
+		return res, util.NilID, ErrValidation
+	}
+
+	if err := checkProposalChangesRoles(ctx, readDBService, curTlSeq, proposalRole.ID, &c.ProposalChanges); err != nil {
+		res.HasErrors = true
+		res.GenericError = err
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeUpdateProposal, correlationID, causationID, callingMember.ID, commands.NewCommandUpdateProposal(c))
+
+	pr := aggregate.NewProposalRepository(s.es, s.uidGenerator)
+	p, err := pr.Load(c.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, p)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// ObjectProposal raises an objection to the proposal. Only the proposal circle
+// members can object.
+func (s *CommandService) ObjectProposal(ctx context.Context, c *change.ObjectProposalChange) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+
+	if c.Reason == "" {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("empty objection reason")
+		return res, util.NilID, ErrValidation
+	}
+	if len([]rune(c.Reason)) > MaxProposalObjectionReasonLength {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("objection reason too long")
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	proposal, err := readDBService.Proposal(ctx, curTlSeq, c.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if proposal == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("proposal with id %s doesn't exist", c.ID)
+		return res, util.NilID, ErrValidation
+	}
+	if proposal.State != models.ProposalStateOpen {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("proposal isn't open")
+		return res, util.NilID, ErrValidation
+	}
+
+	proposalRoleGroups, err := readDBService.ProposalRole(ctx, curTlSeq, []util.ID{proposal.ID})
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	proposalRole := proposalRoleGroups[proposal.ID]
+	if proposalRole == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("proposal role doesn't exist")
+		return res, util.NilID, ErrValidation
+	}
+
+	isRoleMember, err := isCircleMember(ctx, readDBService, curTlSeq, proposalRole.ID, callingMember.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !isRoleMember {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member is not member of role")
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeObjectProposal, correlationID, causationID, callingMember.ID, commands.NewCommandObjectProposal(callingMember.ID, c))
+
+	pr := aggregate.NewProposalRepository(s.es, s.uidGenerator)
+	p, err := pr.Load(c.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, p)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// AcceptProposal accepts the proposal applying all its changes to the roles
+// tree. If one of the changes cannot be applied none of them is applied.
+func (s *CommandService) AcceptProposal(ctx context.Context, c *change.AcceptProposalChange) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	proposal, err := readDBService.Proposal(ctx, curTlSeq, c.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if proposal == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("proposal with id %s doesn't exist", c.ID)
+		return res, util.NilID, ErrValidation
+	}
+	if proposal.State != models.ProposalStateOpen {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("proposal isn't open")
+		return res, util.NilID, ErrValidation
+	}
+	if len(proposal.Objections) > 0 {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("proposal has objections")
+		return res, util.NilID, ErrValidation
+	}
+
+	proposalRoleGroups, err := readDBService.ProposalRole(ctx, curTlSeq, []util.ID{proposal.ID})
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	proposalRole := proposalRoleGroups[proposal.ID]
+	if proposalRole == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("proposal role doesn't exist")
+		return res, util.NilID, ErrValidation
+	}
+
+	cp, err := readDBService.MemberCirclePermissions(ctx, curTlSeq, proposalRole.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	// the proposal role could have been changed to a normal role
+	if cp == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("proposal role isn't a circle")
+		return res, util.NilID, ErrValidation
+	}
+	if !cp.AcceptProposals {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	var proposalChanges change.ProposalChanges
+	if err := json.Unmarshal([]byte(proposal.Changes), &proposalChanges); err != nil {
+		return nil, util.NilID, errors.Wrap(err, "failed to unmarshal proposal changes")
+	}
+
+	newRoleIDs := []util.ID{}
+	for _, createRoleChange := range proposalChanges.CreateRoleChanges {
+		newRoleIDs = append(newRoleIDs, s.uidGenerator.UUID(createRoleChange.Name))
+	}
+
+	// accept the proposal first since the proposal aggregate is the gate that
+	// guarantees that its changes will be applied only once. If the changes
+	// cannot be applied to the roles tree the acceptance will be reverted.
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeAcceptProposal, correlationID, causationID, callingMember.ID, &commands.AcceptProposal{MemberID: callingMember.ID})
+
+	pr := aggregate.NewProposalRepository(s.es, s.uidGenerator)
+	p, err := pr.Load(c.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	if _, _, err := s.execCommand(ctx, command, p); err != nil {
+		// the proposal has been concurrently accepted or changed
+		if hcErr, ok := err.(*aggregate.HandleCommandError); ok {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("proposal cannot be accepted: %v", hcErr)
+			return res, util.NilID, ErrValidation
+		}
+		return nil, util.NilID, err
+	}
+
+	command = commands.NewCommand(commands.CommandTypeCircleApplyProposal, correlationID, command.ID, callingMember.ID, &commands.CircleApplyProposal{RoleID: proposalRole.ID, ProposalID: proposal.ID, NewRoleIDs: newRoleIDs, ProposalChanges: proposalChanges})
+
+	groupID, err := s.applyProposal(ctx, command)
+	if err != nil {
+		// revert the acceptance on every failure (the proposal changes
+		// aren't valid anymore for the current roles tree, the roles tree
+		// has been concurrently changed or cannot be loaded or written) or
+		// the proposal will remain accepted without its changes applied.
+		// Return the group id of the acceptance revert so the caller can
+		// wait for the proposal to be reopened
+		reason := "proposal changes cannot be applied"
+		hcErr, isHcErr := err.(*aggregate.HandleCommandError)
+		if isHcErr {
+			reason = hcErr.Error()
+		}
+		groupID, rerr := s.revertProposalAcceptance(ctx, correlationID, command.ID, callingMember.ID, c.ID, reason)
+		if rerr != nil {
+			return nil, util.NilID, errors.Wrapf(rerr, "failed to revert proposal acceptance after apply error: %v", err)
+		}
+		if isHcErr {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("proposal cannot be applied: %v", hcErr)
+			return res, groupID, ErrValidation
+		}
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// applyProposal applies the accepted proposal changes to the roles tree
+func (s *CommandService) applyProposal(ctx context.Context, command *commands.Command) (util.ID, error) {
+	rtr := aggregate.NewRolesTreeRepository(s.dataDir, s.es, s.uidGenerator)
+	rt, err := rtr.Load(aggregate.RolesTreeAggregateID)
+	if err != nil {
+		return util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
+	return groupID, err
+}
+
+// revertProposalAcceptance reopens an accepted proposal whose changes couldn't
+// be applied to the roles tree
+func (s *CommandService) revertProposalAcceptance(ctx context.Context, correlationID, causationID, callingMemberID, proposalID util.ID, reason string) (util.ID, error) {
+	command := commands.NewCommand(commands.CommandTypeRevertProposalAcceptance, correlationID, causationID, callingMemberID, &commands.RevertProposalAcceptance{Reason: reason})
+
+	pr := aggregate.NewProposalRepository(s.es, s.uidGenerator)
+	p, err := pr.Load(proposalID)
+	if err != nil {
+		return util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, p)
+	return groupID, err
+}
+
+// circleCoreRoleMember returns the member assigned to the circle core role of
+// the provided type (if any)
+func circleCoreRoleMember(ctx context.Context, readDBService readdb.ReadDBService, tl util.TimeLineNumber, roleID util.ID, roleType models.RoleType) (*models.Member, error) {
+	childsGroups, err := readDBService.ChildRoles(ctx, tl, []util.ID{roleID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	var coreRole *models.Role
+	for _, child := range childsGroups[roleID] {
+		if child.RoleType == roleType {
+			coreRole = child
+			break
+		}
+	}
+	if coreRole == nil {
+		return nil, nil
+	}
+
+	roleMemberEdgesGroups, err := readDBService.RoleMemberEdges(ctx, tl, []util.ID{coreRole.ID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	roleMemberEdges := roleMemberEdgesGroups[coreRole.ID]
+	// core roles must have at max one assigned member
+	if len(roleMemberEdges) == 0 {
+		return nil, nil
+	}
+	return roleMemberEdges[0].Member, nil
+}
+
+// isMeetingConductor reports if the member is the meeting facilitator or
+// secretary
+func isMeetingConductor(ctx context.Context, readDBService readdb.ReadDBService, tl util.TimeLineNumber, meetingID, memberID util.ID) (bool, error) {
+	facilitatorGroups, err := readDBService.MeetingFacilitator(ctx, tl, []util.ID{meetingID})
+	if err != nil {
+		return false, err
+	}
+	if facilitator := facilitatorGroups[meetingID]; facilitator != nil && facilitator.ID == memberID {
+		return true, nil
+	}
+	secretaryGroups, err := readDBService.MeetingSecretary(ctx, tl, []util.ID{meetingID})
+	if err != nil {
+		return false, err
+	}
+	if secretary := secretaryGroups[meetingID]; secretary != nil && secretary.ID == memberID {
+		return true, nil
+	}
+	return false, nil
+}
+
+// CreateMeeting creates a new governance or tactical meeting of the circle.
+// The meeting facilitator and secretary are the members currently assigned to
+// the circle facilitator and secretary core roles.
+func (s *CommandService) CreateMeeting(ctx context.Context, c *change.CreateMeetingChange) (*change.CreateMeetingResult, util.ID, error) {
+	res := &change.CreateMeetingResult{}
+	if c.MeetingType == "" {
+		res.HasErrors = true
+		res.CreateMeetingChangeErrors.MeetingType = errors.Errorf("wrong meeting type")
+	}
+	if c.Title == "" {
+		res.HasErrors = true
+		res.CreateMeetingChangeErrors.Title = errors.Errorf("empty meeting title")
+	}
+	if len([]rune(c.Title)) > MaxMeetingTitleLength {
+		res.HasErrors = true
+		res.CreateMeetingChangeErrors.Title = errors.Errorf("title too long")
+	}
+
+	if res.HasErrors {
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	role, err := readDBService.Role(ctx, curTlSeq, c.RoleID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if role == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("role with id %s doesn't exist", c.RoleID)
+		return res, util.NilID, ErrValidation
+	}
+	if role.RoleType != models.RoleTypeCircle {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("role with id %s is not a circle", c.RoleID)
+		return res, util.NilID, ErrValidation
+	}
+
+	isRoleMember, err := isCircleMember(ctx, readDBService, curTlSeq, role.ID, callingMember.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !callingMember.IsAdmin && !isRoleMember {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member is not member of role")
+		return res, util.NilID, ErrValidation
+	}
+
+	// attendees must be circle members
+	attendees := map[util.ID]struct{}{}
+	for _, attendeeID := range c.AttendeesIDs {
+		if _, ok := attendees[attendeeID]; ok {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("duplicate attendee %s", attendeeID)
+			return res, util.NilID, ErrValidation
+		}
+		attendees[attendeeID] = struct{}{}
+
+		isAttendeeMember, err := isCircleMember(ctx, readDBService, curTlSeq, role.ID, attendeeID)
+		if err != nil {
+			return nil, util.NilID, err
+		}
+		if !isAttendeeMember {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("attendee %s is not member of role", attendeeID)
+			return res, util.NilID, ErrValidation
+		}
+	}
+
+	var facilitatorID, secretaryID *util.ID
+	facilitator, err := circleCoreRoleMember(ctx, readDBService, curTlSeq, role.ID, models.RoleTypeFacilitator)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if facilitator != nil {
+		facilitatorID = &facilitator.ID
+	}
+	secretary, err := circleCoreRoleMember(ctx, readDBService, curTlSeq, role.ID, models.RoleTypeSecretary)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if secretary != nil {
+		secretaryID = &secretary.ID
+	}
+
+	meetingID := s.uidGenerator.UUID(c.Title)
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeCreateMeeting, correlationID, causationID, callingMember.ID, commands.NewCommandCreateMeeting(callingMember.ID, facilitatorID, secretaryID, c))
+
+	mr := aggregate.NewMeetingRepository(s.es, s.uidGenerator)
+	m, err := mr.Load(meetingID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, m)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	res.MeetingID = &meetingID
+
+	return res, groupID, nil
+}
+
+// AddMeetingAgendaItem adds an agenda item to the meeting. The agenda item
+// can be related to an open tension of the meeting circle.
+func (s *CommandService) AddMeetingAgendaItem(ctx context.Context, c *change.AddMeetingAgendaItemChange) (*change.AddMeetingAgendaItemResult, util.ID, error) {
+	res := &change.AddMeetingAgendaItemResult{}
+	if c.Title == "" {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("empty agenda item title")
+		return res, util.NilID, ErrValidation
+	}
+	if len([]rune(c.Title)) > MaxMeetingAgendaItemTitleLength {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("agenda item title too long")
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	meeting, err := readDBService.Meeting(ctx, curTlSeq, c.MeetingID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if meeting == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("meeting with id %s doesn't exist", c.MeetingID)
+		return res, util.NilID, ErrValidation
+	}
+	if meeting.Closed {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("meeting already closed")
+		return res, util.NilID, ErrValidation
+	}
+
+	meetingRoleGroups, err := readDBService.MeetingRole(ctx, curTlSeq, []util.ID{meeting.ID})
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	meetingRole := meetingRoleGroups[meeting.ID]
+	if meetingRole == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("meeting role doesn't exist")
+		return res, util.NilID, ErrValidation
+	}
+
+	isRoleMember, err := isCircleMember(ctx, readDBService, curTlSeq, meetingRole.ID, callingMember.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !callingMember.IsAdmin && !isRoleMember {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member is not member of role")
+		return res, util.NilID, ErrValidation
+	}
+
+	if c.TensionID != nil {
+		tension, err := readDBService.Tension(ctx, curTlSeq, *c.TensionID)
+		if err != nil {
+			return nil, util.NilID, err
+		}
+		if tension == nil {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("tension with id %s doesn't exist", *c.TensionID)
+			return res, util.NilID, ErrValidation
+		}
+		if tension.Closed {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("tension already closed")
+			return res, util.NilID, ErrValidation
+		}
+		tensionRoleGroups, err := readDBService.TensionRole(ctx, curTlSeq, []util.ID{tension.ID})
+		if err != nil {
+			return nil, util.NilID, err
+		}
+		tensionRole := tensionRoleGroups[tension.ID]
+		if tensionRole == nil || tensionRole.ID != meetingRole.ID {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("tension isn't assigned to the meeting role")
+			return res, util.NilID, ErrValidation
+		}
+	}
+
+	agendaItemID := s.uidGenerator.UUID(c.Title)
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeAddMeetingAgendaItem, correlationID, causationID, callingMember.ID, commands.NewCommandAddMeetingAgendaItem(agendaItemID, c))
+
+	mr := aggregate.NewMeetingRepository(s.es, s.uidGenerator)
+	m, err := mr.Load(meeting.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, m)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	res.AgendaItemID = &agendaItemID
+
+	return res, groupID, nil
+}
+
+// SetMeetingAgendaItemOutcome sets the outcome of a meeting agenda item. Only
+// the meeting facilitator or secretary can set it.
+func (s *CommandService) SetMeetingAgendaItemOutcome(ctx context.Context, c *change.SetMeetingAgendaItemOutcomeChange) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+	if len([]rune(c.Outcome)) > MaxMeetingAgendaItemOutcomeLength {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("outcome too long")
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	meeting, err := readDBService.Meeting(ctx, curTlSeq, c.MeetingID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if meeting == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("meeting with id %s doesn't exist", c.MeetingID)
+		return res, util.NilID, ErrValidation
+	}
+	if meeting.Closed {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("meeting already closed")
+		return res, util.NilID, ErrValidation
+	}
+
+	agendaItems, err := readDBService.MeetingAgendaItems(ctx, curTlSeq, []util.ID{meeting.ID})
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	found := false
+	for _, ai := range agendaItems[meeting.ID] {
+		if ai.ID == c.AgendaItemID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("agenda item with id %s doesn't exist", c.AgendaItemID)
+		return res, util.NilID, ErrValidation
+	}
+
+	isConductor, err := isMeetingConductor(ctx, readDBService, curTlSeq, meeting.ID, callingMember.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !callingMember.IsAdmin && !isConductor {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeSetMeetingAgendaItemOutcome, correlationID, causationID, callingMember.ID, commands.NewCommandSetMeetingAgendaItemOutcome(c))
+
+	mr := aggregate.NewMeetingRepository(s.es, s.uidGenerator)
+	m, err := mr.Load(meeting.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, m)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// CloseMeeting closes the meeting. Only the meeting facilitator or secretary
+// can close it.
+func (s *CommandService) CloseMeeting(ctx context.Context, meetingID util.ID) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	meeting, err := readDBService.Meeting(ctx, curTlSeq, meetingID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if meeting == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("meeting with id %s doesn't exist", meetingID)
+		return res, util.NilID, ErrValidation
+	}
+	if meeting.Closed {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("meeting already closed")
+		return res, util.NilID, ErrValidation
+	}
+
+	isConductor, err := isMeetingConductor(ctx, readDBService, curTlSeq, meeting.ID, callingMember.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !callingMember.IsAdmin && !isConductor {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeCloseMeeting, correlationID, causationID, callingMember.ID, &commands.CloseMeeting{})
+
+	mr := aggregate.NewMeetingRepository(s.es, s.uidGenerator)
+	m, err := mr.Load(meeting.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, m)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// isRoleFiller reports if the member fills the role. For a circle it reports
+// if the member is a circle member.
+func isRoleFiller(ctx context.Context, readDBService readdb.ReadDBService, tl util.TimeLineNumber, role *models.Role, memberID util.ID) (bool, error) {
+	if role.RoleType == models.RoleTypeCircle {
+		return isCircleMember(ctx, readDBService, tl, role.ID, memberID)
+	}
+	roleMemberEdgesGroups, err := readDBService.RoleMemberEdges(ctx, tl, []util.ID{role.ID}, nil)
+	if err != nil {
+		return false, err
+	}
+	for _, roleMemberEdge := range roleMemberEdgesGroups[role.ID] {
+		if roleMemberEdge.Member.ID == memberID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isProjectMember reports if the member is the member owning the project
+func isProjectMember(ctx context.Context, readDBService readdb.ReadDBService, tl util.TimeLineNumber, projectID, memberID util.ID) (bool, error) {
+	projectMemberGroups, err := readDBService.ProjectMember(ctx, tl, []util.ID{projectID})
+	if err != nil {
+		return false, err
+	}
+	if member := projectMemberGroups[projectID]; member != nil && member.ID == memberID {
+		return true, nil
+	}
+	return false, nil
+}
+
+// CreateProject creates a new project owned by the role and by a member
+// filling it
+func (s *CommandService) CreateProject(ctx context.Context, c *change.CreateProjectChange) (*change.CreateProjectResult, util.ID, error) {
+	res := &change.CreateProjectResult{}
+	if c.Title == "" {
+		res.HasErrors = true
+		res.CreateProjectChangeErrors.Title = errors.Errorf("empty project title")
+	}
+	if len([]rune(c.Title)) > MaxProjectTitleLength {
+		res.HasErrors = true
+		res.CreateProjectChangeErrors.Title = errors.Errorf("title too long")
+	}
+	if len([]rune(c.Description)) > MaxProjectDescriptionLength {
+		res.HasErrors = true
+		res.CreateProjectChangeErrors.Description = errors.Errorf("description too long")
+	}
+
+	if res.HasErrors {
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	// only the member itself or an admin can create a project owned by the
+	// member
+	if !callingMember.IsAdmin && callingMember.ID != c.MemberID {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	role, err := readDBService.Role(ctx, curTlSeq, c.RoleID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if role == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("role with id %s doesn't exist", c.RoleID)
+		return res, util.NilID, ErrValidation
+	}
+
+	member, err := readDBService.Member(ctx, curTlSeq, c.MemberID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if member == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member with id %s doesn't exist", c.MemberID)
+		return res, util.NilID, ErrValidation
+	}
+
+	isFiller, err := isRoleFiller(ctx, readDBService, curTlSeq, role, member.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !isFiller {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member %s doesn't fill role", member.ID)
+		return res, util.NilID, ErrValidation
+	}
+
+	projectID := s.uidGenerator.UUID(c.Title)
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeCreateProject, correlationID, causationID, callingMember.ID, commands.NewCommandCreateProject(c))
+
+	pr := aggregate.NewProjectRepository(s.es, s.uidGenerator)
+	p, err := pr.Load(projectID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, p)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	res.ProjectID = &projectID
+
+	return res, groupID, nil
+}
+
+// UpdateProject updates the project title and description. Only the project
+// member or an admin can update it.
+func (s *CommandService) UpdateProject(ctx context.Context, c *change.UpdateProjectChange) (*change.UpdateProjectResult, util.ID, error) {
+	res := &change.UpdateProjectResult{}
+	if c.Title == "" {
+		res.HasErrors = true
+		res.UpdateProjectChangeErrors.Title = errors.Errorf("empty project title")
+	}
+	if len([]rune(c.Title)) > MaxProjectTitleLength {
+		res.HasErrors = true
+		res.UpdateProjectChangeErrors.Title = errors.Errorf("title too long")
+	}
+	if len([]rune(c.Description)) > MaxProjectDescriptionLength {
+		res.HasErrors = true
+		res.UpdateProjectChangeErrors.Description = errors.Errorf("description too long")
+	}
+
+	if res.HasErrors {
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	project, err := readDBService.Project(ctx, curTlSeq, c.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if project == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("project with id %s doesn't exist", c.ID)
+		return res, util.NilID, ErrValidation
+	}
+	if project.Status == models.ProjectStatusDone {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("project is done")
+		return res, util.NilID, ErrValidation
+	}
+
+	isMember, err := isProjectMember(ctx, readDBService, curTlSeq, project.ID, callingMember.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !callingMember.IsAdmin && !isMember {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeUpdateProject, correlationID, causationID, callingMember.ID, commands.NewCommandUpdateProject(c))
+
+	pr := aggregate.NewProjectRepository(s.es, s.uidGenerator)
+	p, err := pr.Load(project.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, p)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// ChangeProjectStatus changes the project status. Only the project member or
+// an admin can change it.
+func (s *CommandService) ChangeProjectStatus(ctx context.Context, projectID util.ID, status models.ProjectStatus) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+	if status == "" {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("wrong project status")
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	project, err := readDBService.Project(ctx, curTlSeq, projectID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if project == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("project with id %s doesn't exist", projectID)
+		return res, util.NilID, ErrValidation
+	}
+	if project.Status == status {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("project already in status %s", status)
+		return res, util.NilID, ErrValidation
+	}
+
+	isMember, err := isProjectMember(ctx, readDBService, curTlSeq, project.ID, callingMember.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !callingMember.IsAdmin && !isMember {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeChangeProjectStatus, correlationID, causationID, callingMember.ID, &commands.ChangeProjectStatus{Status: status})
+
+	pr := aggregate.NewProjectRepository(s.es, s.uidGenerator)
+	p, err := pr.Load(project.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, p)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// AddProjectNextAction adds a next action to the project. Only the project
+// member or an admin can add it.
+func (s *CommandService) AddProjectNextAction(ctx context.Context, c *change.AddProjectNextActionChange) (*change.AddProjectNextActionResult, util.ID, error) {
+	res := &change.AddProjectNextActionResult{}
+	if c.Description == "" {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("empty next action description")
+		return res, util.NilID, ErrValidation
+	}
+	if len([]rune(c.Description)) > MaxNextActionDescriptionLength {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("next action description too long")
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	project, err := readDBService.Project(ctx, curTlSeq, c.ProjectID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if project == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("project with id %s doesn't exist", c.ProjectID)
+		return res, util.NilID, ErrValidation
+	}
+	if project.Status == models.ProjectStatusDone {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("project is done")
+		return res, util.NilID, ErrValidation
+	}
+
+	isMember, err := isProjectMember(ctx, readDBService, curTlSeq, project.ID, callingMember.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !callingMember.IsAdmin && !isMember {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	nextActionID := s.uidGenerator.UUID(c.Description)
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeAddProjectNextAction, correlationID, causationID, callingMember.ID, commands.NewCommandAddProjectNextAction(nextActionID, c))
+
+	pr := aggregate.NewProjectRepository(s.es, s.uidGenerator)
+	p, err := pr.Load(project.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, p)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	res.NextActionID = &nextActionID
+
+	return res, groupID, nil
+}
+
+// CompleteProjectNextAction marks a project next action as completed. Only
+// the project member or an admin can complete it.
+func (s *CommandService) CompleteProjectNextAction(ctx context.Context, projectID, nextActionID util.ID) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	project, err := readDBService.Project(ctx, curTlSeq, projectID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if project == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("project with id %s doesn't exist", projectID)
+		return res, util.NilID, ErrValidation
+	}
+	if project.Status == models.ProjectStatusDone {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("project is done")
+		return res, util.NilID, ErrValidation
+	}
+
+	nextActions, err := readDBService.ProjectNextActions(ctx, curTlSeq, []util.ID{project.ID})
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	var nextAction *models.NextAction
+	for _, na := range nextActions[project.ID] {
+		if na.ID == nextActionID {
+			nextAction = na
+			break
+		}
+	}
+	if nextAction == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("next action with id %s doesn't exist", nextActionID)
+		return res, util.NilID, ErrValidation
+	}
+	if nextAction.Completed {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("next action already completed")
+		return res, util.NilID, ErrValidation
+	}
+
+	isMember, err := isProjectMember(ctx, readDBService, curTlSeq, project.ID, callingMember.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !callingMember.IsAdmin && !isMember {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeCompleteProjectNextAction, correlationID, causationID, callingMember.ID, &commands.CompleteProjectNextAction{NextActionID: nextActionID})
+
+	pr := aggregate.NewProjectRepository(s.es, s.uidGenerator)
+	p, err := pr.Load(project.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, p)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// isCircleChildRole reports if the role is a child role of the circle
+func isCircleChildRole(ctx context.Context, readDBService readdb.ReadDBService, tl util.TimeLineNumber, circleID, roleID util.ID) (bool, error) {
+	proleGroups, err := readDBService.RoleParent(ctx, tl, []util.ID{roleID})
+	if err != nil {
+		return false, err
+	}
+	prole := proleGroups[roleID]
+	return prole != nil && prole.ID == circleID, nil
+}
+
+// CreateReportItem creates a new circle checklist item or metric assigned to a
+// circle child role. Only the circle lead link or secretary can create it.
+func (s *CommandService) CreateReportItem(ctx context.Context, c *change.CreateReportItemChange) (*change.CreateReportItemResult, util.ID, error) {
+	res := &change.CreateReportItemResult{}
+	if c.ReportItemType == "" {
+		res.HasErrors = true
+		res.CreateReportItemChangeErrors.ReportItemType = errors.Errorf("wrong report item type")
+	}
+	if c.Frequency == "" {
+		res.HasErrors = true
+		res.CreateReportItemChangeErrors.Frequency = errors.Errorf("wrong report item frequency")
+	}
+	if c.Description == "" {
+		res.HasErrors = true
+		res.CreateReportItemChangeErrors.Description = errors.Errorf("empty report item description")
+	}
+	if len([]rune(c.Description)) > MaxReportItemDescriptionLength {
+		res.HasErrors = true
+		res.CreateReportItemChangeErrors.Description = errors.Errorf("description too long")
+	}
+
+	if res.HasErrors {
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	circle, err := readDBService.Role(ctx, curTlSeq, c.CircleID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if circle == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("role with id %s doesn't exist", c.CircleID)
+		return res, util.NilID, ErrValidation
+	}
+	if circle.RoleType != models.RoleTypeCircle {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("role with id %s isn't a circle", c.CircleID)
+		return res, util.NilID, ErrValidation
+	}
+
+	cp, err := readDBService.MemberCirclePermissions(ctx, curTlSeq, circle.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !cp.ManageReportItems {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	isChildRole, err := isCircleChildRole(ctx, readDBService, curTlSeq, circle.ID, c.RoleID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !isChildRole {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("role with id %s doesn't have parent circle with id %s", c.RoleID, circle.ID)
+		return res, util.NilID, ErrValidation
+	}
+
+	reportItemID := s.uidGenerator.UUID(c.Description)
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeCreateReportItem, correlationID, causationID, callingMember.ID, commands.NewCommandCreateReportItem(c))
+
+	rr := aggregate.NewReportItemRepository(s.es, s.uidGenerator)
+	r, err := rr.Load(reportItemID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, r)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	res.ReportItemID = &reportItemID
+
+	return res, groupID, nil
+}
+
+// UpdateReportItem updates the report item description and assigned role.
+// Only the circle lead link or secretary can update it.
+func (s *CommandService) UpdateReportItem(ctx context.Context, c *change.UpdateReportItemChange) (*change.UpdateReportItemResult, util.ID, error) {
+	res := &change.UpdateReportItemResult{}
+	if c.Description == "" {
+		res.HasErrors = true
+		res.UpdateReportItemChangeErrors.Description = errors.Errorf("empty report item description")
+	}
+	if len([]rune(c.Description)) > MaxReportItemDescriptionLength {
+		res.HasErrors = true
+		res.UpdateReportItemChangeErrors.Description = errors.Errorf("description too long")
+	}
+
+	if res.HasErrors {
+		return res, util.NilID, ErrValidation
+	}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	reportItem, err := readDBService.ReportItem(ctx, curTlSeq, c.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if reportItem == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("report item with id %s doesn't exist", c.ID)
+		return res, util.NilID, ErrValidation
+	}
+
+	circleGroups, err := readDBService.ReportItemCircle(ctx, curTlSeq, []util.ID{reportItem.ID})
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	circle := circleGroups[reportItem.ID]
+	if circle == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("report item circle doesn't exist")
+		return res, util.NilID, ErrValidation
+	}
+
+	cp, err := readDBService.MemberCirclePermissions(ctx, curTlSeq, circle.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !cp.ManageReportItems {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	isChildRole, err := isCircleChildRole(ctx, readDBService, curTlSeq, circle.ID, c.RoleID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if !isChildRole {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("role with id %s doesn't have parent circle with id %s", c.RoleID, circle.ID)
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeUpdateReportItem, correlationID, causationID, callingMember.ID, commands.NewCommandUpdateReportItem(c))
+
+	rr := aggregate.NewReportItemRepository(s.es, s.uidGenerator)
+	r, err := rr.Load(reportItem.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, r)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// DeleteReportItem deletes a report item. Only the circle lead link or
+// secretary can delete it.
+func (s *CommandService) DeleteReportItem(ctx context.Context, reportItemID util.ID) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	reportItem, err := readDBService.ReportItem(ctx, curTlSeq, reportItemID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if reportItem == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("report item with id %s doesn't exist", reportItemID)
+		return res, util.NilID, ErrValidation
+	}
+
+	circleGroups, err := readDBService.ReportItemCircle(ctx, curTlSeq, []util.ID{reportItem.ID})
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	// report items of a deleted circle can be deleted only by an admin
+	if circle := circleGroups[reportItem.ID]; circle != nil {
+		cp, err := readDBService.MemberCirclePermissions(ctx, curTlSeq, circle.ID)
+		if err != nil {
+			return nil, util.NilID, err
+		}
+		if !cp.ManageReportItems {
+			res.HasErrors = true
+			res.GenericError = errors.Errorf("member not authorized")
+			return res, util.NilID, ErrValidation
+		}
+	} else if !callingMember.IsAdmin {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeDeleteReportItem, correlationID, causationID, callingMember.ID, &commands.DeleteReportItem{})
+
+	rr := aggregate.NewReportItemRepository(s.es, s.uidGenerator)
+	r, err := rr.Load(reportItem.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, r)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	return res, groupID, nil
+}
+
+// SetReportItemValue reports the report item value for the period containing
+// the provided time. Only the members filling the assigned role or the circle
+// lead link or secretary can report it.
+func (s *CommandService) SetReportItemValue(ctx context.Context, c *change.SetReportItemValueChange) (*change.GenericResult, util.ID, error) {
+	res := &change.GenericResult{}
+
+	tx, err := s.db.NewTx()
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	defer tx.Rollback()
+	readDBService, err := readdb.NewReadDBService(tx)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	curTl := readDBService.CurTimeLine(ctx)
+	curTlSeq := curTl.Number()
+
+	callingMember, err := readDBService.CallingMember(ctx, curTlSeq)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	reportItem, err := readDBService.ReportItem(ctx, curTlSeq, c.ReportItemID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	if reportItem == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("report item with id %s doesn't exist", c.ReportItemID)
+		return res, util.NilID, ErrValidation
+	}
+
+	period := reportItem.Frequency.PeriodStart(c.Period)
+	if period.After(time.Now()) {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("cannot report a value for a future period")
+		return res, util.NilID, ErrValidation
+	}
+	if reportItem.ReportItemType == models.ReportItemTypeChecklist && c.Value != 0 && c.Value != 1 {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("checklist item value must be 0 or 1")
+		return res, util.NilID, ErrValidation
+	}
+
+	circleGroups, err := readDBService.ReportItemCircle(ctx, curTlSeq, []util.ID{reportItem.ID})
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	circle := circleGroups[reportItem.ID]
+	if circle == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("report item circle doesn't exist")
+		return res, util.NilID, ErrValidation
+	}
+
+	cp, err := readDBService.MemberCirclePermissions(ctx, curTlSeq, circle.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+	authorized := cp.ManageReportItems
+	if !authorized {
+		roleGroups, err := readDBService.ReportItemRole(ctx, curTlSeq, []util.ID{reportItem.ID})
+		if err != nil {
+			return nil, util.NilID, err
+		}
+		if role := roleGroups[reportItem.ID]; role != nil {
+			authorized, err = isRoleFiller(ctx, readDBService, curTlSeq, role, callingMember.ID)
+			if err != nil {
+				return nil, util.NilID, err
+			}
+		}
+	}
+	if !authorized {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("member not authorized")
+		return res, util.NilID, ErrValidation
+	}
+
+	correlationID := s.uidGenerator.UUID("")
+	causationID := s.uidGenerator.UUID("")
+	command := commands.NewCommand(commands.CommandTypeSetReportItemValue, correlationID, causationID, callingMember.ID, &commands.SetReportItemValue{Period: period, Value: c.Value})
+
+	rr := aggregate.NewReportItemRepository(s.es, s.uidGenerator)
+	r, err := rr.Load(reportItem.ID)
+	if err != nil {
+		return nil, util.NilID, err
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, r)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1508,7 +4134,7 @@ func (s *CommandService) CloseTension(ctx context.Context, c *change.CloseTensio
 }
 
 // CircleAddDirectMember adds a member as a core role member the specified circle
-func (s *CommandService) CircleAddDirectMember(ctx context.Context, roleID util.ID, memberID util.ID) (*change.GenericResult, util.ID, error) {
+func (s *CommandService) CircleAddDirectMember(ctx context.Context, roleID util.ID, memberID util.ID, expectedTl *util.TimeLineNumber) (*change.GenericResult, util.ID, error) {
 	res := &change.GenericResult{}
 
 	tx, err := s.db.NewTx()
@@ -1571,7 +4197,17 @@ func (s *CommandService) CircleAddDirectMember(ctx context.Context, roleID util.
 		return res, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleMembersConflict(ctx, readDBService, rt, roleID, expectedTl)
+	if err != nil {
+		return res, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1579,7 +4215,7 @@ func (s *CommandService) CircleAddDirectMember(ctx context.Context, roleID util.
 	return res, groupID, nil
 }
 
-func (s *CommandService) CircleRemoveDirectMember(ctx context.Context, roleID util.ID, memberID util.ID) (*change.GenericResult, util.ID, error) {
+func (s *CommandService) CircleRemoveDirectMember(ctx context.Context, roleID util.ID, memberID util.ID, expectedTl *util.TimeLineNumber) (*change.GenericResult, util.ID, error) {
 	res := &change.GenericResult{}
 
 	tx, err := s.db.NewTx()
@@ -1646,7 +4282,17 @@ func (s *CommandService) CircleRemoveDirectMember(ctx context.Context, roleID ut
 		return res, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleMembersConflict(ctx, readDBService, rt, roleID, expectedTl)
+	if err != nil {
+		return res, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1654,7 +4300,7 @@ func (s *CommandService) CircleRemoveDirectMember(ctx context.Context, roleID ut
 	return res, groupID, nil
 }
 
-func (s *CommandService) CircleSetLeadLinkMember(ctx context.Context, roleID, memberID util.ID) (*change.GenericResult, util.ID, error) {
+func (s *CommandService) CircleSetLeadLinkMember(ctx context.Context, roleID, memberID util.ID, expectedTl *util.TimeLineNumber) (*change.GenericResult, util.ID, error) {
 	res := &change.GenericResult{}
 
 	tx, err := s.db.NewTx()
@@ -1738,7 +4384,17 @@ func (s *CommandService) CircleSetLeadLinkMember(ctx context.Context, roleID, me
 		return res, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleMembersConflict(ctx, readDBService, rt, roleID, expectedTl)
+	if err != nil {
+		return res, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1746,7 +4402,7 @@ func (s *CommandService) CircleSetLeadLinkMember(ctx context.Context, roleID, me
 	return res, groupID, nil
 }
 
-func (s *CommandService) CircleUnsetLeadLinkMember(ctx context.Context, roleID util.ID) (*change.GenericResult, util.ID, error) {
+func (s *CommandService) CircleUnsetLeadLinkMember(ctx context.Context, roleID util.ID, expectedTl *util.TimeLineNumber) (*change.GenericResult, util.ID, error) {
 	res := &change.GenericResult{}
 
 	tx, err := s.db.NewTx()
@@ -1837,7 +4493,17 @@ func (s *CommandService) CircleUnsetLeadLinkMember(ctx context.Context, roleID u
 		return res, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleMembersConflict(ctx, readDBService, rt, roleID, expectedTl)
+	if err != nil {
+		return res, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1845,9 +4511,15 @@ func (s *CommandService) CircleUnsetLeadLinkMember(ctx context.Context, roleID u
 	return res, groupID, nil
 }
 
-func (s *CommandService) CircleSetCoreRoleMember(ctx context.Context, roleType models.RoleType, roleID, memberID util.ID, electionExpiration *time.Time) (*change.GenericResult, util.ID, error) {
+func (s *CommandService) CircleSetCoreRoleMember(ctx context.Context, roleType models.RoleType, roleID, memberID util.ID, electionExpiration *time.Time, expectedTl *util.TimeLineNumber) (*change.GenericResult, util.ID, error) {
 	res := &change.GenericResult{}
 
+	if s.holacracyStrictMode && roleType.IsElectedRoleType() && electionExpiration == nil {
+		res.HasErrors = true
+		res.GenericError = errors.Errorf("an election expiration is required for elected role %s", roleType)
+		return res, util.NilID, ErrValidation
+	}
+
 	tx, err := s.db.NewTx()
 	if err != nil {
 		return nil, util.NilID, err
@@ -1908,7 +4580,17 @@ func (s *CommandService) CircleSetCoreRoleMember(ctx context.Context, roleType m
 		return res, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleMembersConflict(ctx, readDBService, rt, roleID, expectedTl)
+	if err != nil {
+		return res, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1916,7 +4598,7 @@ func (s *CommandService) CircleSetCoreRoleMember(ctx context.Context, roleType m
 	return res, groupID, nil
 }
 
-func (s *CommandService) CircleUnsetCoreRoleMember(ctx context.Context, roleType models.RoleType, roleID util.ID) (*change.GenericResult, util.ID, error) {
+func (s *CommandService) CircleUnsetCoreRoleMember(ctx context.Context, roleType models.RoleType, roleID util.ID, expectedTl *util.TimeLineNumber) (*change.GenericResult, util.ID, error) {
 	res := &change.GenericResult{}
 
 	tx, err := s.db.NewTx()
@@ -1987,7 +4669,17 @@ func (s *CommandService) CircleUnsetCoreRoleMember(ctx context.Context, roleType
 		return res, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleMembersConflict(ctx, readDBService, rt, roleID, expectedTl)
+	if err != nil {
+		return res, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1995,7 +4687,7 @@ func (s *CommandService) CircleUnsetCoreRoleMember(ctx context.Context, roleType
 	return res, groupID, nil
 }
 
-func (s *CommandService) RoleAddMember(ctx context.Context, roleID util.ID, memberID util.ID, focus *string, noCoreMember bool) (*change.GenericResult, util.ID, error) {
+func (s *CommandService) RoleAddMember(ctx context.Context, roleID util.ID, memberID util.ID, focus *string, noCoreMember bool, expectedTl *util.TimeLineNumber) (*change.GenericResult, util.ID, error) {
 	res := &change.GenericResult{}
 
 	if focus != nil {
@@ -2082,7 +4774,17 @@ func (s *CommandService) RoleAddMember(ctx context.Context, roleID util.ID, memb
 		return res, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleMembersConflict(ctx, readDBService, rt, roleID, expectedTl)
+	if err != nil {
+		return res, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -2090,7 +4792,7 @@ func (s *CommandService) RoleAddMember(ctx context.Context, roleID util.ID, memb
 	return res, groupID, nil
 }
 
-func (s *CommandService) RoleRemoveMember(ctx context.Context, roleID util.ID, memberID util.ID) (*change.GenericResult, util.ID, error) {
+func (s *CommandService) RoleRemoveMember(ctx context.Context, roleID util.ID, memberID util.ID, expectedTl *util.TimeLineNumber) (*change.GenericResult, util.ID, error) {
 	res := &change.GenericResult{}
 
 	tx, err := s.db.NewTx()
@@ -2164,7 +4866,17 @@ func (s *CommandService) RoleRemoveMember(ctx context.Context, roleID util.ID, m
 		return res, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	conflict, err := s.checkRoleMembersConflict(ctx, readDBService, rt, roleID, expectedTl)
+	if err != nil {
+		return res, util.NilID, err
+	}
+	if conflict != nil {
+		res.HasErrors = true
+		res.GenericError = conflict
+		return res, util.NilID, ErrValidation
+	}
+
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -2255,7 +4967,7 @@ func (s *CommandService) RoleUpdateMember(ctx context.Context, roleID util.ID, m
 		return res, util.NilID, err
 	}
 
-	groupID, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator)
+	groupID, _, err := s.execCommand(ctx, command, rt)
 	if err != nil {
 		return nil, util.NilID, err
 	}
@@ -1,6 +1,12 @@
 package command
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+)
 
 var (
 	goodUserNames = []string{
@@ -39,3 +45,17 @@ func TestIsUserNameValidFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestHolacracyStrictModeElectionExpiration(t *testing.T) {
+	s := NewCommandService("", nil, nil, nil, nil, false, true)
+
+	for _, roleType := range []models.RoleType{models.RoleTypeFacilitator, models.RoleTypeSecretary, models.RoleTypeRepLink} {
+		res, _, err := s.CircleSetCoreRoleMember(context.Background(), roleType, util.NilID, util.NilID, nil, nil)
+		if err != ErrValidation {
+			t.Fatalf("expected validation error for role type %q, got: %v", roleType, err)
+		}
+		if !res.HasErrors {
+			t.Fatalf("expected result errors for role type %q", roleType)
+		}
+	}
+}
@@ -25,6 +25,8 @@ const (
 	CommandTypeCircleUpdateChildRole CommandType = "CircleUpdateChildRole"
 	CommandTypeCircleDeleteChildRole CommandType = "CircleDeleteChildRole"
 
+	CommandTypeCircleApplyProposal CommandType = "CircleApplyProposal"
+
 	CommandTypeSetRoleAdditionalContent CommandType = "SetRoleAdditionalContent"
 
 	CommandTypeCompleteRequest CommandType = "CompleteRequest"
@@ -32,17 +34,48 @@ const (
 	CommandTypeRequestCreateMember      CommandType = "RequestCreateMember"
 	CommandTypeRequestUpdateMember      CommandType = "RequestUpdateMember"
 	CommandTypeRequestSetMemberMatchUID CommandType = "RequestSetMemberMatchUID"
+	CommandTypeRequestDeleteMember      CommandType = "RequestDeleteMember"
 
 	CommandTypeCreateMember      CommandType = "CreateMember"
 	CommandTypeUpdateMember      CommandType = "UpdateMember"
 	CommandTypeDeleteMember      CommandType = "DeleteMember"
 	CommandTypeSetMemberPassword CommandType = "SetMemberPassword"
 	CommandTypeSetMemberMatchUID CommandType = "SetMemberMatchUID"
-
-	CommandTypeCreateTension     CommandType = "CreateTension"
-	CommandTypeUpdateTension     CommandType = "UpdateTension"
-	CommandTypeChangeTensionRole CommandType = "ChangeTensionRole"
-	CommandTypeCloseTension      CommandType = "CloseTension"
+	CommandTypeForgetMember      CommandType = "ForgetMember"
+
+	CommandTypeCreateTension      CommandType = "CreateTension"
+	CommandTypeUpdateTension      CommandType = "UpdateTension"
+	CommandTypeChangeTensionRole  CommandType = "ChangeTensionRole"
+	CommandTypeCloseTension       CommandType = "CloseTension"
+	CommandTypeReopenTension      CommandType = "ReopenTension"
+	CommandTypeChangeTensionState CommandType = "ChangeTensionState"
+	CommandTypeAddTensionComment  CommandType = "AddTensionComment"
+	CommandTypeAssignTension      CommandType = "AssignTension"
+	CommandTypeSetTensionLabels   CommandType = "SetTensionLabels"
+
+	CommandTypeCreateProposal CommandType = "CreateProposal"
+	CommandTypeUpdateProposal CommandType = "UpdateProposal"
+	CommandTypeObjectProposal CommandType = "ObjectProposal"
+	CommandTypeAcceptProposal CommandType = "AcceptProposal"
+	// RevertProposalAcceptance is a compensating command issued when an
+	// accepted proposal cannot be applied to the roles tree
+	CommandTypeRevertProposalAcceptance CommandType = "RevertProposalAcceptance"
+
+	CommandTypeCreateMeeting               CommandType = "CreateMeeting"
+	CommandTypeAddMeetingAgendaItem        CommandType = "AddMeetingAgendaItem"
+	CommandTypeSetMeetingAgendaItemOutcome CommandType = "SetMeetingAgendaItemOutcome"
+	CommandTypeCloseMeeting                CommandType = "CloseMeeting"
+
+	CommandTypeCreateProject             CommandType = "CreateProject"
+	CommandTypeUpdateProject             CommandType = "UpdateProject"
+	CommandTypeChangeProjectStatus       CommandType = "ChangeProjectStatus"
+	CommandTypeAddProjectNextAction      CommandType = "AddProjectNextAction"
+	CommandTypeCompleteProjectNextAction CommandType = "CompleteProjectNextAction"
+
+	CommandTypeCreateReportItem   CommandType = "CreateReportItem"
+	CommandTypeUpdateReportItem   CommandType = "UpdateReportItem"
+	CommandTypeDeleteReportItem   CommandType = "DeleteReportItem"
+	CommandTypeSetReportItemValue CommandType = "SetReportItemValue"
 
 	CommandTypeCircleAddDirectMember    CommandType = "CircleAddDirectMember"
 	CommandTypeCircleRemoveDirectMember CommandType = "CircleRemoveDirectMember"
@@ -53,10 +86,14 @@ const (
 	CommandTypeCircleSetCoreRoleMember   CommandType = "CircleSetCoreRoleMember"
 	CommandTypeCircleUnsetCoreRoleMember CommandType = "CircleUnsetCoreRoleMember"
 
+	CommandTypeCircleExpireCoreRoleElection CommandType = "CircleExpireCoreRoleElection"
+
 	CommandTypeRoleAddMember    CommandType = "RoleAddMember"
 	CommandTypeRoleUpdateMember CommandType = "RoleUpdateMember"
 	CommandTypeRoleRemoveMember CommandType = "RoleRemoveMember"
 
+	CommandTypeRemoveMemberFromRoles CommandType = "RemoveMemberFromRoles"
+
 	CommandTypeReserveValue CommandType = "ReserveValue"
 	CommandTypeReleaseValue CommandType = "ReleaseValue"
 )
@@ -68,6 +105,17 @@ type Command struct {
 	CausationID   util.ID
 	IssuerID      util.ID
 	Data          interface{}
+
+	// RequestInfo contains the information of the request issuing the
+	// command, it's nil for commands issued internally (i.e. by sagas)
+	RequestInfo *RequestInfo `json:",omitempty"`
+}
+
+// RequestInfo contains the information of the client request issuing a
+// command, recorded in the command audit log
+type RequestInfo struct {
+	RemoteAddr string
+	UserAgent  string
 }
 
 func NewCommand(commandType CommandType, correlationID, causationID, issuerID util.ID, commandData interface{}) *Command {
@@ -107,6 +155,15 @@ type CircleDeleteChildRole struct {
 	DeleteRoleChange change.DeleteRoleChange
 }
 
+type CircleApplyProposal struct {
+	RoleID     util.ID
+	ProposalID util.ID
+	// NewRoleIDs are the ids of the roles created by the proposal
+	// CreateRoleChanges
+	NewRoleIDs []util.ID
+	change.ProposalChanges
+}
+
 type SetRoleAdditionalContent struct {
 	RoleID  util.ID
 	Content string
@@ -227,6 +284,19 @@ type SetMemberMatchUID struct {
 	MemberChangeID util.ID
 }
 
+type RequestDeleteMember struct {
+	MemberID util.ID
+	// TensionIDs are the member open tensions that will be closed after
+	// the member deletion
+	TensionIDs []util.ID
+}
+
+type DeleteMember struct {
+	MemberChangeID util.ID
+}
+
+type ForgetMember struct{}
+
 type CreateTension struct {
 	Title       string
 	Description string
@@ -279,6 +349,227 @@ func NewCommandCloseTension(c *change.CloseTensionChange) *CloseTension {
 	}
 }
 
+type ReopenTension struct{}
+
+type ChangeTensionState struct {
+	State models.TensionState
+}
+
+type AddTensionComment struct {
+	CommentID       util.ID
+	ParentCommentID *util.ID
+	MemberID        util.ID
+	Text            string
+}
+
+func NewCommandAddTensionComment(commentID, memberID util.ID, c *change.AddTensionCommentChange) *AddTensionComment {
+	return &AddTensionComment{
+		CommentID:       commentID,
+		ParentCommentID: c.ParentCommentID,
+		MemberID:        memberID,
+		Text:            c.Text,
+	}
+}
+
+type AssignTension struct {
+	RoleID   *util.ID
+	MemberID *util.ID
+}
+
+func NewCommandAssignTension(c *change.AssignTensionChange) *AssignTension {
+	return &AssignTension{
+		RoleID:   c.RoleID,
+		MemberID: c.MemberID,
+	}
+}
+
+type SetTensionLabels struct {
+	Labels []string
+}
+
+type CreateProposal struct {
+	TensionID util.ID
+	RoleID    util.ID
+	MemberID  util.ID
+	change.ProposalChanges
+}
+
+func NewCommandCreateProposal(memberID util.ID, c *change.CreateProposalChange) *CreateProposal {
+	return &CreateProposal{
+		TensionID:       c.TensionID,
+		RoleID:          c.RoleID,
+		MemberID:        memberID,
+		ProposalChanges: c.ProposalChanges,
+	}
+}
+
+type UpdateProposal struct {
+	change.ProposalChanges
+}
+
+func NewCommandUpdateProposal(c *change.UpdateProposalChange) *UpdateProposal {
+	return &UpdateProposal{
+		ProposalChanges: c.ProposalChanges,
+	}
+}
+
+type ObjectProposal struct {
+	MemberID util.ID
+	Reason   string
+}
+
+func NewCommandObjectProposal(memberID util.ID, c *change.ObjectProposalChange) *ObjectProposal {
+	return &ObjectProposal{
+		MemberID: memberID,
+		Reason:   c.Reason,
+	}
+}
+
+type AcceptProposal struct {
+	MemberID util.ID
+}
+
+type RevertProposalAcceptance struct {
+	Reason string
+}
+
+type CreateMeeting struct {
+	RoleID        util.ID
+	MeetingType   models.MeetingType
+	Title         string
+	StartTime     time.Time
+	MemberID      util.ID
+	FacilitatorID *util.ID
+	SecretaryID   *util.ID
+	AttendeesIDs  []util.ID
+}
+
+func NewCommandCreateMeeting(memberID util.ID, facilitatorID, secretaryID *util.ID, c *change.CreateMeetingChange) *CreateMeeting {
+	return &CreateMeeting{
+		RoleID:        c.RoleID,
+		MeetingType:   c.MeetingType,
+		Title:         c.Title,
+		StartTime:     c.StartTime,
+		MemberID:      memberID,
+		FacilitatorID: facilitatorID,
+		SecretaryID:   secretaryID,
+		AttendeesIDs:  c.AttendeesIDs,
+	}
+}
+
+type AddMeetingAgendaItem struct {
+	AgendaItemID util.ID
+	TensionID    *util.ID
+	Title        string
+}
+
+func NewCommandAddMeetingAgendaItem(agendaItemID util.ID, c *change.AddMeetingAgendaItemChange) *AddMeetingAgendaItem {
+	return &AddMeetingAgendaItem{
+		AgendaItemID: agendaItemID,
+		TensionID:    c.TensionID,
+		Title:        c.Title,
+	}
+}
+
+type SetMeetingAgendaItemOutcome struct {
+	AgendaItemID util.ID
+	Outcome      string
+}
+
+func NewCommandSetMeetingAgendaItemOutcome(c *change.SetMeetingAgendaItemOutcomeChange) *SetMeetingAgendaItemOutcome {
+	return &SetMeetingAgendaItemOutcome{
+		AgendaItemID: c.AgendaItemID,
+		Outcome:      c.Outcome,
+	}
+}
+
+type CloseMeeting struct{}
+
+type CreateProject struct {
+	RoleID      util.ID
+	MemberID    util.ID
+	Title       string
+	Description string
+}
+
+func NewCommandCreateProject(c *change.CreateProjectChange) *CreateProject {
+	return &CreateProject{
+		RoleID:      c.RoleID,
+		MemberID:    c.MemberID,
+		Title:       c.Title,
+		Description: c.Description,
+	}
+}
+
+type UpdateProject struct {
+	Title       string
+	Description string
+}
+
+func NewCommandUpdateProject(c *change.UpdateProjectChange) *UpdateProject {
+	return &UpdateProject{
+		Title:       c.Title,
+		Description: c.Description,
+	}
+}
+
+type ChangeProjectStatus struct {
+	Status models.ProjectStatus
+}
+
+type AddProjectNextAction struct {
+	NextActionID util.ID
+	Description  string
+}
+
+func NewCommandAddProjectNextAction(nextActionID util.ID, c *change.AddProjectNextActionChange) *AddProjectNextAction {
+	return &AddProjectNextAction{
+		NextActionID: nextActionID,
+		Description:  c.Description,
+	}
+}
+
+type CompleteProjectNextAction struct {
+	NextActionID util.ID
+}
+
+type CreateReportItem struct {
+	CircleID       util.ID
+	RoleID         util.ID
+	ReportItemType models.ReportItemType
+	Description    string
+	Frequency      models.ReportFrequency
+}
+
+func NewCommandCreateReportItem(c *change.CreateReportItemChange) *CreateReportItem {
+	return &CreateReportItem{
+		CircleID:       c.CircleID,
+		RoleID:         c.RoleID,
+		ReportItemType: c.ReportItemType,
+		Description:    c.Description,
+		Frequency:      c.Frequency,
+	}
+}
+
+type UpdateReportItem struct {
+	RoleID      util.ID
+	Description string
+}
+
+func NewCommandUpdateReportItem(c *change.UpdateReportItemChange) *UpdateReportItem {
+	return &UpdateReportItem{
+		RoleID:      c.RoleID,
+		Description: c.Description,
+	}
+}
+
+type DeleteReportItem struct{}
+
+type SetReportItemValue struct {
+	Period time.Time
+	Value  float64
+}
+
 type CircleAddDirectMember struct {
 	RoleID   util.ID
 	MemberID util.ID
@@ -310,6 +601,13 @@ type CircleUnsetCoreRoleMember struct {
 	RoleID   util.ID
 }
 
+type CircleExpireCoreRoleElection struct {
+	RoleType           models.RoleType
+	RoleID             util.ID
+	MemberID           util.ID
+	ElectionExpiration time.Time
+}
+
 type RoleAddMember struct {
 	RoleID       util.ID
 	MemberID     util.ID
@@ -329,6 +627,10 @@ type RoleRemoveMember struct {
 	MemberID util.ID
 }
 
+type RemoveMemberFromRoles struct {
+	MemberID util.ID
+}
+
 type ReserveValue struct {
 	Value     string
 	ID        util.ID
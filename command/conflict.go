@@ -0,0 +1,244 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sorintlab/sircles/aggregate"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+)
+
+const conflictCheckBatchSize = 100
+
+// ConflictError is the result generic error reported when the entity to
+// change has been changed after the timeline expected by the client (the
+// timeline at which the client read the entity)
+type ConflictError struct {
+	// Kind is the kind of the changed entity (role, tension)
+	Kind             string
+	ID               util.ID
+	ExpectedTimeLine util.TimeLineNumber
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s changed after timeline %d", e.Kind, e.ExpectedTimeLine)
+}
+
+// roleDefinitionEvents are the roles tree events changing a role definition
+var roleDefinitionEvents = map[ep.EventType]struct{}{
+	ep.EventTypeRoleCreated:               {},
+	ep.EventTypeRoleUpdated:               {},
+	ep.EventTypeRoleDeleted:               {},
+	ep.EventTypeRoleDomainCreated:         {},
+	ep.EventTypeRoleDomainUpdated:         {},
+	ep.EventTypeRoleDomainDeleted:         {},
+	ep.EventTypeRoleAccountabilityCreated: {},
+	ep.EventTypeRoleAccountabilityUpdated: {},
+	ep.EventTypeRoleAccountabilityDeleted: {},
+	ep.EventTypeRoleAdditionalContentSet:  {},
+	ep.EventTypeRoleChangedParent:         {},
+}
+
+// roleDefinitionEventRoleID returns the id of the role changed by the role
+// definition event data
+func roleDefinitionEventRoleID(data interface{}) util.ID {
+	switch data := data.(type) {
+	case *ep.EventRoleCreated:
+		return data.RoleID
+	case *ep.EventRoleUpdated:
+		return data.RoleID
+	case *ep.EventRoleDeleted:
+		return data.RoleID
+	case *ep.EventRoleDomainCreated:
+		return data.RoleID
+	case *ep.EventRoleDomainUpdated:
+		return data.RoleID
+	case *ep.EventRoleDomainDeleted:
+		return data.RoleID
+	case *ep.EventRoleAccountabilityCreated:
+		return data.RoleID
+	case *ep.EventRoleAccountabilityUpdated:
+		return data.RoleID
+	case *ep.EventRoleAccountabilityDeleted:
+		return data.RoleID
+	case *ep.EventRoleAdditionalContentSet:
+		return data.RoleID
+	case *ep.EventRoleChangedParent:
+		return data.RoleID
+	}
+	return util.NilID
+}
+
+// checkRoleConflict returns a ConflictError if the definition of the role has
+// been changed after the expected timeline. Changes to the role members
+// aren't considered conflicting.
+func (s *CommandService) checkRoleConflict(ctx context.Context, readDBService readdb.ReadDBService, rt aggregate.Aggregate, roleID util.ID, expectedTl *util.TimeLineNumber) (*ConflictError, error) {
+	if expectedTl == nil {
+		return nil, nil
+	}
+	changed, err := s.changedAfter(ctx, readDBService, rt, *expectedTl, func(e *eventstore.StoredEvent) (bool, error) {
+		if _, ok := roleDefinitionEvents[ep.EventType(e.EventType)]; !ok {
+			return false, nil
+		}
+		data, err := ep.UnmarshalData(e)
+		if err != nil {
+			return false, err
+		}
+		return roleDefinitionEventRoleID(data) == roleID, nil
+	})
+	if err != nil || !changed {
+		return nil, err
+	}
+	return &ConflictError{Kind: "role", ID: roleID, ExpectedTimeLine: *expectedTl}, nil
+}
+
+// roleMembersEvents are the roles tree events changing a role members
+var roleMembersEvents = map[ep.EventType]struct{}{
+	ep.EventTypeRoleMemberAdded:           {},
+	ep.EventTypeRoleMemberUpdated:         {},
+	ep.EventTypeRoleMemberRemoved:         {},
+	ep.EventTypeCircleDirectMemberAdded:   {},
+	ep.EventTypeCircleDirectMemberRemoved: {},
+	ep.EventTypeCircleLeadLinkMemberSet:   {},
+	ep.EventTypeCircleLeadLinkMemberUnset: {},
+	ep.EventTypeCircleCoreRoleMemberSet:   {},
+	ep.EventTypeCircleCoreRoleMemberUnset: {},
+}
+
+// roleMembersEventRoleID returns the id of the role whose members are changed
+// by the role members event data
+func roleMembersEventRoleID(data interface{}) util.ID {
+	switch data := data.(type) {
+	case *ep.EventRoleMemberAdded:
+		return data.RoleID
+	case *ep.EventRoleMemberUpdated:
+		return data.RoleID
+	case *ep.EventRoleMemberRemoved:
+		return data.RoleID
+	case *ep.EventCircleDirectMemberAdded:
+		return data.RoleID
+	case *ep.EventCircleDirectMemberRemoved:
+		return data.RoleID
+	case *ep.EventCircleLeadLinkMemberSet:
+		return data.RoleID
+	case *ep.EventCircleLeadLinkMemberUnset:
+		return data.RoleID
+	case *ep.EventCircleCoreRoleMemberSet:
+		return data.RoleID
+	case *ep.EventCircleCoreRoleMemberUnset:
+		return data.RoleID
+	}
+	return util.NilID
+}
+
+// checkRoleMembersConflict returns a ConflictError if the definition or the
+// members of the role have been changed after the expected timeline. For a
+// circle the members are also its direct members, lead link and core roles
+// members.
+func (s *CommandService) checkRoleMembersConflict(ctx context.Context, readDBService readdb.ReadDBService, rt aggregate.Aggregate, roleID util.ID, expectedTl *util.TimeLineNumber) (*ConflictError, error) {
+	if expectedTl == nil {
+		return nil, nil
+	}
+	changed, err := s.changedAfter(ctx, readDBService, rt, *expectedTl, func(e *eventstore.StoredEvent) (bool, error) {
+		_, isDefinitionEvent := roleDefinitionEvents[ep.EventType(e.EventType)]
+		_, isMembersEvent := roleMembersEvents[ep.EventType(e.EventType)]
+		if !isDefinitionEvent && !isMembersEvent {
+			return false, nil
+		}
+		data, err := ep.UnmarshalData(e)
+		if err != nil {
+			return false, err
+		}
+		if isDefinitionEvent {
+			return roleDefinitionEventRoleID(data) == roleID, nil
+		}
+		return roleMembersEventRoleID(data) == roleID, nil
+	})
+	if err != nil || !changed {
+		return nil, err
+	}
+	return &ConflictError{Kind: "role", ID: roleID, ExpectedTimeLine: *expectedTl}, nil
+}
+
+// checkTensionConflict returns a ConflictError if the tension has been changed
+// after the expected timeline
+func (s *CommandService) checkTensionConflict(ctx context.Context, readDBService readdb.ReadDBService, t aggregate.Aggregate, tensionID util.ID, expectedTl *util.TimeLineNumber) (*ConflictError, error) {
+	if expectedTl == nil {
+		return nil, nil
+	}
+	changed, err := s.changedAfter(ctx, readDBService, t, *expectedTl, func(e *eventstore.StoredEvent) (bool, error) {
+		return true, nil
+	})
+	if err != nil || !changed {
+		return nil, err
+	}
+	return &ConflictError{Kind: "tension", ID: tensionID, ExpectedTimeLine: *expectedTl}, nil
+}
+
+// changedAfter reports if the aggregate has, up to its loaded version, an
+// event accepted by the filter written after the expected timeline.
+// The aggregate events are read from the last one until an event at or before
+// the expected timeline is found. Since the readdb applies the events in
+// sequence order, the scan also stops at the first event with a sequence
+// number not greater than the one of the last event applied at the expected
+// timeline. An event whose group timeline isn't yet in the readdb is newer
+// than every timeline known by the client.
+// Since the command events are written only if the aggregate version hasn't
+// changed since its loading, checking the events up to the loaded version
+// doesn't leave a window for undetected conflicting changes.
+func (s *CommandService) changedAfter(ctx context.Context, readDBService readdb.ReadDBService, a aggregate.Aggregate, expectedTl util.TimeLineNumber, filter func(e *eventstore.StoredEvent) (bool, error)) (bool, error) {
+	var lastGroupID util.ID
+	var lastTl *util.TimeLine
+
+	expectedSn, err := readDBService.TimeLineSequenceNumber(ctx, expectedTl)
+	if err != nil {
+		return false, err
+	}
+
+	for end := a.Version(); end > 0; {
+		start := end - conflictCheckBatchSize + 1
+		if start < 1 {
+			start = 1
+		}
+		events, err := s.es.GetEvents(a.ID(), start, uint64(end-start+1))
+		if err != nil {
+			return false, err
+		}
+		for i := len(events) - 1; i >= 0; i-- {
+			e := events[i]
+			if e.SequenceNumber <= expectedSn {
+				return false, nil
+			}
+			md, err := ep.UnmarshalMetaData(e)
+			if err != nil {
+				return false, err
+			}
+			if md.GroupID == nil {
+				continue
+			}
+			// the events of a group are consecutive
+			if *md.GroupID != lastGroupID {
+				lastGroupID = *md.GroupID
+				lastTl, err = readDBService.TimeLineForGroupID(ctx, lastGroupID)
+				if err != nil {
+					return false, err
+				}
+			}
+			if lastTl != nil && lastTl.Number() <= expectedTl {
+				return false, nil
+			}
+			ok, err := filter(e)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		end = start - 1
+	}
+	return false, nil
+}
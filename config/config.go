@@ -28,6 +28,11 @@ func Parse(configFile string) (*Config, error) {
 type Config struct {
 	Debug bool `json:"debug"`
 
+	// DataDir is the directory where the local aggregates and handlers
+	// snapshot dbs are saved. If empty a temporary directory, removed at exit,
+	// will be used and the snapshot dbs will be rebuilt at every start.
+	DataDir string `json:"dataDir"`
+
 	Web        Web        `json:"web"`
 	ReadDB     DB         `json:"readdb"`
 	EventStore EventStore `json:"eventStore"`
@@ -50,10 +55,21 @@ type Config struct {
 	//
 	// The provided string needs to be an existing member UserName (not email).
 	AdminMember string `json:"adminMember"`
+
+	// HolacracyStrictMode enforces some holacracy constitution rules not
+	// enforced by default. Currently it forbids assigning an elected core role
+	// (facilitator, secretary, rep link) without an election expiration.
+	HolacracyStrictMode bool `json:"holacracyStrictMode"`
+
+	// Webhooks are the endpoints where the selected events will be delivered
+	Webhooks []Webhook `json:"webhooks"`
 }
 
 var defaultConfig = Config{
 	CreateInitialAdmin: true,
+	EventStore: EventStore{
+		SnapshotInterval: 100,
+	},
 	Index: Index{
 		Path: filepath.Join(os.TempDir(), "sircles-index"),
 	},
@@ -85,8 +101,40 @@ type DB struct {
 }
 
 type EventStore struct {
+	// Type is the eventstore type: "sql", "file" or "memory"
 	Type string `json:"type"`
-	DB   DB     `json:"db"`
+	// DB is the database used by the "sql" eventstore
+	DB DB `json:"db"`
+	// Path is the directory where the "file" eventstore saves its data
+	Path string `json:"path"`
+	// SnapshotInterval is the number of aggregate events after which a new
+	// aggregate snapshot is saved (defaults to 100). 0 disables snapshots.
+	SnapshotInterval int64 `json:"snapshotInterval"`
+}
+
+type Webhook struct {
+	// Name is the unique name of the webhook, it's used to identify failed
+	// deliveries in the dead letter records
+	Name string `json:"name"`
+	// URL is the endpoint where the events will be POSTed
+	URL string `json:"url"`
+	// Secret is used to sign the request body with HMAC-SHA256. The signature
+	// is reported in the X-Sircles-Signature header
+	Secret string `json:"secret"`
+	// EventTypes are the event types to deliver (i.e. RoleCreated,
+	// TensionCreated). If empty all the deliverable event types will be
+	// delivered. Member events and the other events containing passwords or
+	// personal data cannot be delivered
+	EventTypes []string `json:"eventTypes"`
+	// CircleID, if defined, limits the delivered events to the ones related
+	// to the roles in the subtree of the provided circle (circle included).
+	// Events not related to a role (i.e. member events) won't be delivered.
+	CircleID string `json:"circleID"`
+	// MaxRetries is the number of retries on failed deliveries before
+	// recording a dead letter (defaults to 5 if 0)
+	MaxRetries int `json:"maxRetries"`
+	// Timeout is the request timeout in seconds (defaults to 10 if 0)
+	Timeout uint `json:"timeout"`
 }
 
 type Index struct {
@@ -27,6 +27,39 @@ type tlDataLoaders struct {
 	TensionMember         dataloader.Interface
 	RoleTensions          dataloader.Interface
 	TensionRole           dataloader.Interface
+	TensionAssignee       dataloader.Interface
+	TensionAssigneeRole   dataloader.Interface
+	TensionComments       dataloader.Interface
+	CommentParent         dataloader.Interface
+	CommentReplies        dataloader.Interface
+	CommentMember         dataloader.Interface
+	TensionLabels         dataloader.Interface
+	TensionProposals      dataloader.Interface
+	ProposalTension       dataloader.Interface
+	ProposalRole          dataloader.Interface
+	ProposalMember        dataloader.Interface
+	RoleMeetings          dataloader.Interface
+	MeetingRole           dataloader.Interface
+	MeetingAttendees      dataloader.Interface
+	MeetingFacilitator    dataloader.Interface
+	MeetingSecretary      dataloader.Interface
+	MeetingAgendaItems    dataloader.Interface
+	AgendaItemTension     dataloader.Interface
+	RoleProjects          dataloader.Interface
+	MemberProjects        dataloader.Interface
+	ProjectRole           dataloader.Interface
+	ProjectMember         dataloader.Interface
+	ProjectNextActions    dataloader.Interface
+	CircleReportItems     dataloader.Interface
+	RoleReportItems       dataloader.Interface
+	ReportItemCircle      dataloader.Interface
+	ReportItemRole        dataloader.Interface
+	ReportItemValues      dataloader.Interface
+	ChildRolePage         dataloader.Interface
+	MemberRoleEdgesPage   dataloader.Interface
+	MemberTensionsPage    dataloader.Interface
+	RoleTensionsPage      dataloader.Interface
+	CircleMemberEdgesPage dataloader.Interface
 }
 
 func NewTlDataLoaders(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) *tlDataLoaders {
@@ -45,6 +78,39 @@ func NewTlDataLoaders(ctx context.Context, s readdb.ReadDBService, timeLine util
 		TensionMember:         dataloader.NewBatchedLoader(TensionMemberBatchFn(ctx, s, timeLine)),
 		RoleTensions:          dataloader.NewBatchedLoader(RoleTensionsBatchFn(ctx, s, timeLine)),
 		TensionRole:           dataloader.NewBatchedLoader(TensionRoleBatchFn(ctx, s, timeLine)),
+		TensionAssignee:       dataloader.NewBatchedLoader(TensionAssigneeBatchFn(ctx, s, timeLine)),
+		TensionAssigneeRole:   dataloader.NewBatchedLoader(TensionAssigneeRoleBatchFn(ctx, s, timeLine)),
+		TensionComments:       dataloader.NewBatchedLoader(TensionCommentsBatchFn(ctx, s, timeLine)),
+		CommentParent:         dataloader.NewBatchedLoader(CommentParentBatchFn(ctx, s, timeLine)),
+		CommentReplies:        dataloader.NewBatchedLoader(CommentRepliesBatchFn(ctx, s, timeLine)),
+		CommentMember:         dataloader.NewBatchedLoader(CommentMemberBatchFn(ctx, s, timeLine)),
+		TensionLabels:         dataloader.NewBatchedLoader(TensionLabelsBatchFn(ctx, s, timeLine)),
+		TensionProposals:      dataloader.NewBatchedLoader(TensionProposalsBatchFn(ctx, s, timeLine)),
+		ProposalTension:       dataloader.NewBatchedLoader(ProposalTensionBatchFn(ctx, s, timeLine)),
+		ProposalRole:          dataloader.NewBatchedLoader(ProposalRoleBatchFn(ctx, s, timeLine)),
+		ProposalMember:        dataloader.NewBatchedLoader(ProposalMemberBatchFn(ctx, s, timeLine)),
+		RoleMeetings:          dataloader.NewBatchedLoader(RoleMeetingsBatchFn(ctx, s, timeLine)),
+		MeetingRole:           dataloader.NewBatchedLoader(MeetingRoleBatchFn(ctx, s, timeLine)),
+		MeetingAttendees:      dataloader.NewBatchedLoader(MeetingAttendeesBatchFn(ctx, s, timeLine)),
+		MeetingFacilitator:    dataloader.NewBatchedLoader(MeetingFacilitatorBatchFn(ctx, s, timeLine)),
+		MeetingSecretary:      dataloader.NewBatchedLoader(MeetingSecretaryBatchFn(ctx, s, timeLine)),
+		MeetingAgendaItems:    dataloader.NewBatchedLoader(MeetingAgendaItemsBatchFn(ctx, s, timeLine)),
+		AgendaItemTension:     dataloader.NewBatchedLoader(AgendaItemTensionBatchFn(ctx, s, timeLine)),
+		RoleProjects:          dataloader.NewBatchedLoader(RoleProjectsBatchFn(ctx, s, timeLine)),
+		MemberProjects:        dataloader.NewBatchedLoader(MemberProjectsBatchFn(ctx, s, timeLine)),
+		ProjectRole:           dataloader.NewBatchedLoader(ProjectRoleBatchFn(ctx, s, timeLine)),
+		ProjectMember:         dataloader.NewBatchedLoader(ProjectMemberBatchFn(ctx, s, timeLine)),
+		ProjectNextActions:    dataloader.NewBatchedLoader(ProjectNextActionsBatchFn(ctx, s, timeLine)),
+		CircleReportItems:     dataloader.NewBatchedLoader(CircleReportItemsBatchFn(ctx, s, timeLine)),
+		RoleReportItems:       dataloader.NewBatchedLoader(RoleReportItemsBatchFn(ctx, s, timeLine)),
+		ReportItemCircle:      dataloader.NewBatchedLoader(ReportItemCircleBatchFn(ctx, s, timeLine)),
+		ReportItemRole:        dataloader.NewBatchedLoader(ReportItemRoleBatchFn(ctx, s, timeLine)),
+		ReportItemValues:      dataloader.NewBatchedLoader(ReportItemValuesBatchFn(ctx, s, timeLine)),
+		ChildRolePage:         dataloader.NewBatchedLoader(ChildRolePageBatchFn(ctx, s, timeLine)),
+		MemberRoleEdgesPage:   dataloader.NewBatchedLoader(MemberRoleEdgesPageBatchFn(ctx, s, timeLine)),
+		MemberTensionsPage:    dataloader.NewBatchedLoader(MemberTensionsPageBatchFn(ctx, s, timeLine)),
+		RoleTensionsPage:      dataloader.NewBatchedLoader(RoleTensionsPageBatchFn(ctx, s, timeLine)),
+		CircleMemberEdgesPage: dataloader.NewBatchedLoader(CircleMemberEdgesPageBatchFn(ctx, s, timeLine)),
 	}
 }
 
@@ -437,6 +503,195 @@ func TensionRoleBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine ut
 	}
 }
 
+func TensionAssigneeBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.TensionAssignee(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func TensionAssigneeRoleBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.TensionAssigneeRole(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func TensionCommentsBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.TensionComments(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.TensionComment{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func CommentParentBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.CommentParent(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func CommentRepliesBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.CommentReplies(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.TensionComment{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func CommentMemberBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.CommentMember(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func TensionLabelsBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.TensionLabels(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.TensionLabel{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
 func RoleTensionsBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
 	return func(ikeys []string) []*dataloader.Result {
 		var results []*dataloader.Result
@@ -463,3 +718,570 @@ func RoleTensionsBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine u
 		return results
 	}
 }
+
+func TensionProposalsBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.TensionProposals(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.Proposal{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func ProposalTensionBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.ProposalTension(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func ProposalRoleBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.ProposalRole(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func ProposalMemberBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.ProposalMember(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func RoleMeetingsBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.RoleMeetings(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.Meeting{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func MeetingRoleBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.MeetingRole(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func MeetingAttendeesBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.MeetingAttendees(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.Member{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func MeetingFacilitatorBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.MeetingFacilitator(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func MeetingSecretaryBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.MeetingSecretary(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func MeetingAgendaItemsBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.MeetingAgendaItems(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.MeetingAgendaItem{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func AgendaItemTensionBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.AgendaItemTension(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func RoleProjectsBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.RoleProjects(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.Project{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func MemberProjectsBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.MemberProjects(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.Project{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func ProjectRoleBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.ProjectRole(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func ProjectMemberBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.ProjectMember(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func ProjectNextActionsBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.ProjectNextActions(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.NextAction{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func CircleReportItemsBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.CircleReportItems(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.ReportItem{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func RoleReportItemsBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.RoleReportItems(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.ReportItem{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func ReportItemCircleBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.ReportItemCircle(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func ReportItemRoleBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.ReportItemRole(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: nil}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
+
+func ReportItemValuesBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		var results []*dataloader.Result
+
+		keys := keysToIDs(ikeys)
+
+		groups, err := s.ReportItemValues(ctx, timeLine, keys)
+		if err != nil {
+			for _ = range keys {
+				results = append(results, &dataloader.Result{Error: err})
+				return results
+			}
+		}
+
+		for _, key := range keys {
+			var result dataloader.Result
+			if group, ok := groups[key]; ok {
+				result = dataloader.Result{Data: group}
+			} else {
+				result = dataloader.Result{Data: []*models.ReportItemValue{}}
+			}
+			results = append(results, &result)
+		}
+		return results
+	}
+}
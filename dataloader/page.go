@@ -0,0 +1,146 @@
+package dataloader
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+
+	"github.com/nicksrandall/dataloader"
+	"github.com/satori/go.uuid"
+)
+
+// PageKey returns the key to load the provided page of the entities connected
+// to the entity with the provided id
+func PageKey(id util.ID, page *readdb.Page) string {
+	return fmt.Sprintf("%s/%d/%d/%d/%d", id, page.First, page.After, page.Last, page.Before)
+}
+
+// pageKeysToIDs groups the ids in the keys by page since in the same batch the
+// connected entities of different entities can be requested with different
+// pages
+func pageKeysToIDs(ikeys []string) map[readdb.Page][]util.ID {
+	pages := map[readdb.Page][]util.ID{}
+	for _, ikey := range ikeys {
+		parts := strings.SplitN(ikey, "/", 2)
+		key, err := uuid.FromString(parts[0])
+		if err != nil {
+			panic(err)
+		}
+		var page readdb.Page
+		if _, err := fmt.Sscanf(parts[1], "%d/%d/%d/%d", &page.First, &page.After, &page.Last, &page.Before); err != nil {
+			panic(err)
+		}
+		pages[page] = append(pages[page], util.NewFromUUID(key))
+	}
+	return pages
+}
+
+// pageBatchResults calls loadPages for every requested page and returns the
+// results in the keys order
+func pageBatchResults(ikeys []string, loadPages func(page *readdb.Page, ids []util.ID) (map[util.ID]interface{}, error)) []*dataloader.Result {
+	var results []*dataloader.Result
+
+	data := map[string]interface{}{}
+	for page, ids := range pageKeysToIDs(ikeys) {
+		page := page
+		groups, err := loadPages(&page, ids)
+		if err != nil {
+			for _ = range ikeys {
+				results = append(results, &dataloader.Result{Error: err})
+			}
+			return results
+		}
+		for id, group := range groups {
+			data[PageKey(id, &page)] = group
+		}
+	}
+
+	for _, ikey := range ikeys {
+		// missing pages (i.e. not visible) are returned as nil
+		results = append(results, &dataloader.Result{Data: data[ikey]})
+	}
+	return results
+}
+
+func ChildRolePageBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		return pageBatchResults(ikeys, func(page *readdb.Page, ids []util.ID) (map[util.ID]interface{}, error) {
+			groups, err := s.ChildRolesPage(ctx, timeLine, ids, page)
+			if err != nil {
+				return nil, err
+			}
+			res := map[util.ID]interface{}{}
+			for id, group := range groups {
+				res[id] = group
+			}
+			return res, nil
+		})
+	}
+}
+
+func MemberRoleEdgesPageBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		return pageBatchResults(ikeys, func(page *readdb.Page, ids []util.ID) (map[util.ID]interface{}, error) {
+			groups, err := s.MemberRoleEdgesPage(ctx, timeLine, ids, page)
+			if err != nil {
+				return nil, err
+			}
+			res := map[util.ID]interface{}{}
+			for id, group := range groups {
+				res[id] = group
+			}
+			return res, nil
+		})
+	}
+}
+
+func MemberTensionsPageBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		return pageBatchResults(ikeys, func(page *readdb.Page, ids []util.ID) (map[util.ID]interface{}, error) {
+			groups, err := s.MemberTensionsPage(ctx, timeLine, ids, page)
+			if err != nil {
+				return nil, err
+			}
+			res := map[util.ID]interface{}{}
+			for id, group := range groups {
+				res[id] = group
+			}
+			return res, nil
+		})
+	}
+}
+
+func RoleTensionsPageBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		return pageBatchResults(ikeys, func(page *readdb.Page, ids []util.ID) (map[util.ID]interface{}, error) {
+			groups, err := s.RoleTensionsPage(ctx, timeLine, ids, page)
+			if err != nil {
+				return nil, err
+			}
+			res := map[util.ID]interface{}{}
+			for id, group := range groups {
+				res[id] = group
+			}
+			return res, nil
+		})
+	}
+}
+
+func CircleMemberEdgesPageBatchFn(ctx context.Context, s readdb.ReadDBService, timeLine util.TimeLineNumber) func(ikeys []string) []*dataloader.Result {
+	return func(ikeys []string) []*dataloader.Result {
+		return pageBatchResults(ikeys, func(page *readdb.Page, ids []util.ID) (map[util.ID]interface{}, error) {
+			groups, err := s.CircleMemberEdgesPage(ctx, timeLine, ids, page)
+			if err != nil {
+				return nil, err
+			}
+			res := map[util.ID]interface{}{}
+			for id, group := range groups {
+				res[id] = group
+			}
+			return res, nil
+		})
+	}
+}
@@ -11,7 +11,7 @@ import (
 
 	"github.com/pkg/errors"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/mattn/go-sqlite3"
 )
 
@@ -111,6 +111,13 @@ func (t dbData) translateArgs(args []interface{}) []interface{} {
 type DB struct {
 	db   *sql.DB
 	data dbData
+
+	// schema is the postgres schema used by the transactions. If empty the
+	// connection search path is used.
+	schema string
+	// connString is the db connection string, used to open connections
+	// outside the sql.DB pool
+	connString string
 }
 
 func NewDB(dbType Type, dbConnString string) (*DB, error) {
@@ -144,8 +151,9 @@ func NewDB(dbType Type, dbConnString string) (*DB, error) {
 	}
 
 	db := &DB{
-		db:   sqldb,
-		data: data,
+		db:         sqldb,
+		data:       data,
+		connString: dbConnString,
 	}
 
 	return db, nil
@@ -167,6 +175,79 @@ type WrappedTx struct {
 	data dbData
 }
 
+// Type returns the db type
+func (db *DB) Type() Type {
+	return db.data.t
+}
+
+// WithSchema returns a DB sharing the same connections but with all the
+// transactions using the provided postgres schema. The returned DB must not be
+// closed.
+func (db *DB) WithSchema(schema string) (*DB, error) {
+	if db.data.t != Postgres {
+		return nil, errors.Errorf("schemas aren't supported by db type %q", db.data.t)
+	}
+	return &DB{
+		db:         db.db,
+		data:       db.data,
+		schema:     schema,
+		connString: db.connString,
+	}, nil
+}
+
+// ReplaceWith atomically replaces the db contents with the src db contents.
+// Currently only sqlite3 file dbs are supported.
+func (db *DB) ReplaceWith(src *DB) error {
+	if db.data.t != Sqlite3 || src.data.t != Sqlite3 {
+		return errors.Errorf("replace is supported only between sqlite3 dbs")
+	}
+
+	// the sqlite3 backup api requires the driver connections that aren't
+	// exposed by the sql.DB pool, so open new ones directly with the driver
+	dconn, err := db.sqlite3Conn()
+	if err != nil {
+		return err
+	}
+	defer dconn.Close()
+	sconn, err := src.sqlite3Conn()
+	if err != nil {
+		return err
+	}
+	defer sconn.Close()
+
+	b, err := dconn.Backup("main", sconn, "main")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	// copy all the pages in a single step so the replace is atomic, retry
+	// while the db is busy
+	for retries := 0; ; retries++ {
+		done, err := b.Step(-1)
+		if err != nil {
+			b.Close()
+			return errors.WithStack(err)
+		}
+		if done {
+			break
+		}
+		if retries >= maxTxRetries {
+			b.Close()
+			return errors.New("db busy, cannot replace it")
+		}
+		time.Sleep(time.Duration(retries%30) * time.Millisecond)
+	}
+	return errors.WithStack(b.Finish())
+}
+
+// sqlite3Conn opens a new sqlite3 driver connection to the db
+func (db *DB) sqlite3Conn() (*sqlite3.SQLiteConn, error) {
+	c, err := (&sqlite3.SQLiteDriver{}).Open(db.connString)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return c.(*sqlite3.SQLiteConn), nil
+}
+
 func (db *DB) Close() error {
 	return db.db.Close()
 }
@@ -238,6 +319,11 @@ func (tx *Tx) Start() error {
 		if _, err := wtx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ"); err != nil {
 			return errors.WithStack(err)
 		}
+		if tx.db.schema != "" {
+			if _, err := wtx.Exec("SET LOCAL search_path TO " + pq.QuoteIdentifier(tx.db.schema)); err != nil {
+				return errors.WithStack(err)
+			}
+		}
 	}
 	tx.wrappedTx.tx = wtx
 	return nil
@@ -3,6 +3,7 @@ package eventhandler
 import (
 	"database/sql"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	sq "github.com/Masterminds/squirrel"
@@ -18,21 +19,25 @@ import (
 )
 
 const (
-	dbName = "drth.db"
+	drthDBName = "drth.db"
 )
 
-func newDB(dataDir string) (*db.DB, error) {
+func newDB(dataDir, dbName string) (*db.DB, error) {
 	return db.NewDB("sqlite3", filepath.Join(dataDir, dbName))
 }
 
 type DeletedRoleTensionHandler struct {
 	dataDir      string
-	es           *eventstore.EventStore
+	es           eventstore.EventStore
 	uidGenerator common.UIDGenerator
 }
 
-func NewDeletedRoleTensionHandler(dataDir string, es *eventstore.EventStore, uidGenerator common.UIDGenerator) (*DeletedRoleTensionHandler, error) {
-	ldb, err := newDB(dataDir)
+func NewDeletedRoleTensionHandler(dataDir string, es eventstore.EventStore, uidGenerator common.UIDGenerator) (*DeletedRoleTensionHandler, error) {
+	if err := checkDB(dataDir, drthDBName, es); err != nil {
+		return nil, err
+	}
+
+	ldb, err := newDB(dataDir, drthDBName)
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +61,68 @@ func NewDeletedRoleTensionHandler(dataDir string, es *eventstore.EventStore, uid
 	}, err
 }
 
+// checkDB checks that the handler snapshot db saved in dataDir is consistent
+// with the eventstore: the last handled event must be the same event saved in
+// the eventstore at that sequence number. If not (i.e. the eventstore has been
+// reset or replaced, or the snapshot db is corrupted) the snapshot db is
+// removed so it'll be rebuilt from scratch.
+// The snapshot db must have a sequencenumber table with the last handled event
+// sequence number and id.
+func checkDB(dataDir, dbName string, es eventstore.EventStore) error {
+	dbPath := filepath.Join(dataDir, dbName)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	valid, err := isDBValid(dataDir, dbName, es)
+	if err != nil {
+		return err
+	}
+	if valid {
+		return nil
+	}
+
+	log.Infof("handler snapshot db %q is stale or corrupted, it will be rebuilt", dbName)
+	if err := os.Remove(dbPath); err != nil {
+		return errors.Wrapf(err, "failed to remove handler snapshot db %q", dbName)
+	}
+	return nil
+}
+
+func isDBValid(dataDir, dbName string, es eventstore.EventStore) (bool, error) {
+	ldb, err := newDB(dataDir, dbName)
+	if err != nil {
+		log.Errorf("failed to open handler snapshot db %q: %+v", dbName, err)
+		return false, nil
+	}
+	defer ldb.Close()
+
+	var sn int64
+	var eventID util.ID
+	err = ldb.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			return tx.QueryRow("select sequencenumber, eventid from sequencenumber limit 1").Scan(&sn, &eventID)
+		})
+	})
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		log.Errorf("failed to read handler snapshot db %q sequence number: %+v", dbName, err)
+		return false, nil
+	}
+
+	events, err := es.GetAllEvents(sn, 1)
+	if err != nil {
+		return false, err
+	}
+	if len(events) == 0 || events[0].SequenceNumber != sn || events[0].ID != eventID {
+		return false, nil
+	}
+
+	return true, nil
+}
+
 func (h *DeletedRoleTensionHandler) Name() string {
 	return "deletedRoleTensionHandler"
 }
@@ -86,7 +153,7 @@ func (h *DeletedRoleTensionHandler) updateTensions(ldb *db.DB) error {
 		}
 
 		groupID := h.uidGenerator.UUID("")
-		eventsData, err := ep.GenEventData(events, &correlationID, &causationID, &groupID, nil)
+		eventsData, err := ep.GenEventData(events, &correlationID, &causationID, nil, &groupID, nil)
 		if err != nil {
 			return err
 		}
@@ -99,7 +166,7 @@ func (h *DeletedRoleTensionHandler) updateTensions(ldb *db.DB) error {
 
 func (h *DeletedRoleTensionHandler) HandleEvents() error {
 	log.Debugf("eh handleEvents")
-	ldb, err := newDB(h.dataDir)
+	ldb, err := newDB(h.dataDir, drthDBName)
 	if err != nil {
 		return err
 	}
@@ -210,6 +277,16 @@ func (h *DeletedRoleTensionHandler) handleEvent(tx *db.Tx, event *eventstore.Sto
 		if err := h.deleteTension(tx, tensionID); err != nil {
 			return err
 		}
+
+	case ep.EventTypeTensionReopened:
+		data := data.(*ep.EventTensionReopened)
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+		if err := h.updateTension(tx, tensionID, event.Version, data.RoleID); err != nil {
+			return err
+		}
 	}
 
 	// for every tension event update tension version if tension exists
@@ -223,7 +300,7 @@ func (h *DeletedRoleTensionHandler) handleEvent(tx *db.Tx, event *eventstore.Sto
 		}
 	}
 
-	if err := h.updateSequenceNumber(tx, event.SequenceNumber); err != nil {
+	if err := updateSequenceNumber(tx, event.SequenceNumber, event.ID); err != nil {
 		return err
 	}
 
@@ -234,7 +311,7 @@ func (h *DeletedRoleTensionHandler) handleEvent(tx *db.Tx, event *eventstore.Sto
 var drthDBCreateStmts = []string{
 	"create table if not exists deletedrole (id uuid, PRIMARY KEY (id))",
 	"create table if not exists tension (id uuid, version bigint, roleid uuid, PRIMARY KEY (id))",
-	"create table if not exists sequencenumber (sequencenumber bigint)",
+	"create table if not exists sequencenumber (sequencenumber bigint, eventid uuid)",
 }
 
 var (
@@ -251,7 +328,7 @@ var (
 	tensionUpdate = sb.Update("tension")
 
 	sequenceNumberSelect = sb.Select("sequencenumber").From("sequencenumber")
-	sequenceNumberInsert = sb.Insert("sequencenumber").Columns("sequencenumber")
+	sequenceNumberInsert = sb.Insert("sequencenumber").Columns("sequencenumber", "eventid")
 	sequenceNumberDelete = sb.Delete("sequencenumber")
 )
 
@@ -374,6 +451,12 @@ func (h *DeletedRoleTensionHandler) updateTensionVersion(tx *db.Tx, id util.ID,
 }
 
 func (h *DeletedRoleTensionHandler) SequenceNumber(tx *db.Tx) (int64, error) {
+	return sequenceNumber(tx)
+}
+
+// sequenceNumber returns the last handled event sequence number saved in a
+// handler snapshot db
+func sequenceNumber(tx *db.Tx) (int64, error) {
 	var sn int64
 	err := tx.Do(func(tx *db.WrappedTx) error {
 		return tx.QueryRow("select sequencenumber from sequencenumber limit 1").Scan(&sn)
@@ -387,7 +470,9 @@ func (h *DeletedRoleTensionHandler) SequenceNumber(tx *db.Tx) (int64, error) {
 	return sn, nil
 }
 
-func (h *DeletedRoleTensionHandler) updateSequenceNumber(tx *db.Tx, sn int64) error {
+// updateSequenceNumber saves in a handler snapshot db the last handled event
+// sequence number and id
+func updateSequenceNumber(tx *db.Tx, sn int64, eventID util.ID) error {
 	q, args, err := sequenceNumberDelete.ToSql()
 	if err != nil {
 		return errors.Wrap(err, "failed to build query")
@@ -400,7 +485,7 @@ func (h *DeletedRoleTensionHandler) updateSequenceNumber(tx *db.Tx, sn int64) er
 		return errors.WithMessage(err, fmt.Sprintf("failed to delete sequencenumber: %v", sn))
 	}
 
-	q, args, err = sequenceNumberInsert.Values(sn).ToSql()
+	q, args, err = sequenceNumberInsert.Values(sn, eventID).ToSql()
 	if err != nil {
 		return errors.Wrap(err, "failed to build query")
 	}
@@ -0,0 +1,302 @@
+package eventhandler
+
+import (
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+	"github.com/sorintlab/sircles/aggregate"
+	"github.com/sorintlab/sircles/command/commands"
+	"github.com/sorintlab/sircles/common"
+	"github.com/sorintlab/sircles/db"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+)
+
+const (
+	eehDBName = "eeh.db"
+)
+
+// ElectionExpirationHandler keeps track of the elected core roles (facilitator,
+// secretary, rep link) assignments with an election expiration and, when the
+// expiration time has passed, executes a CircleExpireCoreRoleElection command
+// on the roles tree aggregate.
+type ElectionExpirationHandler struct {
+	dataDir      string
+	es           eventstore.EventStore
+	uidGenerator common.UIDGenerator
+}
+
+func NewElectionExpirationHandler(dataDir string, es eventstore.EventStore, uidGenerator common.UIDGenerator) (*ElectionExpirationHandler, error) {
+	if err := checkDB(dataDir, eehDBName, es); err != nil {
+		return nil, err
+	}
+
+	ldb, err := newDB(dataDir, eehDBName)
+	if err != nil {
+		return nil, err
+	}
+	defer ldb.Close()
+
+	err = ldb.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			for _, stmt := range eehDBCreateStmts {
+				if _, err := tx.Exec(stmt); err != nil {
+					return errors.WithMessage(err, "create failed")
+				}
+			}
+			return nil
+		})
+	})
+
+	return &ElectionExpirationHandler{
+		dataDir:      dataDir,
+		es:           es,
+		uidGenerator: uidGenerator,
+	}, err
+}
+
+func (h *ElectionExpirationHandler) Name() string {
+	return "electionExpirationHandler"
+}
+
+type election struct {
+	CoreRoleID         util.ID
+	RoleID             util.ID
+	MemberID           util.ID
+	RoleType           models.RoleType
+	ElectionExpiration time.Time
+}
+
+func (h *ElectionExpirationHandler) expireElections(ldb *db.DB) error {
+	var elections []*election
+	err := ldb.Do(func(tx *db.Tx) error {
+		var err error
+		elections, err = h.findExpiredElections(tx, time.Now())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range elections {
+		rtr := aggregate.NewRolesTreeRepository(h.dataDir, h.es, h.uidGenerator)
+		rt, err := rtr.Load(aggregate.RolesTreeAggregateID)
+		if err != nil {
+			return err
+		}
+		correlationID := h.uidGenerator.UUID("")
+		causationID := h.uidGenerator.UUID("")
+		command := commands.NewCommand(commands.CommandTypeCircleExpireCoreRoleElection, correlationID, causationID, util.NilID, &commands.CircleExpireCoreRoleElection{
+			RoleType:           e.RoleType,
+			RoleID:             e.RoleID,
+			MemberID:           e.MemberID,
+			ElectionExpiration: e.ElectionExpiration,
+		})
+
+		if _, _, err := aggregate.ExecCommand(command, rt, h.es, h.uidGenerator); err != nil {
+			hcErr, ok := err.(*aggregate.HandleCommandError)
+			if !ok {
+				return err
+			}
+			// the core role assignment changed in the meantime, the
+			// snapshot will be updated by the related events
+			log.Infof("cannot expire election of core role %s: %v", e.CoreRoleID, hcErr)
+		}
+
+		// remove the election now to avoid executing the command again before
+		// the expired event is handled
+		err = ldb.Do(func(tx *db.Tx) error {
+			return h.deleteElection(tx, e.CoreRoleID)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *ElectionExpirationHandler) HandleEvents() error {
+	log.Debugf("eh handleEvents")
+	ldb, err := newDB(h.dataDir, eehDBName)
+	if err != nil {
+		return err
+	}
+	defer ldb.Close()
+
+	for {
+		var n int
+		err := ldb.Do(func(tx *db.Tx) error {
+			var err error
+			n, err = h.updateSnapshot(tx)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+	}
+
+	return h.expireElections(ldb)
+}
+
+func (h *ElectionExpirationHandler) updateSnapshot(tx *db.Tx) (int, error) {
+	log.Debugf("updateSnapshot")
+
+	sn, err := sequenceNumber(tx)
+	if err != nil {
+		return 0, err
+	}
+	log.Debugf("sn: %d", sn)
+
+	events, err := h.es.GetAllEvents(sn+1, 100)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range events {
+		if err := h.handleEvent(tx, e); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), nil
+}
+
+func (h *ElectionExpirationHandler) handleEvent(tx *db.Tx, event *eventstore.StoredEvent) error {
+	log.Debugf("event: %v", event)
+
+	data, err := ep.UnmarshalData(event)
+	if err != nil {
+		return err
+	}
+
+	switch ep.EventType(event.EventType) {
+	case ep.EventTypeCircleCoreRoleMemberSet:
+		data := data.(*ep.EventCircleCoreRoleMemberSet)
+		if err := h.deleteElection(tx, data.CoreRoleID); err != nil {
+			return err
+		}
+		if data.RoleType.IsElectedRoleType() && data.ElectionExpiration != nil {
+			e := &election{
+				CoreRoleID:         data.CoreRoleID,
+				RoleID:             data.RoleID,
+				MemberID:           data.MemberID,
+				RoleType:           data.RoleType,
+				ElectionExpiration: *data.ElectionExpiration,
+			}
+			if err := h.insertElection(tx, e); err != nil {
+				return err
+			}
+		}
+
+	case ep.EventTypeCircleCoreRoleMemberUnset:
+		data := data.(*ep.EventCircleCoreRoleMemberUnset)
+		if err := h.deleteElection(tx, data.CoreRoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeCircleCoreRoleElectionExpired:
+		data := data.(*ep.EventCircleCoreRoleElectionExpired)
+		if err := h.deleteElection(tx, data.CoreRoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleDeleted:
+		data := data.(*ep.EventRoleDeleted)
+		if err := h.deleteElection(tx, data.RoleID); err != nil {
+			return err
+		}
+	}
+
+	if err := updateSequenceNumber(tx, event.SequenceNumber, event.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ElectionExpirationHandler snapshot db
+var eehDBCreateStmts = []string{
+	"create table if not exists election (coreroleid uuid, roleid uuid, memberid uuid, roletype varchar, electionexpiration timestamptz, PRIMARY KEY (coreroleid))",
+	"create table if not exists sequencenumber (sequencenumber bigint, eventid uuid)",
+}
+
+var (
+	electionSelect = sb.Select("coreroleid", "roleid", "memberid", "roletype", "electionexpiration").From("election")
+	electionInsert = sb.Insert("election").Columns("coreroleid", "roleid", "memberid", "roletype", "electionexpiration")
+	electionDelete = sb.Delete("election")
+)
+
+// findExpiredElections returns the elections expired at time t
+func (h *ElectionExpirationHandler) findExpiredElections(tx *db.Tx, t time.Time) ([]*election, error) {
+	q, args, err := electionSelect.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+
+	elections := []*election{}
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return errors.Wrap(err, "failed to execute query")
+		}
+		for rows.Next() {
+			e := &election{}
+			// To make sqlite3 happy
+			var roleType string
+			if err := rows.Scan(&e.CoreRoleID, &e.RoleID, &e.MemberID, &roleType, &e.ElectionExpiration); err != nil {
+				rows.Close()
+				return errors.Wrap(err, "failed to scan rows")
+			}
+			e.RoleType = models.RoleType(roleType)
+			if !e.ElectionExpiration.After(t) {
+				elections = append(elections, e)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to find expired elections")
+	}
+	return elections, nil
+}
+
+func (h *ElectionExpirationHandler) insertElection(tx *db.Tx, e *election) error {
+	q, args, err := electionInsert.Values(e.CoreRoleID, e.RoleID, e.MemberID, e.RoleType, e.ElectionExpiration).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to insert election for core role %s", e.CoreRoleID))
+	}
+	return nil
+}
+
+func (h *ElectionExpirationHandler) deleteElection(tx *db.Tx, coreRoleID util.ID) error {
+	q, args, err := electionDelete.Where(sq.Eq{"coreroleid": coreRoleID}).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to delete election for core role %s", coreRoleID))
+	}
+	return nil
+}
@@ -12,13 +12,15 @@ import (
 )
 
 type MemberRequestHandler struct {
-	es           *eventstore.EventStore
+	dataDir      string
+	es           eventstore.EventStore
 	uidGenerator common.UIDGenerator
 }
 
-func NewMemberRequestHandler(es *eventstore.EventStore, uidGenerator common.UIDGenerator) *MemberRequestHandler {
+func NewMemberRequestHandler(dataDir string, es eventstore.EventStore, uidGenerator common.UIDGenerator) *MemberRequestHandler {
 	log.Debugf("NewMemberRequestHandler")
 	return &MemberRequestHandler{
+		dataDir:      dataDir,
 		es:           es,
 		uidGenerator: uidGenerator,
 	}
@@ -88,7 +90,7 @@ func (r *MemberRequestHandler) HandleEvents() error {
 		if mcSn != curMCSn || mSn != curMSn {
 			stateEvent := ep.NewEventMemberRequestHandlerStateUpdated(mcSn, mSn)
 
-			eventsData, err := ep.GenEventData([]ep.Event{stateEvent}, nil, nil, nil, nil)
+			eventsData, err := ep.GenEventData([]ep.Event{stateEvent}, nil, nil, nil, nil, nil)
 			if err != nil {
 				return err
 			}
@@ -132,6 +134,13 @@ func (r *MemberRequestHandler) handleEvent(event *eventstore.StoredEvent) error
 		}
 		return r.callSaga(memberChangeID, event)
 
+	case ep.EventTypeMemberChangeDeleteRequested:
+		memberChangeID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+		return r.callSaga(memberChangeID, event)
+
 	case ep.EventTypeMemberChangeCompleted:
 		memberChangeID, err := util.IDFromString(event.StreamID)
 		if err != nil {
@@ -150,6 +159,10 @@ func (r *MemberRequestHandler) handleEvent(event *eventstore.StoredEvent) error
 	case ep.EventTypeMemberMatchUIDSet:
 		data := data.(*ep.EventMemberMatchUIDSet)
 		return r.callSaga(data.MemberChangeID, event)
+
+	case ep.EventTypeMemberDeleted:
+		data := data.(*ep.EventMemberDeleted)
+		return r.callSaga(data.MemberChangeID, event)
 	}
 
 	return nil
@@ -161,7 +174,7 @@ func (r *MemberRequestHandler) callSaga(memberChangeID util.ID, event *eventstor
 		return err
 	}
 
-	sr := saga.NewMemberRequestSagaRepository(r.es, r.uidGenerator)
+	sr := saga.NewMemberRequestSagaRepository(r.dataDir, r.es, r.uidGenerator)
 	// load saga assigned to the member change
 	saganame := fmt.Sprintf("memberrequestsaga-%s", memberChangeID)
 	s, err := sr.Load(saganame)
@@ -177,7 +190,7 @@ func (r *MemberRequestHandler) callSaga(memberChangeID util.ID, event *eventstor
 	causationID := event.ID
 	correlationID := *metaData.CorrelationID
 	groupID := r.uidGenerator.UUID("")
-	eventsData, err := ep.GenEventData(events, &correlationID, &causationID, &groupID, nil)
+	eventsData, err := ep.GenEventData(events, &correlationID, &causationID, nil, &groupID, nil)
 	if err != nil {
 		return err
 	}
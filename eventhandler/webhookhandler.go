@@ -0,0 +1,1251 @@
+package eventhandler
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sorintlab/sircles/aggregate"
+	"github.com/sorintlab/sircles/config"
+	"github.com/sorintlab/sircles/db"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/util"
+)
+
+const (
+	webhookDBName = "webhook.db"
+
+	defaultWebhookMaxRetries = 5
+	defaultWebhookTimeout    = 10 * time.Second
+
+	webhookRetryInterval    = 1 * time.Second
+	webhookMaxRetryInterval = 60 * time.Second
+
+	// interval between the checks of the queued deliveries
+	webhookPollInterval = 1 * time.Second
+
+	// max role tree depth walked when checking if a role is inside a circle
+	// subtree, used to avoid infinite loops on an inconsistent snapshot db
+	webhookMaxRoleDepth = 1000
+)
+
+type webhook struct {
+	name       string
+	url        string
+	secret     []byte
+	eventTypes map[string]struct{}
+	circleID   *util.ID
+	maxRetries int
+	client     *http.Client
+
+	// wakeCh wakes up the webhook delivery goroutine
+	wakeCh chan struct{}
+}
+
+// webhookEventTypes are the event types that can be delivered to webhooks.
+// Member events and the other events containing passwords or personal data,
+// and the internal events (member change requests, sagas, registries) are
+// never delivered.
+var webhookEventTypes = map[ep.EventType]struct{}{
+	ep.EventTypeRoleCreated:                   {},
+	ep.EventTypeRoleUpdated:                   {},
+	ep.EventTypeRoleDeleted:                   {},
+	ep.EventTypeRoleChangedParent:             {},
+	ep.EventTypeRoleDomainCreated:             {},
+	ep.EventTypeRoleDomainUpdated:             {},
+	ep.EventTypeRoleDomainDeleted:             {},
+	ep.EventTypeRoleAccountabilityCreated:     {},
+	ep.EventTypeRoleAccountabilityUpdated:     {},
+	ep.EventTypeRoleAccountabilityDeleted:     {},
+	ep.EventTypeRoleAdditionalContentSet:      {},
+	ep.EventTypeRoleMemberAdded:               {},
+	ep.EventTypeRoleMemberUpdated:             {},
+	ep.EventTypeRoleMemberRemoved:             {},
+	ep.EventTypeCircleDirectMemberAdded:       {},
+	ep.EventTypeCircleDirectMemberRemoved:     {},
+	ep.EventTypeCircleLeadLinkMemberSet:       {},
+	ep.EventTypeCircleLeadLinkMemberUnset:     {},
+	ep.EventTypeCircleCoreRoleMemberSet:       {},
+	ep.EventTypeCircleCoreRoleMemberUnset:     {},
+	ep.EventTypeCircleCoreRoleElectionExpired: {},
+	ep.EventTypeCircleProposalApplied:         {},
+
+	ep.EventTypeTensionCreated:         {},
+	ep.EventTypeTensionUpdated:         {},
+	ep.EventTypeTensionRoleChanged:     {},
+	ep.EventTypeTensionClosed:          {},
+	ep.EventTypeTensionReopened:        {},
+	ep.EventTypeTensionStateChanged:    {},
+	ep.EventTypeTensionAssigneeChanged: {},
+	ep.EventTypeTensionLabelsSet:       {},
+
+	ep.EventTypeProposalCreated:            {},
+	ep.EventTypeProposalUpdated:            {},
+	ep.EventTypeProposalObjectionRaised:    {},
+	ep.EventTypeProposalAccepted:           {},
+	ep.EventTypeProposalAcceptanceReverted: {},
+
+	ep.EventTypeMeetingCreated:              {},
+	ep.EventTypeMeetingAgendaItemAdded:      {},
+	ep.EventTypeMeetingAgendaItemOutcomeSet: {},
+	ep.EventTypeMeetingClosed:               {},
+
+	ep.EventTypeProjectCreated:             {},
+	ep.EventTypeProjectUpdated:             {},
+	ep.EventTypeProjectStatusChanged:       {},
+	ep.EventTypeProjectNextActionAdded:     {},
+	ep.EventTypeProjectNextActionCompleted: {},
+
+	ep.EventTypeReportItemCreated:  {},
+	ep.EventTypeReportItemUpdated:  {},
+	ep.EventTypeReportItemDeleted:  {},
+	ep.EventTypeReportItemValueSet: {},
+}
+
+func (wh *webhook) matchEventType(eventType string) bool {
+	if _, ok := webhookEventTypes[ep.EventType(eventType)]; !ok {
+		return false
+	}
+	if len(wh.eventTypes) == 0 {
+		return true
+	}
+	_, ok := wh.eventTypes[eventType]
+	return ok
+}
+
+// webhookPayload is the json body POSTed to the webhook endpoints
+type webhookPayload struct {
+	ID             util.ID         `json:"id"`
+	SequenceNumber int64           `json:"sequenceNumber"`
+	EventType      string          `json:"eventType"`
+	Category       string          `json:"category"`
+	StreamID       string          `json:"streamID"`
+	Version        int64           `json:"version"`
+	Timestamp      time.Time       `json:"timestamp"`
+	CorrelationID  *util.ID        `json:"correlationID"`
+	CausationID    *util.ID        `json:"causationID"`
+	GroupID        *util.ID        `json:"groupID"`
+	IssuerID       *util.ID        `json:"issuerID"`
+	Data           json.RawMessage `json:"data"`
+}
+
+// WebhookHandler delivers the events to the configured webhooks as signed json
+// HTTP POSTs.
+// Only the event types in webhookEventTypes are delivered.
+// To filter events by circle subtree it keeps, in its snapshot db, the roles
+// tree, the tensions roles and the roles of the other aggregates (proposals,
+// meetings, projects and report items). When the snapshot db is created only
+// the events written after its creation are delivered.
+// Since the snapshot db contains the queued deliveries and the dead letters it
+// requires a persistent dataDir and it's never removed (see checkWebhookDB).
+// The handled events are queued, per webhook, in the snapshot db and delivered
+// by a goroutine for every webhook (see RunDeliveries). Failed deliveries are
+// retried with an exponential backoff and, when the retries are exhausted,
+// moved to the deadletter table of the snapshot db. The dead letters can be
+// listed and queued again with WebhookDeadLetters and
+// ReplayWebhookDeadLetters.
+// Deliveries are at least once: an event could be delivered again if the
+// delivery goroutine is stopped before removing it from the queue.
+type WebhookHandler struct {
+	dataDir  string
+	es       eventstore.EventStore
+	webhooks []*webhook
+}
+
+func NewWebhookHandler(dataDir string, es eventstore.EventStore, webhooksConfig []config.Webhook) (*WebhookHandler, error) {
+	webhooks := []*webhook{}
+	names := map[string]struct{}{}
+	for _, whc := range webhooksConfig {
+		if whc.Name == "" {
+			return nil, errors.Errorf("webhook name is required")
+		}
+		if _, ok := names[whc.Name]; ok {
+			return nil, errors.Errorf("duplicate webhook name %q", whc.Name)
+		}
+		names[whc.Name] = struct{}{}
+		if whc.URL == "" {
+			return nil, errors.Errorf("webhook %q url is required", whc.Name)
+		}
+		if _, err := url.Parse(whc.URL); err != nil {
+			return nil, errors.Wrapf(err, "webhook %q wrong url", whc.Name)
+		}
+
+		wh := &webhook{
+			name:       whc.Name,
+			url:        whc.URL,
+			secret:     []byte(whc.Secret),
+			eventTypes: map[string]struct{}{},
+			maxRetries: whc.MaxRetries,
+			wakeCh:     make(chan struct{}, 1),
+		}
+		for _, eventType := range whc.EventTypes {
+			if _, ok := webhookEventTypes[ep.EventType(eventType)]; !ok {
+				return nil, errors.Errorf("webhook %q: event type %q cannot be delivered", whc.Name, eventType)
+			}
+			wh.eventTypes[eventType] = struct{}{}
+		}
+		if whc.CircleID != "" {
+			circleID, err := util.IDFromString(whc.CircleID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "webhook %q wrong circleID", whc.Name)
+			}
+			wh.circleID = &circleID
+		}
+		if wh.maxRetries <= 0 {
+			wh.maxRetries = defaultWebhookMaxRetries
+		}
+		timeout := defaultWebhookTimeout
+		if whc.Timeout > 0 {
+			timeout = time.Duration(whc.Timeout) * time.Second
+		}
+		wh.client = &http.Client{Timeout: timeout}
+
+		webhooks = append(webhooks, wh)
+	}
+
+	if err := checkWebhookDB(dataDir, es); err != nil {
+		return nil, err
+	}
+
+	ldb, err := newDB(dataDir, webhookDBName)
+	if err != nil {
+		return nil, err
+	}
+	defer ldb.Close()
+
+	err = ldb.Do(func(tx *db.Tx) error {
+		err := tx.Do(func(tx *db.WrappedTx) error {
+			for _, stmt := range webhookDBCreateStmts {
+				if _, err := tx.Exec(stmt); err != nil {
+					return errors.WithMessage(err, "create failed")
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// on a new snapshot db deliver only the events written from now on
+		startSn, err := deliveryStartSequenceNumber(tx)
+		if err != nil {
+			return err
+		}
+		if startSn != nil {
+			return nil
+		}
+		lastSn, err := es.LastSequenceNumber()
+		if err != nil {
+			return err
+		}
+		return insertDeliveryStartSequenceNumber(tx, lastSn)
+	})
+
+	return &WebhookHandler{
+		dataDir:  dataDir,
+		es:       es,
+		webhooks: webhooks,
+	}, err
+}
+
+// checkWebhookDB checks that the webhook handler snapshot db is consistent
+// with the eventstore like checkDB. Since the snapshot db contains the queued
+// deliveries and the dead letters, when it's stale it isn't removed: only the
+// roles tree, the tensions and the aggregates roles are rebuilt from the
+// eventstore events while the deliveries will start after the last handled
+// event sequence number.
+func checkWebhookDB(dataDir string, es eventstore.EventStore) error {
+	if _, err := os.Stat(filepath.Join(dataDir, webhookDBName)); os.IsNotExist(err) {
+		return nil
+	}
+
+	valid, err := isDBValid(dataDir, webhookDBName, es)
+	if err != nil {
+		return err
+	}
+	if valid {
+		return nil
+	}
+
+	ldb, err := newDB(dataDir, webhookDBName)
+	if err != nil {
+		return err
+	}
+	defer ldb.Close()
+
+	return ldb.Do(func(tx *db.Tx) error {
+		sn, err := sequenceNumber(tx)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("failed to read handler snapshot db %q sequence number", webhookDBName))
+		}
+		startSn, err := deliveryStartSequenceNumber(tx)
+		if err != nil {
+			return err
+		}
+		if startSn != nil && *startSn > sn {
+			sn = *startSn
+		}
+		log.Infof("handler snapshot db %q is stale, its roles will be rebuilt and the deliveries will resume after sequence number %d", webhookDBName, sn)
+
+		return tx.Do(func(tx *db.WrappedTx) error {
+			// create the tables missing in a snapshot db created by a
+			// previous version
+			for _, stmt := range webhookDBCreateStmts {
+				if _, err := tx.Exec(stmt); err != nil {
+					return errors.WithMessage(err, "create failed")
+				}
+			}
+			for _, stmt := range []string{
+				"delete from role",
+				"delete from tension",
+				"delete from aggregaterole",
+				"delete from sequencenumber",
+				"delete from deliverystart",
+			} {
+				if _, err := tx.Exec(stmt); err != nil {
+					return errors.WithMessage(err, "failed to reset handler snapshot db")
+				}
+			}
+			if _, err := tx.Exec("insert into deliverystart (sequencenumber) values ($1)", sn); err != nil {
+				return errors.WithMessage(err, "failed to reset handler snapshot db")
+			}
+			return nil
+		})
+	})
+}
+
+func (h *WebhookHandler) Name() string {
+	return "webhookHandler"
+}
+
+func (h *WebhookHandler) HandleEvents() error {
+	log.Debugf("eh handleEvents")
+	ldb, err := newDB(h.dataDir, webhookDBName)
+	if err != nil {
+		return err
+	}
+	defer ldb.Close()
+
+	for {
+		var sn int64
+		err := ldb.Do(func(tx *db.Tx) error {
+			var err error
+			sn, err = sequenceNumber(tx)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		log.Debugf("sn: %d", sn)
+
+		events, err := h.es.GetAllEvents(sn+1, 100)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		// handle every event in its own transaction to not deliver again
+		// the already delivered events on errors
+		for _, e := range events {
+			err := ldb.Do(func(tx *db.Tx) error {
+				return h.handleEvent(tx, e)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		h.wakeDeliveries()
+	}
+
+	return nil
+}
+
+func (h *WebhookHandler) handleEvent(tx *db.Tx, event *eventstore.StoredEvent) error {
+	log.Debugf("event: %v", event)
+
+	data, err := ep.UnmarshalData(event)
+	if err != nil {
+		return err
+	}
+
+	// update the roles tree and tensions roles before delivering the event so
+	// created or moved roles and tensions are matched using their new parent
+	switch ep.EventType(event.EventType) {
+	case ep.EventTypeRoleCreated:
+		data := data.(*ep.EventRoleCreated)
+		if err := h.insertRole(tx, data.RoleID, data.ParentRoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleChangedParent:
+		data := data.(*ep.EventRoleChangedParent)
+		if err := h.insertRole(tx, data.RoleID, data.ParentRoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeTensionCreated:
+		data := data.(*ep.EventTensionCreated)
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+		if err := h.insertTension(tx, tensionID, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeTensionRoleChanged:
+		data := data.(*ep.EventTensionRoleChanged)
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+		if err := h.insertTension(tx, tensionID, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeTensionReopened:
+		data := data.(*ep.EventTensionReopened)
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+		if err := h.insertTension(tx, tensionID, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeProposalCreated:
+		data := data.(*ep.EventProposalCreated)
+		if err := h.insertAggregateRole(tx, event.StreamID, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeMeetingCreated:
+		data := data.(*ep.EventMeetingCreated)
+		if err := h.insertAggregateRole(tx, event.StreamID, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeProjectCreated:
+		data := data.(*ep.EventProjectCreated)
+		if err := h.insertAggregateRole(tx, event.StreamID, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeReportItemCreated:
+		data := data.(*ep.EventReportItemCreated)
+		if err := h.insertAggregateRole(tx, event.StreamID, data.CircleID); err != nil {
+			return err
+		}
+	}
+
+	startSn, err := deliveryStartSequenceNumber(tx)
+	if err != nil {
+		return err
+	}
+	if startSn != nil && event.SequenceNumber > *startSn {
+		if err := h.deliverEvent(tx, event, data); err != nil {
+			return err
+		}
+	}
+
+	switch ep.EventType(event.EventType) {
+	case ep.EventTypeRoleDeleted:
+		data := data.(*ep.EventRoleDeleted)
+		if err := h.deleteRole(tx, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeTensionClosed:
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+		if err := h.deleteTension(tx, tensionID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeReportItemDeleted:
+		if err := h.deleteAggregateRole(tx, event.StreamID); err != nil {
+			return err
+		}
+	}
+
+	if err := updateSequenceNumber(tx, event.SequenceNumber, event.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// deliverEvent queues the event for delivery to the matching webhooks. The
+// queued deliveries are done by the webhooks delivery goroutines.
+func (h *WebhookHandler) deliverEvent(tx *db.Tx, event *eventstore.StoredEvent, data interface{}) error {
+	var payload []byte
+	for _, wh := range h.webhooks {
+		if !wh.matchEventType(event.EventType) {
+			continue
+		}
+		if wh.circleID != nil {
+			roleID, err := h.eventRoleID(tx, event, data)
+			if err != nil {
+				return err
+			}
+			if roleID == nil {
+				continue
+			}
+			inSubtree, err := h.isRoleInSubtree(tx, *roleID, *wh.circleID)
+			if err != nil {
+				return err
+			}
+			if !inSubtree {
+				continue
+			}
+		}
+
+		if payload == nil {
+			var err error
+			payload, err = genWebhookPayload(event)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := insertDelivery(tx, wh.name, event, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunDeliveries starts a goroutine for every webhook delivering its queued
+// events in order. The deliveries are done outside the event handling so a
+// slow or failing endpoint won't block the event handling or the other
+// endpoints. The returned channel is closed when all the goroutines have
+// exited after stop has been closed.
+func (h *WebhookHandler) RunDeliveries(stop chan struct{}) chan struct{} {
+	endCh := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for _, wh := range h.webhooks {
+		wg.Add(1)
+		go func(wh *webhook) {
+			defer wg.Done()
+			for {
+				if err := h.deliverQueued(wh, stop); err != nil {
+					log.Errorf("webhook %q: deliveries error: %+v", wh.name, err)
+				}
+				select {
+				case <-wh.wakeCh:
+					continue
+				case <-time.After(webhookPollInterval):
+					continue
+				case <-stop:
+					return
+				}
+			}
+		}(wh)
+	}
+
+	go func() {
+		wg.Wait()
+		close(endCh)
+	}()
+
+	return endCh
+}
+
+// wakeDeliveries wakes up the webhooks delivery goroutines
+func (h *WebhookHandler) wakeDeliveries() {
+	for _, wh := range h.webhooks {
+		select {
+		case wh.wakeCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// deliverQueued delivers the webhook queued events until the queue is empty
+// or the first queued event must wait before being retried. A failed delivery
+// is retried with an exponential backoff and, when the retries are exhausted,
+// moved to the dead letters.
+func (h *WebhookHandler) deliverQueued(wh *webhook, stop chan struct{}) error {
+	ldb, err := newDB(h.dataDir, webhookDBName)
+	if err != nil {
+		return err
+	}
+	defer ldb.Close()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		var d *webhookDelivery
+		err := ldb.Do(func(tx *db.Tx) error {
+			var err error
+			d, err = nextDelivery(tx, wh.name)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if d == nil || d.nextAttempt.After(time.Now()) {
+			return nil
+		}
+
+		derr := wh.post(d.eventType, d.eventID, d.payload)
+
+		err = ldb.Do(func(tx *db.Tx) error {
+			if derr == nil {
+				return deleteDelivery(tx, d.id)
+			}
+
+			attempts := d.attempts + 1
+			if attempts > wh.maxRetries {
+				log.Errorf("webhook %q: failed to deliver event %s after %d attempts: %+v", wh.name, d.eventID, attempts, derr)
+				if err := insertDeadLetter(tx, d, attempts, derr); err != nil {
+					return err
+				}
+				return deleteDelivery(tx, d.id)
+			}
+
+			interval := webhookRetryBackoff(attempts)
+			log.Infof("webhook %q: failed to deliver event %s (attempt %d), retrying in %s: %v", wh.name, d.eventID, attempts, interval, derr)
+			return updateDeliveryAttempt(tx, d.id, attempts, time.Now().Add(interval), derr)
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// webhookRetryBackoff returns the time to wait before retrying a delivery
+// after the provided number of failed attempts
+func webhookRetryBackoff(attempts int) time.Duration {
+	interval := webhookRetryInterval
+	for i := 1; i < attempts; i++ {
+		interval *= 2
+		if interval > webhookMaxRetryInterval {
+			return webhookMaxRetryInterval
+		}
+	}
+	return interval
+}
+
+func (wh *webhook) post(eventType string, eventID util.ID, payload []byte) error {
+	req, err := http.NewRequest("POST", wh.url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sircles-Event", eventType)
+	req.Header.Set("X-Sircles-Delivery", eventID.String())
+	req.Header.Set("X-Sircles-Signature", "sha256="+signPayload(wh.secret, payload))
+
+	resp, err := wh.client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	// drain the body to reuse the connection
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("unexpected response status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signPayload returns the hex encoded HMAC-SHA256 of the payload
+func signPayload(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func genWebhookPayload(event *eventstore.StoredEvent) ([]byte, error) {
+	md, err := ep.UnmarshalMetaData(event)
+	if err != nil {
+		return nil, err
+	}
+	// deliver the data in the current format
+	data, err := ep.UpcastData(event)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(&webhookPayload{
+		ID:             event.ID,
+		SequenceNumber: event.SequenceNumber,
+		EventType:      event.EventType,
+		Category:       event.Category,
+		StreamID:       event.StreamID,
+		Version:        event.Version,
+		Timestamp:      event.Timestamp,
+		CorrelationID:  md.CorrelationID,
+		CausationID:    md.CausationID,
+		GroupID:        md.GroupID,
+		IssuerID:       md.CommandIssuerID,
+		Data:           json.RawMessage(data),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal webhook payload")
+	}
+	return payload, nil
+}
+
+// eventRoleID returns the id of the role related to the event or nil if the
+// event isn't related to a role
+func (h *WebhookHandler) eventRoleID(tx *db.Tx, event *eventstore.StoredEvent, data interface{}) (*util.ID, error) {
+	switch data := data.(type) {
+	case *ep.EventRoleCreated:
+		return &data.RoleID, nil
+	case *ep.EventRoleUpdated:
+		return &data.RoleID, nil
+	case *ep.EventRoleDeleted:
+		return &data.RoleID, nil
+	case *ep.EventRoleChangedParent:
+		return &data.RoleID, nil
+	case *ep.EventRoleDomainCreated:
+		return &data.RoleID, nil
+	case *ep.EventRoleDomainUpdated:
+		return &data.RoleID, nil
+	case *ep.EventRoleDomainDeleted:
+		return &data.RoleID, nil
+	case *ep.EventRoleAccountabilityCreated:
+		return &data.RoleID, nil
+	case *ep.EventRoleAccountabilityUpdated:
+		return &data.RoleID, nil
+	case *ep.EventRoleAccountabilityDeleted:
+		return &data.RoleID, nil
+	case *ep.EventRoleAdditionalContentSet:
+		return &data.RoleID, nil
+	case *ep.EventRoleMemberAdded:
+		return &data.RoleID, nil
+	case *ep.EventRoleMemberUpdated:
+		return &data.RoleID, nil
+	case *ep.EventRoleMemberRemoved:
+		return &data.RoleID, nil
+	case *ep.EventCircleDirectMemberAdded:
+		return &data.RoleID, nil
+	case *ep.EventCircleDirectMemberRemoved:
+		return &data.RoleID, nil
+	case *ep.EventCircleLeadLinkMemberSet:
+		return &data.RoleID, nil
+	case *ep.EventCircleLeadLinkMemberUnset:
+		return &data.RoleID, nil
+	case *ep.EventCircleCoreRoleMemberSet:
+		return &data.RoleID, nil
+	case *ep.EventCircleCoreRoleMemberUnset:
+		return &data.RoleID, nil
+	case *ep.EventCircleCoreRoleElectionExpired:
+		return &data.RoleID, nil
+	case *ep.EventCircleProposalApplied:
+		return &data.RoleID, nil
+	}
+
+	switch event.Category {
+	case aggregate.TensionAggregate.String():
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return nil, err
+		}
+		return h.tensionRoleID(tx, tensionID)
+	case aggregate.ProposalAggregate.String(),
+		aggregate.MeetingAggregate.String(),
+		aggregate.ProjectAggregate.String(),
+		aggregate.ReportItemAggregate.String():
+		return h.aggregateRoleID(tx, event.StreamID)
+	}
+
+	return nil, nil
+}
+
+// isRoleInSubtree reports if the role is the provided circle or one of its
+// descendants
+func (h *WebhookHandler) isRoleInSubtree(tx *db.Tx, roleID, circleID util.ID) (bool, error) {
+	curRoleID := &roleID
+	for i := 0; i < webhookMaxRoleDepth; i++ {
+		if *curRoleID == circleID {
+			return true, nil
+		}
+		parentID, err := h.roleParentID(tx, *curRoleID)
+		if err != nil {
+			return false, err
+		}
+		if parentID == nil {
+			return false, nil
+		}
+		curRoleID = parentID
+	}
+	return false, errors.Errorf("role %s depth exceeds the max depth of %d", roleID, webhookMaxRoleDepth)
+}
+
+// WebhookHandler snapshot db
+var webhookDBCreateStmts = []string{
+	"create table if not exists role (id uuid, parentid uuid, PRIMARY KEY (id))",
+	"create table if not exists tension (id uuid, roleid uuid, PRIMARY KEY (id))",
+	"create table if not exists aggregaterole (id varchar, roleid uuid, PRIMARY KEY (id))",
+	"create table if not exists deliverystart (sequencenumber bigint)",
+	"create table if not exists delivery (id uuid, webhook varchar, eventid uuid, sequencenumber bigint, eventtype varchar, payload bytea, attempts int, nextattempt timestamptz, error varchar, PRIMARY KEY (id))",
+	"create index if not exists delivery_webhook_sequencenumber on delivery (webhook, sequencenumber)",
+	"create table if not exists deadletter (id uuid, webhook varchar, eventid uuid, sequencenumber bigint, eventtype varchar, payload bytea, error varchar, attempts int, timestamp timestamptz, PRIMARY KEY (id))",
+	"create table if not exists sequencenumber (sequencenumber bigint, eventid uuid)",
+}
+
+var (
+	webhookRoleInsert    = sb.Insert("role").Columns("id", "parentid")
+	webhookRoleDelete    = sb.Delete("role")
+	webhookTensionInsert = sb.Insert("tension").Columns("id", "roleid")
+	webhookTensionDelete = sb.Delete("tension")
+	aggregateRoleInsert  = sb.Insert("aggregaterole").Columns("id", "roleid")
+	aggregateRoleDelete  = sb.Delete("aggregaterole")
+	deliveryStartInsert  = sb.Insert("deliverystart").Columns("sequencenumber")
+	deliveryInsert       = sb.Insert("delivery").Columns("id", "webhook", "eventid", "sequencenumber", "eventtype", "payload", "attempts", "nextattempt", "error")
+	deliverySelect       = sb.Select("id", "webhook", "eventid", "sequencenumber", "eventtype", "payload", "attempts", "nextattempt").From("delivery")
+	deliveryDelete       = sb.Delete("delivery")
+	deadLetterInsert     = sb.Insert("deadletter").Columns("id", "webhook", "eventid", "sequencenumber", "eventtype", "payload", "error", "attempts", "timestamp")
+	deadLetterSelect     = sb.Select("id", "webhook", "eventid", "sequencenumber", "eventtype", "payload", "error", "attempts", "timestamp").From("deadletter")
+	deadLetterDelete     = sb.Delete("deadletter")
+)
+
+func (h *WebhookHandler) insertRole(tx *db.Tx, id util.ID, parentID *util.ID) error {
+	if err := h.deleteRole(tx, id); err != nil {
+		return err
+	}
+
+	q, args, err := webhookRoleInsert.Values(id, parentID).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to insert role %s", id))
+	}
+	return nil
+}
+
+func (h *WebhookHandler) deleteRole(tx *db.Tx, id util.ID) error {
+	q, args, err := webhookRoleDelete.Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to delete role %s", id))
+	}
+	return nil
+}
+
+func (h *WebhookHandler) roleParentID(tx *db.Tx, id util.ID) (*util.ID, error) {
+	var parentID *util.ID
+	err := tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow("select parentid from role where id = $1", id).Scan(&parentID)
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get role %s parent", id)
+	}
+	return parentID, nil
+}
+
+func (h *WebhookHandler) insertTension(tx *db.Tx, id util.ID, roleID *util.ID) error {
+	if err := h.deleteTension(tx, id); err != nil {
+		return err
+	}
+
+	q, args, err := webhookTensionInsert.Values(id, roleID).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to insert tension %s", id))
+	}
+	return nil
+}
+
+func (h *WebhookHandler) deleteTension(tx *db.Tx, id util.ID) error {
+	q, args, err := webhookTensionDelete.Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to delete tension %s", id))
+	}
+	return nil
+}
+
+func (h *WebhookHandler) tensionRoleID(tx *db.Tx, id util.ID) (*util.ID, error) {
+	var roleID *util.ID
+	err := tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow("select roleid from tension where id = $1", id).Scan(&roleID)
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get tension %s role", id)
+	}
+	return roleID, nil
+}
+
+// insertAggregateRole saves the role of the aggregate with the provided stream
+// id
+func (h *WebhookHandler) insertAggregateRole(tx *db.Tx, streamID string, roleID util.ID) error {
+	if err := h.deleteAggregateRole(tx, streamID); err != nil {
+		return err
+	}
+
+	q, args, err := aggregateRoleInsert.Values(streamID, roleID).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to insert aggregate %s role", streamID))
+	}
+	return nil
+}
+
+func (h *WebhookHandler) deleteAggregateRole(tx *db.Tx, streamID string) error {
+	q, args, err := aggregateRoleDelete.Where(sq.Eq{"id": streamID}).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to delete aggregate %s role", streamID))
+	}
+	return nil
+}
+
+func (h *WebhookHandler) aggregateRoleID(tx *db.Tx, streamID string) (*util.ID, error) {
+	var roleID *util.ID
+	err := tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow("select roleid from aggregaterole where id = $1", streamID).Scan(&roleID)
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get aggregate %s role", streamID)
+	}
+	return roleID, nil
+}
+
+// webhookDelivery is an event queued for delivery to a webhook
+type webhookDelivery struct {
+	id             util.ID
+	webhook        string
+	eventID        util.ID
+	sequenceNumber int64
+	eventType      string
+	payload        []byte
+	attempts       int
+	nextAttempt    time.Time
+}
+
+func insertDelivery(tx *db.Tx, webhook string, event *eventstore.StoredEvent, payload []byte) error {
+	id := util.NewFromUUID(uuid.NewV4())
+	q, args, err := deliveryInsert.Values(id, webhook, event.ID, event.SequenceNumber, event.EventType, payload, 0, time.Now(), "").ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to insert delivery for event %s", event.ID))
+	}
+	return nil
+}
+
+// nextDelivery returns the first queued delivery of the webhook
+func nextDelivery(tx *db.Tx, webhook string) (*webhookDelivery, error) {
+	q, args, err := deliverySelect.Where(sq.Eq{"webhook": webhook}).OrderBy("sequencenumber").Limit(1).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+
+	var d *webhookDelivery
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			d = &webhookDelivery{}
+			if err := rows.Scan(&d.id, &d.webhook, &d.eventID, &d.sequenceNumber, &d.eventType, &d.payload, &d.attempts, &d.nextAttempt); err != nil {
+				return errors.Wrap(err, "failed to scan rows")
+			}
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to get webhook %q next delivery", webhook))
+	}
+	return d, nil
+}
+
+func updateDeliveryAttempt(tx *db.Tx, id util.ID, attempts int, nextAttempt time.Time, deliveryErr error) error {
+	q, args, err := sb.Update("delivery").Set("attempts", attempts).Set("nextattempt", nextAttempt).Set("error", deliveryErr.Error()).Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to update delivery %s", id))
+	}
+	return nil
+}
+
+func deleteDelivery(tx *db.Tx, id util.ID) error {
+	q, args, err := deliveryDelete.Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to delete delivery %s", id))
+	}
+	return nil
+}
+
+func insertDeadLetter(tx *db.Tx, d *webhookDelivery, attempts int, deliveryErr error) error {
+	id := util.NewFromUUID(uuid.NewV4())
+	q, args, err := deadLetterInsert.Values(id, d.webhook, d.eventID, d.sequenceNumber, d.eventType, d.payload, deliveryErr.Error(), attempts, time.Now()).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to insert dead letter for event %s", d.eventID))
+	}
+	return nil
+}
+
+// WebhookDeadLetter is an event that couldn't be delivered to a webhook
+type WebhookDeadLetter struct {
+	ID             util.ID   `json:"id"`
+	Webhook        string    `json:"webhook"`
+	EventID        util.ID   `json:"eventID"`
+	SequenceNumber int64     `json:"sequenceNumber"`
+	EventType      string    `json:"eventType"`
+	Error          string    `json:"error"`
+	Attempts       int       `json:"attempts"`
+	Timestamp      time.Time `json:"timestamp"`
+
+	payload []byte
+}
+
+func deadLetters(tx *db.Tx, webhook string, ids []util.ID) ([]*WebhookDeadLetter, error) {
+	s := deadLetterSelect.OrderBy("sequencenumber")
+	if webhook != "" {
+		s = s.Where(sq.Eq{"webhook": webhook})
+	}
+	if len(ids) > 0 {
+		s = s.Where(sq.Eq{"id": ids})
+	}
+	q, args, err := s.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+
+	dls := []*WebhookDeadLetter{}
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			dl := &WebhookDeadLetter{}
+			if err := rows.Scan(&dl.ID, &dl.Webhook, &dl.EventID, &dl.SequenceNumber, &dl.EventType, &dl.payload, &dl.Error, &dl.Attempts, &dl.Timestamp); err != nil {
+				return errors.Wrap(err, "failed to scan rows")
+			}
+			dls = append(dls, dl)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get dead letters")
+	}
+	return dls, nil
+}
+
+// openWebhookDB opens an existing webhook handler snapshot db
+func openWebhookDB(dataDir string) (*db.DB, error) {
+	if _, err := os.Stat(filepath.Join(dataDir, webhookDBName)); err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("webhook snapshot db %s doesn't exist in %s", webhookDBName, dataDir)
+		}
+		return nil, errors.WithStack(err)
+	}
+	return newDB(dataDir, webhookDBName)
+}
+
+// WebhookDeadLetters returns the dead letters, ordered by event sequence
+// number, saved in the webhook handler snapshot db in dataDir. If webhook
+// isn't empty only the webhook dead letters are returned.
+func WebhookDeadLetters(dataDir, webhook string) ([]*WebhookDeadLetter, error) {
+	ldb, err := openWebhookDB(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	defer ldb.Close()
+
+	var dls []*WebhookDeadLetter
+	err = ldb.Do(func(tx *db.Tx) error {
+		var err error
+		dls, err = deadLetters(tx, webhook, nil)
+		return err
+	})
+	return dls, err
+}
+
+// ReplayWebhookDeadLetters queues again for delivery the dead letters with the
+// provided ids, or all the dead letters if ids is empty, removing them from
+// the dead letters. If webhook isn't empty only the webhook dead letters are
+// replayed. It returns the replayed dead letters.
+func ReplayWebhookDeadLetters(dataDir, webhook string, ids []util.ID) ([]*WebhookDeadLetter, error) {
+	ldb, err := openWebhookDB(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	defer ldb.Close()
+
+	var dls []*WebhookDeadLetter
+	err = ldb.Do(func(tx *db.Tx) error {
+		var err error
+		dls, err = deadLetters(tx, webhook, ids)
+		if err != nil {
+			return err
+		}
+		if len(dls) != len(ids) && len(ids) > 0 {
+			return errors.Errorf("%d of the %d provided dead letters don't exist", len(ids)-len(dls), len(ids))
+		}
+		for _, dl := range dls {
+			id := util.NewFromUUID(uuid.NewV4())
+			q, args, err := deliveryInsert.Values(id, dl.Webhook, dl.EventID, dl.SequenceNumber, dl.EventType, dl.payload, 0, time.Now(), "").ToSql()
+			if err != nil {
+				return errors.Wrap(err, "failed to build query")
+			}
+			err = tx.Do(func(tx *db.WrappedTx) error {
+				_, err = tx.Exec(q, args...)
+				return err
+			})
+			if err != nil {
+				return errors.WithMessage(err, fmt.Sprintf("failed to insert delivery for dead letter %s", dl.ID))
+			}
+
+			q, args, err = deadLetterDelete.Where(sq.Eq{"id": dl.ID}).ToSql()
+			if err != nil {
+				return errors.Wrap(err, "failed to build query")
+			}
+			err = tx.Do(func(tx *db.WrappedTx) error {
+				_, err = tx.Exec(q, args...)
+				return err
+			})
+			if err != nil {
+				return errors.WithMessage(err, fmt.Sprintf("failed to delete dead letter %s", dl.ID))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dls, nil
+}
+
+func deliveryStartSequenceNumber(tx *db.Tx) (*int64, error) {
+	var sn int64
+	err := tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow("select sequencenumber from deliverystart limit 1").Scan(&sn)
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sn, nil
+}
+
+func insertDeliveryStartSequenceNumber(tx *db.Tx, sn int64) error {
+	q, args, err := deliveryStartInsert.Values(sn).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to insert delivery start sequencenumber: %v", sn))
+	}
+	return nil
+}
@@ -0,0 +1,542 @@
+package eventhandler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sorintlab/sircles/aggregate"
+	"github.com/sorintlab/sircles/config"
+	"github.com/sorintlab/sircles/db"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	ln "github.com/sorintlab/sircles/listennotify"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+type testWebhookRequest struct {
+	eventType string
+	signature string
+	body      []byte
+}
+
+// testWebhookServer records the received requests and replies with the
+// status code returned by statusCode
+type testWebhookServer struct {
+	*httptest.Server
+
+	m          sync.Mutex
+	requests   []*testWebhookRequest
+	statusCode int
+}
+
+func newTestWebhookServer() *testWebhookServer {
+	s := &testWebhookServer{statusCode: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		s.m.Lock()
+		defer s.m.Unlock()
+		s.requests = append(s.requests, &testWebhookRequest{
+			eventType: r.Header.Get("X-Sircles-Event"),
+			signature: r.Header.Get("X-Sircles-Signature"),
+			body:      body,
+		})
+		w.WriteHeader(s.statusCode)
+	}))
+	return s
+}
+
+func (s *testWebhookServer) setStatusCode(statusCode int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.statusCode = statusCode
+}
+
+func (s *testWebhookServer) Requests() []*testWebhookRequest {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return append([]*testWebhookRequest{}, s.requests...)
+}
+
+func setupWebhookHandler(t *testing.T, webhooksConfig []config.Webhook) (*WebhookHandler, eventstore.EventStore, string) {
+	dir, err := ioutil.TempDir("", "webhook")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	es := eventstore.NewMemoryEventStore(ln.NewLocalNotifierFactory(ln.NewLocalListenNotify()))
+
+	h, err := NewWebhookHandler(dir, es, webhooksConfig)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return h, es, dir
+}
+
+func writeTestEvents(t *testing.T, es eventstore.EventStore, category aggregate.AggregateType, streamID util.ID, events ...ep.Event) {
+	eventsData, err := ep.GenEventData(events, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var v int64
+	last, err := es.GetLastEvent(streamID.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if last != nil {
+		v = last.Version
+	}
+	if _, err := es.WriteEvents(eventsData, category.String(), streamID.String(), v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func testRoleCreatedEvent(parentRoleID *util.ID) *ep.EventRoleCreated {
+	role := &models.Role{
+		RoleType: models.RoleTypeCircle,
+		Name:     "role01",
+	}
+	role.ID = util.NewFromUUID(uuid.NewV4())
+	return ep.NewEventRoleCreated(role, parentRoleID)
+}
+
+// deliverAll delivers the queued events of all the webhooks ignoring the retry
+// backoff
+func deliverAll(t *testing.T, h *WebhookHandler) {
+	ldb, err := newDB(h.dataDir, webhookDBName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ldb.Close()
+
+	err = ldb.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			_, err := tx.Exec("update delivery set nextattempt = $1", time.Now().Add(-1*time.Second))
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stop := make(chan struct{})
+	for _, wh := range h.webhooks {
+		if err := h.deliverQueued(wh, stop); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func queuedDeliveries(t *testing.T, h *WebhookHandler, webhook string) []*webhookDelivery {
+	ldb, err := newDB(h.dataDir, webhookDBName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer ldb.Close()
+
+	q, args, err := deliverySelect.Where("webhook = ?", webhook).OrderBy("sequencenumber").ToSql()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ds := []*webhookDelivery{}
+	err = ldb.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			rows, err := tx.Query(q, args...)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				d := &webhookDelivery{}
+				if err := rows.Scan(&d.id, &d.webhook, &d.eventID, &d.sequenceNumber, &d.eventType, &d.payload, &d.attempts, &d.nextAttempt); err != nil {
+					return err
+				}
+				ds = append(ds, d)
+			}
+			return rows.Err()
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return ds
+}
+
+func TestWebhookSignature(t *testing.T) {
+	s := newTestWebhookServer()
+	defer s.Close()
+
+	h, es, dir := setupWebhookHandler(t, []config.Webhook{
+		{Name: "webhook01", URL: s.URL, Secret: "secret01"},
+	})
+	defer os.RemoveAll(dir)
+
+	event := testRoleCreatedEvent(nil)
+	writeTestEvents(t, es, aggregate.RolesTreeAggregate, aggregate.RolesTreeAggregateID, event)
+
+	if err := h.HandleEvents(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deliverAll(t, h)
+
+	requests := s.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	r := requests[0]
+	if r.eventType != ep.EventTypeRoleCreated.String() {
+		t.Fatalf("expected event type %q, got %q", ep.EventTypeRoleCreated, r.eventType)
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret01"))
+	mac.Write(r.body)
+	expectedSignature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if r.signature != expectedSignature {
+		t.Fatalf("expected signature %q, got %q", expectedSignature, r.signature)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(r.body, &payload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var data ep.EventRoleCreated
+	if err := json.Unmarshal(payload.Data, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.RoleID != event.RoleID {
+		t.Fatalf("expected role id %s, got %s", event.RoleID, data.RoleID)
+	}
+
+	// the delivered event must be removed from the queue
+	if ds := queuedDeliveries(t, h, "webhook01"); len(ds) != 0 {
+		t.Fatalf("expected no queued deliveries, got %d", len(ds))
+	}
+}
+
+func TestWebhookFiltering(t *testing.T) {
+	t.Run("not deliverable event types cannot be configured", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "webhook")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		es := eventstore.NewMemoryEventStore(ln.NewLocalNotifierFactory(ln.NewLocalListenNotify()))
+		for _, eventType := range []ep.EventType{ep.EventTypeMemberPasswordSet, ep.EventTypeMemberCreated, ep.EventTypeMemberChangeCreateRequested} {
+			_, err := NewWebhookHandler(dir, es, []config.Webhook{
+				{Name: "webhook01", URL: "http://localhost", EventTypes: []string{eventType.String()}},
+			})
+			if err == nil {
+				t.Fatalf("expected error configuring event type %s", eventType)
+			}
+		}
+	})
+
+	t.Run("deliver only the deliverable and configured event types", func(t *testing.T) {
+		s := newTestWebhookServer()
+		defer s.Close()
+
+		h, es, dir := setupWebhookHandler(t, []config.Webhook{
+			{Name: "all", URL: s.URL},
+			{Name: "tensions", URL: s.URL, EventTypes: []string{ep.EventTypeTensionCreated.String()}},
+		})
+		defer os.RemoveAll(dir)
+
+		memberID := util.NewFromUUID(uuid.NewV4())
+		member := &models.Member{UserName: "user01", FullName: "User 01", Email: "user01@example.com"}
+		member.ID = memberID
+		writeTestEvents(t, es, aggregate.MemberAggregate, memberID,
+			ep.NewEventMemberCreated(member, util.NewFromUUID(uuid.NewV4())),
+			ep.NewEventMemberPasswordSet(memberID, "passwordhash"),
+		)
+		writeTestEvents(t, es, aggregate.RolesTreeAggregate, aggregate.RolesTreeAggregateID, testRoleCreatedEvent(nil))
+
+		if err := h.HandleEvents(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if ds := queuedDeliveries(t, h, "all"); len(ds) != 1 || ds[0].eventType != ep.EventTypeRoleCreated.String() {
+			t.Fatalf("expected only the RoleCreated event queued, got %d deliveries", len(ds))
+		}
+		if ds := queuedDeliveries(t, h, "tensions"); len(ds) != 0 {
+			t.Fatalf("expected no queued deliveries, got %d", len(ds))
+		}
+
+		deliverAll(t, h)
+		for _, r := range s.Requests() {
+			if r.eventType != ep.EventTypeRoleCreated.String() {
+				t.Fatalf("unexpected delivered event type %q", r.eventType)
+			}
+		}
+	})
+
+	t.Run("deliver only the events in the circle subtree", func(t *testing.T) {
+		s := newTestWebhookServer()
+		defer s.Close()
+
+		rootRole := testRoleCreatedEvent(nil)
+		circle01 := testRoleCreatedEvent(&rootRole.RoleID)
+		circle01Role := testRoleCreatedEvent(&circle01.RoleID)
+		circle02 := testRoleCreatedEvent(&rootRole.RoleID)
+
+		h, es, dir := setupWebhookHandler(t, []config.Webhook{
+			{Name: "circle01", URL: s.URL, CircleID: circle01.RoleID.String()},
+		})
+		defer os.RemoveAll(dir)
+
+		writeTestEvents(t, es, aggregate.RolesTreeAggregate, aggregate.RolesTreeAggregateID, rootRole, circle01, circle01Role, circle02)
+
+		if err := h.HandleEvents(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ds := queuedDeliveries(t, h, "circle01")
+		if len(ds) != 2 {
+			t.Fatalf("expected 2 queued deliveries, got %d", len(ds))
+		}
+	})
+
+	t.Run("deliver only the events of the aggregates in the circle subtree", func(t *testing.T) {
+		s := newTestWebhookServer()
+		defer s.Close()
+
+		rootRole := testRoleCreatedEvent(nil)
+		circle01 := testRoleCreatedEvent(&rootRole.RoleID)
+		circle02 := testRoleCreatedEvent(&rootRole.RoleID)
+
+		h, es, dir := setupWebhookHandler(t, []config.Webhook{
+			{Name: "circle01", URL: s.URL, CircleID: circle01.RoleID.String()},
+		})
+		defer os.RemoveAll(dir)
+
+		writeTestEvents(t, es, aggregate.RolesTreeAggregate, aggregate.RolesTreeAggregateID, rootRole, circle01, circle02)
+
+		// the project updates don't contain the project role
+		project01ID := util.NewFromUUID(uuid.NewV4())
+		project02ID := util.NewFromUUID(uuid.NewV4())
+		writeTestEvents(t, es, aggregate.ProjectAggregate, project01ID,
+			&ep.EventProjectCreated{RoleID: circle01.RoleID, Title: "project01"},
+			&ep.EventProjectUpdated{Title: "project01 new title"},
+		)
+		writeTestEvents(t, es, aggregate.ProjectAggregate, project02ID,
+			&ep.EventProjectCreated{RoleID: circle02.RoleID, Title: "project02"},
+			&ep.EventProjectUpdated{Title: "project02 new title"},
+		)
+
+		if err := h.HandleEvents(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ds := queuedDeliveries(t, h, "circle01")
+		expectedEventTypes := []ep.EventType{ep.EventTypeRoleCreated, ep.EventTypeProjectCreated, ep.EventTypeProjectUpdated}
+		if len(ds) != len(expectedEventTypes) {
+			t.Fatalf("expected %d queued deliveries, got %d", len(expectedEventTypes), len(ds))
+		}
+		for i, d := range ds {
+			if d.eventType != expectedEventTypes[i].String() {
+				t.Fatalf("expected event type %q, got %q", expectedEventTypes[i], d.eventType)
+			}
+		}
+	})
+}
+
+func TestWebhookRetry(t *testing.T) {
+	s := newTestWebhookServer()
+	defer s.Close()
+	s.setStatusCode(http.StatusInternalServerError)
+
+	h, es, dir := setupWebhookHandler(t, []config.Webhook{
+		{Name: "webhook01", URL: s.URL, MaxRetries: 3},
+	})
+	defer os.RemoveAll(dir)
+
+	writeTestEvents(t, es, aggregate.RolesTreeAggregate, aggregate.RolesTreeAggregateID, testRoleCreatedEvent(nil), testRoleCreatedEvent(nil))
+
+	if err := h.HandleEvents(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the failed first event must block the delivery of the next ones and
+	// be scheduled for a retry
+	deliverAll(t, h)
+	if n := len(s.Requests()); n != 1 {
+		t.Fatalf("expected 1 request, got %d", n)
+	}
+	ds := queuedDeliveries(t, h, "webhook01")
+	if len(ds) != 2 {
+		t.Fatalf("expected 2 queued deliveries, got %d", len(ds))
+	}
+	if ds[0].attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", ds[0].attempts)
+	}
+	if !ds[0].nextAttempt.After(time.Now()) {
+		t.Fatalf("expected next attempt in the future, got %s", ds[0].nextAttempt)
+	}
+
+	// the retry shouldn't be done before the backoff interval
+	if err := h.deliverQueued(h.webhooks[0], make(chan struct{})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(s.Requests()); n != 1 {
+		t.Fatalf("expected 1 request, got %d", n)
+	}
+
+	s.setStatusCode(http.StatusOK)
+	deliverAll(t, h)
+	if n := len(s.Requests()); n != 3 {
+		t.Fatalf("expected 3 requests, got %d", n)
+	}
+	if ds := queuedDeliveries(t, h, "webhook01"); len(ds) != 0 {
+		t.Fatalf("expected no queued deliveries, got %d", len(ds))
+	}
+
+	if b := webhookRetryBackoff(1); b != webhookRetryInterval {
+		t.Fatalf("expected backoff %s, got %s", webhookRetryInterval, b)
+	}
+	if b := webhookRetryBackoff(3); b != 4*webhookRetryInterval {
+		t.Fatalf("expected backoff %s, got %s", 4*webhookRetryInterval, b)
+	}
+	if b := webhookRetryBackoff(100); b != webhookMaxRetryInterval {
+		t.Fatalf("expected backoff %s, got %s", webhookMaxRetryInterval, b)
+	}
+}
+
+func TestWebhookDeadLetters(t *testing.T) {
+	s := newTestWebhookServer()
+	defer s.Close()
+	s.setStatusCode(http.StatusInternalServerError)
+
+	h, es, dir := setupWebhookHandler(t, []config.Webhook{
+		{Name: "webhook01", URL: s.URL, MaxRetries: 1},
+	})
+	defer os.RemoveAll(dir)
+
+	writeTestEvents(t, es, aggregate.RolesTreeAggregate, aggregate.RolesTreeAggregateID, testRoleCreatedEvent(nil))
+
+	if err := h.HandleEvents(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// first attempt and one retry
+	deliverAll(t, h)
+	deliverAll(t, h)
+	if n := len(s.Requests()); n != 2 {
+		t.Fatalf("expected 2 requests, got %d", n)
+	}
+	if ds := queuedDeliveries(t, h, "webhook01"); len(ds) != 0 {
+		t.Fatalf("expected no queued deliveries, got %d", len(ds))
+	}
+
+	dls, err := WebhookDeadLetters(dir, "webhook01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dls) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(dls))
+	}
+	if dls[0].Attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", dls[0].Attempts)
+	}
+	if dls[0].EventType != ep.EventTypeRoleCreated.String() {
+		t.Fatalf("expected event type %q, got %q", ep.EventTypeRoleCreated, dls[0].EventType)
+	}
+	if dls, err := WebhookDeadLetters(dir, "webhook02"); err != nil || len(dls) != 0 {
+		t.Fatalf("expected no dead letters, got %d, err: %v", len(dls), err)
+	}
+
+	// replay the dead letter
+	s.setStatusCode(http.StatusOK)
+	if _, err := ReplayWebhookDeadLetters(dir, "", []util.ID{util.NewFromUUID(uuid.NewV4())}); err == nil {
+		t.Fatalf("expected error replaying an unexistent dead letter")
+	}
+	replayed, err := ReplayWebhookDeadLetters(dir, "", []util.ID{dls[0].ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(replayed) != 1 {
+		t.Fatalf("expected 1 replayed dead letter, got %d", len(replayed))
+	}
+
+	deliverAll(t, h)
+	requests := s.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	if string(requests[2].body) != string(requests[0].body) {
+		t.Fatalf("expected the replayed payload to be equal to the original one")
+	}
+
+	dls, err = WebhookDeadLetters(dir, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dls) != 0 {
+		t.Fatalf("expected no dead letters, got %d", len(dls))
+	}
+}
+
+func TestWebhookStaleDB(t *testing.T) {
+	s := newTestWebhookServer()
+	defer s.Close()
+	s.setStatusCode(http.StatusInternalServerError)
+
+	webhooksConfig := []config.Webhook{
+		{Name: "webhook01", URL: s.URL, MaxRetries: 1},
+	}
+	h, es, dir := setupWebhookHandler(t, webhooksConfig)
+	defer os.RemoveAll(dir)
+
+	writeTestEvents(t, es, aggregate.RolesTreeAggregate, aggregate.RolesTreeAggregateID, testRoleCreatedEvent(nil), testRoleCreatedEvent(nil))
+
+	if err := h.HandleEvents(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// move the first event to the dead letters, the second stays queued
+	deliverAll(t, h)
+	deliverAll(t, h)
+
+	// a new eventstore, with different events, makes the snapshot db stale
+	nes := eventstore.NewMemoryEventStore(ln.NewLocalNotifierFactory(ln.NewLocalListenNotify()))
+	writeTestEvents(t, nes, aggregate.RolesTreeAggregate, aggregate.RolesTreeAggregateID, testRoleCreatedEvent(nil), testRoleCreatedEvent(nil), testRoleCreatedEvent(nil))
+
+	nh, err := NewWebhookHandler(dir, nes, webhooksConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the queued deliveries and the dead letters must be kept
+	if ds := queuedDeliveries(t, nh, "webhook01"); len(ds) != 1 {
+		t.Fatalf("expected 1 queued delivery, got %d", len(ds))
+	}
+	dls, err := WebhookDeadLetters(dir, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dls) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(dls))
+	}
+
+	// only the events after the last handled sequence number are queued
+	if err := nh.HandleEvents(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ds := queuedDeliveries(t, nh, "webhook01")
+	if len(ds) != 2 {
+		t.Fatalf("expected 2 queued deliveries, got %d", len(ds))
+	}
+	if ds[1].sequenceNumber != 3 {
+		t.Fatalf("expected queued event with sequence number %d, got %d", 3, ds[1].sequenceNumber)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
+	"github.com/sorintlab/sircles/change"
 	"github.com/sorintlab/sircles/eventstore"
 	"github.com/sorintlab/sircles/models"
 	"github.com/sorintlab/sircles/util"
@@ -22,13 +23,10 @@ type Event interface {
 	EventType() EventType
 }
 
+// UnmarshalData returns the stored event data, upcasted to the current event
+// data version, unmarshalled in the event type struct
 func UnmarshalData(e *eventstore.StoredEvent) (interface{}, error) {
-	d := GetEventDataType(EventType(e.EventType))
-	if err := json.Unmarshal(e.Data, &d); err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	return d, nil
+	return upcasters.UnmarshalData(e)
 }
 
 func UnmarshalMetaData(e *eventstore.StoredEvent) (*eventstore.EventMetaData, error) {
@@ -40,7 +38,7 @@ func UnmarshalMetaData(e *eventstore.StoredEvent) (*eventstore.EventMetaData, er
 	return md, nil
 }
 
-func GenEventData(events []Event, correlationID, causationID, groupID, issuerID *util.ID) ([]*eventstore.EventData, error) {
+func GenEventData(events []Event, correlationID, causationID, commandCausationID, groupID, issuerID *util.ID) ([]*eventstore.EventData, error) {
 	eventsData := make([]*eventstore.EventData, len(events))
 	for i, e := range events {
 		data, err := json.Marshal(e)
@@ -50,10 +48,12 @@ func GenEventData(events []Event, correlationID, causationID, groupID, issuerID
 
 		// augment events with common metadata
 		md := &eventstore.EventMetaData{
-			CorrelationID:   correlationID,
-			CausationID:     causationID,
-			GroupID:         groupID,
-			CommandIssuerID: issuerID,
+			CorrelationID:      correlationID,
+			CausationID:        causationID,
+			GroupID:            groupID,
+			CommandIssuerID:    issuerID,
+			CommandCausationID: commandCausationID,
+			DataVersion:        EventDataVersion(e.EventType()),
 		}
 		metaData, err := json.Marshal(md)
 		if err != nil {
@@ -105,10 +105,15 @@ const (
 	EventTypeCircleCoreRoleMemberSet   EventType = "CircleCoreRoleMemberSet"
 	EventTypeCircleCoreRoleMemberUnset EventType = "CircleCoreRoleMemberUnset"
 
+	EventTypeCircleCoreRoleElectionExpired EventType = "CircleCoreRoleElectionExpired"
+
+	EventTypeCircleProposalApplied EventType = "CircleProposalApplied"
+
 	// MemberChange Aggregate
 	EventTypeMemberChangeCreateRequested      EventType = "MemberChangeCreateRequested"
 	EventTypeMemberChangeUpdateRequested      EventType = "MemberChangeUpdateRequested"
 	EventTypeMemberChangeSetMatchUIDRequested EventType = "MemberChangeSetMatchUIDRequested"
+	EventTypeMemberChangeDeleteRequested      EventType = "MemberChangeDeleteRequested"
 	EventTypeMemberChangeCompleted            EventType = "MemberChangeCompleted"
 
 	// Member Aggregate
@@ -118,12 +123,46 @@ const (
 	EventTypeMemberPasswordSet EventType = "MemberPasswordSet"
 	EventTypeMemberAvatarSet   EventType = "MemberAvatarSet"
 	EventTypeMemberMatchUIDSet EventType = "MemberMatchUIDSet"
+	EventTypeMemberForgotten   EventType = "MemberForgotten"
 
 	// Tension Aggregate
-	EventTypeTensionCreated     EventType = "TensionCreated"
-	EventTypeTensionUpdated     EventType = "TensionUpdated"
-	EventTypeTensionRoleChanged EventType = "TensionRoleChanged"
-	EventTypeTensionClosed      EventType = "TensionClosed"
+	EventTypeTensionCreated         EventType = "TensionCreated"
+	EventTypeTensionUpdated         EventType = "TensionUpdated"
+	EventTypeTensionRoleChanged     EventType = "TensionRoleChanged"
+	EventTypeTensionClosed          EventType = "TensionClosed"
+	EventTypeTensionReopened        EventType = "TensionReopened"
+	EventTypeTensionStateChanged    EventType = "TensionStateChanged"
+	EventTypeTensionCommentAdded    EventType = "TensionCommentAdded"
+	EventTypeTensionAssigneeChanged EventType = "TensionAssigneeChanged"
+	EventTypeTensionLabelsSet       EventType = "TensionLabelsSet"
+
+	// Proposal Aggregate
+	EventTypeProposalCreated         EventType = "ProposalCreated"
+	EventTypeProposalUpdated         EventType = "ProposalUpdated"
+	EventTypeProposalObjectionRaised EventType = "ProposalObjectionRaised"
+	EventTypeProposalAccepted        EventType = "ProposalAccepted"
+	// ProposalAcceptanceReverted reopens a proposal whose changes couldn't be
+	// applied to the roles tree after its acceptance
+	EventTypeProposalAcceptanceReverted EventType = "ProposalAcceptanceReverted"
+
+	// Meeting Aggregate
+	EventTypeMeetingCreated              EventType = "MeetingCreated"
+	EventTypeMeetingAgendaItemAdded      EventType = "MeetingAgendaItemAdded"
+	EventTypeMeetingAgendaItemOutcomeSet EventType = "MeetingAgendaItemOutcomeSet"
+	EventTypeMeetingClosed               EventType = "MeetingClosed"
+
+	// Project Aggregate
+	EventTypeProjectCreated             EventType = "ProjectCreated"
+	EventTypeProjectUpdated             EventType = "ProjectUpdated"
+	EventTypeProjectStatusChanged       EventType = "ProjectStatusChanged"
+	EventTypeProjectNextActionAdded     EventType = "ProjectNextActionAdded"
+	EventTypeProjectNextActionCompleted EventType = "ProjectNextActionCompleted"
+
+	// ReportItem Aggregate
+	EventTypeReportItemCreated  EventType = "ReportItemCreated"
+	EventTypeReportItemUpdated  EventType = "ReportItemUpdated"
+	EventTypeReportItemDeleted  EventType = "ReportItemDeleted"
+	EventTypeReportItemValueSet EventType = "ReportItemValueSet"
 
 	EventTypeMemberRequestHandlerStateUpdated EventType = "MemberRequestHandlerStateUpdated"
 
@@ -186,12 +225,20 @@ func GetEventDataType(eventType EventType) interface{} {
 	case EventTypeCircleCoreRoleMemberUnset:
 		return &EventCircleCoreRoleMemberUnset{}
 
+	case EventTypeCircleCoreRoleElectionExpired:
+		return &EventCircleCoreRoleElectionExpired{}
+
+	case EventTypeCircleProposalApplied:
+		return &EventCircleProposalApplied{}
+
 	case EventTypeMemberChangeCreateRequested:
 		return &EventMemberChangeCreateRequested{}
 	case EventTypeMemberChangeUpdateRequested:
 		return &EventMemberChangeUpdateRequested{}
 	case EventTypeMemberChangeSetMatchUIDRequested:
 		return &EventMemberChangeSetMatchUIDRequested{}
+	case EventTypeMemberChangeDeleteRequested:
+		return &EventMemberChangeDeleteRequested{}
 	case EventTypeMemberChangeCompleted:
 		return &EventMemberChangeCompleted{}
 
@@ -199,12 +246,16 @@ func GetEventDataType(eventType EventType) interface{} {
 		return &EventMemberCreated{}
 	case EventTypeMemberUpdated:
 		return &EventMemberUpdated{}
+	case EventTypeMemberDeleted:
+		return &EventMemberDeleted{}
 	case EventTypeMemberPasswordSet:
 		return &EventMemberPasswordSet{}
 	case EventTypeMemberAvatarSet:
 		return &EventMemberAvatarSet{}
 	case EventTypeMemberMatchUIDSet:
 		return &EventMemberMatchUIDSet{}
+	case EventTypeMemberForgotten:
+		return &EventMemberForgotten{}
 
 	case EventTypeTensionCreated:
 		return &EventTensionCreated{}
@@ -214,6 +265,55 @@ func GetEventDataType(eventType EventType) interface{} {
 		return &EventTensionRoleChanged{}
 	case EventTypeTensionClosed:
 		return &EventTensionClosed{}
+	case EventTypeTensionReopened:
+		return &EventTensionReopened{}
+	case EventTypeTensionStateChanged:
+		return &EventTensionStateChanged{}
+	case EventTypeTensionCommentAdded:
+		return &EventTensionCommentAdded{}
+	case EventTypeTensionAssigneeChanged:
+		return &EventTensionAssigneeChanged{}
+	case EventTypeTensionLabelsSet:
+		return &EventTensionLabelsSet{}
+
+	case EventTypeProposalCreated:
+		return &EventProposalCreated{}
+	case EventTypeProposalUpdated:
+		return &EventProposalUpdated{}
+	case EventTypeProposalObjectionRaised:
+		return &EventProposalObjectionRaised{}
+	case EventTypeProposalAccepted:
+		return &EventProposalAccepted{}
+	case EventTypeProposalAcceptanceReverted:
+		return &EventProposalAcceptanceReverted{}
+
+	case EventTypeMeetingCreated:
+		return &EventMeetingCreated{}
+	case EventTypeMeetingAgendaItemAdded:
+		return &EventMeetingAgendaItemAdded{}
+	case EventTypeMeetingAgendaItemOutcomeSet:
+		return &EventMeetingAgendaItemOutcomeSet{}
+	case EventTypeMeetingClosed:
+		return &EventMeetingClosed{}
+
+	case EventTypeProjectCreated:
+		return &EventProjectCreated{}
+	case EventTypeProjectUpdated:
+		return &EventProjectUpdated{}
+	case EventTypeProjectStatusChanged:
+		return &EventProjectStatusChanged{}
+	case EventTypeProjectNextActionAdded:
+		return &EventProjectNextActionAdded{}
+	case EventTypeProjectNextActionCompleted:
+		return &EventProjectNextActionCompleted{}
+	case EventTypeReportItemCreated:
+		return &EventReportItemCreated{}
+	case EventTypeReportItemUpdated:
+		return &EventReportItemUpdated{}
+	case EventTypeReportItemDeleted:
+		return &EventReportItemDeleted{}
+	case EventTypeReportItemValueSet:
+		return &EventReportItemValueSet{}
 
 	case EventTypeMemberRequestHandlerStateUpdated:
 		return &EventMemberRequestHandlerStateUpdated{}
@@ -601,6 +701,50 @@ func (e *EventCircleCoreRoleMemberUnset) EventType() EventType {
 	return EventTypeCircleCoreRoleMemberUnset
 }
 
+// EventCircleCoreRoleElectionExpired is emitted when the election term of an
+// elected core role member has ended. The member keeps the role until a new
+// election takes place.
+type EventCircleCoreRoleElectionExpired struct {
+	RoleID             util.ID
+	RoleType           models.RoleType
+	MemberID           util.ID
+	ElectionExpiration time.Time
+	// This field isn't needed but can be retrieved from the current
+	// aggregate state. It's provided to add additional information and to
+	// avoid gets during the event application
+	CoreRoleID util.ID
+}
+
+func NewEventCircleCoreRoleElectionExpired(roleID, coreRoleID, memberID util.ID, roleType models.RoleType, electionExpiration time.Time) *EventCircleCoreRoleElectionExpired {
+	return &EventCircleCoreRoleElectionExpired{
+		RoleID:             roleID,
+		RoleType:           roleType,
+		MemberID:           memberID,
+		ElectionExpiration: electionExpiration,
+		CoreRoleID:         coreRoleID,
+	}
+}
+
+func (e *EventCircleCoreRoleElectionExpired) EventType() EventType {
+	return EventTypeCircleCoreRoleElectionExpired
+}
+
+type EventCircleProposalApplied struct {
+	RoleID     util.ID
+	ProposalID util.ID
+}
+
+func NewEventCircleProposalApplied(roleID, proposalID util.ID) *EventCircleProposalApplied {
+	return &EventCircleProposalApplied{
+		RoleID:     roleID,
+		ProposalID: proposalID,
+	}
+}
+
+func (e *EventCircleProposalApplied) EventType() EventType {
+	return EventTypeCircleProposalApplied
+}
+
 type EventTensionCreated struct {
 	Title       string
 	Description string
@@ -667,6 +811,402 @@ func (e *EventTensionClosed) EventType() EventType {
 	return EventTypeTensionClosed
 }
 
+// EventTensionReopened reports the tension role at reopen time so the
+// handlers tracking the open tensions can restore them
+type EventTensionReopened struct {
+	RoleID *util.ID
+}
+
+func NewEventTensionReopened(roleID *util.ID) *EventTensionReopened {
+	return &EventTensionReopened{
+		RoleID: roleID,
+	}
+}
+
+func (e *EventTensionReopened) EventType() EventType {
+	return EventTypeTensionReopened
+}
+
+type EventTensionStateChanged struct {
+	State         models.TensionState
+	PreviousState models.TensionState
+}
+
+func NewEventTensionStateChanged(state, previousState models.TensionState) *EventTensionStateChanged {
+	return &EventTensionStateChanged{
+		State:         state,
+		PreviousState: previousState,
+	}
+}
+
+func (e *EventTensionStateChanged) EventType() EventType {
+	return EventTypeTensionStateChanged
+}
+
+type EventTensionCommentAdded struct {
+	CommentID       util.ID
+	ParentCommentID *util.ID
+	MemberID        util.ID
+	Text            string
+}
+
+func NewEventTensionCommentAdded(commentID util.ID, parentCommentID *util.ID, memberID util.ID, text string) *EventTensionCommentAdded {
+	return &EventTensionCommentAdded{
+		CommentID:       commentID,
+		ParentCommentID: parentCommentID,
+		MemberID:        memberID,
+		Text:            text,
+	}
+}
+
+func (e *EventTensionCommentAdded) EventType() EventType {
+	return EventTypeTensionCommentAdded
+}
+
+// EventTensionAssigneeChanged reports the new tension assignee, a member
+// filling a role, and the previous one. Nil ids mean no assignee.
+type EventTensionAssigneeChanged struct {
+	RoleID           *util.ID
+	MemberID         *util.ID
+	PreviousRoleID   *util.ID
+	PreviousMemberID *util.ID
+}
+
+func NewEventTensionAssigneeChanged(roleID, memberID, previousRoleID, previousMemberID *util.ID) *EventTensionAssigneeChanged {
+	return &EventTensionAssigneeChanged{
+		RoleID:           roleID,
+		MemberID:         memberID,
+		PreviousRoleID:   previousRoleID,
+		PreviousMemberID: previousMemberID,
+	}
+}
+
+func (e *EventTensionAssigneeChanged) EventType() EventType {
+	return EventTypeTensionAssigneeChanged
+}
+
+type EventTensionLabelsSet struct {
+	Labels []string
+}
+
+func NewEventTensionLabelsSet(labels []string) *EventTensionLabelsSet {
+	return &EventTensionLabelsSet{
+		Labels: labels,
+	}
+}
+
+func (e *EventTensionLabelsSet) EventType() EventType {
+	return EventTypeTensionLabelsSet
+}
+
+type EventProposalCreated struct {
+	TensionID util.ID
+	RoleID    util.ID
+	MemberID  util.ID
+	change.ProposalChanges
+}
+
+func NewEventProposalCreated(tensionID, roleID, memberID util.ID, changes change.ProposalChanges) *EventProposalCreated {
+	return &EventProposalCreated{
+		TensionID:       tensionID,
+		RoleID:          roleID,
+		MemberID:        memberID,
+		ProposalChanges: changes,
+	}
+}
+
+func (e *EventProposalCreated) EventType() EventType {
+	return EventTypeProposalCreated
+}
+
+type EventProposalUpdated struct {
+	change.ProposalChanges
+}
+
+func NewEventProposalUpdated(changes change.ProposalChanges) *EventProposalUpdated {
+	return &EventProposalUpdated{
+		ProposalChanges: changes,
+	}
+}
+
+func (e *EventProposalUpdated) EventType() EventType {
+	return EventTypeProposalUpdated
+}
+
+type EventProposalObjectionRaised struct {
+	MemberID util.ID
+	Reason   string
+}
+
+func NewEventProposalObjectionRaised(memberID util.ID, reason string) *EventProposalObjectionRaised {
+	return &EventProposalObjectionRaised{
+		MemberID: memberID,
+		Reason:   reason,
+	}
+}
+
+func (e *EventProposalObjectionRaised) EventType() EventType {
+	return EventTypeProposalObjectionRaised
+}
+
+type EventProposalAccepted struct {
+	MemberID util.ID
+}
+
+func NewEventProposalAccepted(memberID util.ID) *EventProposalAccepted {
+	return &EventProposalAccepted{
+		MemberID: memberID,
+	}
+}
+
+func (e *EventProposalAccepted) EventType() EventType {
+	return EventTypeProposalAccepted
+}
+
+type EventProposalAcceptanceReverted struct {
+	Reason string
+}
+
+func NewEventProposalAcceptanceReverted(reason string) *EventProposalAcceptanceReverted {
+	return &EventProposalAcceptanceReverted{
+		Reason: reason,
+	}
+}
+
+func (e *EventProposalAcceptanceReverted) EventType() EventType {
+	return EventTypeProposalAcceptanceReverted
+}
+
+type EventMeetingCreated struct {
+	RoleID        util.ID
+	MeetingType   models.MeetingType
+	Title         string
+	StartTime     time.Time
+	MemberID      util.ID
+	FacilitatorID *util.ID
+	SecretaryID   *util.ID
+	AttendeesIDs  []util.ID
+}
+
+func NewEventMeetingCreated(meeting *models.Meeting, roleID, memberID util.ID, facilitatorID, secretaryID *util.ID, attendeesIDs []util.ID) *EventMeetingCreated {
+	return &EventMeetingCreated{
+		RoleID:        roleID,
+		MeetingType:   meeting.MeetingType,
+		Title:         meeting.Title,
+		StartTime:     meeting.StartTime,
+		MemberID:      memberID,
+		FacilitatorID: facilitatorID,
+		SecretaryID:   secretaryID,
+		AttendeesIDs:  attendeesIDs,
+	}
+}
+
+func (e *EventMeetingCreated) EventType() EventType {
+	return EventTypeMeetingCreated
+}
+
+type EventMeetingAgendaItemAdded struct {
+	AgendaItemID util.ID
+	TensionID    *util.ID
+	Title        string
+}
+
+func NewEventMeetingAgendaItemAdded(agendaItemID util.ID, tensionID *util.ID, title string) *EventMeetingAgendaItemAdded {
+	return &EventMeetingAgendaItemAdded{
+		AgendaItemID: agendaItemID,
+		TensionID:    tensionID,
+		Title:        title,
+	}
+}
+
+func (e *EventMeetingAgendaItemAdded) EventType() EventType {
+	return EventTypeMeetingAgendaItemAdded
+}
+
+type EventMeetingAgendaItemOutcomeSet struct {
+	AgendaItemID util.ID
+	Outcome      string
+}
+
+func NewEventMeetingAgendaItemOutcomeSet(agendaItemID util.ID, outcome string) *EventMeetingAgendaItemOutcomeSet {
+	return &EventMeetingAgendaItemOutcomeSet{
+		AgendaItemID: agendaItemID,
+		Outcome:      outcome,
+	}
+}
+
+func (e *EventMeetingAgendaItemOutcomeSet) EventType() EventType {
+	return EventTypeMeetingAgendaItemOutcomeSet
+}
+
+type EventMeetingClosed struct{}
+
+func NewEventMeetingClosed() *EventMeetingClosed {
+	return &EventMeetingClosed{}
+}
+
+func (e *EventMeetingClosed) EventType() EventType {
+	return EventTypeMeetingClosed
+}
+
+type EventProjectCreated struct {
+	RoleID      util.ID
+	MemberID    util.ID
+	Title       string
+	Description string
+	Status      models.ProjectStatus
+}
+
+func NewEventProjectCreated(project *models.Project, roleID, memberID util.ID) *EventProjectCreated {
+	return &EventProjectCreated{
+		RoleID:      roleID,
+		MemberID:    memberID,
+		Title:       project.Title,
+		Description: project.Description,
+		Status:      project.Status,
+	}
+}
+
+func (e *EventProjectCreated) EventType() EventType {
+	return EventTypeProjectCreated
+}
+
+type EventProjectUpdated struct {
+	Title       string
+	Description string
+}
+
+func NewEventProjectUpdated(title, description string) *EventProjectUpdated {
+	return &EventProjectUpdated{
+		Title:       title,
+		Description: description,
+	}
+}
+
+func (e *EventProjectUpdated) EventType() EventType {
+	return EventTypeProjectUpdated
+}
+
+type EventProjectStatusChanged struct {
+	Status         models.ProjectStatus
+	PreviousStatus models.ProjectStatus
+}
+
+func NewEventProjectStatusChanged(status, previousStatus models.ProjectStatus) *EventProjectStatusChanged {
+	return &EventProjectStatusChanged{
+		Status:         status,
+		PreviousStatus: previousStatus,
+	}
+}
+
+func (e *EventProjectStatusChanged) EventType() EventType {
+	return EventTypeProjectStatusChanged
+}
+
+type EventProjectNextActionAdded struct {
+	NextActionID util.ID
+	Description  string
+}
+
+func NewEventProjectNextActionAdded(nextActionID util.ID, description string) *EventProjectNextActionAdded {
+	return &EventProjectNextActionAdded{
+		NextActionID: nextActionID,
+		Description:  description,
+	}
+}
+
+func (e *EventProjectNextActionAdded) EventType() EventType {
+	return EventTypeProjectNextActionAdded
+}
+
+type EventProjectNextActionCompleted struct {
+	NextActionID util.ID
+}
+
+func NewEventProjectNextActionCompleted(nextActionID util.ID) *EventProjectNextActionCompleted {
+	return &EventProjectNextActionCompleted{
+		NextActionID: nextActionID,
+	}
+}
+
+func (e *EventProjectNextActionCompleted) EventType() EventType {
+	return EventTypeProjectNextActionCompleted
+}
+
+type EventReportItemCreated struct {
+	CircleID       util.ID
+	RoleID         util.ID
+	ReportItemType models.ReportItemType
+	Description    string
+	Frequency      models.ReportFrequency
+}
+
+func NewEventReportItemCreated(reportItem *models.ReportItem, circleID, roleID util.ID) *EventReportItemCreated {
+	return &EventReportItemCreated{
+		CircleID:       circleID,
+		RoleID:         roleID,
+		ReportItemType: reportItem.ReportItemType,
+		Description:    reportItem.Description,
+		Frequency:      reportItem.Frequency,
+	}
+}
+
+func (e *EventReportItemCreated) EventType() EventType {
+	return EventTypeReportItemCreated
+}
+
+type EventReportItemUpdated struct {
+	RoleID         util.ID
+	PreviousRoleID util.ID
+	Description    string
+}
+
+func NewEventReportItemUpdated(roleID, previousRoleID util.ID, description string) *EventReportItemUpdated {
+	return &EventReportItemUpdated{
+		RoleID:         roleID,
+		PreviousRoleID: previousRoleID,
+		Description:    description,
+	}
+}
+
+func (e *EventReportItemUpdated) EventType() EventType {
+	return EventTypeReportItemUpdated
+}
+
+type EventReportItemDeleted struct {
+	CircleID util.ID
+	RoleID   util.ID
+}
+
+func NewEventReportItemDeleted(circleID, roleID util.ID) *EventReportItemDeleted {
+	return &EventReportItemDeleted{
+		CircleID: circleID,
+		RoleID:   roleID,
+	}
+}
+
+func (e *EventReportItemDeleted) EventType() EventType {
+	return EventTypeReportItemDeleted
+}
+
+type EventReportItemValueSet struct {
+	Period        time.Time
+	Value         float64
+	PreviousValue *float64
+}
+
+func NewEventReportItemValueSet(period time.Time, value float64, previousValue *float64) *EventReportItemValueSet {
+	return &EventReportItemValueSet{
+		Period:        period,
+		Value:         value,
+		PreviousValue: previousValue,
+	}
+}
+
+func (e *EventReportItemValueSet) EventType() EventType {
+	return EventTypeReportItemValueSet
+}
+
 type EventMemberChangeCreateRequested struct {
 	MemberID     util.ID
 	IsAdmin      bool
@@ -740,6 +1280,22 @@ func (e *EventMemberChangeSetMatchUIDRequested) EventType() EventType {
 	return EventTypeMemberChangeSetMatchUIDRequested
 }
 
+type EventMemberChangeDeleteRequested struct {
+	MemberID   util.ID
+	TensionIDs []util.ID
+}
+
+func NewEventMemberChangeDeleteRequested(memberChangeID util.ID, memberID util.ID, tensionIDs []util.ID) *EventMemberChangeDeleteRequested {
+	return &EventMemberChangeDeleteRequested{
+		MemberID:   memberID,
+		TensionIDs: tensionIDs,
+	}
+}
+
+func (e *EventMemberChangeDeleteRequested) EventType() EventType {
+	return EventTypeMemberChangeDeleteRequested
+}
+
 type EventMemberChangeCompleted struct {
 	Error  bool
 	Reason string
@@ -807,6 +1363,27 @@ func (e *EventMemberUpdated) EventType() EventType {
 	return EventTypeMemberUpdated
 }
 
+type EventMemberDeleted struct {
+	UserName string
+	Email    string
+	MatchUID string
+
+	MemberChangeID util.ID
+}
+
+func NewEventMemberDeleted(memberID util.ID, memberChangeID util.ID, userName, email, matchUID string) *EventMemberDeleted {
+	return &EventMemberDeleted{
+		UserName:       userName,
+		Email:          email,
+		MatchUID:       matchUID,
+		MemberChangeID: memberChangeID,
+	}
+}
+
+func (e *EventMemberDeleted) EventType() EventType {
+	return EventTypeMemberDeleted
+}
+
 type EventMemberPasswordSet struct {
 	PasswordHash string
 }
@@ -855,6 +1432,18 @@ func (e *EventMemberMatchUIDSet) EventType() EventType {
 	return EventTypeMemberMatchUIDSet
 }
 
+// EventMemberForgotten is emitted when the member personal data key has been
+// destroyed
+type EventMemberForgotten struct{}
+
+func NewEventMemberForgotten(memberID util.ID) *EventMemberForgotten {
+	return &EventMemberForgotten{}
+}
+
+func (e *EventMemberForgotten) EventType() EventType {
+	return EventTypeMemberForgotten
+}
+
 type EventMemberRequestHandlerStateUpdated struct {
 	MemberChangeSequenceNumber int64
 	MemberSequenceNumber       int64
@@ -0,0 +1,324 @@
+package events
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/util"
+)
+
+// PersonalDataPlaceholder replaces the text personal data of a forgotten
+// member
+const PersonalDataPlaceholder = "[forgotten]"
+
+// encryptedFieldPrefix prefixes the encrypted personal data fields values
+const encryptedFieldPrefix = "pd1:"
+
+var (
+	textPlaceholder   = json.RawMessage(`"` + PersonalDataPlaceholder + `"`)
+	binaryPlaceholder = json.RawMessage(`null`)
+)
+
+// personalDataFields defines the personal data fields of an event type
+type personalDataFields struct {
+	// memberIDField is the data field containing the ID of the member owning
+	// the personal data. When empty the event stream ID is the member ID.
+	memberIDField string
+	// fields maps the personal data fields to the placeholder used when the
+	// member has been forgotten
+	fields map[string]json.RawMessage
+}
+
+var personalData = map[EventType]*personalDataFields{
+	EventTypeMemberChangeCreateRequested: {
+		memberIDField: "MemberID",
+		fields: map[string]json.RawMessage{
+			"MatchUID":     textPlaceholder,
+			"UserName":     textPlaceholder,
+			"FullName":     textPlaceholder,
+			"Email":        textPlaceholder,
+			"PasswordHash": textPlaceholder,
+			"Avatar":       binaryPlaceholder,
+		},
+	},
+	EventTypeMemberChangeUpdateRequested: {
+		memberIDField: "MemberID",
+		fields: map[string]json.RawMessage{
+			"UserName":     textPlaceholder,
+			"FullName":     textPlaceholder,
+			"Email":        textPlaceholder,
+			"Avatar":       binaryPlaceholder,
+			"PrevUserName": textPlaceholder,
+			"PrevEmail":    textPlaceholder,
+		},
+	},
+	EventTypeMemberChangeSetMatchUIDRequested: {
+		memberIDField: "MemberID",
+		fields: map[string]json.RawMessage{
+			"MatchUID": textPlaceholder,
+		},
+	},
+	EventTypeMemberCreated: {
+		fields: map[string]json.RawMessage{
+			"UserName": textPlaceholder,
+			"FullName": textPlaceholder,
+			"Email":    textPlaceholder,
+		},
+	},
+	EventTypeMemberUpdated: {
+		fields: map[string]json.RawMessage{
+			"UserName":     textPlaceholder,
+			"FullName":     textPlaceholder,
+			"Email":        textPlaceholder,
+			"PrevUserName": textPlaceholder,
+			"PrevEmail":    textPlaceholder,
+		},
+	},
+	EventTypeMemberDeleted: {
+		fields: map[string]json.RawMessage{
+			"UserName": textPlaceholder,
+			"Email":    textPlaceholder,
+			"MatchUID": textPlaceholder,
+		},
+	},
+	EventTypeMemberPasswordSet: {
+		fields: map[string]json.RawMessage{
+			"PasswordHash": textPlaceholder,
+		},
+	},
+	EventTypeMemberAvatarSet: {
+		fields: map[string]json.RawMessage{
+			"Image": binaryPlaceholder,
+		},
+	},
+	EventTypeMemberMatchUIDSet: {
+		fields: map[string]json.RawMessage{
+			"MatchUID":     textPlaceholder,
+			"PrevMatchUID": textPlaceholder,
+		},
+	},
+	// the unique value registry is only used to reserve members usernames,
+	// emails and matchUIDs
+	EventTypeUniqueRegistryValueReserved: {
+		memberIDField: "ID",
+		fields: map[string]json.RawMessage{
+			"Value": textPlaceholder,
+		},
+	},
+	EventTypeUniqueRegistryValueReleased: {
+		memberIDField: "ID",
+		fields: map[string]json.RawMessage{
+			"Value": textPlaceholder,
+		},
+	},
+}
+
+// PersonalDataKeyStore provides the per member personal data keys
+type PersonalDataKeyStore interface {
+	// PersonalDataKey returns the key with the provided id, creating it when
+	// it doesn't exist and create is true. A nil key is returned if the key
+	// has been destroyed.
+	PersonalDataKey(id util.ID, create bool) ([]byte, error)
+}
+
+// PersonalDataCodec is an eventstore data codec encrypting the members
+// personal data fields in the events data with a per member key.
+// Destroying a member key (forgetting the member) makes the encrypted fields
+// unreadable: they'll be decoded as placeholders while all the other event
+// data remains available.
+type PersonalDataCodec struct {
+	ks PersonalDataKeyStore
+}
+
+func NewPersonalDataCodec(ks PersonalDataKeyStore) *PersonalDataCodec {
+	return &PersonalDataCodec{ks: ks}
+}
+
+func (c *PersonalDataCodec) unmarshal(e *eventstore.StoredEvent) (*personalDataFields, map[string]json.RawMessage, *eventstore.EventMetaData, error) {
+	pdf, ok := personalData[EventType(e.EventType)]
+	if !ok {
+		return nil, nil, nil, nil
+	}
+
+	md := &eventstore.EventMetaData{}
+	// old or test events may not have metadata
+	if len(e.MetaData) > 0 {
+		var err error
+		md, err = UnmarshalMetaData(e)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to unmarshal event data")
+	}
+
+	return pdf, data, md, nil
+}
+
+func (c *PersonalDataCodec) marshal(e *eventstore.StoredEvent, data map[string]json.RawMessage, md *eventstore.EventMetaData) error {
+	var err error
+	e.Data, err = json.Marshal(data)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	e.MetaData, err = json.Marshal(md)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Encode encrypts the event personal data fields with the key of the member
+// owning them and saves the key id in the event metadata. If the member key
+// has been destroyed the fields are replaced by placeholders.
+func (c *PersonalDataCodec) Encode(e *eventstore.StoredEvent) error {
+	pdf, data, md, err := c.unmarshal(e)
+	if err != nil || pdf == nil {
+		return err
+	}
+	// already encoded
+	if md.PersonalDataKeyID != nil {
+		return nil
+	}
+
+	var memberID util.ID
+	if pdf.memberIDField == "" {
+		memberID, err = util.IDFromString(e.StreamID)
+		if err != nil {
+			return errors.Wrapf(err, "wrong member id %q", e.StreamID)
+		}
+	} else {
+		if err := json.Unmarshal(data[pdf.memberIDField], &memberID); err != nil {
+			return errors.Wrapf(err, "wrong member id field %q", pdf.memberIDField)
+		}
+	}
+
+	key, err := c.ks.PersonalDataKey(memberID, true)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		replacePersonalData(pdf, data)
+		return c.marshal(e, data, md)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	for field := range pdf.fields {
+		v, ok := data[field]
+		if !ok || isEmptyValue(v) {
+			continue
+		}
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return errors.Wrap(err, "failed to generate nonce")
+		}
+		// authenticate the encrypted value with the event id and field name
+		// to avoid moving it to another event or field
+		ct := gcm.Seal(nonce, nonce, v, additionalData(e, field))
+		ev, err := json.Marshal(encryptedFieldPrefix + base64.StdEncoding.EncodeToString(ct))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		data[field] = ev
+	}
+	md.PersonalDataKeyID = &memberID
+
+	return c.marshal(e, data, md)
+}
+
+// Decode decrypts the event personal data fields or replaces them with
+// placeholders if the key has been destroyed. The key id is removed from the
+// event metadata so the decoded event can be written again (i.e. restored
+// from a dump) and encoded with a new key.
+func (c *PersonalDataCodec) Decode(e *eventstore.StoredEvent) error {
+	pdf, data, md, err := c.unmarshal(e)
+	if err != nil || pdf == nil {
+		return err
+	}
+	if md.PersonalDataKeyID == nil {
+		return nil
+	}
+
+	key, err := c.ks.PersonalDataKey(*md.PersonalDataKeyID, false)
+	if err != nil {
+		return err
+	}
+	md.PersonalDataKeyID = nil
+	if key == nil {
+		replacePersonalData(pdf, data)
+		return c.marshal(e, data, md)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	for field := range pdf.fields {
+		v, ok := data[field]
+		if !ok || isEmptyValue(v) {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(v, &s); err != nil || !strings.HasPrefix(s, encryptedFieldPrefix) {
+			return errors.Errorf("field %q isn't encrypted", field)
+		}
+		ct, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, encryptedFieldPrefix))
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode field %q", field)
+		}
+		if len(ct) < gcm.NonceSize() {
+			return errors.Errorf("field %q encrypted value too short", field)
+		}
+		pv, err := gcm.Open(nil, ct[:gcm.NonceSize()], ct[gcm.NonceSize():], additionalData(e, field))
+		if err != nil {
+			return errors.Wrapf(err, "failed to decrypt field %q", field)
+		}
+		data[field] = pv
+	}
+
+	return c.marshal(e, data, md)
+}
+
+func replacePersonalData(pdf *personalDataFields, data map[string]json.RawMessage) {
+	for field, placeholder := range pdf.fields {
+		if v, ok := data[field]; ok && !isEmptyValue(v) {
+			data[field] = placeholder
+		}
+	}
+}
+
+// isEmptyValue reports if the provided json value is an empty string or
+// null. Empty values aren't personal data so they aren't encrypted or
+// replaced.
+func isEmptyValue(v json.RawMessage) bool {
+	s := string(v)
+	return s == `""` || s == "null"
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	return gcm, nil
+}
+
+func additionalData(e *eventstore.StoredEvent, field string) []byte {
+	return []byte(e.ID.String() + ":" + field)
+}
@@ -0,0 +1,246 @@
+package events
+
+import (
+	"bytes"
+	"crypto/rand"
+	"reflect"
+	"testing"
+
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/util"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+type testKeyStore struct {
+	keys map[util.ID][]byte
+}
+
+func newTestKeyStore() *testKeyStore {
+	return &testKeyStore{keys: map[util.ID][]byte{}}
+}
+
+func (ks *testKeyStore) PersonalDataKey(id util.ID, create bool) ([]byte, error) {
+	key, ok := ks.keys[id]
+	if ok {
+		return key, nil
+	}
+	if !create {
+		return nil, errors.Errorf("personal data key %s doesn't exist", id)
+	}
+	key = make([]byte, eventstore.PersonalDataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	ks.keys[id] = key
+	return key, nil
+}
+
+func (ks *testKeyStore) destroy(id util.ID) {
+	ks.keys[id] = nil
+}
+
+func toTestStoredEvents(t *testing.T, events []Event, streamID string) []*eventstore.StoredEvent {
+	correlationID := util.NewFromUUID(uuid.NewV4())
+	eventsData, err := GenEventData(events, &correlationID, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	storedEvents := make([]*eventstore.StoredEvent, len(eventsData))
+	for i, ed := range eventsData {
+		storedEvents[i] = &eventstore.StoredEvent{
+			ID:        ed.ID,
+			EventType: ed.EventType,
+			StreamID:  streamID,
+			Data:      ed.Data,
+			MetaData:  ed.MetaData,
+		}
+	}
+	return storedEvents
+}
+
+func unmarshalTestEvents(t *testing.T, events []*eventstore.StoredEvent) []interface{} {
+	out := make([]interface{}, len(events))
+	for i, e := range events {
+		d, err := UnmarshalData(e)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		out[i] = d
+	}
+	return out
+}
+
+func TestPersonalDataCodec(t *testing.T) {
+	memberID := util.NewFromUUID(uuid.NewV4())
+	memberChangeID := util.NewFromUUID(uuid.NewV4())
+	roleID := util.NewFromUUID(uuid.NewV4())
+
+	events := []Event{
+		&EventMemberCreated{
+			IsAdmin:        true,
+			UserName:       "user01",
+			FullName:       "User 01",
+			Email:          "user01@example.com",
+			MemberChangeID: memberChangeID,
+		},
+		&EventMemberAvatarSet{
+			Image: []byte("image01"),
+		},
+		// empty values are kept
+		&EventMemberMatchUIDSet{
+			MemberChangeID: memberChangeID,
+			PrevMatchUID:   "matchUID01",
+		},
+		&EventRoleMemberAdded{
+			RoleID:   roleID,
+			MemberID: memberID,
+		},
+	}
+
+	ks := newTestKeyStore()
+	codec := NewPersonalDataCodec(ks)
+
+	storedEvents := toTestStoredEvents(t, events, memberID.String())
+	for _, e := range storedEvents {
+		if err := codec.Encode(e); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for _, s := range []string{"user01", "User 01", "user01@example.com"} {
+		if bytes.Contains(storedEvents[0].Data, []byte(s)) {
+			t.Fatalf("encoded event data contains personal data %q: %s", s, storedEvents[0].Data)
+		}
+	}
+	for i, e := range storedEvents[:3] {
+		md, err := UnmarshalMetaData(e)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if md.PersonalDataKeyID == nil || *md.PersonalDataKeyID != memberID {
+			t.Fatalf("event %d: expected personal data key id %s, got %v", i, memberID, md.PersonalDataKeyID)
+		}
+	}
+	// the not personal data fields are kept in clear
+	data, err := UnmarshalData(storedEvents[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := data.(*EventMemberCreated); !d.IsAdmin || d.MemberChangeID != memberChangeID {
+		t.Fatalf("unexpected event data: %s", spew.Sdump(d))
+	}
+
+	// keep a copy of the encoded events
+	encodedEvents := make([]*eventstore.StoredEvent, len(storedEvents))
+	for i, e := range storedEvents {
+		ee := *e
+		encodedEvents[i] = &ee
+	}
+
+	for _, e := range storedEvents {
+		if err := codec.Decode(e); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		md, err := UnmarshalMetaData(e)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if md.PersonalDataKeyID != nil {
+			t.Fatalf("expected no personal data key id in decoded event metadata")
+		}
+	}
+	expected := []interface{}{events[0], events[1], events[2], events[3]}
+	if out := unmarshalTestEvents(t, storedEvents); !reflect.DeepEqual(out, expected) {
+		t.Fatalf("got:\n%s\nwant:\n%s", spew.Sdump(out), spew.Sdump(expected))
+	}
+
+	// an encrypted field moved to another event must not be decrypted
+	movedEvent := *encodedEvents[1]
+	movedEvent.ID = util.NewFromUUID(uuid.NewV4())
+	if err := codec.Decode(&movedEvent); err == nil {
+		t.Fatalf("expected error decoding an event with a moved encrypted field")
+	}
+
+	// forget the member
+	ks.destroy(memberID)
+
+	for _, e := range encodedEvents {
+		if err := codec.Decode(e); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expected = []interface{}{
+		&EventMemberCreated{
+			IsAdmin:        true,
+			UserName:       PersonalDataPlaceholder,
+			FullName:       PersonalDataPlaceholder,
+			Email:          PersonalDataPlaceholder,
+			MemberChangeID: memberChangeID,
+		},
+		&EventMemberAvatarSet{},
+		&EventMemberMatchUIDSet{
+			MemberChangeID: memberChangeID,
+			PrevMatchUID:   PersonalDataPlaceholder,
+		},
+		events[3],
+	}
+	if out := unmarshalTestEvents(t, encodedEvents); !reflect.DeepEqual(out, expected) {
+		t.Fatalf("got:\n%s\nwant:\n%s", spew.Sdump(out), spew.Sdump(expected))
+	}
+
+	// new events of a forgotten member are written with placeholders
+	storedEvents = toTestStoredEvents(t, []Event{
+		&EventMemberChangeUpdateRequested{
+			MemberID:     memberID,
+			UserName:     "user02",
+			FullName:     "User 02",
+			Email:        "user02@example.com",
+			PrevUserName: "user01",
+			PrevEmail:    "user01@example.com",
+		},
+	}, memberChangeID.String())
+	if err := codec.Encode(storedEvents[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := codec.Decode(storedEvents[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []interface{}{
+		&EventMemberChangeUpdateRequested{
+			MemberID:     memberID,
+			UserName:     PersonalDataPlaceholder,
+			FullName:     PersonalDataPlaceholder,
+			Email:        PersonalDataPlaceholder,
+			PrevUserName: PersonalDataPlaceholder,
+			PrevEmail:    PersonalDataPlaceholder,
+		},
+	}
+	if out := unmarshalTestEvents(t, storedEvents); !reflect.DeepEqual(out, expected) {
+		t.Fatalf("got:\n%s\nwant:\n%s", spew.Sdump(out), spew.Sdump(expected))
+	}
+}
+
+func TestPersonalDataCodecPlaintextEvents(t *testing.T) {
+	memberID := util.NewFromUUID(uuid.NewV4())
+
+	events := []Event{
+		&EventMemberPasswordSet{
+			PasswordHash: "passwordHash",
+		},
+	}
+
+	codec := NewPersonalDataCodec(newTestKeyStore())
+
+	// events written before personal data encryption are decoded as is
+	storedEvents := toTestStoredEvents(t, events, memberID.String())
+	data := storedEvents[0].Data
+	if err := codec.Decode(storedEvents[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(storedEvents[0].Data, data) {
+		t.Fatalf("got data %s, want data %s", storedEvents[0].Data, data)
+	}
+}
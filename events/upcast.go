@@ -0,0 +1,114 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sorintlab/sircles/eventstore"
+)
+
+// Upcaster transforms an event data payload from a data version to the next
+// one
+type Upcaster func(data []byte) ([]byte, error)
+
+// UpcasterRegistry keeps, for every event type, the chain of upcasters
+// transforming the old event data versions to the current one. The current
+// data version of an event type is the number of registered upcasters plus
+// one.
+type UpcasterRegistry struct {
+	upcasters map[EventType][]Upcaster
+}
+
+func NewUpcasterRegistry() *UpcasterRegistry {
+	return &UpcasterRegistry{
+		upcasters: map[EventType][]Upcaster{},
+	}
+}
+
+// Register registers an upcaster transforming the data of the provided event
+// type from fromVersion to fromVersion + 1. fromVersion must be the current
+// event type data version.
+func (r *UpcasterRegistry) Register(eventType EventType, fromVersion int, u Upcaster) {
+	if cv := r.CurrentVersion(eventType); fromVersion != cv {
+		panic(fmt.Sprintf("cannot register upcaster for event type %q from version %d, the current version is %d", eventType, fromVersion, cv))
+	}
+	r.upcasters[eventType] = append(r.upcasters[eventType], u)
+}
+
+// CurrentVersion returns the current data version of the provided event type
+func (r *UpcasterRegistry) CurrentVersion(eventType EventType) int {
+	return len(r.upcasters[eventType]) + 1
+}
+
+// Upcast transforms the event data from the provided version to the current
+// version
+func (r *UpcasterRegistry) Upcast(eventType EventType, version int, data []byte) ([]byte, error) {
+	// events written before data versioning have version 1
+	if version == 0 {
+		version = 1
+	}
+	cv := r.CurrentVersion(eventType)
+	if version > cv {
+		return nil, errors.Errorf("event type %q data version %d is newer than the current version %d", eventType, version, cv)
+	}
+	for v := version; v < cv; v++ {
+		var err error
+		data, err = r.upcasters[eventType][v-1](data)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("failed to upcast event type %q data from version %d", eventType, v))
+		}
+	}
+	return data, nil
+}
+
+// UpcastData returns the stored event data transformed to the current event
+// data version
+func (r *UpcasterRegistry) UpcastData(e *eventstore.StoredEvent) ([]byte, error) {
+	var version int
+	// old or test events may not have metadata
+	if len(e.MetaData) > 0 {
+		md, err := UnmarshalMetaData(e)
+		if err != nil {
+			return nil, err
+		}
+		version = md.DataVersion
+	}
+	return r.Upcast(EventType(e.EventType), version, e.Data)
+}
+
+// UnmarshalData returns the stored event data, transformed to the current
+// event data version, unmarshalled in the event type struct
+func (r *UpcasterRegistry) UnmarshalData(e *eventstore.StoredEvent) (interface{}, error) {
+	data, err := r.UpcastData(e)
+	if err != nil {
+		return nil, err
+	}
+	d := GetEventDataType(EventType(e.EventType))
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return d, nil
+}
+
+// upcasters is the registry used to read the stored events
+var upcasters = NewUpcasterRegistry()
+
+// RegisterUpcaster registers an upcaster in the registry used to read the
+// stored events. It must be called at init time.
+func RegisterUpcaster(eventType EventType, fromVersion int, u Upcaster) {
+	upcasters.Register(eventType, fromVersion, u)
+}
+
+// EventDataVersion returns the current data version of the provided event
+// type
+func EventDataVersion(eventType EventType) int {
+	return upcasters.CurrentVersion(eventType)
+}
+
+// UpcastData returns the stored event data transformed to the current event
+// data version
+func UpcastData(e *eventstore.StoredEvent) ([]byte, error) {
+	return upcasters.UpcastData(e)
+}
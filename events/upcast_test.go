@@ -0,0 +1,220 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+)
+
+type fixtureEvent struct {
+	EventType string
+	MetaData  json.RawMessage
+	Data      json.RawMessage
+}
+
+func loadFixtures(t *testing.T, path string) []*eventstore.StoredEvent {
+	fixturesData, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var fixtures []*fixtureEvent
+	if err := json.Unmarshal(fixturesData, &fixtures); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events := []*eventstore.StoredEvent{}
+	for i, f := range fixtures {
+		events = append(events, &eventstore.StoredEvent{
+			SequenceNumber: int64(i + 1),
+			EventType:      f.EventType,
+			Data:           f.Data,
+			MetaData:       f.MetaData,
+		})
+	}
+	return events
+}
+
+func TestUnmarshalDataFixtures(t *testing.T) {
+	roleID := util.IDFromStringOrNil("66c0cc1f-f608-53dc-88b5-f3afd68a4d6c")
+	memberID := util.IDFromStringOrNil("1699e266-8401-558e-b9f5-7e2d7f965b82")
+	focus := "focus01"
+
+	// events written before data versioning
+	events := loadFixtures(t, "testdata/events_v1.json")
+	expected := []interface{}{
+		&EventRoleCreated{
+			RoleID:   roleID,
+			RoleType: models.RoleTypeCircle,
+			Name:     "General",
+		},
+		&EventMemberCreated{
+			IsAdmin:        true,
+			UserName:       "admin",
+			FullName:       "Admin",
+			Email:          "admin@example.com",
+			MemberChangeID: util.IDFromStringOrNil("d2b7b1a0-52c1-4a8e-a41b-2a3a3f2e5c66"),
+		},
+		&EventRoleMemberAdded{
+			RoleID:   roleID,
+			MemberID: memberID,
+			Focus:    &focus,
+		},
+		&EventTensionCreated{
+			Title:       "tension01",
+			Description: "description01",
+			MemberID:    memberID,
+			RoleID:      &roleID,
+		},
+		&EventTensionClosed{
+			Reason: "reason01",
+		},
+	}
+
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(events))
+	}
+	for i, e := range events {
+		data, err := UnmarshalData(e)
+		if err != nil {
+			t.Fatalf("event %d: unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(data, expected[i]) {
+			t.Fatalf("event %d: expected data %#v, got %#v", i, expected[i], data)
+		}
+	}
+}
+
+func TestUpcast(t *testing.T) {
+	r := NewUpcasterRegistry()
+
+	// simulate a RoleCreated event whose "RoleName" field has been renamed to
+	// "Name" in version 2 and whose empty purpose has been replaced by a
+	// default one in version 3
+	r.Register(EventTypeRoleCreated, 1, func(data []byte) ([]byte, error) {
+		var d map[string]interface{}
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, err
+		}
+		d["Name"] = d["RoleName"]
+		delete(d, "RoleName")
+		return json.Marshal(d)
+	})
+	r.Register(EventTypeRoleCreated, 2, func(data []byte) ([]byte, error) {
+		var d map[string]interface{}
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, err
+		}
+		if d["Purpose"] == "" {
+			d["Purpose"] = "no purpose"
+		}
+		return json.Marshal(d)
+	})
+
+	if v := r.CurrentVersion(EventTypeRoleCreated); v != 3 {
+		t.Fatalf("expected current version %d, got %d", 3, v)
+	}
+	if v := r.CurrentVersion(EventTypeRoleUpdated); v != 1 {
+		t.Fatalf("expected current version %d, got %d", 1, v)
+	}
+
+	roleID := util.IDFromStringOrNil("66c0cc1f-f608-53dc-88b5-f3afd68a4d6c")
+	metaData := func(version int) []byte {
+		md, err := json.Marshal(&eventstore.EventMetaData{DataVersion: version})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return md
+	}
+
+	tests := []struct {
+		metaData []byte
+		data     string
+		out      interface{}
+		err      error
+	}{
+		// event without metadata
+		{
+			data: `{"RoleID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c", "RoleType": "circle", "RoleName": "General", "Purpose": ""}`,
+			out:  &EventRoleCreated{RoleID: roleID, RoleType: models.RoleTypeCircle, Name: "General", Purpose: "no purpose"},
+		},
+		// event written before data versioning
+		{
+			metaData: metaData(0),
+			data:     `{"RoleID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c", "RoleType": "circle", "RoleName": "General", "Purpose": "purpose01"}`,
+			out:      &EventRoleCreated{RoleID: roleID, RoleType: models.RoleTypeCircle, Name: "General", Purpose: "purpose01"},
+		},
+		{
+			metaData: metaData(2),
+			data:     `{"RoleID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c", "RoleType": "circle", "Name": "General", "Purpose": ""}`,
+			out:      &EventRoleCreated{RoleID: roleID, RoleType: models.RoleTypeCircle, Name: "General", Purpose: "no purpose"},
+		},
+		{
+			metaData: metaData(3),
+			data:     `{"RoleID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c", "RoleType": "circle", "Name": "General", "Purpose": ""}`,
+			out:      &EventRoleCreated{RoleID: roleID, RoleType: models.RoleTypeCircle, Name: "General", Purpose: ""},
+		},
+		{
+			metaData: metaData(4),
+			data:     `{"RoleID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c", "RoleType": "circle", "Name": "General", "Purpose": ""}`,
+			err:      fmt.Errorf(`event type "RoleCreated" data version 4 is newer than the current version 3`),
+		},
+		{
+			data: `{"RoleID": "66c0cc1f-f608-53dc-88b5-f3afd68a4d6c", "RoleType": "circle", "RoleName": "General", "Purpose": ""`,
+			err:  fmt.Errorf(`failed to upcast event type "RoleCreated" data from version 1: unexpected end of JSON input`),
+		},
+	}
+
+	for i, tt := range tests {
+		e := &eventstore.StoredEvent{
+			EventType: string(EventTypeRoleCreated),
+			Data:      []byte(tt.data),
+			MetaData:  tt.metaData,
+		}
+		out, err := r.UnmarshalData(e)
+		if err != nil {
+			if tt.err == nil {
+				t.Fatalf("#%d: unexpected error: %v", i, err)
+			}
+			if err.Error() != tt.err.Error() {
+				t.Fatalf("#%d: expected error %q, got error %q", i, tt.err, err)
+			}
+			continue
+		}
+		if tt.err != nil {
+			t.Fatalf("#%d: expected error %q, got no error", i, tt.err)
+		}
+		if !reflect.DeepEqual(out, tt.out) {
+			t.Fatalf("#%d: expected data %#v, got %#v", i, tt.out, out)
+		}
+	}
+}
+
+func TestRegisterUpcasterWrongVersion(t *testing.T) {
+	r := NewUpcasterRegistry()
+	defer func() {
+		if p := recover(); p == nil {
+			t.Fatalf("expected panic registering upcaster from wrong version")
+		}
+	}()
+	r.Register(EventTypeRoleCreated, 2, func(data []byte) ([]byte, error) { return data, nil })
+}
+
+func TestGenEventDataVersion(t *testing.T) {
+	eventsData, err := GenEventData([]Event{&EventTensionClosed{Reason: "reason01"}}, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e := &eventstore.StoredEvent{EventType: eventsData[0].EventType, MetaData: eventsData[0].MetaData}
+	md, err := UnmarshalMetaData(e)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if md.DataVersion != EventDataVersion(EventTypeTensionClosed) {
+		t.Fatalf("expected data version %d, got %d", EventDataVersion(EventTypeTensionClosed), md.DataVersion)
+	}
+}
@@ -0,0 +1,241 @@
+package eventstore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/sorintlab/sircles/db"
+	"github.com/sorintlab/sircles/util"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+)
+
+// MaxCommandAuditFetchSize is the max number of command audit entries
+// returned by a single query
+const MaxCommandAuditFetchSize = 100
+
+var (
+	commandAuditSelect     = sb.Select("sequencenumber", "timestamp", "commandid", "commandtype", "correlationid", "causationid", "issuerid", "aggregatetype", "aggregateid", "groupid", "eventscount", "result", "error", "remoteaddr", "useragent").From("commandaudit")
+	commandAuditInsert     = sb.Insert("commandaudit").Columns("timestamp", "commandid", "commandtype", "correlationid", "causationid", "issuerid", "aggregatetype", "aggregateid", "groupid", "eventscount", "result", "error", "remoteaddr", "useragent")
+	commandAuditCopyInsert = sb.Insert("commandaudit").Columns("sequencenumber", "timestamp", "commandid", "commandtype", "correlationid", "causationid", "issuerid", "aggregatetype", "aggregateid", "groupid", "eventscount", "result", "error", "remoteaddr", "useragent")
+)
+
+type CommandAuditResult string
+
+const (
+	// CommandAuditResultExecuted is a command executed with its events
+	// written to the eventstore
+	CommandAuditResultExecuted CommandAuditResult = "executed"
+	// CommandAuditResultRejected is a command rejected by its validation
+	CommandAuditResultRejected CommandAuditResult = "rejected"
+	// CommandAuditResultFailed is a command failed for an internal error
+	CommandAuditResultFailed CommandAuditResult = "failed"
+)
+
+// CommandAuditEntry is an entry of the command audit log.
+// The command related fields are empty when a request was rejected before
+// creating a command (i.e. by the command service input validation).
+type CommandAuditEntry struct {
+	SequenceNumber int64
+	Timestamp      time.Time
+	CommandID      *util.ID
+	CommandType    string
+	CorrelationID  *util.ID
+	CausationID    *util.ID
+	IssuerID       *util.ID
+	AggregateType  string
+	AggregateID    string
+	GroupID        *util.ID
+	EventsCount    int
+	Result         CommandAuditResult
+	Error          string
+	RemoteAddr     string
+	UserAgent      string
+}
+
+// CommandAuditFilter filters the command audit entries. Empty fields are
+// ignored.
+type CommandAuditFilter struct {
+	IssuerID    *util.ID
+	CommandType string
+	// FromTime and ToTime define the entries time range [FromTime, ToTime)
+	FromTime *time.Time
+	ToTime   *time.Time
+	// Before returns only the entries with a sequence number lower than the
+	// provided one
+	Before int64
+}
+
+// WriteCommandAudit appends the provided entry to the command audit log. The
+// entry sequence number and timestamp are set by the eventstore.
+// The timestamp is the wall clock time and not the one provided by the
+// eventstore time generator, used to generate the events timestamps, to not
+// alter the events timestamps.
+func (s *SQLEventStore) WriteCommandAudit(entry *CommandAuditEntry) error {
+	return s.db.Do(func(tx *db.Tx) error {
+		return s.writeCommandAudit(tx, entry)
+	})
+}
+
+func (s *SQLEventStore) writeCommandAudit(tx *db.Tx, entry *CommandAuditEntry) error {
+	entry.Timestamp = time.Now().UTC()
+
+	q, args, err := commandAuditInsert.Values(entry.Timestamp, entry.CommandID, entry.CommandType, entry.CorrelationID, entry.CausationID, entry.IssuerID, entry.AggregateType, entry.AggregateID, entry.GroupID, entry.EventsCount, entry.Result, entry.Error, entry.RemoteAddr, entry.UserAgent).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+	return tx.Do(func(tx *db.WrappedTx) error {
+		if _, err := tx.Exec(q, args...); err != nil {
+			return errors.WithMessage(err, "failed to insert command audit entry")
+		}
+		return nil
+	})
+}
+
+// CommandAudit returns, newest first, at most limit command audit entries
+// matching the provided filter and if there're more entries
+func (s *SQLEventStore) CommandAudit(filter *CommandAuditFilter, limit int) ([]*CommandAuditEntry, bool, error) {
+	if limit <= 0 || limit > MaxCommandAuditFetchSize {
+		limit = MaxCommandAuditFetchSize
+	}
+
+	sb := commandAuditSelect.OrderBy("sequencenumber DESC")
+	if filter != nil {
+		if filter.IssuerID != nil {
+			sb = sb.Where(sq.Eq{"issuerid": filter.IssuerID})
+		}
+		if filter.CommandType != "" {
+			sb = sb.Where(sq.Eq{"commandtype": filter.CommandType})
+		}
+		if filter.FromTime != nil {
+			sb = sb.Where(sq.GtOrEq{"timestamp": filter.FromTime.UTC()})
+		}
+		if filter.ToTime != nil {
+			sb = sb.Where(sq.Lt{"timestamp": filter.ToTime.UTC()})
+		}
+		if filter.Before > 0 {
+			sb = sb.Where(sq.Lt{"sequencenumber": filter.Before})
+		}
+	}
+	// ask for limit + 1 entries to know if there're more entries
+	sb = sb.Limit(uint64(limit + 1))
+
+	q, args, err := sb.ToSql()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to build query")
+	}
+
+	var entries []*CommandAuditEntry
+	err = s.db.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			rows, err := tx.Query(q, args...)
+			if err != nil {
+				return errors.WithMessage(err, "failed to execute query")
+			}
+			entries, err = scanCommandAuditEntries(rows)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(entries) > limit {
+		return entries[:limit], true, nil
+	}
+	return entries, false, nil
+}
+
+// GetCommandAuditEntries returns, oldest first, count command audit entries
+// with a sequence number greater than the provided one
+func (s *SQLEventStore) GetCommandAuditEntries(afterSequenceNumber int64, count uint64) ([]*CommandAuditEntry, error) {
+	if count < 1 {
+		return []*CommandAuditEntry{}, nil
+	}
+
+	q, args, err := commandAuditSelect.Where(sq.Gt{"sequencenumber": afterSequenceNumber}).OrderBy("sequencenumber ASC").Limit(count).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+
+	var entries []*CommandAuditEntry
+	err = s.db.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			rows, err := tx.Query(q, args...)
+			if err != nil {
+				return errors.WithMessage(err, "failed to execute query")
+			}
+			entries, err = scanCommandAuditEntries(rows)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// CopyCommandAuditEntries inserts the provided command audit entries keeping
+// their sequence numbers and timestamps
+func (s *SQLEventStore) CopyCommandAuditEntries(entries []*CommandAuditEntry) error {
+	return s.db.Do(func(tx *db.Tx) error {
+		for _, entry := range entries {
+			q, args, err := commandAuditCopyInsert.Values(entry.SequenceNumber, entry.Timestamp, entry.CommandID, entry.CommandType, entry.CorrelationID, entry.CausationID, entry.IssuerID, entry.AggregateType, entry.AggregateID, entry.GroupID, entry.EventsCount, entry.Result, entry.Error, entry.RemoteAddr, entry.UserAgent).ToSql()
+			if err != nil {
+				return errors.Wrap(err, "failed to build query")
+			}
+			err = tx.Do(func(tx *db.WrappedTx) error {
+				_, err := tx.Exec(q, args...)
+				return err
+			})
+			if err != nil {
+				return errors.WithMessage(err, "failed to insert command audit entry")
+			}
+		}
+
+		// sqlite3 autoincrement is automatically updated when inserting an
+		// explicit value, postgres serial sequence needs to be updated
+		if s.db.Type() == db.Postgres {
+			err := tx.Do(func(tx *db.WrappedTx) error {
+				_, err := tx.Exec("select setval(pg_get_serial_sequence('commandaudit', 'sequencenumber'), (select max(sequencenumber) from commandaudit))")
+				return err
+			})
+			if err != nil {
+				return errors.WithMessage(err, "failed to update command audit sequence")
+			}
+		}
+		return nil
+	})
+}
+
+func scanCommandAuditEntry(rows *sql.Rows) (*CommandAuditEntry, error) {
+	e := CommandAuditEntry{}
+	var aggregateType, aggregateID, auditError, remoteAddr, userAgent sql.NullString
+	fields := []interface{}{&e.SequenceNumber, &e.Timestamp, &e.CommandID, &e.CommandType, &e.CorrelationID, &e.CausationID, &e.IssuerID, &aggregateType, &aggregateID, &e.GroupID, &e.EventsCount, &e.Result, &auditError, &remoteAddr, &userAgent}
+	if err := rows.Scan(fields...); err != nil {
+		return nil, errors.Wrap(err, "error scanning command audit entry")
+	}
+	e.AggregateType = aggregateType.String
+	e.AggregateID = aggregateID.String
+	e.Error = auditError.String
+	e.RemoteAddr = remoteAddr.String
+	e.UserAgent = userAgent.String
+	return &e, nil
+}
+
+func scanCommandAuditEntries(rows *sql.Rows) ([]*CommandAuditEntry, error) {
+	entries := []*CommandAuditEntry{}
+	for rows.Next() {
+		e, err := scanCommandAuditEntry(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return entries, nil
+}
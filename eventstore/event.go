@@ -35,6 +35,16 @@ type EventMetaData struct {
 	CausationID     *util.ID // event ID causing this event
 	GroupID         *util.ID // event group ID
 	CommandIssuerID *util.ID // issuer of the command generating this event
+	// CommandCausationID is the causation ID of the command generating this
+	// event. When the command is issued by a saga or an event handler it's
+	// the ID of the event that triggered the command.
+	CommandCausationID *util.ID `json:",omitempty"`
+	// DataVersion is the version of the event data format. Events written
+	// before data versioning don't have it and are at version 1.
+	DataVersion int `json:",omitempty"`
+	// PersonalDataKeyID is the ID of the key used to encrypt the event data
+	// personal data fields. Events without it have plaintext data.
+	PersonalDataKeyID *util.ID `json:",omitempty"`
 }
 
 type EventData struct {
@@ -49,3 +59,12 @@ type StreamVersion struct {
 	StreamID string
 	Version  int64 // Stream Version. Increased for every event saved in the stream.
 }
+
+// Snapshot is the serialized state of the aggregate owning the stream at the
+// provided stream version
+type Snapshot struct {
+	Category string
+	StreamID string
+	Version  int64 // Stream Version of the last event applied to the snapshotted aggregate.
+	Data     []byte
+}
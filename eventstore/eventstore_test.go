@@ -1,17 +1,72 @@
 package eventstore
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/sorintlab/sircles/db"
 	ln "github.com/sorintlab/sircles/listennotify"
+	"github.com/sorintlab/sircles/util"
 )
 
+type newTestEventStoreFunc func(t *testing.T, name string) EventStore
+
+// runEventStoreTests runs the provided test with all the eventstore types
+func runEventStoreTests(t *testing.T, test func(t *testing.T, newES newTestEventStoreFunc)) {
+	for _, typ := range []string{"sql", "memory", "file"} {
+		typ := typ
+		t.Run(typ, func(t *testing.T) {
+			tmpDir, err := ioutil.TempDir("", "")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir(%q, %q) got error %q", "", "", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			test(t, func(t *testing.T, name string) EventStore {
+				return newTestEventStore(t, typ, filepath.Join(tmpDir, name))
+			})
+		})
+	}
+}
+
+func newTestEventStore(t *testing.T, typ, path string) EventStore {
+	localln := ln.NewLocalListenNotify()
+	nf := ln.NewLocalNotifierFactory(localln)
+
+	switch typ {
+	case "sql":
+		db, err := db.NewDB("sqlite3", path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := db.Migrate("eventstore", Migrations); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return NewSQLEventStore(db, nf)
+	case "memory":
+		return NewMemoryEventStore(nf)
+	case "file":
+		es, err := NewFileEventStore(path, nf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return es
+	}
+	t.Fatalf("unknown eventstore type %q", typ)
+	return nil
+}
+
 func TestWriteEvents(t *testing.T) {
+	runEventStoreTests(t, testWriteEvents)
+}
+
+func testWriteEvents(t *testing.T, newES newTestEventStoreFunc) {
 	events := []*EventData{
 		&EventData{
 			EventType: "eventtype01",
@@ -33,25 +88,7 @@ func TestWriteEvents(t *testing.T) {
 		6,
 	}
 
-	tmpDir, err := ioutil.TempDir("", "")
-	if err != nil {
-		t.Fatalf("ioutil.TempDir(%q, %q) got error %q", "", "", err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	dbpath := filepath.Join(tmpDir, "db")
-
-	db, err := db.NewDB("sqlite3", dbpath)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if err := db.Migrate("eventstore", Migrations); err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	localln := ln.NewLocalListenNotify()
-	nf := ln.NewLocalNotifierFactory(localln)
-	es := NewEventStore(db, nf)
+	es := newES(t, "es")
 
 	if _, err := es.WriteEvents(events, "category01", "b1399c23-5b50-4c72-b803-804efaba0cb1", 0); err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -101,6 +138,10 @@ func TestWriteEvents(t *testing.T) {
 }
 
 func TestRestoreEvents(t *testing.T) {
+	runEventStoreTests(t, testRestoreEvents)
+}
+
+func testRestoreEvents(t *testing.T, newES newTestEventStoreFunc) {
 	events1 := []*EventData{
 		&EventData{
 			EventType: "eventtype01",
@@ -134,64 +175,474 @@ func TestRestoreEvents(t *testing.T) {
 		3,
 	}
 
-	tmpDir, err := ioutil.TempDir("", "")
+	es := newES(t, "es01")
+
+	if _, err := es.WriteEvents(events1, "category01", "b1399c23-5b50-4c72-b803-804efaba0cb1", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := es.WriteEvents(events2, "category01", "65c4dce5-2935-46eb-a71e-3ea1cb4b970c", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	writtenEvents, err := es.GetAllEvents(0, 1000)
 	if err != nil {
-		t.Fatalf("ioutil.TempDir(%q, %q) got error %q", "", "", err)
+		t.Fatalf("unexpected error: %v", err)
 	}
-	defer os.RemoveAll(tmpDir)
 
-	dbpath := filepath.Join(tmpDir, "db")
+	es = newES(t, "es02")
 
-	db1, err := db.NewDB("sqlite3", dbpath)
+	if err := es.RestoreEvents(writtenEvents); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	restoredEvents, err := es.GetAllEvents(0, 1000)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if err := db1.Migrate("eventstore", Migrations); err != nil {
+
+	for i, expectedVersion := range expectedVersions {
+		if restoredEvents[i].Version != expectedVersion {
+			t.Fatalf("expected event version %d, got %d for event seq %d", expectedVersion, restoredEvents[i].Version, restoredEvents[i].SequenceNumber)
+		}
+	}
+}
+
+func TestCopyEvents(t *testing.T) {
+	runEventStoreTests(t, testCopyEvents)
+}
+
+func testCopyEvents(t *testing.T, newES newTestEventStoreFunc) {
+	events := []*EventData{
+		&EventData{
+			EventType: "eventtype01",
+		},
+		&EventData{
+			EventType: "eventtype01",
+		},
+		&EventData{
+			EventType: "eventtype01",
+		},
+	}
+
+	es1 := newES(t, "es01")
+	es2 := newES(t, "es02")
+
+	if _, err := es1.WriteEvents(events, "category01", "b1399c23-5b50-4c72-b803-804efaba0cb1", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := es1.WriteEvents(events, "category01", "65c4dce5-2935-46eb-a71e-3ea1cb4b970c", 0); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	localln := ln.NewLocalListenNotify()
-	nf := ln.NewLocalNotifierFactory(localln)
-	es := NewEventStore(db1, nf)
+	// copy only the events after the first one to check that sequence numbers are preserved
+	writtenEvents, err := es1.GetAllEvents(2, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := es2.CopyEvents(writtenEvents); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svs, err := es1.GetStreamVersions("", 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := es2.CopyStreamVersions(svs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	if _, err := es.WriteEvents(events1, "category01", "b1399c23-5b50-4c72-b803-804efaba0cb1", 0); err != nil {
+	copiedEvents, err := es2.GetAllEvents(0, 1000)
+	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if _, err := es.WriteEvents(events2, "category01", "65c4dce5-2935-46eb-a71e-3ea1cb4b970c", 0); err != nil {
+	if !reflect.DeepEqual(copiedEvents, writtenEvents) {
+		t.Fatalf("expected copied events equal to written events, got %v, want %v", copiedEvents, writtenEvents)
+	}
+	copiedSvs, err := es2.GetStreamVersions("", 1000)
+	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if !reflect.DeepEqual(copiedSvs, svs) {
+		t.Fatalf("expected copied stream versions equal to source stream versions, got %v, want %v", copiedSvs, svs)
+	}
 
-	writtenEvents, err := es.GetAllEvents(0, 1000)
+	// new events must continue the copied sequence and stream versions
+	if _, err := es2.WriteEvents(events, "category01", "b1399c23-5b50-4c72-b803-804efaba0cb1", 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lastSequenceNumber, err := es2.LastSequenceNumber()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lastSequenceNumber != 9 {
+		t.Fatalf("expected last sequence number %d, got %d", 9, lastSequenceNumber)
+	}
+	count, err := es2.EventsCount()
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if count != 8 {
+		t.Fatalf("expected %d events, got %d", 8, count)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	runEventStoreTests(t, testSnapshot)
+}
+
+func testSnapshot(t *testing.T, newES newTestEventStoreFunc) {
+	es := newES(t, "es")
 
-	dbpath = filepath.Join(tmpDir, "db2.ql")
+	streamID := "b1399c23-5b50-4c72-b803-804efaba0cb1"
 
-	db2, err := db.NewDB("sqlite3", dbpath)
+	snapshot, err := es.GetSnapshot(streamID)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if err := db2.Migrate("eventstore", Migrations); err != nil {
+	if snapshot != nil {
+		t.Fatalf("expected nil snapshot, got %v", snapshot)
+	}
+
+	snapshot2 := &Snapshot{Category: "category01", StreamID: streamID, Version: 2, Data: []byte("data02")}
+	snapshot5 := &Snapshot{Category: "category01", StreamID: streamID, Version: 5, Data: []byte("data05")}
+
+	for _, sn := range []*Snapshot{snapshot2, snapshot5} {
+		if err := es.WriteSnapshot(sn); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		snapshot, err = es.GetSnapshot(streamID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(snapshot, sn) {
+			t.Fatalf("expected snapshot %v, got %v", sn, snapshot)
+		}
+	}
+
+	// an older snapshot shouldn't replace the current one
+	if err := es.WriteSnapshot(snapshot2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	snapshot, err = es.GetSnapshot(streamID)
+	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if !reflect.DeepEqual(snapshot, snapshot5) {
+		t.Fatalf("expected snapshot %v, got %v", snapshot5, snapshot)
+	}
 
-	localln = ln.NewLocalListenNotify()
-	nf = ln.NewLocalNotifierFactory(localln)
-	es = NewEventStore(db2, nf)
+	if err := es.DeleteSnapshot(streamID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	snapshot, err = es.GetSnapshot(streamID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot != nil {
+		t.Fatalf("expected nil snapshot, got %v", snapshot)
+	}
+}
 
-	if err := es.RestoreEvents(writtenEvents); err != nil {
+// testCodec reverses the events data
+type testCodec struct{}
+
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i, c := range b {
+		r[len(b)-1-i] = c
+	}
+	return r
+}
+
+func (c *testCodec) Encode(e *StoredEvent) error {
+	e.Data = reverse(e.Data)
+	return nil
+}
+
+func (c *testCodec) Decode(e *StoredEvent) error {
+	e.Data = reverse(e.Data)
+	return nil
+}
+
+func TestDataCodec(t *testing.T) {
+	runEventStoreTests(t, testDataCodec)
+}
+
+func testDataCodec(t *testing.T, newES newTestEventStoreFunc) {
+	es := newES(t, "es")
+	es.SetDataCodec(&testCodec{})
+
+	streamID := "b1399c23-5b50-4c72-b803-804efaba0cb1"
+	events := []*EventData{
+		&EventData{
+			EventType: "eventtype01",
+			Data:      []byte("data01"),
+		},
+	}
+
+	writtenEvents, err := es.WriteEvents(events, "category01", streamID, 0)
+	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if string(writtenEvents[0].Data) != "data01" {
+		t.Fatalf("expected written event data %q, got %q", "data01", writtenEvents[0].Data)
+	}
 
-	restoredEvents, err := es.GetAllEvents(0, 1000)
+	readEvents, err := es.GetEvents(streamID, 1, 1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if string(readEvents[0].Data) != "data01" {
+		t.Fatalf("expected read event data %q, got %q", "data01", readEvents[0].Data)
+	}
 
-	for i, expectedVersion := range expectedVersions {
-		if restoredEvents[i].Version != expectedVersion {
-			t.Fatalf("expected event version %d, got %d for event seq %d", expectedVersion, restoredEvents[i].Version, restoredEvents[i].SequenceNumber)
+	// read the stored data without the codec
+	es.SetDataCodec(nil)
+	readEvents, err = es.GetEvents(streamID, 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(readEvents[0].Data) != "10atad" {
+		t.Fatalf("expected stored event data %q, got %q", "10atad", readEvents[0].Data)
+	}
+}
+
+func TestPersonalDataKey(t *testing.T) {
+	runEventStoreTests(t, testPersonalDataKey)
+}
+
+func testPersonalDataKey(t *testing.T, newES newTestEventStoreFunc) {
+	es := newES(t, "es")
+
+	id := util.IDFromStringOrNil("b1399c23-5b50-4c72-b803-804efaba0cb1")
+
+	if _, err := es.PersonalDataKey(id, false); err == nil {
+		t.Fatalf("expected error getting a not existing key")
+	}
+
+	key, err := es.PersonalDataKey(id, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(key) != PersonalDataKeySize {
+		t.Fatalf("expected key size %d, got %d", PersonalDataKeySize, len(key))
+	}
+	key2, err := es.PersonalDataKey(id, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(key, key2) {
+		t.Fatalf("expected the same key")
+	}
+
+	// a destroyed key is never recreated
+	for i := 0; i < 2; i++ {
+		if err := es.DestroyPersonalDataKey(id); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		key, err = es.PersonalDataKey(id, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if key != nil {
+			t.Fatalf("expected nil destroyed key, got %v", key)
+		}
+	}
+}
+
+func TestCommandAudit(t *testing.T) {
+	runEventStoreTests(t, testCommandAudit)
+}
+
+func testCommandAudit(t *testing.T, newES newTestEventStoreFunc) {
+	es := newES(t, "es")
+
+	member01 := util.IDFromStringOrNil("b1399c23-5b50-4c72-b803-804efaba0cb1")
+	member02 := util.IDFromStringOrNil("2ba1e21b-f0c6-4ab4-a4b8-f84bd4a4ef5a")
+	commandID := util.IDFromStringOrNil("0d9c3f33-a6e5-4eb2-8c9a-7d6c1f3f0b1a")
+
+	start := time.Now()
+	entries := []*CommandAuditEntry{
+		{CommandID: &commandID, CommandType: "CreateTension", IssuerID: &member01, AggregateType: "tension", AggregateID: "tension01", EventsCount: 1, Result: CommandAuditResultExecuted},
+		{CommandType: "CreateTension", IssuerID: &member02, Result: CommandAuditResultRejected, Error: "empty tension title", RemoteAddr: "127.0.0.1:1234", UserAgent: "agent"},
+		{CommandType: "CloseTension", IssuerID: &member01, Result: CommandAuditResultFailed, Error: "failure"},
+		{CommandType: "CircleExpireCoreRoleElection", Result: CommandAuditResultExecuted},
+	}
+	for _, e := range entries {
+		if err := es.WriteCommandAudit(e); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	end := time.Now().Add(1 * time.Second)
+
+	types := func(entries []*CommandAuditEntry) []string {
+		types := []string{}
+		for _, e := range entries {
+			types = append(types, e.CommandType+":"+string(e.Result))
+		}
+		return types
+	}
+
+	tests := []struct {
+		filter       *CommandAuditFilter
+		limit        int
+		expected     []string
+		expectedMore bool
+	}{
+		{
+			expected: []string{"CircleExpireCoreRoleElection:executed", "CloseTension:failed", "CreateTension:rejected", "CreateTension:executed"},
+		},
+		{
+			filter:   &CommandAuditFilter{IssuerID: &member01},
+			expected: []string{"CloseTension:failed", "CreateTension:executed"},
+		},
+		{
+			filter:   &CommandAuditFilter{CommandType: "CreateTension"},
+			expected: []string{"CreateTension:rejected", "CreateTension:executed"},
+		},
+		{
+			filter:   &CommandAuditFilter{FromTime: &start, ToTime: &end},
+			expected: []string{"CircleExpireCoreRoleElection:executed", "CloseTension:failed", "CreateTension:rejected", "CreateTension:executed"},
+		},
+		{
+			filter:   &CommandAuditFilter{FromTime: &end},
+			expected: []string{},
+		},
+		{
+			filter:   &CommandAuditFilter{ToTime: &start},
+			expected: []string{},
+		},
+		{
+			limit:        2,
+			expected:     []string{"CircleExpireCoreRoleElection:executed", "CloseTension:failed"},
+			expectedMore: true,
+		},
+		{
+			filter:   &CommandAuditFilter{Before: 3},
+			limit:    2,
+			expected: []string{"CreateTension:rejected", "CreateTension:executed"},
+		},
+	}
+
+	for i, tt := range tests {
+		entries, more, err := es.CommandAudit(tt.filter, tt.limit)
+		if err != nil {
+			t.Fatalf("#%d: unexpected error: %v", i, err)
+		}
+		if out := types(entries); !reflect.DeepEqual(out, tt.expected) {
+			t.Fatalf("#%d: got entries %v, want %v", i, out, tt.expected)
+		}
+		if more != tt.expectedMore {
+			t.Fatalf("#%d: got more entries %t, want %t", i, more, tt.expectedMore)
+		}
+	}
+
+	entries, _, err := es.CommandAudit(&CommandAuditFilter{IssuerID: &member02}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e := entries[0]
+	if e.CommandID != nil || e.AggregateID != "" || e.RemoteAddr != "127.0.0.1:1234" || e.UserAgent != "agent" || e.Error != "empty tension title" {
+		t.Fatalf("unexpected entry: %#v", e)
+	}
+	entries, _, err = es.CommandAudit(&CommandAuditFilter{IssuerID: &member01, CommandType: "CreateTension"}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e = entries[0]
+	if e.CommandID == nil || *e.CommandID != commandID || e.AggregateType != "tension" || e.AggregateID != "tension01" || e.EventsCount != 1 {
+		t.Fatalf("unexpected entry: %#v", e)
+	}
+}
+
+func TestCopyStoreData(t *testing.T) {
+	runEventStoreTests(t, testCopyStoreData)
+}
+
+// testCopyStoreData tests the listing and copying of the snapshots, personal
+// data keys and command audit entries
+func testCopyStoreData(t *testing.T, newES newTestEventStoreFunc) {
+	es := newES(t, "es01")
+
+	for _, streamID := range []string{"stream03", "stream01", "stream02"} {
+		if err := es.WriteSnapshot(&Snapshot{Category: "category01", StreamID: streamID, Version: 1, Data: []byte(streamID)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
 		}
 	}
+	snapshots, err := es.GetSnapshots("stream01", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].StreamID != "stream02" || snapshots[1].StreamID != "stream03" {
+		t.Fatalf("unexpected snapshots: %v", snapshots)
+	}
+
+	id01 := util.IDFromStringOrNil("b1399c23-5b50-4c72-b803-804efaba0cb1")
+	id02 := util.IDFromStringOrNil("2ba1e21b-f0c6-4ab4-a4b8-f84bd4a4ef5a")
+	key01, err := es.PersonalDataKey(id01, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := es.DestroyPersonalDataKey(id02); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, commandType := range []string{"command01", "command02", "command03"} {
+		if err := es.WriteCommandAudit(&CommandAuditEntry{CommandType: commandType, Result: CommandAuditResultExecuted}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	entries, err := es.GetCommandAuditEntries(1, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].CommandType != "command02" || entries[1].CommandType != "command03" {
+		t.Fatalf("unexpected command audit entries: %v", entries)
+	}
+
+	keys, err := es.PersonalDataKeys()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	es2 := newES(t, "es02")
+	if err := es2.CopyPersonalDataKeys(keys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	copiedKey01, err := es2.PersonalDataKey(id01, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(key01, copiedKey01) {
+		t.Fatalf("expected the same key")
+	}
+	// the destroyed key isn't recreated
+	copiedKey02, err := es2.PersonalDataKey(id02, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if copiedKey02 != nil {
+		t.Fatalf("expected nil destroyed key, got %v", copiedKey02)
+	}
+
+	// copy only the last entries
+	if err := es2.CopyCommandAuditEntries(entries); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := es2.WriteCommandAudit(&CommandAuditEntry{CommandType: "command04", Result: CommandAuditResultExecuted}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	copiedEntries, err := es2.GetCommandAuditEntries(0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sequenceNumbers := []int64{}
+	for _, e := range copiedEntries {
+		sequenceNumbers = append(sequenceNumbers, e.SequenceNumber)
+	}
+	if !reflect.DeepEqual(sequenceNumbers, []int64{2, 3, 4}) {
+		t.Fatalf("expected command audit entries sequence numbers %v, got %v", []int64{2, 3, 4}, sequenceNumbers)
+	}
+	if !copiedEntries[0].Timestamp.Equal(entries[0].Timestamp) {
+		t.Fatalf("expected timestamp %v, got %v", entries[0].Timestamp, copiedEntries[0].Timestamp)
+	}
 }
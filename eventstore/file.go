@@ -0,0 +1,218 @@
+package eventstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+
+	ln "github.com/sorintlab/sircles/listennotify"
+	"github.com/sorintlab/sircles/util"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	fileEventStoreLogName  = "events.log"
+	fileEventStoreKeysName = "personaldatakeys.json"
+	fileEventStoreLockName = "lock"
+)
+
+// logRecord is a record of the file eventstore log. Every record contains the
+// changes done by a single eventstore write.
+type logRecord struct {
+	Events            []*StoredEvent     `json:",omitempty"`
+	StreamVersions    []*StreamVersion   `json:",omitempty"`
+	CommandAuditEntry *CommandAuditEntry `json:",omitempty"`
+}
+
+type personalDataKeyRecord struct {
+	ID util.ID
+	// Key is nil for a destroyed key
+	Key []byte
+}
+
+// FileEventStore is an eventstore for small single node installations. It
+// keeps all its data in memory (like the MemoryEventStore) and appends every
+// change to a log file. When opened the log file is replayed to restore the
+// eventstore state.
+//
+// The personal data keys aren't saved in the log but in a file rewritten at
+// every change, so a destroyed key is really removed from the disk.
+// The aggregates snapshots aren't saved.
+//
+// The eventstore directory is locked so it can be used by only one process at
+// a time.
+type FileEventStore struct {
+	*MemoryEventStore
+
+	dir      string
+	lockFile *os.File
+	logFile  *os.File
+	// logSize is the size of the log file after the last fully written
+	// record
+	logSize int64
+}
+
+// NewFileEventStore opens the file eventstore saved in the provided
+// directory, creating it if it doesn't exist
+func NewFileEventStore(dir string, nf ln.NotifierFactory) (*FileEventStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create eventstore dir %q", dir)
+	}
+
+	lockFile, err := os.OpenFile(filepath.Join(dir, fileEventStoreLockName), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open eventstore lock file")
+	}
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		lockFile.Close()
+		return nil, errors.Wrapf(err, "failed to lock eventstore dir %q, is it used by another process?", dir)
+	}
+
+	s := &FileEventStore{
+		MemoryEventStore: NewMemoryEventStore(nf),
+		dir:              dir,
+		lockFile:         lockFile,
+	}
+	if err := s.load(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.journal = s
+
+	return s, nil
+}
+
+// Close closes the eventstore files and releases the eventstore directory
+// lock
+func (s *FileEventStore) Close() error {
+	var err error
+	if s.logFile != nil {
+		err = s.logFile.Close()
+	}
+	// closing the lock file also releases the lock
+	if lerr := s.lockFile.Close(); lerr != nil && err == nil {
+		err = lerr
+	}
+	return errors.WithStack(err)
+}
+
+func (s *FileEventStore) load() error {
+	keysData, err := ioutil.ReadFile(filepath.Join(s.dir, fileEventStoreKeysName))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to read personal data keys")
+	}
+	if err == nil {
+		var keys []*personalDataKeyRecord
+		if err := json.Unmarshal(keysData, &keys); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal personal data keys")
+		}
+		for _, k := range keys {
+			s.personalDataKeys[k.ID] = k.Key
+		}
+	}
+
+	s.logFile, err = os.OpenFile(filepath.Join(s.dir, fileEventStoreLogName), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open eventstore log")
+	}
+
+	br := bufio.NewReader(s.logFile)
+	for {
+		line, err := br.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				// a record not fully written (i.e. the process was
+				// killed while writing it), remove it
+				log.Warnf("removing incomplete record at the end of eventstore log")
+				if err := s.logFile.Truncate(s.logSize); err != nil {
+					return errors.Wrapf(err, "failed to truncate eventstore log")
+				}
+			}
+			break
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read eventstore log")
+		}
+
+		var r logRecord
+		if err := json.Unmarshal(line, &r); err != nil {
+			return errors.Wrapf(err, "corrupted eventstore log record at offset %d", s.logSize)
+		}
+		s.applyRecord(&r)
+		s.logSize += int64(len(line))
+	}
+
+	return nil
+}
+
+func (s *FileEventStore) writeRecord(r *logRecord) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	data = append(data, '\n')
+
+	if _, err := s.logFile.Write(data); err != nil {
+		// remove the partially written record
+		if terr := s.logFile.Truncate(s.logSize); terr != nil {
+			log.Errorf("failed to truncate eventstore log: %+v", terr)
+		}
+		return errors.Wrapf(err, "failed to write eventstore log")
+	}
+	if err := s.logFile.Sync(); err != nil {
+		return errors.Wrapf(err, "failed to sync eventstore log")
+	}
+	s.logSize += int64(len(data))
+
+	return nil
+}
+
+func (s *FileEventStore) writePersonalDataKeys(keys map[util.ID][]byte) error {
+	records := make([]*personalDataKeyRecord, 0, len(keys))
+	for id, key := range keys {
+		records = append(records, &personalDataKeyRecord{ID: id, Key: key})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID.String() < records[j].ID.String() })
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// atomically replace the keys file
+	f, err := ioutil.TempFile(s.dir, fileEventStoreKeysName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create personal data keys file")
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "failed to write personal data keys file")
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "failed to sync personal data keys file")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrapf(err, "failed to close personal data keys file")
+	}
+	if err := os.Rename(f.Name(), filepath.Join(s.dir, fileEventStoreKeysName)); err != nil {
+		return errors.Wrapf(err, "failed to rename personal data keys file")
+	}
+	return syncDir(s.dir)
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer d.Close()
+	return errors.WithStack(d.Sync())
+}
@@ -0,0 +1,155 @@
+package eventstore
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	ln "github.com/sorintlab/sircles/listennotify"
+	"github.com/sorintlab/sircles/util"
+)
+
+func TestFileEventStoreReopen(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir(%q, %q) got error %q", "", "", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	nf := ln.NewLocalNotifierFactory(ln.NewLocalListenNotify())
+
+	es, err := NewFileEventStore(tmpDir, nf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the eventstore dir can be used by only one process at a time
+	if _, err := NewFileEventStore(tmpDir, nf); err == nil {
+		t.Fatalf("expected error opening an already opened eventstore")
+	}
+
+	streamID := "b1399c23-5b50-4c72-b803-804efaba0cb1"
+	events := []*EventData{
+		&EventData{
+			EventType: "eventtype01",
+			Data:      []byte("data01"),
+		},
+		&EventData{
+			EventType: "eventtype01",
+			Data:      []byte("data02"),
+		},
+	}
+	if _, err := es.WriteEvents(events, "category01", streamID, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := es.WriteCommandAudit(&CommandAuditEntry{CommandType: "CreateTension", Result: CommandAuditResultExecuted}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id01 := util.IDFromStringOrNil("2ba1e21b-f0c6-4ab4-a4b8-f84bd4a4ef5a")
+	id02 := util.IDFromStringOrNil("0d9c3f33-a6e5-4eb2-8c9a-7d6c1f3f0b1a")
+	key01, err := es.PersonalDataKey(id01, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	key02, err := es.PersonalDataKey(id02, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := es.DestroyPersonalDataKey(id02); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	writtenEvents, err := es.GetAllEvents(0, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := es.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a destroyed key must be removed from the disk
+	keysData, err := ioutil.ReadFile(filepath.Join(tmpDir, fileEventStoreKeysName))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains(keysData, []byte(`"`+id02.String()+`","Key":null`)) {
+		t.Fatalf("expected destroyed key %s, got keys: %s", id02, keysData)
+	}
+
+	// simulate a record not fully written
+	f, err := os.OpenFile(filepath.Join(tmpDir, fileEventStoreLogName), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := f.Write([]byte(`{"Events":[{"ID":`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.Close()
+
+	es, err = NewFileEventStore(tmpDir, nf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer es.Close()
+
+	readEvents, err := es.GetAllEvents(0, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(readEvents) != len(writtenEvents) {
+		t.Fatalf("expected %d events, got %d", len(writtenEvents), len(readEvents))
+	}
+	for i, e := range readEvents {
+		// compare the timestamps separately since the monotonic clock
+		// reading is lost
+		if !e.Timestamp.Equal(writtenEvents[i].Timestamp) {
+			t.Fatalf("expected event timestamp %s, got %s", writtenEvents[i].Timestamp, e.Timestamp)
+		}
+		e.Timestamp = writtenEvents[i].Timestamp
+	}
+	if !reflect.DeepEqual(readEvents, writtenEvents) {
+		t.Fatalf("expected events %v, got %v", writtenEvents, readEvents)
+	}
+
+	entries, _, err := es.CommandAudit(nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].CommandType != "CreateTension" || entries[0].SequenceNumber != 1 {
+		t.Fatalf("unexpected command audit entries: %v", entries)
+	}
+
+	key, err := es.PersonalDataKey(id01, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(key, key01) {
+		t.Fatalf("expected key %v, got %v", key01, key)
+	}
+	key, err = es.PersonalDataKey(id02, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key != nil {
+		t.Fatalf("expected nil destroyed key, got %v", key)
+	}
+	if bytes.Equal(key01, key02) {
+		t.Fatalf("expected different keys")
+	}
+
+	// new events must continue the stream after the removed incomplete record
+	if _, err := es.WriteEvents(events, "category01", streamID, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lastSequenceNumber, err := es.LastSequenceNumber()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lastSequenceNumber != 4 {
+		t.Fatalf("expected last sequence number %d, got %d", 4, lastSequenceNumber)
+	}
+}
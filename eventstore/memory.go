@@ -0,0 +1,634 @@
+package eventstore
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	ln "github.com/sorintlab/sircles/listennotify"
+	"github.com/sorintlab/sircles/util"
+
+	"github.com/pkg/errors"
+)
+
+// journal persists the changes of a memory eventstore
+type journal interface {
+	writeRecord(r *logRecord) error
+	writePersonalDataKeys(keys map[util.ID][]byte) error
+}
+
+// MemoryEventStore is an eventstore keeping all its data in memory. It's
+// useful for tests and it's the base of the file eventstore.
+type MemoryEventStore struct {
+	baseEventStore
+
+	nf ln.NotifierFactory
+
+	// journal, when set, is called with every change before applying it. If
+	// it returns an error the change isn't applied.
+	journal journal
+
+	m sync.RWMutex
+	// events ordered by sequence number
+	events     []*StoredEvent
+	eventsByID map[util.ID]*StoredEvent
+	// streams events ordered by version
+	streams        map[string][]*StoredEvent
+	streamVersions map[string]*StreamVersion
+	snapshots      map[string]*Snapshot
+	commandAudit   []*CommandAuditEntry
+	// a destroyed personal data key has a nil value
+	personalDataKeys map[util.ID][]byte
+}
+
+func NewMemoryEventStore(nf ln.NotifierFactory) *MemoryEventStore {
+	return &MemoryEventStore{
+		baseEventStore:   newBaseEventStore(),
+		nf:               nf,
+		eventsByID:       map[util.ID]*StoredEvent{},
+		streams:          map[string][]*StoredEvent{},
+		streamVersions:   map[string]*StreamVersion{},
+		snapshots:        map[string]*Snapshot{},
+		personalDataKeys: map[util.ID][]byte{},
+	}
+}
+
+// commit journals and applies the provided record. It must be called with the
+// lock held
+func (s *MemoryEventStore) commit(r *logRecord) error {
+	// like the sql eventstore check only the unique sequence numbers and
+	// stream versions
+	for _, e := range r.Events {
+		if i, ok := s.searchEvent(e.SequenceNumber); ok {
+			return errors.Errorf("event with sequence number %d already exists", s.events[i].SequenceNumber)
+		}
+		if i, ok := s.searchStreamEvent(e.StreamID, e.Version); ok {
+			return errors.Errorf("event with stream id %s and version %d already exists", e.StreamID, s.streams[e.StreamID][i].Version)
+		}
+	}
+	if s.journal != nil {
+		if err := s.journal.writeRecord(r); err != nil {
+			return err
+		}
+	}
+	s.applyRecord(r)
+	return nil
+}
+
+// applyRecord applies the record changes. It must be called with the lock
+// held
+func (s *MemoryEventStore) applyRecord(r *logRecord) {
+	for _, e := range r.Events {
+		s.insertEvent(e)
+	}
+	for _, sv := range r.StreamVersions {
+		nsv := *sv
+		s.streamVersions[sv.StreamID] = &nsv
+	}
+	if r.CommandAuditEntry != nil {
+		s.commandAudit = append(s.commandAudit, r.CommandAuditEntry)
+	}
+}
+
+func (s *MemoryEventStore) insertEvent(e *StoredEvent) {
+	i, _ := s.searchEvent(e.SequenceNumber)
+	s.events = append(s.events, nil)
+	copy(s.events[i+1:], s.events[i:])
+	s.events[i] = e
+
+	s.eventsByID[e.ID] = e
+
+	stream := s.streams[e.StreamID]
+	i, _ = s.searchStreamEvent(e.StreamID, e.Version)
+	stream = append(stream, nil)
+	copy(stream[i+1:], stream[i:])
+	stream[i] = e
+	s.streams[e.StreamID] = stream
+}
+
+// searchEvent returns the index of the first event with a sequence number
+// greater or equal than the provided one and if the event has the provided
+// sequence number
+func (s *MemoryEventStore) searchEvent(sequenceNumber int64) (int, bool) {
+	i := sort.Search(len(s.events), func(i int) bool { return s.events[i].SequenceNumber >= sequenceNumber })
+	return i, i < len(s.events) && s.events[i].SequenceNumber == sequenceNumber
+}
+
+// searchStreamEvent returns the index of the first stream event with a
+// version greater or equal than the provided one and if the event has the
+// provided version
+func (s *MemoryEventStore) searchStreamEvent(streamID string, version int64) (int, bool) {
+	stream := s.streams[streamID]
+	i := sort.Search(len(stream), func(i int) bool { return stream[i].Version >= version })
+	return i, i < len(stream) && stream[i].Version == version
+}
+
+func (s *MemoryEventStore) lastSequenceNumber() int64 {
+	if len(s.events) == 0 {
+		return 0
+	}
+	return s.events[len(s.events)-1].SequenceNumber
+}
+
+// readEvents returns decoded copies of the provided events. It must be called
+// without the lock held since the codec could use the eventstore
+func (s *MemoryEventStore) readEvents(events []*StoredEvent) ([]*StoredEvent, error) {
+	out := make([]*StoredEvent, len(events))
+	for i, e := range events {
+		ne := *e
+		out[i] = &ne
+	}
+	if err := s.decodeEvents(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *MemoryEventStore) LastSequenceNumber() (int64, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	return s.lastSequenceNumber(), nil
+}
+
+func (s *MemoryEventStore) WriteEvents(eventsData []*EventData, category string, streamID string, version int64) ([]*StoredEvent, error) {
+	if len(eventsData) == 0 {
+		return nil, nil
+	}
+	return s.writeEventsWithCommandAudit(eventsData, category, streamID, version, nil)
+}
+
+func (s *MemoryEventStore) WriteEventsWithCommandAudit(eventsData []*EventData, category string, streamID string, version int64, entry *CommandAuditEntry) ([]*StoredEvent, error) {
+	if len(eventsData) == 0 {
+		return nil, s.WriteCommandAudit(entry)
+	}
+	return s.writeEventsWithCommandAudit(eventsData, category, streamID, version, entry)
+}
+
+func (s *MemoryEventStore) writeEventsWithCommandAudit(eventsData []*EventData, category string, streamID string, version int64, entry *CommandAuditEntry) ([]*StoredEvent, error) {
+
+	timestamp := s.tg.Now()
+
+	// encode the events outside the lock since the codec could use the
+	// eventstore
+	encodedEventsData, err := s.encodeEventsData(eventsData, category, streamID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.m.Lock()
+	events, err := s.writeEvents(timestamp, encodedEventsData, category, streamID, version, entry)
+	s.m.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.nf.NewNotifier().Notify("event", ""); err != nil {
+		return nil, err
+	}
+
+	// return the events with the not encoded data
+	storedEvents := make([]*StoredEvent, len(events))
+	for i, e := range events {
+		se := *e
+		se.Data = eventsData[i].Data
+		se.MetaData = eventsData[i].MetaData
+		storedEvents[i] = &se
+	}
+
+	return storedEvents, nil
+}
+
+func (s *MemoryEventStore) writeEvents(timestamp time.Time, eventsData []*EventData, category string, streamID string, version int64, entry *CommandAuditEntry) ([]*StoredEvent, error) {
+	var curVersion int64
+	sv, ok := s.streamVersions[streamID]
+	if ok {
+		curVersion = sv.Version
+	}
+
+	// optimistic locking: check current version with expected version
+	if curVersion != version {
+		return nil, errors.WithStack(&concurrentUpdateError{providedVersion: version, currentVersion: curVersion})
+	}
+
+	if version != 0 && category != sv.Category {
+		return nil, errors.Errorf("stream in version has different category")
+	}
+
+	sequenceNumber := s.lastSequenceNumber()
+
+	events := make([]*StoredEvent, len(eventsData))
+	for i, ed := range eventsData {
+		sequenceNumber++
+		version++
+
+		events[i] = &StoredEvent{
+			ID:             ed.ID,
+			SequenceNumber: sequenceNumber,
+			EventType:      ed.EventType,
+			Category:       category,
+			StreamID:       streamID,
+			Data:           ed.Data,
+			MetaData:       ed.MetaData,
+
+			Timestamp: timestamp,
+			Version:   version,
+		}
+	}
+
+	log.Debugf("updating stream %s to version: %d", streamID, version)
+	r := &logRecord{
+		Events:         events,
+		StreamVersions: []*StreamVersion{{Category: category, StreamID: streamID, Version: version}},
+	}
+	// the command audit entry is saved in the same record of the events
+	if entry != nil {
+		entry.Timestamp = time.Now().UTC()
+		e := *entry
+		e.SequenceNumber = s.lastCommandAuditSequenceNumber() + 1
+		r.CommandAuditEntry = &e
+	}
+	if err := s.commit(r); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (s *MemoryEventStore) RestoreEvents(events []*StoredEvent) error {
+	events, err := s.encodeEvents(events)
+	if err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	sequenceNumber := s.lastSequenceNumber()
+	versions := map[string]*StreamVersion{}
+
+	restoredEvents := make([]*StoredEvent, len(events))
+	for i, e := range events {
+		sequenceNumber++
+
+		re := *e
+		re.SequenceNumber = sequenceNumber
+		restoredEvents[i] = &re
+
+		versions[e.StreamID] = &StreamVersion{
+			Category: e.Category,
+			StreamID: e.StreamID,
+			Version:  e.Version,
+		}
+	}
+
+	r := &logRecord{Events: restoredEvents}
+	for _, sv := range versions {
+		r.StreamVersions = append(r.StreamVersions, sv)
+	}
+	return s.commit(r)
+}
+
+func (s *MemoryEventStore) CopyEvents(events []*StoredEvent) error {
+	events, err := s.encodeEvents(events)
+	if err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	copiedEvents := make([]*StoredEvent, len(events))
+	for i, e := range events {
+		ce := *e
+		copiedEvents[i] = &ce
+	}
+	return s.commit(&logRecord{Events: copiedEvents})
+}
+
+func (s *MemoryEventStore) CopyStreamVersions(svs []*StreamVersion) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.commit(&logRecord{StreamVersions: svs})
+}
+
+func (s *MemoryEventStore) GetStreamVersions(afterStreamID string, count uint64) ([]*StreamVersion, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	streamIDs := []string{}
+	for streamID := range s.streamVersions {
+		if streamID > afterStreamID {
+			streamIDs = append(streamIDs, streamID)
+		}
+	}
+	sort.Strings(streamIDs)
+	if uint64(len(streamIDs)) > count {
+		streamIDs = streamIDs[:count]
+	}
+
+	svs := make([]*StreamVersion, len(streamIDs))
+	for i, streamID := range streamIDs {
+		sv := *s.streamVersions[streamID]
+		svs[i] = &sv
+	}
+	return svs, nil
+}
+
+func (s *MemoryEventStore) EventsCount() (int64, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	return int64(len(s.events)), nil
+}
+
+func (s *MemoryEventStore) GetEvent(id *util.ID) (*StoredEvent, error) {
+	s.m.RLock()
+	e, ok := s.eventsByID[*id]
+	s.m.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+
+	events, err := s.readEvents([]*StoredEvent{e})
+	if err != nil {
+		return nil, err
+	}
+	return events[0], nil
+}
+
+func (s *MemoryEventStore) GetAllEvents(start int64, count uint64) ([]*StoredEvent, error) {
+	s.m.RLock()
+	i, _ := s.searchEvent(start)
+	events := s.events[i:]
+	if uint64(len(events)) > count {
+		events = events[:count]
+	}
+	s.m.RUnlock()
+
+	return s.readEvents(events)
+}
+
+func (s *MemoryEventStore) GetEventsByCategory(category string, start int64, count uint64) ([]*StoredEvent, error) {
+	s.m.RLock()
+	events := []*StoredEvent{}
+	i, _ := s.searchEvent(start)
+	for _, e := range s.events[i:] {
+		if uint64(len(events)) >= count {
+			break
+		}
+		if e.Category == category {
+			events = append(events, e)
+		}
+	}
+	s.m.RUnlock()
+
+	return s.readEvents(events)
+}
+
+func (s *MemoryEventStore) GetEvents(streamID string, startVersion int64, count uint64) ([]*StoredEvent, error) {
+	s.m.RLock()
+	i, _ := s.searchStreamEvent(streamID, startVersion)
+	events := s.streams[streamID][i:]
+	if uint64(len(events)) > count {
+		events = events[:count]
+	}
+	s.m.RUnlock()
+
+	return s.readEvents(events)
+}
+
+func (s *MemoryEventStore) GetLastEvent(streamID string) (*StoredEvent, error) {
+	s.m.RLock()
+	stream := s.streams[streamID]
+	if len(stream) == 0 {
+		s.m.RUnlock()
+		return nil, nil
+	}
+	e := stream[len(stream)-1]
+	s.m.RUnlock()
+
+	events, err := s.readEvents([]*StoredEvent{e})
+	if err != nil {
+		return nil, err
+	}
+	return events[0], nil
+}
+
+func (s *MemoryEventStore) WriteSnapshot(snapshot *Snapshot) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if cur, ok := s.snapshots[snapshot.StreamID]; ok && cur.Version >= snapshot.Version {
+		return nil
+	}
+	sn := *snapshot
+	s.snapshots[snapshot.StreamID] = &sn
+	return nil
+}
+
+func (s *MemoryEventStore) GetSnapshot(streamID string) (*Snapshot, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	snapshot, ok := s.snapshots[streamID]
+	if !ok {
+		return nil, nil
+	}
+	sn := *snapshot
+	return &sn, nil
+}
+
+func (s *MemoryEventStore) GetSnapshots(afterStreamID string, count uint64) ([]*Snapshot, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	streamIDs := []string{}
+	for streamID := range s.snapshots {
+		if streamID > afterStreamID {
+			streamIDs = append(streamIDs, streamID)
+		}
+	}
+	sort.Strings(streamIDs)
+	if uint64(len(streamIDs)) > count {
+		streamIDs = streamIDs[:count]
+	}
+
+	snapshots := make([]*Snapshot, len(streamIDs))
+	for i, streamID := range streamIDs {
+		sn := *s.snapshots[streamID]
+		snapshots[i] = &sn
+	}
+	return snapshots, nil
+}
+
+func (s *MemoryEventStore) DeleteSnapshot(streamID string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	delete(s.snapshots, streamID)
+	return nil
+}
+
+func (s *MemoryEventStore) WriteCommandAudit(entry *CommandAuditEntry) error {
+	entry.Timestamp = time.Now().UTC()
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	e := *entry
+	e.SequenceNumber = s.lastCommandAuditSequenceNumber() + 1
+	return s.commit(&logRecord{CommandAuditEntry: &e})
+}
+
+// lastCommandAuditSequenceNumber returns the sequence number of the last
+// command audit entry. It must be called with the lock held
+func (s *MemoryEventStore) lastCommandAuditSequenceNumber() int64 {
+	if len(s.commandAudit) == 0 {
+		return 0
+	}
+	return s.commandAudit[len(s.commandAudit)-1].SequenceNumber
+}
+
+func (s *MemoryEventStore) GetCommandAuditEntries(afterSequenceNumber int64, count uint64) ([]*CommandAuditEntry, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	i := sort.Search(len(s.commandAudit), func(i int) bool { return s.commandAudit[i].SequenceNumber > afterSequenceNumber })
+	entries := []*CommandAuditEntry{}
+	for _, e := range s.commandAudit[i:] {
+		if uint64(len(entries)) >= count {
+			break
+		}
+		ne := *e
+		entries = append(entries, &ne)
+	}
+	return entries, nil
+}
+
+func (s *MemoryEventStore) CopyCommandAuditEntries(entries []*CommandAuditEntry) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for _, entry := range entries {
+		if entry.SequenceNumber <= s.lastCommandAuditSequenceNumber() {
+			return errors.Errorf("command audit entry with sequence number %d already exists or isn't the last one", entry.SequenceNumber)
+		}
+		e := *entry
+		if err := s.commit(&logRecord{CommandAuditEntry: &e}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MemoryEventStore) CommandAudit(filter *CommandAuditFilter, limit int) ([]*CommandAuditEntry, bool, error) {
+	if limit <= 0 || limit > MaxCommandAuditFetchSize {
+		limit = MaxCommandAuditFetchSize
+	}
+	if filter == nil {
+		filter = &CommandAuditFilter{}
+	}
+
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	entries := []*CommandAuditEntry{}
+	for i := len(s.commandAudit) - 1; i >= 0; i-- {
+		e := s.commandAudit[i]
+		if filter.IssuerID != nil && (e.IssuerID == nil || *e.IssuerID != *filter.IssuerID) {
+			continue
+		}
+		if filter.CommandType != "" && e.CommandType != filter.CommandType {
+			continue
+		}
+		if filter.FromTime != nil && e.Timestamp.Before(*filter.FromTime) {
+			continue
+		}
+		if filter.ToTime != nil && !e.Timestamp.Before(*filter.ToTime) {
+			continue
+		}
+		if filter.Before > 0 && e.SequenceNumber >= filter.Before {
+			continue
+		}
+		if len(entries) == limit {
+			return entries, true, nil
+		}
+		ne := *e
+		entries = append(entries, &ne)
+	}
+	return entries, false, nil
+}
+
+func (s *MemoryEventStore) PersonalDataKey(id util.ID, create bool) ([]byte, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if key, ok := s.personalDataKeys[id]; ok {
+		return key, nil
+	}
+	if !create {
+		return nil, errors.Errorf("personal data key %s doesn't exist", id)
+	}
+
+	key, err := newPersonalDataKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setPersonalDataKey(id, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *MemoryEventStore) DestroyPersonalDataKey(id util.ID) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.setPersonalDataKey(id, nil)
+}
+
+func (s *MemoryEventStore) PersonalDataKeys() (map[util.ID][]byte, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	keys := make(map[util.ID][]byte, len(s.personalDataKeys))
+	for id, key := range s.personalDataKeys {
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+func (s *MemoryEventStore) CopyPersonalDataKeys(keys map[util.ID][]byte) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.setPersonalDataKeys(keys)
+}
+
+// setPersonalDataKey journals and saves the provided key. It must be called
+// with the lock held
+func (s *MemoryEventStore) setPersonalDataKey(id util.ID, key []byte) error {
+	return s.setPersonalDataKeys(map[util.ID][]byte{id: key})
+}
+
+// setPersonalDataKeys journals and saves the provided keys. It must be called
+// with the lock held
+func (s *MemoryEventStore) setPersonalDataKeys(newKeys map[util.ID][]byte) error {
+	keys := make(map[util.ID][]byte, len(s.personalDataKeys)+len(newKeys))
+	for kid, k := range s.personalDataKeys {
+		keys[kid] = k
+	}
+	for kid, k := range newKeys {
+		keys[kid] = k
+	}
+
+	if s.journal != nil {
+		if err := s.journal.writePersonalDataKeys(keys); err != nil {
+			return err
+		}
+	}
+	s.personalDataKeys = keys
+	return nil
+}
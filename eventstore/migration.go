@@ -20,4 +20,29 @@ var Migrations = []db.Migration{
 			"create table streamversion (streamid varchar not null, category varchar not null, version bigint not null, PRIMARY KEY(streamid))",
 		},
 	},
+	{
+		Stmts: []string{
+			// stores the latest aggregate snapshot for every stream
+			"create table snapshot (streamid varchar not null, category varchar not null, version bigint not null, data bytea, PRIMARY KEY(streamid))",
+		},
+	},
+	{
+		Stmts: []string{
+			// stores the keys used to encrypt the events personal data. A
+			// null key is a destroyed key.
+			"create table personaldatakey (id uuid not null, key bytea, PRIMARY KEY(id))",
+		},
+	},
+	{
+		Stmts: []string{
+			// append only log of the executed commands
+			`--POSTGRES
+             create table commandaudit (sequencenumber bigserial, timestamp timestamptz not null, commandid uuid, commandtype varchar not null, correlationid uuid, causationid uuid, issuerid uuid, aggregatetype varchar, aggregateid varchar, groupid uuid, eventscount bigint not null, result varchar not null, error varchar, remoteaddr varchar, useragent varchar, PRIMARY KEY (sequencenumber))`,
+			`--SQLITE3
+             create table commandaudit (sequencenumber INTEGER PRIMARY KEY AUTOINCREMENT, timestamp timestamptz not null, commandid uuid, commandtype varchar not null, correlationid uuid, causationid uuid, issuerid uuid, aggregatetype varchar, aggregateid varchar, groupid uuid, eventscount bigint not null, result varchar not null, error varchar, remoteaddr varchar, useragent varchar)`,
+			"create index commandaudit_issuerid on commandaudit(issuerid)",
+			"create index commandaudit_commandtype on commandaudit(commandtype)",
+			"create index commandaudit_timestamp on commandaudit(timestamp)",
+		},
+	},
 }
@@ -0,0 +1,241 @@
+package eventstore
+
+import (
+	"crypto/rand"
+	"database/sql"
+
+	"github.com/sorintlab/sircles/db"
+	"github.com/sorintlab/sircles/util"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+)
+
+// PersonalDataKeySize is the size of the personal data keys (AES-256)
+const PersonalDataKeySize = 32
+
+var (
+	personalDataKeySelect  = sb.Select("key").From("personaldatakey")
+	personalDataKeysSelect = sb.Select("id", "key").From("personaldatakey")
+	personalDataKeyInsert  = sb.Insert("personaldatakey").Columns("id", "key")
+)
+
+// DataCodec transforms the events data before they are written to the
+// eventstore (Encode) and after they are read from it (Decode)
+type DataCodec interface {
+	Encode(e *StoredEvent) error
+	Decode(e *StoredEvent) error
+}
+
+func (s *baseEventStore) SetDataCodec(codec DataCodec) {
+	s.codec = codec
+}
+
+// encodeEvents returns a copy of the provided events encoded by the data
+// codec
+func (s *baseEventStore) encodeEvents(events []*StoredEvent) ([]*StoredEvent, error) {
+	if s.codec == nil {
+		return events, nil
+	}
+	encoded := make([]*StoredEvent, len(events))
+	for i, e := range events {
+		ee := *e
+		if err := s.codec.Encode(&ee); err != nil {
+			return nil, err
+		}
+		encoded[i] = &ee
+	}
+	return encoded, nil
+}
+
+// encodeEventsData returns a copy of the provided events data encoded by the
+// data codec
+func (s *baseEventStore) encodeEventsData(eventsData []*EventData, category, streamID string) ([]*EventData, error) {
+	if s.codec == nil {
+		return eventsData, nil
+	}
+	encoded := make([]*EventData, len(eventsData))
+	for i, ed := range eventsData {
+		e := &StoredEvent{
+			ID:        ed.ID,
+			EventType: ed.EventType,
+			Category:  category,
+			StreamID:  streamID,
+			Data:      ed.Data,
+			MetaData:  ed.MetaData,
+		}
+		if err := s.codec.Encode(e); err != nil {
+			return nil, err
+		}
+		encoded[i] = &EventData{
+			ID:        e.ID,
+			EventType: e.EventType,
+			Data:      e.Data,
+			MetaData:  e.MetaData,
+		}
+	}
+	return encoded, nil
+}
+
+// decodeEvents decodes in place the provided events using the data codec
+func (s *baseEventStore) decodeEvents(events []*StoredEvent) error {
+	if s.codec == nil {
+		return nil
+	}
+	for _, e := range events {
+		if err := s.codec.Decode(e); err != nil {
+			return errors.WithMessage(err, "failed to decode event "+e.ID.String())
+		}
+	}
+	return nil
+}
+
+func newPersonalDataKey() ([]byte, error) {
+	key := make([]byte, PersonalDataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Wrap(err, "failed to generate personal data key")
+	}
+	return key, nil
+}
+
+// PersonalDataKey returns the personal data key with the provided id. If the
+// key doesn't exist and create is true a new random key is created, if create
+// is false an error is returned. If the key has been destroyed a nil key is
+// returned.
+func (s *SQLEventStore) PersonalDataKey(id util.ID, create bool) ([]byte, error) {
+	var key []byte
+	err := s.db.Do(func(tx *db.Tx) error {
+		var found bool
+		var err error
+		key, found, err = s.personalDataKey(tx, id)
+		if err != nil {
+			return err
+		}
+		if found {
+			return nil
+		}
+		if !create {
+			return errors.Errorf("personal data key %s doesn't exist", id)
+		}
+
+		key, err = newPersonalDataKey()
+		if err != nil {
+			return err
+		}
+		return s.insertPersonalDataKey(tx, id, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// DestroyPersonalDataKey destroys the personal data key with the provided id.
+// A destroyed key is kept as a tombstone so it won't be created again.
+func (s *SQLEventStore) DestroyPersonalDataKey(id util.ID) error {
+	return s.db.Do(func(tx *db.Tx) error {
+		// poor man insert or update...
+		err := tx.Do(func(tx *db.WrappedTx) error {
+			_, err := tx.Exec("delete from personaldatakey where id = $1", id)
+			return err
+		})
+		if err != nil {
+			return errors.WithMessage(err, "failed to delete personal data key")
+		}
+		return s.insertPersonalDataKey(tx, id, nil)
+	})
+}
+
+// PersonalDataKeys returns all the personal data keys. The destroyed keys have
+// a nil value.
+func (s *SQLEventStore) PersonalDataKeys() (map[util.ID][]byte, error) {
+	q, args, err := personalDataKeysSelect.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+
+	keys := map[util.ID][]byte{}
+	err = s.db.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			rows, err := tx.Query(q, args...)
+			if err != nil {
+				return errors.WithMessage(err, "failed to execute query")
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var id util.ID
+				var key []byte
+				if err := rows.Scan(&id, &key); err != nil {
+					return errors.Wrap(err, "error scanning personal data key")
+				}
+				if len(key) == 0 {
+					key = nil
+				}
+				keys[id] = key
+			}
+			return errors.WithStack(rows.Err())
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// CopyPersonalDataKeys inserts the provided personal data keys. A nil key is
+// inserted as a destroyed key.
+func (s *SQLEventStore) CopyPersonalDataKeys(keys map[util.ID][]byte) error {
+	return s.db.Do(func(tx *db.Tx) error {
+		for id, key := range keys {
+			if err := s.insertPersonalDataKey(tx, id, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *SQLEventStore) personalDataKey(tx *db.Tx, id util.ID) ([]byte, bool, error) {
+	q, args, err := personalDataKeySelect.Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to build query")
+	}
+
+	var key []byte
+	found := true
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		err := tx.QueryRow(q, args...).Scan(&key)
+		if err == sql.ErrNoRows {
+			found = false
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, false, errors.WithMessage(err, "failed to execute query")
+	}
+	if len(key) == 0 {
+		key = nil
+	}
+	return key, found, nil
+}
+
+func (s *SQLEventStore) insertPersonalDataKey(tx *db.Tx, id util.ID, key []byte) error {
+	var v interface{}
+	// a destroyed key is saved as null
+	if key != nil {
+		v = key
+	}
+	q, args, err := personalDataKeyInsert.Values(id, v).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+	err = tx.Do(func(tx *db.WrappedTx) error {
+		_, err := tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to insert personal data key")
+	}
+	return nil
+}
@@ -67,14 +67,14 @@ type loginHandler struct {
 	config           *config.Config
 	dataDir          string
 	readDB           *db.DB
-	es               *eventstore.EventStore
+	es               eventstore.EventStore
 	lnf              ln.ListenerFactory
 	authenticator    auth.Authenticator
 	memberProvider   auth.MemberProvider
 	tokenSigningData *TokenSigningData
 }
 
-func NewLoginHandler(config *config.Config, dataDir string, readDB *db.DB, es *eventstore.EventStore, lnf ln.ListenerFactory, authenticator auth.Authenticator, memberProvider auth.MemberProvider, tokenSigningData *TokenSigningData) *loginHandler {
+func NewLoginHandler(config *config.Config, dataDir string, readDB *db.DB, es eventstore.EventStore, lnf ln.ListenerFactory, authenticator auth.Authenticator, memberProvider auth.MemberProvider, tokenSigningData *TokenSigningData) *loginHandler {
 	return &loginHandler{
 		config:           config,
 		dataDir:          dataDir,
@@ -113,6 +113,7 @@ func doAuth(ctx context.Context, authenticator auth.Authenticator, loginName, pa
 
 func (h *loginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	ctx = context.WithValue(ctx, "requestinfo", requestInfo(r))
 
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, "", http.StatusInternalServerError)
@@ -143,7 +144,7 @@ func (h *loginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "", http.StatusInternalServerError)
 		return
 	}
-	commandService := command.NewCommandService(h.dataDir, h.readDB, h.es, nil, h.lnf, h.memberProvider != nil)
+	commandService := command.NewCommandService(h.dataDir, h.readDB, h.es, nil, h.lnf, h.memberProvider != nil, h.config.HolacracyStrictMode)
 
 	// find a matching member using the matchUID reported by the authenticator
 	member, err := auth.FindMatchingMember(ctx, readDBService, matchUID)
@@ -389,6 +390,8 @@ func (h *AuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// a deleted member doesn't exist at the current timeline so its token will
+	// be rejected
 	member, err := readDBService.Member(ctx, readDBService.CurTimeLine(ctx).Number(), util.NewFromUUID(userID))
 	if err != nil {
 		log.Errorf("auth err: %+v", err)
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sorintlab/sircles/db"
+	ln "github.com/sorintlab/sircles/listennotify"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// eventsPollInterval is the interval used to check for new timelines if
+	// a readdb notification is missed. New timelines are usually sent when
+	// receiving the readdb notification so this is just a fallback.
+	eventsPollInterval = 1 * time.Minute
+	// eventsKeepAliveInterval is the interval between keep alive comments sent
+	// to avoid idle connections being closed by proxies
+	eventsKeepAliveInterval = 30 * time.Second
+)
+
+type sseTimeLine struct {
+	ID        util.TimeLineNumber `json:"id"`
+	Timestamp time.Time           `json:"time"`
+}
+
+type sseRoleParentChange struct {
+	RoleID         util.ID `json:"roleID"`
+	PreviousParent util.ID `json:"previousParent"`
+	NewParent      util.ID `json:"newParent"`
+}
+
+type sseRoleChange struct {
+	RoleID     util.ID `json:"roleID"`
+	ChangeType string  `json:"changeType"`
+}
+
+type sseRoleEvent struct {
+	TimeLineID      util.TimeLineNumber    `json:"timeLineID"`
+	ID              util.ID                `json:"id"`
+	RoleID          util.ID                `json:"roleID"`
+	Type            string                 `json:"type"`
+	IssuerID        util.ID                `json:"issuerID"`
+	ChangedRoles    []*sseRoleChange       `json:"changedRoles"`
+	RolesToCircle   []*sseRoleParentChange `json:"rolesToCircle"`
+	RolesFromCircle []*sseRoleParentChange `json:"rolesFromCircle"`
+}
+
+// eventsHandler streams, using server-sent events, the new timelines and role
+// events generated after the client connection. Clients can resume a stream
+// using the standard Last-Event-ID header (or the "after" query parameter)
+// containing the last received timeline id.
+type eventsHandler struct {
+	readDB *db.DB
+	lnf    ln.ListenerFactory
+}
+
+func NewEventsHandler(readDB *db.DB, lnf ln.ListenerFactory) *eventsHandler {
+	return &eventsHandler{
+		readDB: readDB,
+		lnf:    lnf,
+	}
+}
+
+func (h *eventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Errorf("streaming not supported by response writer")
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+
+	lastTlString := r.Header.Get("Last-Event-ID")
+	if lastTlString == "" {
+		lastTlString = r.FormValue("after")
+	}
+
+	var lastTl util.TimeLineNumber
+	if lastTlString != "" {
+		t, err := strconv.ParseInt(lastTlString, 10, 64)
+		if err != nil {
+			log.Errorf("err: %v", err)
+			http.Error(w, "", http.StatusBadRequest)
+			return
+		}
+		lastTl = util.TimeLineNumber(t)
+	} else {
+		err := h.readDB.Do(func(tx *db.Tx) error {
+			readDBService, err := readdb.NewReadDBService(tx)
+			if err != nil {
+				return err
+			}
+			lastTl = readDBService.CurTimeLine(ctx).Number()
+			return nil
+		})
+		if err != nil {
+			log.Errorf("err: %+v", err)
+			http.Error(w, "", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	l := h.lnf.NewListener()
+	if err := l.Listen("readdb"); err != nil {
+		log.Errorf("err: %+v", err)
+		http.Error(w, "", http.StatusInternalServerError)
+		return
+	}
+	defer l.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAliveTicker := time.NewTicker(eventsKeepAliveInterval)
+	defer keepAliveTicker.Stop()
+
+	for {
+		var err error
+		var memberExists bool
+		lastTl, memberExists, err = h.sendNewEvents(ctx, w, lastTl)
+		if err != nil {
+			log.Errorf("err: %+v", err)
+			return
+		}
+		flusher.Flush()
+		// stop streaming to a member that doesn't exist anymore
+		if !memberExists {
+			return
+		}
+
+		// wait for a readdb notification or the poll timeout, keep alive
+		// comments don't require new queries
+		pollTimer := time.NewTimer(eventsPollInterval)
+	wait:
+		for {
+			select {
+			case <-l.NotificationChannel():
+				break wait
+			case <-pollTimer.C:
+				break wait
+			case <-keepAliveTicker.C:
+				if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+					pollTimer.Stop()
+					return
+				}
+				flusher.Flush()
+			case <-ctx.Done():
+				pollTimer.Stop()
+				return
+			}
+		}
+		pollTimer.Stop()
+	}
+}
+
+// sseTimeLineEvents is a timeline with its role events loaded from the readdb
+type sseTimeLineEvents struct {
+	tl         *util.TimeLine
+	roleEvents []*models.RoleEvent
+}
+
+// sendNewEvents writes all the timelines after lastTl with their role events.
+// It returns the last sent timeline and if the calling member still exists.
+func (h *eventsHandler) sendNewEvents(ctx context.Context, w http.ResponseWriter, lastTl util.TimeLineNumber) (util.TimeLineNumber, bool, error) {
+	for {
+		tlsEvents, hasMoreData, memberExists, err := h.loadNewEvents(ctx, lastTl)
+		if err != nil {
+			return lastTl, false, err
+		}
+		if !memberExists {
+			return lastTl, false, nil
+		}
+
+		// write the events outside the readdb transaction so a slow client
+		// won't keep it open
+		for _, tlEvents := range tlsEvents {
+			for _, roleEvent := range tlEvents.roleEvents {
+				if err := writeSSE(w, "", "roleevent", newSSERoleEvent(roleEvent)); err != nil {
+					return lastTl, false, err
+				}
+			}
+			// send the timeline event last with the sse id so a client
+			// resuming the stream will receive again all the role events of
+			// a partially sent timeline
+			tl := tlEvents.tl
+			if err := writeSSE(w, strconv.FormatInt(int64(tl.Number()), 10), "timeline", &sseTimeLine{ID: tl.Number(), Timestamp: tl.Timestamp}); err != nil {
+				return lastTl, false, err
+			}
+			lastTl = tl.Number()
+		}
+		if !hasMoreData {
+			return lastTl, true, nil
+		}
+	}
+}
+
+// loadNewEvents loads a batch of timelines after lastTl with their role
+// events. Like the graphql Role.events field, all the role events are visible
+// to every member.
+func (h *eventsHandler) loadNewEvents(ctx context.Context, lastTl util.TimeLineNumber) ([]*sseTimeLineEvents, bool, bool, error) {
+	var tlsEvents []*sseTimeLineEvents
+	var hasMoreData bool
+	memberExists := true
+	err := h.readDB.Do(func(tx *db.Tx) error {
+		readDBService, err := readdb.NewReadDBService(tx)
+		if err != nil {
+			return err
+		}
+
+		curTl := readDBService.CurTimeLine(ctx).Number()
+		callingMember, err := readDBService.CallingMember(ctx, curTl)
+		if err != nil {
+			return err
+		}
+		if callingMember == nil {
+			memberExists = false
+			return nil
+		}
+
+		var tls []*util.TimeLine
+		tls, hasMoreData, err = readDBService.TimeLines(ctx, nil, lastTl, 0, true, "", nil)
+		if err != nil {
+			return err
+		}
+		for _, tl := range tls {
+			roleEvents, err := readDBService.TimeLineRoleEvents(ctx, tl.Number())
+			if err != nil {
+				return err
+			}
+			tlsEvents = append(tlsEvents, &sseTimeLineEvents{tl: tl, roleEvents: roleEvents})
+		}
+		return nil
+	})
+	return tlsEvents, hasMoreData, memberExists, err
+}
+
+func newSSERoleEvent(roleEvent *models.RoleEvent) *sseRoleEvent {
+	e := &sseRoleEvent{
+		TimeLineID:      roleEvent.TimeLineID,
+		ID:              roleEvent.ID,
+		RoleID:          roleEvent.RoleID,
+		Type:            string(roleEvent.EventType),
+		ChangedRoles:    []*sseRoleChange{},
+		RolesToCircle:   []*sseRoleParentChange{},
+		RolesFromCircle: []*sseRoleParentChange{},
+	}
+
+	switch roleEvent.EventType {
+	case models.RoleEventTypeCircleChangesApplied:
+		data := roleEvent.Data.(*models.RoleEventCircleChangesApplied)
+		e.IssuerID = data.IssuerID
+		// sort maps to get repeatable ordered results
+		for _, roleID := range sortedRoleIDs(data.ChangedRoles) {
+			e.ChangedRoles = append(e.ChangedRoles, &sseRoleChange{RoleID: roleID, ChangeType: string(data.ChangedRoles[roleID].ChangeType)})
+		}
+		for _, roleID := range sortedParentRoleIDs(data.RolesToCircle) {
+			e.RolesToCircle = append(e.RolesToCircle, &sseRoleParentChange{RoleID: roleID, PreviousParent: data.RolesToCircle[roleID], NewParent: roleEvent.RoleID})
+		}
+		for _, roleID := range sortedParentRoleIDs(data.RolesFromCircle) {
+			e.RolesFromCircle = append(e.RolesFromCircle, &sseRoleParentChange{RoleID: roleID, PreviousParent: roleEvent.RoleID, NewParent: data.RolesFromCircle[roleID]})
+		}
+	}
+
+	return e
+}
+
+func sortedRoleIDs(m map[util.ID]models.RoleChange) util.IDs {
+	roleIDs := util.IDs{}
+	for roleID := range m {
+		roleIDs = append(roleIDs, roleID)
+	}
+	sort.Sort(roleIDs)
+	return roleIDs
+}
+
+func sortedParentRoleIDs(m map[util.ID]util.ID) util.IDs {
+	roleIDs := util.IDs{}
+	for roleID := range m {
+		roleIDs = append(roleIDs, roleID)
+	}
+	sort.Sort(roleIDs)
+	return roleIDs
+}
+
+func writeSSE(w http.ResponseWriter, id, event string, data interface{}) error {
+	d, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal event data")
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, d); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
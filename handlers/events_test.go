@@ -0,0 +1,261 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sorintlab/sircles/change"
+	"github.com/sorintlab/sircles/command"
+	"github.com/sorintlab/sircles/common"
+	"github.com/sorintlab/sircles/db"
+	"github.com/sorintlab/sircles/eventhandler"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/handlers"
+	ln "github.com/sorintlab/sircles/listennotify"
+	"github.com/sorintlab/sircles/lock"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/readdb"
+	"github.com/sorintlab/sircles/util"
+)
+
+type testSSE struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+type testRoleEvent struct {
+	RoleID util.ID `json:"roleID"`
+	Type   string  `json:"type"`
+}
+
+// readSSE sends the server-sent events read from r to the returned channel
+func readSSE(r *bufio.Reader) chan *testSSE {
+	ch := make(chan *testSSE)
+	go func() {
+		defer close(ch)
+		e := &testSSE{}
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "":
+				if e.Event != "" {
+					ch <- e
+				}
+				e = &testSSE{}
+			case strings.HasPrefix(line, "id: "):
+				e.ID = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				e.Event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				e.Data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return ch
+}
+
+// connectEvents connects to the events handler as the provided member and
+// returns the channel of the received events
+func connectEvents(t *testing.T, server *httptest.Server, memberID util.ID, lastTl util.TimeLineNumber) (chan *testSSE, func()) {
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req.Header.Set("X-Test-UserID", memberID.String())
+	if lastTl != 0 {
+		req.Header.Set("Last-Event-ID", strconv.FormatInt(int64(lastTl), 10))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	return readSSE(bufio.NewReader(resp.Body)), func() { resp.Body.Close() }
+}
+
+// receiveRoleEvents returns the role events received before the timeline
+// event with the provided id. It fails if it isn't received in a time much
+// lower than the events poll interval so the stream must be woken up by the
+// readdb notifications.
+func receiveRoleEvents(t *testing.T, ch chan *testSSE, tl util.TimeLineNumber) []*testRoleEvent {
+	roleEvents := []*testRoleEvent{}
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				t.Fatalf("events stream closed")
+			}
+			switch e.Event {
+			case "roleevent":
+				var roleEvent *testRoleEvent
+				if err := json.Unmarshal([]byte(e.Data), &roleEvent); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				roleEvents = append(roleEvents, roleEvent)
+			case "timeline":
+				if e.ID == strconv.FormatInt(int64(tl), 10) {
+					return roleEvents
+				}
+			}
+		case <-timeout:
+			t.Fatalf("timeout waiting for timeline %d", tl)
+		}
+	}
+}
+
+func TestEventsHandler(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	readDB, err := db.NewDB("sqlite3", filepath.Join(tmpDir, "readdb"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer readDB.Close()
+	if err := readDB.Migrate("readdb", readdb.Migrations); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	esDB, err := db.NewDB("sqlite3", filepath.Join(tmpDir, "esdb"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer esDB.Close()
+	if err := esDB.Migrate("eventstore", eventstore.Migrations); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	localln := ln.NewLocalListenNotify()
+	lf := ln.NewLocalListenerFactory(localln)
+	nf := ln.NewLocalNotifierFactory(localln)
+	lkf := lock.NewLocalLockFactory(lock.NewLocalLocks())
+
+	es := eventstore.NewSQLEventStore(esDB, nf)
+	readDBListener := readdb.NewDBListener(readDB, lf)
+	cs := command.NewCommandService(tmpDir, readDB, es, nil, lf, false, false)
+
+	stop := make(chan struct{})
+	endChs := []chan struct{}{}
+	readDBh := readdb.NewDBEventHandler(readDB, es, nf)
+	mrh := eventhandler.NewMemberRequestHandler(tmpDir, es, &common.DefaultUidGenerator{})
+	for _, h := range []eventhandler.EventHandler{readDBh, mrh} {
+		endCh, err := eventhandler.RunEventHandler(h, stop, lf, lkf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		endChs = append(endChs, endCh)
+	}
+	defer func() {
+		close(stop)
+		for _, endCh := range endChs {
+			<-endCh
+		}
+	}()
+
+	ctx := context.Background()
+	waitTimeLine := func(groupID util.ID) util.TimeLineNumber {
+		tl, err := readDBListener.WaitTimeLineForGroupID(ctx, groupID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return tl.Number()
+	}
+
+	rootRoleID, groupID, err := cs.SetupRootRole()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitTimeLine(groupID)
+
+	membersIDs := []util.ID{}
+	for i, userName := range []string{"user01", "user02"} {
+		c := &change.CreateMemberChange{
+			IsAdmin:  i == 0,
+			UserName: userName,
+			FullName: userName,
+			Email:    userName + "@example.com",
+			Password: "password",
+		}
+		res, groupID, err := cs.CreateMemberInternal(ctx, c, false, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		waitTimeLine(groupID)
+		membersIDs = append(membersIDs, *res.MemberID)
+	}
+	adminCtx := context.WithValue(ctx, "userid", membersIDs[0].String())
+
+	createRole := func(parentID util.ID, name string, roleType models.RoleType) (util.ID, util.TimeLineNumber) {
+		res, groupID, err := cs.CircleCreateChildRole(adminCtx, parentID, &change.CreateRoleChange{Name: name, RoleType: roleType}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.HasErrors {
+			t.Fatalf("unexpected errors: %#v", res)
+		}
+		return *res.RoleID, waitTimeLine(groupID)
+	}
+
+	circle01ID, _ := createRole(rootRoleID, "circle01", models.RoleTypeCircle)
+	circle02ID, startTl := createRole(rootRoleID, "circle02", models.RoleTypeCircle)
+
+	eventsHandler := handlers.NewEventsHandler(readDB, lf)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), "userid", r.Header.Get("X-Test-UserID"))
+		eventsHandler.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	defer server.Close()
+
+	user02Events, closeUser02Events := connectEvents(t, server, membersIDs[1], 0)
+	defer closeUser02Events()
+
+	_, tl01 := createRole(circle02ID, "role01", models.RoleTypeNormal)
+	_, tl02 := createRole(circle01ID, "role02", models.RoleTypeNormal)
+
+	// like the graphql Role.events field, user02, that isn't a core member
+	// of any circle, receives all the role events
+	roleEvents := receiveRoleEvents(t, user02Events, tl02)
+	expectedRolesIDs := []util.ID{circle02ID, circle01ID}
+	if len(roleEvents) != len(expectedRolesIDs) {
+		t.Fatalf("expected %d role events, got %d role events", len(expectedRolesIDs), len(roleEvents))
+	}
+	for i, roleEvent := range roleEvents {
+		if roleEvent.RoleID != expectedRolesIDs[i] {
+			t.Fatalf("expected role event for role %s, got role event for role %s", expectedRolesIDs[i], roleEvent.RoleID)
+		}
+	}
+
+	// the admin resumes the stream from the provided timeline
+	adminEvents, closeAdminEvents := connectEvents(t, server, membersIDs[0], startTl)
+	defer closeAdminEvents()
+	roleEvents = receiveRoleEvents(t, adminEvents, tl01)
+	expectedRolesIDs = []util.ID{circle02ID}
+	if len(roleEvents) != len(expectedRolesIDs) {
+		t.Fatalf("expected %d role events, got %d role events", len(expectedRolesIDs), len(roleEvents))
+	}
+	for i, roleEvent := range roleEvents {
+		if roleEvent.RoleID != expectedRolesIDs[i] {
+			t.Fatalf("expected role event for role %s, got role event for role %s", expectedRolesIDs[i], roleEvent.RoleID)
+		}
+	}
+}
@@ -24,14 +24,15 @@ type graphqlHandler struct {
 	dataDir        string
 	readDB         *db.DB
 	readDBListener readdb.ReadDBListener
-	es             *eventstore.EventStore
+	es             eventstore.EventStore
 	lnf            ln.ListenerFactory
 	searchEngine   *search.SearchEngine
 	schema         *graphql.Schema
 	memberProvider auth.MemberProvider
+	rebuilder      *readdb.Rebuilder
 }
 
-func NewGraphQLHandler(config *config.Config, dataDir string, readDB *db.DB, readDBListener readdb.ReadDBListener, es *eventstore.EventStore, lnf ln.ListenerFactory, searchEngine *search.SearchEngine, schema *graphql.Schema, memberProvider auth.MemberProvider) *graphqlHandler {
+func NewGraphQLHandler(config *config.Config, dataDir string, readDB *db.DB, readDBListener readdb.ReadDBListener, es eventstore.EventStore, lnf ln.ListenerFactory, searchEngine *search.SearchEngine, schema *graphql.Schema, memberProvider auth.MemberProvider, rebuilder *readdb.Rebuilder) *graphqlHandler {
 	return &graphqlHandler{
 		config:         config,
 		dataDir:        dataDir,
@@ -42,6 +43,7 @@ func NewGraphQLHandler(config *config.Config, dataDir string, readDB *db.DB, rea
 		searchEngine:   searchEngine,
 		schema:         schema,
 		memberProvider: memberProvider,
+		rebuilder:      rebuilder,
 	}
 }
 
@@ -100,7 +102,7 @@ func (h *graphqlHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	commandService := command.NewCommandService(h.dataDir, h.readDB, h.es, nil, h.lnf, h.memberProvider != nil)
+	commandService := command.NewCommandService(h.dataDir, h.readDB, h.es, nil, h.lnf, h.memberProvider != nil, h.config.HolacracyStrictMode)
 
 	// NOTE(sgotti) only for performance reasons we want to query the readdb
 	// within a single transaction. Since the graphql library calls various
@@ -120,7 +122,9 @@ func (h *graphqlHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx = context.WithValue(ctx, "commandservice", commandService)
 	ctx = context.WithValue(ctx, "memberprovider", h.memberProvider)
 	ctx = context.WithValue(ctx, "searchEngine", h.searchEngine)
+	ctx = context.WithValue(ctx, "readdbrebuilder", h.rebuilder)
 	ctx = context.WithValue(ctx, "image", image)
+	ctx = context.WithValue(ctx, "requestinfo", requestInfo(r))
 
 	log.Debugf("graphql exec")
 	response := h.schema.Exec(ctx, params.Query, params.OperationName, params.Variables)
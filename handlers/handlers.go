@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/sorintlab/sircles/command/commands"
 	slog "github.com/sorintlab/sircles/log"
 )
 
@@ -24,3 +25,13 @@ func (h *maxBytesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	r.Body = http.MaxBytesReader(w, r.Body, h.n)
 	h.h.ServeHTTP(w, r)
 }
+
+// requestInfo returns the request info recorded in the command audit log.
+// The remote address is the one of the direct client (X-Forwarded-For headers
+// aren't trusted).
+func requestInfo(r *http.Request) *commands.RequestInfo {
+	return &commands.RequestInfo{
+		RemoteAddr: r.RemoteAddr,
+		UserAgent:  r.UserAgent(),
+	}
+}
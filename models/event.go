@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/sorintlab/sircles/util"
+)
+
+// Event is the metadata of a stored event
+type Event struct {
+	TimeLineID         util.TimeLineNumber
+	ID                 util.ID
+	SequenceNumber     int64
+	EventType          string
+	AggregateType      string
+	AggregateID        string
+	Timestamp          time.Time
+	CorrelationID      *util.ID
+	CausationID        *util.ID
+	CommandCausationID *util.ID
+	GroupID            *util.ID
+	CommandIssuerID    *util.ID
+}
+
+// CausationEvent is an event in a causation tree
+type CausationEvent struct {
+	*Event
+	// ParentID is the ID of the event that caused this event. It's nil for the
+	// tree roots: the events generated by commands not issued in response to
+	// another event
+	ParentID *util.ID
+	// Depth is the event depth in the causation tree
+	Depth int
+}
+
+// CausationTree contains all the events with the same correlation ID in
+// depth first order
+type CausationTree struct {
+	CorrelationID util.ID
+	Events        []*CausationEvent
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+type MeetingType string
+
+const (
+	MeetingTypeGovernance MeetingType = "governance"
+	MeetingTypeTactical   MeetingType = "tactical"
+)
+
+func MeetingTypeFromString(m string) MeetingType {
+	switch m {
+	case "governance":
+		return MeetingTypeGovernance
+	case "tactical":
+		return MeetingTypeTactical
+	default:
+		return ""
+	}
+}
+
+type MeetingAgendaItem struct {
+	Vertex
+	// Number is the position of the agenda item in the meeting agenda
+	Number  int
+	Title   string
+	Outcome string
+}
+
+type MeetingAgendaItems []*MeetingAgendaItem
+
+func (m MeetingAgendaItems) Len() int           { return len(m) }
+func (m MeetingAgendaItems) Less(i, j int) bool { return m[i].Number < m[j].Number }
+func (m MeetingAgendaItems) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+
+type Meeting struct {
+	Vertex
+	MeetingType MeetingType
+	Title       string
+	StartTime   time.Time
+	Closed      bool
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sorintlab/sircles/util"
+)
+
+type MemberEventType string
+
+const (
+	MemberEventTypeMemberCreated MemberEventType = "MemberCreated"
+	MemberEventTypeMemberUpdated MemberEventType = "MemberUpdated"
+	MemberEventTypeMemberDeleted MemberEventType = "MemberDeleted"
+
+	MemberEventTypeCircleDirectMemberAdded   MemberEventType = "CircleDirectMemberAdded"
+	MemberEventTypeCircleDirectMemberRemoved MemberEventType = "CircleDirectMemberRemoved"
+
+	MemberEventTypeRoleMemberAdded         MemberEventType = "RoleMemberAdded"
+	MemberEventTypeRoleMemberUpdated       MemberEventType = "RoleMemberUpdated"
+	MemberEventTypeRoleMemberRemoved       MemberEventType = "RoleMemberRemoved"
+	MemberEventTypeLeadLinkMemberSet       MemberEventType = "LeadLinkMemberSet"
+	MemberEventTypeLeadLinkMemberUnset     MemberEventType = "LeadLinkMemberUnset"
+	MemberEventTypeCoreRoleMemberSet       MemberEventType = "CoreRoleMemberSet"
+	MemberEventTypeCoreRoleMemberUnset     MemberEventType = "CoreRoleMemberUnset"
+	MemberEventTypeCoreRoleElectionExpired MemberEventType = "CoreRoleElectionExpired"
+)
+
+// MemberEvent is a read side event of the member history. Its ID is the ID
+// of the stored event generating it.
+type MemberEvent struct {
+	SequenceNumber int64
+	TimeLineID     util.TimeLineNumber
+	ID             util.ID
+	MemberID       util.ID
+	EventType      MemberEventType
+	Data           interface{}
+}
+
+func GetMemberEventDataType(eventType MemberEventType) interface{} {
+	switch eventType {
+	case MemberEventTypeMemberCreated, MemberEventTypeMemberUpdated, MemberEventTypeMemberDeleted:
+		return &MemberEventMemberChanged{}
+	case MemberEventTypeCircleDirectMemberAdded, MemberEventTypeCircleDirectMemberRemoved:
+		return &MemberEventCircleDirectMemberChanged{}
+	case MemberEventTypeRoleMemberAdded, MemberEventTypeRoleMemberUpdated, MemberEventTypeRoleMemberRemoved,
+		MemberEventTypeLeadLinkMemberSet, MemberEventTypeLeadLinkMemberUnset,
+		MemberEventTypeCoreRoleMemberSet, MemberEventTypeCoreRoleMemberUnset, MemberEventTypeCoreRoleElectionExpired:
+		return &MemberEventRoleMemberChanged{}
+	default:
+		panic(fmt.Errorf("unknown member event type: %q", eventType))
+	}
+}
+
+func NewMemberEvent(sequenceNumber int64, timeLineID util.TimeLineNumber, id, memberID util.ID, eventType MemberEventType, data interface{}) *MemberEvent {
+	return &MemberEvent{
+		SequenceNumber: sequenceNumber,
+		TimeLineID:     timeLineID,
+		ID:             id,
+		MemberID:       memberID,
+		EventType:      eventType,
+		Data:           data,
+	}
+}
+
+// MemberEventMemberChanged is the data of the member profile events.
+//
+// NOTE(sgotti) the changed values aren't saved since they contain personal
+// data, they can be retrieved from the member at the event timeline and at
+// the previous one.
+type MemberEventMemberChanged struct {
+	// ChangedFields are the names of the changed profile fields
+	// (isAdmin, userName, fullName, email)
+	ChangedFields []string
+}
+
+type MemberEventCircleDirectMemberChanged struct {
+	CircleID util.ID
+}
+
+// MemberEventRoleMemberChanged is the data of the events changing the
+// member assignment to a role (normal or core role)
+type MemberEventRoleMemberChanged struct {
+	RoleID   util.ID
+	RoleType RoleType
+	// CircleID is the circle containing the role
+	CircleID           *util.ID
+	Focus              *string
+	NoCoreMember       bool
+	ElectionExpiration *time.Time
+}
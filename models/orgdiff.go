@@ -0,0 +1,69 @@
+package models
+
+import "github.com/sorintlab/sircles/util"
+
+// OrgDiff contains the changes of the organization, or of a roles subtree,
+// between two timelines.
+//
+// Every diff entry contains the changed entity at the two timelines: the
+// previous values are the ones at FromTl (nil for new entities), the other
+// ones are at ToTl (nil for deleted entities).
+type OrgDiff struct {
+	FromTl util.TimeLineNumber
+	ToTl   util.TimeLineNumber
+
+	Roles            []*RoleDiff
+	Domains          []*DomainDiff
+	Accountabilities []*AccountabilityDiff
+	RoleMembers      []*RoleMemberDiff
+	CoreRoles        []*CoreRoleDiff
+}
+
+// RoleDiff is a role change. A role is updated when its type, name, purpose
+// or parent changed.
+type RoleDiff struct {
+	ChangeType     ChangeType
+	Role           *Role
+	PreviousRole   *Role
+	Parent         *Role
+	PreviousParent *Role
+}
+
+type DomainDiff struct {
+	ChangeType     ChangeType
+	Role           *Role
+	PreviousRole   *Role
+	Domain         *Domain
+	PreviousDomain *Domain
+}
+
+type AccountabilityDiff struct {
+	ChangeType             ChangeType
+	Role                   *Role
+	PreviousRole           *Role
+	Accountability         *Accountability
+	PreviousAccountability *Accountability
+}
+
+// RoleMemberDiff is a change of a member assignment to a role. An assignment
+// is updated when its focus, nocoremember or election expiration changed.
+type RoleMemberDiff struct {
+	ChangeType         ChangeType
+	Role               *Role
+	PreviousRole       *Role
+	RoleMember         *RoleMemberEdge
+	PreviousRoleMember *RoleMemberEdge
+}
+
+// CoreRoleDiff is a change of the member filling a circle core role. It's new
+// when the core role has been filled, deleted when it has been emptied and
+// updated when filled by another member or with a different election
+// expiration.
+type CoreRoleDiff struct {
+	ChangeType         ChangeType
+	RoleType           RoleType
+	Circle             *Role
+	PreviousCircle     *Role
+	RoleMember         *RoleMemberEdge
+	PreviousRoleMember *RoleMemberEdge
+}
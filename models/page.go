@@ -0,0 +1,29 @@
+package models
+
+// PageInfo describes a page of an ordered list
+type PageInfo struct {
+	// Start is the position, starting from 1, of the first page item in the
+	// list
+	Start      int
+	TotalCount int
+}
+
+type RolesPage struct {
+	PageInfo
+	Roles []*Role
+}
+
+type TensionsPage struct {
+	PageInfo
+	Tensions []*Tension
+}
+
+type MemberRoleEdgesPage struct {
+	PageInfo
+	MemberRoleEdges []*MemberRoleEdge
+}
+
+type CircleMemberEdgesPage struct {
+	PageInfo
+	CircleMemberEdges []*CircleMemberEdge
+}
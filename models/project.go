@@ -0,0 +1,43 @@
+package models
+
+type ProjectStatus string
+
+const (
+	ProjectStatusCurrent ProjectStatus = "current"
+	ProjectStatusWaiting ProjectStatus = "waiting"
+	ProjectStatusDone    ProjectStatus = "done"
+)
+
+func ProjectStatusFromString(s string) ProjectStatus {
+	switch s {
+	case "current":
+		return ProjectStatusCurrent
+	case "waiting":
+		return ProjectStatusWaiting
+	case "done":
+		return ProjectStatusDone
+	default:
+		return ""
+	}
+}
+
+type NextAction struct {
+	Vertex
+	// Number is the position of the next action in the project next actions
+	Number      int
+	Description string
+	Completed   bool
+}
+
+type NextActions []*NextAction
+
+func (n NextActions) Len() int           { return len(n) }
+func (n NextActions) Less(i, j int) bool { return n[i].Number < n[j].Number }
+func (n NextActions) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+
+type Project struct {
+	Vertex
+	Title       string
+	Description string
+	Status      ProjectStatus
+}
@@ -0,0 +1,23 @@
+package models
+
+import "github.com/sorintlab/sircles/util"
+
+type ProposalState string
+
+const (
+	ProposalStateOpen     ProposalState = "open"
+	ProposalStateAccepted ProposalState = "accepted"
+)
+
+type ProposalObjection struct {
+	MemberID util.ID
+	Reason   string
+}
+
+type Proposal struct {
+	Vertex
+	State ProposalState
+	// Changes are the json serialized proposal changes
+	Changes    string
+	Objections []*ProposalObjection
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+)
+
+type ReportItemType string
+
+const (
+	ReportItemTypeChecklist ReportItemType = "checklist"
+	ReportItemTypeMetric    ReportItemType = "metric"
+)
+
+func ReportItemTypeFromString(s string) ReportItemType {
+	switch s {
+	case "checklist":
+		return ReportItemTypeChecklist
+	case "metric":
+		return ReportItemTypeMetric
+	default:
+		return ""
+	}
+}
+
+type ReportFrequency string
+
+const (
+	ReportFrequencyWeekly    ReportFrequency = "weekly"
+	ReportFrequencyMonthly   ReportFrequency = "monthly"
+	ReportFrequencyQuarterly ReportFrequency = "quarterly"
+)
+
+func ReportFrequencyFromString(s string) ReportFrequency {
+	switch s {
+	case "weekly":
+		return ReportFrequencyWeekly
+	case "monthly":
+		return ReportFrequencyMonthly
+	case "quarterly":
+		return ReportFrequencyQuarterly
+	default:
+		return ""
+	}
+}
+
+// PeriodStart returns the start time (in UTC) of the report period containing
+// t. Weeks start on monday.
+func (f ReportFrequency) PeriodStart(t time.Time) time.Time {
+	t = t.UTC()
+	switch f {
+	case ReportFrequencyWeekly:
+		d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+	case ReportFrequencyMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case ReportFrequencyQuarterly:
+		return time.Date(t.Year(), ((t.Month()-1)/3)*3+1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return t
+	}
+}
+
+// ReportItemValue is the value reported for a period. For checklist items the
+// value is 1 when the item has been done, 0 otherwise.
+type ReportItemValue struct {
+	Vertex
+	Period time.Time
+	Value  float64
+}
+
+type ReportItemValues []*ReportItemValue
+
+func (r ReportItemValues) Len() int           { return len(r) }
+func (r ReportItemValues) Less(i, j int) bool { return r[i].Period.Before(r[j].Period) }
+func (r ReportItemValues) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
+// ReportItem is a circle checklist item or metric assigned to a role
+type ReportItem struct {
+	Vertex
+	ReportItemType ReportItemType
+	Description    string
+	Frequency      ReportFrequency
+}
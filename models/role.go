@@ -21,6 +21,15 @@ func (r RoleType) IsCoreRoleType() bool {
 		r == RoleTypeSecretary
 }
 
+// IsElectedRoleType reports whether the core role is assigned by an election
+// (facilitator, secretary and rep link). The lead link is appointed by the
+// super circle.
+func (r RoleType) IsElectedRoleType() bool {
+	return r == RoleTypeRepLink ||
+		r == RoleTypeFacilitator ||
+		r == RoleTypeSecretary
+}
+
 func (r RoleType) String() string {
 	return string(r)
 }
@@ -70,6 +79,8 @@ type MemberCirclePermissions struct {
 	AssignCircleDirectMembers   bool
 	AssignCircleCoreRoles       bool
 	ManageRoleAdditionalContent bool
+	AcceptProposals             bool
+	ManageReportItems           bool
 	// special cases for root circle
 	AssignRootCircleLeadLink bool
 	ManageRootCircle         bool
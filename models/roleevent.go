@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"time"
 
 	uuid "github.com/satori/go.uuid"
 	"github.com/sorintlab/sircles/util"
@@ -10,7 +11,8 @@ import (
 type RoleEventType string
 
 const (
-	RoleEventTypeCircleChangesApplied RoleEventType = "CircleChangesApplied"
+	RoleEventTypeCircleChangesApplied    RoleEventType = "CircleChangesApplied"
+	RoleEventTypeCoreRoleElectionExpired RoleEventType = "CoreRoleElectionExpired"
 )
 
 type RoleEvent struct {
@@ -25,6 +27,8 @@ func GetRoleEventDataType(eventType RoleEventType) interface{} {
 	switch eventType {
 	case RoleEventTypeCircleChangesApplied:
 		return &RoleEventCircleChangesApplied{}
+	case RoleEventTypeCoreRoleElectionExpired:
+		return &RoleEventCoreRoleElectionExpired{}
 	default:
 		panic(fmt.Errorf("unknown role event type: %q", eventType))
 	}
@@ -67,6 +71,8 @@ type RoleEventCircleChangesApplied struct {
 	RolesFromCircle map[util.ID]util.ID
 	// key: moved role, value: new parent
 	RolesToCircle map[util.ID]util.ID
+	// the accepted proposal that generated these changes
+	ProposalID *util.ID
 }
 
 func NewRoleEventCircleChangesApplied(timeLineID util.TimeLineNumber, roleID, issuerID util.ID) *RoleEvent {
@@ -82,3 +88,24 @@ func NewRoleEventCircleChangesApplied(timeLineID util.TimeLineNumber, roleID, is
 		},
 	)
 }
+
+type RoleEventCoreRoleElectionExpired struct {
+	CoreRoleID         util.ID
+	RoleType           RoleType
+	MemberID           util.ID
+	ElectionExpiration time.Time
+}
+
+func NewRoleEventCoreRoleElectionExpired(timeLineID util.TimeLineNumber, roleID, coreRoleID, memberID util.ID, roleType RoleType, electionExpiration time.Time) *RoleEvent {
+	return newRoleEvent(
+		timeLineID,
+		roleID,
+		RoleEventTypeCoreRoleElectionExpired,
+		&RoleEventCoreRoleElectionExpired{
+			CoreRoleID:         coreRoleID,
+			RoleType:           roleType,
+			MemberID:           memberID,
+			ElectionExpiration: electionExpiration,
+		},
+	)
+}
@@ -1,9 +1,78 @@
 package models
 
+import (
+	"github.com/sorintlab/sircles/util"
+)
+
+type TensionState string
+
+const (
+	TensionStateOpen       TensionState = "open"
+	TensionStateInProgress TensionState = "inprogress"
+	TensionStateProcessed  TensionState = "processed"
+	TensionStateClosed     TensionState = "closed"
+)
+
+func TensionStateFromString(s string) TensionState {
+	switch s {
+	case "open":
+		return TensionStateOpen
+	case "inprogress":
+		return TensionStateInProgress
+	case "processed":
+		return TensionStateProcessed
+	case "closed":
+		return TensionStateClosed
+	default:
+		return ""
+	}
+}
+
+// TensionComment is a tension discussion comment. A comment can be a reply to
+// another comment of the same tension.
+type TensionComment struct {
+	Vertex
+	// Number is the position of the comment in the tension discussion
+	Number   int
+	Text     string
+	TimeLine util.TimeLineNumber
+}
+
+type TensionComments []*TensionComment
+
+func (t TensionComments) Len() int           { return len(t) }
+func (t TensionComments) Less(i, j int) bool { return t[i].Number < t[j].Number }
+func (t TensionComments) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+
+type TensionLabel struct {
+	Vertex
+	// Number is the position of the label in the tension labels
+	Number int
+	Name   string
+}
+
+type TensionLabels []*TensionLabel
+
+func (t TensionLabels) Len() int           { return len(t) }
+func (t TensionLabels) Less(i, j int) bool { return t[i].Number < t[j].Number }
+func (t TensionLabels) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+
 type Tension struct {
 	Vertex
 	Title       string
 	Description string
 	Closed      bool
 	CloseReason string
+	State       TensionState
+}
+
+// MemberTensionPermissions are the calling member permissions on a tension
+type MemberTensionPermissions struct {
+	// change the tension state (except closing it), reopen it, assign it
+	// and set its labels
+	ManageTension bool
+	// change the tension state to open, in progress or processed
+	ChangeTensionState bool
+	// read and add comments
+	Comment bool
 }
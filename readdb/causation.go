@@ -0,0 +1,208 @@
+package readdb
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/sorintlab/sircles/db"
+	"github.com/sorintlab/sircles/eventstore"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+)
+
+var (
+	eventMetaDataColumns = []string{
+		"timeline",
+		"id",
+		"sequencenumber",
+		"eventtype",
+		"aggregatetype",
+		"aggregateid",
+		"timestamp",
+		"correlationid",
+		"causationid",
+		"commandcausationid",
+		"groupid",
+		"commandissuerid",
+	}
+
+	eventMetaDataSelect = sb.Select(eventMetaDataColumns...).From("eventmetadata")
+	eventMetaDataInsert = sb.Insert("eventmetadata").Columns(eventMetaDataColumns...)
+)
+
+func (s *readDBService) insertEventMetaData(tl util.TimeLineNumber, event *eventstore.StoredEvent, metaData *eventstore.EventMetaData) error {
+	q, args, err := eventMetaDataInsert.Values(tl, event.ID, event.SequenceNumber, event.EventType, event.Category, event.StreamID, event.Timestamp, metaData.CorrelationID, metaData.CausationID, metaData.CommandCausationID, metaData.GroupID, metaData.CommandIssuerID).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
+	}
+	return nil
+}
+
+func scanEvent(rows *sql.Rows) (*models.Event, error) {
+	e := models.Event{}
+	fields := []interface{}{&e.TimeLineID, &e.ID, &e.SequenceNumber, &e.EventType, &e.AggregateType, &e.AggregateID, &e.Timestamp, &e.CorrelationID, &e.CausationID, &e.CommandCausationID, &e.GroupID, &e.CommandIssuerID}
+	if err := rows.Scan(fields...); err != nil {
+		return nil, errors.Wrap(err, "error scanning event")
+	}
+	return &e, nil
+}
+
+func scanEvents(rows *sql.Rows) ([]*models.Event, error) {
+	events := []*models.Event{}
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *readDBService) events(condition sq.Sqlizer) ([]*models.Event, error) {
+	q, args, err := eventMetaDataSelect.Where(condition).OrderBy("sequencenumber").ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+
+	var events []*models.Event
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return errors.WithMessage(err, "failed to execute query")
+		}
+		events, err = scanEvents(rows)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Event returns the metadata of the provided event
+func (s *readDBService) Event(ctx context.Context, id util.ID) (*models.Event, error) {
+	events, err := s.events(sq.Eq{"id": id})
+	if err != nil {
+		return nil, err
+	}
+	if len(events) < 1 {
+		return nil, nil
+	}
+	return events[0], nil
+}
+
+// GroupEvents returns the metadata of the events in the provided group
+func (s *readDBService) GroupEvents(ctx context.Context, groupID util.ID) ([]*models.Event, error) {
+	return s.events(sq.Eq{"groupid": groupID})
+}
+
+// TimeLineEvents returns the metadata of the events generated at the provided
+// timeline
+func (s *readDBService) TimeLineEvents(ctx context.Context, tl util.TimeLineNumber) ([]*models.Event, error) {
+	return s.events(sq.Eq{"timeline": tl})
+}
+
+// CausationTree returns all the events with the provided correlation ID
+// arranged in a causation tree.
+// The parent of an event is the event referenced by its causation ID (events
+// generated by sagas) or by its command causation ID (events generated by
+// commands issued by sagas or event handlers). Events without a parent, like
+// the ones generated by commands issued by a member, are the tree roots.
+func (s *readDBService) CausationTree(ctx context.Context, correlationID util.ID) (*models.CausationTree, error) {
+	events, err := s.events(sq.Eq{"correlationid": correlationID})
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCausationTree(correlationID, events), nil
+}
+
+// buildCausationTree builds the causation tree of the provided events sorted
+// by sequence number
+func buildCausationTree(correlationID util.ID, events []*models.Event) *models.CausationTree {
+	eventsMap := make(map[util.ID]*models.Event, len(events))
+	for _, e := range events {
+		eventsMap[e.ID] = e
+	}
+
+	parentID := func(e *models.Event) *util.ID {
+		for _, id := range []*util.ID{e.CausationID, e.CommandCausationID} {
+			if id == nil {
+				continue
+			}
+			// only a preceding event could be the parent
+			if pe, ok := eventsMap[*id]; ok && pe.SequenceNumber < e.SequenceNumber {
+				return &pe.ID
+			}
+		}
+		return nil
+	}
+
+	roots := []*models.Event{}
+	children := map[util.ID][]*models.Event{}
+	parents := map[util.ID]*util.ID{}
+	for _, e := range events {
+		pid := parentID(e)
+		parents[e.ID] = pid
+		if pid == nil {
+			roots = append(roots, e)
+			continue
+		}
+		children[*pid] = append(children[*pid], e)
+	}
+
+	tree := &models.CausationTree{CorrelationID: correlationID, Events: []*models.CausationEvent{}}
+	var walk func(e *models.Event, depth int)
+	walk = func(e *models.Event, depth int) {
+		tree.Events = append(tree.Events, &models.CausationEvent{Event: e, ParentID: parents[e.ID], Depth: depth})
+		for _, c := range children[e.ID] {
+			walk(c, depth+1)
+		}
+	}
+	for _, r := range roots {
+		walk(r, 0)
+	}
+
+	return tree
+}
+
+// CausationTrees returns the causation trees of the provided events
+func (s *readDBService) CausationTrees(ctx context.Context, events []*models.Event) ([]*models.CausationTree, error) {
+	correlationIDs := map[util.ID]struct{}{}
+	for _, e := range events {
+		if e.CorrelationID != nil {
+			correlationIDs[*e.CorrelationID] = struct{}{}
+		}
+	}
+
+	trees := []*models.CausationTree{}
+	for correlationID := range correlationIDs {
+		tree, err := s.CausationTree(ctx, correlationID)
+		if err != nil {
+			return nil, err
+		}
+		trees = append(trees, tree)
+	}
+	// sort the trees by their first event (the first event is always a root)
+	sort.Slice(trees, func(i, j int) bool {
+		return trees[i].Events[0].SequenceNumber < trees[j].Events[0].SequenceNumber
+	})
+
+	return trees, nil
+}
@@ -0,0 +1,65 @@
+package readdb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+)
+
+func TestBuildCausationTree(t *testing.T) {
+	id := func(s string) *util.ID {
+		id := util.IDFromStringOrNil(s)
+		return &id
+	}
+
+	correlationID := id("00000000-0000-0000-0000-000000000000")
+	// random command ids
+	command01 := id("10000000-0000-0000-0000-000000000000")
+	command02 := id("20000000-0000-0000-0000-000000000000")
+	command03 := id("30000000-0000-0000-0000-000000000000")
+	command04 := id("40000000-0000-0000-0000-000000000000")
+
+	newEvent := func(sn int64, eventID string, causationID, commandCausationID *util.ID) *models.Event {
+		return &models.Event{
+			ID:                 *id(eventID),
+			SequenceNumber:     sn,
+			CorrelationID:      correlationID,
+			CausationID:        causationID,
+			CommandCausationID: commandCausationID,
+		}
+	}
+
+	// a member change request (event 01) handled by a saga that writes a saga
+	// event (event 03) and issues two commands (events 02 and 04). Event 05
+	// is generated by a command issued by the saga handling event 04.
+	// Event 06 references a later event and must be a root.
+	e01 := newEvent(1, "00000000-0000-0000-0000-000000000001", command01, nil)
+	e02 := newEvent(2, "00000000-0000-0000-0000-000000000002", command02, &e01.ID)
+	e03 := newEvent(3, "00000000-0000-0000-0000-000000000003", &e01.ID, nil)
+	e04 := newEvent(4, "00000000-0000-0000-0000-000000000004", command03, &e01.ID)
+	e06 := newEvent(5, "00000000-0000-0000-0000-000000000006", command04, id("00000000-0000-0000-0000-000000000005"))
+	e05 := newEvent(6, "00000000-0000-0000-0000-000000000005", command04, &e04.ID)
+
+	tree := buildCausationTree(*correlationID, []*models.Event{e01, e02, e03, e04, e06, e05})
+
+	expected := &models.CausationTree{
+		CorrelationID: *correlationID,
+		Events: []*models.CausationEvent{
+			{Event: e01, Depth: 0},
+			{Event: e02, ParentID: &e01.ID, Depth: 1},
+			{Event: e03, ParentID: &e01.ID, Depth: 1},
+			{Event: e04, ParentID: &e01.ID, Depth: 1},
+			{Event: e05, ParentID: &e04.ID, Depth: 2},
+			{Event: e06, Depth: 0},
+		},
+	}
+
+	if !reflect.DeepEqual(tree, expected) {
+		for i, e := range tree.Events {
+			t.Logf("%d: event: %s, parent: %v, depth: %d", i, e.ID, e.ParentID, e.Depth)
+		}
+		t.Fatalf("unexpected causation tree")
+	}
+}
@@ -0,0 +1,312 @@
+package readdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sorintlab/sircles/db"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+)
+
+// RoleFilter defines the conditions the roles must match. Nil or empty fields
+// are ignored.
+type RoleFilter struct {
+	RoleTypes []models.RoleType
+	MinDepth  *int
+	MaxDepth  *int
+	// SubtreeRootID restricts the roles to the ones in the subtree starting at
+	// the provided role (included)
+	SubtreeRootID *util.ID
+	// Unfilled, when true, restricts the roles to the ones, excluding circles,
+	// without members filling them. When false to the ones with members.
+	Unfilled *bool
+}
+
+type RoleOrderField string
+
+const (
+	RoleOrderFieldName     RoleOrderField = "name"
+	RoleOrderFieldDepth    RoleOrderField = "depth"
+	RoleOrderFieldRoleType RoleOrderField = "roletype"
+)
+
+type RoleOrder struct {
+	Field RoleOrderField
+	Desc  bool
+}
+
+// MemberFilter defines the conditions the members must match. Nil fields are
+// ignored.
+type MemberFilter struct {
+	// FilledRolesGreaterThan restricts the members to the ones filling more
+	// than the provided number of roles
+	FilledRolesGreaterThan *int
+	// LeadLinkOfID restricts the members to the lead link of the provided
+	// circle
+	LeadLinkOfID *util.ID
+}
+
+type MemberOrderField string
+
+const (
+	MemberOrderFieldFullName    MemberOrderField = "fullname"
+	MemberOrderFieldUserName    MemberOrderField = "username"
+	MemberOrderFieldFilledRoles MemberOrderField = "filledroles"
+)
+
+type MemberOrder struct {
+	Field MemberOrderField
+	Desc  bool
+}
+
+// subquery returns the provided query as a sqlizer usable inside another
+// query. The query must use the question placeholder format since the outer
+// query will convert them.
+func subquery(format string, q sq.SelectBuilder, args ...interface{}) (sq.Sqlizer, error) {
+	sql, qargs, err := q.PlaceholderFormat(sq.Question).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	return sq.Expr(fmt.Sprintf(format, sql), append(qargs, args...)...), nil
+}
+
+// subtreeRolesIDs returns the ids of the roles in the subtree starting at the
+// provided role (included)
+func (s *readDBService) subtreeRolesIDs(ctx context.Context, tl util.TimeLineNumber, rootRoleID util.ID) ([]util.ID, error) {
+	ids := []util.ID{rootRoleID}
+	parentsIDs := []util.ID{rootRoleID}
+	for len(parentsIDs) > 0 {
+		childsGroups, err := s.ChildRoles(ctx, tl, parentsIDs, nil)
+		if err != nil {
+			return nil, err
+		}
+		childsIDs := []util.ID{}
+		for _, parentID := range parentsIDs {
+			for _, child := range childsGroups[parentID] {
+				childsIDs = append(childsIDs, child.ID)
+			}
+		}
+		ids = append(ids, childsIDs...)
+		parentsIDs = childsIDs
+	}
+	return ids, nil
+}
+
+// roleFilterCond returns the condition selecting the roles matching the
+// provided filter
+func (s *readDBService) roleFilterCond(ctx context.Context, tl util.TimeLineNumber, filter *RoleFilter) (sq.Sqlizer, error) {
+	cond := sq.And{}
+	if filter == nil {
+		return cond, nil
+	}
+
+	if len(filter.RoleTypes) > 0 {
+		roleTypes := make([]string, len(filter.RoleTypes))
+		for i, roleType := range filter.RoleTypes {
+			roleTypes[i] = string(roleType)
+		}
+		cond = append(cond, sq.Eq{"role.roletype": roleTypes})
+	}
+	if filter.MinDepth != nil {
+		cond = append(cond, sq.GtOrEq{"role.depth": *filter.MinDepth})
+	}
+	if filter.MaxDepth != nil {
+		cond = append(cond, sq.LtOrEq{"role.depth": *filter.MaxDepth})
+	}
+	if filter.SubtreeRootID != nil {
+		ids, err := s.subtreeRolesIDs(ctx, tl, *filter.SubtreeRootID)
+		if err != nil {
+			return nil, err
+		}
+		cond = append(cond, sq.Eq{"role.id": ids})
+	}
+	if filter.Unfilled != nil {
+		filledRoles := sq.Select("rolemember.y").From("rolemember").Where(s.timeLineCond("rolemember", tl))
+		if *filter.Unfilled {
+			c, err := subquery("role.id NOT IN (%s)", filledRoles)
+			if err != nil {
+				return nil, err
+			}
+			cond = append(cond, c, sq.NotEq{"role.roletype": string(models.RoleTypeCircle)})
+		} else {
+			c, err := subquery("role.id IN (%s)", filledRoles)
+			if err != nil {
+				return nil, err
+			}
+			cond = append(cond, c)
+		}
+	}
+
+	return cond, nil
+}
+
+func roleOrderBys(order *RoleOrder) []string {
+	if order == nil {
+		order = &RoleOrder{Field: RoleOrderFieldName}
+	}
+	orderBy := "role." + string(order.Field)
+	if order.Desc {
+		orderBy += " desc"
+	}
+	orderBys := []string{orderBy}
+	if order.Field != RoleOrderFieldName {
+		orderBys = append(orderBys, "role.name")
+	}
+	return append(orderBys, "role.id")
+}
+
+// FilteredRoles returns all the roles matching the provided filter ordered by
+// the provided order (by name if nil)
+func (s *readDBService) FilteredRoles(ctx context.Context, tl util.TimeLineNumber, filter *RoleFilter, order *RoleOrder) ([]*models.Role, error) {
+	cond, err := s.roleFilterCond(ctx, tl, filter)
+	if err != nil {
+		return nil, err
+	}
+	vs, err := s.vertices(tl, vertexClassRole, 0, cond, roleOrderBys(order))
+	if err != nil {
+		return nil, err
+	}
+	return vs.([]*models.Role), nil
+}
+
+// membersSelect returns a select on the members at the provided timeline
+// joined with their number of filled roles (in the
+// memberfilledroles.filledroles column)
+func (s *readDBService) membersSelect(tl util.TimeLineNumber, columns ...string) (sq.SelectBuilder, error) {
+	filledRoles := sq.Select("rolemember.x AS memberid", "count(*) AS filledroles").From("rolemember").Where(s.timeLineCond("rolemember", tl)).GroupBy("rolemember.x")
+	join, err := subquery("LEFT JOIN (%s) AS memberfilledroles ON memberfilledroles.memberid = member.id", filledRoles)
+	if err != nil {
+		return sq.SelectBuilder{}, err
+	}
+	return sb.Select(columns...).From(vertexClassMember.String()).JoinClause(join).Where(s.timeLineCond(vertexClassMember.String(), tl)), nil
+}
+
+const memberFilledRolesColumn = "coalesce(memberfilledroles.filledroles, 0)"
+
+// memberFilterCond returns the condition selecting the members matching the
+// provided filter. It must be used on a membersSelect query.
+func (s *readDBService) memberFilterCond(tl util.TimeLineNumber, filter *MemberFilter) (sq.Sqlizer, error) {
+	cond := sq.And{}
+	if filter == nil {
+		return cond, nil
+	}
+
+	if filter.FilledRolesGreaterThan != nil {
+		cond = append(cond, sq.Gt{memberFilledRolesColumn: *filter.FilledRolesGreaterThan})
+	}
+	if filter.LeadLinkOfID != nil {
+		leadLinks := sq.Select("rolemember.x").From("rolemember").
+			Join("rolerole ON rolerole.y = rolemember.y").
+			Join("role ON role.id = rolerole.y").
+			Where(sq.Eq{"rolerole.x": *filter.LeadLinkOfID, "role.roletype": string(models.RoleTypeLeadLink)}).
+			Where(s.timeLineCond("rolemember", tl)).
+			Where(s.timeLineCond("rolerole", tl)).
+			Where(s.timeLineCond("role", tl))
+		c, err := subquery("member.id IN (%s)", leadLinks)
+		if err != nil {
+			return nil, err
+		}
+		cond = append(cond, c)
+	}
+
+	return cond, nil
+}
+
+func memberOrderColumn(order *MemberOrder) string {
+	switch order.Field {
+	case MemberOrderFieldFilledRoles:
+		return memberFilledRolesColumn
+	default:
+		return "member." + string(order.Field)
+	}
+}
+
+// members returns at most first members matching the provided search string
+// and filter, ordered by the provided order and then by id. If after is
+// provided, only the members following it are returned.
+func (s *readDBService) members(ctx context.Context, tl util.TimeLineNumber, searchString string, filter *MemberFilter, order *MemberOrder, first int, after *util.ID) ([]*models.Member, error) {
+	if order == nil {
+		order = &MemberOrder{Field: MemberOrderFieldFullName}
+	}
+	orderColumn := memberOrderColumn(order)
+
+	cond := sq.And{}
+	if searchString != "" {
+		search := "%" + searchString + "%"
+		cond = append(cond, sq.Or{sq.Expr("lower(member.fullname) LIKE lower(?)", search), sq.Expr("lower(member.username) LIKE lower(?)", search)})
+	}
+	filterCond, err := s.memberFilterCond(tl, filter)
+	if err != nil {
+		return nil, err
+	}
+	cond = append(cond, filterCond)
+
+	if after != nil {
+		// get the order value of the after member
+		kq, err := s.membersSelect(tl, orderColumn)
+		if err != nil {
+			return nil, err
+		}
+		q, args, err := kq.Where(sq.Eq{"member.id": *after}).ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to build query")
+		}
+		var key interface{}
+		err = s.tx.Do(func(tx *db.WrappedTx) error {
+			if order.Field == MemberOrderFieldFilledRoles {
+				var count int
+				err := tx.QueryRow(q, args...).Scan(&count)
+				key = count
+				return err
+			}
+			var value string
+			err := tx.QueryRow(q, args...).Scan(&value)
+			key = value
+			return err
+		})
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get the cursor member")
+		}
+		op := ">"
+		if order.Desc {
+			op = "<"
+		}
+		cond = append(cond, sq.Or{sq.Expr(fmt.Sprintf("%s %s ?", orderColumn, op), key), sq.And{sq.Eq{orderColumn: key}, sq.Gt{"member.id": *after}}})
+	}
+
+	orderBy := orderColumn
+	if order.Desc {
+		orderBy += " desc"
+	}
+
+	mq, err := s.membersSelect(tl, tableColumns(vertexClassMember.String(), memberAllColumns)...)
+	if err != nil {
+		return nil, err
+	}
+	mq = mq.Where(cond).OrderBy(orderBy, "member.id")
+	if first > 0 {
+		mq = mq.Limit(uint64(first))
+	}
+	q, args, err := mq.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+
+	var members []*models.Member
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return errors.WithMessage(err, "failed to execute query")
+		}
+		members, err = scanMembers(rows)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
@@ -0,0 +1,193 @@
+package readdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/sorintlab/sircles/db"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+)
+
+var (
+	memberEventColumns = []string{
+		"sequencenumber",
+		"timeline",
+		"id",
+		"memberid",
+		"eventtype",
+		"data",
+	}
+
+	memberEventSelect = sb.Select(memberEventColumns...).From("memberevent")
+	memberEventInsert = sb.Insert("memberevent").Columns(memberEventColumns...)
+)
+
+func scanMemberEvent(rows *sql.Rows) (*models.MemberEvent, error) {
+	e := models.MemberEvent{}
+	var rawData []byte
+	// To make sqlite3 happy
+	var eventType string
+	fields := []interface{}{&e.SequenceNumber, &e.TimeLineID, &e.ID, &e.MemberID, &eventType, &rawData}
+	if err := rows.Scan(fields...); err != nil {
+		return nil, errors.Wrap(err, "error scanning event")
+	}
+	e.EventType = models.MemberEventType(eventType)
+
+	data := models.GetMemberEventDataType(e.EventType)
+	if err := json.Unmarshal(rawData, &data); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal event")
+	}
+	e.Data = data
+
+	return &e, nil
+}
+
+func scanMemberEvents(rows *sql.Rows) ([]*models.MemberEvent, error) {
+	memberEvents := []*models.MemberEvent{}
+	for rows.Next() {
+		e, err := scanMemberEvent(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		memberEvents = append(memberEvents, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return memberEvents, nil
+}
+
+// insertMemberEvent inserts or update a member event
+func (s *readDBService) insertMemberEvent(memberEvent *models.MemberEvent) error {
+	data, err := json.Marshal(memberEvent.Data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal event")
+	}
+	q, args, err := memberEventInsert.Values(memberEvent.SequenceNumber, memberEvent.TimeLineID, memberEvent.ID, memberEvent.MemberID, memberEvent.EventType, data).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
+	}
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		// poor man insert or update...
+		if _, err := tx.Exec("delete from memberevent where id = $1", memberEvent.ID); err != nil {
+			return errors.Wrap(err, "failed to delete memberevent")
+		}
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
+	}
+	return nil
+}
+
+// roleMemberChangedData returns the member event data of a change of a member
+// assignment to the provided role, getting the role type and circle at the
+// provided timeline or, if the role has been deleted in the same timeline, at
+// the previous one
+func (s *readDBService) roleMemberChangedData(ctx context.Context, tl util.TimeLineNumber, roleID util.ID) (*models.MemberEventRoleMemberChanged, error) {
+	data := &models.MemberEventRoleMemberChanged{
+		RoleID: roleID,
+	}
+	for _, rtl := range []util.TimeLineNumber{tl, tl - 1} {
+		role, err := s.Role(ctx, rtl, roleID)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			continue
+		}
+		data.RoleType = role.RoleType
+		proleGroups, err := s.RoleParent(ctx, rtl, []util.ID{roleID})
+		if err != nil {
+			return nil, err
+		}
+		if prole, ok := proleGroups[roleID]; ok {
+			data.CircleID = &prole.ID
+		}
+		return data, nil
+	}
+	return nil, errors.Errorf("role with id %s doesn't exist", roleID)
+}
+
+// memberChangedFields returns the names of the member profile fields changed
+// at the provided timeline
+func (s *readDBService) memberChangedFields(ctx context.Context, tl util.TimeLineNumber, memberID util.ID) ([]string, error) {
+	prevMember, err := s.Member(ctx, tl-1, memberID)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.Member(ctx, tl, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errors.Errorf("member with id %s doesn't exist", memberID)
+	}
+	if prevMember == nil {
+		// member created in the same timeline
+		return nil, nil
+	}
+
+	changedFields := []string{}
+	if member.IsAdmin != prevMember.IsAdmin {
+		changedFields = append(changedFields, "isAdmin")
+	}
+	if member.UserName != prevMember.UserName {
+		changedFields = append(changedFields, "userName")
+	}
+	if member.FullName != prevMember.FullName {
+		changedFields = append(changedFields, "fullName")
+	}
+	if member.Email != prevMember.Email {
+		changedFields = append(changedFields, "email")
+	}
+	return changedFields, nil
+}
+
+// MemberEvents returns, newest first, at most first member events. If after
+// (a sequence number) is provided only the events before it are returned,
+// otherwise the events up to the provided start timeline.
+func (s *readDBService) MemberEvents(ctx context.Context, memberID util.ID, first int, start util.TimeLineNumber, after int64) ([]*models.MemberEvent, bool, error) {
+	sb := memberEventSelect.Where(sq.Eq{"memberid": memberID}).OrderBy("sequencenumber desc")
+
+	if after != 0 {
+		sb = sb.Where(sq.Lt{"sequencenumber": after})
+	} else if start != 0 {
+		sb = sb.Where(sq.LtOrEq{"timeline": start})
+	}
+
+	if first != 0 {
+		// fetch one more event to know if there're more events
+		sb = sb.Limit(uint64(first + 1))
+	}
+
+	q, args, err := sb.ToSql()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to build query")
+	}
+
+	var events []*models.MemberEvent
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return errors.WithMessage(err, "failed to execute query")
+		}
+		events, err = scanMemberEvents(rows)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if first != 0 && len(events) > first {
+		return events[:first], true, nil
+	}
+	return events, false, nil
+}
@@ -103,4 +103,196 @@ var Migrations = []db.Migration{
 			"create table membermatch (memberid uuid, matchuid varchar)",
 		},
 	},
+	{
+		Stmts: []string{
+			// objections are json serialized
+			"create table proposal (id uuid, start_tl bigint, end_tl bigint, state varchar, changes varchar, objections varchar, PRIMARY KEY (id, start_tl))",
+			"create unique index proposal_tl on proposal(id, start_tl, end_tl DESC)",
+
+			"create table tensionproposal (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: proposal id, y: tension id
+			"create index tensionproposal_x_start_tl on tensionproposal(x, start_tl, end_tl DESC)",
+			"create index tensionproposal_y_start_tl on tensionproposal(y, start_tl, end_tl DESC)",
+
+			"create table roleproposal (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: proposal id, y: role id
+			"create index roleproposal_x_start_tl on roleproposal(x, start_tl, end_tl DESC)",
+			"create index roleproposal_y_start_tl on roleproposal(y, start_tl, end_tl DESC)",
+
+			"create table memberproposal (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: proposal id, y: member id
+			"create index memberproposal_x_start_tl on memberproposal(x, start_tl, end_tl DESC)",
+			"create index memberproposal_y_start_tl on memberproposal(y, start_tl, end_tl DESC)",
+		},
+	},
+	{
+		Stmts: []string{
+			// agendaitems are json serialized
+			"create table meeting (id uuid, start_tl bigint, end_tl bigint, meetingtype varchar, title varchar, starttime timestamptz, agendaitems varchar, closed bool, PRIMARY KEY (id, start_tl))",
+			"create unique index meeting_tl on meeting(id, start_tl, end_tl DESC)",
+
+			"create table rolemeeting (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: meeting id, y: role id
+			"create index rolemeeting_x_start_tl on rolemeeting(x, start_tl, end_tl DESC)",
+			"create index rolemeeting_y_start_tl on rolemeeting(y, start_tl, end_tl DESC)",
+
+			"create table meetingattendee (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: member id, y: meeting id
+			"create index meetingattendee_x_start_tl on meetingattendee(x, start_tl, end_tl DESC)",
+			"create index meetingattendee_y_start_tl on meetingattendee(y, start_tl, end_tl DESC)",
+
+			"create table meetingfacilitator (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: member id, y: meeting id
+			"create index meetingfacilitator_x_start_tl on meetingfacilitator(x, start_tl, end_tl DESC)",
+			"create index meetingfacilitator_y_start_tl on meetingfacilitator(y, start_tl, end_tl DESC)",
+
+			"create table meetingsecretary (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: member id, y: meeting id
+			"create index meetingsecretary_x_start_tl on meetingsecretary(x, start_tl, end_tl DESC)",
+			"create index meetingsecretary_y_start_tl on meetingsecretary(y, start_tl, end_tl DESC)",
+		},
+	},
+	{
+		Stmts: []string{
+			// nextactions are json serialized
+			"create table project (id uuid, start_tl bigint, end_tl bigint, title varchar, description varchar, status varchar, nextactions varchar, PRIMARY KEY (id, start_tl))",
+			"create unique index project_tl on project(id, start_tl, end_tl DESC)",
+
+			"create table roleproject (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: project id, y: role id
+			"create index roleproject_x_start_tl on roleproject(x, start_tl, end_tl DESC)",
+			"create index roleproject_y_start_tl on roleproject(y, start_tl, end_tl DESC)",
+
+			"create table memberproject (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: project id, y: member id
+			"create index memberproject_x_start_tl on memberproject(x, start_tl, end_tl DESC)",
+			"create index memberproject_y_start_tl on memberproject(y, start_tl, end_tl DESC)",
+		},
+	},
+	{
+		Stmts: []string{
+			// values are json serialized
+			"create table reportitem (id uuid, start_tl bigint, end_tl bigint, reportitemtype varchar, description varchar, frequency varchar, reportvalues varchar, PRIMARY KEY (id, start_tl))",
+			"create unique index reportitem_tl on reportitem(id, start_tl, end_tl DESC)",
+
+			"create table circlereportitem (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: reportitem id, y: circle role id
+			"create index circlereportitem_x_start_tl on circlereportitem(x, start_tl, end_tl DESC)",
+			"create index circlereportitem_y_start_tl on circlereportitem(y, start_tl, end_tl DESC)",
+
+			"create table rolereportitem (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: reportitem id, y: role id
+			"create index rolereportitem_x_start_tl on rolereportitem(x, start_tl, end_tl DESC)",
+			"create index rolereportitem_y_start_tl on rolereportitem(y, start_tl, end_tl DESC)",
+		},
+	},
+	{
+		Stmts: []string{
+			// events metadata, used to explore the events causation
+			"create table eventmetadata (sequencenumber bigint, id uuid, timeline bigint, eventtype varchar, aggregatetype varchar, aggregateid varchar, timestamp timestamptz, correlationid uuid, causationid uuid, commandcausationid uuid, groupid uuid, commandissuerid uuid, PRIMARY KEY(id))",
+			"create index eventmetadata_correlationid on eventmetadata(correlationid)",
+			"create index eventmetadata_groupid on eventmetadata(groupid)",
+			"create index eventmetadata_timeline on eventmetadata(timeline)",
+		},
+	},
+	{
+		Stmts: []string{
+			// the commandevent table was never populated, the executed
+			// commands are recorded in the eventstore command audit log
+			"drop table commandevent",
+		},
+	},
+	{
+		Stmts: []string{
+			// the memberevent table was never populated, recreate it
+			// with the event sequence number used to order the member
+			// events
+			"drop table memberevent",
+			"create table memberevent (sequencenumber bigint, timeline bigint, id uuid, memberid uuid, eventtype varchar, data bytea, PRIMARY KEY(id))",
+			"create index memberevent_memberid on memberevent(memberid, sequencenumber)",
+		},
+	},
+	{
+		Stmts: []string{
+			// labels and comments are json serialized
+			"alter table tension add column state varchar not null default 'open'",
+			"alter table tension add column labels varchar not null default '[]'",
+			"alter table tension add column comments varchar not null default '[]'",
+			"update tension set state = 'closed' where closed",
+
+			"create table tensionassignee (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: tension id, y: member id
+			"create index tensionassignee_x_start_tl on tensionassignee(x, start_tl, end_tl DESC)",
+			"create index tensionassignee_y_start_tl on tensionassignee(y, start_tl, end_tl DESC)",
+
+			"create table tensionassigneerole (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: tension id, y: role id
+			"create index tensionassigneerole_x_start_tl on tensionassigneerole(x, start_tl, end_tl DESC)",
+			"create index tensionassigneerole_y_start_tl on tensionassigneerole(y, start_tl, end_tl DESC)",
+		},
+	},
+	{
+		Stmts: []string{
+			// project next actions are now vertices connected to their
+			// project. The old json serialized project nextactions column
+			// isn't used anymore: readdbs containing projects with next
+			// actions must be rebuilt with the rebuild-readdb command.
+			"create table nextaction (id uuid, start_tl bigint, end_tl bigint, number int, description varchar, completed bool, PRIMARY KEY (id, start_tl))",
+			"create unique index nextaction_tl on nextaction(id, start_tl, end_tl DESC)",
+
+			"create table projectnextaction (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: nextaction id, y: project id
+			"create index projectnextaction_x_start_tl on projectnextaction(x, start_tl, end_tl DESC)",
+			"create index projectnextaction_y_start_tl on projectnextaction(y, start_tl, end_tl DESC)",
+		},
+	},
+	{
+		Stmts: []string{
+			// meeting agenda items are now vertices connected to their
+			// meeting and to the discussed tension. The old json serialized
+			// meeting agendaitems column isn't used anymore: readdbs
+			// containing meetings with agenda items must be rebuilt with the
+			// rebuild-readdb command.
+			"create table agendaitem (id uuid, start_tl bigint, end_tl bigint, number int, title varchar, outcome varchar, PRIMARY KEY (id, start_tl))",
+			"create unique index agendaitem_tl on agendaitem(id, start_tl, end_tl DESC)",
+
+			"create table meetingagendaitem (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: agendaitem id, y: meeting id
+			"create index meetingagendaitem_x_start_tl on meetingagendaitem(x, start_tl, end_tl DESC)",
+			"create index meetingagendaitem_y_start_tl on meetingagendaitem(y, start_tl, end_tl DESC)",
+
+			"create table agendaitemtension (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: agendaitem id, y: tension id
+			"create index agendaitemtension_x_start_tl on agendaitemtension(x, start_tl, end_tl DESC)",
+			"create index agendaitemtension_y_start_tl on agendaitemtension(y, start_tl, end_tl DESC)",
+		},
+	},
+	{
+		Stmts: []string{
+			// report item values are now vertices connected to their
+			// report item. The old json serialized reportitem reportvalues
+			// column isn't used anymore: readdbs containing report items
+			// with values must be rebuilt with the rebuild-readdb command.
+			"create table reportvalue (id uuid, start_tl bigint, end_tl bigint, period timestamptz, value double precision, PRIMARY KEY (id, start_tl))",
+			"create unique index reportvalue_tl on reportvalue(id, start_tl, end_tl DESC)",
+
+			"create table reportitemvalue (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: reportvalue id, y: reportitem id
+			"create index reportitemvalue_x_start_tl on reportitemvalue(x, start_tl, end_tl DESC)",
+			"create index reportitemvalue_y_start_tl on reportitemvalue(y, start_tl, end_tl DESC)",
+		},
+	},
+	{
+		Stmts: []string{
+			// tension comments and labels are now vertices connected to
+			// their tension. The old json serialized tension comments and
+			// labels columns aren't used anymore: readdbs containing tensions
+			// with comments or labels must be rebuilt with the
+			// rebuild-readdb command.
+			"create table comment (id uuid, start_tl bigint, end_tl bigint, number int, text varchar, timeline bigint, PRIMARY KEY (id, start_tl))",
+			"create unique index comment_tl on comment(id, start_tl, end_tl DESC)",
+
+			"create table label (id uuid, start_tl bigint, end_tl bigint, number int, name varchar, PRIMARY KEY (id, start_tl))",
+			"create unique index label_tl on label(id, start_tl, end_tl DESC)",
+
+			"create table tensioncomment (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: comment id, y: tension id
+			"create index tensioncomment_x_start_tl on tensioncomment(x, start_tl, end_tl DESC)",
+			"create index tensioncomment_y_start_tl on tensioncomment(y, start_tl, end_tl DESC)",
+
+			"create table commentreply (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: reply comment id, y: parent comment id
+			"create index commentreply_x_start_tl on commentreply(x, start_tl, end_tl DESC)",
+			"create index commentreply_y_start_tl on commentreply(y, start_tl, end_tl DESC)",
+
+			"create table commentmember (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: comment id, y: member id
+			"create index commentmember_x_start_tl on commentmember(x, start_tl, end_tl DESC)",
+			"create index commentmember_y_start_tl on commentmember(y, start_tl, end_tl DESC)",
+
+			"create table tensionlabel (start_tl bigint, end_tl bigint, x uuid, y uuid)", // x: label id, y: tension id
+			"create index tensionlabel_x_start_tl on tensionlabel(x, start_tl, end_tl DESC)",
+			"create index tensionlabel_y_start_tl on tensionlabel(y, start_tl, end_tl DESC)",
+		},
+	},
 }
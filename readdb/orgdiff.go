@@ -0,0 +1,369 @@
+package readdb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+
+	"github.com/pkg/errors"
+)
+
+type roleMemberKey struct {
+	roleID   util.ID
+	memberID util.ID
+}
+
+type coreRoleKey struct {
+	circleID util.ID
+	roleType models.RoleType
+}
+
+type coreRoleMember struct {
+	circle     *models.Role
+	roleMember *models.RoleMemberEdge
+}
+
+// orgState is the state of a roles subtree at a timeline
+type orgState struct {
+	roles map[util.ID]*models.Role
+	// parents contains the parent of every role, including the subtree
+	// root parent
+	parents map[util.ID]*models.Role
+
+	domains              map[util.ID]*models.Domain
+	domainsRole          map[util.ID]util.ID
+	accountabilities     map[util.ID]*models.Accountability
+	accountabilitiesRole map[util.ID]util.ID
+	roleMembers          map[roleMemberKey]*models.RoleMemberEdge
+	coreRoleMembers      map[coreRoleKey]*coreRoleMember
+}
+
+func newOrgState() *orgState {
+	return &orgState{
+		roles:                map[util.ID]*models.Role{},
+		parents:              map[util.ID]*models.Role{},
+		domains:              map[util.ID]*models.Domain{},
+		domainsRole:          map[util.ID]util.ID{},
+		accountabilities:     map[util.ID]*models.Accountability{},
+		accountabilitiesRole: map[util.ID]util.ID{},
+		roleMembers:          map[roleMemberKey]*models.RoleMemberEdge{},
+		coreRoleMembers:      map[coreRoleKey]*coreRoleMember{},
+	}
+}
+
+// orgState loads the subtree starting at the role with the provided id (or at
+// the root role if nil). If the role doesn't exist at the provided timeline
+// an empty state is returned.
+func (s *readDBService) orgState(ctx context.Context, tl util.TimeLineNumber, rootRoleID *util.ID) (*orgState, error) {
+	st := newOrgState()
+
+	var root *models.Role
+	var err error
+	if rootRoleID == nil {
+		root, err = s.RootRole(ctx, tl)
+	} else {
+		root, err = s.Role(ctx, tl, *rootRoleID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return st, nil
+	}
+
+	st.roles[root.ID] = root
+	rootParent, err := s.RoleParent(ctx, tl, []util.ID{root.ID})
+	if err != nil {
+		return nil, err
+	}
+	if parent, ok := rootParent[root.ID]; ok {
+		st.parents[root.ID] = parent
+	}
+
+	rolesIDs := []util.ID{root.ID}
+	parentsIDs := []util.ID{root.ID}
+	for len(parentsIDs) > 0 {
+		childsGroups, err := s.ChildRoles(ctx, tl, parentsIDs, nil)
+		if err != nil {
+			return nil, err
+		}
+		childsIDs := []util.ID{}
+		for _, parentID := range parentsIDs {
+			for _, child := range childsGroups[parentID] {
+				st.roles[child.ID] = child
+				st.parents[child.ID] = st.roles[parentID]
+				childsIDs = append(childsIDs, child.ID)
+			}
+		}
+		rolesIDs = append(rolesIDs, childsIDs...)
+		parentsIDs = childsIDs
+	}
+
+	domainsGroups, err := s.RoleDomains(ctx, tl, rolesIDs)
+	if err != nil {
+		return nil, err
+	}
+	for roleID, domains := range domainsGroups {
+		for _, domain := range domains {
+			st.domains[domain.ID] = domain
+			st.domainsRole[domain.ID] = roleID
+		}
+	}
+
+	accountabilitiesGroups, err := s.RoleAccountabilities(ctx, tl, rolesIDs)
+	if err != nil {
+		return nil, err
+	}
+	for roleID, accountabilities := range accountabilitiesGroups {
+		for _, accountability := range accountabilities {
+			st.accountabilities[accountability.ID] = accountability
+			st.accountabilitiesRole[accountability.ID] = roleID
+		}
+	}
+
+	roleMemberEdgesGroups, err := s.RoleMemberEdges(ctx, tl, rolesIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+	for roleID, roleMemberEdges := range roleMemberEdgesGroups {
+		for _, roleMemberEdge := range roleMemberEdges {
+			st.roleMembers[roleMemberKey{roleID: roleID, memberID: roleMemberEdge.Member.ID}] = roleMemberEdge
+		}
+
+		role := st.roles[roleID]
+		circle, ok := st.parents[roleID]
+		if !ok || !role.RoleType.IsCoreRoleType() || len(roleMemberEdges) == 0 {
+			continue
+		}
+		// NOTE(sgotti): there must be only one member filling a core role
+		st.coreRoleMembers[coreRoleKey{circleID: circle.ID, roleType: role.RoleType}] = &coreRoleMember{circle: circle, roleMember: roleMemberEdges[0]}
+	}
+
+	return st, nil
+}
+
+// OrgDiff returns the changes of the organization, or of the subtree starting
+// at the role with the provided id, between the two timelines
+func (s *readDBService) OrgDiff(ctx context.Context, fromTl, toTl util.TimeLineNumber, rootRoleID *util.ID) (*models.OrgDiff, error) {
+	if fromTl <= 0 || toTl <= 0 {
+		return nil, errors.Errorf("wrong timelines %d, %d", fromTl, toTl)
+	}
+
+	prev, err := s.orgState(ctx, fromTl, rootRoleID)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := s.orgState(ctx, toTl, rootRoleID)
+	if err != nil {
+		return nil, err
+	}
+	if rootRoleID != nil && len(prev.roles) == 0 && len(cur.roles) == 0 {
+		return nil, errors.Errorf("role with id %s doesn't exist", *rootRoleID)
+	}
+
+	diff := &models.OrgDiff{
+		FromTl:           fromTl,
+		ToTl:             toTl,
+		Roles:            []*models.RoleDiff{},
+		Domains:          []*models.DomainDiff{},
+		Accountabilities: []*models.AccountabilityDiff{},
+		RoleMembers:      []*models.RoleMemberDiff{},
+		CoreRoles:        []*models.CoreRoleDiff{},
+	}
+
+	for id, prevRole := range prev.roles {
+		role, ok := cur.roles[id]
+		if !ok {
+			diff.Roles = append(diff.Roles, &models.RoleDiff{ChangeType: models.ChangeTypeDeleted, PreviousRole: prevRole, PreviousParent: prev.parents[id]})
+			continue
+		}
+		if role.RoleType != prevRole.RoleType || role.Name != prevRole.Name || role.Purpose != prevRole.Purpose || !sameRole(cur.parents[id], prev.parents[id]) {
+			diff.Roles = append(diff.Roles, &models.RoleDiff{ChangeType: models.ChangeTypeUpdated, Role: role, PreviousRole: prevRole, Parent: cur.parents[id], PreviousParent: prev.parents[id]})
+		}
+	}
+	for id, role := range cur.roles {
+		if _, ok := prev.roles[id]; !ok {
+			diff.Roles = append(diff.Roles, &models.RoleDiff{ChangeType: models.ChangeTypeNew, Role: role, Parent: cur.parents[id]})
+		}
+	}
+
+	for id, prevDomain := range prev.domains {
+		prevRole := prev.roles[prev.domainsRole[id]]
+		domain, ok := cur.domains[id]
+		if !ok {
+			diff.Domains = append(diff.Domains, &models.DomainDiff{ChangeType: models.ChangeTypeDeleted, PreviousRole: prevRole, PreviousDomain: prevDomain})
+			continue
+		}
+		role := cur.roles[cur.domainsRole[id]]
+		if domain.Description != prevDomain.Description || role.ID != prevRole.ID {
+			diff.Domains = append(diff.Domains, &models.DomainDiff{ChangeType: models.ChangeTypeUpdated, Role: role, PreviousRole: prevRole, Domain: domain, PreviousDomain: prevDomain})
+		}
+	}
+	for id, domain := range cur.domains {
+		if _, ok := prev.domains[id]; !ok {
+			diff.Domains = append(diff.Domains, &models.DomainDiff{ChangeType: models.ChangeTypeNew, Role: cur.roles[cur.domainsRole[id]], Domain: domain})
+		}
+	}
+
+	for id, prevAccountability := range prev.accountabilities {
+		prevRole := prev.roles[prev.accountabilitiesRole[id]]
+		accountability, ok := cur.accountabilities[id]
+		if !ok {
+			diff.Accountabilities = append(diff.Accountabilities, &models.AccountabilityDiff{ChangeType: models.ChangeTypeDeleted, PreviousRole: prevRole, PreviousAccountability: prevAccountability})
+			continue
+		}
+		role := cur.roles[cur.accountabilitiesRole[id]]
+		if accountability.Description != prevAccountability.Description || role.ID != prevRole.ID {
+			diff.Accountabilities = append(diff.Accountabilities, &models.AccountabilityDiff{ChangeType: models.ChangeTypeUpdated, Role: role, PreviousRole: prevRole, Accountability: accountability, PreviousAccountability: prevAccountability})
+		}
+	}
+	for id, accountability := range cur.accountabilities {
+		if _, ok := prev.accountabilities[id]; !ok {
+			diff.Accountabilities = append(diff.Accountabilities, &models.AccountabilityDiff{ChangeType: models.ChangeTypeNew, Role: cur.roles[cur.accountabilitiesRole[id]], Accountability: accountability})
+		}
+	}
+
+	for k, prevRoleMember := range prev.roleMembers {
+		prevRole := prev.roles[k.roleID]
+		roleMember, ok := cur.roleMembers[k]
+		if !ok {
+			diff.RoleMembers = append(diff.RoleMembers, &models.RoleMemberDiff{ChangeType: models.ChangeTypeDeleted, PreviousRole: prevRole, PreviousRoleMember: prevRoleMember})
+			continue
+		}
+		if !sameRoleMemberEdge(roleMember, prevRoleMember) {
+			diff.RoleMembers = append(diff.RoleMembers, &models.RoleMemberDiff{ChangeType: models.ChangeTypeUpdated, Role: cur.roles[k.roleID], PreviousRole: prevRole, RoleMember: roleMember, PreviousRoleMember: prevRoleMember})
+		}
+	}
+	for k, roleMember := range cur.roleMembers {
+		if _, ok := prev.roleMembers[k]; !ok {
+			diff.RoleMembers = append(diff.RoleMembers, &models.RoleMemberDiff{ChangeType: models.ChangeTypeNew, Role: cur.roles[k.roleID], RoleMember: roleMember})
+		}
+	}
+
+	for k, prevCoreRoleMember := range prev.coreRoleMembers {
+		coreRoleMember, ok := cur.coreRoleMembers[k]
+		if !ok {
+			diff.CoreRoles = append(diff.CoreRoles, &models.CoreRoleDiff{ChangeType: models.ChangeTypeDeleted, RoleType: k.roleType, PreviousCircle: prevCoreRoleMember.circle, PreviousRoleMember: prevCoreRoleMember.roleMember})
+			continue
+		}
+		if coreRoleMember.roleMember.Member.ID != prevCoreRoleMember.roleMember.Member.ID || !sameTime(coreRoleMember.roleMember.ElectionExpiration, prevCoreRoleMember.roleMember.ElectionExpiration) {
+			diff.CoreRoles = append(diff.CoreRoles, &models.CoreRoleDiff{ChangeType: models.ChangeTypeUpdated, RoleType: k.roleType, Circle: coreRoleMember.circle, PreviousCircle: prevCoreRoleMember.circle, RoleMember: coreRoleMember.roleMember, PreviousRoleMember: prevCoreRoleMember.roleMember})
+		}
+	}
+	for k, coreRoleMember := range cur.coreRoleMembers {
+		if _, ok := prev.coreRoleMembers[k]; !ok {
+			diff.CoreRoles = append(diff.CoreRoles, &models.CoreRoleDiff{ChangeType: models.ChangeTypeNew, RoleType: k.roleType, Circle: coreRoleMember.circle, RoleMember: coreRoleMember.roleMember})
+		}
+	}
+
+	sortOrgDiff(diff)
+
+	return diff, nil
+}
+
+// sortOrgDiff sorts the diff entries by role name and then by entity to get
+// repeatable ordered results
+func sortOrgDiff(diff *models.OrgDiff) {
+	sort.Slice(diff.Roles, func(i, j int) bool {
+		ri, rj := diffRole(diff.Roles[i].Role, diff.Roles[i].PreviousRole), diffRole(diff.Roles[j].Role, diff.Roles[j].PreviousRole)
+		if ri.Name != rj.Name {
+			return ri.Name < rj.Name
+		}
+		return ri.ID.String() < rj.ID.String()
+	})
+	sort.Slice(diff.Domains, func(i, j int) bool {
+		ri, rj := diffRole(diff.Domains[i].Role, diff.Domains[i].PreviousRole), diffRole(diff.Domains[j].Role, diff.Domains[j].PreviousRole)
+		if ri.Name != rj.Name {
+			return ri.Name < rj.Name
+		}
+		di, dj := diff.Domains[i].Domain, diff.Domains[j].Domain
+		if di == nil {
+			di = diff.Domains[i].PreviousDomain
+		}
+		if dj == nil {
+			dj = diff.Domains[j].PreviousDomain
+		}
+		if di.Description != dj.Description {
+			return di.Description < dj.Description
+		}
+		return di.ID.String() < dj.ID.String()
+	})
+	sort.Slice(diff.Accountabilities, func(i, j int) bool {
+		ri, rj := diffRole(diff.Accountabilities[i].Role, diff.Accountabilities[i].PreviousRole), diffRole(diff.Accountabilities[j].Role, diff.Accountabilities[j].PreviousRole)
+		if ri.Name != rj.Name {
+			return ri.Name < rj.Name
+		}
+		ai, aj := diff.Accountabilities[i].Accountability, diff.Accountabilities[j].Accountability
+		if ai == nil {
+			ai = diff.Accountabilities[i].PreviousAccountability
+		}
+		if aj == nil {
+			aj = diff.Accountabilities[j].PreviousAccountability
+		}
+		if ai.Description != aj.Description {
+			return ai.Description < aj.Description
+		}
+		return ai.ID.String() < aj.ID.String()
+	})
+	sort.Slice(diff.RoleMembers, func(i, j int) bool {
+		ri, rj := diffRole(diff.RoleMembers[i].Role, diff.RoleMembers[i].PreviousRole), diffRole(diff.RoleMembers[j].Role, diff.RoleMembers[j].PreviousRole)
+		if ri.Name != rj.Name {
+			return ri.Name < rj.Name
+		}
+		if ri.ID != rj.ID {
+			return ri.ID.String() < rj.ID.String()
+		}
+		mi, mj := diff.RoleMembers[i].RoleMember, diff.RoleMembers[j].RoleMember
+		if mi == nil {
+			mi = diff.RoleMembers[i].PreviousRoleMember
+		}
+		if mj == nil {
+			mj = diff.RoleMembers[j].PreviousRoleMember
+		}
+		return mi.Member.ID.String() < mj.Member.ID.String()
+	})
+	sort.Slice(diff.CoreRoles, func(i, j int) bool {
+		ci, cj := diffRole(diff.CoreRoles[i].Circle, diff.CoreRoles[i].PreviousCircle), diffRole(diff.CoreRoles[j].Circle, diff.CoreRoles[j].PreviousCircle)
+		if ci.Name != cj.Name {
+			return ci.Name < cj.Name
+		}
+		if ci.ID != cj.ID {
+			return ci.ID.String() < cj.ID.String()
+		}
+		return diff.CoreRoles[i].RoleType < diff.CoreRoles[j].RoleType
+	})
+}
+
+func diffRole(role, previousRole *models.Role) *models.Role {
+	if role != nil {
+		return role
+	}
+	return previousRole
+}
+
+func sameRole(r1, r2 *models.Role) bool {
+	if r1 == nil || r2 == nil {
+		return r1 == r2
+	}
+	return r1.ID == r2.ID
+}
+
+func sameRoleMemberEdge(e1, e2 *models.RoleMemberEdge) bool {
+	if e1.NoCoreMember != e2.NoCoreMember {
+		return false
+	}
+	if (e1.Focus == nil) != (e2.Focus == nil) || (e1.Focus != nil && *e1.Focus != *e2.Focus) {
+		return false
+	}
+	return sameTime(e1.ElectionExpiration, e2.ElectionExpiration)
+}
+
+func sameTime(t1, t2 *time.Time) bool {
+	if t1 == nil || t2 == nil {
+		return t1 == t2
+	}
+	return t1.Equal(*t2)
+}
@@ -0,0 +1,357 @@
+package readdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sorintlab/sircles/db"
+	"github.com/sorintlab/sircles/models"
+	"github.com/sorintlab/sircles/util"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+)
+
+// Page defines a page of an ordered list using the relay cursor connections
+// arguments. Since the read db content at a timeline is immutable, the
+// After and Before cursors are the positions, starting from 1, of the items
+// in the list at the queried timeline. A zero value means not provided.
+type Page struct {
+	First  int
+	After  int
+	Last   int
+	Before int
+}
+
+func (p *Page) Validate() error {
+	if p.First < 0 || p.Last < 0 {
+		return errors.Errorf("first and last cannot be negative")
+	}
+	if p.First > 0 && p.Last > 0 {
+		return errors.Errorf("first and last cannot be used together")
+	}
+	if p.After < 0 || p.Before < 0 {
+		return errors.Errorf("invalid cursor")
+	}
+	return nil
+}
+
+// bounds returns the positions of the first and the last items of the page in
+// a list of totalCount items. The page is empty when start is greater than end.
+// When neither first or last are provided the page contains at most
+// MaxFetchSize items.
+func (p *Page) bounds(totalCount int) (int, int) {
+	first := p.First
+	if first == 0 && p.Last == 0 {
+		first = MaxFetchSize
+	}
+
+	start, end := 1, totalCount
+	if p.After > 0 && p.After+1 > start {
+		start = p.After + 1
+	}
+	if p.Before > 0 && p.Before-1 < end {
+		end = p.Before - 1
+	}
+	if first > 0 && start+first-1 < end {
+		end = start + first - 1
+	}
+	if p.Last > 0 && end-p.Last+1 > start {
+		start = end - p.Last + 1
+	}
+	return start, end
+}
+
+// connectedVerticesPage returns, for every provided vertex, the requested page
+// of its connected vertices and the page info. orderBys must define a total
+// order of the connected vertices.
+//
+// The connected vertices are counted with a first query, then a second query
+// numbers them with a window function partitioned by the provided vertex and
+// returns only the rows inside every vertex page.
+func (s *readDBService) connectedVerticesPage(tl util.TimeLineNumber, vertexID []util.ID, ec edgeClass, direction edgeDirection, outputVertexClass vertexClass, condition interface{}, orderBys []string, page *Page) (interface{}, map[util.ID]models.PageInfo, error) {
+	vsb, vc, outputVertexClass, groupColumn := s.connectedVerticesSelect(tl, vertexID, ec, direction, outputVertexClass, condition)
+	// the subqueries placeholders will be converted by the outer query
+	vsb = vsb.PlaceholderFormat(sq.Question)
+
+	cq, args, err := sb.Select(groupColumn, "count(*)").FromSelect(vsb, "pagecount").GroupBy(groupColumn).ToSql()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to build query")
+	}
+
+	counts := map[util.ID]int{}
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(cq, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var group util.ID
+			var count int
+			if err := rows.Scan(&group, &count); err != nil {
+				return errors.Wrap(err, "failed to scan rows")
+			}
+			counts[group] = count
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pageInfos := map[util.ID]models.PageInfo{}
+	pagesCond := sq.Or{}
+	for _, id := range vertexID {
+		start, end := page.bounds(counts[id])
+		pageInfos[id] = models.PageInfo{Start: start, TotalCount: counts[id]}
+		if start <= end {
+			pagesCond = append(pagesCond, sq.And{sq.Eq{groupColumn: id}, sq.GtOrEq{"rn": start}, sq.LtOrEq{"rn": end}})
+		}
+	}
+	if len(pagesCond) == 0 {
+		return nil, pageInfos, nil
+	}
+
+	vsb = vsb.Column(fmt.Sprintf("row_number() over (partition by %s.%s order by %s) as rn", ec.String(), groupColumn, strings.Join(orderBys, ", ")))
+	q, args, err := sb.Select("*").FromSelect(vsb, "page").Where(pagesCond).OrderBy(groupColumn, "rn").ToSql()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to build query")
+	}
+
+	var res interface{}
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return err
+		}
+
+		switch outputVertexClass {
+		case vertexClassRole:
+			res, err = scanRolesPageGroups(rows)
+		case vertexClassRoleMemberEdge:
+			if vc == vertexClassRole {
+				res, err = scanMemberRoleEdgesPageGroups(rows)
+			} else {
+				return errors.Errorf("unsupported vertex class: %q", vc)
+			}
+		case vertexClassTension:
+			res, err = scanTensionsPageGroups(rows)
+		default:
+			return errors.Errorf("unsupported vertex class: %q", outputVertexClass)
+		}
+		return err
+	})
+
+	return res, pageInfos, err
+}
+
+func scanRolesPageGroups(rows *sql.Rows) (map[util.ID][]*models.Role, error) {
+	roles := map[util.ID][]*models.Role{}
+	for rows.Next() {
+		var group util.ID
+		var rn int
+		r, err := scanRole(rows, &group, &rn)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		roles[group] = append(roles[group], r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func scanMemberRoleEdgesPageGroups(rows *sql.Rows) (map[util.ID][]*models.MemberRoleEdge, error) {
+	memberRoleEdgesGroups := map[util.ID][]*models.MemberRoleEdge{}
+	for rows.Next() {
+		var group util.ID
+		var rn int
+		r, err := scanMemberRoleEdge(rows, &group, &rn)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		memberRoleEdgesGroups[group] = append(memberRoleEdgesGroups[group], r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return memberRoleEdgesGroups, nil
+}
+
+func scanTensionsPageGroups(rows *sql.Rows) (map[util.ID][]*models.Tension, error) {
+	tensionsGroups := map[util.ID][]*models.Tension{}
+	for rows.Next() {
+		var group util.ID
+		var rn int
+		t, err := scanTension(rows, &group, &rn)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tensionsGroups[group] = append(tensionsGroups[group], t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tensionsGroups, nil
+}
+
+// RolesPage returns a page of the roles matching the provided filter ordered
+// by the provided order (by name if nil)
+func (s *readDBService) RolesPage(ctx context.Context, tl util.TimeLineNumber, filter *RoleFilter, order *RoleOrder, page *Page) (*models.RolesPage, error) {
+	cond, err := s.roleFilterCond(ctx, tl, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	cq, args, err := sb.Select("count(*)").From(vertexClassRole.String()).Where(s.timeLineCond(vertexClassRole.String(), tl)).Where(cond).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	var count int
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow(cq, args...).Scan(&count)
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to execute query")
+	}
+
+	rolesPage := &models.RolesPage{
+		PageInfo: models.PageInfo{TotalCount: count},
+		Roles:    []*models.Role{},
+	}
+	start, end := page.bounds(count)
+	rolesPage.Start = start
+	if start > end {
+		return rolesPage, nil
+	}
+
+	q, args, err := roleSelect.Where(s.timeLineCond(vertexClassRole.String(), tl)).Where(cond).OrderBy(roleOrderBys(order)...).Limit(uint64(end - start + 1)).Offset(uint64(start - 1)).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
+	}
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return errors.WithMessage(err, "failed to execute query")
+		}
+		rolesPage.Roles, err = scanRoles(rows)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rolesPage, nil
+}
+
+// ChildRolesPage returns, for every provided role, a page of its child roles
+// ordered by name
+func (s *readDBService) ChildRolesPage(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID, page *Page) (map[util.ID]*models.RolesPage, error) {
+	vs, pageInfos, err := s.connectedVerticesPage(tl, rolesIDs, edgeClassRoleRole, edgeDirectionOut, "", nil, []string{"role.name", "role.id"}, page)
+	if err != nil {
+		return nil, err
+	}
+	rolesGroups, _ := vs.(map[util.ID][]*models.Role)
+
+	pages := map[util.ID]*models.RolesPage{}
+	for _, roleID := range rolesIDs {
+		roles := rolesGroups[roleID]
+		if roles == nil {
+			roles = []*models.Role{}
+		}
+		pages[roleID] = &models.RolesPage{PageInfo: pageInfos[roleID], Roles: roles}
+	}
+	return pages, nil
+}
+
+// MemberRoleEdgesPage returns, for every provided member, a page of its
+// filled roles ordered by role name
+func (s *readDBService) MemberRoleEdgesPage(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID, page *Page) (map[util.ID]*models.MemberRoleEdgesPage, error) {
+	vs, pageInfos, err := s.connectedVerticesPage(tl, membersIDs, edgeClassRoleMember, edgeDirectionOut, vertexClassRoleMemberEdge, nil, []string{"role.name", "role.id"}, page)
+	if err != nil {
+		return nil, err
+	}
+	memberRoleEdgesGroups, _ := vs.(map[util.ID][]*models.MemberRoleEdge)
+
+	pages := map[util.ID]*models.MemberRoleEdgesPage{}
+	for _, memberID := range membersIDs {
+		memberRoleEdges := memberRoleEdgesGroups[memberID]
+		if memberRoleEdges == nil {
+			memberRoleEdges = []*models.MemberRoleEdge{}
+		}
+		pages[memberID] = &models.MemberRoleEdgesPage{PageInfo: pageInfos[memberID], MemberRoleEdges: memberRoleEdges}
+	}
+	return pages, nil
+}
+
+func (s *readDBService) tensionsPage(tl util.TimeLineNumber, ids []util.ID, ec edgeClass, page *Page) (map[util.ID]*models.TensionsPage, error) {
+	vs, pageInfos, err := s.connectedVerticesPage(tl, ids, ec, edgeDirectionIn, "", nil, []string{"tension.title", "tension.id"}, page)
+	if err != nil {
+		return nil, err
+	}
+	tensionsGroups, _ := vs.(map[util.ID][]*models.Tension)
+
+	pages := map[util.ID]*models.TensionsPage{}
+	for _, id := range ids {
+		tensions := tensionsGroups[id]
+		if tensions == nil {
+			tensions = []*models.Tension{}
+		}
+		pages[id] = &models.TensionsPage{PageInfo: pageInfos[id], Tensions: tensions}
+	}
+	return pages, nil
+}
+
+// MemberTensionsPage returns, for every provided member, a page of its
+// tensions ordered by title. Like MemberTensions only the calling member
+// tensions are returned.
+func (s *readDBService) MemberTensionsPage(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID, page *Page) (map[util.ID]*models.TensionsPage, error) {
+	member, err := s.CallingMember(ctx, tl)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, memberID := range membersIDs {
+		if member.ID == memberID {
+			return s.tensionsPage(tl, []util.ID{memberID}, edgeClassMemberTension, page)
+		}
+	}
+	return nil, nil
+}
+
+// RoleTensionsPage returns, for every provided role, a page of its tensions
+// ordered by title
+func (s *readDBService) RoleTensionsPage(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID, page *Page) (map[util.ID]*models.TensionsPage, error) {
+	return s.tensionsPage(tl, rolesIDs, edgeClassRoleTension, page)
+}
+
+// CircleMemberEdgesPage returns, for every provided circle, a page of its
+// members ordered like CircleMemberEdges. Since circle members are calculated
+// from multiple sources, the page is extracted from the whole list.
+func (s *readDBService) CircleMemberEdgesPage(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID, page *Page) (map[util.ID]*models.CircleMemberEdgesPage, error) {
+	circleMemberEdgesGroups, err := s.CircleMemberEdges(ctx, tl, rolesIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := map[util.ID]*models.CircleMemberEdgesPage{}
+	for _, roleID := range rolesIDs {
+		circleMemberEdges := circleMemberEdgesGroups[roleID]
+		start, end := page.bounds(len(circleMemberEdges))
+		p := &models.CircleMemberEdgesPage{
+			PageInfo:          models.PageInfo{Start: start, TotalCount: len(circleMemberEdges)},
+			CircleMemberEdges: []*models.CircleMemberEdge{},
+		}
+		if start <= end {
+			p.CircleMemberEdges = circleMemberEdges[start-1 : end]
+		}
+		pages[roleID] = p
+	}
+	return pages, nil
+}
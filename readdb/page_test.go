@@ -0,0 +1,33 @@
+package readdb
+
+import "testing"
+
+func TestPageBounds(t *testing.T) {
+	tests := []struct {
+		page       Page
+		totalCount int
+		start      int
+		end        int
+	}{
+		// default page size
+		{page: Page{}, totalCount: 100, start: 1, end: MaxFetchSize},
+		{page: Page{}, totalCount: 0, start: 1, end: 0},
+		{page: Page{First: 2}, totalCount: 10, start: 1, end: 2},
+		{page: Page{First: 2, After: 2}, totalCount: 10, start: 3, end: 4},
+		{page: Page{First: 2, After: 9}, totalCount: 10, start: 10, end: 10},
+		{page: Page{First: 2, After: 10}, totalCount: 10, start: 11, end: 10},
+		{page: Page{First: 5, After: 2, Before: 5}, totalCount: 10, start: 3, end: 4},
+		{page: Page{Last: 2}, totalCount: 10, start: 9, end: 10},
+		{page: Page{Last: 2, Before: 5}, totalCount: 10, start: 3, end: 4},
+		{page: Page{Last: 5, Before: 3}, totalCount: 10, start: 1, end: 2},
+		{page: Page{Last: 2, Before: 1}, totalCount: 10, start: 1, end: 0},
+		{page: Page{Last: 20}, totalCount: 10, start: 1, end: 10},
+	}
+
+	for i, tt := range tests {
+		start, end := tt.page.bounds(tt.totalCount)
+		if start != tt.start || end != tt.end {
+			t.Errorf("#%d: expected bounds %d-%d, got %d-%d", i, tt.start, tt.end, start, end)
+		}
+	}
+}
@@ -39,6 +39,7 @@ type ReadDBService interface {
 	TimeLines(ctx context.Context, ts *time.Time, tl util.TimeLineNumber, limit int, after bool, aggregateType string, aggregateID *util.ID) ([]*util.TimeLine, bool, error)
 	TimeLineAtTimeStamp(ctx context.Context, t time.Time) (*util.TimeLine, error)
 	TimeLineForGroupID(ctx context.Context, groupID util.ID) (*util.TimeLine, error)
+	TimeLineSequenceNumber(ctx context.Context, tl util.TimeLineNumber) (int64, error)
 
 	CallingMember(ctx context.Context, curTl util.TimeLineNumber) (*models.Member, error)
 	RootRole(ctx context.Context, tl util.TimeLineNumber) (*models.Role, error)
@@ -51,8 +52,9 @@ type ReadDBService interface {
 	MemberAvatar(ctx context.Context, tl util.TimeLineNumber, id util.ID) (*models.Avatar, error)
 	Tension(ctx context.Context, tl util.TimeLineNumber, id util.ID) (*models.Tension, error)
 	MembersByIDs(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) ([]*models.Member, error)
-	Members(ctx context.Context, tl util.TimeLineNumber, searchString string, first int, after *string) ([]*models.Member, bool, error)
+	Members(ctx context.Context, tl util.TimeLineNumber, searchString string, filter *MemberFilter, order *MemberOrder, first int, after *util.ID) ([]*models.Member, bool, error)
 	Roles(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) ([]*models.Role, error)
+	FilteredRoles(ctx context.Context, tl util.TimeLineNumber, filter *RoleFilter, order *RoleOrder) ([]*models.Role, error)
 	RolesAdditionalContent(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID]*models.RoleAdditionalContent, error)
 
 	RoleParent(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID]*models.Role, error)
@@ -70,14 +72,68 @@ type ReadDBService interface {
 	RoleAccountabilities(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Accountability, error)
 	RoleTensions(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Tension, error)
 	TensionRole(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID]*models.Role, error)
+	TensionAssignee(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID]*models.Member, error)
+	TensionAssigneeRole(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID]*models.Role, error)
+	TensionComments(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID][]*models.TensionComment, error)
+	CommentParent(ctx context.Context, tl util.TimeLineNumber, commentsIDs []util.ID) (map[util.ID]*models.TensionComment, error)
+	CommentReplies(ctx context.Context, tl util.TimeLineNumber, commentsIDs []util.ID) (map[util.ID][]*models.TensionComment, error)
+	CommentMember(ctx context.Context, tl util.TimeLineNumber, commentsIDs []util.ID) (map[util.ID]*models.Member, error)
+	TensionLabels(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID][]*models.TensionLabel, error)
+
+	RolesPage(ctx context.Context, tl util.TimeLineNumber, filter *RoleFilter, order *RoleOrder, page *Page) (*models.RolesPage, error)
+	ChildRolesPage(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID, page *Page) (map[util.ID]*models.RolesPage, error)
+	MemberRoleEdgesPage(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID, page *Page) (map[util.ID]*models.MemberRoleEdgesPage, error)
+	MemberTensionsPage(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID, page *Page) (map[util.ID]*models.TensionsPage, error)
+	RoleTensionsPage(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID, page *Page) (map[util.ID]*models.TensionsPage, error)
+	CircleMemberEdgesPage(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID, page *Page) (map[util.ID]*models.CircleMemberEdgesPage, error)
+
+	Proposal(ctx context.Context, tl util.TimeLineNumber, id util.ID) (*models.Proposal, error)
+	TensionProposals(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID][]*models.Proposal, error)
+	ProposalTension(ctx context.Context, tl util.TimeLineNumber, proposalsIDs []util.ID) (map[util.ID]*models.Tension, error)
+	ProposalRole(ctx context.Context, tl util.TimeLineNumber, proposalsIDs []util.ID) (map[util.ID]*models.Role, error)
+	ProposalMember(ctx context.Context, tl util.TimeLineNumber, proposalsIDs []util.ID) (map[util.ID]*models.Member, error)
+
+	Meeting(ctx context.Context, tl util.TimeLineNumber, id util.ID) (*models.Meeting, error)
+	RoleMeetings(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Meeting, error)
+	MeetingRole(ctx context.Context, tl util.TimeLineNumber, meetingsIDs []util.ID) (map[util.ID]*models.Role, error)
+	MeetingAttendees(ctx context.Context, tl util.TimeLineNumber, meetingsIDs []util.ID) (map[util.ID][]*models.Member, error)
+	MeetingFacilitator(ctx context.Context, tl util.TimeLineNumber, meetingsIDs []util.ID) (map[util.ID]*models.Member, error)
+	MeetingSecretary(ctx context.Context, tl util.TimeLineNumber, meetingsIDs []util.ID) (map[util.ID]*models.Member, error)
+	MeetingAgendaItems(ctx context.Context, tl util.TimeLineNumber, meetingsIDs []util.ID) (map[util.ID][]*models.MeetingAgendaItem, error)
+	AgendaItemTension(ctx context.Context, tl util.TimeLineNumber, agendaItemsIDs []util.ID) (map[util.ID]*models.Tension, error)
+
+	Project(ctx context.Context, tl util.TimeLineNumber, id util.ID) (*models.Project, error)
+	RoleProjects(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Project, error)
+	MemberProjects(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) (map[util.ID][]*models.Project, error)
+	ProjectRole(ctx context.Context, tl util.TimeLineNumber, projectsIDs []util.ID) (map[util.ID]*models.Role, error)
+	ProjectMember(ctx context.Context, tl util.TimeLineNumber, projectsIDs []util.ID) (map[util.ID]*models.Member, error)
+	ProjectNextActions(ctx context.Context, tl util.TimeLineNumber, projectsIDs []util.ID) (map[util.ID][]*models.NextAction, error)
+
+	ReportItem(ctx context.Context, tl util.TimeLineNumber, id util.ID) (*models.ReportItem, error)
+	CircleReportItems(ctx context.Context, tl util.TimeLineNumber, circlesIDs []util.ID) (map[util.ID][]*models.ReportItem, error)
+	RoleReportItems(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.ReportItem, error)
+	ReportItemCircle(ctx context.Context, tl util.TimeLineNumber, reportItemsIDs []util.ID) (map[util.ID]*models.Role, error)
+	ReportItemRole(ctx context.Context, tl util.TimeLineNumber, reportItemsIDs []util.ID) (map[util.ID]*models.Role, error)
+	ReportItemValues(ctx context.Context, tl util.TimeLineNumber, reportItemsIDs []util.ID) (map[util.ID][]*models.ReportItemValue, error)
 
 	// Auth
 	AuthenticateUIDPassword(ctx context.Context, memberID util.ID, password string) (*models.Member, error)
 	AuthenticateEmailPassword(ctx context.Context, email string, password string) (*models.Member, error)
 
 	MemberCirclePermissions(ctx context.Context, tl util.TimeLineNumber, roleID util.ID) (*models.MemberCirclePermissions, error)
+	MemberTensionPermissions(ctx context.Context, tl util.TimeLineNumber, tensionID util.ID) (*models.MemberTensionPermissions, error)
 
 	RoleEvents(ctx context.Context, roleID util.ID, first int, start, after util.TimeLineNumber) ([]*models.RoleEvent, bool, error)
+	TimeLineRoleEvents(ctx context.Context, tl util.TimeLineNumber) ([]*models.RoleEvent, error)
+	MemberEvents(ctx context.Context, memberID util.ID, first int, start util.TimeLineNumber, after int64) ([]*models.MemberEvent, bool, error)
+
+	Event(ctx context.Context, id util.ID) (*models.Event, error)
+	GroupEvents(ctx context.Context, groupID util.ID) ([]*models.Event, error)
+	TimeLineEvents(ctx context.Context, tl util.TimeLineNumber) ([]*models.Event, error)
+	CausationTree(ctx context.Context, correlationID util.ID) (*models.CausationTree, error)
+	CausationTrees(ctx context.Context, events []*models.Event) ([]*models.CausationTree, error)
+
+	OrgDiff(ctx context.Context, fromTl, toTl util.TimeLineNumber, rootRoleID *util.ID) (*models.OrgDiff, error)
 }
 
 type GenericSqlizer string
@@ -174,6 +230,7 @@ var (
 		"description",
 		"closed",
 		"closereason",
+		"state",
 	}
 
 	tensionAllColumns = append(vertexColumns, tensionColumns...)
@@ -181,6 +238,104 @@ var (
 	tensionSelect = sb.Select(tableColumns(vertexClassTension.String(), tensionAllColumns)...).From(vertexClassTension.String())
 	tensionInsert = sb.Insert(vertexClassTension.String()).Columns(tensionAllColumns...)
 
+	commentColumns = []string{
+		"number",
+		"text",
+		"timeline",
+	}
+
+	commentAllColumns = append(vertexColumns, commentColumns...)
+
+	commentSelect = sb.Select(tableColumns(vertexClassComment.String(), commentAllColumns)...).From(vertexClassComment.String())
+	commentInsert = sb.Insert(vertexClassComment.String()).Columns(commentAllColumns...)
+
+	labelColumns = []string{
+		"number",
+		"name",
+	}
+
+	labelAllColumns = append(vertexColumns, labelColumns...)
+
+	labelSelect = sb.Select(tableColumns(vertexClassLabel.String(), labelAllColumns)...).From(vertexClassLabel.String())
+	labelInsert = sb.Insert(vertexClassLabel.String()).Columns(labelAllColumns...)
+
+	proposalColumns = []string{
+		"state",
+		"changes",
+		"objections",
+	}
+
+	proposalAllColumns = append(vertexColumns, proposalColumns...)
+
+	proposalSelect = sb.Select(tableColumns(vertexClassProposal.String(), proposalAllColumns)...).From(vertexClassProposal.String())
+	proposalInsert = sb.Insert(vertexClassProposal.String()).Columns(proposalAllColumns...)
+
+	meetingColumns = []string{
+		"meetingtype",
+		"title",
+		"starttime",
+		"closed",
+	}
+
+	meetingAllColumns = append(vertexColumns, meetingColumns...)
+
+	meetingSelect = sb.Select(tableColumns(vertexClassMeeting.String(), meetingAllColumns)...).From(vertexClassMeeting.String())
+	meetingInsert = sb.Insert(vertexClassMeeting.String()).Columns(meetingAllColumns...)
+
+	agendaItemColumns = []string{
+		"number",
+		"title",
+		"outcome",
+	}
+
+	agendaItemAllColumns = append(vertexColumns, agendaItemColumns...)
+
+	agendaItemSelect = sb.Select(tableColumns(vertexClassAgendaItem.String(), agendaItemAllColumns)...).From(vertexClassAgendaItem.String())
+	agendaItemInsert = sb.Insert(vertexClassAgendaItem.String()).Columns(agendaItemAllColumns...)
+
+	projectColumns = []string{
+		"title",
+		"description",
+		"status",
+	}
+
+	projectAllColumns = append(vertexColumns, projectColumns...)
+
+	projectSelect = sb.Select(tableColumns(vertexClassProject.String(), projectAllColumns)...).From(vertexClassProject.String())
+	projectInsert = sb.Insert(vertexClassProject.String()).Columns(projectAllColumns...)
+
+	nextActionColumns = []string{
+		"number",
+		"description",
+		"completed",
+	}
+
+	nextActionAllColumns = append(vertexColumns, nextActionColumns...)
+
+	nextActionSelect = sb.Select(tableColumns(vertexClassNextAction.String(), nextActionAllColumns)...).From(vertexClassNextAction.String())
+	nextActionInsert = sb.Insert(vertexClassNextAction.String()).Columns(nextActionAllColumns...)
+
+	reportItemColumns = []string{
+		"reportitemtype",
+		"description",
+		"frequency",
+	}
+
+	reportItemAllColumns = append(vertexColumns, reportItemColumns...)
+
+	reportItemSelect = sb.Select(tableColumns(vertexClassReportItem.String(), reportItemAllColumns)...).From(vertexClassReportItem.String())
+	reportItemInsert = sb.Insert(vertexClassReportItem.String()).Columns(reportItemAllColumns...)
+
+	reportValueColumns = []string{
+		"period",
+		"value",
+	}
+
+	reportValueAllColumns = append(vertexColumns, reportValueColumns...)
+
+	reportValueSelect = sb.Select(tableColumns(vertexClassReportValue.String(), reportValueAllColumns)...).From(vertexClassReportValue.String())
+	reportValueInsert = sb.Insert(vertexClassReportValue.String()).Columns(reportValueAllColumns...)
+
 	roleEventSelect = sb.Select("timeline", "id", "roleid", "eventtype", "data").From("roleevent")
 	roleEventInsert = sb.Insert("roleevent").Columns("timeline", "id", "roleid", "eventtype", "data")
 )
@@ -227,6 +382,15 @@ const (
 	vertexClassRoleMemberEdge        vertexClass = "rolememberedge"
 	vertexClassMemberRoleEdge        vertexClass = "memberroleedge"
 	vertexClassTension               vertexClass = "tension"
+	vertexClassComment               vertexClass = "comment"
+	vertexClassLabel                 vertexClass = "label"
+	vertexClassProposal              vertexClass = "proposal"
+	vertexClassMeeting               vertexClass = "meeting"
+	vertexClassAgendaItem            vertexClass = "agendaitem"
+	vertexClassProject               vertexClass = "project"
+	vertexClassNextAction            vertexClass = "nextaction"
+	vertexClassReportItem            vertexClass = "reportitem"
+	vertexClassReportValue           vertexClass = "reportvalue"
 )
 
 func (vc vertexClass) String() string {
@@ -240,26 +404,56 @@ type edgeClass struct {
 }
 
 var (
-	edgeClassRoleRole           = edgeClass{Name: "rolerole", X: vertexClassRole, Y: vertexClassRole}
-	edgeClassRoleDomain         = edgeClass{Name: "roledomain", X: vertexClassDomain, Y: vertexClassRole}
-	edgeClassRoleAccountability = edgeClass{Name: "roleaccountability", X: vertexClassAccountability, Y: vertexClassRole}
-	edgeClassRoleMember         = edgeClass{Name: "rolemember", X: vertexClassMember, Y: vertexClassRole}
-	edgeClassCircleDirectMember = edgeClass{Name: "circledirectmember", X: vertexClassMember, Y: vertexClassRole}
-	edgeClassMemberTension      = edgeClass{Name: "membertension", X: vertexClassTension, Y: vertexClassMember}
-	edgeClassRoleTension        = edgeClass{Name: "roletension", X: vertexClassTension, Y: vertexClassRole}
+	edgeClassRoleRole            = edgeClass{Name: "rolerole", X: vertexClassRole, Y: vertexClassRole}
+	edgeClassRoleDomain          = edgeClass{Name: "roledomain", X: vertexClassDomain, Y: vertexClassRole}
+	edgeClassRoleAccountability  = edgeClass{Name: "roleaccountability", X: vertexClassAccountability, Y: vertexClassRole}
+	edgeClassRoleMember          = edgeClass{Name: "rolemember", X: vertexClassMember, Y: vertexClassRole}
+	edgeClassCircleDirectMember  = edgeClass{Name: "circledirectmember", X: vertexClassMember, Y: vertexClassRole}
+	edgeClassMemberTension       = edgeClass{Name: "membertension", X: vertexClassTension, Y: vertexClassMember}
+	edgeClassRoleTension         = edgeClass{Name: "roletension", X: vertexClassTension, Y: vertexClassRole}
+	edgeClassTensionProposal     = edgeClass{Name: "tensionproposal", X: vertexClassProposal, Y: vertexClassTension}
+	edgeClassTensionAssignee     = edgeClass{Name: "tensionassignee", X: vertexClassTension, Y: vertexClassMember}
+	edgeClassTensionAssigneeRole = edgeClass{Name: "tensionassigneerole", X: vertexClassTension, Y: vertexClassRole}
+	edgeClassTensionComment      = edgeClass{Name: "tensioncomment", X: vertexClassComment, Y: vertexClassTension}
+	edgeClassCommentReply        = edgeClass{Name: "commentreply", X: vertexClassComment, Y: vertexClassComment}
+	edgeClassCommentMember       = edgeClass{Name: "commentmember", X: vertexClassComment, Y: vertexClassMember}
+	edgeClassTensionLabel        = edgeClass{Name: "tensionlabel", X: vertexClassLabel, Y: vertexClassTension}
+	edgeClassRoleProposal        = edgeClass{Name: "roleproposal", X: vertexClassProposal, Y: vertexClassRole}
+	edgeClassMemberProposal      = edgeClass{Name: "memberproposal", X: vertexClassProposal, Y: vertexClassMember}
+	edgeClassRoleMeeting         = edgeClass{Name: "rolemeeting", X: vertexClassMeeting, Y: vertexClassRole}
+	edgeClassMeetingAttendee     = edgeClass{Name: "meetingattendee", X: vertexClassMember, Y: vertexClassMeeting}
+	edgeClassMeetingFacilitator  = edgeClass{Name: "meetingfacilitator", X: vertexClassMember, Y: vertexClassMeeting}
+	edgeClassMeetingSecretary    = edgeClass{Name: "meetingsecretary", X: vertexClassMember, Y: vertexClassMeeting}
+	edgeClassMeetingAgendaItem   = edgeClass{Name: "meetingagendaitem", X: vertexClassAgendaItem, Y: vertexClassMeeting}
+	edgeClassAgendaItemTension   = edgeClass{Name: "agendaitemtension", X: vertexClassAgendaItem, Y: vertexClassTension}
+	edgeClassRoleProject         = edgeClass{Name: "roleproject", X: vertexClassProject, Y: vertexClassRole}
+	edgeClassMemberProject       = edgeClass{Name: "memberproject", X: vertexClassProject, Y: vertexClassMember}
+	edgeClassProjectNextAction   = edgeClass{Name: "projectnextaction", X: vertexClassNextAction, Y: vertexClassProject}
+	edgeClassCircleReportItem    = edgeClass{Name: "circlereportitem", X: vertexClassReportItem, Y: vertexClassRole}
+	edgeClassRoleReportItem      = edgeClass{Name: "rolereportitem", X: vertexClassReportItem, Y: vertexClassRole}
+	edgeClassReportItemValue     = edgeClass{Name: "reportitemvalue", X: vertexClassReportValue, Y: vertexClassReportItem}
 )
 
 func (ec edgeClass) String() string {
 	return ec.Name
 }
 
-var edgeClasses = []edgeClass{edgeClassRoleRole, edgeClassRoleDomain, edgeClassRoleAccountability, edgeClassRoleMember, edgeClassCircleDirectMember, edgeClassMemberTension, edgeClassRoleTension}
+var edgeClasses = []edgeClass{edgeClassRoleRole, edgeClassRoleDomain, edgeClassRoleAccountability, edgeClassRoleMember, edgeClassCircleDirectMember, edgeClassMemberTension, edgeClassRoleTension, edgeClassTensionProposal, edgeClassTensionAssignee, edgeClassTensionAssigneeRole, edgeClassTensionComment, edgeClassCommentReply, edgeClassCommentMember, edgeClassTensionLabel, edgeClassRoleProposal, edgeClassMemberProposal, edgeClassRoleMeeting, edgeClassMeetingAttendee, edgeClassMeetingFacilitator, edgeClassMeetingSecretary, edgeClassMeetingAgendaItem, edgeClassAgendaItemTension, edgeClassRoleProject, edgeClassMemberProject, edgeClassProjectNextAction, edgeClassCircleReportItem, edgeClassRoleReportItem, edgeClassReportItemValue}
 
-var roleEdges = []edgeClass{edgeClassRoleRole, edgeClassRoleDomain, edgeClassRoleAccountability, edgeClassRoleMember, edgeClassRoleTension}
+var roleEdges = []edgeClass{edgeClassRoleRole, edgeClassRoleDomain, edgeClassRoleAccountability, edgeClassRoleMember, edgeClassRoleTension, edgeClassTensionAssigneeRole, edgeClassRoleProposal, edgeClassRoleMeeting, edgeClassRoleProject, edgeClassCircleReportItem, edgeClassRoleReportItem}
 var domainEdges = []edgeClass{edgeClassRoleDomain}
 var accountabilityEdges = []edgeClass{edgeClassRoleAccountability}
-var memberEdges = []edgeClass{edgeClassRoleMember, edgeClassCircleDirectMember, edgeClassMemberTension}
-var tensionEdges = []edgeClass{edgeClassMemberTension, edgeClassRoleTension}
+var memberEdges = []edgeClass{edgeClassRoleMember, edgeClassCircleDirectMember, edgeClassMemberTension, edgeClassTensionAssignee, edgeClassCommentMember, edgeClassMemberProposal, edgeClassMeetingAttendee, edgeClassMeetingFacilitator, edgeClassMeetingSecretary, edgeClassMemberProject}
+var tensionEdges = []edgeClass{edgeClassMemberTension, edgeClassRoleTension, edgeClassTensionProposal, edgeClassTensionAssignee, edgeClassTensionAssigneeRole, edgeClassTensionComment, edgeClassTensionLabel, edgeClassAgendaItemTension}
+var commentEdges = []edgeClass{edgeClassTensionComment, edgeClassCommentReply, edgeClassCommentMember}
+var labelEdges = []edgeClass{edgeClassTensionLabel}
+var proposalEdges = []edgeClass{edgeClassTensionProposal, edgeClassRoleProposal, edgeClassMemberProposal}
+var meetingEdges = []edgeClass{edgeClassRoleMeeting, edgeClassMeetingAttendee, edgeClassMeetingFacilitator, edgeClassMeetingSecretary, edgeClassMeetingAgendaItem}
+var agendaItemEdges = []edgeClass{edgeClassMeetingAgendaItem, edgeClassAgendaItemTension}
+var projectEdges = []edgeClass{edgeClassRoleProject, edgeClassMemberProject, edgeClassProjectNextAction}
+var nextActionEdges = []edgeClass{edgeClassProjectNextAction}
+var reportItemEdges = []edgeClass{edgeClassCircleReportItem, edgeClassRoleReportItem, edgeClassReportItemValue}
+var reportValueEdges = []edgeClass{edgeClassReportItemValue}
 
 func (s *readDBService) vertices(tl util.TimeLineNumber, vertexClass vertexClass, limit uint64, condition interface{}, orderBys []string) (interface{}, error) {
 	if tl <= 0 {
@@ -282,6 +476,24 @@ func (s *readDBService) vertices(tl util.TimeLineNumber, vertexClass vertexClass
 		sb = memberAvatarSelect
 	case vertexClassTension:
 		sb = tensionSelect
+	case vertexClassComment:
+		sb = commentSelect
+	case vertexClassLabel:
+		sb = labelSelect
+	case vertexClassProposal:
+		sb = proposalSelect
+	case vertexClassMeeting:
+		sb = meetingSelect
+	case vertexClassAgendaItem:
+		sb = agendaItemSelect
+	case vertexClassProject:
+		sb = projectSelect
+	case vertexClassNextAction:
+		sb = nextActionSelect
+	case vertexClassReportItem:
+		sb = reportItemSelect
+	case vertexClassReportValue:
+		sb = reportValueSelect
 	default:
 		return nil, errors.Errorf("unknown vertex class: %q", vertexClass)
 	}
@@ -325,6 +537,24 @@ func (s *readDBService) vertices(tl util.TimeLineNumber, vertexClass vertexClass
 			res, err = scanAvatars(rows)
 		case vertexClassTension:
 			res, err = scanTensions(rows)
+		case vertexClassComment:
+			res, err = scanComments(rows)
+		case vertexClassLabel:
+			res, err = scanLabels(rows)
+		case vertexClassProposal:
+			res, err = scanProposals(rows)
+		case vertexClassMeeting:
+			res, err = scanMeetings(rows)
+		case vertexClassAgendaItem:
+			res, err = scanAgendaItems(rows)
+		case vertexClassProject:
+			res, err = scanProjects(rows)
+		case vertexClassNextAction:
+			res, err = scanNextActions(rows)
+		case vertexClassReportItem:
+			res, err = scanReportItems(rows)
+		case vertexClassReportValue:
+			res, err = scanReportValues(rows)
 		default:
 			return errors.Errorf("unknown vertex class: %q", vertexClass)
 		}
@@ -334,7 +564,11 @@ func (s *readDBService) vertices(tl util.TimeLineNumber, vertexClass vertexClass
 	return res, err
 }
 
-func (s *readDBService) connectedVertices(tl util.TimeLineNumber, vertexID []util.ID, ec edgeClass, direction edgeDirection, outputVertexClass vertexClass, condition interface{}, orderBys []string) (interface{}, error) {
+// connectedVerticesSelect returns the query selecting the vertices connected
+// to the provided vertices, the vertex class of the connected vertices, the
+// output vertex class and the selected edge column containing the provided
+// vertex id
+func (s *readDBService) connectedVerticesSelect(tl util.TimeLineNumber, vertexID []util.ID, ec edgeClass, direction edgeDirection, outputVertexClass vertexClass, condition interface{}) (sq.SelectBuilder, vertexClass, vertexClass, string) {
 	var sb sq.SelectBuilder
 	var vc vertexClass
 	var startEdgePoint, endEdgePoint string
@@ -362,6 +596,48 @@ func (s *readDBService) connectedVertices(tl util.TimeLineNumber, vertexID []uti
 			sb = memberSelect
 		case edgeClassRoleTension:
 			sb = roleSelect
+		case edgeClassTensionProposal:
+			sb = tensionSelect
+		case edgeClassTensionAssignee:
+			sb = memberSelect
+		case edgeClassTensionAssigneeRole:
+			sb = roleSelect
+		case edgeClassTensionComment:
+			sb = tensionSelect
+		case edgeClassCommentReply:
+			sb = commentSelect
+		case edgeClassCommentMember:
+			sb = memberSelect
+		case edgeClassTensionLabel:
+			sb = tensionSelect
+		case edgeClassRoleProposal:
+			sb = roleSelect
+		case edgeClassMemberProposal:
+			sb = memberSelect
+		case edgeClassRoleMeeting:
+			sb = roleSelect
+		case edgeClassMeetingAttendee:
+			sb = meetingSelect
+		case edgeClassMeetingFacilitator:
+			sb = meetingSelect
+		case edgeClassMeetingSecretary:
+			sb = meetingSelect
+		case edgeClassMeetingAgendaItem:
+			sb = meetingSelect
+		case edgeClassAgendaItemTension:
+			sb = tensionSelect
+		case edgeClassRoleProject:
+			sb = roleSelect
+		case edgeClassMemberProject:
+			sb = memberSelect
+		case edgeClassProjectNextAction:
+			sb = projectSelect
+		case edgeClassCircleReportItem:
+			sb = roleSelect
+		case edgeClassRoleReportItem:
+			sb = roleSelect
+		case edgeClassReportItemValue:
+			sb = reportItemSelect
 		default:
 			panic(fmt.Sprintf("unknown edgeClass: %s", ec))
 		}
@@ -384,6 +660,48 @@ func (s *readDBService) connectedVertices(tl util.TimeLineNumber, vertexID []uti
 			sb = tensionSelect
 		case edgeClassRoleTension:
 			sb = tensionSelect
+		case edgeClassTensionProposal:
+			sb = proposalSelect
+		case edgeClassTensionAssignee:
+			sb = tensionSelect
+		case edgeClassTensionAssigneeRole:
+			sb = tensionSelect
+		case edgeClassTensionComment:
+			sb = commentSelect
+		case edgeClassCommentReply:
+			sb = commentSelect
+		case edgeClassCommentMember:
+			sb = commentSelect
+		case edgeClassTensionLabel:
+			sb = labelSelect
+		case edgeClassRoleProposal:
+			sb = proposalSelect
+		case edgeClassMemberProposal:
+			sb = proposalSelect
+		case edgeClassRoleMeeting:
+			sb = meetingSelect
+		case edgeClassMeetingAttendee:
+			sb = memberSelect
+		case edgeClassMeetingFacilitator:
+			sb = memberSelect
+		case edgeClassMeetingSecretary:
+			sb = memberSelect
+		case edgeClassMeetingAgendaItem:
+			sb = agendaItemSelect
+		case edgeClassAgendaItemTension:
+			sb = agendaItemSelect
+		case edgeClassRoleProject:
+			sb = projectSelect
+		case edgeClassMemberProject:
+			sb = projectSelect
+		case edgeClassProjectNextAction:
+			sb = nextActionSelect
+		case edgeClassCircleReportItem:
+			sb = reportItemSelect
+		case edgeClassRoleReportItem:
+			sb = reportItemSelect
+		case edgeClassReportItemValue:
+			sb = reportValueSelect
 		default:
 			panic(fmt.Sprintf("unknown edgeClass: %s", ec))
 		}
@@ -408,6 +726,12 @@ func (s *readDBService) connectedVertices(tl util.TimeLineNumber, vertexID []uti
 		sb = sb.Where(condition)
 	}
 
+	return sb, vc, outputVertexClass, startEdgePoint
+}
+
+func (s *readDBService) connectedVertices(tl util.TimeLineNumber, vertexID []util.ID, ec edgeClass, direction edgeDirection, outputVertexClass vertexClass, condition interface{}, orderBys []string) (interface{}, error) {
+	sb, vc, outputVertexClass, _ := s.connectedVerticesSelect(tl, vertexID, ec, direction, outputVertexClass, condition)
+
 	q, args, err := sb.ToSql()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to build query")
@@ -440,6 +764,24 @@ func (s *readDBService) connectedVertices(tl util.TimeLineNumber, vertexID []uti
 			}
 		case vertexClassTension:
 			res, err = scanTensionsGroups(rows)
+		case vertexClassComment:
+			res, err = scanCommentsGroups(rows)
+		case vertexClassLabel:
+			res, err = scanLabelsGroups(rows)
+		case vertexClassProposal:
+			res, err = scanProposalsGroups(rows)
+		case vertexClassMeeting:
+			res, err = scanMeetingsGroups(rows)
+		case vertexClassAgendaItem:
+			res, err = scanAgendaItemsGroups(rows)
+		case vertexClassProject:
+			res, err = scanProjectsGroups(rows)
+		case vertexClassNextAction:
+			res, err = scanNextActionsGroups(rows)
+		case vertexClassReportItem:
+			res, err = scanReportItemsGroups(rows)
+		case vertexClassReportValue:
+			res, err = scanReportValuesGroups(rows)
 		default:
 			return errors.Errorf("unknown vertex class: %q", vc)
 		}
@@ -467,6 +809,24 @@ func (s *readDBService) verticesFiltered(tl util.TimeLineNumber, vc vertexClass,
 		sb = memberSelect
 	case vertexClassTension:
 		sb = tensionSelect
+	case vertexClassComment:
+		sb = commentSelect
+	case vertexClassLabel:
+		sb = labelSelect
+	case vertexClassProposal:
+		sb = proposalSelect
+	case vertexClassMeeting:
+		sb = meetingSelect
+	case vertexClassAgendaItem:
+		sb = agendaItemSelect
+	case vertexClassProject:
+		sb = projectSelect
+	case vertexClassNextAction:
+		sb = nextActionSelect
+	case vertexClassReportItem:
+		sb = reportItemSelect
+	case vertexClassReportValue:
+		sb = reportValueSelect
 	default:
 		return nil, errors.Errorf("unknown vertex class: %q", vc)
 	}
@@ -517,6 +877,24 @@ func (s *readDBService) verticesFiltered(tl util.TimeLineNumber, vc vertexClass,
 			res, err = scanMembers(rows)
 		case vertexClassTension:
 			res, err = scanTensions(rows)
+		case vertexClassComment:
+			res, err = scanComments(rows)
+		case vertexClassLabel:
+			res, err = scanLabels(rows)
+		case vertexClassProposal:
+			res, err = scanProposals(rows)
+		case vertexClassMeeting:
+			res, err = scanMeetings(rows)
+		case vertexClassAgendaItem:
+			res, err = scanAgendaItems(rows)
+		case vertexClassProject:
+			res, err = scanProjects(rows)
+		case vertexClassNextAction:
+			res, err = scanNextActions(rows)
+		case vertexClassReportItem:
+			res, err = scanReportItems(rows)
+		case vertexClassReportValue:
+			res, err = scanReportValues(rows)
 		default:
 			return errors.Errorf("unknown vertex class: %q", vc)
 		}
@@ -618,6 +996,24 @@ func (s *readDBService) insertVertex(tl util.TimeLineNumber, vc vertexClass, id
 		return s.insertMemberAvatar(tl, id, vertex.(*models.Avatar))
 	case vertexClassTension:
 		return s.insertTension(tl, id, vertex.(*models.Tension))
+	case vertexClassComment:
+		return s.insertComment(tl, id, vertex.(*models.TensionComment))
+	case vertexClassLabel:
+		return s.insertLabel(tl, id, vertex.(*models.TensionLabel))
+	case vertexClassProposal:
+		return s.insertProposal(tl, id, vertex.(*models.Proposal))
+	case vertexClassMeeting:
+		return s.insertMeeting(tl, id, vertex.(*models.Meeting))
+	case vertexClassAgendaItem:
+		return s.insertAgendaItem(tl, id, vertex.(*models.MeetingAgendaItem))
+	case vertexClassProject:
+		return s.insertProject(tl, id, vertex.(*models.Project))
+	case vertexClassNextAction:
+		return s.insertNextAction(tl, id, vertex.(*models.NextAction))
+	case vertexClassReportItem:
+		return s.insertReportItem(tl, id, vertex.(*models.ReportItem))
+	case vertexClassReportValue:
+		return s.insertReportValue(tl, id, vertex.(*models.ReportItemValue))
 	default:
 		return errors.Errorf("unknown vertex class: %q", vc)
 	}
@@ -1095,10 +1491,13 @@ func scanMemberRoleEdgesGroups(rows *sql.Rows) (map[util.ID][]*models.MemberRole
 
 func scanTension(rows *sql.Rows, additionalFields ...interface{}) (*models.Tension, error) {
 	t := models.Tension{}
-	fields := append([]interface{}{&t.ID, &t.StartTl, &t.EndTl, &t.Title, &t.Description, &t.Closed, &t.CloseReason}, additionalFields...)
+	// To make sqlite3 happy
+	var state string
+	fields := append([]interface{}{&t.ID, &t.StartTl, &t.EndTl, &t.Title, &t.Description, &t.Closed, &t.CloseReason, &state}, additionalFields...)
 	if err := rows.Scan(fields...); err != nil {
 		return nil, errors.Wrap(err, "failed to scan tension rows")
 	}
+	t.State = models.TensionState(state)
 	return &t, nil
 }
 
@@ -1135,1708 +1534,3486 @@ func scanTensionsGroups(rows *sql.Rows) (map[util.ID][]*models.Tension, error) {
 	return tensionsGroups, nil
 }
 
-func scanRoleEvent(rows *sql.Rows) (*models.RoleEvent, error) {
-	e := models.RoleEvent{}
-	var rawData []byte
-	// To make sqlite3 happy
-	var eventType string
-	fields := []interface{}{&e.TimeLineID, &e.ID, &e.RoleID, &eventType, &rawData}
+func scanComment(rows *sql.Rows, additionalFields ...interface{}) (*models.TensionComment, error) {
+	c := models.TensionComment{}
+	fields := append([]interface{}{&c.ID, &c.StartTl, &c.EndTl, &c.Number, &c.Text, &c.TimeLine}, additionalFields...)
 	if err := rows.Scan(fields...); err != nil {
-		return nil, errors.Wrap(err, "error scanning event")
+		return nil, errors.Wrap(err, "failed to scan comment rows")
 	}
-	e.EventType = models.RoleEventType(eventType)
+	return &c, nil
+}
 
-	data := models.GetRoleEventDataType(e.EventType)
-	if err := json.Unmarshal(rawData, &data); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal event")
+func scanComments(rows *sql.Rows) ([]*models.TensionComment, error) {
+	comments := []*models.TensionComment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		comments = append(comments, c)
 	}
-	e.Data = data
-
-	return &e, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return comments, nil
 }
 
-func scanRoleEvents(rows *sql.Rows) ([]*models.RoleEvent, error) {
-	roleEvents := []*models.RoleEvent{}
+func scanCommentsGroups(rows *sql.Rows) (map[util.ID][]*models.TensionComment, error) {
+	commentsGroups := map[util.ID][]*models.TensionComment{}
 	for rows.Next() {
-		e, err := scanRoleEvent(rows)
+		var group util.ID
+		c, err := scanComment(rows, &group)
 		if err != nil {
 			rows.Close()
 			return nil, err
 		}
-		roleEvents = append(roleEvents, e)
+		commentsGroups[group] = append(commentsGroups[group], c)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return roleEvents, nil
+	return commentsGroups, nil
 }
 
-func (s *readDBService) insertRole(tl util.TimeLineNumber, id util.ID, role *models.Role) error {
-	q, args, err := roleInsert.Values(id, tl, nil, role.RoleType, role.Depth, role.Name, role.Purpose).ToSql()
-	if err != nil {
-		return errors.Wrap(err, "failed to build query")
-	}
-	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		_, err = tx.Exec(q, args...)
-		return err
-	})
-	if err != nil {
-		return errors.WithMessage(err, "failed to execute query")
+func scanLabel(rows *sql.Rows, additionalFields ...interface{}) (*models.TensionLabel, error) {
+	c := models.TensionLabel{}
+	fields := append([]interface{}{&c.ID, &c.StartTl, &c.EndTl, &c.Number, &c.Name}, additionalFields...)
+	if err := rows.Scan(fields...); err != nil {
+		return nil, errors.Wrap(err, "failed to scan label rows")
 	}
-	return nil
+	return &c, nil
 }
 
-func (s *readDBService) insertDomain(tl util.TimeLineNumber, id util.ID, domain *models.Domain) error {
-	q, args, err := domainInsert.Values(id, tl, nil, domain.Description).ToSql()
-	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+func scanLabels(rows *sql.Rows) ([]*models.TensionLabel, error) {
+	labels := []*models.TensionLabel{}
+	for rows.Next() {
+		c, err := scanLabel(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		labels = append(labels, c)
 	}
-	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		_, err = tx.Exec(q, args...)
-		return err
-	})
-	if err != nil {
-		return errors.WithMessage(err, "failed to execute query")
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	return nil
+	return labels, nil
 }
 
-func (s *readDBService) insertAccountability(tl util.TimeLineNumber, id util.ID, accountability *models.Accountability) error {
-	q, args, err := accountabilityInsert.Values(id, tl, nil, accountability.Description).ToSql()
-	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+func scanLabelsGroups(rows *sql.Rows) (map[util.ID][]*models.TensionLabel, error) {
+	labelsGroups := map[util.ID][]*models.TensionLabel{}
+	for rows.Next() {
+		var group util.ID
+		c, err := scanLabel(rows, &group)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		labelsGroups[group] = append(labelsGroups[group], c)
 	}
-	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		_, err = tx.Exec(q, args...)
-		return err
-	})
-	if err != nil {
-		return errors.WithMessage(err, "failed to execute query")
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	return nil
+	return labelsGroups, nil
 }
 
-func (s *readDBService) insertRoleAdditionalContent(tl util.TimeLineNumber, id util.ID, roleAdditionalContent *models.RoleAdditionalContent) error {
-	q, args, err := roleAdditionalContentInsert.Values(id, tl, nil, roleAdditionalContent.Content).ToSql()
-	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+func scanProposal(rows *sql.Rows, additionalFields ...interface{}) (*models.Proposal, error) {
+	p := models.Proposal{}
+	// To make sqlite3 happy
+	var state, objections string
+	fields := append([]interface{}{&p.ID, &p.StartTl, &p.EndTl, &state, &p.Changes, &objections}, additionalFields...)
+	if err := rows.Scan(fields...); err != nil {
+		return nil, errors.Wrap(err, "failed to scan proposal rows")
 	}
-	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		_, err = tx.Exec(q, args...)
-		return err
-	})
-	if err != nil {
-		return errors.WithMessage(err, "failed to execute query")
+	p.State = models.ProposalState(state)
+	if err := json.Unmarshal([]byte(objections), &p.Objections); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal proposal objections")
 	}
-	return nil
+	return &p, nil
 }
 
-func (s *readDBService) insertMember(tl util.TimeLineNumber, id util.ID, member *models.Member) error {
-	q, args, err := memberInsert.Values(id, tl, nil, member.IsAdmin, member.UserName, member.FullName, member.Email).ToSql()
-	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+func scanProposals(rows *sql.Rows) ([]*models.Proposal, error) {
+	proposals := []*models.Proposal{}
+	for rows.Next() {
+		p, err := scanProposal(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		proposals = append(proposals, p)
 	}
-	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		_, err = tx.Exec(q, args...)
-		return err
-	})
-	if err != nil {
-		return errors.WithMessage(err, "failed to execute query")
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	return nil
+	return proposals, nil
 }
 
-func (s *readDBService) insertMemberAvatar(tl util.TimeLineNumber, id util.ID, avatar *models.Avatar) error {
-	q, args, err := memberAvatarInsert.Values(id, tl, nil, avatar.Image).ToSql()
-	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+func scanProposalsGroups(rows *sql.Rows) (map[util.ID][]*models.Proposal, error) {
+	proposalsGroups := map[util.ID][]*models.Proposal{}
+	for rows.Next() {
+		var group util.ID
+		p, err := scanProposal(rows, &group)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		proposalsGroups[group] = append(proposalsGroups[group], p)
 	}
-	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		_, err = tx.Exec(q, args...)
-		return err
-	})
-	if err != nil {
-		return errors.WithMessage(err, "failed to execute query")
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	return nil
+	return proposalsGroups, nil
 }
 
-func (s *readDBService) insertTension(tl util.TimeLineNumber, id util.ID, tension *models.Tension) error {
-	q, args, err := tensionInsert.Values(id, tl, nil, tension.Title, tension.Description, tension.Closed, tension.CloseReason).ToSql()
-	if err != nil {
-		return errors.Wrap(err, "failed to build query")
-	}
-	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		_, err = tx.Exec(q, args...)
-		return err
-	})
-	if err != nil {
-		return errors.WithMessage(err, "failed to execute query")
+func scanMeeting(rows *sql.Rows, additionalFields ...interface{}) (*models.Meeting, error) {
+	m := models.Meeting{}
+	// To make sqlite3 happy
+	var meetingType string
+	fields := append([]interface{}{&m.ID, &m.StartTl, &m.EndTl, &meetingType, &m.Title, &m.StartTime, &m.Closed}, additionalFields...)
+	if err := rows.Scan(fields...); err != nil {
+		return nil, errors.Wrap(err, "failed to scan meeting rows")
 	}
-	return nil
+	m.MeetingType = models.MeetingType(meetingType)
+	return &m, nil
 }
 
-// insertRoleEvent inserts or update a role event
-func (s *readDBService) insertRoleEvent(roleEvent *models.RoleEvent) error {
-	data, err := json.Marshal(roleEvent.Data)
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal event")
+func scanMeetings(rows *sql.Rows) ([]*models.Meeting, error) {
+	meetings := []*models.Meeting{}
+	for rows.Next() {
+		m, err := scanMeeting(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		meetings = append(meetings, m)
 	}
-	q, args, err := roleEventInsert.Values(roleEvent.TimeLineID, roleEvent.ID, roleEvent.RoleID, roleEvent.EventType, data).ToSql()
-	if err != nil {
-		return errors.Wrap(err, "failed to build query")
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		// poor man insert or update...
-		if _, err := tx.Exec("delete from roleevent where id = $1", roleEvent.ID); err != nil {
-			return errors.Wrap(err, "failed to delete roleevent")
+	return meetings, nil
+}
+
+func scanMeetingsGroups(rows *sql.Rows) (map[util.ID][]*models.Meeting, error) {
+	meetingsGroups := map[util.ID][]*models.Meeting{}
+	for rows.Next() {
+		var group util.ID
+		m, err := scanMeeting(rows, &group)
+		if err != nil {
+			rows.Close()
+			return nil, err
 		}
-		_, err = tx.Exec(q, args...)
-		return err
-	})
-	if err != nil {
-		return errors.WithMessage(err, "failed to execute query")
+		meetingsGroups[group] = append(meetingsGroups[group], m)
 	}
-	return nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return meetingsGroups, nil
 }
 
-type readDBService struct {
-	tx                        *db.Tx
-	forcedAdminMemberUserName string
+func scanAgendaItem(rows *sql.Rows, additionalFields ...interface{}) (*models.MeetingAgendaItem, error) {
+	ai := models.MeetingAgendaItem{}
+	fields := append([]interface{}{&ai.ID, &ai.StartTl, &ai.EndTl, &ai.Number, &ai.Title, &ai.Outcome}, additionalFields...)
+	if err := rows.Scan(fields...); err != nil {
+		return nil, errors.Wrap(err, "failed to scan agenda item rows")
+	}
+	return &ai, nil
+}
 
-	// cached curTl to not query every time
-	// the DBService lives inside a repreatable read/serializable transaction so
-	// curTl could change only if we are chaning it in this transaction
-	curTl     *util.TimeLine
-	curTlLock sync.Mutex
-}
-
-func NewReadDBService(tx *db.Tx) (*readDBService, error) {
-	s := &readDBService{tx: tx}
-
-	curTl, err := s.curTimeLineFromDB()
-	if err != nil {
-		return nil, err
+func scanAgendaItems(rows *sql.Rows) ([]*models.MeetingAgendaItem, error) {
+	agendaItems := []*models.MeetingAgendaItem{}
+	for rows.Next() {
+		ai, err := scanAgendaItem(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		agendaItems = append(agendaItems, ai)
 	}
-	if curTl == nil {
-		curTl = &util.TimeLine{}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-
-	s.curTl = curTl
-
-	return s, nil
+	return agendaItems, nil
 }
 
-func (s *readDBService) SetForceAdminMemberUserName(u string) {
-	s.forcedAdminMemberUserName = u
+func scanAgendaItemsGroups(rows *sql.Rows) (map[util.ID][]*models.MeetingAgendaItem, error) {
+	agendaItemsGroups := map[util.ID][]*models.MeetingAgendaItem{}
+	for rows.Next() {
+		var group util.ID
+		ai, err := scanAgendaItem(rows, &group)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		agendaItemsGroups[group] = append(agendaItemsGroups[group], ai)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return agendaItemsGroups, nil
 }
 
-func (s *readDBService) curTimeLineFromDB() (*util.TimeLine, error) {
-	// zeroed timeline, also valid if there're no rows
-	var tl util.TimeLine
-
-	err := s.tx.Do(func(tx *db.WrappedTx) error {
-		return tx.QueryRow("select timestamp from timeline order by timestamp desc limit 1").Scan(&tl.Timestamp)
-	})
-	if err == sql.ErrNoRows {
-		return nil, nil
+func scanProject(rows *sql.Rows, additionalFields ...interface{}) (*models.Project, error) {
+	p := models.Project{}
+	// To make sqlite3 happy
+	var status string
+	fields := append([]interface{}{&p.ID, &p.StartTl, &p.EndTl, &p.Title, &p.Description, &status}, additionalFields...)
+	if err := rows.Scan(fields...); err != nil {
+		return nil, errors.Wrap(err, "failed to scan project rows")
 	}
-
-	return &tl, err
+	p.Status = models.ProjectStatus(status)
+	return &p, nil
 }
 
-func (s *readDBService) CurTimeLine(ctx context.Context) *util.TimeLine {
-	s.curTlLock.Lock()
-	defer s.curTlLock.Unlock()
-
-	if s.curTl == nil {
-		return &util.TimeLine{}
+func scanProjects(rows *sql.Rows) ([]*models.Project, error) {
+	projects := []*models.Project{}
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		projects = append(projects, p)
 	}
-
-	// take a copy of curTl
-	c := *s.curTl
-
-	//log.Debugf("curTl: %s", c)
-	return &c
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return projects, nil
 }
 
-func (s *readDBService) TimeLine(ctx context.Context, sn util.TimeLineNumber) (*util.TimeLine, error) {
-	var tl util.TimeLine
-
-	err := s.tx.Do(func(tx *db.WrappedTx) error {
-		if err := tx.QueryRow("select timestamp from timeline where timestamp = $1", time.Unix(0, int64(sn))).Scan(&tl.Timestamp); err != nil {
-			return err
+func scanProjectsGroups(rows *sql.Rows) (map[util.ID][]*models.Project, error) {
+	projectsGroups := map[util.ID][]*models.Project{}
+	for rows.Next() {
+		var group util.ID
+		p, err := scanProject(rows, &group)
+		if err != nil {
+			rows.Close()
+			return nil, err
 		}
-		return nil
-	})
-	if err == sql.ErrNoRows {
-		return nil, errors.Errorf("timeline %d doesn't exists", sn)
+		projectsGroups[group] = append(projectsGroups[group], p)
 	}
-
-	return &tl, err
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return projectsGroups, nil
 }
 
-func (s *readDBService) TimeLineForGroupID(ctx context.Context, groupID util.ID) (*util.TimeLine, error) {
-	var tl util.TimeLine
-
-	err := s.tx.Do(func(tx *db.WrappedTx) error {
-		return tx.QueryRow("select timestamp from timeline where groupid = $1", groupID).Scan(&tl.Timestamp)
-	})
-	if err == sql.ErrNoRows {
-		return nil, nil
+func scanNextAction(rows *sql.Rows, additionalFields ...interface{}) (*models.NextAction, error) {
+	na := models.NextAction{}
+	fields := append([]interface{}{&na.ID, &na.StartTl, &na.EndTl, &na.Number, &na.Description, &na.Completed}, additionalFields...)
+	if err := rows.Scan(fields...); err != nil {
+		return nil, errors.Wrap(err, "failed to scan next action rows")
 	}
-
-	return &tl, err
+	return &na, nil
 }
 
-func (s *readDBService) TimeLines(ctx context.Context, ts *time.Time, sn util.TimeLineNumber, limit int, after bool, aggregateType string, aggregateID *util.ID) ([]*util.TimeLine, bool, error) {
-	var tls []*util.TimeLine
-	if limit <= 0 {
-		limit = MaxFetchSize
-	}
-	if ts == nil {
-		t := time.Unix(0, int64(sn)).UTC()
-		ts = &t
+func scanNextActions(rows *sql.Rows) ([]*models.NextAction, error) {
+	nextActions := []*models.NextAction{}
+	for rows.Next() {
+		na, err := scanNextAction(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		nextActions = append(nextActions, na)
 	}
-	sb := sb.Select("timestamp").From("timeline")
-	// ask for limit + 1 rows to know if there's more data
-	if after {
-		sb = sb.Where(sq.Gt{"timestamp": ts}).OrderBy("timestamp asc")
-	} else {
-		sb = sb.Where(sq.Lt{"timestamp": ts}).OrderBy("timestamp desc")
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	sb = sb.Limit(uint64(limit + 1))
+	return nextActions, nil
+}
 
-	if aggregateType != "" {
-		sb = sb.Where(sq.Eq{"aggregatetype": aggregateType})
+func scanNextActionsGroups(rows *sql.Rows) (map[util.ID][]*models.NextAction, error) {
+	nextActionsGroups := map[util.ID][]*models.NextAction{}
+	for rows.Next() {
+		var group util.ID
+		na, err := scanNextAction(rows, &group)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		nextActionsGroups[group] = append(nextActionsGroups[group], na)
 	}
-	if aggregateID != nil {
-		sb = sb.Where(sq.Eq{"aggregateid": aggregateID})
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
+	return nextActionsGroups, nil
+}
 
-	q, args, err := sb.ToSql()
-	if err != nil {
-		return nil, false, errors.Wrap(err, "failed to build query")
+func scanReportItem(rows *sql.Rows, additionalFields ...interface{}) (*models.ReportItem, error) {
+	r := models.ReportItem{}
+	// To make sqlite3 happy
+	var reportItemType, frequency string
+	fields := append([]interface{}{&r.ID, &r.StartTl, &r.EndTl, &reportItemType, &r.Description, &frequency}, additionalFields...)
+	if err := rows.Scan(fields...); err != nil {
+		return nil, errors.Wrap(err, "failed to scan report item rows")
 	}
-	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		rows, err := tx.Query(q, args...)
+	r.ReportItemType = models.ReportItemType(reportItemType)
+	r.Frequency = models.ReportFrequency(frequency)
+	return &r, nil
+}
+
+func scanReportItems(rows *sql.Rows) ([]*models.ReportItem, error) {
+	reportItems := []*models.ReportItem{}
+	for rows.Next() {
+		r, err := scanReportItem(rows)
 		if err != nil {
-			return err
+			rows.Close()
+			return nil, err
 		}
-		tls, err = scanTimeLines(rows)
+		reportItems = append(reportItems, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reportItems, nil
+}
+
+func scanReportItemsGroups(rows *sql.Rows) (map[util.ID][]*models.ReportItem, error) {
+	reportItemsGroups := map[util.ID][]*models.ReportItem{}
+	for rows.Next() {
+		var group util.ID
+		r, err := scanReportItem(rows, &group)
 		if err != nil {
-			return err
+			rows.Close()
+			return nil, err
 		}
-		return nil
-	})
-	if err != nil {
-		return nil, false, err
+		reportItemsGroups[group] = append(reportItemsGroups[group], r)
 	}
-
-	size := len(tls)
-	if len(tls) > limit {
-		size = limit
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	return tls[:size], len(tls) > limit, err
+	return reportItemsGroups, nil
 }
 
-func (s *readDBService) TimeLineAtTimeStamp(ctx context.Context, ts time.Time) (*util.TimeLine, error) {
-	var tl util.TimeLine
-
-	err := s.tx.Do(func(tx *db.WrappedTx) error {
-		return tx.QueryRow("select timestamp from timeline where timestamp >= $1 order by timestamp asc limit 1", ts).Scan(&tl.Timestamp)
-	})
-	if err == sql.ErrNoRows {
-		return nil, nil
+func scanReportValue(rows *sql.Rows, additionalFields ...interface{}) (*models.ReportItemValue, error) {
+	v := models.ReportItemValue{}
+	fields := append([]interface{}{&v.ID, &v.StartTl, &v.EndTl, &v.Period, &v.Value}, additionalFields...)
+	if err := rows.Scan(fields...); err != nil {
+		return nil, errors.Wrap(err, "failed to scan report value rows")
 	}
-
-	return &tl, err
+	v.Period = v.Period.UTC()
+	return &v, nil
 }
 
-func (s *readDBService) RootRole(ctx context.Context, tl util.TimeLineNumber) (*models.Role, error) {
-	vs, err := s.vertices(tl, vertexClassRole, 0, sq.Eq{"role.depth": 0}, nil)
-	if err != nil {
+func scanReportValues(rows *sql.Rows) ([]*models.ReportItemValue, error) {
+	values := []*models.ReportItemValue{}
+	for rows.Next() {
+		v, err := scanReportValue(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	roles := vs.([]*models.Role)
+	return values, nil
+}
 
-	if len(roles) == 0 {
-		return nil, nil
+func scanReportValuesGroups(rows *sql.Rows) (map[util.ID][]*models.ReportItemValue, error) {
+	valuesGroups := map[util.ID][]*models.ReportItemValue{}
+	for rows.Next() {
+		var group util.ID
+		v, err := scanReportValue(rows, &group)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		valuesGroups[group] = append(valuesGroups[group], v)
 	}
-	if len(roles) > 1 {
-		return nil, errors.Errorf("too many root roles. This shouldn't happen!")
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	return roles[0], nil
+	return valuesGroups, nil
 }
 
-func (s *readDBService) Role(ctx context.Context, tl util.TimeLineNumber, id util.ID) (*models.Role, error) {
-	var err error
-
-	vs, err := s.vertices(tl, vertexClassRole, 0, sq.Eq{"role.id": id}, nil)
-	if err != nil {
-		return nil, err
+func scanRoleEvent(rows *sql.Rows) (*models.RoleEvent, error) {
+	e := models.RoleEvent{}
+	var rawData []byte
+	// To make sqlite3 happy
+	var eventType string
+	fields := []interface{}{&e.TimeLineID, &e.ID, &e.RoleID, &eventType, &rawData}
+	if err := rows.Scan(fields...); err != nil {
+		return nil, errors.Wrap(err, "error scanning event")
 	}
-	roles := vs.([]*models.Role)
+	e.EventType = models.RoleEventType(eventType)
 
-	if len(roles) < 1 {
-		return nil, nil
+	data := models.GetRoleEventDataType(e.EventType)
+	if err := json.Unmarshal(rawData, &data); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal event")
 	}
-	return roles[0], nil
+	e.Data = data
+
+	return &e, nil
 }
 
-func (s *readDBService) Roles(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) ([]*models.Role, error) {
-	var condition interface{}
-	if len(rolesIDs) > 0 {
-		condition = sq.Eq{"role.id": rolesIDs}
+func scanRoleEvents(rows *sql.Rows) ([]*models.RoleEvent, error) {
+	roleEvents := []*models.RoleEvent{}
+	for rows.Next() {
+		e, err := scanRoleEvent(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		roleEvents = append(roleEvents, e)
 	}
-	vs, err := s.vertices(tl, vertexClassRole, 0, condition, []string{"role.name"})
-	if err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	roles := vs.([]*models.Role)
-
-	return roles, nil
+	return roleEvents, nil
 }
 
-func (s *readDBService) ChildRoles(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID, orderBys []string) (map[util.ID][]*models.Role, error) {
-	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleRole, edgeDirectionOut, "", nil, orderBys)
+func (s *readDBService) insertRole(tl util.TimeLineNumber, id util.ID, role *models.Role) error {
+	q, args, err := roleInsert.Values(id, tl, nil, role.RoleType, role.Depth, role.Name, role.Purpose).ToSql()
 	if err != nil {
-		return nil, err
+		return errors.Wrap(err, "failed to build query")
 	}
-	roles := vs.(map[util.ID][]*models.Role)
-	return roles, nil
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
+	}
+	return nil
 }
 
-func (s *readDBService) RoleEventsByType(ctx context.Context, roleID util.ID, tl util.TimeLineNumber, eventType models.RoleEventType) ([]*models.RoleEvent, error) {
-
-	sb := roleEventSelect.Where(sq.Eq{"roleid": roleID, "timeline": tl, "eventtype": string(eventType)})
-
-	q, args, err := sb.ToSql()
+func (s *readDBService) insertDomain(tl util.TimeLineNumber, id util.ID, domain *models.Domain) error {
+	q, args, err := domainInsert.Values(id, tl, nil, domain.Description).ToSql()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to build query")
+		return errors.Wrap(err, "failed to build query")
 	}
-
-	var events []*models.RoleEvent
 	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		rows, err := tx.Query(q, args...)
-		if err != nil {
-			return errors.WithMessage(err, "failed to execute query")
-		}
-		events, err = scanRoleEvents(rows)
+		_, err = tx.Exec(q, args...)
 		return err
 	})
 	if err != nil {
-		return nil, err
+		return errors.WithMessage(err, "failed to execute query")
 	}
-
-	return events, nil
+	return nil
 }
 
-func (s *readDBService) RoleEvents(ctx context.Context, roleID util.ID, first int, start, after util.TimeLineNumber) ([]*models.RoleEvent, bool, error) {
-	var condition sq.Sqlizer
-
-	if start != 0 {
-		condition = sq.LtOrEq{"roleevent.timeline": start}
+func (s *readDBService) insertAccountability(tl util.TimeLineNumber, id util.ID, accountability *models.Accountability) error {
+	q, args, err := accountabilityInsert.Values(id, tl, nil, accountability.Description).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
 	}
-	if after != 0 {
-		condition = sq.Lt{"roleevent.timeline": after}
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
 	}
+	return nil
+}
 
-	sb := roleEventSelect.Where(sq.Eq{"roleid": roleID})
-	sb = sb.OrderBy("timeline desc")
-
-	if condition != nil {
-		sb = sb.Where(condition)
+func (s *readDBService) insertRoleAdditionalContent(tl util.TimeLineNumber, id util.ID, roleAdditionalContent *models.RoleAdditionalContent) error {
+	q, args, err := roleAdditionalContentInsert.Values(id, tl, nil, roleAdditionalContent.Content).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
 	}
-
-	if first != 0 {
-		sb = sb.Limit(uint64(first))
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
 	}
+	return nil
+}
 
-	q, args, err := sb.ToSql()
+func (s *readDBService) insertMember(tl util.TimeLineNumber, id util.ID, member *models.Member) error {
+	q, args, err := memberInsert.Values(id, tl, nil, member.IsAdmin, member.UserName, member.FullName, member.Email).ToSql()
 	if err != nil {
-		return nil, false, errors.Wrap(err, "failed to build query")
+		return errors.Wrap(err, "failed to build query")
 	}
-
-	var events []*models.RoleEvent
 	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		rows, err := tx.Query(q, args...)
-		if err != nil {
-			return errors.WithMessage(err, "failed to execute query")
-		}
-		events, err = scanRoleEvents(rows)
+		_, err = tx.Exec(q, args...)
 		return err
 	})
 	if err != nil {
-		return nil, false, err
-	}
-
-	size := len(events)
-	if len(events) > first {
-		size = first
+		return errors.WithMessage(err, "failed to execute query")
 	}
-	return events[:size], len(events) > first, nil
+	return nil
 }
 
-func (s *readDBService) Member(ctx context.Context, tl util.TimeLineNumber, memberID util.ID) (*models.Member, error) {
-	vs, err := s.vertices(tl, vertexClassMember, 0, sq.Eq{"member.id": memberID}, nil)
+func (s *readDBService) insertMemberAvatar(tl util.TimeLineNumber, id util.ID, avatar *models.Avatar) error {
+	q, args, err := memberAvatarInsert.Values(id, tl, nil, avatar.Image).ToSql()
 	if err != nil {
-		return nil, err
+		return errors.Wrap(err, "failed to build query")
 	}
-	members := vs.([]*models.Member)
-	if len(members) == 0 {
-		return nil, nil
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
 	}
-	return members[0], nil
+	return nil
 }
 
-func (s *readDBService) MemberAvatar(ctx context.Context, tl util.TimeLineNumber, id util.ID) (*models.Avatar, error) {
-	vs, err := s.vertices(tl, vertexClassMemberAvatar, 0, sq.Eq{"memberavatar.id": id}, nil)
+func (s *readDBService) insertTension(tl util.TimeLineNumber, id util.ID, tension *models.Tension) error {
+	q, args, err := tensionInsert.Values(id, tl, nil, tension.Title, tension.Description, tension.Closed, tension.CloseReason, string(tension.State)).ToSql()
 	if err != nil {
-		return nil, err
+		return errors.Wrap(err, "failed to build query")
 	}
-	avatars := vs.([]*models.Avatar)
-	if len(avatars) == 0 {
-		return nil, nil
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
 	}
-	return avatars[0], nil
+	return nil
 }
 
-func (s *readDBService) MemberMatchUID(ctx context.Context, memberID util.ID) (string, error) {
-	sb := sb.Select("matchUID").From("membermatch").Where(sq.Eq{"memberid": memberID})
-	q, args, err := sb.ToSql()
+func (s *readDBService) insertComment(tl util.TimeLineNumber, id util.ID, comment *models.TensionComment) error {
+	q, args, err := commentInsert.Values(id, tl, nil, comment.Number, comment.Text, comment.TimeLine).ToSql()
 	if err != nil {
-		return "", err
+		return errors.Wrap(err, "failed to build query")
 	}
-
-	var matchUID string
 	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		return tx.QueryRow(q, args...).Scan(&matchUID)
+		_, err = tx.Exec(q, args...)
+		return err
 	})
-	if err != nil && err != sql.ErrNoRows {
-		return "", err
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
 	}
-
-	return matchUID, nil
+	return nil
 }
 
-func (s *readDBService) MemberByMatchUID(ctx context.Context, matchUID string) (*models.Member, error) {
-	sb := sb.Select("memberid").From("membermatch").Where(sq.Eq{"matchUID": matchUID})
-	q, args, err := sb.ToSql()
+func (s *readDBService) insertLabel(tl util.TimeLineNumber, id util.ID, label *models.TensionLabel) error {
+	q, args, err := labelInsert.Values(id, tl, nil, label.Number, label.Name).ToSql()
 	if err != nil {
-		return nil, err
+		return errors.Wrap(err, "failed to build query")
 	}
-
-	var memberID util.ID
 	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		return tx.QueryRow(q, args...).Scan(&memberID)
+		_, err = tx.Exec(q, args...)
+		return err
 	})
-	if err != nil && err != sql.ErrNoRows {
-		return nil, err
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
 	}
-	return s.Member(ctx, s.CurTimeLine(ctx).Number(), memberID)
+	return nil
 }
 
-func (s *readDBService) MemberByUserName(ctx context.Context, tl util.TimeLineNumber, userName string) (*models.Member, error) {
-	vs, err := s.vertices(tl, vertexClassMember, 0, sq.Eq{"member.username": userName}, nil)
+func (s *readDBService) insertProposal(tl util.TimeLineNumber, id util.ID, proposal *models.Proposal) error {
+	objections := proposal.Objections
+	if objections == nil {
+		objections = []*models.ProposalObjection{}
+	}
+	objectionsData, err := json.Marshal(objections)
 	if err != nil {
-		return nil, err
+		return errors.Wrap(err, "failed to marshal proposal objections")
 	}
-	members := vs.([]*models.Member)
-	if len(members) == 0 {
-		return nil, nil
+	q, args, err := proposalInsert.Values(id, tl, nil, proposal.State, proposal.Changes, string(objectionsData)).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
 	}
-	return members[0], nil
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
+	}
+	return nil
 }
 
-func (s *readDBService) MemberByEmail(ctx context.Context, tl util.TimeLineNumber, email string) (*models.Member, error) {
-	vs, err := s.vertices(tl, vertexClassMember, 0, sq.Eq{"member.email": email}, nil)
+func (s *readDBService) insertMeeting(tl util.TimeLineNumber, id util.ID, meeting *models.Meeting) error {
+	q, args, err := meetingInsert.Values(id, tl, nil, meeting.MeetingType, meeting.Title, meeting.StartTime, meeting.Closed).ToSql()
 	if err != nil {
-		return nil, err
+		return errors.Wrap(err, "failed to build query")
 	}
-	members := vs.([]*models.Member)
-	if len(members) == 0 {
-		return nil, nil
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
 	}
-	return members[0], nil
+	return nil
 }
 
-func (s *readDBService) MembersByIDs(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) ([]*models.Member, error) {
-	var condition interface{}
-	if len(membersIDs) > 0 {
-		condition = sq.Eq{"member.id": membersIDs}
+func (s *readDBService) insertAgendaItem(tl util.TimeLineNumber, id util.ID, agendaItem *models.MeetingAgendaItem) error {
+	q, args, err := agendaItemInsert.Values(id, tl, nil, agendaItem.Number, agendaItem.Title, agendaItem.Outcome).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
 	}
-	vs, err := s.vertices(tl, vertexClassMember, 0, condition, []string{"member.fullname"})
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return errors.WithMessage(err, "failed to execute query")
 	}
-	members := vs.([]*models.Member)
-
-	return members, nil
+	return nil
 }
 
-func (s *readDBService) members(tl util.TimeLineNumber, searchString string, first int, after *string) ([]*models.Member, error) {
-	var condition sq.Sqlizer
-	if after != nil {
-		condition = sq.Gt{"member.fullname": after}
-	}
-	if searchString != "" {
-		likeCondition := sq.Or{GenericSqlizer(fmt.Sprintf(`lower(member.fullname) LIKE lower('%%%s%%')`, searchString)), GenericSqlizer(fmt.Sprintf(`lower(member.UserName) LIKE lower('%%%s%%')`, searchString))}
-		if condition == nil {
-			condition = likeCondition
-		} else {
-			condition = sq.And{condition, likeCondition}
-		}
+func (s *readDBService) insertProject(tl util.TimeLineNumber, id util.ID, project *models.Project) error {
+	q, args, err := projectInsert.Values(id, tl, nil, project.Title, project.Description, project.Status).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
 	}
-
-	vs, err := s.vertices(tl, vertexClassMember, uint64(first), condition, []string{"member.fullname"})
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return errors.WithMessage(err, "failed to execute query")
 	}
-	members := vs.([]*models.Member)
-
-	return members, nil
+	return nil
 }
 
-func (s *readDBService) Members(ctx context.Context, tl util.TimeLineNumber, searchString string, first int, after *string) ([]*models.Member, bool, error) {
-	if first == 0 {
-		first = MaxFetchSize
-	}
-
-	// ask for first + 1 members to know if there're more members
-	members, err := s.members(tl, searchString, first+1, after)
+func (s *readDBService) insertNextAction(tl util.TimeLineNumber, id util.ID, nextAction *models.NextAction) error {
+	q, args, err := nextActionInsert.Values(id, tl, nil, nextAction.Number, nextAction.Description, nextAction.Completed).ToSql()
 	if err != nil {
-		return nil, false, err
+		return errors.Wrap(err, "failed to build query")
 	}
-
-	size := len(members)
-	if len(members) > first {
-		size = first
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
 	}
-	return members[:size], len(members) > first, nil
+	return nil
 }
 
-func (s *readDBService) DirectMemberCircles(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) (map[util.ID][]*models.Role, error) {
-	vs, err := s.connectedVertices(tl, membersIDs, edgeClassCircleDirectMember, edgeDirectionOut, "", nil, nil)
+func (s *readDBService) insertReportItem(tl util.TimeLineNumber, id util.ID, reportItem *models.ReportItem) error {
+	q, args, err := reportItemInsert.Values(id, tl, nil, reportItem.ReportItemType, reportItem.Description, reportItem.Frequency).ToSql()
 	if err != nil {
-		return nil, err
+		return errors.Wrap(err, "failed to build query")
 	}
-	return vs.(map[util.ID][]*models.Role), nil
-}
-
-func (s *readDBService) MemberCircleEdges(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) (map[util.ID][]*models.MemberCircleEdge, error) {
-	memberCircleEdges := map[util.ID][]*models.MemberCircleEdge{}
-
-	memberCircleEdgesMap := map[util.ID]map[util.ID]*models.MemberCircleEdge{}
-	for _, memberID := range membersIDs {
-		memberCircleEdgesMap[memberID] = map[util.ID]*models.MemberCircleEdge{}
-	}
-
-	// Add directly defined circles
-	rolesGroups, err := s.DirectMemberCircles(ctx, tl, membersIDs)
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
 	if err != nil {
-		return nil, err
-	}
-	for _, memberID := range membersIDs {
-		for _, role := range rolesGroups[memberID] {
-			if memberCircleEdge, ok := memberCircleEdgesMap[memberID][role.ID]; ok {
-				memberCircleEdge.IsDirectMember = true
-			} else {
-				memberCircleEdgesMap[memberID][role.ID] = &models.MemberCircleEdge{Role: role, IsDirectMember: true}
-			}
-		}
+		return errors.WithMessage(err, "failed to execute query")
 	}
+	return nil
+}
 
-	// get filled roles
-	memberRoleEdgesGroups, err := s.MemberRoleEdges(ctx, tl, membersIDs)
+func (s *readDBService) insertReportValue(tl util.TimeLineNumber, id util.ID, value *models.ReportItemValue) error {
+	q, args, err := reportValueInsert.Values(id, tl, nil, value.Period, value.Value).ToSql()
 	if err != nil {
-		return nil, err
-	}
-
-	roleIDsMap := map[util.ID]struct{}{}
-	for _, memberID := range membersIDs {
-		for _, memberRoleEdge := range memberRoleEdgesGroups[memberID] {
-			roleIDsMap[memberRoleEdge.Role.ID] = struct{}{}
-		}
-	}
-	roleIDs := []util.ID{}
-	for k := range roleIDsMap {
-		roleIDs = append(roleIDs, k)
+		return errors.Wrap(err, "failed to build query")
 	}
-
-	parentMap := map[util.ID]*models.Role{}
-	// We need roles parents
-	parentGroups, err := s.RoleParent(ctx, tl, roleIDs)
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		_, err = tx.Exec(q, args...)
+		return err
+	})
 	if err != nil {
-		return nil, err
-	}
-	parentIDs := []util.ID{}
-	for roleID, parent := range parentGroups {
-		parentIDs = append(parentIDs, parent.ID)
-		parentMap[roleID] = parent
+		return errors.WithMessage(err, "failed to execute query")
 	}
+	return nil
+}
 
-	// We also need parent of parent to get the replink fillers
-	subParentGroups, err := s.RoleParent(ctx, tl, parentIDs)
+// insertRoleEvent inserts or update a role event
+func (s *readDBService) insertRoleEvent(roleEvent *models.RoleEvent) error {
+	data, err := json.Marshal(roleEvent.Data)
 	if err != nil {
-		return nil, err
-	}
-	for parentID, parentParent := range subParentGroups {
-		parentIDs = append(parentIDs, parentParent.ID)
-		parentMap[parentID] = parentParent
+		return errors.Wrap(err, "failed to marshal event")
 	}
-
-	// Add role fillers (except the ones set as nocoremember)
-	for _, memberID := range membersIDs {
-		for _, memberRoleEdge := range memberRoleEdgesGroups[memberID] {
-			if memberRoleEdge.NoCoreMember {
-				continue
-			}
-			role := memberRoleEdge.Role
-			parent := parentMap[role.ID]
-			if memberCircleEdge, ok := memberCircleEdgesMap[memberID][parent.ID]; ok {
-				memberCircleEdge.FilledRoles = append(memberCircleEdge.FilledRoles, role)
-			} else {
-				memberCircleEdgesMap[memberID][parent.ID] = &models.MemberCircleEdge{Role: parent, FilledRoles: []*models.Role{role}}
-			}
-			if role.RoleType == models.RoleTypeLeadLink {
-				memberCircleEdgesMap[memberID][parent.ID].IsLeadLink = true
-			}
-		}
+	q, args, err := roleEventInsert.Values(roleEvent.TimeLineID, roleEvent.ID, roleEvent.RoleID, roleEvent.EventType, data).ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build query")
 	}
-
-	// Add sub circles replink fillers
-	for _, memberID := range membersIDs {
-		for _, memberRoleEdge := range memberRoleEdgesGroups[memberID] {
-			if memberRoleEdge.Role.RoleType != models.RoleTypeRepLink {
-				continue
-			}
-			role := memberRoleEdge.Role
-			parent := parentMap[role.ID]
-			parentParent := parentMap[parent.ID]
-			if memberCircleEdge, ok := memberCircleEdgesMap[memberID][parentParent.ID]; ok {
-				memberCircleEdge.RepLink = append(memberCircleEdge.RepLink, parent)
-			} else {
-				memberCircleEdgesMap[memberID][parentParent.ID] = &models.MemberCircleEdge{Role: parentParent, RepLink: []*models.Role{parent}}
-			}
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		// poor man insert or update...
+		if _, err := tx.Exec("delete from roleevent where id = $1", roleEvent.ID); err != nil {
+			return errors.Wrap(err, "failed to delete roleevent")
 		}
+		_, err = tx.Exec(q, args...)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to execute query")
 	}
+	return nil
+}
 
-	for _, memberID := range membersIDs {
-		for _, memberCircleEdge := range memberCircleEdgesMap[memberID] {
-			memberCircleEdge.IsCoreMember = memberCircleEdge.IsDirectMember
-			if len(memberCircleEdge.FilledRoles) > 0 {
-				memberCircleEdge.IsCoreMember = true
-			}
-			if len(memberCircleEdge.RepLink) > 0 {
-				memberCircleEdge.IsCoreMember = true
-			}
-			memberCircleEdges[memberID] = append(memberCircleEdges[memberID], memberCircleEdge)
-		}
-
-		// sort memberCircleEdge by role to get repeatable ordered results
-		sort.Sort(models.MemberCircleEdges(memberCircleEdges[memberID]))
-	}
+type readDBService struct {
+	tx                        *db.Tx
+	forcedAdminMemberUserName string
 
-	return memberCircleEdges, nil
+	// cached curTl to not query every time
+	// the DBService lives inside a repreatable read/serializable transaction so
+	// curTl could change only if we are chaning it in this transaction
+	curTl     *util.TimeLine
+	curTlLock sync.Mutex
 }
 
-func (s *readDBService) MemberRoleEdges(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) (map[util.ID][]*models.MemberRoleEdge, error) {
-	vs, err := s.connectedVertices(tl, membersIDs, edgeClassRoleMember, edgeDirectionOut, vertexClassRoleMemberEdge, nil, nil)
+func NewReadDBService(tx *db.Tx) (*readDBService, error) {
+	s := &readDBService{tx: tx}
+
+	curTl, err := s.curTimeLineFromDB()
 	if err != nil {
 		return nil, err
 	}
+	if curTl == nil {
+		curTl = &util.TimeLine{}
+	}
 
-	return vs.(map[util.ID][]*models.MemberRoleEdge), nil
+	s.curTl = curTl
+
+	return s, nil
 }
 
-func (s *readDBService) Tension(ctx context.Context, tl util.TimeLineNumber, tensionID util.ID) (*models.Tension, error) {
-	vs, err := s.vertices(tl, vertexClassTension, 0, sq.Eq{"tension.id": tensionID}, nil)
-	if err != nil {
-		return nil, err
-	}
-	tensions := vs.([]*models.Tension)
-	if len(tensions) == 0 {
-		return nil, nil
-	}
-	return tensions[0], nil
+func (s *readDBService) SetForceAdminMemberUserName(u string) {
+	s.forcedAdminMemberUserName = u
 }
 
-func (s *readDBService) MemberTensions(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) (map[util.ID][]*models.Tension, error) {
-	// Only the member itself can see its tensions
-	member, err := s.CallingMember(ctx, tl)
-	if err != nil {
-		return nil, err
-	}
+func (s *readDBService) curTimeLineFromDB() (*util.TimeLine, error) {
+	// zeroed timeline, also valid if there're no rows
+	var tl util.TimeLine
 
-	for _, memberID := range membersIDs {
-		if member.ID == memberID {
-			break
-		}
+	err := s.tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow("select timestamp from timeline order by timestamp desc limit 1").Scan(&tl.Timestamp)
+	})
+	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 
-	vs, err := s.connectedVertices(tl, membersIDs, edgeClassMemberTension, edgeDirectionIn, "", nil, nil)
-	if err != nil {
-		return nil, err
+	return &tl, err
+}
+
+func (s *readDBService) CurTimeLine(ctx context.Context) *util.TimeLine {
+	s.curTlLock.Lock()
+	defer s.curTlLock.Unlock()
+
+	if s.curTl == nil {
+		return &util.TimeLine{}
 	}
-	tensionsGroups := vs.(map[util.ID][]*models.Tension)
 
-	return tensionsGroups, nil
+	// take a copy of curTl
+	c := *s.curTl
+
+	//log.Debugf("curTl: %s", c)
+	return &c
 }
 
-func (s *readDBService) TensionMember(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID]*models.Member, error) {
-	vs, err := s.connectedVertices(tl, tensionsIDs, edgeClassMemberTension, edgeDirectionOut, "", nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	membersGroups := vs.(map[util.ID][]*models.Member)
+func (s *readDBService) TimeLine(ctx context.Context, sn util.TimeLineNumber) (*util.TimeLine, error) {
+	var tl util.TimeLine
 
-	mg := map[util.ID]*models.Member{}
-	for k, v := range membersGroups {
-		mg[k] = v[0]
+	err := s.tx.Do(func(tx *db.WrappedTx) error {
+		if err := tx.QueryRow("select timestamp from timeline where timestamp = $1", time.Unix(0, int64(sn))).Scan(&tl.Timestamp); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil, errors.Errorf("timeline %d doesn't exists", sn)
 	}
 
-	return mg, nil
+	return &tl, err
 }
 
-func (s *readDBService) RoleTensions(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Tension, error) {
-	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleTension, edgeDirectionIn, "", nil, nil)
-	if err != nil {
-		return nil, err
+func (s *readDBService) TimeLineForGroupID(ctx context.Context, groupID util.ID) (*util.TimeLine, error) {
+	var tl util.TimeLine
+
+	err := s.tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow("select timestamp from timeline where groupid = $1", groupID).Scan(&tl.Timestamp)
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
-	return vs.(map[util.ID][]*models.Tension), nil
+
+	return &tl, err
 }
 
-func (s *readDBService) TensionRole(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID]*models.Role, error) {
-	vs, err := s.connectedVertices(tl, tensionsIDs, edgeClassRoleTension, edgeDirectionOut, "", nil, nil)
-	if err != nil {
-		return nil, err
-	}
-	rolesGroups := vs.(map[util.ID][]*models.Role)
+// TimeLineSequenceNumber returns the sequence number of the last event applied
+// at or before the provided timeline. It returns 0 if there're no events
+// metadata for these timelines (i.e. events applied before the events
+// metadata was recorded).
+func (s *readDBService) TimeLineSequenceNumber(ctx context.Context, tl util.TimeLineNumber) (int64, error) {
+	var sn int64
 
-	mg := map[util.ID]*models.Role{}
-	for k, v := range rolesGroups {
-		mg[k] = v[0]
+	err := s.tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow("select sequencenumber from eventmetadata where timeline <= $1 order by timeline desc, sequencenumber desc limit 1", tl).Scan(&sn)
+	})
+	if err == sql.ErrNoRows {
+		return 0, nil
 	}
 
-	return mg, nil
+	return sn, err
 }
 
-func (s *readDBService) RoleParent(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID]*models.Role, error) {
-	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleRole, edgeDirectionIn, "", nil, nil)
-	if err != nil {
-		return nil, err
+func (s *readDBService) TimeLines(ctx context.Context, ts *time.Time, sn util.TimeLineNumber, limit int, after bool, aggregateType string, aggregateID *util.ID) ([]*util.TimeLine, bool, error) {
+	var tls []*util.TimeLine
+	if limit <= 0 {
+		limit = MaxFetchSize
 	}
-	rolesGroups := vs.(map[util.ID][]*models.Role)
-
-	rg := map[util.ID]*models.Role{}
-	for k, v := range rolesGroups {
-		rg[k] = v[0]
+	if ts == nil {
+		t := time.Unix(0, int64(sn)).UTC()
+		ts = &t
+	}
+	sb := sb.Select("timestamp").From("timeline")
+	// ask for limit + 1 rows to know if there's more data
+	if after {
+		sb = sb.Where(sq.Gt{"timestamp": ts}).OrderBy("timestamp asc")
+	} else {
+		sb = sb.Where(sq.Lt{"timestamp": ts}).OrderBy("timestamp desc")
 	}
+	sb = sb.Limit(uint64(limit + 1))
 
-	return rg, nil
-}
+	if aggregateType != "" {
+		sb = sb.Where(sq.Eq{"aggregatetype": aggregateType})
+	}
+	if aggregateID != nil {
+		sb = sb.Where(sq.Eq{"aggregateid": aggregateID})
+	}
 
-func (s *readDBService) RoleParents(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Role, error) {
-	roleParentsGroups := map[util.ID][]*models.Role{}
-	// TODO(sgotti) use sql WITH RECURSIVE where supported? (postgres)
-	roleParentGroups, err := s.RoleParent(ctx, tl, rolesIDs)
+	q, args, err := sb.ToSql()
 	if err != nil {
-		return nil, err
-	}
-	parents := map[util.ID]*models.Role{}
-	for id, roleParent := range roleParentGroups {
-		parents[id] = roleParent
+		return nil, false, errors.Wrap(err, "failed to build query")
 	}
-	for {
-		// collect role with unknown parent
-		neededMap := map[util.ID]struct{}{}
-		for _, parent := range parents {
-			if _, ok := parents[parent.ID]; !ok {
-				neededMap[parent.ID] = struct{}{}
-			}
-		}
-		needed := []util.ID{}
-		for id := range neededMap {
-			needed = append(needed, id)
-		}
-		roleParentGroups, err = s.RoleParent(ctx, tl, needed)
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(q, args...)
 		if err != nil {
-			return nil, err
-		}
-		if len(roleParentGroups) == 0 {
-			break
+			return err
 		}
-		for id, roleParent := range roleParentGroups {
-			parents[id] = roleParent
+		tls, err = scanTimeLines(rows)
+		if err != nil {
+			return err
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
 	}
 
-	for _, id := range rolesIDs {
-		roleParentsGroups[id] = []*models.Role{}
-		curID := id
-		for {
-			if parent, ok := parents[curID]; ok {
-				roleParentsGroups[id] = append(roleParentsGroups[id], parent)
-				curID = parent.ID
-			} else {
-				break
-			}
-		}
+	size := len(tls)
+	if len(tls) > limit {
+		size = limit
 	}
-
-	return roleParentsGroups, nil
+	return tls[:size], len(tls) > limit, err
 }
 
-func (s *readDBService) CircleCoreRole(ctx context.Context, tl util.TimeLineNumber, roleType models.RoleType, rolesIDs []util.ID) (map[util.ID]*models.Role, error) {
-	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleRole, edgeDirectionOut, "", sq.Eq{"role.roletype": roleType}, nil)
-	if err != nil {
-		return nil, err
-	}
-	rolesGroups := vs.(map[util.ID][]*models.Role)
+func (s *readDBService) TimeLineAtTimeStamp(ctx context.Context, ts time.Time) (*util.TimeLine, error) {
+	var tl util.TimeLine
 
-	rg := map[util.ID]*models.Role{}
-	for k, v := range rolesGroups {
-		rg[k] = v[0]
+	err := s.tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow("select timestamp from timeline where timestamp >= $1 order by timestamp asc limit 1", ts).Scan(&tl.Timestamp)
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
 
-	return rg, nil
+	return &tl, err
 }
 
-func (s *readDBService) CircleDirectMembers(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Member, error) {
-	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassCircleDirectMember, edgeDirectionIn, "", nil, nil)
+func (s *readDBService) RootRole(ctx context.Context, tl util.TimeLineNumber) (*models.Role, error) {
+	vs, err := s.vertices(tl, vertexClassRole, 0, sq.Eq{"role.depth": 0}, nil)
 	if err != nil {
 		return nil, err
 	}
+	roles := vs.([]*models.Role)
 
-	return vs.(map[util.ID][]*models.Member), nil
+	if len(roles) == 0 {
+		return nil, nil
+	}
+	if len(roles) > 1 {
+		return nil, errors.Errorf("too many root roles. This shouldn't happen!")
+	}
+	return roles[0], nil
 }
 
-func (s *readDBService) CircleMemberEdges(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.CircleMemberEdge, error) {
-	circleMemberEdges := map[util.ID][]*models.CircleMemberEdge{}
-
-	circleMemberEdgesMap := map[util.ID]map[util.ID]*models.CircleMemberEdge{}
-	for _, roleID := range rolesIDs {
-		circleMemberEdgesMap[roleID] = map[util.ID]*models.CircleMemberEdge{}
-	}
+func (s *readDBService) Role(ctx context.Context, tl util.TimeLineNumber, id util.ID) (*models.Role, error) {
+	var err error
 
-	// Add directly defined circle members
-	membersGroups, err := s.CircleDirectMembers(ctx, tl, rolesIDs)
+	vs, err := s.vertices(tl, vertexClassRole, 0, sq.Eq{"role.id": id}, nil)
 	if err != nil {
 		return nil, err
 	}
-	for _, roleID := range rolesIDs {
-		for _, member := range membersGroups[roleID] {
-			if circleMemberEdge, ok := circleMemberEdgesMap[roleID][member.ID]; ok {
-				circleMemberEdge.IsDirectMember = true
-			} else {
-				circleMemberEdgesMap[roleID][member.ID] = &models.CircleMemberEdge{Member: member, IsDirectMember: true}
-			}
-		}
-	}
+	roles := vs.([]*models.Role)
 
-	childsGroups, err := s.ChildRoles(ctx, tl, rolesIDs, nil)
-	if err != nil {
-		return nil, err
-	}
-	childsIDsMap := map[util.ID]struct{}{}
-	for _, roleID := range rolesIDs {
-		childs := childsGroups[roleID]
-		for _, child := range childs {
-			childsIDsMap[child.ID] = struct{}{}
-		}
-	}
-	childsIDs := []util.ID{}
-	for k := range childsIDsMap {
-		childsIDs = append(childsIDs, k)
+	if len(roles) < 1 {
+		return nil, nil
 	}
+	return roles[0], nil
+}
 
-	// We also need child of child to get the replink fillers
-	subChildsGroups, err := s.ChildRoles(ctx, tl, childsIDs, nil)
+func (s *readDBService) Roles(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) ([]*models.Role, error) {
+	var condition interface{}
+	if len(rolesIDs) > 0 {
+		condition = sq.Eq{"role.id": rolesIDs}
+	}
+	vs, err := s.vertices(tl, vertexClassRole, 0, condition, []string{"role.name"})
 	if err != nil {
 		return nil, err
 	}
-	for _, childID := range childsIDs {
-		subChilds := subChildsGroups[childID]
-		for _, subChild := range subChilds {
-			childsIDsMap[subChild.ID] = struct{}{}
-		}
-	}
+	roles := vs.([]*models.Role)
 
-	// Merge childs and subchilds in the same list to do just one call to s.getRoleMemberEdges
-	childsIDs = []util.ID{}
-	for k := range childsIDsMap {
-		childsIDs = append(childsIDs, k)
-	}
+	return roles, nil
+}
 
-	roleMemberEdgesGroups, err := s.RoleMemberEdges(ctx, tl, childsIDs, nil)
+func (s *readDBService) ChildRoles(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID, orderBys []string) (map[util.ID][]*models.Role, error) {
+	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleRole, edgeDirectionOut, "", nil, orderBys)
 	if err != nil {
 		return nil, err
 	}
+	roles := vs.(map[util.ID][]*models.Role)
+	return roles, nil
+}
 
-	// Add role fillers (except the ones set as nocoremember)
-	for _, roleID := range rolesIDs {
-		for _, child := range childsGroups[roleID] {
-			for _, roleMemberEdge := range roleMemberEdgesGroups[child.ID] {
-				if roleMemberEdge.NoCoreMember {
-					continue
-				}
-				if circleMemberEdge, ok := circleMemberEdgesMap[roleID][roleMemberEdge.Member.ID]; ok {
-					circleMemberEdge.FilledRoles = append(circleMemberEdge.FilledRoles, child)
-				} else {
-					circleMemberEdgesMap[roleID][roleMemberEdge.Member.ID] = &models.CircleMemberEdge{Member: roleMemberEdge.Member, FilledRoles: []*models.Role{child}}
-				}
-				if child.RoleType == models.RoleTypeLeadLink {
-					circleMemberEdgesMap[roleID][roleMemberEdge.Member.ID].IsLeadLink = true
-				}
-			}
-		}
-	}
-
-	// Add sub circles replink fillers
-	for _, roleID := range rolesIDs {
-		for _, child := range childsGroups[roleID] {
-			for _, subChild := range subChildsGroups[child.ID] {
-				if subChild.RoleType != models.RoleTypeRepLink {
-					continue
-				}
-				for _, roleMemberEdge := range roleMemberEdgesGroups[subChild.ID] {
-					// NOTE(sgotti): there must be only one member filling the replink
-					if circleMemberEdge, ok := circleMemberEdgesMap[roleID][roleMemberEdge.Member.ID]; ok {
-						circleMemberEdge.RepLink = append(circleMemberEdge.RepLink, child)
-					} else {
-						circleMemberEdgesMap[roleID][roleMemberEdge.Member.ID] = &models.CircleMemberEdge{Member: roleMemberEdge.Member, RepLink: []*models.Role{child}}
-					}
-				}
-			}
-		}
-	}
+func (s *readDBService) RoleEventsByType(ctx context.Context, roleID util.ID, tl util.TimeLineNumber, eventType models.RoleEventType) ([]*models.RoleEvent, error) {
 
-	for _, roleID := range rolesIDs {
-		for _, circleMemberEdge := range circleMemberEdgesMap[roleID] {
-			circleMemberEdge.IsCoreMember = circleMemberEdge.IsDirectMember
-			if len(circleMemberEdge.FilledRoles) > 0 {
-				circleMemberEdge.IsCoreMember = true
-			}
-			if len(circleMemberEdge.RepLink) > 0 {
-				circleMemberEdge.IsCoreMember = true
-			}
-			circleMemberEdges[roleID] = append(circleMemberEdges[roleID], circleMemberEdge)
-		}
+	sb := roleEventSelect.Where(sq.Eq{"roleid": roleID, "timeline": tl, "eventtype": string(eventType)})
 
-		// sort circleMemberEdges by member to get repeatable ordered results
-		sort.Sort(models.CircleMemberEdges(circleMemberEdges[roleID]))
+	q, args, err := sb.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build query")
 	}
 
-	return circleMemberEdges, nil
-}
-
-func (s *readDBService) RoleMemberEdges(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID, orderBys []string) (map[util.ID][]*models.RoleMemberEdge, error) {
-	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleMember, edgeDirectionIn, vertexClassRoleMemberEdge, nil, nil)
+	var events []*models.RoleEvent
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return errors.WithMessage(err, "failed to execute query")
+		}
+		events, err = scanRoleEvents(rows)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return vs.(map[util.ID][]*models.RoleMemberEdge), nil
+	return events, nil
 }
 
-func (s *readDBService) RoleDomains(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Domain, error) {
-	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleDomain, edgeDirectionIn, "", nil, nil)
+// TimeLineRoleEvents returns all the role events, of every role, generated at
+// the provided timeline
+func (s *readDBService) TimeLineRoleEvents(ctx context.Context, tl util.TimeLineNumber) ([]*models.RoleEvent, error) {
+	sb := roleEventSelect.Where(sq.Eq{"timeline": tl}).OrderBy("roleid")
+
+	q, args, err := sb.ToSql()
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "failed to build query")
 	}
 
-	return vs.(map[util.ID][]*models.Domain), nil
-}
-
-func (s *readDBService) RoleAccountabilities(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Accountability, error) {
-	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleAccountability, edgeDirectionIn, "", nil, nil)
+	var events []*models.RoleEvent
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return errors.WithMessage(err, "failed to execute query")
+		}
+		events, err = scanRoleEvents(rows)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return vs.(map[util.ID][]*models.Accountability), nil
+	return events, nil
 }
 
-func (s *readDBService) RolesAdditionalContent(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID]*models.RoleAdditionalContent, error) {
-	condition := sq.Eq{"roleadditionalcontent.id": rolesIDs}
-	vs, err := s.vertices(tl, vertexClassRoleAdditionalContent, 0, condition, nil)
-	if err != nil {
-		return nil, err
+func (s *readDBService) RoleEvents(ctx context.Context, roleID util.ID, first int, start, after util.TimeLineNumber) ([]*models.RoleEvent, bool, error) {
+	var condition sq.Sqlizer
+
+	if start != 0 {
+		condition = sq.LtOrEq{"roleevent.timeline": start}
+	}
+	if after != 0 {
+		condition = sq.Lt{"roleevent.timeline": after}
 	}
-	rolesAdditionalContent := vs.([]*models.RoleAdditionalContent)
 
-	rolesAdditionalContentMap := map[util.ID]*models.RoleAdditionalContent{}
-	for _, r := range rolesAdditionalContent {
-		rolesAdditionalContentMap[r.ID] = r
+	sb := roleEventSelect.Where(sq.Eq{"roleid": roleID})
+	sb = sb.OrderBy("timeline desc")
+
+	if condition != nil {
+		sb = sb.Where(condition)
+	}
+
+	if first != 0 {
+		sb = sb.Limit(uint64(first))
 	}
-	return rolesAdditionalContentMap, nil
-}
 
-func (s *readDBService) MemberPassword(ctx context.Context, memberID util.ID) (string, error) {
-	sb := sb.Select("password").From("password").Where(sq.Eq{"memberid": memberID})
 	q, args, err := sb.ToSql()
 	if err != nil {
-		return "", err
+		return nil, false, errors.Wrap(err, "failed to build query")
 	}
 
-	var password string
+	var events []*models.RoleEvent
 	err = s.tx.Do(func(tx *db.WrappedTx) error {
-		return tx.QueryRow(q, args...).Scan(&password)
+		rows, err := tx.Query(q, args...)
+		if err != nil {
+			return errors.WithMessage(err, "failed to execute query")
+		}
+		events, err = scanRoleEvents(rows)
+		return err
 	})
 	if err != nil {
-		return "", err
+		return nil, false, err
 	}
 
-	return password, nil
+	size := len(events)
+	if len(events) > first {
+		size = first
+	}
+	return events[:size], len(events) > first, nil
 }
 
-func (s *readDBService) AuthenticateUIDPassword(ctx context.Context, memberID util.ID, password string) (*models.Member, error) {
-	tl := s.CurTimeLine(ctx)
-
-	member, err := s.Member(ctx, tl.Number(), memberID)
+func (s *readDBService) Member(ctx context.Context, tl util.TimeLineNumber, memberID util.ID) (*models.Member, error) {
+	vs, err := s.vertices(tl, vertexClassMember, 0, sq.Eq{"member.id": memberID}, nil)
 	if err != nil {
 		return nil, err
 	}
-	if member == nil {
-		return nil, errors.Errorf("no member with id: %s", memberID)
+	members := vs.([]*models.Member)
+	if len(members) == 0 {
+		return nil, nil
 	}
+	return members[0], nil
+}
 
-	curPasswordHash, err := s.MemberPassword(ctx, memberID)
+func (s *readDBService) MemberAvatar(ctx context.Context, tl util.TimeLineNumber, id util.ID) (*models.Avatar, error) {
+	vs, err := s.vertices(tl, vertexClassMemberAvatar, 0, sq.Eq{"memberavatar.id": id}, nil)
 	if err != nil {
 		return nil, err
 	}
+	avatars := vs.([]*models.Avatar)
+	if len(avatars) == 0 {
+		return nil, nil
+	}
+	return avatars[0], nil
+}
 
-	ok, err := util.CompareHashAndPassword(curPasswordHash, password)
+func (s *readDBService) MemberMatchUID(ctx context.Context, memberID util.ID) (string, error) {
+	sb := sb.Select("matchUID").From("membermatch").Where(sq.Eq{"memberid": memberID})
+	q, args, err := sb.ToSql()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to check password")
+		return "", err
 	}
-	if !ok {
-		return nil, errors.Errorf("invalid password")
+
+	var matchUID string
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow(q, args...).Scan(&matchUID)
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
 	}
 
-	return member, nil
+	return matchUID, nil
 }
 
-func (s *readDBService) AuthenticateUserNamePassword(ctx context.Context, userName string, password string) (*models.Member, error) {
-	tl := s.CurTimeLine(ctx)
-
-	member, err := s.MemberByUserName(ctx, tl.Number(), userName)
+func (s *readDBService) MemberByMatchUID(ctx context.Context, matchUID string) (*models.Member, error) {
+	sb := sb.Select("memberid").From("membermatch").Where(sq.Eq{"matchUID": matchUID})
+	q, args, err := sb.ToSql()
 	if err != nil {
 		return nil, err
 	}
-	if member == nil {
-		return nil, errors.Errorf("no member with username: %s", userName)
-	}
 
-	curPasswordHash, err := s.MemberPassword(ctx, member.ID)
-	if err != nil {
+	var memberID util.ID
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow(q, args...).Scan(&memberID)
+	})
+	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
-
-	ok, err := util.CompareHashAndPassword(curPasswordHash, password)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to check password")
-	}
-	if !ok {
-		return nil, errors.Errorf("invalid password")
-	}
-
-	return member, nil
+	return s.Member(ctx, s.CurTimeLine(ctx).Number(), memberID)
 }
 
-func (s *readDBService) AuthenticateEmailPassword(ctx context.Context, email string, password string) (*models.Member, error) {
-	tl := s.CurTimeLine(ctx)
-
-	member, err := s.MemberByEmail(ctx, tl.Number(), email)
+func (s *readDBService) MemberByUserName(ctx context.Context, tl util.TimeLineNumber, userName string) (*models.Member, error) {
+	vs, err := s.vertices(tl, vertexClassMember, 0, sq.Eq{"member.username": userName}, nil)
 	if err != nil {
 		return nil, err
 	}
-	if member == nil {
-		return nil, errors.Errorf("no member with email: %s", email)
+	members := vs.([]*models.Member)
+	if len(members) == 0 {
+		return nil, nil
 	}
+	return members[0], nil
+}
 
-	curPasswordHash, err := s.MemberPassword(ctx, member.ID)
+func (s *readDBService) MemberByEmail(ctx context.Context, tl util.TimeLineNumber, email string) (*models.Member, error) {
+	vs, err := s.vertices(tl, vertexClassMember, 0, sq.Eq{"member.email": email}, nil)
 	if err != nil {
 		return nil, err
 	}
-
-	ok, err := util.CompareHashAndPassword(curPasswordHash, password)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to check password")
-	}
-	if !ok {
-		return nil, errors.Errorf("invalid password")
+	members := vs.([]*models.Member)
+	if len(members) == 0 {
+		return nil, nil
 	}
-
-	return member, nil
+	return members[0], nil
 }
 
-func (s *readDBService) CallingMember(ctx context.Context, curTl util.TimeLineNumber) (*models.Member, error) {
-	useridString, ok := ctx.Value("userid").(string)
-	if !ok || useridString == "" {
-		return nil, errors.Errorf("empty userid")
+func (s *readDBService) MembersByIDs(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) ([]*models.Member, error) {
+	var condition interface{}
+	if len(membersIDs) > 0 {
+		condition = sq.Eq{"member.id": membersIDs}
 	}
-	userid, err := uuid.FromString(useridString)
+	vs, err := s.vertices(tl, vertexClassMember, 0, condition, []string{"member.fullname"})
 	if err != nil {
 		return nil, err
 	}
+	members := vs.([]*models.Member)
 
-	// check that the member is valid
-	// TODO(sgotti) check disabled members when implemented
-	member, err := s.Member(ctx, curTl, util.NewFromUUID(userid))
-	if err != nil {
-		return nil, err
-	}
-	if member == nil {
-		return nil, errors.Errorf("unexistent member with id: %s", userid)
-	}
+	return members, nil
+}
 
-	// Set member as admin if defined as forcedAdminMemberUserName
-	if s.forcedAdminMemberUserName == member.UserName {
-		member.IsAdmin = true
+func (s *readDBService) Members(ctx context.Context, tl util.TimeLineNumber, searchString string, filter *MemberFilter, order *MemberOrder, first int, after *util.ID) ([]*models.Member, bool, error) {
+	if first == 0 {
+		first = MaxFetchSize
 	}
 
-	return member, nil
-}
-
-func (s *readDBService) memberIsLeadLink(ctx context.Context, curTl util.TimeLineNumber, memberID, roleID util.ID) (bool, error) {
-	childsGroups, err := s.ChildRoles(ctx, curTl, []util.ID{roleID}, nil)
+	// ask for first + 1 members to know if there're more members
+	members, err := s.members(ctx, tl, searchString, filter, order, first+1, after)
 	if err != nil {
-		return false, err
+		return nil, false, err
 	}
-	childs := childsGroups[roleID]
 
-	var leadLinkRole *models.Role
-	for _, child := range childs {
-		if child.RoleType == models.RoleTypeLeadLink {
-			leadLinkRole = child
-			break
-		}
-	}
-	if leadLinkRole == nil {
-		return false, nil
+	size := len(members)
+	if len(members) > first {
+		size = first
 	}
+	return members[:size], len(members) > first, nil
+}
 
-	roleMemberEdgesGroups, err := s.RoleMemberEdges(ctx, curTl, []util.ID{leadLinkRole.ID}, nil)
+func (s *readDBService) DirectMemberCircles(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) (map[util.ID][]*models.Role, error) {
+	vs, err := s.connectedVertices(tl, membersIDs, edgeClassCircleDirectMember, edgeDirectionOut, "", nil, nil)
 	if err != nil {
-		return false, err
-	}
-	roleMemberEdges := roleMemberEdgesGroups[leadLinkRole.ID]
-
-	// lead link must have at max one assigned member
-	if len(roleMemberEdges) == 0 {
-		return false, nil
+		return nil, err
 	}
-	return roleMemberEdges[0].Member.ID == memberID, nil
+	return vs.(map[util.ID][]*models.Role), nil
 }
 
-// retrieve permission at the circle level
-func (s *readDBService) MemberCirclePermissions(ctx context.Context, tl util.TimeLineNumber, roleID util.ID) (*models.MemberCirclePermissions, error) {
-	cp := &models.MemberCirclePermissions{}
+func (s *readDBService) MemberCircleEdges(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) (map[util.ID][]*models.MemberCircleEdge, error) {
+	memberCircleEdges := map[util.ID][]*models.MemberCircleEdge{}
 
-	callingMember, err := s.CallingMember(ctx, tl)
-	if err != nil {
-		return nil, err
+	memberCircleEdgesMap := map[util.ID]map[util.ID]*models.MemberCircleEdge{}
+	for _, memberID := range membersIDs {
+		memberCircleEdgesMap[memberID] = map[util.ID]*models.MemberCircleEdge{}
 	}
 
-	role, err := s.Role(ctx, tl, roleID)
+	// Add directly defined circles
+	rolesGroups, err := s.DirectMemberCircles(ctx, tl, membersIDs)
 	if err != nil {
 		return nil, err
 	}
-	if role == nil {
-		return nil, errors.Errorf("role with id %s doesn't exist", roleID)
-	}
-	if role.RoleType != models.RoleTypeCircle {
-		// don't return an error since the caller can't know if this is a circle
-		// or another kind of role
-		return nil, nil
+	for _, memberID := range membersIDs {
+		for _, role := range rolesGroups[memberID] {
+			if memberCircleEdge, ok := memberCircleEdgesMap[memberID][role.ID]; ok {
+				memberCircleEdge.IsDirectMember = true
+			} else {
+				memberCircleEdgesMap[memberID][role.ID] = &models.MemberCircleEdge{Role: role, IsDirectMember: true}
+			}
+		}
 	}
 
-	proleGroups, err := s.RoleParent(ctx, tl, []util.ID{roleID})
+	// get filled roles
+	memberRoleEdgesGroups, err := s.MemberRoleEdges(ctx, tl, membersIDs)
 	if err != nil {
 		return nil, err
 	}
-	prole := proleGroups[role.ID]
 
-	isLeadLink, err := s.memberIsLeadLink(ctx, tl, callingMember.ID, roleID)
+	roleIDsMap := map[util.ID]struct{}{}
+	for _, memberID := range membersIDs {
+		for _, memberRoleEdge := range memberRoleEdgesGroups[memberID] {
+			roleIDsMap[memberRoleEdge.Role.ID] = struct{}{}
+		}
+	}
+	roleIDs := []util.ID{}
+	for k := range roleIDsMap {
+		roleIDs = append(roleIDs, k)
+	}
+
+	parentMap := map[util.ID]*models.Role{}
+	// We need roles parents
+	parentGroups, err := s.RoleParent(ctx, tl, roleIDs)
 	if err != nil {
 		return nil, err
 	}
+	parentIDs := []util.ID{}
+	for roleID, parent := range parentGroups {
+		parentIDs = append(parentIDs, parent.ID)
+		parentMap[roleID] = parent
+	}
 
-	// Only the circle lead link can assign child circles lead links
-	if callingMember.IsAdmin || isLeadLink {
-		cp.AssignChildCircleLeadLink = true
+	// We also need parent of parent to get the replink fillers
+	subParentGroups, err := s.RoleParent(ctx, tl, parentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for parentID, parentParent := range subParentGroups {
+		parentIDs = append(parentIDs, parentParent.ID)
+		parentMap[parentID] = parentParent
 	}
 
-	// Only the circle lead link can assign member to core roles
-	if callingMember.IsAdmin || isLeadLink {
-		cp.AssignCircleCoreRoles = true
+	// Add role fillers (except the ones set as nocoremember)
+	for _, memberID := range membersIDs {
+		for _, memberRoleEdge := range memberRoleEdgesGroups[memberID] {
+			if memberRoleEdge.NoCoreMember {
+				continue
+			}
+			role := memberRoleEdge.Role
+			parent := parentMap[role.ID]
+			if memberCircleEdge, ok := memberCircleEdgesMap[memberID][parent.ID]; ok {
+				memberCircleEdge.FilledRoles = append(memberCircleEdge.FilledRoles, role)
+			} else {
+				memberCircleEdgesMap[memberID][parent.ID] = &models.MemberCircleEdge{Role: parent, FilledRoles: []*models.Role{role}}
+			}
+			if role.RoleType == models.RoleTypeLeadLink {
+				memberCircleEdgesMap[memberID][parent.ID].IsLeadLink = true
+			}
+		}
 	}
 
-	// Only the circle lead link can assign member assignment to child roles
-	if callingMember.IsAdmin || isLeadLink {
-		cp.AssignChildRoleMembers = true
+	// Add sub circles replink fillers
+	for _, memberID := range membersIDs {
+		for _, memberRoleEdge := range memberRoleEdgesGroups[memberID] {
+			if memberRoleEdge.Role.RoleType != models.RoleTypeRepLink {
+				continue
+			}
+			role := memberRoleEdge.Role
+			parent := parentMap[role.ID]
+			parentParent := parentMap[parent.ID]
+			if memberCircleEdge, ok := memberCircleEdgesMap[memberID][parentParent.ID]; ok {
+				memberCircleEdge.RepLink = append(memberCircleEdge.RepLink, parent)
+			} else {
+				memberCircleEdgesMap[memberID][parentParent.ID] = &models.MemberCircleEdge{Role: parentParent, RepLink: []*models.Role{parent}}
+			}
+		}
 	}
 
-	// Only the circle lead link can assign direct member
-	if callingMember.IsAdmin || isLeadLink {
-		cp.AssignCircleDirectMembers = true
+	for _, memberID := range membersIDs {
+		for _, memberCircleEdge := range memberCircleEdgesMap[memberID] {
+			memberCircleEdge.IsCoreMember = memberCircleEdge.IsDirectMember
+			if len(memberCircleEdge.FilledRoles) > 0 {
+				memberCircleEdge.IsCoreMember = true
+			}
+			if len(memberCircleEdge.RepLink) > 0 {
+				memberCircleEdge.IsCoreMember = true
+			}
+			memberCircleEdges[memberID] = append(memberCircleEdges[memberID], memberCircleEdge)
+		}
+
+		// sort memberCircleEdge by role to get repeatable ordered results
+		sort.Sort(models.MemberCircleEdges(memberCircleEdges[memberID]))
 	}
 
-	// Only the circle lead link can manage roles
-	if callingMember.IsAdmin || isLeadLink {
-		cp.ManageChildRoles = true
+	return memberCircleEdges, nil
+}
+
+func (s *readDBService) MemberRoleEdges(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) (map[util.ID][]*models.MemberRoleEdge, error) {
+	vs, err := s.connectedVertices(tl, membersIDs, edgeClassRoleMember, edgeDirectionOut, vertexClassRoleMemberEdge, nil, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	// Only the circle lead link can manage additional content
-	if callingMember.IsAdmin || isLeadLink {
-		cp.ManageRoleAdditionalContent = true
+	return vs.(map[util.ID][]*models.MemberRoleEdge), nil
+}
+
+func (s *readDBService) Tension(ctx context.Context, tl util.TimeLineNumber, tensionID util.ID) (*models.Tension, error) {
+	vs, err := s.vertices(tl, vertexClassTension, 0, sq.Eq{"tension.id": tensionID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	tensions := vs.([]*models.Tension)
+	if len(tensions) == 0 {
+		return nil, nil
 	}
+	return tensions[0], nil
+}
 
-	// As a special case, on the root role(circle), its lead link can manage the
-	// circle data and its lead link
-	if prole == nil {
-		if callingMember.IsAdmin || isLeadLink {
-			cp.AssignRootCircleLeadLink = true
-			cp.ManageRootCircle = true
+func (s *readDBService) MemberTensions(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) (map[util.ID][]*models.Tension, error) {
+	// Only the member itself can see its tensions
+	member, err := s.CallingMember(ctx, tl)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, memberID := range membersIDs {
+		if member.ID == memberID {
+			break
 		}
+		return nil, nil
 	}
 
-	return cp, nil
+	vs, err := s.connectedVertices(tl, membersIDs, edgeClassMemberTension, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	tensionsGroups := vs.(map[util.ID][]*models.Tension)
+
+	return tensionsGroups, nil
 }
 
-type DBEventHandler struct {
-	db *db.DB
-	es *eventstore.EventStore
-	nf ln.NotifierFactory
+// MemberOpenTensions returns the open tensions of the provided member. Unlike
+// MemberTensions it doesn't check the calling member so it must be used only
+// internally
+func (s *readDBService) MemberOpenTensions(ctx context.Context, tl util.TimeLineNumber, memberID util.ID) ([]*models.Tension, error) {
+	vs, err := s.connectedVertices(tl, []util.ID{memberID}, edgeClassMemberTension, edgeDirectionIn, "", sq.Eq{"tension.closed": false}, nil)
+	if err != nil {
+		return nil, err
+	}
+	tensionsGroups := vs.(map[util.ID][]*models.Tension)
+
+	return tensionsGroups[memberID], nil
 }
 
-func NewDBEventHandler(db *db.DB, es *eventstore.EventStore, nf ln.NotifierFactory) *DBEventHandler {
-	return &DBEventHandler{
-		db: db,
-		es: es,
-		nf: nf,
+func (s *readDBService) TensionMember(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID]*models.Member, error) {
+	vs, err := s.connectedVertices(tl, tensionsIDs, edgeClassMemberTension, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
 	}
+	membersGroups := vs.(map[util.ID][]*models.Member)
+
+	mg := map[util.ID]*models.Member{}
+	for k, v := range membersGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
 }
 
-func (h *DBEventHandler) Name() string {
-	return "readdb"
+func (s *readDBService) RoleTensions(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Tension, error) {
+	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleTension, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.Tension), nil
 }
 
-func (h *DBEventHandler) HandleEvents() error {
-	var sn int64
-	err := h.db.Do(func(tx *db.Tx) error {
-		err := tx.Do(func(tx *db.WrappedTx) error {
-			return tx.QueryRow("select sequencenumber from sequencenumber order by sequencenumber desc limit 1").Scan(&sn)
-		})
-		if err != nil {
-			if err == sql.ErrNoRows {
-				sn = 0
-			} else {
-				return err
-			}
-		}
-		return nil
+func (s *readDBService) TensionRole(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID]*models.Role, error) {
+	vs, err := s.connectedVertices(tl, tensionsIDs, edgeClassRoleTension, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	rolesGroups := vs.(map[util.ID][]*models.Role)
 
-	})
+	mg := map[util.ID]*models.Role{}
+	for k, v := range rolesGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) TensionAssignee(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID]*models.Member, error) {
+	vs, err := s.connectedVertices(tl, tensionsIDs, edgeClassTensionAssignee, edgeDirectionOut, "", nil, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	membersGroups := vs.(map[util.ID][]*models.Member)
 
-	notifier := h.nf.NewNotifier()
-	hasTxNotifier := false
-	txNotifier, ok := notifier.(ln.TxNotifier)
-	if ok {
-		hasTxNotifier = true
+	mg := map[util.ID]*models.Member{}
+	for k, v := range membersGroups {
+		mg[k] = v[0]
 	}
 
-	for {
-		events, err := h.es.GetAllEvents(sn+1, 100)
-		if err != nil {
-			return err
-		}
+	return mg, nil
+}
 
-		if len(events) == 0 {
-			return nil
-		}
+func (s *readDBService) TensionAssigneeRole(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID]*models.Role, error) {
+	vs, err := s.connectedVertices(tl, tensionsIDs, edgeClassTensionAssigneeRole, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	rolesGroups := vs.(map[util.ID][]*models.Role)
 
-		err = h.db.Do(func(tx *db.Tx) error {
-			readDBService, err := NewReadDBService(tx)
-			if err != nil {
-				return err
-			}
+	mg := map[util.ID]*models.Role{}
+	for k, v := range rolesGroups {
+		mg[k] = v[0]
+	}
 
-			for _, e := range events {
-				if err := h.handleEvent(e, tx, readDBService); err != nil {
-					return err
-				}
+	return mg, nil
+}
 
-				err = tx.Do(func(tx *db.WrappedTx) error {
-					if _, err := tx.Exec("insert into sequencenumber (sequencenumber) values ($1)", e.SequenceNumber); err != nil {
-						return errors.Wrap(err, "failed to save eventstate")
-					}
-					return nil
-				})
-				if err != nil {
-					return err
-				}
-			}
+func (s *readDBService) TensionComments(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID][]*models.TensionComment, error) {
+	vs, err := s.connectedVertices(tl, tensionsIDs, edgeClassTensionComment, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.TensionComment), nil
+}
 
-			if hasTxNotifier {
-				txNotifier.BindTx(tx)
-				return txNotifier.Notify("readdb", "")
-			}
-			return nil
+func (s *readDBService) CommentParent(ctx context.Context, tl util.TimeLineNumber, commentsIDs []util.ID) (map[util.ID]*models.TensionComment, error) {
+	vs, err := s.connectedVertices(tl, commentsIDs, edgeClassCommentReply, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	commentsGroups := vs.(map[util.ID][]*models.TensionComment)
+
+	mg := map[util.ID]*models.TensionComment{}
+	for k, v := range commentsGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) CommentReplies(ctx context.Context, tl util.TimeLineNumber, commentsIDs []util.ID) (map[util.ID][]*models.TensionComment, error) {
+	vs, err := s.connectedVertices(tl, commentsIDs, edgeClassCommentReply, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.TensionComment), nil
+}
+
+func (s *readDBService) CommentMember(ctx context.Context, tl util.TimeLineNumber, commentsIDs []util.ID) (map[util.ID]*models.Member, error) {
+	vs, err := s.connectedVertices(tl, commentsIDs, edgeClassCommentMember, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	membersGroups := vs.(map[util.ID][]*models.Member)
+
+	mg := map[util.ID]*models.Member{}
+	for k, v := range membersGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) TensionLabels(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID][]*models.TensionLabel, error) {
+	vs, err := s.connectedVertices(tl, tensionsIDs, edgeClassTensionLabel, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.TensionLabel), nil
+}
+
+// tensionLabelID returns the id of the label vertex of the tension label. It's
+// derived from them since the events don't provide it.
+func tensionLabelID(tensionID util.ID, name string) util.ID {
+	return util.NewFromUUID(uuid.NewV5(tensionID.UUID, name))
+}
+
+func (s *readDBService) Proposal(ctx context.Context, tl util.TimeLineNumber, proposalID util.ID) (*models.Proposal, error) {
+	vs, err := s.vertices(tl, vertexClassProposal, 0, sq.Eq{"proposal.id": proposalID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	proposals := vs.([]*models.Proposal)
+	if len(proposals) == 0 {
+		return nil, nil
+	}
+	return proposals[0], nil
+}
+
+func (s *readDBService) TensionProposals(ctx context.Context, tl util.TimeLineNumber, tensionsIDs []util.ID) (map[util.ID][]*models.Proposal, error) {
+	vs, err := s.connectedVertices(tl, tensionsIDs, edgeClassTensionProposal, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.Proposal), nil
+}
+
+func (s *readDBService) ProposalTension(ctx context.Context, tl util.TimeLineNumber, proposalsIDs []util.ID) (map[util.ID]*models.Tension, error) {
+	vs, err := s.connectedVertices(tl, proposalsIDs, edgeClassTensionProposal, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	tensionsGroups := vs.(map[util.ID][]*models.Tension)
+
+	mg := map[util.ID]*models.Tension{}
+	for k, v := range tensionsGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) ProposalRole(ctx context.Context, tl util.TimeLineNumber, proposalsIDs []util.ID) (map[util.ID]*models.Role, error) {
+	vs, err := s.connectedVertices(tl, proposalsIDs, edgeClassRoleProposal, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	rolesGroups := vs.(map[util.ID][]*models.Role)
+
+	mg := map[util.ID]*models.Role{}
+	for k, v := range rolesGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) ProposalMember(ctx context.Context, tl util.TimeLineNumber, proposalsIDs []util.ID) (map[util.ID]*models.Member, error) {
+	vs, err := s.connectedVertices(tl, proposalsIDs, edgeClassMemberProposal, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	membersGroups := vs.(map[util.ID][]*models.Member)
+
+	mg := map[util.ID]*models.Member{}
+	for k, v := range membersGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) Meeting(ctx context.Context, tl util.TimeLineNumber, meetingID util.ID) (*models.Meeting, error) {
+	vs, err := s.vertices(tl, vertexClassMeeting, 0, sq.Eq{"meeting.id": meetingID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	meetings := vs.([]*models.Meeting)
+	if len(meetings) == 0 {
+		return nil, nil
+	}
+	return meetings[0], nil
+}
+
+func (s *readDBService) RoleMeetings(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Meeting, error) {
+	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleMeeting, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.Meeting), nil
+}
+
+func (s *readDBService) MeetingRole(ctx context.Context, tl util.TimeLineNumber, meetingsIDs []util.ID) (map[util.ID]*models.Role, error) {
+	vs, err := s.connectedVertices(tl, meetingsIDs, edgeClassRoleMeeting, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	rolesGroups := vs.(map[util.ID][]*models.Role)
+
+	mg := map[util.ID]*models.Role{}
+	for k, v := range rolesGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) MeetingAttendees(ctx context.Context, tl util.TimeLineNumber, meetingsIDs []util.ID) (map[util.ID][]*models.Member, error) {
+	vs, err := s.connectedVertices(tl, meetingsIDs, edgeClassMeetingAttendee, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.Member), nil
+}
+
+func (s *readDBService) MeetingFacilitator(ctx context.Context, tl util.TimeLineNumber, meetingsIDs []util.ID) (map[util.ID]*models.Member, error) {
+	vs, err := s.connectedVertices(tl, meetingsIDs, edgeClassMeetingFacilitator, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	membersGroups := vs.(map[util.ID][]*models.Member)
+
+	mg := map[util.ID]*models.Member{}
+	for k, v := range membersGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) MeetingSecretary(ctx context.Context, tl util.TimeLineNumber, meetingsIDs []util.ID) (map[util.ID]*models.Member, error) {
+	vs, err := s.connectedVertices(tl, meetingsIDs, edgeClassMeetingSecretary, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	membersGroups := vs.(map[util.ID][]*models.Member)
+
+	mg := map[util.ID]*models.Member{}
+	for k, v := range membersGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) Project(ctx context.Context, tl util.TimeLineNumber, projectID util.ID) (*models.Project, error) {
+	vs, err := s.vertices(tl, vertexClassProject, 0, sq.Eq{"project.id": projectID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	projects := vs.([]*models.Project)
+	if len(projects) == 0 {
+		return nil, nil
+	}
+	return projects[0], nil
+}
+
+func (s *readDBService) MeetingAgendaItems(ctx context.Context, tl util.TimeLineNumber, meetingsIDs []util.ID) (map[util.ID][]*models.MeetingAgendaItem, error) {
+	vs, err := s.connectedVertices(tl, meetingsIDs, edgeClassMeetingAgendaItem, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.MeetingAgendaItem), nil
+}
+
+func (s *readDBService) AgendaItemTension(ctx context.Context, tl util.TimeLineNumber, agendaItemsIDs []util.ID) (map[util.ID]*models.Tension, error) {
+	vs, err := s.connectedVertices(tl, agendaItemsIDs, edgeClassAgendaItemTension, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	tensionsGroups := vs.(map[util.ID][]*models.Tension)
+
+	mg := map[util.ID]*models.Tension{}
+	for k, v := range tensionsGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) agendaItem(tl util.TimeLineNumber, agendaItemID util.ID) (*models.MeetingAgendaItem, error) {
+	vs, err := s.vertices(tl, vertexClassAgendaItem, 0, sq.Eq{"agendaitem.id": agendaItemID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	agendaItems := vs.([]*models.MeetingAgendaItem)
+	if len(agendaItems) == 0 {
+		return nil, nil
+	}
+	return agendaItems[0], nil
+}
+
+func (s *readDBService) RoleProjects(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Project, error) {
+	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleProject, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.Project), nil
+}
+
+func (s *readDBService) MemberProjects(ctx context.Context, tl util.TimeLineNumber, membersIDs []util.ID) (map[util.ID][]*models.Project, error) {
+	vs, err := s.connectedVertices(tl, membersIDs, edgeClassMemberProject, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.Project), nil
+}
+
+func (s *readDBService) ProjectRole(ctx context.Context, tl util.TimeLineNumber, projectsIDs []util.ID) (map[util.ID]*models.Role, error) {
+	vs, err := s.connectedVertices(tl, projectsIDs, edgeClassRoleProject, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	rolesGroups := vs.(map[util.ID][]*models.Role)
+
+	mg := map[util.ID]*models.Role{}
+	for k, v := range rolesGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) ProjectMember(ctx context.Context, tl util.TimeLineNumber, projectsIDs []util.ID) (map[util.ID]*models.Member, error) {
+	vs, err := s.connectedVertices(tl, projectsIDs, edgeClassMemberProject, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	membersGroups := vs.(map[util.ID][]*models.Member)
+
+	mg := map[util.ID]*models.Member{}
+	for k, v := range membersGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) ProjectNextActions(ctx context.Context, tl util.TimeLineNumber, projectsIDs []util.ID) (map[util.ID][]*models.NextAction, error) {
+	vs, err := s.connectedVertices(tl, projectsIDs, edgeClassProjectNextAction, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.NextAction), nil
+}
+
+func (s *readDBService) nextAction(tl util.TimeLineNumber, nextActionID util.ID) (*models.NextAction, error) {
+	vs, err := s.vertices(tl, vertexClassNextAction, 0, sq.Eq{"nextaction.id": nextActionID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	nextActions := vs.([]*models.NextAction)
+	if len(nextActions) == 0 {
+		return nil, nil
+	}
+	return nextActions[0], nil
+}
+
+func (s *readDBService) ReportItem(ctx context.Context, tl util.TimeLineNumber, reportItemID util.ID) (*models.ReportItem, error) {
+	vs, err := s.vertices(tl, vertexClassReportItem, 0, sq.Eq{"reportitem.id": reportItemID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	reportItems := vs.([]*models.ReportItem)
+	if len(reportItems) == 0 {
+		return nil, nil
+	}
+	return reportItems[0], nil
+}
+
+func (s *readDBService) CircleReportItems(ctx context.Context, tl util.TimeLineNumber, circlesIDs []util.ID) (map[util.ID][]*models.ReportItem, error) {
+	vs, err := s.connectedVertices(tl, circlesIDs, edgeClassCircleReportItem, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.ReportItem), nil
+}
+
+func (s *readDBService) RoleReportItems(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.ReportItem, error) {
+	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleReportItem, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.ReportItem), nil
+}
+
+func (s *readDBService) ReportItemCircle(ctx context.Context, tl util.TimeLineNumber, reportItemsIDs []util.ID) (map[util.ID]*models.Role, error) {
+	vs, err := s.connectedVertices(tl, reportItemsIDs, edgeClassCircleReportItem, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	rolesGroups := vs.(map[util.ID][]*models.Role)
+
+	mg := map[util.ID]*models.Role{}
+	for k, v := range rolesGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) ReportItemRole(ctx context.Context, tl util.TimeLineNumber, reportItemsIDs []util.ID) (map[util.ID]*models.Role, error) {
+	vs, err := s.connectedVertices(tl, reportItemsIDs, edgeClassRoleReportItem, edgeDirectionOut, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	rolesGroups := vs.(map[util.ID][]*models.Role)
+
+	mg := map[util.ID]*models.Role{}
+	for k, v := range rolesGroups {
+		mg[k] = v[0]
+	}
+
+	return mg, nil
+}
+
+func (s *readDBService) ReportItemValues(ctx context.Context, tl util.TimeLineNumber, reportItemsIDs []util.ID) (map[util.ID][]*models.ReportItemValue, error) {
+	vs, err := s.connectedVertices(tl, reportItemsIDs, edgeClassReportItemValue, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vs.(map[util.ID][]*models.ReportItemValue), nil
+}
+
+func (s *readDBService) reportValue(tl util.TimeLineNumber, valueID util.ID) (*models.ReportItemValue, error) {
+	vs, err := s.vertices(tl, vertexClassReportValue, 0, sq.Eq{"reportvalue.id": valueID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	values := vs.([]*models.ReportItemValue)
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return values[0], nil
+}
+
+// reportValueID returns the id of the report value vertex of the report item
+// period. It's derived from them since the events don't provide it.
+func reportValueID(reportItemID util.ID, period time.Time) util.ID {
+	return util.NewFromUUID(uuid.NewV5(reportItemID.UUID, period.UTC().Format(time.RFC3339)))
+}
+
+func (s *readDBService) RoleParent(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID]*models.Role, error) {
+	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleRole, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	rolesGroups := vs.(map[util.ID][]*models.Role)
+
+	rg := map[util.ID]*models.Role{}
+	for k, v := range rolesGroups {
+		rg[k] = v[0]
+	}
+
+	return rg, nil
+}
+
+func (s *readDBService) RoleParents(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Role, error) {
+	roleParentsGroups := map[util.ID][]*models.Role{}
+	// TODO(sgotti) use sql WITH RECURSIVE where supported? (postgres)
+	roleParentGroups, err := s.RoleParent(ctx, tl, rolesIDs)
+	if err != nil {
+		return nil, err
+	}
+	parents := map[util.ID]*models.Role{}
+	for id, roleParent := range roleParentGroups {
+		parents[id] = roleParent
+	}
+	for {
+		// collect role with unknown parent
+		neededMap := map[util.ID]struct{}{}
+		for _, parent := range parents {
+			if _, ok := parents[parent.ID]; !ok {
+				neededMap[parent.ID] = struct{}{}
+			}
+		}
+		needed := []util.ID{}
+		for id := range neededMap {
+			needed = append(needed, id)
+		}
+		roleParentGroups, err = s.RoleParent(ctx, tl, needed)
+		if err != nil {
+			return nil, err
+		}
+		if len(roleParentGroups) == 0 {
+			break
+		}
+		for id, roleParent := range roleParentGroups {
+			parents[id] = roleParent
+		}
+	}
+
+	for _, id := range rolesIDs {
+		roleParentsGroups[id] = []*models.Role{}
+		curID := id
+		for {
+			if parent, ok := parents[curID]; ok {
+				roleParentsGroups[id] = append(roleParentsGroups[id], parent)
+				curID = parent.ID
+			} else {
+				break
+			}
+		}
+	}
+
+	return roleParentsGroups, nil
+}
+
+func (s *readDBService) CircleCoreRole(ctx context.Context, tl util.TimeLineNumber, roleType models.RoleType, rolesIDs []util.ID) (map[util.ID]*models.Role, error) {
+	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleRole, edgeDirectionOut, "", sq.Eq{"role.roletype": roleType}, nil)
+	if err != nil {
+		return nil, err
+	}
+	rolesGroups := vs.(map[util.ID][]*models.Role)
+
+	rg := map[util.ID]*models.Role{}
+	for k, v := range rolesGroups {
+		rg[k] = v[0]
+	}
+
+	return rg, nil
+}
+
+func (s *readDBService) CircleDirectMembers(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Member, error) {
+	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassCircleDirectMember, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return vs.(map[util.ID][]*models.Member), nil
+}
+
+func (s *readDBService) CircleMemberEdges(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.CircleMemberEdge, error) {
+	circleMemberEdges := map[util.ID][]*models.CircleMemberEdge{}
+
+	circleMemberEdgesMap := map[util.ID]map[util.ID]*models.CircleMemberEdge{}
+	for _, roleID := range rolesIDs {
+		circleMemberEdgesMap[roleID] = map[util.ID]*models.CircleMemberEdge{}
+	}
+
+	// Add directly defined circle members
+	membersGroups, err := s.CircleDirectMembers(ctx, tl, rolesIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, roleID := range rolesIDs {
+		for _, member := range membersGroups[roleID] {
+			if circleMemberEdge, ok := circleMemberEdgesMap[roleID][member.ID]; ok {
+				circleMemberEdge.IsDirectMember = true
+			} else {
+				circleMemberEdgesMap[roleID][member.ID] = &models.CircleMemberEdge{Member: member, IsDirectMember: true}
+			}
+		}
+	}
+
+	childsGroups, err := s.ChildRoles(ctx, tl, rolesIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+	childsIDsMap := map[util.ID]struct{}{}
+	for _, roleID := range rolesIDs {
+		childs := childsGroups[roleID]
+		for _, child := range childs {
+			childsIDsMap[child.ID] = struct{}{}
+		}
+	}
+	childsIDs := []util.ID{}
+	for k := range childsIDsMap {
+		childsIDs = append(childsIDs, k)
+	}
+
+	// We also need child of child to get the replink fillers
+	subChildsGroups, err := s.ChildRoles(ctx, tl, childsIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, childID := range childsIDs {
+		subChilds := subChildsGroups[childID]
+		for _, subChild := range subChilds {
+			childsIDsMap[subChild.ID] = struct{}{}
+		}
+	}
+
+	// Merge childs and subchilds in the same list to do just one call to s.getRoleMemberEdges
+	childsIDs = []util.ID{}
+	for k := range childsIDsMap {
+		childsIDs = append(childsIDs, k)
+	}
+
+	roleMemberEdgesGroups, err := s.RoleMemberEdges(ctx, tl, childsIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add role fillers (except the ones set as nocoremember)
+	for _, roleID := range rolesIDs {
+		for _, child := range childsGroups[roleID] {
+			for _, roleMemberEdge := range roleMemberEdgesGroups[child.ID] {
+				if roleMemberEdge.NoCoreMember {
+					continue
+				}
+				if circleMemberEdge, ok := circleMemberEdgesMap[roleID][roleMemberEdge.Member.ID]; ok {
+					circleMemberEdge.FilledRoles = append(circleMemberEdge.FilledRoles, child)
+				} else {
+					circleMemberEdgesMap[roleID][roleMemberEdge.Member.ID] = &models.CircleMemberEdge{Member: roleMemberEdge.Member, FilledRoles: []*models.Role{child}}
+				}
+				if child.RoleType == models.RoleTypeLeadLink {
+					circleMemberEdgesMap[roleID][roleMemberEdge.Member.ID].IsLeadLink = true
+				}
+			}
+		}
+	}
+
+	// Add sub circles replink fillers
+	for _, roleID := range rolesIDs {
+		for _, child := range childsGroups[roleID] {
+			for _, subChild := range subChildsGroups[child.ID] {
+				if subChild.RoleType != models.RoleTypeRepLink {
+					continue
+				}
+				for _, roleMemberEdge := range roleMemberEdgesGroups[subChild.ID] {
+					// NOTE(sgotti): there must be only one member filling the replink
+					if circleMemberEdge, ok := circleMemberEdgesMap[roleID][roleMemberEdge.Member.ID]; ok {
+						circleMemberEdge.RepLink = append(circleMemberEdge.RepLink, child)
+					} else {
+						circleMemberEdgesMap[roleID][roleMemberEdge.Member.ID] = &models.CircleMemberEdge{Member: roleMemberEdge.Member, RepLink: []*models.Role{child}}
+					}
+				}
+			}
+		}
+	}
+
+	for _, roleID := range rolesIDs {
+		for _, circleMemberEdge := range circleMemberEdgesMap[roleID] {
+			circleMemberEdge.IsCoreMember = circleMemberEdge.IsDirectMember
+			if len(circleMemberEdge.FilledRoles) > 0 {
+				circleMemberEdge.IsCoreMember = true
+			}
+			if len(circleMemberEdge.RepLink) > 0 {
+				circleMemberEdge.IsCoreMember = true
+			}
+			circleMemberEdges[roleID] = append(circleMemberEdges[roleID], circleMemberEdge)
+		}
+
+		// sort circleMemberEdges by member to get repeatable ordered results
+		sort.Sort(models.CircleMemberEdges(circleMemberEdges[roleID]))
+	}
+
+	return circleMemberEdges, nil
+}
+
+func (s *readDBService) RoleMemberEdges(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID, orderBys []string) (map[util.ID][]*models.RoleMemberEdge, error) {
+	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleMember, edgeDirectionIn, vertexClassRoleMemberEdge, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return vs.(map[util.ID][]*models.RoleMemberEdge), nil
+}
+
+func (s *readDBService) RoleDomains(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Domain, error) {
+	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleDomain, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return vs.(map[util.ID][]*models.Domain), nil
+}
+
+func (s *readDBService) RoleAccountabilities(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID][]*models.Accountability, error) {
+	vs, err := s.connectedVertices(tl, rolesIDs, edgeClassRoleAccountability, edgeDirectionIn, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return vs.(map[util.ID][]*models.Accountability), nil
+}
+
+func (s *readDBService) RolesAdditionalContent(ctx context.Context, tl util.TimeLineNumber, rolesIDs []util.ID) (map[util.ID]*models.RoleAdditionalContent, error) {
+	condition := sq.Eq{"roleadditionalcontent.id": rolesIDs}
+	vs, err := s.vertices(tl, vertexClassRoleAdditionalContent, 0, condition, nil)
+	if err != nil {
+		return nil, err
+	}
+	rolesAdditionalContent := vs.([]*models.RoleAdditionalContent)
+
+	rolesAdditionalContentMap := map[util.ID]*models.RoleAdditionalContent{}
+	for _, r := range rolesAdditionalContent {
+		rolesAdditionalContentMap[r.ID] = r
+	}
+	return rolesAdditionalContentMap, nil
+}
+
+func (s *readDBService) MemberPassword(ctx context.Context, memberID util.ID) (string, error) {
+	sb := sb.Select("password").From("password").Where(sq.Eq{"memberid": memberID})
+	q, args, err := sb.ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	var password string
+	err = s.tx.Do(func(tx *db.WrappedTx) error {
+		return tx.QueryRow(q, args...).Scan(&password)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return password, nil
+}
+
+func (s *readDBService) AuthenticateUIDPassword(ctx context.Context, memberID util.ID, password string) (*models.Member, error) {
+	tl := s.CurTimeLine(ctx)
+
+	member, err := s.Member(ctx, tl.Number(), memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errors.Errorf("no member with id: %s", memberID)
+	}
+
+	curPasswordHash, err := s.MemberPassword(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := util.CompareHashAndPassword(curPasswordHash, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check password")
+	}
+	if !ok {
+		return nil, errors.Errorf("invalid password")
+	}
+
+	return member, nil
+}
+
+func (s *readDBService) AuthenticateUserNamePassword(ctx context.Context, userName string, password string) (*models.Member, error) {
+	tl := s.CurTimeLine(ctx)
+
+	member, err := s.MemberByUserName(ctx, tl.Number(), userName)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errors.Errorf("no member with username: %s", userName)
+	}
+
+	curPasswordHash, err := s.MemberPassword(ctx, member.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := util.CompareHashAndPassword(curPasswordHash, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check password")
+	}
+	if !ok {
+		return nil, errors.Errorf("invalid password")
+	}
+
+	return member, nil
+}
+
+func (s *readDBService) AuthenticateEmailPassword(ctx context.Context, email string, password string) (*models.Member, error) {
+	tl := s.CurTimeLine(ctx)
+
+	member, err := s.MemberByEmail(ctx, tl.Number(), email)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errors.Errorf("no member with email: %s", email)
+	}
+
+	curPasswordHash, err := s.MemberPassword(ctx, member.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := util.CompareHashAndPassword(curPasswordHash, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check password")
+	}
+	if !ok {
+		return nil, errors.Errorf("invalid password")
+	}
+
+	return member, nil
+}
+
+func (s *readDBService) CallingMember(ctx context.Context, curTl util.TimeLineNumber) (*models.Member, error) {
+	useridString, ok := ctx.Value("userid").(string)
+	if !ok || useridString == "" {
+		return nil, errors.Errorf("empty userid")
+	}
+	userid, err := uuid.FromString(useridString)
+	if err != nil {
+		return nil, err
+	}
+
+	// check that the member is valid (a deleted member doesn't exist at the
+	// current timeline)
+	// TODO(sgotti) check disabled members when implemented
+	member, err := s.Member(ctx, curTl, util.NewFromUUID(userid))
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errors.Errorf("unexistent member with id: %s", userid)
+	}
+
+	// Set member as admin if defined as forcedAdminMemberUserName
+	if s.forcedAdminMemberUserName == member.UserName {
+		member.IsAdmin = true
+	}
+
+	return member, nil
+}
+
+func (s *readDBService) memberIsLeadLink(ctx context.Context, curTl util.TimeLineNumber, memberID, roleID util.ID) (bool, error) {
+	return s.memberIsCoreRoleMember(ctx, curTl, memberID, roleID, models.RoleTypeLeadLink)
+}
+
+func (s *readDBService) memberIsCoreRoleMember(ctx context.Context, curTl util.TimeLineNumber, memberID, roleID util.ID, roleType models.RoleType) (bool, error) {
+	childsGroups, err := s.ChildRoles(ctx, curTl, []util.ID{roleID}, nil)
+	if err != nil {
+		return false, err
+	}
+	childs := childsGroups[roleID]
+
+	var coreRole *models.Role
+	for _, child := range childs {
+		if child.RoleType == roleType {
+			coreRole = child
+			break
+		}
+	}
+	if coreRole == nil {
+		return false, nil
+	}
+
+	roleMemberEdgesGroups, err := s.RoleMemberEdges(ctx, curTl, []util.ID{coreRole.ID}, nil)
+	if err != nil {
+		return false, err
+	}
+	roleMemberEdges := roleMemberEdgesGroups[coreRole.ID]
+
+	// core roles must have at max one assigned member
+	if len(roleMemberEdges) == 0 {
+		return false, nil
+	}
+	return roleMemberEdges[0].Member.ID == memberID, nil
+}
+
+// retrieve permission at the circle level
+func (s *readDBService) MemberCirclePermissions(ctx context.Context, tl util.TimeLineNumber, roleID util.ID) (*models.MemberCirclePermissions, error) {
+	cp := &models.MemberCirclePermissions{}
+
+	callingMember, err := s.CallingMember(ctx, tl)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.Role(ctx, tl, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, errors.Errorf("role with id %s doesn't exist", roleID)
+	}
+	if role.RoleType != models.RoleTypeCircle {
+		// don't return an error since the caller can't know if this is a circle
+		// or another kind of role
+		return nil, nil
+	}
+
+	proleGroups, err := s.RoleParent(ctx, tl, []util.ID{roleID})
+	if err != nil {
+		return nil, err
+	}
+	prole := proleGroups[role.ID]
+
+	isLeadLink, err := s.memberIsLeadLink(ctx, tl, callingMember.ID, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	isSecretary, err := s.memberIsCoreRoleMember(ctx, tl, callingMember.ID, roleID, models.RoleTypeSecretary)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the circle lead link can assign child circles lead links
+	if callingMember.IsAdmin || isLeadLink {
+		cp.AssignChildCircleLeadLink = true
+	}
+
+	// Only the circle lead link can assign member to core roles
+	if callingMember.IsAdmin || isLeadLink {
+		cp.AssignCircleCoreRoles = true
+	}
+
+	// Only the circle lead link can assign member assignment to child roles
+	if callingMember.IsAdmin || isLeadLink {
+		cp.AssignChildRoleMembers = true
+	}
+
+	// Only the circle lead link can assign direct member
+	if callingMember.IsAdmin || isLeadLink {
+		cp.AssignCircleDirectMembers = true
+	}
+
+	// Only the circle lead link can manage roles
+	if callingMember.IsAdmin || isLeadLink {
+		cp.ManageChildRoles = true
+	}
+
+	// Only the circle lead link can manage additional content
+	if callingMember.IsAdmin || isLeadLink {
+		cp.ManageRoleAdditionalContent = true
+	}
+
+	// Only the circle lead link or secretary can accept proposals
+	if callingMember.IsAdmin || isLeadLink || isSecretary {
+		cp.AcceptProposals = true
+	}
+
+	// Only the circle lead link or secretary can manage checklist items and
+	// metrics
+	if callingMember.IsAdmin || isLeadLink || isSecretary {
+		cp.ManageReportItems = true
+	}
+
+	// As a special case, on the root role(circle), its lead link can manage the
+	// circle data and its lead link
+	if prole == nil {
+		if callingMember.IsAdmin || isLeadLink {
+			cp.AssignRootCircleLeadLink = true
+			cp.ManageRootCircle = true
+		}
+	}
+
+	return cp, nil
+}
+
+// retrieve permission at the tension level
+func (s *readDBService) MemberTensionPermissions(ctx context.Context, tl util.TimeLineNumber, tensionID util.ID) (*models.MemberTensionPermissions, error) {
+	tp := &models.MemberTensionPermissions{}
+
+	callingMember, err := s.CallingMember(ctx, tl)
+	if err != nil {
+		return nil, err
+	}
+
+	tension, err := s.Tension(ctx, tl, tensionID)
+	if err != nil {
+		return nil, err
+	}
+	if tension == nil {
+		return nil, errors.Errorf("tension with id %s doesn't exist", tensionID)
+	}
+
+	tensionMemberGroups, err := s.TensionMember(ctx, tl, []util.ID{tensionID})
+	if err != nil {
+		return nil, err
+	}
+	// the tension member is nil when the member has been deleted
+	isTensionMember := false
+	if tensionMember, ok := tensionMemberGroups[tensionID]; ok {
+		isTensionMember = tensionMember.ID == callingMember.ID
+	}
+
+	tensionRoleGroups, err := s.TensionRole(ctx, tl, []util.ID{tensionID})
+	if err != nil {
+		return nil, err
+	}
+	isLeadLink := false
+	if tensionRole, ok := tensionRoleGroups[tensionID]; ok {
+		isLeadLink, err = s.memberIsLeadLink(ctx, tl, callingMember.ID, tensionRole.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tensionAssigneeGroups, err := s.TensionAssignee(ctx, tl, []util.ID{tensionID})
+	if err != nil {
+		return nil, err
+	}
+	isAssignee := false
+	if tensionAssignee, ok := tensionAssigneeGroups[tensionID]; ok {
+		isAssignee = tensionAssignee.ID == callingMember.ID
+	}
+
+	// Only the tension member or the tension circle lead link can manage the
+	// tension
+	if callingMember.IsAdmin || isTensionMember || isLeadLink {
+		tp.ManageTension = true
+	}
+
+	// The assignee can also change the tension state
+	if tp.ManageTension || isAssignee {
+		tp.ChangeTensionState = true
+	}
+
+	// Only the members that can see the tension (and the assignee) can
+	// read and add comments
+	if tp.ManageTension || isAssignee {
+		tp.Comment = true
+	}
+
+	return tp, nil
+}
+
+type DBEventHandler struct {
+	db *db.DB
+	es eventstore.EventStore
+	nf ln.NotifierFactory
+}
+
+func NewDBEventHandler(db *db.DB, es eventstore.EventStore, nf ln.NotifierFactory) *DBEventHandler {
+	return &DBEventHandler{
+		db: db,
+		es: es,
+		nf: nf,
+	}
+}
+
+func (h *DBEventHandler) Name() string {
+	return "readdb"
+}
+
+func (h *DBEventHandler) HandleEvents() error {
+	return h.handleEvents(nil)
+}
+
+// handleEvents applies all the new events, if provided progress is called
+// with the last applied sequence number after every batch of events
+func (h *DBEventHandler) handleEvents(progress func(sn int64)) error {
+	var sn int64
+	err := h.db.Do(func(tx *db.Tx) error {
+		err := tx.Do(func(tx *db.WrappedTx) error {
+			return tx.QueryRow("select sequencenumber from sequencenumber order by sequencenumber desc limit 1").Scan(&sn)
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				sn = 0
+			} else {
+				return err
+			}
+		}
+		return nil
+
+	})
+	if err != nil {
+		return err
+	}
+
+	notifier := h.nf.NewNotifier()
+	hasTxNotifier := false
+	txNotifier, ok := notifier.(ln.TxNotifier)
+	if ok {
+		hasTxNotifier = true
+	}
+
+	for {
+		events, err := h.es.GetAllEvents(sn+1, 100)
+		if err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		err = h.db.Do(func(tx *db.Tx) error {
+			readDBService, err := NewReadDBService(tx)
+			if err != nil {
+				return err
+			}
+
+			for _, e := range events {
+				if err := h.handleEvent(e, tx, readDBService); err != nil {
+					return err
+				}
+
+				err = tx.Do(func(tx *db.WrappedTx) error {
+					if _, err := tx.Exec("insert into sequencenumber (sequencenumber) values ($1)", e.SequenceNumber); err != nil {
+						return errors.Wrap(err, "failed to save eventstate")
+					}
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			if hasTxNotifier {
+				txNotifier.BindTx(tx)
+				return txNotifier.Notify("readdb", "")
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if !hasTxNotifier {
+			if err := notifier.Notify("readdb", ""); err != nil {
+				return err
+			}
+		}
+
+		sn = events[len(events)-1].SequenceNumber
+		if progress != nil {
+			progress(sn)
+		}
+	}
+
+	return nil
+}
+
+func (h *DBEventHandler) handleEvent(event *eventstore.StoredEvent, tx *db.Tx, s *readDBService) error {
+	log.Debugf("event: %v", event)
+
+	metaData, err := ep.UnmarshalMetaData(event)
+	if err != nil {
+		return err
+	}
+
+	if metaData.GroupID == nil {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	tl, err := s.TimeLineForGroupID(ctx, *metaData.GroupID)
+	if err != nil {
+		return err
+	}
+	if tl == nil {
+		log.Debugf("no tl with groupID: %s", metaData.GroupID)
+		tl = &util.TimeLine{
+			Timestamp: event.Timestamp,
+		}
+
+		err := tx.Do(func(tx *db.WrappedTx) error {
+			if _, err := tx.Exec("insert into timeline (timestamp, groupid, aggregatetype, aggregateid) values ($1, $2, $3, $4)", tl.Timestamp, metaData.GroupID.UUID, event.Category, event.StreamID); err != nil {
+				return errors.Wrap(err, "failed to insert timeline")
+			}
+			return nil
 		})
 		if err != nil {
 			return err
 		}
+		// Reread to inserted timeline since postgres has a microsecond resolution
+		// so the nanosecond will be lost
+		tl, err = s.TimeLineForGroupID(ctx, *metaData.GroupID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+	log.Debugf("tl: %d", tl)
+
+	if err := s.insertEventMetaData(tl.Number(), event, metaData); err != nil {
+		return err
+	}
+
+	data, err := ep.UnmarshalData(event)
+	if err != nil {
+		return err
+	}
+
+	switch ep.EventType(event.EventType) {
+	case ep.EventTypeRoleCreated:
+		data := data.(*ep.EventRoleCreated)
+		// We have to calculate the role depth
+		depth := int32(0)
+		if data.ParentRoleID != nil {
+			prole, err := s.Role(ctx, tl.Number(), *data.ParentRoleID)
+			if err != nil {
+				return err
+			}
+			if prole == nil {
+				return errors.Errorf("role with id %s doesn't exist", *data.ParentRoleID)
+			}
+			depth = prole.Depth + 1
+		}
+		role := &models.Role{
+			RoleType: data.RoleType,
+			Depth:    depth,
+			Name:     data.Name,
+			Purpose:  data.Purpose,
+		}
+		if err := s.newVertex(tl.Number(), data.RoleID, vertexClassRole, role); err != nil {
+			return err
+		}
+		if data.ParentRoleID != nil {
+			if err := s.addEdge(tl.Number(), edgeClassRoleRole, *data.ParentRoleID, data.RoleID); err != nil {
+				return err
+			}
+		}
+
+	case ep.EventTypeRoleDeleted:
+		data := data.(*ep.EventRoleDeleted)
+		proleGroups, err := s.RoleParent(ctx, tl.Number(), []util.ID{data.RoleID})
+		if err != nil {
+			return err
+		}
+		prole := proleGroups[data.RoleID]
+		if err := s.deleteVertex(tl.Number(), vertexClassRole, data.RoleID); err != nil {
+			return err
+		}
+		// close the edges between the role and its meetings
+		vs, err := s.connectedVertices(tl.Number(), []util.ID{data.RoleID}, edgeClassRoleMeeting, edgeDirectionIn, "", nil, nil)
+		if err != nil {
+			return err
+		}
+		for _, meeting := range vs.(map[util.ID][]*models.Meeting)[data.RoleID] {
+			if err := s.deleteEdge(tl.Number(), edgeClassRoleMeeting, meeting.ID, data.RoleID); err != nil {
+				return err
+			}
+		}
+		// close the edges between the role and its projects
+		vs, err = s.connectedVertices(tl.Number(), []util.ID{data.RoleID}, edgeClassRoleProject, edgeDirectionIn, "", nil, nil)
+		if err != nil {
+			return err
+		}
+		for _, project := range vs.(map[util.ID][]*models.Project)[data.RoleID] {
+			if err := s.deleteEdge(tl.Number(), edgeClassRoleProject, project.ID, data.RoleID); err != nil {
+				return err
+			}
+		}
+		// close the edges between the role and its report items
+		for _, ec := range []edgeClass{edgeClassCircleReportItem, edgeClassRoleReportItem} {
+			vs, err = s.connectedVertices(tl.Number(), []util.ID{data.RoleID}, ec, edgeDirectionIn, "", nil, nil)
+			if err != nil {
+				return err
+			}
+			for _, reportItem := range vs.(map[util.ID][]*models.ReportItem)[data.RoleID] {
+				if err := s.deleteEdge(tl.Number(), ec, reportItem.ID, data.RoleID); err != nil {
+					return err
+				}
+			}
+		}
+		if prole != nil {
+			if err := s.deleteEdge(tl.Number(), edgeClassRoleRole, prole.ID, data.RoleID); err != nil {
+				return err
+			}
+		}
+
+	case ep.EventTypeRoleUpdated:
+		data := data.(*ep.EventRoleUpdated)
+		// We have to retrieve the current role depth
+		crole, err := s.Role(ctx, tl.Number(), data.RoleID)
+		if err != nil {
+			return err
+		}
+		if crole == nil {
+			return errors.Errorf("role with id %s doesn't exist", data.RoleID)
+		}
+		role := &models.Role{
+			RoleType: data.RoleType,
+			Depth:    crole.Depth,
+			Name:     data.Name,
+			Purpose:  data.Purpose,
+		}
+		if err := s.updateVertex(tl.Number(), vertexClassRole, data.RoleID, role); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleDomainCreated:
+		data := data.(*ep.EventRoleDomainCreated)
+		domainID := data.DomainID
+		domain := &models.Domain{
+			Description: data.Description,
+		}
+		if err := s.newVertex(tl.Number(), domainID, vertexClassDomain, domain); err != nil {
+			return err
+		}
+		if err := s.addEdge(tl.Number(), edgeClassRoleDomain, domainID, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleDomainUpdated:
+		data := data.(*ep.EventRoleDomainUpdated)
+		domainID := data.DomainID
+		domain := &models.Domain{
+			Description: data.Description,
+		}
+		if err := s.updateVertex(tl.Number(), vertexClassDomain, domainID, domain); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleDomainDeleted:
+		data := data.(*ep.EventRoleDomainDeleted)
+		domainID := data.DomainID
+		if err := s.deleteVertex(tl.Number(), vertexClassDomain, domainID); err != nil {
+			return err
+		}
+		if err := s.deleteEdge(tl.Number(), edgeClassRoleDomain, domainID, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleAccountabilityCreated:
+		data := data.(*ep.EventRoleAccountabilityCreated)
+		accountabilityID := data.AccountabilityID
+		accountability := &models.Accountability{
+			Description: data.Description,
+		}
+		if err := s.newVertex(tl.Number(), accountabilityID, vertexClassAccountability, accountability); err != nil {
+			return err
+		}
+		if err := s.addEdge(tl.Number(), edgeClassRoleAccountability, accountabilityID, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleAccountabilityUpdated:
+		data := data.(*ep.EventRoleAccountabilityUpdated)
+		accountabilityID := data.AccountabilityID
+		accountability := &models.Accountability{
+			Description: data.Description,
+		}
+		if err := s.updateVertex(tl.Number(), vertexClassAccountability, accountabilityID, accountability); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleAccountabilityDeleted:
+		data := data.(*ep.EventRoleAccountabilityDeleted)
+		accountabilityID := data.AccountabilityID
+		if err := s.deleteVertex(tl.Number(), vertexClassAccountability, accountabilityID); err != nil {
+			return err
+		}
+		if err := s.deleteEdge(tl.Number(), edgeClassRoleAccountability, accountabilityID, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleAdditionalContentSet:
+		data := data.(*ep.EventRoleAdditionalContentSet)
+		roleAdditionalContent := &models.RoleAdditionalContent{
+			Content: data.Content,
+		}
+		if err := s.updateVertex(tl.Number(), vertexClassRoleAdditionalContent, data.RoleID, roleAdditionalContent); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleChangedParent:
+		data := data.(*ep.EventRoleChangedParent)
+		if err := s.changeRoleParent(ctx, tl.Number(), data.RoleID, data.ParentRoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleMemberAdded:
+		data := data.(*ep.EventRoleMemberAdded)
+		if err := s.roleAddMember(tl.Number(), data.RoleID, data.MemberID, data.Focus, data.NoCoreMember); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleMemberUpdated:
+		data := data.(*ep.EventRoleMemberUpdated)
+		if err := s.roleUpdateMember(tl.Number(), data.RoleID, data.MemberID, data.Focus, data.NoCoreMember); err != nil {
+			return err
+		}
+
+	case ep.EventTypeRoleMemberRemoved:
+		data := data.(*ep.EventRoleMemberRemoved)
+		if err := s.roleRemoveMember(tl.Number(), data.RoleID, data.MemberID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeCircleDirectMemberAdded:
+		data := data.(*ep.EventCircleDirectMemberAdded)
+		if err := s.addEdge(tl.Number(), edgeClassCircleDirectMember, data.MemberID, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeCircleDirectMemberRemoved:
+		data := data.(*ep.EventCircleDirectMemberRemoved)
+		if err := s.circleRemoveDirectMember(tl.Number(), data.RoleID, data.MemberID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeCircleLeadLinkMemberSet:
+		data := data.(*ep.EventCircleLeadLinkMemberSet)
+		if err := s.addEdge(tl.Number(), edgeClassRoleMember, data.MemberID, data.LeadLinkRoleID, nil, false, nil); err != nil {
+			return err
+		}
+
+	case ep.EventTypeCircleLeadLinkMemberUnset:
+		data := data.(*ep.EventCircleLeadLinkMemberUnset)
+		if err := s.deleteEdge(tl.Number(), edgeClassRoleMember, data.MemberID, data.LeadLinkRoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeCircleCoreRoleMemberSet:
+		data := data.(*ep.EventCircleCoreRoleMemberSet)
+		if err := s.addEdge(tl.Number(), edgeClassRoleMember, data.MemberID, data.CoreRoleID, nil, false, data.ElectionExpiration); err != nil {
+			return err
+		}
+
+	case ep.EventTypeCircleCoreRoleMemberUnset:
+		data := data.(*ep.EventCircleCoreRoleMemberUnset)
+		if err := s.deleteEdge(tl.Number(), edgeClassRoleMember, data.MemberID, data.CoreRoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeCircleCoreRoleElectionExpired:
+
+	case ep.EventTypeCircleProposalApplied:
+
+	case ep.EventTypeTensionCreated:
+		data := data.(*ep.EventTensionCreated)
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		tension := &models.Tension{
+			Title:       data.Title,
+			Description: data.Description,
+			Closed:      false,
+			State:       models.TensionStateOpen,
+		}
+		if err := s.newVertex(tl.Number(), tensionID, vertexClassTension, tension); err != nil {
+			return err
+		}
+		if err := s.addEdge(tl.Number(), edgeClassMemberTension, tensionID, data.MemberID); err != nil {
+			return err
+		}
+		if data.RoleID != nil {
+			if err := s.addEdge(tl.Number(), edgeClassRoleTension, tensionID, *data.RoleID); err != nil {
+				return err
+			}
+		}
+
+	case ep.EventTypeTensionUpdated:
+		data := data.(*ep.EventTensionUpdated)
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		tension, err := s.Tension(ctx, tl.Number(), tensionID)
+		if err != nil {
+			return err
+		}
+		if tension == nil {
+			return errors.Errorf("tension with id %s doesn't exist", tensionID)
+		}
+
+		tension.Title = data.Title
+		tension.Description = data.Description
+		if err := s.updateVertex(tl.Number(), vertexClassTension, tensionID, tension); err != nil {
+			return err
+		}
+
+	case ep.EventTypeTensionRoleChanged:
+		data := data.(*ep.EventTensionRoleChanged)
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		if data.PrevRoleID != nil {
+			if err := s.deleteEdge(tl.Number(), edgeClassRoleTension, tensionID, *data.PrevRoleID); err != nil {
+				return err
+			}
+		}
+		if data.RoleID != nil {
+			if err := s.addEdge(tl.Number(), edgeClassRoleTension, tensionID, *data.RoleID); err != nil {
+				return err
+			}
+		}
+
+	case ep.EventTypeTensionClosed:
+		data := data.(*ep.EventTensionClosed)
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		tension, err := s.Tension(ctx, tl.Number(), tensionID)
+		if err != nil {
+			return err
+		}
+		if tension == nil {
+			return errors.Errorf("tension with id %s doesn't exist", tensionID)
+		}
+
+		tension.Closed = true
+		tension.CloseReason = data.Reason
+		tension.State = models.TensionStateClosed
+		if err := s.updateVertex(tl.Number(), vertexClassTension, tensionID, tension); err != nil {
+			return err
+		}
+
+	case ep.EventTypeTensionReopened:
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		tension, err := s.Tension(ctx, tl.Number(), tensionID)
+		if err != nil {
+			return err
+		}
+		if tension == nil {
+			return errors.Errorf("tension with id %s doesn't exist", tensionID)
+		}
+
+		tension.Closed = false
+		tension.CloseReason = ""
+		tension.State = models.TensionStateOpen
+		if err := s.updateVertex(tl.Number(), vertexClassTension, tensionID, tension); err != nil {
+			return err
+		}
+
+	case ep.EventTypeTensionStateChanged:
+		data := data.(*ep.EventTensionStateChanged)
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		tension, err := s.Tension(ctx, tl.Number(), tensionID)
+		if err != nil {
+			return err
+		}
+		if tension == nil {
+			return errors.Errorf("tension with id %s doesn't exist", tensionID)
+		}
+
+		tension.State = data.State
+		if err := s.updateVertex(tl.Number(), vertexClassTension, tensionID, tension); err != nil {
+			return err
+		}
+
+	case ep.EventTypeTensionCommentAdded:
+		data := data.(*ep.EventTensionCommentAdded)
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		tension, err := s.Tension(ctx, tl.Number(), tensionID)
+		if err != nil {
+			return err
+		}
+		if tension == nil {
+			return errors.Errorf("tension with id %s doesn't exist", tensionID)
+		}
+
+		comments, err := s.TensionComments(ctx, tl.Number(), []util.ID{tensionID})
+		if err != nil {
+			return err
+		}
+
+		comment := &models.TensionComment{
+			Number:   len(comments[tensionID]),
+			Text:     data.Text,
+			TimeLine: tl.Number(),
+		}
+		if err := s.newVertex(tl.Number(), data.CommentID, vertexClassComment, comment); err != nil {
+			return err
+		}
+		if err := s.addEdge(tl.Number(), edgeClassTensionComment, data.CommentID, tensionID); err != nil {
+			return err
+		}
+		if err := s.addEdge(tl.Number(), edgeClassCommentMember, data.CommentID, data.MemberID); err != nil {
+			return err
+		}
+		if data.ParentCommentID != nil {
+			if err := s.addEdge(tl.Number(), edgeClassCommentReply, data.CommentID, *data.ParentCommentID); err != nil {
+				return err
+			}
+		}
+
+	case ep.EventTypeTensionAssigneeChanged:
+		data := data.(*ep.EventTensionAssigneeChanged)
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		if data.PreviousMemberID != nil {
+			if err := s.deleteEdge(tl.Number(), edgeClassTensionAssignee, tensionID, *data.PreviousMemberID); err != nil {
+				return err
+			}
+		}
+		if data.PreviousRoleID != nil {
+			if err := s.deleteEdge(tl.Number(), edgeClassTensionAssigneeRole, tensionID, *data.PreviousRoleID); err != nil {
+				return err
+			}
+		}
+		if data.MemberID != nil {
+			if err := s.addEdge(tl.Number(), edgeClassTensionAssignee, tensionID, *data.MemberID); err != nil {
+				return err
+			}
+		}
+		if data.RoleID != nil {
+			if err := s.addEdge(tl.Number(), edgeClassTensionAssigneeRole, tensionID, *data.RoleID); err != nil {
+				return err
+			}
+		}
+
+	case ep.EventTypeTensionLabelsSet:
+		data := data.(*ep.EventTensionLabelsSet)
+		tensionID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		tension, err := s.Tension(ctx, tl.Number(), tensionID)
+		if err != nil {
+			return err
+		}
+		if tension == nil {
+			return errors.Errorf("tension with id %s doesn't exist", tensionID)
+		}
+
+		labelsGroups, err := s.TensionLabels(ctx, tl.Number(), []util.ID{tensionID})
+		if err != nil {
+			return err
+		}
+		labels := map[string]*models.TensionLabel{}
+		for _, label := range labelsGroups[tensionID] {
+			labels[label.Name] = label
+		}
+		newLabels := map[string]struct{}{}
+		for _, name := range data.Labels {
+			newLabels[name] = struct{}{}
+		}
+
+		// remove the labels not set anymore
+		for name, label := range labels {
+			if _, ok := newLabels[name]; ok {
+				continue
+			}
+			if err := s.deleteVertex(tl.Number(), vertexClassLabel, label.ID); err != nil {
+				return err
+			}
+			if err := s.deleteEdge(tl.Number(), edgeClassTensionLabel, label.ID, tensionID); err != nil {
+				return err
+			}
+		}
+		// add the new labels and update the position of the existing ones
+		for i, name := range data.Labels {
+			label, ok := labels[name]
+			if !ok {
+				labelID := tensionLabelID(tensionID, name)
+				if err := s.newVertex(tl.Number(), labelID, vertexClassLabel, &models.TensionLabel{Number: i, Name: name}); err != nil {
+					return err
+				}
+				if err := s.addEdge(tl.Number(), edgeClassTensionLabel, labelID, tensionID); err != nil {
+					return err
+				}
+				continue
+			}
+			if label.Number != i {
+				label.Number = i
+				if err := s.updateVertex(tl.Number(), vertexClassLabel, label.ID, label); err != nil {
+					return err
+				}
+			}
+		}
+
+	case ep.EventTypeProposalCreated:
+		data := data.(*ep.EventProposalCreated)
+		proposalID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		changes, err := json.Marshal(data.ProposalChanges)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal proposal changes")
+		}
+
+		proposal := &models.Proposal{
+			State:   models.ProposalStateOpen,
+			Changes: string(changes),
+		}
+		if err := s.newVertex(tl.Number(), proposalID, vertexClassProposal, proposal); err != nil {
+			return err
+		}
+		if err := s.addEdge(tl.Number(), edgeClassTensionProposal, proposalID, data.TensionID); err != nil {
+			return err
+		}
+		if err := s.addEdge(tl.Number(), edgeClassRoleProposal, proposalID, data.RoleID); err != nil {
+			return err
+		}
+		if err := s.addEdge(tl.Number(), edgeClassMemberProposal, proposalID, data.MemberID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeProposalUpdated:
+		data := data.(*ep.EventProposalUpdated)
+		proposalID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		proposal, err := s.Proposal(ctx, tl.Number(), proposalID)
+		if err != nil {
+			return err
+		}
+		if proposal == nil {
+			return errors.Errorf("proposal with id %s doesn't exist", proposalID)
+		}
 
-		if !hasTxNotifier {
-			if err := notifier.Notify("readdb", ""); err != nil {
-				return err
-			}
+		changes, err := json.Marshal(data.ProposalChanges)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal proposal changes")
 		}
 
-		sn = events[len(events)-1].SequenceNumber
-	}
+		proposal.Changes = string(changes)
+		// the objections are related to the previous changes
+		proposal.Objections = nil
+		if err := s.updateVertex(tl.Number(), vertexClassProposal, proposalID, proposal); err != nil {
+			return err
+		}
 
-	return nil
-}
+	case ep.EventTypeProposalObjectionRaised:
+		data := data.(*ep.EventProposalObjectionRaised)
+		proposalID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
 
-func (h *DBEventHandler) handleEvent(event *eventstore.StoredEvent, tx *db.Tx, s *readDBService) error {
-	log.Debugf("event: %v", event)
+		proposal, err := s.Proposal(ctx, tl.Number(), proposalID)
+		if err != nil {
+			return err
+		}
+		if proposal == nil {
+			return errors.Errorf("proposal with id %s doesn't exist", proposalID)
+		}
 
-	metaData, err := ep.UnmarshalMetaData(event)
-	if err != nil {
-		return err
-	}
+		proposal.Objections = append(proposal.Objections, &models.ProposalObjection{MemberID: data.MemberID, Reason: data.Reason})
+		if err := s.updateVertex(tl.Number(), vertexClassProposal, proposalID, proposal); err != nil {
+			return err
+		}
 
-	if metaData.GroupID == nil {
-		return nil
-	}
+	case ep.EventTypeProposalAccepted:
+		proposalID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
 
-	ctx := context.Background()
+		proposal, err := s.Proposal(ctx, tl.Number(), proposalID)
+		if err != nil {
+			return err
+		}
+		if proposal == nil {
+			return errors.Errorf("proposal with id %s doesn't exist", proposalID)
+		}
 
-	tl, err := s.TimeLineForGroupID(ctx, *metaData.GroupID)
-	if err != nil {
-		return err
-	}
-	if tl == nil {
-		log.Debugf("no tl with groupID: %s", metaData.GroupID)
-		tl = &util.TimeLine{
-			Timestamp: event.Timestamp,
+		proposal.State = models.ProposalStateAccepted
+		if err := s.updateVertex(tl.Number(), vertexClassProposal, proposalID, proposal); err != nil {
+			return err
 		}
 
-		err := tx.Do(func(tx *db.WrappedTx) error {
-			if _, err := tx.Exec("insert into timeline (timestamp, groupid, aggregatetype, aggregateid) values ($1, $2, $3, $4)", tl.Timestamp, metaData.GroupID.UUID, event.Category, event.StreamID); err != nil {
-				return errors.Wrap(err, "failed to insert timeline")
-			}
-			return nil
-		})
+	case ep.EventTypeProposalAcceptanceReverted:
+		proposalID, err := util.IDFromString(event.StreamID)
 		if err != nil {
 			return err
 		}
-		// Reread to inserted timeline since postgres has a microsecond resolution
-		// so the nanosecond will be lost
-		tl, err = s.TimeLineForGroupID(ctx, *metaData.GroupID)
+
+		proposal, err := s.Proposal(ctx, tl.Number(), proposalID)
 		if err != nil {
 			return err
 		}
-	}
+		if proposal == nil {
+			return errors.Errorf("proposal with id %s doesn't exist", proposalID)
+		}
 
-	if err != nil {
-		return err
-	}
-	log.Debugf("tl: %d", tl)
+		proposal.State = models.ProposalStateOpen
+		if err := s.updateVertex(tl.Number(), vertexClassProposal, proposalID, proposal); err != nil {
+			return err
+		}
 
-	data, err := ep.UnmarshalData(event)
-	if err != nil {
-		return err
-	}
+	case ep.EventTypeMeetingCreated:
+		data := data.(*ep.EventMeetingCreated)
+		meetingID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
 
-	switch ep.EventType(event.EventType) {
-	case ep.EventTypeRoleCreated:
-		data := data.(*ep.EventRoleCreated)
-		// We have to calculate the role depth
-		depth := int32(0)
-		if data.ParentRoleID != nil {
-			prole, err := s.Role(ctx, tl.Number(), *data.ParentRoleID)
-			if err != nil {
+		meeting := &models.Meeting{
+			MeetingType: data.MeetingType,
+			Title:       data.Title,
+			StartTime:   data.StartTime,
+		}
+		if err := s.newVertex(tl.Number(), meetingID, vertexClassMeeting, meeting); err != nil {
+			return err
+		}
+		if err := s.addEdge(tl.Number(), edgeClassRoleMeeting, meetingID, data.RoleID); err != nil {
+			return err
+		}
+		if data.FacilitatorID != nil {
+			if err := s.addEdge(tl.Number(), edgeClassMeetingFacilitator, *data.FacilitatorID, meetingID); err != nil {
 				return err
 			}
-			if prole == nil {
-				return errors.Errorf("role with id %s doesn't exist", *data.ParentRoleID)
+		}
+		if data.SecretaryID != nil {
+			if err := s.addEdge(tl.Number(), edgeClassMeetingSecretary, *data.SecretaryID, meetingID); err != nil {
+				return err
 			}
-			depth = prole.Depth + 1
 		}
-		role := &models.Role{
-			RoleType: data.RoleType,
-			Depth:    depth,
-			Name:     data.Name,
-			Purpose:  data.Purpose,
+		for _, attendeeID := range data.AttendeesIDs {
+			if err := s.addEdge(tl.Number(), edgeClassMeetingAttendee, attendeeID, meetingID); err != nil {
+				return err
+			}
 		}
-		if err := s.newVertex(tl.Number(), data.RoleID, vertexClassRole, role); err != nil {
+
+	case ep.EventTypeMeetingAgendaItemAdded:
+		data := data.(*ep.EventMeetingAgendaItemAdded)
+		meetingID, err := util.IDFromString(event.StreamID)
+		if err != nil {
 			return err
 		}
-		if data.ParentRoleID != nil {
-			if err := s.addEdge(tl.Number(), edgeClassRoleRole, *data.ParentRoleID, data.RoleID); err != nil {
-				return err
-			}
+
+		meeting, err := s.Meeting(ctx, tl.Number(), meetingID)
+		if err != nil {
+			return err
+		}
+		if meeting == nil {
+			return errors.Errorf("meeting with id %s doesn't exist", meetingID)
 		}
 
-	case ep.EventTypeRoleDeleted:
-		data := data.(*ep.EventRoleDeleted)
-		proleGroups, err := s.RoleParent(ctx, tl.Number(), []util.ID{data.RoleID})
+		agendaItems, err := s.MeetingAgendaItems(ctx, tl.Number(), []util.ID{meetingID})
 		if err != nil {
 			return err
 		}
-		prole := proleGroups[data.RoleID]
-		if err := s.deleteVertex(tl.Number(), vertexClassRole, data.RoleID); err != nil {
+
+		agendaItem := &models.MeetingAgendaItem{
+			Number: len(agendaItems[meetingID]),
+			Title:  data.Title,
+		}
+		if err := s.newVertex(tl.Number(), data.AgendaItemID, vertexClassAgendaItem, agendaItem); err != nil {
 			return err
 		}
-		if prole != nil {
-			if err := s.deleteEdge(tl.Number(), edgeClassRoleRole, prole.ID, data.RoleID); err != nil {
+		if err := s.addEdge(tl.Number(), edgeClassMeetingAgendaItem, data.AgendaItemID, meetingID); err != nil {
+			return err
+		}
+		if data.TensionID != nil {
+			if err := s.addEdge(tl.Number(), edgeClassAgendaItemTension, data.AgendaItemID, *data.TensionID); err != nil {
 				return err
 			}
 		}
 
-	case ep.EventTypeRoleUpdated:
-		data := data.(*ep.EventRoleUpdated)
-		// We have to retrieve the current role depth
-		crole, err := s.Role(ctx, tl.Number(), data.RoleID)
+	case ep.EventTypeMeetingAgendaItemOutcomeSet:
+		data := data.(*ep.EventMeetingAgendaItemOutcomeSet)
+		meetingID, err := util.IDFromString(event.StreamID)
 		if err != nil {
 			return err
 		}
-		if crole == nil {
-			return errors.Errorf("role with id %s doesn't exist", data.RoleID)
+
+		meeting, err := s.Meeting(ctx, tl.Number(), meetingID)
+		if err != nil {
+			return err
 		}
-		role := &models.Role{
-			RoleType: data.RoleType,
-			Depth:    crole.Depth,
-			Name:     data.Name,
-			Purpose:  data.Purpose,
+		if meeting == nil {
+			return errors.Errorf("meeting with id %s doesn't exist", meetingID)
 		}
-		if err := s.updateVertex(tl.Number(), vertexClassRole, data.RoleID, role); err != nil {
+
+		agendaItem, err := s.agendaItem(tl.Number(), data.AgendaItemID)
+		if err != nil {
 			return err
 		}
-
-	case ep.EventTypeRoleDomainCreated:
-		data := data.(*ep.EventRoleDomainCreated)
-		domainID := data.DomainID
-		domain := &models.Domain{
-			Description: data.Description,
+		if agendaItem == nil {
+			return errors.Errorf("agenda item with id %s doesn't exist", data.AgendaItemID)
 		}
-		if err := s.newVertex(tl.Number(), domainID, vertexClassDomain, domain); err != nil {
+
+		agendaItem.Outcome = data.Outcome
+		if err := s.updateVertex(tl.Number(), vertexClassAgendaItem, data.AgendaItemID, agendaItem); err != nil {
 			return err
 		}
-		if err := s.addEdge(tl.Number(), edgeClassRoleDomain, domainID, data.RoleID); err != nil {
+
+	case ep.EventTypeMeetingClosed:
+		meetingID, err := util.IDFromString(event.StreamID)
+		if err != nil {
 			return err
 		}
 
-	case ep.EventTypeRoleDomainUpdated:
-		data := data.(*ep.EventRoleDomainUpdated)
-		domainID := data.DomainID
-		domain := &models.Domain{
-			Description: data.Description,
-		}
-		if err := s.updateVertex(tl.Number(), vertexClassDomain, domainID, domain); err != nil {
+		meeting, err := s.Meeting(ctx, tl.Number(), meetingID)
+		if err != nil {
 			return err
 		}
+		if meeting == nil {
+			return errors.Errorf("meeting with id %s doesn't exist", meetingID)
+		}
 
-	case ep.EventTypeRoleDomainDeleted:
-		data := data.(*ep.EventRoleDomainDeleted)
-		domainID := data.DomainID
-		if err := s.deleteVertex(tl.Number(), vertexClassDomain, domainID); err != nil {
+		meeting.Closed = true
+		if err := s.updateVertex(tl.Number(), vertexClassMeeting, meetingID, meeting); err != nil {
 			return err
 		}
-		if err := s.deleteEdge(tl.Number(), edgeClassRoleDomain, domainID, data.RoleID); err != nil {
+
+	case ep.EventTypeProjectCreated:
+		data := data.(*ep.EventProjectCreated)
+		projectID, err := util.IDFromString(event.StreamID)
+		if err != nil {
 			return err
 		}
 
-	case ep.EventTypeRoleAccountabilityCreated:
-		data := data.(*ep.EventRoleAccountabilityCreated)
-		accountabilityID := data.AccountabilityID
-		accountability := &models.Accountability{
+		project := &models.Project{
+			Title:       data.Title,
 			Description: data.Description,
+			Status:      data.Status,
 		}
-		if err := s.newVertex(tl.Number(), accountabilityID, vertexClassAccountability, accountability); err != nil {
+		if err := s.newVertex(tl.Number(), projectID, vertexClassProject, project); err != nil {
 			return err
 		}
-		if err := s.addEdge(tl.Number(), edgeClassRoleAccountability, accountabilityID, data.RoleID); err != nil {
+		if err := s.addEdge(tl.Number(), edgeClassRoleProject, projectID, data.RoleID); err != nil {
 			return err
 		}
-
-	case ep.EventTypeRoleAccountabilityUpdated:
-		data := data.(*ep.EventRoleAccountabilityUpdated)
-		accountabilityID := data.AccountabilityID
-		accountability := &models.Accountability{
-			Description: data.Description,
-		}
-		if err := s.updateVertex(tl.Number(), vertexClassAccountability, accountabilityID, accountability); err != nil {
+		if err := s.addEdge(tl.Number(), edgeClassMemberProject, projectID, data.MemberID); err != nil {
 			return err
 		}
 
-	case ep.EventTypeRoleAccountabilityDeleted:
-		data := data.(*ep.EventRoleAccountabilityDeleted)
-		accountabilityID := data.AccountabilityID
-		if err := s.deleteVertex(tl.Number(), vertexClassAccountability, accountabilityID); err != nil {
+	case ep.EventTypeProjectUpdated:
+		data := data.(*ep.EventProjectUpdated)
+		projectID, err := util.IDFromString(event.StreamID)
+		if err != nil {
 			return err
 		}
-		if err := s.deleteEdge(tl.Number(), edgeClassRoleAccountability, accountabilityID, data.RoleID); err != nil {
+
+		project, err := s.Project(ctx, tl.Number(), projectID)
+		if err != nil {
 			return err
 		}
+		if project == nil {
+			return errors.Errorf("project with id %s doesn't exist", projectID)
+		}
 
-	case ep.EventTypeRoleAdditionalContentSet:
-		data := data.(*ep.EventRoleAdditionalContentSet)
-		roleAdditionalContent := &models.RoleAdditionalContent{
-			Content: data.Content,
+		project.Title = data.Title
+		project.Description = data.Description
+		if err := s.updateVertex(tl.Number(), vertexClassProject, projectID, project); err != nil {
+			return err
 		}
-		if err := s.updateVertex(tl.Number(), vertexClassRoleAdditionalContent, data.RoleID, roleAdditionalContent); err != nil {
+
+	case ep.EventTypeProjectStatusChanged:
+		data := data.(*ep.EventProjectStatusChanged)
+		projectID, err := util.IDFromString(event.StreamID)
+		if err != nil {
 			return err
 		}
 
-	case ep.EventTypeRoleChangedParent:
-		data := data.(*ep.EventRoleChangedParent)
-		if err := s.changeRoleParent(ctx, tl.Number(), data.RoleID, data.ParentRoleID); err != nil {
+		project, err := s.Project(ctx, tl.Number(), projectID)
+		if err != nil {
 			return err
 		}
+		if project == nil {
+			return errors.Errorf("project with id %s doesn't exist", projectID)
+		}
 
-	case ep.EventTypeRoleMemberAdded:
-		data := data.(*ep.EventRoleMemberAdded)
-		if err := s.roleAddMember(tl.Number(), data.RoleID, data.MemberID, data.Focus, data.NoCoreMember); err != nil {
+		project.Status = data.Status
+		if err := s.updateVertex(tl.Number(), vertexClassProject, projectID, project); err != nil {
 			return err
 		}
 
-	case ep.EventTypeRoleMemberUpdated:
-		data := data.(*ep.EventRoleMemberUpdated)
-		if err := s.roleUpdateMember(tl.Number(), data.RoleID, data.MemberID, data.Focus, data.NoCoreMember); err != nil {
+	case ep.EventTypeProjectNextActionAdded:
+		data := data.(*ep.EventProjectNextActionAdded)
+		projectID, err := util.IDFromString(event.StreamID)
+		if err != nil {
 			return err
 		}
 
-	case ep.EventTypeRoleMemberRemoved:
-		data := data.(*ep.EventRoleMemberRemoved)
-		if err := s.roleRemoveMember(tl.Number(), data.RoleID, data.MemberID); err != nil {
+		project, err := s.Project(ctx, tl.Number(), projectID)
+		if err != nil {
 			return err
 		}
+		if project == nil {
+			return errors.Errorf("project with id %s doesn't exist", projectID)
+		}
 
-	case ep.EventTypeCircleDirectMemberAdded:
-		data := data.(*ep.EventCircleDirectMemberAdded)
-		if err := s.addEdge(tl.Number(), edgeClassCircleDirectMember, data.MemberID, data.RoleID); err != nil {
+		nextActions, err := s.ProjectNextActions(ctx, tl.Number(), []util.ID{projectID})
+		if err != nil {
 			return err
 		}
 
-	case ep.EventTypeCircleDirectMemberRemoved:
-		data := data.(*ep.EventCircleDirectMemberRemoved)
-		if err := s.circleRemoveDirectMember(tl.Number(), data.RoleID, data.MemberID); err != nil {
+		nextAction := &models.NextAction{
+			Number:      len(nextActions[projectID]),
+			Description: data.Description,
+		}
+		if err := s.newVertex(tl.Number(), data.NextActionID, vertexClassNextAction, nextAction); err != nil {
+			return err
+		}
+		if err := s.addEdge(tl.Number(), edgeClassProjectNextAction, data.NextActionID, projectID); err != nil {
 			return err
 		}
 
-	case ep.EventTypeCircleLeadLinkMemberSet:
-		data := data.(*ep.EventCircleLeadLinkMemberSet)
-		if err := s.addEdge(tl.Number(), edgeClassRoleMember, data.MemberID, data.LeadLinkRoleID, nil, false, nil); err != nil {
+	case ep.EventTypeProjectNextActionCompleted:
+		data := data.(*ep.EventProjectNextActionCompleted)
+		projectID, err := util.IDFromString(event.StreamID)
+		if err != nil {
 			return err
 		}
 
-	case ep.EventTypeCircleLeadLinkMemberUnset:
-		data := data.(*ep.EventCircleLeadLinkMemberUnset)
-		if err := s.deleteEdge(tl.Number(), edgeClassRoleMember, data.MemberID, data.LeadLinkRoleID); err != nil {
+		project, err := s.Project(ctx, tl.Number(), projectID)
+		if err != nil {
 			return err
 		}
+		if project == nil {
+			return errors.Errorf("project with id %s doesn't exist", projectID)
+		}
 
-	case ep.EventTypeCircleCoreRoleMemberSet:
-		data := data.(*ep.EventCircleCoreRoleMemberSet)
-		if err := s.addEdge(tl.Number(), edgeClassRoleMember, data.MemberID, data.CoreRoleID, nil, false, data.ElectionExpiration); err != nil {
+		nextAction, err := s.nextAction(tl.Number(), data.NextActionID)
+		if err != nil {
 			return err
 		}
+		if nextAction == nil {
+			return errors.Errorf("next action with id %s doesn't exist", data.NextActionID)
+		}
 
-	case ep.EventTypeCircleCoreRoleMemberUnset:
-		data := data.(*ep.EventCircleCoreRoleMemberUnset)
-		if err := s.deleteEdge(tl.Number(), edgeClassRoleMember, data.MemberID, data.CoreRoleID); err != nil {
+		nextAction.Completed = true
+		if err := s.updateVertex(tl.Number(), vertexClassNextAction, data.NextActionID, nextAction); err != nil {
 			return err
 		}
 
-	case ep.EventTypeTensionCreated:
-		data := data.(*ep.EventTensionCreated)
-		tensionID, err := util.IDFromString(event.StreamID)
+	case ep.EventTypeReportItemCreated:
+		data := data.(*ep.EventReportItemCreated)
+		reportItemID, err := util.IDFromString(event.StreamID)
 		if err != nil {
 			return err
 		}
 
-		tension := &models.Tension{
-			Title:       data.Title,
-			Description: data.Description,
-			Closed:      false,
+		reportItem := &models.ReportItem{
+			ReportItemType: data.ReportItemType,
+			Description:    data.Description,
+			Frequency:      data.Frequency,
 		}
-		if err := s.newVertex(tl.Number(), tensionID, vertexClassTension, tension); err != nil {
+		if err := s.newVertex(tl.Number(), reportItemID, vertexClassReportItem, reportItem); err != nil {
 			return err
 		}
-		if err := s.addEdge(tl.Number(), edgeClassMemberTension, tensionID, data.MemberID); err != nil {
+		if err := s.addEdge(tl.Number(), edgeClassCircleReportItem, reportItemID, data.CircleID); err != nil {
 			return err
 		}
-		if data.RoleID != nil {
-			if err := s.addEdge(tl.Number(), edgeClassRoleTension, tensionID, *data.RoleID); err != nil {
-				return err
-			}
+		if err := s.addEdge(tl.Number(), edgeClassRoleReportItem, reportItemID, data.RoleID); err != nil {
+			return err
 		}
 
-	case ep.EventTypeTensionUpdated:
-		data := data.(*ep.EventTensionUpdated)
-		tensionID, err := util.IDFromString(event.StreamID)
+	case ep.EventTypeReportItemUpdated:
+		data := data.(*ep.EventReportItemUpdated)
+		reportItemID, err := util.IDFromString(event.StreamID)
 		if err != nil {
 			return err
 		}
 
-		tension := &models.Tension{
-			Title:       data.Title,
-			Description: data.Description,
-			Closed:      false,
+		reportItem, err := s.ReportItem(ctx, tl.Number(), reportItemID)
+		if err != nil {
+			return err
+		}
+		if reportItem == nil {
+			return errors.Errorf("report item with id %s doesn't exist", reportItemID)
 		}
 
-		if err := s.updateVertex(tl.Number(), vertexClassTension, tensionID, tension); err != nil {
+		reportItem.Description = data.Description
+		if err := s.updateVertex(tl.Number(), vertexClassReportItem, reportItemID, reportItem); err != nil {
 			return err
 		}
+		if data.RoleID != data.PreviousRoleID {
+			if err := s.deleteEdge(tl.Number(), edgeClassRoleReportItem, reportItemID, data.PreviousRoleID); err != nil {
+				return err
+			}
+			if err := s.addEdge(tl.Number(), edgeClassRoleReportItem, reportItemID, data.RoleID); err != nil {
+				return err
+			}
+		}
 
-	case ep.EventTypeTensionRoleChanged:
-		data := data.(*ep.EventTensionRoleChanged)
-		tensionID, err := util.IDFromString(event.StreamID)
+	case ep.EventTypeReportItemDeleted:
+		data := data.(*ep.EventReportItemDeleted)
+		reportItemID, err := util.IDFromString(event.StreamID)
 		if err != nil {
 			return err
 		}
 
-		if data.PrevRoleID != nil {
-			if err := s.deleteEdge(tl.Number(), edgeClassRoleTension, tensionID, *data.PrevRoleID); err != nil {
+		values, err := s.ReportItemValues(ctx, tl.Number(), []util.ID{reportItemID})
+		if err != nil {
+			return err
+		}
+		for _, v := range values[reportItemID] {
+			if err := s.deleteVertex(tl.Number(), vertexClassReportValue, v.ID); err != nil {
 				return err
 			}
-		}
-		if data.RoleID != nil {
-			if err := s.addEdge(tl.Number(), edgeClassRoleTension, tensionID, *data.RoleID); err != nil {
+			if err := s.deleteEdge(tl.Number(), edgeClassReportItemValue, v.ID, reportItemID); err != nil {
 				return err
 			}
 		}
 
-	case ep.EventTypeTensionClosed:
-		data := data.(*ep.EventTensionClosed)
-		tensionID, err := util.IDFromString(event.StreamID)
+		if err := s.deleteVertex(tl.Number(), vertexClassReportItem, reportItemID); err != nil {
+			return err
+		}
+		if err := s.deleteEdge(tl.Number(), edgeClassCircleReportItem, reportItemID, data.CircleID); err != nil {
+			return err
+		}
+		if err := s.deleteEdge(tl.Number(), edgeClassRoleReportItem, reportItemID, data.RoleID); err != nil {
+			return err
+		}
+
+	case ep.EventTypeReportItemValueSet:
+		data := data.(*ep.EventReportItemValueSet)
+		reportItemID, err := util.IDFromString(event.StreamID)
 		if err != nil {
 			return err
 		}
 
-		tension, err := s.Tension(ctx, tl.Number(), tensionID)
+		reportItem, err := s.ReportItem(ctx, tl.Number(), reportItemID)
 		if err != nil {
 			return err
 		}
-		if tension == nil {
-			return errors.Errorf("tension with id %s doesn't exist", tensionID)
+		if reportItem == nil {
+			return errors.Errorf("report item with id %s doesn't exist", reportItemID)
 		}
 
-		tension.Closed = true
-		tension.CloseReason = data.Reason
-		if err := s.updateVertex(tl.Number(), vertexClassTension, tensionID, tension); err != nil {
+		valueID := reportValueID(reportItemID, data.Period)
+		value, err := s.reportValue(tl.Number(), valueID)
+		if err != nil {
 			return err
 		}
+		if value == nil {
+			value = &models.ReportItemValue{Period: data.Period, Value: data.Value}
+			if err := s.newVertex(tl.Number(), valueID, vertexClassReportValue, value); err != nil {
+				return err
+			}
+			if err := s.addEdge(tl.Number(), edgeClassReportItemValue, valueID, reportItemID); err != nil {
+				return err
+			}
+		} else {
+			value.Value = data.Value
+			if err := s.updateVertex(tl.Number(), vertexClassReportValue, valueID, value); err != nil {
+				return err
+			}
+		}
 
 	case ep.EventTypeMemberCreated:
 		data := data.(*ep.EventMemberCreated)
@@ -2924,9 +5101,109 @@ func (h *DBEventHandler) handleEvent(event *eventstore.StoredEvent, tx *db.Tx, s
 			return err
 		}
 
+	case ep.EventTypeMemberDeleted:
+		memberID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		// close the edges between the member and its tensions, assigned
+		// tensions, comments, proposals, meetings and projects.
+		// The member roles and circles edges are already closed by the events
+		// emitted by the rolestree
+		for _, ec := range []edgeClass{edgeClassMemberTension, edgeClassTensionAssignee} {
+			vs, err := s.connectedVertices(tl.Number(), []util.ID{memberID}, ec, edgeDirectionIn, "", nil, nil)
+			if err != nil {
+				return err
+			}
+			for _, tension := range vs.(map[util.ID][]*models.Tension)[memberID] {
+				if err := s.deleteEdge(tl.Number(), ec, tension.ID, memberID); err != nil {
+					return err
+				}
+			}
+		}
+		vs, err := s.connectedVertices(tl.Number(), []util.ID{memberID}, edgeClassCommentMember, edgeDirectionIn, "", nil, nil)
+		if err != nil {
+			return err
+		}
+		for _, comment := range vs.(map[util.ID][]*models.TensionComment)[memberID] {
+			if err := s.deleteEdge(tl.Number(), edgeClassCommentMember, comment.ID, memberID); err != nil {
+				return err
+			}
+		}
+		vs, err = s.connectedVertices(tl.Number(), []util.ID{memberID}, edgeClassMemberProposal, edgeDirectionIn, "", nil, nil)
+		if err != nil {
+			return err
+		}
+		for _, proposal := range vs.(map[util.ID][]*models.Proposal)[memberID] {
+			if err := s.deleteEdge(tl.Number(), edgeClassMemberProposal, proposal.ID, memberID); err != nil {
+				return err
+			}
+		}
+		for _, ec := range []edgeClass{edgeClassMeetingAttendee, edgeClassMeetingFacilitator, edgeClassMeetingSecretary} {
+			vs, err = s.connectedVertices(tl.Number(), []util.ID{memberID}, ec, edgeDirectionOut, "", nil, nil)
+			if err != nil {
+				return err
+			}
+			for _, meeting := range vs.(map[util.ID][]*models.Meeting)[memberID] {
+				if err := s.deleteEdge(tl.Number(), ec, memberID, meeting.ID); err != nil {
+					return err
+				}
+			}
+		}
+		vs, err = s.connectedVertices(tl.Number(), []util.ID{memberID}, edgeClassMemberProject, edgeDirectionIn, "", nil, nil)
+		if err != nil {
+			return err
+		}
+		for _, project := range vs.(map[util.ID][]*models.Project)[memberID] {
+			if err := s.deleteEdge(tl.Number(), edgeClassMemberProject, project.ID, memberID); err != nil {
+				return err
+			}
+		}
+
+		if err := s.deleteVertex(tl.Number(), vertexClassMember, memberID); err != nil {
+			return err
+		}
+		if err := s.deleteVertex(tl.Number(), vertexClassMemberAvatar, memberID); err != nil {
+			return err
+		}
+		err = tx.Do(func(tx *db.WrappedTx) error {
+			if _, err := tx.Exec("delete from password where memberid = $1", memberID); err != nil {
+				return errors.Wrap(err, "failed to delete password")
+			}
+			if _, err := tx.Exec("delete from membermatch where memberid = $1", memberID); err != nil {
+				return errors.Wrap(err, "failed to delete matchuid")
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+	case ep.EventTypeMemberForgotten:
+		memberID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		// replace the member personal data in all the member history
+		err = tx.Do(func(tx *db.WrappedTx) error {
+			if _, err := tx.Exec("update member set username = $1, fullname = $2, email = $3 where id = $4", ep.PersonalDataPlaceholder, ep.PersonalDataPlaceholder, ep.PersonalDataPlaceholder, memberID); err != nil {
+				return errors.Wrap(err, "failed to update member")
+			}
+			if _, err := tx.Exec("update memberavatar set image = null where id = $1", memberID); err != nil {
+				return errors.Wrap(err, "failed to update member avatar")
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
 	case ep.EventTypeMemberChangeCreateRequested:
 	case ep.EventTypeMemberChangeUpdateRequested:
 	case ep.EventTypeMemberChangeSetMatchUIDRequested:
+	case ep.EventTypeMemberChangeDeleteRequested:
 	case ep.EventTypeMemberChangeCompleted:
 
 	case ep.EventTypeMemberRequestHandlerStateUpdated:
@@ -3197,31 +5474,154 @@ func (h *DBEventHandler) handleEvent(event *eventstore.StoredEvent, tx *db.Tx, s
 		}
 
 	case ep.EventTypeRoleMemberAdded:
-		//data := data.(*ep.EventRoleMemberAdded)
+		data := data.(*ep.EventRoleMemberAdded)
+
+		eventData, err := s.roleMemberChangedData(ctx, tl.Number(), data.RoleID)
+		if err != nil {
+			return err
+		}
+		eventData.Focus = data.Focus
+		eventData.NoCoreMember = data.NoCoreMember
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, data.MemberID, models.MemberEventTypeRoleMemberAdded, eventData)
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
 
 	case ep.EventTypeRoleMemberUpdated:
-		//data := data.(*ep.EventRoleMemberUpdated)
+		data := data.(*ep.EventRoleMemberUpdated)
+
+		eventData, err := s.roleMemberChangedData(ctx, tl.Number(), data.RoleID)
+		if err != nil {
+			return err
+		}
+		eventData.Focus = data.Focus
+		eventData.NoCoreMember = data.NoCoreMember
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, data.MemberID, models.MemberEventTypeRoleMemberUpdated, eventData)
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
 
 	case ep.EventTypeRoleMemberRemoved:
-		//data := data.(*ep.EventRoleMemberRemoved)
+		data := data.(*ep.EventRoleMemberRemoved)
+
+		eventData, err := s.roleMemberChangedData(ctx, tl.Number(), data.RoleID)
+		if err != nil {
+			return err
+		}
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, data.MemberID, models.MemberEventTypeRoleMemberRemoved, eventData)
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
 
 	case ep.EventTypeCircleDirectMemberAdded:
-		//data := data.(*ep.EventCircleDirectMemberAdded)
+		data := data.(*ep.EventCircleDirectMemberAdded)
+
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, data.MemberID, models.MemberEventTypeCircleDirectMemberAdded, &models.MemberEventCircleDirectMemberChanged{CircleID: data.RoleID})
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
 
 	case ep.EventTypeCircleDirectMemberRemoved:
-		//data := data.(*ep.EventCircleDirectMemberRemoved)
+		data := data.(*ep.EventCircleDirectMemberRemoved)
+
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, data.MemberID, models.MemberEventTypeCircleDirectMemberRemoved, &models.MemberEventCircleDirectMemberChanged{CircleID: data.RoleID})
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
 
 	case ep.EventTypeCircleLeadLinkMemberSet:
-		//data := data.(*ep.EventCircleLeadLinkMemberSet)
+		data := data.(*ep.EventCircleLeadLinkMemberSet)
+
+		eventData, err := s.roleMemberChangedData(ctx, tl.Number(), data.LeadLinkRoleID)
+		if err != nil {
+			return err
+		}
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, data.MemberID, models.MemberEventTypeLeadLinkMemberSet, eventData)
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
 
 	case ep.EventTypeCircleLeadLinkMemberUnset:
-		//data := data.(*ep.EventCircleLeadLinkMemberUnset)
+		data := data.(*ep.EventCircleLeadLinkMemberUnset)
+
+		eventData, err := s.roleMemberChangedData(ctx, tl.Number(), data.LeadLinkRoleID)
+		if err != nil {
+			return err
+		}
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, data.MemberID, models.MemberEventTypeLeadLinkMemberUnset, eventData)
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
 
 	case ep.EventTypeCircleCoreRoleMemberSet:
-		//data := data.(*ep.EventCircleCoreRoleMemberSet)
+		data := data.(*ep.EventCircleCoreRoleMemberSet)
+
+		eventData, err := s.roleMemberChangedData(ctx, tl.Number(), data.CoreRoleID)
+		if err != nil {
+			return err
+		}
+		eventData.ElectionExpiration = data.ElectionExpiration
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, data.MemberID, models.MemberEventTypeCoreRoleMemberSet, eventData)
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
 
 	case ep.EventTypeCircleCoreRoleMemberUnset:
-		//data := data.(*ep.EventCircleCoreRoleMemberUnset)
+		data := data.(*ep.EventCircleCoreRoleMemberUnset)
+
+		eventData, err := s.roleMemberChangedData(ctx, tl.Number(), data.CoreRoleID)
+		if err != nil {
+			return err
+		}
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, data.MemberID, models.MemberEventTypeCoreRoleMemberUnset, eventData)
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
+
+	case ep.EventTypeCircleCoreRoleElectionExpired:
+		data := data.(*ep.EventCircleCoreRoleElectionExpired)
+
+		roleEvent := models.NewRoleEventCoreRoleElectionExpired(tl.Number(), data.RoleID, data.CoreRoleID, data.MemberID, data.RoleType, data.ElectionExpiration)
+		if err := s.insertRoleEvent(roleEvent); err != nil {
+			return err
+		}
+
+		eventData, err := s.roleMemberChangedData(ctx, tl.Number(), data.CoreRoleID)
+		if err != nil {
+			return err
+		}
+		electionExpiration := data.ElectionExpiration
+		eventData.ElectionExpiration = &electionExpiration
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, data.MemberID, models.MemberEventTypeCoreRoleElectionExpired, eventData)
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
+
+	case ep.EventTypeCircleProposalApplied:
+		data := data.(*ep.EventCircleProposalApplied)
+
+		issuerID := metaData.CommandIssuerID
+		if issuerID == nil {
+			break
+		}
+
+		// the proposal changes events are in the same timeline so the role
+		// event already exists (if the proposal changed something)
+		roleEvent, err := s.getCircleChangesAppliedRoleEvent(ctx, tl.Number(), data.RoleID)
+		if err != nil {
+			return err
+		}
+		if roleEvent == nil {
+			roleEvent = models.NewRoleEventCircleChangesApplied(tl.Number(), data.RoleID, *issuerID)
+		}
+
+		eventData := roleEvent.Data.(*models.RoleEventCircleChangesApplied)
+		proposalID := data.ProposalID
+		eventData.ProposalID = &proposalID
+
+		if err := s.insertRoleEvent(roleEvent); err != nil {
+			return err
+		}
 
 	case ep.EventTypeTensionCreated:
 		//data := data.(*ep.EventTensionCreated)
@@ -3235,11 +5635,100 @@ func (h *DBEventHandler) handleEvent(event *eventstore.StoredEvent, tx *db.Tx, s
 	case ep.EventTypeTensionClosed:
 		//data := data.(*ep.EventTensionClosed)
 
+	case ep.EventTypeTensionReopened:
+		//data := data.(*ep.EventTensionReopened)
+
+	case ep.EventTypeTensionStateChanged:
+		//data := data.(*ep.EventTensionStateChanged)
+
+	case ep.EventTypeTensionCommentAdded:
+		//data := data.(*ep.EventTensionCommentAdded)
+
+	case ep.EventTypeTensionAssigneeChanged:
+		//data := data.(*ep.EventTensionAssigneeChanged)
+
+	case ep.EventTypeTensionLabelsSet:
+		//data := data.(*ep.EventTensionLabelsSet)
+
+	case ep.EventTypeProposalCreated:
+		//data := data.(*ep.EventProposalCreated)
+
+	case ep.EventTypeProposalUpdated:
+		//data := data.(*ep.EventProposalUpdated)
+
+	case ep.EventTypeProposalObjectionRaised:
+		//data := data.(*ep.EventProposalObjectionRaised)
+
+	case ep.EventTypeProposalAccepted:
+		//data := data.(*ep.EventProposalAccepted)
+
+	case ep.EventTypeProposalAcceptanceReverted:
+		//data := data.(*ep.EventProposalAcceptanceReverted)
+
+	case ep.EventTypeMeetingCreated:
+		//data := data.(*ep.EventMeetingCreated)
+
+	case ep.EventTypeMeetingAgendaItemAdded:
+		//data := data.(*ep.EventMeetingAgendaItemAdded)
+
+	case ep.EventTypeMeetingAgendaItemOutcomeSet:
+		//data := data.(*ep.EventMeetingAgendaItemOutcomeSet)
+
+	case ep.EventTypeMeetingClosed:
+		//data := data.(*ep.EventMeetingClosed)
+
+	case ep.EventTypeProjectCreated:
+		//data := data.(*ep.EventProjectCreated)
+
+	case ep.EventTypeProjectUpdated:
+		//data := data.(*ep.EventProjectUpdated)
+
+	case ep.EventTypeProjectStatusChanged:
+		//data := data.(*ep.EventProjectStatusChanged)
+
+	case ep.EventTypeProjectNextActionAdded:
+		//data := data.(*ep.EventProjectNextActionAdded)
+
+	case ep.EventTypeProjectNextActionCompleted:
+		//data := data.(*ep.EventProjectNextActionCompleted)
+
+	case ep.EventTypeReportItemCreated:
+		//data := data.(*ep.EventReportItemCreated)
+
+	case ep.EventTypeReportItemUpdated:
+		//data := data.(*ep.EventReportItemUpdated)
+
+	case ep.EventTypeReportItemDeleted:
+		//data := data.(*ep.EventReportItemDeleted)
+
+	case ep.EventTypeReportItemValueSet:
+		//data := data.(*ep.EventReportItemValueSet)
+
 	case ep.EventTypeMemberCreated:
-		//data := data.(*ep.EventMemberCreated)
+		memberID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, memberID, models.MemberEventTypeMemberCreated, &models.MemberEventMemberChanged{})
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
 
 	case ep.EventTypeMemberUpdated:
-		//data := data.(*ep.EventMemberUpdated)
+		memberID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+		changedFields, err := s.memberChangedFields(ctx, tl.Number(), memberID)
+		if err != nil {
+			return err
+		}
+
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, memberID, models.MemberEventTypeMemberUpdated, &models.MemberEventMemberChanged{ChangedFields: changedFields})
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
 
 	case ep.EventTypeMemberPasswordSet:
 		//data := data.(*ep.EventMemberPasswordSet)
@@ -3248,9 +5737,23 @@ func (h *DBEventHandler) handleEvent(event *eventstore.StoredEvent, tx *db.Tx, s
 	case ep.EventTypeMemberAvatarSet:
 		//data := data.(*ep.EventMemberAvatarSet)
 
+	case ep.EventTypeMemberDeleted:
+		memberID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+
+		memberEvent := models.NewMemberEvent(event.SequenceNumber, tl.Number(), event.ID, memberID, models.MemberEventTypeMemberDeleted, &models.MemberEventMemberChanged{})
+		if err := s.insertMemberEvent(memberEvent); err != nil {
+			return err
+		}
+
+	case ep.EventTypeMemberForgotten:
+
 	case ep.EventTypeMemberChangeCreateRequested:
 	case ep.EventTypeMemberChangeUpdateRequested:
 	case ep.EventTypeMemberChangeSetMatchUIDRequested:
+	case ep.EventTypeMemberChangeDeleteRequested:
 	case ep.EventTypeMemberChangeCompleted:
 
 	case ep.EventTypeMemberRequestHandlerStateUpdated:
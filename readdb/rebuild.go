@@ -0,0 +1,355 @@
+package readdb
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sorintlab/sircles/db"
+	"github.com/sorintlab/sircles/eventstore"
+	ln "github.com/sorintlab/sircles/listennotify"
+	"github.com/sorintlab/sircles/lock"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+var ErrRebuildRunning = errors.New("readdb rebuild already running")
+
+// RebuildStatus reports the status of the current or last readdb rebuild
+type RebuildStatus struct {
+	Running   bool
+	StartTime *time.Time
+	EndTime   *time.Time
+	// SequenceNumber is the last event sequence number applied to the
+	// rebuilt readdb
+	SequenceNumber int64
+	// LastSequenceNumber is the eventstore last sequence number
+	LastSequenceNumber int64
+	Error              string
+}
+
+// Rebuilder rebuilds the readdb projection from the eventstore into a shadow
+// db while the current readdb keeps serving. When the shadow db has applied
+// all the events, the readdb event handler is blocked and the readdb is
+// atomically replaced by the shadow db.
+// With postgres the shadow db is a schema in the readdb database, with sqlite3
+// it's a db file inside dataDir.
+// After the switch the readdb listeners are notified using nf.
+type Rebuilder struct {
+	readDB  *db.DB
+	es      eventstore.EventStore
+	nf      ln.NotifierFactory
+	lkf     lock.LockFactory
+	dataDir string
+
+	progressFunc func(sn, lastSn int64)
+
+	m      sync.Mutex
+	status RebuildStatus
+}
+
+func NewRebuilder(readDB *db.DB, es eventstore.EventStore, nf ln.NotifierFactory, lkf lock.LockFactory, dataDir string) *Rebuilder {
+	return &Rebuilder{
+		readDB:  readDB,
+		es:      es,
+		nf:      nf,
+		lkf:     lkf,
+		dataDir: dataDir,
+	}
+}
+
+// SetProgressFunc sets a function called with the last applied and the
+// eventstore last sequence numbers after every batch of applied events.
+func (r *Rebuilder) SetProgressFunc(f func(sn, lastSn int64)) {
+	r.progressFunc = f
+}
+
+func (r *Rebuilder) Status() *RebuildStatus {
+	r.m.Lock()
+	defer r.m.Unlock()
+	status := r.status
+	return &status
+}
+
+func (r *Rebuilder) start() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.status.Running {
+		return ErrRebuildRunning
+	}
+	now := time.Now()
+	r.status = RebuildStatus{
+		Running:   true,
+		StartTime: &now,
+	}
+	return nil
+}
+
+func (r *Rebuilder) end(err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	now := time.Now()
+	r.status.Running = false
+	r.status.EndTime = &now
+	if err != nil {
+		r.status.Error = err.Error()
+	}
+}
+
+func (r *Rebuilder) setProgress(sn, lastSn int64) {
+	r.m.Lock()
+	r.status.SequenceNumber = sn
+	if lastSn > r.status.LastSequenceNumber {
+		r.status.LastSequenceNumber = lastSn
+	}
+	lastSn = r.status.LastSequenceNumber
+	r.m.Unlock()
+
+	if r.progressFunc != nil {
+		r.progressFunc(sn, lastSn)
+	}
+}
+
+// Rebuild rebuilds the readdb and waits for its completion
+func (r *Rebuilder) Rebuild() error {
+	if err := r.start(); err != nil {
+		return err
+	}
+	err := r.rebuild()
+	r.end(err)
+	return err
+}
+
+// Start starts a readdb rebuild in background. The rebuild progress can be
+// retrieved using Status.
+func (r *Rebuilder) Start() error {
+	if err := r.start(); err != nil {
+		return err
+	}
+	go func() {
+		err := r.rebuild()
+		if err != nil {
+			log.Errorf("readdb rebuild failed: %+v", err)
+		}
+		r.end(err)
+	}()
+	return nil
+}
+
+func (r *Rebuilder) rebuild() error {
+	var target rebuildTarget
+	var err error
+	switch r.readDB.Type() {
+	case db.Postgres:
+		target, err = newPGRebuildTarget(r.readDB)
+	case db.Sqlite3:
+		target, err = newSqlite3RebuildTarget(r.readDB, r.dataDir)
+	default:
+		err = errors.Errorf("unsupported readdb type %q", r.readDB.Type())
+	}
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	shadowDB := target.DB()
+	if err := shadowDB.Migrate("readdb", Migrations); err != nil {
+		return err
+	}
+
+	// the shadow db event handler doesn't notify the readdb listeners
+	h := NewDBEventHandler(shadowDB, r.es, ln.NewLocalNotifierFactory(ln.NewLocalListenNotify()))
+
+	lastSn, err := r.es.LastSequenceNumber()
+	if err != nil {
+		return err
+	}
+	r.setProgress(0, lastSn)
+	progress := func(sn int64) { r.setProgress(sn, 0) }
+
+	// first apply the events without blocking the readdb event handler
+	if err := h.handleEvents(progress); err != nil {
+		return err
+	}
+
+	// block the readdb event handler, apply the events written in the
+	// meantime and switch to the rebuilt readdb
+	lk := r.lkf.NewLock(h.Name())
+	if err := lk.Lock(); err != nil {
+		return err
+	}
+	defer lk.Unlock()
+
+	lastSn, err = r.es.LastSequenceNumber()
+	if err != nil {
+		return err
+	}
+	r.setProgress(r.Status().SequenceNumber, lastSn)
+	if err := h.handleEvents(progress); err != nil {
+		return err
+	}
+
+	if err := target.Switch(); err != nil {
+		return errors.WithMessage(err, "failed to switch to the rebuilt readdb")
+	}
+	log.Infof("readdb rebuilt up to sequence number %d", r.Status().SequenceNumber)
+
+	// notify the readdb listeners waiting for timelines applied to the
+	// rebuilt readdb after the readdb event handler has been blocked
+	return r.notify()
+}
+
+func (r *Rebuilder) notify() error {
+	notifier := r.nf.NewNotifier()
+	if txNotifier, ok := notifier.(ln.TxNotifier); ok {
+		return r.readDB.Do(func(tx *db.Tx) error {
+			txNotifier.BindTx(tx)
+			return txNotifier.Notify("readdb", "")
+		})
+	}
+	return notifier.Notify("readdb", "")
+}
+
+type rebuildTarget interface {
+	// DB returns the shadow db where the readdb is rebuilt
+	DB() *db.DB
+	// Switch atomically replaces the readdb with the shadow db
+	Switch() error
+	// Close removes the shadow db
+	Close() error
+}
+
+type pgRebuildTarget struct {
+	readDB       *db.DB
+	shadowDB     *db.DB
+	schema       string
+	shadowSchema string
+}
+
+func newPGRebuildTarget(readDB *db.DB) (*pgRebuildTarget, error) {
+	t := &pgRebuildTarget{readDB: readDB}
+	err := readDB.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			if err := tx.QueryRow("select current_schema()").Scan(&t.schema); err != nil {
+				return errors.Wrap(err, "failed to get readdb schema")
+			}
+			t.shadowSchema = t.schema + "_rebuild"
+			if _, err := tx.Exec("drop schema if exists " + pq.QuoteIdentifier(t.shadowSchema) + " cascade"); err != nil {
+				return errors.Wrap(err, "failed to drop shadow schema")
+			}
+			if _, err := tx.Exec("create schema " + pq.QuoteIdentifier(t.shadowSchema)); err != nil {
+				return errors.Wrap(err, "failed to create shadow schema")
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t.shadowDB, err = readDB.WithSchema(t.shadowSchema)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *pgRebuildTarget) DB() *db.DB {
+	return t.shadowDB
+}
+
+// Switch moves, in a single transaction, all the shadow schema tables to the
+// readdb schema replacing the current ones
+func (t *pgRebuildTarget) Switch() error {
+	return t.readDB.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			rows, err := tx.Query("select tablename from pg_tables where schemaname = $1", t.shadowSchema)
+			if err != nil {
+				return errors.Wrap(err, "failed to get shadow schema tables")
+			}
+			tables := []string{}
+			for rows.Next() {
+				var table string
+				if err := rows.Scan(&table); err != nil {
+					rows.Close()
+					return errors.WithStack(err)
+				}
+				tables = append(tables, table)
+			}
+			if err := rows.Err(); err != nil {
+				return errors.WithStack(err)
+			}
+
+			for _, table := range tables {
+				if _, err := tx.Exec("drop table if exists " + pq.QuoteIdentifier(t.schema) + "." + pq.QuoteIdentifier(table) + " cascade"); err != nil {
+					return errors.Wrapf(err, "failed to drop table %q", table)
+				}
+				if _, err := tx.Exec("alter table " + pq.QuoteIdentifier(t.shadowSchema) + "." + pq.QuoteIdentifier(table) + " set schema " + pq.QuoteIdentifier(t.schema)); err != nil {
+					return errors.Wrapf(err, "failed to move table %q", table)
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func (t *pgRebuildTarget) Close() error {
+	return t.readDB.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			if _, err := tx.Exec("drop schema if exists " + pq.QuoteIdentifier(t.shadowSchema) + " cascade"); err != nil {
+				return errors.Wrap(err, "failed to drop shadow schema")
+			}
+			return nil
+		})
+	})
+}
+
+type sqlite3RebuildTarget struct {
+	readDB   *db.DB
+	shadowDB *db.DB
+	path     string
+}
+
+func newSqlite3RebuildTarget(readDB *db.DB, dataDir string) (*sqlite3RebuildTarget, error) {
+	t := &sqlite3RebuildTarget{
+		readDB: readDB,
+		path:   filepath.Join(dataDir, "readdb-rebuild"),
+	}
+	// remove leftovers of a previous rebuild
+	if err := t.removeFiles(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	t.shadowDB, err = db.NewDB(db.Sqlite3, t.path)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *sqlite3RebuildTarget) DB() *db.DB {
+	return t.shadowDB
+}
+
+func (t *sqlite3RebuildTarget) Switch() error {
+	return t.readDB.ReplaceWith(t.shadowDB)
+}
+
+func (t *sqlite3RebuildTarget) removeFiles() error {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Remove(t.path + suffix); err != nil && !os.IsNotExist(err) {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (t *sqlite3RebuildTarget) Close() error {
+	if err := t.shadowDB.Close(); err != nil {
+		return err
+	}
+	return t.removeFiles()
+}
@@ -0,0 +1,277 @@
+package readdb_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sorintlab/sircles/change"
+	"github.com/sorintlab/sircles/command"
+	"github.com/sorintlab/sircles/common"
+	"github.com/sorintlab/sircles/db"
+	"github.com/sorintlab/sircles/eventhandler"
+	"github.com/sorintlab/sircles/eventstore"
+	ln "github.com/sorintlab/sircles/listennotify"
+	"github.com/sorintlab/sircles/lock"
+	"github.com/sorintlab/sircles/readdb"
+)
+
+func memberExists(t *testing.T, readDB *db.DB, userName string) bool {
+	var exists bool
+	err := readDB.Do(func(tx *db.Tx) error {
+		readDBService, err := readdb.NewReadDBService(tx)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		tl := readDBService.CurTimeLine(ctx)
+		member, err := readDBService.MemberByUserName(ctx, tl.Number(), userName)
+		if err != nil {
+			return err
+		}
+		exists = member != nil
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return exists
+}
+
+type rebuildTestEnv struct {
+	readDB         *db.DB
+	es             eventstore.EventStore
+	readDBListener *readdb.DBListener
+	cs             *command.CommandService
+	lf             ln.ListenerFactory
+	nf             ln.NotifierFactory
+	lkf            lock.LockFactory
+
+	stop   chan struct{}
+	endChs []chan struct{}
+}
+
+// newRebuildTestEnv creates a readdb and an eventstore and starts the readdb
+// and member requests event handlers
+func newRebuildTestEnv(t *testing.T, tmpDir string) *rebuildTestEnv {
+	readDB, err := db.NewDB("sqlite3", filepath.Join(tmpDir, "readdb"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := readDB.Migrate("readdb", readdb.Migrations); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	esDB, err := db.NewDB("sqlite3", filepath.Join(tmpDir, "esdb"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := esDB.Migrate("eventstore", eventstore.Migrations); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	localln := ln.NewLocalListenNotify()
+	lf := ln.NewLocalListenerFactory(localln)
+	nf := ln.NewLocalNotifierFactory(localln)
+	lkf := lock.NewLocalLockFactory(lock.NewLocalLocks())
+
+	es := eventstore.NewSQLEventStore(esDB, nf)
+	env := &rebuildTestEnv{
+		readDB:         readDB,
+		es:             es,
+		readDBListener: readdb.NewDBListener(readDB, lf),
+		cs:             command.NewCommandService(tmpDir, readDB, es, nil, lf, false, false),
+		lf:             lf,
+		nf:             nf,
+		lkf:            lkf,
+		stop:           make(chan struct{}),
+	}
+
+	readDBh := readdb.NewDBEventHandler(readDB, es, nf)
+	mrh := eventhandler.NewMemberRequestHandler(tmpDir, es, &common.DefaultUidGenerator{})
+	for _, h := range []eventhandler.EventHandler{readDBh, mrh} {
+		endCh, err := eventhandler.RunEventHandler(h, env.stop, lf, lkf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		env.endChs = append(env.endChs, endCh)
+	}
+
+	_, groupID, err := env.cs.SetupRootRole()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := env.readDBListener.WaitTimeLineForGroupID(context.Background(), groupID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return env
+}
+
+func (env *rebuildTestEnv) Close() {
+	close(env.stop)
+	for _, endCh := range env.endChs {
+		<-endCh
+	}
+	env.readDB.Close()
+}
+
+func (env *rebuildTestEnv) createMember(userName string) error {
+	ctx := context.Background()
+	c := &change.CreateMemberChange{
+		IsAdmin:  true,
+		UserName: userName,
+		FullName: userName,
+		Email:    userName + "@example.com",
+		Password: "password",
+	}
+	_, groupID, err := env.cs.CreateMemberInternal(ctx, c, false, false)
+	if err != nil {
+		return err
+	}
+	_, err = env.readDBListener.WaitTimeLineForGroupID(ctx, groupID)
+	return err
+}
+
+func TestRebuild(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir(%q, %q) got error %q", "", "", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	env := newRebuildTestEnv(t, tmpDir)
+	defer env.Close()
+	readDB := env.readDB
+	es := env.es
+
+	if err := env.createMember("user01"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// remove the member from the readdb projection
+	err = readDB.Do(func(tx *db.Tx) error {
+		return tx.Do(func(tx *db.WrappedTx) error {
+			_, err := tx.Exec("delete from member")
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if memberExists(t, readDB, "user01") {
+		t.Fatalf("expected member user01 not existing")
+	}
+
+	r := readdb.NewRebuilder(readDB, es, env.nf, env.lkf, tmpDir)
+	if err := r.Rebuild(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !memberExists(t, readDB, "user01") {
+		t.Fatalf("expected member user01 existing in the rebuilt readdb")
+	}
+
+	lastSn, err := es.LastSequenceNumber()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status := r.Status()
+	if status.Running {
+		t.Fatalf("expected rebuild not running")
+	}
+	if status.Error != "" {
+		t.Fatalf("unexpected rebuild error: %s", status.Error)
+	}
+	if status.SequenceNumber != lastSn || status.LastSequenceNumber != lastSn {
+		t.Fatalf("expected sequence number and last sequence number %d, got %d and %d", lastSn, status.SequenceNumber, status.LastSequenceNumber)
+	}
+
+	// the readdb event handler must continue from the rebuilt readdb
+	if err := env.createMember("user02"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !memberExists(t, readDB, "user02") {
+		t.Fatalf("expected member user02 existing")
+	}
+}
+
+// TestRebuildWhileHandlingEvents rebuilds the readdb while new events are
+// written and applied by the readdb event handler
+func TestRebuildWhileHandlingEvents(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir(%q, %q) got error %q", "", "", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	env := newRebuildTestEnv(t, tmpDir)
+	defer env.Close()
+
+	for i := 0; i < 5; i++ {
+		if err := env.createMember(fmt.Sprintf("user%02d", i)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	r := readdb.NewRebuilder(env.readDB, env.es, env.nf, env.lkf, tmpDir)
+
+	// create members, waiting for them in the readdb, during the rebuilds
+	userNames := []string{}
+	for i := 5; i < 25; i++ {
+		userNames = append(userNames, fmt.Sprintf("user%02d", i))
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		for _, userName := range userNames {
+			if err := env.createMember(userName); err != nil {
+				errCh <- err
+				return
+			}
+		}
+		errCh <- nil
+	}()
+
+	rebuilds := 0
+	for done := false; !done; {
+		if err := r.Rebuild(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rebuilds++
+		select {
+		case err := <-errCh:
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			done = true
+		default:
+		}
+	}
+	t.Logf("readdb rebuilt %d times", rebuilds)
+
+	for i := 0; i < 25; i++ {
+		userName := fmt.Sprintf("user%02d", i)
+		if !memberExists(t, env.readDB, userName) {
+			t.Fatalf("expected member %s existing", userName)
+		}
+	}
+
+	// with no new events the only readdb notification is the one sent
+	// after switching to the rebuilt readdb
+	l := env.lf.NewListener()
+	if err := l.Listen("readdb"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer l.Close()
+	if err := r.Rebuild(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-l.NotificationChannel():
+	case <-time.After(10 * time.Second):
+		t.Fatalf("expected a readdb notification after the rebuild")
+	}
+}
@@ -15,17 +15,18 @@ import (
 var log = slog.S()
 
 type MemberRequestSagaRepository struct {
-	es           *eventstore.EventStore
+	dataDir      string
+	es           eventstore.EventStore
 	uidGenerator common.UIDGenerator
 }
 
-func NewMemberRequestSagaRepository(es *eventstore.EventStore, uidGenerator common.UIDGenerator) *MemberRequestSagaRepository {
-	return &MemberRequestSagaRepository{es: es, uidGenerator: uidGenerator}
+func NewMemberRequestSagaRepository(dataDir string, es eventstore.EventStore, uidGenerator common.UIDGenerator) *MemberRequestSagaRepository {
+	return &MemberRequestSagaRepository{dataDir: dataDir, es: es, uidGenerator: uidGenerator}
 }
 
 func (r *MemberRequestSagaRepository) Load(id string) (*MemberRequestSaga, error) {
 	log.Debugf("Load id: %s", id)
-	s, err := NewMemberRequestSaga(r.es, r.uidGenerator, id)
+	s, err := NewMemberRequestSaga(r.dataDir, r.es, r.uidGenerator, id)
 	if err != nil {
 		return nil, err
 	}
@@ -60,13 +61,16 @@ type MemberRequestSaga struct {
 
 	completed bool
 
-	es           *eventstore.EventStore
+	// dataDir is the data dir used by the rolestree aggregate
+	dataDir      string
+	es           eventstore.EventStore
 	uidGenerator common.UIDGenerator
 }
 
-func NewMemberRequestSaga(es *eventstore.EventStore, uidGenerator common.UIDGenerator, id string) (*MemberRequestSaga, error) {
+func NewMemberRequestSaga(dataDir string, es eventstore.EventStore, uidGenerator common.UIDGenerator, id string) (*MemberRequestSaga, error) {
 	return &MemberRequestSaga{
 		id:           id,
+		dataDir:      dataDir,
 		es:           es,
 		uidGenerator: uidGenerator,
 	}, nil
@@ -258,7 +262,7 @@ func (s *MemberRequestSaga) HandleEvent(event *eventstore.StoredEvent) ([]ep.Eve
 		})
 
 		_, _, err = aggregate.ExecCommand(command, m, s.es, s.uidGenerator)
-		if _, ok := err.(aggregate.HandleCommandError); ok {
+		if _, ok := err.(*aggregate.HandleCommandError); ok {
 			// Rollback reservations if the member update command returned an error
 			log.Error(err)
 
@@ -318,6 +322,37 @@ func (s *MemberRequestSaga) HandleEvent(event *eventstore.StoredEvent) ([]ep.Eve
 			return nil, err
 		}
 
+	case ep.EventTypeMemberChangeDeleteRequested:
+		data := data.(*ep.EventMemberChangeDeleteRequested)
+		memberChangeID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return nil, err
+		}
+
+		mr := aggregate.NewMemberRepository(s.es, s.uidGenerator)
+		m, err := mr.Load(data.MemberID)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Debugf("deleting member %s", data.MemberID)
+		command := commands.NewCommand(commands.CommandTypeDeleteMember, correlationID, causationID, util.NilID, &commands.DeleteMember{
+			MemberChangeID: memberChangeID,
+		})
+
+		_, _, err = aggregate.ExecCommand(command, m, s.es, s.uidGenerator)
+		if _, ok := err.(*aggregate.HandleCommandError); ok {
+			log.Error(err)
+
+			if err := s.completeMemberChange(correlationID, causationID, memberChangeID, fmt.Sprintf("error deleting member: %v", err)); err != nil {
+				return nil, err
+			}
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
 	case ep.EventTypeMemberCreated:
 		data := data.(*ep.EventMemberCreated)
 
@@ -368,6 +403,51 @@ func (s *MemberRequestSaga) HandleEvent(event *eventstore.StoredEvent) ([]ep.Eve
 			return nil, err
 		}
 
+	case ep.EventTypeMemberDeleted:
+		data := data.(*ep.EventMemberDeleted)
+		memberID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return nil, err
+		}
+
+		// release all the values reserved by the deleted member
+		if err := s.releaseUserName(correlationID, causationID, data.UserName, memberID, data.MemberChangeID); err != nil {
+			return nil, err
+		}
+
+		if err := s.releaseEmail(correlationID, causationID, data.Email, memberID, data.MemberChangeID); err != nil {
+			return nil, err
+		}
+
+		if data.MatchUID != "" {
+			if err := s.releaseMatchUID(correlationID, causationID, data.MatchUID, memberID, data.MemberChangeID); err != nil {
+				return nil, err
+			}
+		}
+
+		// remove the deleted member from all its roles and circles and
+		// close its open tensions. The request is completed only after
+		// them so the request group id is the one of the last step.
+		// Every step is idempotent since the event will be handled again
+		// if a step fails.
+		if err := s.removeMemberFromRoles(correlationID, causationID, memberID); err != nil {
+			return nil, err
+		}
+
+		request, err := s.deleteRequest(data.MemberChangeID)
+		if err != nil {
+			return nil, err
+		}
+		for _, tensionID := range request.TensionIDs {
+			if err := s.closeTension(correlationID, causationID, tensionID); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := s.completeMemberChange(correlationID, causationID, data.MemberChangeID, ""); err != nil {
+			return nil, err
+		}
+
 	case ep.EventTypeMemberChangeCompleted:
 		events := []ep.Event{}
 		events = append(events, ep.NewEventMemberRequestSagaCompleted(s.id))
@@ -535,6 +615,60 @@ func (s *MemberRequestSaga) completeMemberChange(correlationID, causationID util
 	return nil
 }
 
+func (s *MemberRequestSaga) removeMemberFromRoles(correlationID, causationID util.ID, memberID util.ID) error {
+	rtr := aggregate.NewRolesTreeRepository(s.dataDir, s.es, s.uidGenerator)
+	rt, err := rtr.Load(aggregate.RolesTreeAggregateID)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("removing member %s from roles", memberID)
+	command := commands.NewCommand(commands.CommandTypeRemoveMemberFromRoles, correlationID, causationID, util.NilID, &commands.RemoveMemberFromRoles{MemberID: memberID})
+
+	if _, _, err := aggregate.ExecCommand(command, rt, s.es, s.uidGenerator); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *MemberRequestSaga) closeTension(correlationID, causationID util.ID, tensionID util.ID) error {
+	tr := aggregate.NewTensionRepository(s.es, s.uidGenerator)
+	t, err := tr.Load(tensionID)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("closing tension %s", tensionID)
+	command := commands.NewCommand(commands.CommandTypeCloseTension, correlationID, causationID, util.NilID, &commands.CloseTension{Reason: "member deleted"})
+
+	_, _, err = aggregate.ExecCommand(command, t, s.es, s.uidGenerator)
+	// the tension has been already closed
+	if _, ok := err.(*aggregate.HandleCommandError); ok {
+		log.Debugf("tension %s not closed: %v", tensionID, err)
+		return nil
+	}
+	return err
+}
+
+// deleteRequest returns the delete request of the provided member change
+func (s *MemberRequestSaga) deleteRequest(memberChangeID util.ID) (*ep.EventMemberChangeDeleteRequested, error) {
+	events, err := s.es.GetEvents(memberChangeID.String(), 1, 100)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		if ep.EventType(e.EventType) != ep.EventTypeMemberChangeDeleteRequested {
+			continue
+		}
+		data, err := ep.UnmarshalData(e)
+		if err != nil {
+			return nil, err
+		}
+		return data.(*ep.EventMemberChangeDeleteRequested), nil
+	}
+	return nil, fmt.Errorf("no delete request for member change %s", memberChangeID)
+}
+
 func userNameRegistry(userName string) string {
 	return fmt.Sprintf("username-%s", userName)
 }
@@ -0,0 +1,67 @@
+package saga
+
+import (
+	"testing"
+
+	"github.com/sorintlab/sircles/aggregate"
+	"github.com/sorintlab/sircles/command/commands"
+	"github.com/sorintlab/sircles/common"
+	ep "github.com/sorintlab/sircles/events"
+	"github.com/sorintlab/sircles/eventstore"
+	ln "github.com/sorintlab/sircles/listennotify"
+	"github.com/sorintlab/sircles/util"
+)
+
+// TestDeleteMemberRejected checks that when the member deletion is rejected
+// the saga completes the member change request with an error instead of
+// failing.
+func TestDeleteMemberRejected(t *testing.T) {
+	es := eventstore.NewMemoryEventStore(ln.NewLocalNotifierFactory(ln.NewLocalListenNotify()))
+	uidGenerator := &common.DefaultUidGenerator{}
+
+	memberChangeID := uidGenerator.UUID("")
+	// an unexistent member
+	memberID := uidGenerator.UUID("")
+
+	mcr := aggregate.NewMemberChangeRepository(es, uidGenerator)
+	mc, err := mcr.Load(memberChangeID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	command := commands.NewCommand(commands.CommandTypeRequestDeleteMember, uidGenerator.UUID(""), uidGenerator.UUID(""), util.NilID, &commands.RequestDeleteMember{MemberID: memberID})
+	if _, _, err := aggregate.ExecCommand(command, mc, es, uidGenerator); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events, err := es.GetEvents(memberChangeID.String(), 0, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+
+	s, err := NewMemberRequestSaga("", es, uidGenerator, "saga01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.HandleEvent(events[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events, err = es.GetEvents(memberChangeID.String(), 0, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last := events[len(events)-1]
+	if ep.EventType(last.EventType) != ep.EventTypeMemberChangeCompleted {
+		t.Fatalf("expected event type %s, got %s", ep.EventTypeMemberChangeCompleted, last.EventType)
+	}
+	data, err := ep.UnmarshalData(last)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !data.(*ep.EventMemberChangeCompleted).Error {
+		t.Fatalf("expected member change completed with error")
+	}
+}
@@ -25,12 +25,12 @@ var log = slog.S()
 
 type SearchEngine struct {
 	db *db.DB
-	es *eventstore.EventStore
+	es eventstore.EventStore
 
 	index bleve.Index
 }
 
-func NewSearchEngine(db *db.DB, es *eventstore.EventStore, indexPath string) *SearchEngine {
+func NewSearchEngine(db *db.DB, es eventstore.EventStore, indexPath string) *SearchEngine {
 	mapping := buildIndexMapping()
 
 	index, err := createOpenIndex(indexPath, mapping)
@@ -292,6 +292,10 @@ func (s *SearchEngine) HandlEvent(event *eventstore.StoredEvent) error {
 		data := data.(*ep.EventCircleCoreRoleMemberUnset)
 		reindexMembers = append(reindexMembers, data.MemberID)
 
+	case ep.EventTypeCircleCoreRoleElectionExpired:
+
+	case ep.EventTypeCircleProposalApplied:
+
 	case ep.EventTypeTensionCreated:
 
 	case ep.EventTypeTensionUpdated:
@@ -300,6 +304,38 @@ func (s *SearchEngine) HandlEvent(event *eventstore.StoredEvent) error {
 
 	case ep.EventTypeTensionClosed:
 
+	case ep.EventTypeTensionReopened:
+
+	case ep.EventTypeTensionStateChanged:
+
+	case ep.EventTypeTensionCommentAdded:
+
+	case ep.EventTypeTensionAssigneeChanged:
+
+	case ep.EventTypeTensionLabelsSet:
+
+	case ep.EventTypeProposalCreated:
+	case ep.EventTypeProposalUpdated:
+	case ep.EventTypeProposalObjectionRaised:
+	case ep.EventTypeProposalAccepted:
+	case ep.EventTypeProposalAcceptanceReverted:
+
+	case ep.EventTypeMeetingCreated:
+	case ep.EventTypeMeetingAgendaItemAdded:
+	case ep.EventTypeMeetingAgendaItemOutcomeSet:
+	case ep.EventTypeMeetingClosed:
+
+	case ep.EventTypeProjectCreated:
+	case ep.EventTypeProjectUpdated:
+	case ep.EventTypeProjectStatusChanged:
+	case ep.EventTypeProjectNextActionAdded:
+	case ep.EventTypeProjectNextActionCompleted:
+
+	case ep.EventTypeReportItemCreated:
+	case ep.EventTypeReportItemUpdated:
+	case ep.EventTypeReportItemDeleted:
+	case ep.EventTypeReportItemValueSet:
+
 	case ep.EventTypeMemberCreated:
 		memberID, err := util.IDFromString(event.StreamID)
 		if err != nil {
@@ -314,6 +350,16 @@ func (s *SearchEngine) HandlEvent(event *eventstore.StoredEvent) error {
 		}
 		reindexMembers = append(reindexMembers, memberID)
 
+	case ep.EventTypeMemberDeleted:
+		memberID, err := util.IDFromString(event.StreamID)
+		if err != nil {
+			return err
+		}
+		deleteMembers = append(deleteMembers, memberID)
+
+	case ep.EventTypeMemberForgotten:
+		// the member has already been removed from the index when deleted
+
 	case ep.EventTypeMemberPasswordSet:
 
 	case ep.EventTypeMemberAvatarSet:
@@ -321,6 +367,7 @@ func (s *SearchEngine) HandlEvent(event *eventstore.StoredEvent) error {
 	case ep.EventTypeMemberChangeCreateRequested:
 	case ep.EventTypeMemberChangeUpdateRequested:
 	case ep.EventTypeMemberChangeSetMatchUIDRequested:
+	case ep.EventTypeMemberChangeDeleteRequested:
 	case ep.EventTypeMemberChangeCompleted:
 
 	case ep.EventTypeMemberRequestHandlerStateUpdated:
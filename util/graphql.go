@@ -39,6 +39,20 @@ func (tln TimeLineNumber) MarshalJSON() ([]byte, error) {
 	return strconv.AppendQuote(nil, strconv.FormatInt(int64(tln), 10)), nil
 }
 
+// UnmarshalJSON accepts both the string marshalled by MarshalJSON and a number
+func (tl *TimeLineNumber) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if us, err := strconv.Unquote(s); err == nil {
+		s = us
+	}
+	t, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse timeline %s", data)
+	}
+	*tl = TimeLineNumber(t)
+	return nil
+}
+
 type TimeLine struct {
 	Timestamp time.Time
 }
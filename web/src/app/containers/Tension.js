@@ -70,7 +70,8 @@ class Tension extends React.Component {
     const tension = tensionQuery.tension
     const { closeReason, showError, errorMessage } = this.state
 
-    let member = JSON.parse(JSON.stringify(tension.member))
+    // member is null when the tension member has been deleted
+    let member = tension.member ? JSON.parse(JSON.stringify(tension.member)) : { circles: [] }
 
     // sort circle by depth then by name
     member.circles.sort((a, b) => {